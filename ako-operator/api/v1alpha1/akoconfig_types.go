@@ -68,6 +68,8 @@ type AKOSettings struct {
 	ServicesAPI bool `json:"servicesAPI,omitempty"`
 	// VipPerNamespace enables AKO to create Parent VS per Namespace in EVH mode
 	VipPerNamespace bool `json:"vipPerNamespace,omitempty"`
+	// LeaderElection enables the leader election among the AKO replicas
+	LeaderElection bool `json:"leaderElection,omitempty"`
 }
 
 type NodeNetwork struct {
@@ -139,6 +141,9 @@ type ControllerSettings struct {
 	TenantsPerCluster bool `json:"tenantsPerCluster,omitempty"`
 	// TenantName is the name of the tenant where all AKO objects will be created in Avi.
 	TenantName string `json:"tenantName,omitempty"`
+	// TenantsPerNamespace if set to true, AKO creates the objects of each namespace in the Avi tenant
+	// set on the namespace
+	TenantsPerNamespace bool `json:"tenantsPerNamespace,omitempty"`
 }
 
 // NodePortSelector defines the node port settings, to be used only if the serviceTYpe is selected
@@ -187,6 +192,8 @@ type AKOConfigSpec struct {
 	PersistentVolumeClaim string `json:"pvc,omitempty"`
 	MountPath             string `json:"mountPath,omitempty"`
 	LogFile               string `json:"logFile,omitempty"`
+	// ReplicaCount is the number of replicas of the AKO controller, leader election is enabled for more than one replica
+	ReplicaCount int32 `json:"replicaCount,omitempty"`
 }
 
 // AKOConfigStatus defines the observed state of AKOConfig
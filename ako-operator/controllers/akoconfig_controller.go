@@ -95,9 +95,11 @@ func removeFinalizer(finalizers []string, key string) (result []string) {
 // +kubebuilder:rbac:groups=ako.vmware.com,resources=aviinfrasettings;aviinfrasettings/status;aviinfrasettings/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ako.vmware.com,resources=httprules;httprules/status;httprules/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ako.vmware.com,resources=hostrules;hostrules/status;hostrules/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ako.vmware.com,resources=l4rules;l4rules/status;l4rules/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=crd.projectcalico.org,resources=blockaffinities;blockaffinities/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions;customresourcedefinitions/status;customresourcedefinitions/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="apps",resources=statefulsets;statefulsets/status;statefulsets/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 // +kubebuilder:rbac:groups=extensions,resources=ingresses; ingresses/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=network.openshift.io,resources=hostsubnets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingressclasses;ingressclasses/finalizers,verbs=get;list;watch;create;update;patch;delete
@@ -170,6 +170,12 @@ func BuildConfigMap(ako akov1alpha1.AKOConfig) (corev1.ConfigMap, error) {
 	}
 	cm.Data[DeleteConfig] = deleteConfig
 
+	leaderElection := "false"
+	if ako.Spec.AKOSettings.LeaderElection || ako.Spec.ReplicaCount > 1 {
+		leaderElection = "true"
+	}
+	cm.Data[LeaderElection] = leaderElection
+
 	enableRHI := "false"
 	if ako.Spec.NetworkSettings.EnableRHI {
 		enableRHI = "true"
@@ -247,6 +253,12 @@ func BuildConfigMap(ako akov1alpha1.AKOConfig) (corev1.ConfigMap, error) {
 	cm.Data[NSSyncLabelValue] = ako.Spec.AKOSettings.NSSelector.LabelValue
 
 	cm.Data[TenantName] = ako.Spec.ControllerSettings.TenantName
+
+	tenantsPerNamespace := "false"
+	if ako.Spec.ControllerSettings.TenantsPerNamespace {
+		tenantsPerNamespace = "true"
+	}
+	cm.Data[TenantsPerNamespace] = tenantsPerNamespace
 	cm.Data[AutoFQDN] = ako.Spec.L4Settings.AutoFQDN
 
 	return cm, nil
@@ -32,6 +32,9 @@ var (
 	AviInfraSettingFullCRDName = "aviinfrasettings.ako.vmware.com"
 	AviInfraSettingCRDSingular = "aviinfrasetting"
 	AviInfraSettingCRDPlural   = "aviinfrasettings"
+	L4RuleFullCRDName          = "l4rules.ako.vmware.com"
+	L4RuleCRDSingular          = "l4rule"
+	L4RuleCRDPlural            = "l4rules"
 )
 
 func createHostRuleCRD(clientset *apiextension.ApiextensionsV1Client, log logr.Logger) error {
@@ -659,6 +662,171 @@ func createAviInfraSettingCRD(clientset *apiextension.ApiextensionsV1Client, log
 	return err
 }
 
+func createL4RuleCRD(clientset *apiextension.ApiextensionsV1Client, log logr.Logger) error {
+	backendProperties := map[string]apiextensionv1.JSONSchemaProps{
+		"loadBalancerPolicy": {
+			Type: "object",
+			Properties: map[string]apiextensionv1.JSONSchemaProps{
+				"algorithm": {
+					Type: "string",
+					Enum: []apiextensionv1.JSON{
+						{
+							Raw: []byte("\"LB_ALGORITHM_CONSISTENT_HASH\""),
+						}, {
+							Raw: []byte("\"LB_ALGORITHM_CORE_AFFINITY\""),
+						}, {
+							Raw: []byte("\"LB_ALGORITHM_FASTEST_RESPONSE\""),
+						}, {
+							Raw: []byte("\"LB_ALGORITHM_FEWEST_SERVERS\""),
+						}, {
+							Raw: []byte("\"LB_ALGORITHM_LEAST_CONNECTIONS\""),
+						}, {
+							Raw: []byte("\"LB_ALGORITHM_LEAST_LOAD\""),
+						}, {
+							Raw: []byte("\"LB_ALGORITHM_ROUND_ROBIN\""),
+						},
+					},
+				},
+				"hash": {
+					Type: "string",
+					Enum: []apiextensionv1.JSON{
+						{
+							Raw: []byte("\"LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS\""),
+						}, {
+							Raw: []byte("\"LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS_AND_PORT\""),
+						},
+					},
+				},
+			},
+		},
+		"applicationPersistence": {
+			Type: "string",
+		},
+		"healthMonitors": {
+			Type: "array",
+			Items: &apiextensionv1.JSONSchemaPropsOrArray{
+				Schema: &apiextensionv1.JSONSchemaProps{
+					Type: "string",
+				},
+			},
+		},
+	}
+	portProperties := map[string]apiextensionv1.JSONSchemaProps{
+		"port": {
+			Type:    "integer",
+			Minimum: proto.Float64(1),
+			Maximum: proto.Float64(65535),
+		},
+	}
+	for name, props := range backendProperties {
+		portProperties[name] = props
+	}
+
+	version := apiextensionv1.CustomResourceDefinitionVersion{
+		Name:    Version,
+		Served:  true,
+		Storage: true,
+		Schema: &apiextensionv1.CustomResourceValidation{
+			OpenAPIV3Schema: &apiextensionv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]apiextensionv1.JSONSchemaProps{
+					"spec": {
+						Type: "object",
+						Properties: map[string]apiextensionv1.JSONSchemaProps{
+							"networkProfile": {
+								Type: "string",
+							},
+							"applicationProfile": {
+								Type: "string",
+							},
+							"analyticsProfile": {
+								Type: "string",
+							},
+							"loadBalancerIP": {
+								Type: "string",
+							},
+							"backendProperties": {
+								Type:       "object",
+								Properties: backendProperties,
+							},
+							"portProperties": {
+								Type: "array",
+								Items: &apiextensionv1.JSONSchemaPropsOrArray{
+									Schema: &apiextensionv1.JSONSchemaProps{
+										Type:       "object",
+										Required:   []string{"port"},
+										Properties: portProperties,
+									},
+								},
+							},
+						},
+					},
+					"status": {
+						Type: "object",
+						Properties: map[string]apiextensionv1.JSONSchemaProps{
+							"error": {
+								Type: "string",
+							},
+							"status": {
+								Type: "string",
+							},
+						},
+					},
+				},
+			},
+		},
+		Subresources: &apiextensionv1.CustomResourceSubresources{
+			Status: &apiextensionv1.CustomResourceSubresourceStatus{},
+		},
+		AdditionalPrinterColumns: []apiextensionv1.CustomResourceColumnDefinition{
+			{
+				Description: "status of the l4rule object",
+				JSONPath:    ".status.status",
+				Name:        "Status",
+				Type:        "string",
+			},
+			{
+				JSONPath: ".metadata.creationTimestamp",
+				Name:     "Age",
+				Type:     "date",
+			},
+		},
+	}
+	crd := &apiextensionv1.CustomResourceDefinition{
+		TypeMeta:   v1.TypeMeta{},
+		ObjectMeta: v1.ObjectMeta{Name: L4RuleFullCRDName},
+		Spec: apiextensionv1.CustomResourceDefinitionSpec{
+			Group: CRDGroup,
+			Names: apiextensionv1.CustomResourceDefinitionNames{
+				Plural:   L4RuleCRDPlural,
+				Singular: L4RuleCRDSingular,
+				ShortNames: []string{
+					L4RuleCRDSingular,
+				},
+				Kind: reflect.TypeOf(akov1alpha1.L4Rule{}).Name(),
+			},
+			Scope: apiextensionv1.NamespaceScoped,
+			Versions: []apiextensionv1.CustomResourceDefinitionVersion{
+				version,
+			},
+			Conversion: &apiextensionv1.CustomResourceConversion{
+				Strategy: apiextensionv1.ConversionStrategyType("None"),
+			},
+		},
+		Status: apiextensionv1.CustomResourceDefinitionStatus{},
+	}
+
+	_, err := clientset.CustomResourceDefinitions().Create(context.TODO(), crd, v1.CreateOptions{})
+	if err == nil {
+		log.V(0).Info("l4rules.ako.vmware.com CRD created")
+		return nil
+	} else if apierrors.IsAlreadyExists(err) {
+		log.V(0).Info("l4rules.ako.vmware.com CRD already exists")
+		return nil
+	}
+	return err
+}
+
 func createCRDs(cfg *rest.Config, log logr.Logger) error {
 	kubeClient, _ := apiextension.NewForConfig(cfg)
 
@@ -674,6 +842,10 @@ func createCRDs(cfg *rest.Config, log logr.Logger) error {
 	if err != nil {
 		return err
 	}
+	err = createL4RuleCRD(kubeClient, log)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -691,5 +863,9 @@ func deleteCRDs(cfg *rest.Config) error {
 	if err != nil {
 		return err
 	}
+	err = clientset.CustomResourceDefinitions().Delete(context.TODO(), L4RuleFullCRDName, v1.DeleteOptions{})
+	if err != nil {
+		return err
+	}
 	return nil
 }
@@ -107,6 +107,11 @@ func BuildClusterrole(ako akov1alpha1.AKOConfig, r *AKOConfigReconciler, log log
 				Resources: []string{"ingressclasses"},
 				Verbs:     []string{"get", "watch", "list"},
 			},
+			{
+				APIGroups: []string{"discovery.k8s.io"},
+				Resources: []string{"endpointslices"},
+				Verbs:     []string{"get", "watch", "list"},
+			},
 			{
 				APIGroups: []string{""},
 				Resources: []string{"services", "services/status", "secrets"},
@@ -117,6 +122,11 @@ func BuildClusterrole(ako akov1alpha1.AKOConfig, r *AKOConfigReconciler, log log
 				Resources: []string{"events"},
 				Verbs:     []string{"create", "patch", "update"},
 			},
+			{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     []string{"get", "create", "update"},
+			},
 			{
 				APIGroups: []string{"crd.projectcalico.org"},
 				Resources: []string{"blockaffinities"},
@@ -134,7 +144,7 @@ func BuildClusterrole(ako akov1alpha1.AKOConfig, r *AKOConfigReconciler, log log
 			},
 			{
 				APIGroups: []string{"ako.vmware.com"},
-				Resources: []string{"hostrules", "hostrules/status", "httprules", "httprules/status", "aviinfrasettings", "aviinfrasettings/status"},
+				Resources: []string{"hostrules", "hostrules/status", "httprules", "httprules/status", "aviinfrasettings", "aviinfrasettings/status", "l4rules", "l4rules/status"},
 				Verbs:     []string{"get", "watch", "list", "patch", "update"},
 			},
 			{
@@ -170,6 +170,9 @@ func BuildStatefulSet(ako akov1alpha1.AKOConfig, aviSecret corev1.Secret) (appsv
 		return sf, err
 	}
 	var replicas int32 = 1
+	if ako.Spec.ReplicaCount > 0 {
+		replicas = ako.Spec.ReplicaCount
+	}
 	sf.Spec.Replicas = &replicas
 	sf.Spec.ServiceName = ServiceName
 	akoLabels := map[string]string{
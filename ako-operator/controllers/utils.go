@@ -59,8 +59,10 @@ const (
 	NSSyncLabelKey         = "nsSyncLabelKey"
 	NSSyncLabelValue       = "nsSyncLabelValue"
 	TenantName             = "tenantName"
+	TenantsPerNamespace    = "tenantsPerNamespace"
 	NoPGForSni             = "noPGForSni"
 	NsxtT1LR               = "nsxtT1LR"
+	LeaderElection         = "leaderElection"
 )
 
 var SecretEnvVars = map[string]string{
@@ -94,9 +96,11 @@ var ConfigMapEnvVars = map[string]string{
 	"NODE_NETWORK_LIST":          NodeNetworkList,
 	"AKO_API_PORT":               APIServerPort,
 	"TENANT_NAME":                TenantName,
+	"TENANTS_PER_NAMESPACE":      TenantsPerNamespace,
 	"NAMESPACE_SYNC_LABEL_KEY":   NSSyncLabelKey,
 	"NAMESPACE_SYNC_LABEL_VALUE": NSSyncLabelValue,
 	"NSXT_T1_LR":                 NsxtT1LR,
+	"LEADER_ELECTION":            LeaderElection,
 }
 
 func getSFNamespacedName() types.NamespacedName {
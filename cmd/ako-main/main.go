@@ -39,6 +39,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	gwapicrd "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned"
 	svcapi "sigs.k8s.io/service-apis/pkg/client/clientset/versioned"
 )
 
@@ -56,9 +57,14 @@ func main() {
 }
 
 func InitializeAKOApi() {
-	akoApi := api.NewServer(lib.GetAkoApiServerPort(), []models.ApiModel{})
+	akoApi := api.NewServer(lib.GetAkoApiServerPort(), []models.ApiModel{&k8s.IntrospectionModel{}})
 	akoApi.InitApi()
 	lib.SetApiServerInstance(akoApi)
+	utils.SharedAviRestLimiter().Configure(lib.GetAviRestQPS(), 0, lib.GetAviRestMaxConcurrency())
+	if lib.IsDryRun() {
+		utils.AviLog.Warnf("AKO is running in the dry-run mode, the rest operations would be recorded to %s and not executed", lib.GetDryRunFilePath())
+		models.DryRun.SetFilePath(lib.GetDryRunFilePath())
+	}
 }
 
 func InitializeAKC() {
@@ -90,6 +96,7 @@ func InitializeAKC() {
 	var crdClient *crd.Clientset
 	var advl4Client *advl4.Clientset
 	var svcAPIClient *svcapi.Clientset
+	var gwAPIClient *gwapicrd.Clientset
 	var istioClient *istiocrd.Clientset
 	if lib.GetAdvancedL4() {
 		advl4Client, err = advl4.NewForConfig(cfg)
@@ -105,6 +112,13 @@ func InitializeAKC() {
 			}
 			akoControlConfig.SetServicesAPIClientset(svcAPIClient)
 		}
+		if lib.UseGatewayAPI() {
+			gwAPIClient, err = gwapicrd.NewForConfig(cfg)
+			if err != nil {
+				utils.AviLog.Fatalf("Error building gateway-api clientset: %s", err.Error())
+			}
+			akoControlConfig.SetGatewayAPIClientset(gwAPIClient)
+		}
 
 		crdClient, err = crd.NewForConfig(cfg)
 		if err != nil {
@@ -179,6 +193,9 @@ func InitializeAKC() {
 		if lib.UseServicesAPI() {
 			k8s.NewSvcApiInformers(svcAPIClient)
 		}
+		if lib.UseGatewayAPI() {
+			k8s.NewGatewayAPIInformers(gwAPIClient)
+		}
 	}
 	// Set Istio Informers
 	if lib.IsIstioEnabled() {
@@ -296,6 +313,12 @@ func InitializeAKC() {
 	waitGroupMap["graph"] = wgGraph
 	wgStatus := &sync.WaitGroup{}
 	waitGroupMap["status"] = wgStatus
+	if lib.IsLeaderElectionEnabled() {
+		c.RunLeaderElection(kubeClient, stopCh)
+	}
+	if lib.IsAdmissionWebhookEnabled() && !lib.GetAdvancedL4() {
+		k8s.StartAdmissionWebhook(kubeClient, stopCh)
+	}
 	go c.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
 	<-stopCh
 	close(ctrlCh)
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+// ako-sim builds the Avi models for a directory of Kubernetes manifests, using the ingestion and graph layers of AKO,
+// without connecting to the Kubernetes cluster or the Avi controller. The models are printed as JSON or as a tree,
+// along with the virtualservice serving each hostname.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	oshiftfake "github.com/openshift/client-go/route/clientset/versioned/fake"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	manifestsDir  string
+	checkpoint    string
+	outputFormat  string
+	cloudType     string
+	dnsSubdomains string
+	logLevel      string
+)
+
+func main() {
+	flag.Parse()
+	utils.AviLog.SetOutput(os.Stderr)
+	utils.AviLog.SetLevel(strings.ToUpper(logLevel))
+	if manifestsDir == "" {
+		fmt.Fprintln(os.Stderr, "-manifests is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if outputFormat != "json" && outputFormat != "tree" {
+		fmt.Fprintf(os.Stderr, "unknown output format %s, expected json or tree\n", outputFormat)
+		os.Exit(2)
+	}
+	if err := simulate(); err != nil {
+		fmt.Fprintf(os.Stderr, "ako-sim: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func simulate() error {
+	m, err := loadManifests(manifestsDir)
+	if err != nil {
+		return err
+	}
+	m.applyConfigMap()
+
+	kubeClient := k8sfake.NewSimpleClientset(m.kubeObjects...)
+	crdClient := crdfake.NewSimpleClientset(m.crdObjects...)
+	oshiftClient := oshiftfake.NewSimpleClientset(m.routeObjects...)
+	gvrToKind := map[schema.GroupVersionResource]string{
+		lib.CalicoBlockaffinityGVR: "BlockAffinityList",
+		lib.HostSubnetGVR:          "HostSubnetList",
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), gvrToKind, m.dynamicObjects...)
+
+	akoControlConfig := lib.AKOControlConfig()
+	isPrimaryAKO, err := strconv.ParseBool(os.Getenv("PRIMARY_AKO_FLAG"))
+	if err != nil {
+		isPrimaryAKO = true
+	}
+	akoControlConfig.SetAKOInstanceFlag(isPrimaryAKO)
+	akoControlConfig.SetCRDClientset(crdClient)
+	akoControlConfig.SetEventRecorder(lib.AKOEventComponent, kubeClient, true)
+	k8s.NewCRDInformers(crdClient)
+
+	lib.SetCloudType(cloudType)
+	aviObjCache := avicache.SharedAviObjCache()
+	cloudObj := &avicache.AviCloudPropertyCache{Name: utils.CloudName, VType: cloudType}
+	if dnsSubdomains != "" {
+		cloudObj.NSIpamDNS = strings.Split(dnsSubdomains, ",")
+	}
+	aviObjCache.CloudKeyCache.AviCacheAdd(utils.CloudName, cloudObj)
+	if checkpoint != "" {
+		if err := aviObjCache.PopulateCacheFromCheckpoint(checkpoint); err != nil {
+			return err
+		}
+	}
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.SecretInformer,
+		utils.ConfigMapInformer,
+		utils.PodInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+	}
+	if m.hasEpSlices {
+		registeredInformers = append(registeredInformers, utils.EndpointSlicesInformer)
+	} else {
+		registeredInformers = append(registeredInformers, utils.EndpointInformer)
+	}
+	if len(m.routeObjects) > 0 {
+		registeredInformers = append(registeredInformers, utils.RouteInformer)
+	} else {
+		registeredInformers = append(registeredInformers, utils.IngressInformer, utils.IngressClassInformer)
+	}
+
+	informersArg := make(map[string]interface{})
+	informersArg[utils.INFORMERS_OPENSHIFT_CLIENT] = oshiftClient
+	informersArg[utils.INFORMERS_AKO_CLIENT] = crdClient
+	if lib.GetNamespaceToSync() != "" {
+		informersArg[utils.INFORMERS_NAMESPACE] = lib.GetNamespaceToSync()
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: kubeClient}, registeredInformers, informersArg)
+	lib.NewDynamicInformers(dynamicClient)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	if err := k8s.SharedAviController().OfflineSync(stopCh); err != nil {
+		return err
+	}
+
+	if outputFormat == "json" {
+		return printJSON(os.Stdout)
+	}
+	return printTree(os.Stdout)
+}
+
+func init() {
+	flag.StringVar(&manifestsDir, "manifests", "", "Directory of the Kubernetes manifests, in YAML or JSON, including the AKO configmap.")
+	flag.StringVar(&checkpoint, "cache", "", "Cache checkpoint of the Avi controller objects, as saved by AKO. The models are built against an empty cache if not set.")
+	flag.StringVar(&outputFormat, "output", "tree", "Output format of the models, json or tree.")
+	flag.StringVar(&cloudType, "cloud-type", lib.CLOUD_VCENTER, "Type of the Avi cloud.")
+	flag.StringVar(&dnsSubdomains, "dns-subdomains", "", "Comma separated list of the DNS subdomains of the IPAM DNS profile of the cloud.")
+	flag.StringVar(&logLevel, "log-level", "WARN", "Level of the logs, which are written to stderr.")
+}
@@ -0,0 +1,258 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	akoscheme "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/scheme"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	routev1 "github.com/openshift/api/route/v1"
+	routescheme "github.com/openshift/client-go/route/clientset/versioned/scheme"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+// clusterScopedKinds are the kinds read from the manifests, which are not namespaced.
+var clusterScopedKinds = map[string]bool{
+	"Namespace":       true,
+	"Node":            true,
+	"IngressClass":    true,
+	"AviInfraSetting": true,
+	"BlockAffinity":   true,
+	"HostSubnet":      true,
+}
+
+// configMapEnv maps the keys of the AKO configmap to the environment variables set from them in the AKO statefulset.
+var configMapEnv = map[string]string{
+	"controllerVersion":      "CTRL_VERSION",
+	"cniPlugin":              "CNI_PLUGIN",
+	"vipPerNamespace":        "VIP_PER_NAMESPACE",
+	"shardVSSize":            "SHARD_VS_SIZE",
+	"passthroughShardSize":   "PASSTHROUGH_SHARD_SIZE",
+	"primaryInstance":        "PRIMARY_AKO_FLAG",
+	"cloudName":              "CLOUD_NAME",
+	"tenantName":             "TENANT_NAME",
+	"tenantsPerNamespace":    "TENANTS_PER_NAMESPACE",
+	"clusterName":            "CLUSTER_NAME",
+	"enableRHI":              "ENABLE_RHI",
+	"enableEVH":              "ENABLE_EVH",
+	"defaultDomain":          "DEFAULT_DOMAIN",
+	"disableStaticRouteSync": "DISABLE_STATIC_ROUTE_SYNC",
+	"nsSyncLabelKey":         "NAMESPACE_SYNC_LABEL_KEY",
+	"nsSyncLabelValue":       "NAMESPACE_SYNC_LABEL_VALUE",
+	"defaultIngController":   "DEFAULT_ING_CONTROLLER",
+	"serviceEngineGroupName": "SEG_NAME",
+	"bgpPeerLabels":          "BGP_PEER_LABELS",
+	"nodeNetworkList":        "NODE_NETWORK_LIST",
+	"vipNetworkList":         "VIP_NETWORK_LIST",
+	"serviceType":            "SERVICE_TYPE",
+	"nodeKey":                "NODE_KEY",
+	"nodeValue":              "NODE_VALUE",
+	"autoFQDN":               "AUTO_L4_FQDN",
+}
+
+// manifests holds the objects read from the manifests, grouped by the clientset serving them.
+type manifests struct {
+	kubeObjects    []runtime.Object
+	crdObjects     []runtime.Object
+	routeObjects   []runtime.Object
+	dynamicObjects []runtime.Object
+	configMap      *corev1.ConfigMap
+	hasEpSlices    bool
+}
+
+func newScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(kubescheme.AddToScheme(scheme))
+	utilruntime.Must(akoscheme.AddToScheme(scheme))
+	utilruntime.Must(routescheme.AddToScheme(scheme))
+	return scheme
+}
+
+// loadManifests reads the YAML and JSON files in the directory, in the order of their names. A file can have multiple
+// documents, and a document can be a List of objects.
+func loadManifests(dir string) (*manifests, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			if !info.IsDir() {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	m := &manifests{}
+	decoder := serializer.NewCodecFactory(newScheme()).UniversalDeserializer()
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		docs := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+		for {
+			var raw runtime.RawExtension
+			if err := docs.Decode(&raw); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return nil, fmt.Errorf("failed to read %s: %v", file, err)
+			}
+			raw.Raw = bytes.TrimSpace(raw.Raw)
+			if len(raw.Raw) == 0 || string(raw.Raw) == "null" {
+				continue
+			}
+			if err := m.add(decoder, raw.Raw, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+	m.addNamespaces()
+	return m, nil
+}
+
+// addNamespaces adds the namespaces of the objects, which are not part of the manifests, as they would exist in
+// the cluster.
+func (m *manifests) addNamespaces() {
+	namespaces := make(map[string]bool)
+	var objs []runtime.Object
+	objs = append(objs, m.kubeObjects...)
+	objs = append(objs, m.crdObjects...)
+	objs = append(objs, m.routeObjects...)
+	for _, obj := range objs {
+		if ns, ok := obj.(*corev1.Namespace); ok {
+			namespaces[ns.Name] = true
+		}
+	}
+	for _, obj := range objs {
+		objMeta, _ := meta.Accessor(obj)
+		if namespace := objMeta.GetNamespace(); namespace != "" && !namespaces[namespace] {
+			namespaces[namespace] = true
+			m.kubeObjects = append(m.kubeObjects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+		}
+	}
+}
+
+func (m *manifests) add(decoder runtime.Decoder, data []byte, file string) error {
+	obj, gvk, err := decoder.Decode(data, nil, nil)
+	if err != nil {
+		if !runtime.IsNotRegisteredError(err) {
+			return fmt.Errorf("failed to decode an object in %s: %v", file, err)
+		}
+		// The BlockAffinities of Calico and the HostSubnets of OpenShift are served by the dynamic client.
+		unstructuredObj := &unstructured.Unstructured{}
+		if err := unstructuredObj.UnmarshalJSON(data); err != nil {
+			return fmt.Errorf("failed to decode an object in %s: %v", file, err)
+		}
+		gv := unstructuredObj.GroupVersionKind().GroupVersion()
+		if gv != lib.CalicoBlockaffinityGVR.GroupVersion() && gv != lib.HostSubnetGVR.GroupVersion() {
+			utils.AviLog.Warnf("Skipping the object %s/%s of kind %s in %s, the kind is not handled by AKO",
+				unstructuredObj.GetNamespace(), unstructuredObj.GetName(), unstructuredObj.GetKind(), file)
+			return nil
+		}
+		m.dynamicObjects = append(m.dynamicObjects, unstructuredObj)
+		return nil
+	}
+
+	if list, ok := obj.(*corev1.List); ok {
+		for _, item := range list.Items {
+			if err := m.add(decoder, item.Raw, file); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return fmt.Errorf("failed to decode an object in %s: %v", file, err)
+	}
+	if objMeta.GetNamespace() == "" && !clusterScopedKinds[gvk.Kind] {
+		objMeta.SetNamespace(corev1.NamespaceDefault)
+	}
+
+	switch gvk.Group {
+	case akov1alpha1.SchemeGroupVersion.Group:
+		m.crdObjects = append(m.crdObjects, obj)
+	case routev1.GroupName:
+		m.routeObjects = append(m.routeObjects, obj)
+	default:
+		if gvk.Kind == "EndpointSlice" {
+			m.hasEpSlices = true
+		}
+		if configMap, ok := obj.(*corev1.ConfigMap); ok && configMap.Name == lib.AviConfigMap {
+			m.configMap = configMap
+		}
+		m.kubeObjects = append(m.kubeObjects, obj)
+	}
+	return nil
+}
+
+// applyConfigMap sets the AKO configuration from the configmap, the way the AKO statefulset does. The environment
+// variables which are already set take precedence over the configmap.
+func (m *manifests) applyConfigMap() {
+	if _, found := os.LookupEnv("POD_NAMESPACE"); !found {
+		namespace := utils.AKO_DEFAULT_NS
+		if m.configMap != nil {
+			namespace = m.configMap.Namespace
+		}
+		os.Setenv("POD_NAMESPACE", namespace)
+	}
+	if m.configMap == nil {
+		utils.AviLog.Warnf("ConfigMap %s not found in the manifests, using the AKO configuration from the environment", lib.AviConfigMap)
+	} else {
+		for key, env := range configMapEnv {
+			if value, found := m.configMap.Data[key]; found {
+				if _, found := os.LookupEnv(env); !found {
+					os.Setenv(env, value)
+				}
+			}
+		}
+		lib.SetLayer7Only(m.configMap.Data["layer7Only"])
+		lib.SetNoPGForSNI(m.configMap.Data["noPGForSNI"])
+	}
+
+	cloudName := os.Getenv("CLOUD_NAME")
+	if cloudName == "" {
+		cloudName = "Default-Cloud"
+	}
+	utils.SetCloudName(cloudName)
+}
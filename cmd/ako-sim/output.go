@@ -0,0 +1,222 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+)
+
+// ShardAssignment is the virtualservice serving a hostname. Child is the SNI or EVH child of the virtualservice
+// for the hostname, if any.
+type ShardAssignment struct {
+	Host           string `json:"host"`
+	Model          string `json:"model"`
+	VirtualService string `json:"virtualservice"`
+	Child          string `json:"child,omitempty"`
+}
+
+type simulationResult struct {
+	Models []k8s.ModelDump   `json:"models"`
+	Shards []ShardAssignment `json:"shards"`
+}
+
+// aviModels returns the models built by the graph layer, sorted by name. The models of the vrf context and of the
+// deleted objects are skipped.
+func aviModels() (names []string, models map[string]*nodes.AviObjectGraph) {
+	models = make(map[string]*nodes.AviObjectGraph)
+	for modelName, modelIntf := range objects.SharedAviGraphLister().GetAll().(map[string]interface{}) {
+		aviModel, ok := modelIntf.(*nodes.AviObjectGraph)
+		if !ok || aviModel == nil || (len(aviModel.GetAviVS()) == 0 && len(aviModel.GetAviEvhVS()) == 0) {
+			continue
+		}
+		names = append(names, modelName)
+		models[modelName] = aviModel
+	}
+	sort.Strings(names)
+	return names, models
+}
+
+func shardAssignments(names []string, models map[string]*nodes.AviObjectGraph) []ShardAssignment {
+	shards := []ShardAssignment{}
+	for _, modelName := range names {
+		assignments := make(map[string]*ShardAssignment)
+		assign := func(host, vsName, child string) {
+			if host == "" {
+				return
+			}
+			if shard, found := assignments[host]; found {
+				if child != "" {
+					shard.Child = child
+				}
+				return
+			}
+			assignments[host] = &ShardAssignment{Host: host, Model: modelName, VirtualService: vsName, Child: child}
+		}
+		for _, vsNode := range models[modelName].GetAviVS() {
+			for _, vsvip := range vsNode.VSVIPRefs {
+				for _, fqdn := range vsvip.FQDNs {
+					assign(fqdn, vsNode.Name, "")
+				}
+			}
+			for _, host := range vsNode.VHDomainNames {
+				assign(host, vsNode.Name, "")
+			}
+			for _, sniNode := range vsNode.SniNodes {
+				for _, host := range sniNode.VHDomainNames {
+					assign(host, vsNode.Name, sniNode.Name)
+				}
+			}
+		}
+		for _, evhNode := range models[modelName].GetAviEvhVS() {
+			for _, vsvip := range evhNode.VSVIPRefs {
+				for _, fqdn := range vsvip.FQDNs {
+					assign(fqdn, evhNode.Name, "")
+				}
+			}
+			for _, evhChild := range evhNode.EvhNodes {
+				for _, host := range evhChild.VHDomainNames {
+					assign(host, evhNode.Name, evhChild.Name)
+				}
+			}
+		}
+		var hosts []string
+		for host := range assignments {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		for _, host := range hosts {
+			shards = append(shards, *assignments[host])
+		}
+	}
+	return shards
+}
+
+func printJSON(w io.Writer) error {
+	names, models := aviModels()
+	result := simulationResult{Models: []k8s.ModelDump{}}
+	for _, modelName := range names {
+		result.Models = append(result.Models, k8s.NewModelDump(modelName, models[modelName]))
+	}
+	result.Shards = shardAssignments(names, models)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// treePrinter prints the virtualservices of the models with the objects referred by them, one object per line.
+type treePrinter struct {
+	w io.Writer
+}
+
+func (p *treePrinter) line(depth int, format string, args ...interface{}) {
+	fmt.Fprintf(p.w, "%s%s\n", strings.Repeat("  ", depth), fmt.Sprintf(format, args...))
+}
+
+func (p *treePrinter) vsvips(depth int, vsvips []*nodes.AviVSVIPNode) {
+	for _, vsvip := range vsvips {
+		p.line(depth, "vsvip %s ip=%q fqdns=[%s]", vsvip.Name, vsvip.IPAddress, strings.Join(vsvip.FQDNs, ","))
+	}
+}
+
+func (p *treePrinter) pools(depth int, pools []*nodes.AviPoolNode) {
+	for _, pool := range pools {
+		var servers []string
+		for _, server := range pool.Servers {
+			if server.Ip.Addr == nil {
+				continue
+			}
+			if server.Port != 0 {
+				servers = append(servers, fmt.Sprintf("%s:%d", *server.Ip.Addr, server.Port))
+			} else {
+				servers = append(servers, *server.Ip.Addr)
+			}
+		}
+		p.line(depth, "pool %s port=%d servers=[%s]", pool.Name, pool.Port, strings.Join(servers, ","))
+	}
+}
+
+func (p *treePrinter) poolGroups(depth int, poolGroups []*nodes.AviPoolGroupNode) {
+	for _, pg := range poolGroups {
+		var members []string
+		for _, member := range pg.Members {
+			if member.PoolRef != nil {
+				members = append(members, strings.TrimPrefix(*member.PoolRef, "/api/pool?name="))
+			}
+		}
+		p.line(depth, "poolgroup %s members=[%s]", pg.Name, strings.Join(members, ","))
+	}
+}
+
+func (p *treePrinter) policies(depth int, httpPolicies []*nodes.AviHttpPolicySetNode, l4Policies []*nodes.AviL4PolicyNode) {
+	for _, httpPolicy := range httpPolicies {
+		p.line(depth, "httppolicyset %s", httpPolicy.Name)
+	}
+	for _, l4Policy := range l4Policies {
+		p.line(depth, "l4policyset %s", l4Policy.Name)
+	}
+}
+
+func (p *treePrinter) vs(depth int, kind string, vsNode *nodes.AviVsNode) {
+	p.line(depth, "%s %s hosts=[%s]", kind, vsNode.Name, strings.Join(vsNode.VHDomainNames, ","))
+	p.vsvips(depth+1, vsNode.VSVIPRefs)
+	p.poolGroups(depth+1, vsNode.PoolGroupRefs)
+	p.pools(depth+1, vsNode.PoolRefs)
+	p.policies(depth+1, vsNode.HttpPolicyRefs, vsNode.L4PolicyRefs)
+	for _, sniNode := range vsNode.SniNodes {
+		p.vs(depth+1, "sni", sniNode)
+	}
+}
+
+func (p *treePrinter) evhVS(depth int, kind string, evhNode *nodes.AviEvhVsNode) {
+	p.line(depth, "%s %s hosts=[%s]", kind, evhNode.Name, strings.Join(evhNode.VHDomainNames, ","))
+	p.vsvips(depth+1, evhNode.VSVIPRefs)
+	p.poolGroups(depth+1, evhNode.PoolGroupRefs)
+	p.pools(depth+1, evhNode.PoolRefs)
+	p.policies(depth+1, evhNode.HttpPolicyRefs, nil)
+	for _, evhChild := range evhNode.EvhNodes {
+		p.evhVS(depth+1, "evh", evhChild)
+	}
+}
+
+func printTree(w io.Writer) error {
+	names, models := aviModels()
+	p := &treePrinter{w: w}
+	for _, modelName := range names {
+		p.line(0, "model %s", modelName)
+		for _, vsNode := range models[modelName].GetAviVS() {
+			p.vs(1, "virtualservice", vsNode)
+		}
+		for _, evhNode := range models[modelName].GetAviEvhVS() {
+			p.evhVS(1, "virtualservice", evhNode)
+		}
+	}
+	p.line(0, "shards")
+	for _, shard := range shardAssignments(names, models) {
+		if shard.Child != "" {
+			p.line(1, "%s -> %s (%s)", shard.Host, shard.VirtualService, shard.Child)
+		} else {
+			p.line(1, "%s -> %s", shard.Host, shard.VirtualService)
+		}
+	}
+	return nil
+}
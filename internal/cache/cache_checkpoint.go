@@ -0,0 +1,784 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
+)
+
+const (
+	cacheCheckpointVersion = "2"
+	// Number of uuids fetched in a single request during an incremental sync.
+	checkpointFetchBatchSize = 50
+)
+
+// cacheCheckpoint is the state of the objects created by AKO on the Avi controller at the last sync of the
+// cache. During the next sync, only the objects whose _last_modified changed after the checkpoint are fetched,
+// the rest are picked from the cache. In memory, the checkpoint holds only the _last_modified of the objects. The
+// objects are copied into the checkpoint when it is persisted in the AKO volume, which is done only if USE_PVC is
+// enabled, so that a restarted AKO syncs its cache incrementally as well.
+type cacheCheckpoint struct {
+	Version        string `json:"version"`
+	ControllerUUID string `json:"controllerUUID"`
+	Cloud          string `json:"cloud"`
+	ClusterName    string `json:"clusterName"`
+	Tenant         string `json:"tenant"`
+
+	// LastModified holds the _last_modified of the objects of each collection, keyed by the object uuid.
+	LastModified map[string]map[string]string `json:"lastModified"`
+
+	PkiProfiles     []AviPkiProfileCache    `json:"pkiProfiles,omitempty"`
+	HealthMonitors  []AviHealthMonitorCache `json:"healthMonitors,omitempty"`
+	AppProfiles     []AviAppProfileCache    `json:"appProfiles,omitempty"`
+	Pools           []AviPoolCache          `json:"pools,omitempty"`
+	PoolGroups      []AviPGCache            `json:"poolGroups,omitempty"`
+	DataScripts     []AviDSCache            `json:"dataScripts,omitempty"`
+	SSLKeys         []AviSSLCache           `json:"sslKeys,omitempty"`
+	VSVips          []AviVSVIPCache         `json:"vsVips,omitempty"`
+	HTTPPolicySets  []AviHTTPPolicyCache    `json:"httpPolicySets,omitempty"`
+	L4PolicySets    []AviL4PolicyCache      `json:"l4PolicySets,omitempty"`
+	VirtualServices []*AviVsCache           `json:"virtualServices,omitempty"`
+
+	lock sync.Mutex
+	// loaded holds the objects of the checkpoint read from the AKO volume, keyed by the collection. The unchanged
+	// objects are picked from these during the first sync after a restart, as the cache is empty.
+	loaded map[string]*AviCache
+	// changed holds the keys of the objects which are fetched or deleted during the ongoing sync, the virtualservices
+	// referring to them are fetched again.
+	changed    map[NamespaceName]bool
+	allChanged bool
+}
+
+func newCacheCheckpoint(cloud string) *cacheCheckpoint {
+	return &cacheCheckpoint{
+		Version:        cacheCheckpointVersion,
+		ControllerUUID: GetControllerClusterUUID(),
+		Cloud:          cloud,
+		ClusterName:    lib.GetClusterName(),
+		Tenant:         lib.GetCacheTenant(),
+		LastModified:   make(map[string]map[string]string),
+		changed:        make(map[NamespaceName]bool),
+	}
+}
+
+// matches checks whether the checkpoint was recorded by this AKO instance, for the same controller and cloud.
+func (cp *cacheCheckpoint) matches(other *cacheCheckpoint) bool {
+	return cp.Version == other.Version &&
+		cp.ControllerUUID != "" &&
+		cp.ControllerUUID == other.ControllerUUID &&
+		cp.Cloud == other.Cloud &&
+		cp.ClusterName == other.ClusterName &&
+		cp.Tenant == other.Tenant
+}
+
+func (cp *cacheCheckpoint) record(collection string, delta *collectionDelta) {
+	if cp == nil || delta == nil || delta.failed {
+		return
+	}
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	cp.LastModified[collection] = delta.lastModified
+}
+
+func (cp *cacheCheckpoint) markChanged(key NamespaceName) {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	cp.changed[key] = true
+}
+
+func (cp *cacheCheckpoint) markAllChanged() {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	cp.allChanged = true
+}
+
+// childrenChanged checks whether any of the objects referred by the virtualservice changed during the ongoing sync.
+func (cp *cacheCheckpoint) childrenChanged(vs *AviVsCache) bool {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	if cp.allChanged {
+		return true
+	}
+	collections := [][]NamespaceName{
+		vs.PGKeyCollection,
+		vs.VSVipKeyCollection,
+		vs.PoolKeyCollection,
+		vs.DSKeyCollection,
+		vs.HTTPKeyCollection,
+		vs.SSLKeyCertCollection,
+		vs.L4PolicyCollection,
+	}
+	for _, keys := range collections {
+		for _, key := range keys {
+			if cp.changed[key] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectionDelta holds the objects of a collection which changed after the checkpoint.
+type collectionDelta struct {
+	collection string
+	uri        string
+	// lastModified holds the _last_modified of all the objects of the collection on the controller.
+	lastModified map[string]string
+	// full is set if the collection is not part of the checkpoint, and has to be fetched entirely.
+	full bool
+	// fetch holds the uuids of the objects which are new or modified after the checkpoint.
+	fetch  map[string]bool
+	failed bool
+}
+
+// reuse checks whether the object from the checkpoint is unchanged and can be added to the cache as is.
+func (d *collectionDelta) reuse(uuid string) bool {
+	_, found := d.lastModified[uuid]
+	return found && !d.fetch[uuid]
+}
+
+// setError marks the delta as failed, so that the collection is not checkpointed and is fetched entirely during
+// the next sync.
+func (d *collectionDelta) setError(err error) {
+	if d != nil && err != nil {
+		d.failed = true
+	}
+}
+
+// fetchUris returns the uris to fetch the new or modified objects of the collection.
+func (d *collectionDelta) fetchUris() []string {
+	var uuids, uris []string
+	for uuid := range d.fetch {
+		uuids = append(uuids, uuid)
+	}
+	for i := 0; i < len(uuids); i += checkpointFetchBatchSize {
+		end := i + checkpointFetchBatchSize
+		if end > len(uuids) {
+			end = len(uuids)
+		}
+		uris = append(uris, d.uri+"&uuid.in="+strings.Join(uuids[i:end], ",")+"&page_size=100")
+	}
+	return uris
+}
+
+// collectionDelta lists the uuid and _last_modified of the objects of the collection, and compares them against
+// the checkpoint. Returns nil if the objects can't be listed, in which case the collection is fetched entirely and
+// is not checkpointed.
+func (c *AviObjCache) collectionDelta(client *clients.AviClient, collection, uri string) *collectionDelta {
+	if c.nextCheckpoint == nil {
+		return nil
+	}
+	lastModified := make(map[string]string)
+	listUri := uri + "&fields=uuid,_last_modified&page_size=200"
+	for listUri != "" {
+		result, err := lib.AviGetCollectionRaw(client, listUri)
+		if err != nil {
+			utils.AviLog.Warnf("Get uri %v returned err for %s %v", listUri, collection, err)
+			c.nextCheckpoint.markAllChanged()
+			return nil
+		}
+		var elems []map[string]interface{}
+		if err = json.Unmarshal(result.Results, &elems); err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal %s data, err: %v", collection, err)
+			c.nextCheckpoint.markAllChanged()
+			return nil
+		}
+		for _, elem := range elems {
+			uuid, ok := elem["uuid"].(string)
+			if !ok {
+				continue
+			}
+			lastModified[uuid], _ = elem["_last_modified"].(string)
+		}
+		listUri = ""
+		if next := strings.Split(result.Next, "/api/"+collection); result.Next != "" && len(next) > 1 {
+			listUri = "/api/" + collection + next[1]
+		}
+	}
+
+	delta := &collectionDelta{
+		collection:   collection,
+		uri:          uri,
+		lastModified: lastModified,
+		fetch:        make(map[string]bool),
+	}
+	var checkpointed map[string]string
+	if c.checkpoint != nil {
+		checkpointed = c.checkpoint.LastModified[collection]
+	}
+	if checkpointed == nil {
+		delta.full = true
+		c.nextCheckpoint.markAllChanged()
+		return delta
+	}
+	for uuid, modified := range lastModified {
+		if old, found := checkpointed[uuid]; !found || old != modified || modified == "" {
+			delta.fetch[uuid] = true
+		}
+	}
+	utils.AviLog.Infof("Incremental sync of %s: %d objects on the controller, %d new or modified since the checkpoint",
+		collection, len(lastModified), len(delta.fetch))
+	return delta
+}
+
+// loadCacheCheckpoint prepares the checkpoint for the sync of the cache. The checkpoint of the last sync is read from
+// the AKO volume if this is the first sync after a restart.
+func (c *AviObjCache) loadCacheCheckpoint(cloud string) {
+	c.nextCheckpoint = newCacheCheckpoint(cloud)
+	if c.checkpoint == nil {
+		c.checkpoint = readCacheCheckpoint()
+	}
+	if c.checkpoint != nil && !c.checkpoint.matches(c.nextCheckpoint) {
+		utils.AviLog.Infof("Cache checkpoint is not valid for the controller %s and cloud %s, syncing all the objects",
+			c.nextCheckpoint.ControllerUUID, cloud)
+		c.checkpoint = nil
+	}
+}
+
+// AviObjCacheRefresh syncs the cache through the checkpoint of the last sync, so that only the objects created or
+// modified since are fetched from the Avi controller. The cache is populated afresh if it has no checkpoint, as is
+// the case when the last sync failed.
+func (c *AviObjCache) AviObjCacheRefresh(client []*clients.AviClient, cloud string) error {
+	if c.checkpoint == nil {
+		utils.AviLog.Infof("No checkpoint found for the avi cache, populating the cache afresh")
+	}
+	_, _, err := c.AviObjCachePopulate(client, utils.CtrlVersion, cloud)
+	return err
+}
+
+// saveCacheCheckpoint replaces the checkpoint with the one recorded during the sync, once the sync succeeds.
+func (c *AviObjCache) saveCacheCheckpoint(synced bool) {
+	if c.nextCheckpoint == nil {
+		return
+	}
+	if synced {
+		c.checkpoint = c.nextCheckpoint
+		if lib.GetCacheCheckpointFilePath() != "" {
+			writeCacheCheckpoint(c.buildCacheCheckpoint(c.checkpoint))
+		}
+	}
+	c.nextCheckpoint = nil
+}
+
+// buildCacheCheckpoint copies the objects of the checkpoint from the cache, to persist the checkpoint in the AKO
+// volume.
+func (c *AviObjCache) buildCacheCheckpoint(checkpoint *cacheCheckpoint) *cacheCheckpoint {
+	persisted := &cacheCheckpoint{
+		Version:        checkpoint.Version,
+		ControllerUUID: checkpoint.ControllerUUID,
+		Cloud:          checkpoint.Cloud,
+		ClusterName:    checkpoint.ClusterName,
+		Tenant:         checkpoint.Tenant,
+		LastModified:   checkpoint.LastModified,
+	}
+	for collection, lastModified := range checkpoint.LastModified {
+		objCache := c.collectionCache(collection)
+		if objCache == nil {
+			continue
+		}
+		for _, obj := range objCache.ShallowCopy() {
+			if _, found := lastModified[cacheObjectUuid(obj)]; !found {
+				continue
+			}
+			switch obj := obj.(type) {
+			case *AviPkiProfileCache:
+				persisted.PkiProfiles = append(persisted.PkiProfiles, *obj)
+			case *AviHealthMonitorCache:
+				persisted.HealthMonitors = append(persisted.HealthMonitors, *obj)
+			case *AviAppProfileCache:
+				persisted.AppProfiles = append(persisted.AppProfiles, *obj)
+			case *AviPoolCache:
+				persisted.Pools = append(persisted.Pools, *obj)
+			case *AviPGCache:
+				persisted.PoolGroups = append(persisted.PoolGroups, *obj)
+			case *AviDSCache:
+				persisted.DataScripts = append(persisted.DataScripts, *obj)
+			case *AviSSLCache:
+				persisted.SSLKeys = append(persisted.SSLKeys, *obj)
+			case *AviVSVIPCache:
+				persisted.VSVips = append(persisted.VSVips, *obj)
+			case *AviHTTPPolicyCache:
+				persisted.HTTPPolicySets = append(persisted.HTTPPolicySets, *obj)
+			case *AviL4PolicyCache:
+				persisted.L4PolicySets = append(persisted.L4PolicySets, *obj)
+			case *AviVsCache:
+				if vsCopy, done := obj.GetVSCopy(); done {
+					persisted.VirtualServices = append(persisted.VirtualServices, vsCopy)
+				}
+			}
+		}
+	}
+	return persisted
+}
+
+// objectCaches returns the objects of the checkpoint, keyed by the collection.
+func (cp *cacheCheckpoint) objectCaches() map[string]*AviCache {
+	caches := make(map[string]*AviCache)
+	add := func(collection, tenant, name string, obj interface{}) {
+		if caches[collection] == nil {
+			caches[collection] = NewAviCache()
+		}
+		caches[collection].AviCacheAdd(NamespaceName{Namespace: tenant, Name: name}, obj)
+	}
+	for i, pki := range cp.PkiProfiles {
+		add("pkiprofile", pki.Tenant, pki.Name, &cp.PkiProfiles[i])
+	}
+	for i, hm := range cp.HealthMonitors {
+		add("healthmonitor", hm.Tenant, hm.Name, &cp.HealthMonitors[i])
+	}
+	for i, appProfile := range cp.AppProfiles {
+		add("applicationprofile", appProfile.Tenant, appProfile.Name, &cp.AppProfiles[i])
+	}
+	for i, pool := range cp.Pools {
+		add("pool", pool.Tenant, pool.Name, &cp.Pools[i])
+	}
+	for i, pg := range cp.PoolGroups {
+		add("poolgroup", pg.Tenant, pg.Name, &cp.PoolGroups[i])
+	}
+	for i, ds := range cp.DataScripts {
+		add("vsdatascriptset", ds.Tenant, ds.Name, &cp.DataScripts[i])
+	}
+	for i, sslKey := range cp.SSLKeys {
+		add("sslkeyandcertificate", sslKey.Tenant, sslKey.Name, &cp.SSLKeys[i])
+	}
+	for i, vsVip := range cp.VSVips {
+		add("vsvip", vsVip.Tenant, vsVip.Name, &cp.VSVips[i])
+	}
+	for i, httpPol := range cp.HTTPPolicySets {
+		add("httppolicyset", httpPol.Tenant, httpPol.Name, &cp.HTTPPolicySets[i])
+	}
+	for i, l4Pol := range cp.L4PolicySets {
+		add("l4policyset", l4Pol.Tenant, l4Pol.Name, &cp.L4PolicySets[i])
+	}
+	for _, vs := range cp.VirtualServices {
+		if vs != nil {
+			add("virtualservice", vs.Tenant, vs.Name, vs)
+		}
+	}
+	return caches
+}
+
+func readCacheCheckpoint() *cacheCheckpoint {
+	filePath := lib.GetCacheCheckpointFilePath()
+	if filePath == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			utils.AviLog.Warnf("Failed to read the cache checkpoint %s, err: %v", filePath, err)
+		}
+		return nil
+	}
+	checkpoint := &cacheCheckpoint{}
+	if err = json.Unmarshal(data, checkpoint); err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal the cache checkpoint %s, err: %v", filePath, err)
+		return nil
+	}
+	checkpoint.loaded = checkpoint.objectCaches()
+	utils.AviLog.Infof("Read the cache checkpoint from %s", filePath)
+	return checkpoint
+}
+
+func writeCacheCheckpoint(checkpoint *cacheCheckpoint) {
+	filePath := lib.GetCacheCheckpointFilePath()
+	if filePath == "" {
+		return
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to marshal the cache checkpoint, err: %v", err)
+		return
+	}
+	// Write to a temporary file first, so that a crash doesn't leave a partial checkpoint behind.
+	tmpFilePath := filePath + ".tmp"
+	if err = ioutil.WriteFile(tmpFilePath, data, 0644); err != nil {
+		utils.AviLog.Warnf("Failed to write the cache checkpoint %s, err: %v", tmpFilePath, err)
+		return
+	}
+	if err = os.Rename(tmpFilePath, filePath); err != nil {
+		utils.AviLog.Warnf("Failed to write the cache checkpoint %s, err: %v", filePath, err)
+		return
+	}
+	utils.AviLog.Infof("Saved the cache checkpoint to %s", filePath)
+}
+
+func collectionUri(collection, cloud string, cloudScoped bool) string {
+	uri := "/api/" + collection + "/?include_name=true&created_by=" + lib.AKOUser
+	if cloudScoped {
+		uri += "&cloud_ref.name=" + cloud
+	}
+	return uri
+}
+
+// collectionCache returns the cache holding the objects of the collection.
+func (c *AviObjCache) collectionCache(collection string) *AviCache {
+	switch collection {
+	case "pkiprofile":
+		return c.PKIProfileCache
+	case "healthmonitor":
+		return c.HealthMonitorCache
+	case "applicationprofile":
+		return c.AppProfileCache
+	case "pool":
+		return c.PoolCache
+	case "poolgroup":
+		return c.PgCache
+	case "vsdatascriptset":
+		return c.DSCache
+	case "sslkeyandcertificate":
+		return c.SSLKeyCache
+	case "vsvip":
+		return c.VSVIPCache
+	case "httppolicyset":
+		return c.HTTPPolicyCache
+	case "l4policyset":
+		return c.L4PolicyCache
+	case "virtualservice":
+		return c.VsCacheMeta
+	}
+	return nil
+}
+
+func cacheObjectUuid(obj interface{}) string {
+	switch obj := obj.(type) {
+	case *AviPkiProfileCache:
+		return obj.Uuid
+	case *AviHealthMonitorCache:
+		return obj.Uuid
+	case *AviAppProfileCache:
+		return obj.Uuid
+	case *AviPoolCache:
+		return obj.Uuid
+	case *AviPGCache:
+		return obj.Uuid
+	case *AviDSCache:
+		return obj.Uuid
+	case *AviSSLCache:
+		return obj.Uuid
+	case *AviVSVIPCache:
+		return obj.Uuid
+	case *AviHTTPPolicyCache:
+		return obj.Uuid
+	case *AviL4PolicyCache:
+		return obj.Uuid
+	case *AviVsCache:
+		return obj.Uuid
+	}
+	return ""
+}
+
+// unchangedObjects returns the objects of the collection which are unchanged since the checkpoint, picked from the
+// objects read from the AKO volume after a restart, and from the cache otherwise. The objects modified or deleted
+// since the checkpoint are marked as changed, and the objects missing in the cache are marked to be fetched.
+func (c *AviObjCache) unchangedObjects(collection string, delta *collectionDelta) map[interface{}]interface{} {
+	source := c.collectionCache(collection)
+	if c.checkpoint != nil && c.checkpoint.loaded != nil {
+		source = c.checkpoint.loaded[collection]
+	}
+	unchanged := make(map[interface{}]interface{})
+	found := make(map[string]bool)
+	if source != nil {
+		for key, obj := range source.ShallowCopy() {
+			uuid := cacheObjectUuid(obj)
+			if delta.reuse(uuid) {
+				unchanged[key] = obj
+				found[uuid] = true
+			} else if nsName, ok := key.(NamespaceName); ok {
+				c.nextCheckpoint.markChanged(nsName)
+			}
+		}
+	}
+	for uuid := range delta.lastModified {
+		if !found[uuid] {
+			delta.fetch[uuid] = true
+		}
+	}
+	return unchanged
+}
+
+// fetchCollection fetches the objects of the collection. Only the objects which are new or modified since the
+// checkpoint are fetched with populate, from the uri of the page given, or from the entire collection if no page is
+// given. add is called with every unchanged object picked from the cache, and returns false if the object has to be
+// fetched nevertheless.
+func (c *AviObjCache) fetchCollection(client *clients.AviClient, collection, uri string,
+	populate func(nextPage ...NextPage) error, add func(obj interface{}) bool) error {
+	var err error
+	delta := c.collectionDelta(client, collection, uri)
+	if delta == nil || delta.full {
+		err = populate()
+	} else {
+		for _, obj := range c.unchangedObjects(collection, delta) {
+			if !add(obj) {
+				delta.fetch[cacheObjectUuid(obj)] = true
+			}
+		}
+		for _, uri := range delta.fetchUris() {
+			if fetchErr := populate(NextPage{Next_uri: uri}); fetchErr != nil {
+				err = fetchErr
+			}
+		}
+	}
+	delta.setError(err)
+	c.nextCheckpoint.record(collection, delta)
+	return err
+}
+
+func (c *AviObjCache) fetchPkiProfiles(client *clients.AviClient) []AviPkiProfileCache {
+	var pkiData []AviPkiProfileCache
+	c.fetchCollection(client, "pkiprofile", collectionUri("pkiprofile", "", false),
+		func(nextPage ...NextPage) error {
+			_, _, err := c.AviPopulateAllPkiPRofiles(client, &pkiData, nextPage...)
+			return err
+		},
+		func(obj interface{}) bool {
+			pki, ok := obj.(*AviPkiProfileCache)
+			if ok {
+				pkiData = append(pkiData, *pki)
+			}
+			return ok
+		})
+	return pkiData
+}
+
+func (c *AviObjCache) fetchHealthMonitors(client *clients.AviClient) []AviHealthMonitorCache {
+	var hmData []AviHealthMonitorCache
+	// healthmonitors have no created_by field, the ones created by AKO are filtered with the name prefix.
+	uri := "/api/healthmonitor/?name.contains=" + lib.GetNamePrefix() + "&include_name=true"
+	c.fetchCollection(client, "healthmonitor", uri,
+		func(nextPage ...NextPage) error {
+			_, _, err := c.AviPopulateAllHealthMonitors(client, &hmData, nextPage...)
+			return err
+		},
+		func(obj interface{}) bool {
+			hm, ok := obj.(*AviHealthMonitorCache)
+			if ok {
+				hmData = append(hmData, *hm)
+			}
+			return ok
+		})
+	return hmData
+}
+
+func (c *AviObjCache) fetchAppProfiles(client *clients.AviClient) []AviAppProfileCache {
+	var appProfileData []AviAppProfileCache
+	c.fetchCollection(client, "applicationprofile", collectionUri("applicationprofile", "", false),
+		func(nextPage ...NextPage) error {
+			_, _, err := c.AviPopulateAllAppProfiles(client, &appProfileData, nextPage...)
+			return err
+		},
+		func(obj interface{}) bool {
+			appProfile, ok := obj.(*AviAppProfileCache)
+			if ok {
+				appProfileData = append(appProfileData, *appProfile)
+			}
+			return ok
+		})
+	return appProfileData
+}
+
+func (c *AviObjCache) fetchPools(client *clients.AviClient, cloud string) []AviPoolCache {
+	var poolsData []AviPoolCache
+	c.fetchCollection(client, "pool", collectionUri("pool", cloud, true),
+		func(nextPage ...NextPage) error {
+			_, _, err := c.AviPopulateAllPools(client, cloud, &poolsData, nextPage...)
+			return err
+		},
+		func(obj interface{}) bool {
+			pool, ok := obj.(*AviPoolCache)
+			if ok {
+				poolsData = append(poolsData, *pool)
+			}
+			return ok
+		})
+	return poolsData
+}
+
+func (c *AviObjCache) fetchPGs(client *clients.AviClient, cloud string) []AviPGCache {
+	var pgData []AviPGCache
+	c.fetchCollection(client, "poolgroup", collectionUri("poolgroup", cloud, true),
+		func(nextPage ...NextPage) error {
+			_, _, err := c.AviPopulateAllPGs(client, cloud, &pgData, nextPage...)
+			return err
+		},
+		func(obj interface{}) bool {
+			pg, ok := obj.(*AviPGCache)
+			if ok {
+				pgData = append(pgData, *pg)
+			}
+			return ok
+		})
+	return pgData
+}
+
+func (c *AviObjCache) fetchDSs(client *clients.AviClient, cloud string) []AviDSCache {
+	var dsData []AviDSCache
+	c.fetchCollection(client, "vsdatascriptset", collectionUri("vsdatascriptset", cloud, false),
+		func(nextPage ...NextPage) error {
+			_, _, err := c.AviPopulateAllDSs(client, cloud, &dsData, nextPage...)
+			return err
+		},
+		func(obj interface{}) bool {
+			ds, ok := obj.(*AviDSCache)
+			if ok {
+				dsData = append(dsData, *ds)
+			}
+			return ok
+		})
+	return dsData
+}
+
+func (c *AviObjCache) fetchSSLKeys(client *clients.AviClient, cloud string) []AviSSLCache {
+	var sslData []AviSSLCache
+	c.fetchCollection(client, "sslkeyandcertificate", collectionUri("sslkeyandcertificate", cloud, false),
+		func(nextPage ...NextPage) error {
+			_, _, err := c.AviPopulateAllSSLKeys(client, cloud, &sslData, nextPage...)
+			return err
+		},
+		func(obj interface{}) bool {
+			sslKey, ok := obj.(*AviSSLCache)
+			if ok {
+				sslData = append(sslData, *sslKey)
+			}
+			return ok
+		})
+	return sslData
+}
+
+func (c *AviObjCache) fetchVSVips(client *clients.AviClient, cloud string) []AviVSVIPCache {
+	var vsVipData []AviVSVIPCache
+	// vsvips are not filtered with created_by, since the ones created by the AKO of older releases are not marked.
+	uri := "/api/vsvip/?name.contains=" + lib.GetNamePrefix() + "&include_name=true&cloud_ref.name=" + cloud
+	c.fetchCollection(client, "vsvip", uri,
+		func(nextPage ...NextPage) error {
+			_, err := c.AviPopulateAllVSVips(client, cloud, &vsVipData, nextPage...)
+			return err
+		},
+		func(obj interface{}) bool {
+			vsVip, ok := obj.(*AviVSVIPCache)
+			if ok {
+				vsVipData = append(vsVipData, *vsVip)
+			}
+			return ok
+		})
+	return vsVipData
+}
+
+func (c *AviObjCache) fetchHttpPolicySets(client *clients.AviClient, cloud string) ([]AviHTTPPolicyCache, error) {
+	var httpPolData []AviHTTPPolicyCache
+	err := c.fetchCollection(client, "httppolicyset", collectionUri("httppolicyset", cloud, false),
+		func(nextPage ...NextPage) error {
+			var fetched []AviHTTPPolicyCache
+			_, count, err := c.AviPopulateAllHttpPolicySets(client, cloud, &fetched, nextPage...)
+			if err == nil && len(nextPage) == 0 && len(fetched) != count {
+				err = fmt.Errorf("fetched %d of %d httppolicysets", len(fetched), count)
+			}
+			httpPolData = append(httpPolData, fetched...)
+			return err
+		},
+		func(obj interface{}) bool {
+			httpPol, ok := obj.(*AviHTTPPolicyCache)
+			if ok {
+				httpPolData = append(httpPolData, *httpPol)
+			}
+			return ok
+		})
+	return httpPolData, err
+}
+
+func (c *AviObjCache) fetchL4PolicySets(client *clients.AviClient, cloud string) ([]AviL4PolicyCache, error) {
+	var l4PolData []AviL4PolicyCache
+	err := c.fetchCollection(client, "l4policyset", collectionUri("l4policyset", cloud, false),
+		func(nextPage ...NextPage) error {
+			var fetched []AviL4PolicyCache
+			_, count, err := c.AviPopulateAllL4PolicySets(client, cloud, &fetched, nextPage...)
+			if err == nil && len(nextPage) == 0 && len(fetched) != count {
+				err = fmt.Errorf("fetched %d of %d l4policysets", len(fetched), count)
+			}
+			l4PolData = append(l4PolData, fetched...)
+			return err
+		},
+		func(obj interface{}) bool {
+			l4Pol, ok := obj.(*AviL4PolicyCache)
+			if ok {
+				l4PolData = append(l4PolData, *l4Pol)
+			}
+			return ok
+		})
+	return l4PolData, err
+}
+
+// populateVirtualServices populates the virtualservices in VsCacheLocal. The virtualservices which are unchanged
+// since the checkpoint, and don't refer to any changed object, are picked from the cache.
+func (c *AviObjCache) populateVirtualServices(client *clients.AviClient, cloud string, vsCacheCopy *[]NamespaceName) error {
+	return c.fetchCollection(client, "virtualservice", collectionUri("virtualservice", cloud, true),
+		func(nextPage ...NextPage) error {
+			return c.AviObjVSCachePopulate(client, cloud, vsCacheCopy, nextPage...)
+		},
+		func(obj interface{}) bool {
+			vs, ok := obj.(*AviVsCache)
+			if !ok {
+				return false
+			}
+			vsCopy, done := vs.GetVSCopy()
+			if !done || c.nextCheckpoint.childrenChanged(vsCopy) {
+				return false
+			}
+			k := NamespaceName{Namespace: vsCopy.Tenant, Name: vsCopy.Name}
+			*vsCacheCopy = RemoveNamespaceName(*vsCacheCopy, k)
+			c.VsCacheLocal.AviCacheAdd(k, vsCopy)
+			return true
+		})
+}
+
+// PopulateCacheFromCheckpoint populates the cache from a cache checkpoint file, instead of fetching the objects from
+// the Avi controller. This is used to build the models offline, against the Avi objects captured in the checkpoint.
+func (c *AviObjCache) PopulateCacheFromCheckpoint(filePath string) error {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	checkpoint := &cacheCheckpoint{}
+	if err = json.Unmarshal(data, checkpoint); err != nil {
+		return fmt.Errorf("failed to unmarshal the cache checkpoint %s, err: %v", filePath, err)
+	}
+	if checkpoint.Version != cacheCheckpointVersion {
+		utils.AviLog.Warnf("Cache checkpoint %s has version %s, expected version %s", filePath, checkpoint.Version, cacheCheckpointVersion)
+	}
+
+	for collection, objects := range checkpoint.objectCaches() {
+		objCache := c.collectionCache(collection)
+		if collection == "virtualservice" {
+			// The virtualservices are moved to VsCacheMeta along with their SNI children.
+			objCache = c.VsCacheLocal
+		}
+		for key, obj := range objects.ShallowCopy() {
+			objCache.AviCacheAdd(key, obj)
+		}
+	}
+	c.PopulateVsMetaCache()
+	utils.AviLog.Infof("Populated the cache from the cache checkpoint %s, recorded for the controller %s and cloud %s",
+		filePath, checkpoint.ControllerUUID, checkpoint.Cloud)
+	return nil
+}
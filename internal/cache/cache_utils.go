@@ -32,15 +32,16 @@ type NamespaceName struct {
  */
 
 type AviPoolCache struct {
-	Name                 string
-	Tenant               string
-	Uuid                 string
-	CloudConfigCksum     string
-	ServiceMetadataObj   lib.ServiceMetadataObj
-	PkiProfileCollection NamespaceName
-	LastModified         string
-	InvalidData          bool
-	HasReference         bool
+	Name                    string
+	Tenant                  string
+	Uuid                    string
+	CloudConfigCksum        string
+	ServiceMetadataObj      lib.ServiceMetadataObj
+	PkiProfileCollection    NamespaceName
+	HealthMonitorCollection NamespaceName
+	LastModified            string
+	InvalidData             bool
+	HasReference            bool
 }
 
 type AviDSCache struct {
@@ -290,6 +291,26 @@ type AviPkiProfileCache struct {
 	HasReference     bool
 }
 
+type AviHealthMonitorCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+	InvalidData      bool
+	HasReference     bool
+}
+
+type AviAppProfileCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+	InvalidData      bool
+	HasReference     bool
+}
+
 type NextPage struct {
 	Next_uri   string
 	Collection interface{}
@@ -389,6 +410,52 @@ func (c *AviCache) AviCacheGet(k interface{}) (interface{}, bool) {
 	return val, ok
 }
 
+// AviCacheUpdate replaces the object of the key with the one returned by update, under the lock of the cache.
+// Returns false if the key is not in the cache.
+func (c *AviCache) AviCacheUpdate(k interface{}, update func(val interface{}) interface{}) bool {
+	c.cache_lock.Lock()
+	defer c.cache_lock.Unlock()
+	val, ok := c.cache[k]
+	if !ok {
+		return false
+	}
+	c.cache[k] = update(val)
+	return true
+}
+
+// AviCacheGetCopy returns a deep copy of the cache object of the key, for the readers which use the object
+// after the cache lock is released, while the rest layer keeps updating the cached object.
+func (c *AviCache) AviCacheGetCopy(k interface{}) (interface{}, bool) {
+	c.cache_lock.RLock()
+	defer c.cache_lock.RUnlock()
+	val, ok := c.cache[k]
+	if !ok || val == nil {
+		return nil, false
+	}
+	if vsCache, isVS := val.(*AviVsCache); isVS {
+		vsCopy, done := vsCache.GetVSCopy()
+		if !done {
+			return nil, false
+		}
+		return vsCopy, true
+	}
+	valType := reflect.TypeOf(val)
+	if valType.Kind() != reflect.Ptr {
+		return val, true
+	}
+	newObj := reflect.New(valType.Elem()).Interface()
+	bytes, err := json.Marshal(val)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal cache object of key %v: %v", k, err)
+		return nil, false
+	}
+	if err = json.Unmarshal(bytes, newObj); err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal cache object of key %v: %v", k, err)
+		return nil, false
+	}
+	return newObj, true
+}
+
 func (c *AviCache) AviCacheGetAllParentVSKeys() []NamespaceName {
 	c.cache_lock.RLock()
 	defer c.cache_lock.RUnlock()
@@ -499,11 +566,29 @@ func (c *AviCache) AviCacheGetNameByUuid(uuid string) (interface{}, bool) {
 			} else if value.(*AviPkiProfileCache).Uuid == uuid {
 				return value.(*AviPkiProfileCache).Name, true
 			}
+		case *AviHealthMonitorCache:
+			if value.(*AviHealthMonitorCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for health monitor key %v", reflect.ValueOf(key))
+			} else if value.(*AviHealthMonitorCache).Uuid == uuid {
+				return value.(*AviHealthMonitorCache).Name, true
+			}
+		case *AviAppProfileCache:
+			if value.(*AviAppProfileCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for application profile key %v", reflect.ValueOf(key))
+			} else if value.(*AviAppProfileCache).Uuid == uuid {
+				return value.(*AviAppProfileCache).Name, true
+			}
 		}
 	}
 	return nil, false
 }
 
+func (c *AviCache) AviCacheLen() int {
+	c.cache_lock.RLock()
+	defer c.cache_lock.RUnlock()
+	return len(c.cache)
+}
+
 func (c *AviCache) AviCacheAdd(k interface{}, val interface{}) {
 	c.cache_lock.Lock()
 	defer c.cache_lock.Unlock()
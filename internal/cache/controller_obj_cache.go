@@ -47,11 +47,18 @@ type AviObjCache struct {
 	L4PolicyCache      *AviCache
 	SSLKeyCache        *AviCache
 	PKIProfileCache    *AviCache
+	HealthMonitorCache *AviCache
+	AppProfileCache    *AviCache
 	VSVIPCache         *AviCache
 	VrfCache           *AviCache
 	VsCacheMeta        *AviCache
 	VsCacheLocal       *AviCache
 	ClusterStatusCache *AviCache
+
+	// checkpoint is the state of the objects at the last sync of the cache, nextCheckpoint is recorded during
+	// the ongoing sync.
+	checkpoint     *cacheCheckpoint
+	nextCheckpoint *cacheCheckpoint
 }
 
 func NewAviObjCache() *AviObjCache {
@@ -68,6 +75,8 @@ func NewAviObjCache() *AviObjCache {
 	c.VSVIPCache = NewAviCache()
 	c.VrfCache = NewAviCache()
 	c.PKIProfileCache = NewAviCache()
+	c.HealthMonitorCache = NewAviCache()
+	c.AppProfileCache = NewAviCache()
 	c.ClusterStatusCache = NewAviCache()
 	return &c
 }
@@ -78,6 +87,7 @@ var cacheOnce sync.Once
 func SharedAviObjCache() *AviObjCache {
 	cacheOnce.Do(func() {
 		cacheInstance = NewAviObjCache()
+		utils.MetricsRegistry.MustRegister(&cacheMetricsCollector{cache: cacheInstance})
 	})
 	return cacheInstance
 }
@@ -95,6 +105,8 @@ func (c *AviObjCache) AviRefreshObjectCache(client []*clients.AviClient, cloud s
 		c.PopulateVsVipDataToCache(client[7], cloud)
 	}()
 	c.PopulatePkiProfilesToCache(client[0])
+	c.PopulateHealthMonitorsToCache(client[0])
+	c.PopulateAppProfilesToCache(client[0])
 	c.PopulatePoolsToCache(client[1], cloud)
 	c.PopulatePgDataToCache(client[2], cloud)
 
@@ -123,17 +135,26 @@ func (c *AviObjCache) AviCacheRefresh(client *clients.AviClient, cloud string) {
 func (c *AviObjCache) AviObjCachePopulate(client []*clients.AviClient, version string, cloud string) ([]NamespaceName, []NamespaceName, error) {
 	vsCacheCopy := []NamespaceName{}
 	allVsKeys := []NamespaceName{}
+	// The sync is not blocked by the circuit breaker of the rest requests, it is retried as a whole on a failure.
+	utils.SharedAviRestLimiter().BeginBulkSync()
+	defer utils.SharedAviRestLimiter().EndBulkSync()
 	err := c.AviObjVrfCachePopulate(client[0], cloud)
 	if err != nil {
 		return vsCacheCopy, allVsKeys, err
 	}
+	// The objects are fetched from all the tenants, when the namespaces are mapped to tenants.
+	setSessionTenant(lib.GetCacheTenant(), client...)
+	defer setSessionTenant(lib.GetTenant(), client...)
+	// Only the objects modified after the checkpoint of the last sync are fetched from the controller.
+	c.loadCacheCheckpoint(cloud)
+	defer func() { c.saveCacheCheckpoint(err == nil) }()
 	// Populate the VS cache
 	utils.AviLog.Infof("Refreshing all object cache")
 	c.AviRefreshObjectCache(client, cloud)
 	utils.AviLog.Infof("Finished Refreshing all object cache")
 	vsCacheCopy = c.VsCacheMeta.AviCacheGetAllParentVSKeys()
 	allVsKeys = c.VsCacheMeta.AviGetAllKeys()
-	err = c.AviObjVSCachePopulate(client[0], cloud, &allVsKeys)
+	err = c.populateVirtualServices(client[0], cloud, &allVsKeys)
 	if err != nil {
 		return vsCacheCopy, allVsKeys, err
 	}
@@ -296,17 +317,24 @@ func (c *AviObjCache) MarkReference(vsCacheObj *AviVsCache) {
 }
 
 // DeleteUnmarked : Adds non referenced cached objects to a Dummy VS, which
-// would be used later to delete these objects from AVI Controller
+// would be used later to delete these objects from AVI Controller. The objects
+// are grouped into one Dummy VS per tenant.
 func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 
-	var dsKeys, vsVipKeys, httpKeys, sslKeys []NamespaceName
-	var pgKeys, poolKeys, l4Keys []NamespaceName
+	staleVSes := make(map[string]*AviVsCache)
+	staleVS := func(tenant string) *AviVsCache {
+		if _, ok := staleVSes[tenant]; !ok {
+			staleVSes[tenant] = &AviVsCache{Name: lib.DummyVSForStaleData, Tenant: tenant}
+		}
+		return staleVSes[tenant]
+	}
 	for _, objkey := range c.DSCache.AviGetAllKeys() {
 		intf, _ := c.DSCache.AviCacheGet(objkey)
 		if obj, ok := intf.(*AviDSCache); ok {
 			if obj.HasReference == false {
 				utils.AviLog.Infof("Reference Not found for datascript: %s", objkey)
-				dsKeys = append(dsKeys, objkey)
+				vs := staleVS(objkey.Namespace)
+				vs.DSKeyCollection = append(vs.DSKeyCollection, objkey)
 			}
 		}
 	}
@@ -316,7 +344,8 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		if obj, ok := intf.(*AviHTTPPolicyCache); ok {
 			if obj.HasReference == false {
 				utils.AviLog.Infof("Reference Not found for http policy: %s", objkey)
-				httpKeys = append(httpKeys, objkey)
+				vs := staleVS(objkey.Namespace)
+				vs.HTTPKeyCollection = append(vs.HTTPKeyCollection, objkey)
 			}
 		}
 	}
@@ -326,7 +355,8 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		if obj, ok := intf.(*AviL4PolicyCache); ok {
 			if obj.HasReference == false {
 				utils.AviLog.Infof("Reference Not found for l4 policy: %s", objkey)
-				l4Keys = append(l4Keys, objkey)
+				vs := staleVS(objkey.Namespace)
+				vs.L4PolicyCollection = append(vs.L4PolicyCollection, objkey)
 			}
 		}
 	}
@@ -336,7 +366,8 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		if obj, ok := intf.(*AviPGCache); ok {
 			if obj.HasReference == false {
 				utils.AviLog.Infof("Reference Not found for poolgroup: %s", objkey)
-				pgKeys = append(pgKeys, objkey)
+				vs := staleVS(objkey.Namespace)
+				vs.PGKeyCollection = append(vs.PGKeyCollection, objkey)
 			}
 		}
 
@@ -347,7 +378,8 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		if obj, ok := intf.(*AviPoolCache); ok {
 			if obj.HasReference == false {
 				utils.AviLog.Infof("Reference Not found for pool: %s", objkey)
-				poolKeys = append(poolKeys, objkey)
+				vs := staleVS(objkey.Namespace)
+				vs.PoolKeyCollection = append(vs.PoolKeyCollection, objkey)
 			}
 		}
 	}
@@ -357,7 +389,8 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		if obj, ok := intf.(*AviSSLCache); ok {
 			if obj.HasReference == false {
 				utils.AviLog.Infof("Reference Not found for ssl key: %s", objkey)
-				sslKeys = append(sslKeys, objkey)
+				vs := staleVS(objkey.Namespace)
+				vs.SSLKeyCertCollection = append(vs.SSLKeyCertCollection, objkey)
 			}
 		}
 	}
@@ -371,30 +404,31 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 			}
 			if obj.HasReference == false {
 				utils.AviLog.Infof("Reference Not found for vsvip: %s", objkey)
-				vsVipKeys = append(vsVipKeys, objkey)
+				vs := staleVS(objkey.Namespace)
+				vs.VSVipKeyCollection = append(vs.VSVipKeyCollection, objkey)
 			}
 		}
 	}
 
-	// Only add this if we have stale data
-	vsMetaObj := AviVsCache{
-		Name:                 lib.DummyVSForStaleData,
-		VSVipKeyCollection:   vsVipKeys,
-		HTTPKeyCollection:    httpKeys,
-		DSKeyCollection:      dsKeys,
-		SSLKeyCertCollection: sslKeys,
-		PGKeyCollection:      pgKeys,
-		PoolKeyCollection:    poolKeys,
-		L4PolicyCollection:   l4Keys,
-		SNIChildCollection:   childCollection,
-	}
-	vsKey := NamespaceName{
-		Namespace: lib.GetTenant(),
-		Name:      lib.DummyVSForStaleData,
+	for _, childUuid := range childCollection {
+		tenant := lib.GetTenant()
+		if childKey, found := c.VsCacheMeta.AviCacheGetKeyByUuid(childUuid); found {
+			tenant = childKey.(NamespaceName).Namespace
+		}
+		vs := staleVS(tenant)
+		vs.SNIChildCollection = append(vs.SNIChildCollection, childUuid)
 	}
-	utils.AviLog.Infof("Dummy VS for stale objects Deletion %s", utils.Stringify(&vsMetaObj))
-	c.VsCacheMeta.AviCacheAdd(vsKey, &vsMetaObj)
 
+	// The Dummy VS of the tenant of AKO is always added.
+	staleVS(lib.GetTenant())
+	for tenant, vsMetaObj := range staleVSes {
+		vsKey := NamespaceName{
+			Namespace: tenant,
+			Name:      lib.DummyVSForStaleData,
+		}
+		utils.AviLog.Infof("Dummy VS for stale objects Deletion %s", utils.Stringify(vsMetaObj))
+		c.VsCacheMeta.AviCacheAdd(vsKey, vsMetaObj)
+	}
 }
 
 func (c *AviObjCache) AviPopulateAllPGs(client *clients.AviClient, cloud string, pgData *[]AviPGCache, overrideUri ...NextPage) (*[]AviPGCache, int, error) {
@@ -444,6 +478,7 @@ func (c *AviObjCache) AviPopulateAllPGs(client *clients.AviClient, cloud string,
 		}
 		pgCacheObj := AviPGCache{
 			Name:             *pg.Name,
+			Tenant:           tenantNameFromRef(pg.TenantRef),
 			Uuid:             *pg.UUID,
 			CloudConfigCksum: *pg.CloudConfigCksum,
 			LastModified:     *pg.LastModified,
@@ -467,13 +502,12 @@ func (c *AviObjCache) AviPopulateAllPGs(client *clients.AviClient, cloud string,
 }
 
 func (c *AviObjCache) PopulatePgDataToCache(client *clients.AviClient, cloud string) {
-	var pgData []AviPGCache
-	c.AviPopulateAllPGs(client, cloud, &pgData)
+	pgData := c.fetchPGs(client, cloud)
 
 	// Get all the PG cache data and copy them.
 	pgCacheData := c.PgCache.ShallowCopy()
 	for i, pgCacheObj := range pgData {
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: pgCacheObj.Name}
+		k := NamespaceName{Namespace: pgCacheObj.Tenant, Name: pgCacheObj.Name}
 		oldPGIntf, found := c.PgCache.AviCacheGet(k)
 		if found {
 			oldPGData, ok := oldPGIntf.(*AviPGCache)
@@ -531,12 +565,11 @@ func (c *AviObjCache) AviPopulateAllPkiPRofiles(client *clients.AviClient, pkiDa
 			utils.AviLog.Warnf("Incomplete pki data unmarshalled, %s", utils.Stringify(pki))
 			continue
 		}
-		emptyIngestionMarkers := utils.AviObjectMarkers{}
 		pkiCacheObj := AviPkiProfileCache{
 			Name:             *pki.Name,
 			Uuid:             *pki.UUID,
-			Tenant:           lib.GetTenant(),
-			CloudConfigCksum: lib.SSLKeyCertChecksum(*pki.Name, string(*pki.CaCerts[0].Certificate), "", emptyIngestionMarkers, pki.Markers, true),
+			Tenant:           tenantNameFromRef(pki.TenantRef),
+			CloudConfigCksum: PKIProfileChecksum(&pki),
 		}
 		*pkiData = append(*pkiData, pkiCacheObj)
 
@@ -557,6 +590,123 @@ func (c *AviObjCache) AviPopulateAllPkiPRofiles(client *clients.AviClient, pkiDa
 	return pkiData, result.Count, nil
 }
 
+func (c *AviObjCache) AviPopulateAllHealthMonitors(client *clients.AviClient, hmData *[]AviHealthMonitorCache, overrideUri ...NextPage) (*[]AviHealthMonitorCache, int, error) {
+	var uri string
+
+	if len(overrideUri) == 1 {
+		uri = overrideUri[0].Next_uri
+	} else {
+		uri = "/api/healthmonitor/?" + "name.contains=" + lib.GetNamePrefix() + "&include_name=true" + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for healthmonitor %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		hm := models.HealthMonitor{}
+		err = json.Unmarshal(elems[i], &hm)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal healthmonitor data, err: %v", err)
+			continue
+		}
+
+		if hm.Name == nil || hm.UUID == nil {
+			utils.AviLog.Warnf("Incomplete healthmonitor data unmarshalled, %s", utils.Stringify(hm))
+			continue
+		}
+		hmCacheObj := AviHealthMonitorCache{
+			Name:             *hm.Name,
+			Uuid:             *hm.UUID,
+			Tenant:           tenantNameFromRef(hm.TenantRef),
+			CloudConfigCksum: HealthMonitorChecksum(&hm),
+		}
+		if hm.LastModified != nil {
+			hmCacheObj.LastModified = *hm.LastModified
+		}
+		*hmData = append(*hmData, hmCacheObj)
+	}
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/healthmonitor")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/healthmonitor" + next_uri[1]
+			nextPage := NextPage{Next_uri: overrideUri}
+			_, _, err := c.AviPopulateAllHealthMonitors(client, hmData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	return hmData, result.Count, nil
+}
+
+func (c *AviObjCache) AviPopulateAllAppProfiles(client *clients.AviClient, appProfileData *[]AviAppProfileCache, overrideUri ...NextPage) (*[]AviAppProfileCache, int, error) {
+	var uri string
+	akoUser := lib.AKOUser
+
+	if len(overrideUri) == 1 {
+		uri = overrideUri[0].Next_uri
+	} else {
+		uri = "/api/applicationprofile/?" + "&include_name=true" + "&created_by=" + akoUser + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for applicationprofile %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		appProfile := models.ApplicationProfile{}
+		err = json.Unmarshal(elems[i], &appProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal applicationprofile data, err: %v", err)
+			continue
+		}
+
+		if appProfile.Name == nil || appProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete applicationprofile data unmarshalled, %s", utils.Stringify(appProfile))
+			continue
+		}
+		appProfileCacheObj := AviAppProfileCache{
+			Name:             *appProfile.Name,
+			Uuid:             *appProfile.UUID,
+			Tenant:           tenantNameFromRef(appProfile.TenantRef),
+			CloudConfigCksum: AppProfileChecksum(&appProfile),
+		}
+		if appProfile.LastModified != nil {
+			appProfileCacheObj.LastModified = *appProfile.LastModified
+		}
+		*appProfileData = append(*appProfileData, appProfileCacheObj)
+	}
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/applicationprofile")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/applicationprofile" + next_uri[1]
+			nextPage := NextPage{Next_uri: overrideUri}
+			_, _, err := c.AviPopulateAllAppProfiles(client, appProfileData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	return appProfileData, result.Count, nil
+}
+
 func (c *AviObjCache) AviPopulateAllPools(client *clients.AviClient, cloud string, poolData *[]AviPoolCache, overrideUri ...NextPage) (*[]AviPoolCache, int, error) {
 	var uri string
 	akoUser := lib.AKOUser
@@ -596,22 +746,26 @@ func (c *AviObjCache) AviPopulateAllPools(client *clients.AviClient, cloud strin
 			utils.AviLog.Warnf("Error parsing service metadata during pool cache :%v", err)
 		}
 
+		tenant := tenantNameFromRef(pool.TenantRef)
 		var pkiKey NamespaceName
 		if pool.PkiProfileRef != nil {
 			pkiUuid := ExtractUuid(*pool.PkiProfileRef, "pkiprofile-.*.#")
 			pkiName, foundPki := c.PKIProfileCache.AviCacheGetNameByUuid(pkiUuid)
 			if foundPki {
-				pkiKey = NamespaceName{Namespace: lib.GetTenant(), Name: pkiName.(string)}
+				pkiKey = NamespaceName{Namespace: tenant, Name: pkiName.(string)}
 			}
 		}
+		hmKey := c.HealthMonitorKeyFromRefs(tenant, pool.HealthMonitorRefs)
 
 		poolCacheObj := AviPoolCache{
-			Name:                 *pool.Name,
-			Uuid:                 *pool.UUID,
-			CloudConfigCksum:     *pool.CloudConfigCksum,
-			PkiProfileCollection: pkiKey,
-			ServiceMetadataObj:   svc_mdata_obj,
-			LastModified:         *pool.LastModified,
+			Name:                    *pool.Name,
+			Tenant:                  tenant,
+			Uuid:                    *pool.UUID,
+			CloudConfigCksum:        *pool.CloudConfigCksum,
+			PkiProfileCollection:    pkiKey,
+			HealthMonitorCollection: hmKey,
+			ServiceMetadataObj:      svc_mdata_obj,
+			LastModified:            *pool.LastModified,
 		}
 		*poolData = append(*poolData, poolCacheObj)
 	}
@@ -632,12 +786,11 @@ func (c *AviObjCache) AviPopulateAllPools(client *clients.AviClient, cloud strin
 }
 
 func (c *AviObjCache) PopulatePkiProfilesToCache(client *clients.AviClient, overrideUri ...NextPage) {
-	var pkiProfData []AviPkiProfileCache
-	c.AviPopulateAllPkiPRofiles(client, &pkiProfData)
+	pkiProfData := c.fetchPkiProfiles(client)
 
 	pkiCacheData := c.PKIProfileCache.ShallowCopy()
 	for i, pkiCacheObj := range pkiProfData {
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: pkiCacheObj.Name}
+		k := NamespaceName{Namespace: pkiCacheObj.Tenant, Name: pkiCacheObj.Name}
 		oldPkiIntf, found := c.PKIProfileCache.AviCacheGet(k)
 		if found {
 			oldPkiData, ok := oldPkiIntf.(*AviPkiProfileCache)
@@ -661,13 +814,70 @@ func (c *AviObjCache) PopulatePkiProfilesToCache(client *clients.AviClient, over
 	}
 }
 
+func (c *AviObjCache) PopulateHealthMonitorsToCache(client *clients.AviClient, overrideUri ...NextPage) {
+	hmData := c.fetchHealthMonitors(client)
+
+	hmCacheData := c.HealthMonitorCache.ShallowCopy()
+	for i, hmCacheObj := range hmData {
+		k := NamespaceName{Namespace: hmCacheObj.Tenant, Name: hmCacheObj.Name}
+		oldHMIntf, found := c.HealthMonitorCache.AviCacheGet(k)
+		if found {
+			oldHMData, ok := oldHMIntf.(*AviHealthMonitorCache)
+			if ok {
+				if oldHMData.InvalidData {
+					hmData[i].InvalidData = true
+					utils.AviLog.Infof("Invalid cache data for healthmonitor: %s", k)
+				}
+			} else {
+				utils.AviLog.Infof("Wrong data type for healthmonitor: %s in cache", k)
+			}
+		}
+		utils.AviLog.Infof("Adding key to healthmonitor cache :%s value :%s", k, hmCacheObj.Uuid)
+		c.HealthMonitorCache.AviCacheAdd(k, &hmData[i])
+		delete(hmCacheData, k)
+	}
+	// The data that is left in hmCacheData should be explicitly removed
+	for key := range hmCacheData {
+		utils.AviLog.Infof("Deleting key from healthmonitor cache :%s", key)
+		c.HealthMonitorCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) PopulateAppProfilesToCache(client *clients.AviClient, overrideUri ...NextPage) {
+	appProfileData := c.fetchAppProfiles(client)
+
+	appProfileCacheData := c.AppProfileCache.ShallowCopy()
+	for i, appProfileCacheObj := range appProfileData {
+		k := NamespaceName{Namespace: appProfileCacheObj.Tenant, Name: appProfileCacheObj.Name}
+		oldAppProfileIntf, found := c.AppProfileCache.AviCacheGet(k)
+		if found {
+			oldAppProfileData, ok := oldAppProfileIntf.(*AviAppProfileCache)
+			if ok {
+				if oldAppProfileData.InvalidData {
+					appProfileData[i].InvalidData = true
+					utils.AviLog.Infof("Invalid cache data for applicationprofile: %s", k)
+				}
+			} else {
+				utils.AviLog.Infof("Wrong data type for applicationprofile: %s in cache", k)
+			}
+		}
+		utils.AviLog.Infof("Adding key to applicationprofile cache :%s value :%s", k, appProfileCacheObj.Uuid)
+		c.AppProfileCache.AviCacheAdd(k, &appProfileData[i])
+		delete(appProfileCacheData, k)
+	}
+	// The data that is left in appProfileCacheData should be explicitly removed
+	for key := range appProfileCacheData {
+		utils.AviLog.Infof("Deleting key from applicationprofile cache :%s", key)
+		c.AppProfileCache.AviCacheDelete(key)
+	}
+}
+
 func (c *AviObjCache) PopulatePoolsToCache(client *clients.AviClient, cloud string, overrideUri ...NextPage) {
-	var poolsData []AviPoolCache
-	c.AviPopulateAllPools(client, cloud, &poolsData)
+	poolsData := c.fetchPools(client, cloud)
 
 	poolCacheData := c.PoolCache.ShallowCopy()
 	for i, poolCacheObj := range poolsData {
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: poolCacheObj.Name}
+		k := NamespaceName{Namespace: poolCacheObj.Tenant, Name: poolCacheObj.Name}
 		oldPoolIntf, found := c.PoolCache.AviCacheGet(k)
 		if found {
 			oldPoolData, ok := oldPoolIntf.(*AviPoolCache)
@@ -733,7 +943,12 @@ func (c *AviObjCache) AviPopulateAllVSVips(client *clients.AviClient, cloud stri
 		var fips []string
 		var networkNames []string
 		for _, vip := range vsvip.Vip {
-			vips = append(vips, *vip.IPAddress.Addr)
+			if vip.IPAddress != nil {
+				vips = append(vips, *vip.IPAddress.Addr)
+			}
+			if vip.Ip6Address != nil {
+				vips = append(vips, *vip.Ip6Address.Addr)
+			}
 			if vip.FloatingIP != nil {
 				fips = append(fips, *vip.FloatingIP.Addr)
 			}
@@ -756,6 +971,7 @@ func (c *AviObjCache) AviPopulateAllVSVips(client *clients.AviClient, cloud stri
 
 		vsVipCacheObj := AviVSVIPCache{
 			Name:             *vsvip.Name,
+			Tenant:           tenantNameFromRef(vsvip.TenantRef),
 			Uuid:             *vsvip.UUID,
 			FQDNs:            fqdns,
 			NetworkNames:     networkNames,
@@ -782,12 +998,11 @@ func (c *AviObjCache) AviPopulateAllVSVips(client *clients.AviClient, cloud stri
 }
 
 func (c *AviObjCache) PopulateVsVipDataToCache(client *clients.AviClient, cloud string) {
-	var vsVipData []AviVSVIPCache
-	c.AviPopulateAllVSVips(client, cloud, &vsVipData)
+	vsVipData := c.fetchVSVips(client, cloud)
 
 	vsVipCacheData := c.VSVIPCache.ShallowCopy()
 	for i, vsVipCacheObj := range vsVipData {
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: vsVipCacheObj.Name}
+		k := NamespaceName{Namespace: vsVipCacheObj.Tenant, Name: vsVipCacheObj.Name}
 		oldVsvipIntf, found := c.VSVIPCache.AviCacheGet(k)
 		if found {
 			oldVsvipData, ok := oldVsvipIntf.(*AviVSVIPCache)
@@ -857,6 +1072,7 @@ func (c *AviObjCache) AviPopulateAllDSs(client *clients.AviClient, cloud string,
 		}
 		dsCacheObj := AviDSCache{
 			Name:       *ds.Name,
+			Tenant:     tenantNameFromRef(ds.TenantRef),
 			Uuid:       *ds.UUID,
 			PoolGroups: pgs,
 		}
@@ -883,11 +1099,10 @@ func (c *AviObjCache) AviPopulateAllDSs(client *clients.AviClient, cloud string,
 }
 
 func (c *AviObjCache) PopulateDSDataToCache(client *clients.AviClient, cloud string, overrideUri ...NextPage) {
-	var DsData []AviDSCache
-	c.AviPopulateAllDSs(client, cloud, &DsData)
+	DsData := c.fetchDSs(client, cloud)
 	dsCacheData := c.DSCache.ShallowCopy()
 	for i, DsCacheObj := range DsData {
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: DsCacheObj.Name}
+		k := NamespaceName{Namespace: DsCacheObj.Tenant, Name: DsCacheObj.Name}
 		oldDSIntf, found := c.DSCache.AviCacheGet(k)
 		if found {
 			oldDSData, ok := oldDSIntf.(*AviDSCache)
@@ -918,7 +1133,7 @@ func (c *AviObjCache) AviPopulateAllSSLKeys(client *clients.AviClient, cloud str
 	if len(nextPage) == 1 {
 		uri = nextPage[0].Next_uri
 	} else {
-		uri = "/api/sslkeyandcertificate/?" + "&created_by=" + akoUser + "&page_size=100"
+		uri = "/api/sslkeyandcertificate/?" + "&include_name=true" + "&created_by=" + akoUser + "&page_size=100"
 	}
 
 	result, err := lib.AviGetCollectionRaw(client, uri)
@@ -959,13 +1174,18 @@ func (c *AviObjCache) AviPopulateAllSSLKeys(client *clients.AviClient, cloud str
 			}
 		}
 		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		checksum := lib.SSLKeyCertChecksum(*sslkey.Name, *sslkey.Certificate.Certificate, cacert, emptyIngestionMarkers, sslkey.Markers, true)
+		if sslkey.EnableOcspStapling != nil && *sslkey.EnableOcspStapling {
+			checksum += lib.OCSPConfigChecksum(sslkey.OcspConfig)
+		}
 		sslCacheObj := AviSSLCache{
 			Name:             *sslkey.Name,
+			Tenant:           tenantNameFromRef(sslkey.TenantRef),
 			Uuid:             *sslkey.UUID,
 			Cert:             *sslkey.Certificate.Certificate,
 			HasCARef:         hasCA,
 			CACertUUID:       cacertUUID,
-			CloudConfigCksum: lib.SSLKeyCertChecksum(*sslkey.Name, *sslkey.Certificate.Certificate, cacert, emptyIngestionMarkers, sslkey.Markers, true),
+			CloudConfigCksum: checksum,
 		}
 		*SslData = append(*SslData, sslCacheObj)
 	}
@@ -984,12 +1204,31 @@ func (c *AviObjCache) AviPopulateAllSSLKeys(client *clients.AviClient, cloud str
 	return SslData, result.Count, nil
 }
 
+// caCertChainKeys returns the keys of the CA certs in the chain of the SSL cert, from its issuer to the root.
+func (c *AviObjCache) caCertChainKeys(sslData *AviSSLCache) []NamespaceName {
+	var caCertKeys []NamespaceName
+	for depth := 0; sslData.CACertUUID != "" && depth < lib.MaxCACertChainDepth; depth++ {
+		caName, found := c.SSLKeyCache.AviCacheGetNameByUuid(sslData.CACertUUID)
+		if !found {
+			break
+		}
+		caCertKey := NamespaceName{Namespace: sslData.Tenant, Name: caName.(string)}
+		caCertKeys = append(caCertKeys, caCertKey)
+		caIntf, found := c.SSLKeyCache.AviCacheGet(caCertKey)
+		if !found {
+			break
+		}
+		sslData = caIntf.(*AviSSLCache)
+	}
+	return caCertKeys
+}
+
 func (c *AviObjCache) AviPopulateOneSSLCache(client *clients.AviClient,
 	cloud string, objName string) error {
 	var uri string
 	akoUser := lib.AKOUser
 
-	uri = "/api/sslkeyandcertificate?name=" + objName + "&created_by=" + akoUser
+	uri = "/api/sslkeyandcertificate?name=" + objName + "&include_name=true" + "&created_by=" + akoUser
 
 	result, err := lib.AviGetCollectionRaw(client, uri)
 	if err != nil {
@@ -1019,12 +1258,12 @@ func (c *AviObjCache) AviPopulateOneSSLCache(client *clients.AviClient,
 		if !strings.HasPrefix(*sslkey.Name, lib.GetNamePrefix()) {
 			continue
 		}
-		var cacert string
+		var cacertUUID, cacert string
 		hasCA := false
 		if len(sslkey.CaCerts) != 0 {
 			if sslkey.CaCerts[0].CaRef != nil {
 				hasCA = true
-				cacertUUID := ExtractUuidWithoutHash(*sslkey.CaCerts[0].CaRef, "sslkeyandcertificate-.*.")
+				cacertUUID = ExtractUuidWithoutHash(*sslkey.CaCerts[0].CaRef, "sslkeyandcertificate-.*.")
 				cacertIntf, found := c.SSLKeyCache.AviCacheGetNameByUuid(cacertUUID)
 				if found {
 					cacert = cacertIntf.(string)
@@ -1032,13 +1271,19 @@ func (c *AviObjCache) AviPopulateOneSSLCache(client *clients.AviClient,
 			}
 		}
 		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		checksum := lib.SSLKeyCertChecksum(*sslkey.Name, *sslkey.Certificate.Certificate, cacert, emptyIngestionMarkers, sslkey.Markers, true)
+		if sslkey.EnableOcspStapling != nil && *sslkey.EnableOcspStapling {
+			checksum += lib.OCSPConfigChecksum(sslkey.OcspConfig)
+		}
 		sslCacheObj := AviSSLCache{
 			Name:             *sslkey.Name,
+			Tenant:           tenantNameFromRef(sslkey.TenantRef),
 			Uuid:             *sslkey.UUID,
-			CloudConfigCksum: lib.SSLKeyCertChecksum(*sslkey.Name, *sslkey.Certificate.Certificate, cacert, emptyIngestionMarkers, sslkey.Markers, true),
+			CloudConfigCksum: checksum,
 			HasCARef:         hasCA,
+			CACertUUID:       cacertUUID,
 		}
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: *sslkey.Name}
+		k := NamespaceName{Namespace: tenantNameFromRef(sslkey.TenantRef), Name: *sslkey.Name}
 		c.SSLKeyCache.AviCacheAdd(k, &sslCacheObj)
 		utils.AviLog.Debugf("Adding sslkey to Cache during refresh %s", k)
 	}
@@ -1050,7 +1295,7 @@ func (c *AviObjCache) AviPopulateOnePKICache(client *clients.AviClient,
 	var uri string
 	akoUser := lib.AKOUser
 
-	uri = "/api/pkiprofile?name=" + objName + "&created_by=" + akoUser
+	uri = "/api/pkiprofile?name=" + objName + "&include_name=true" + "&created_by=" + akoUser
 
 	result, err := lib.AviGetCollectionRaw(client, uri)
 	if err != nil {
@@ -1078,25 +1323,226 @@ func (c *AviObjCache) AviPopulateOnePKICache(client *clients.AviClient,
 		if !strings.HasPrefix(*pkikey.Name, lib.GetNamePrefix()) {
 			continue
 		}
-		emptyIngestionMarkers := utils.AviObjectMarkers{}
-		sslCacheObj := AviSSLCache{
+		pkiCacheObj := AviPkiProfileCache{
 			Name:             *pkikey.Name,
+			Tenant:           tenantNameFromRef(pkikey.TenantRef),
 			Uuid:             *pkikey.UUID,
-			CloudConfigCksum: lib.SSLKeyCertChecksum(*pkikey.Name, *pkikey.CaCerts[0].Certificate, "", emptyIngestionMarkers, pkikey.Markers, true),
+			CloudConfigCksum: PKIProfileChecksum(&pkikey),
 		}
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: *pkikey.Name}
-		c.SSLKeyCache.AviCacheAdd(k, &sslCacheObj)
+		k := NamespaceName{Namespace: tenantNameFromRef(pkikey.TenantRef), Name: *pkikey.Name}
+		c.PKIProfileCache.AviCacheAdd(k, &pkiCacheObj)
 		utils.AviLog.Debugf("Adding pkikey to Cache during refresh %s", k)
 	}
 	return nil
 }
 
+func (c *AviObjCache) AviPopulateOneHealthMonitorCache(client *clients.AviClient,
+	cloud string, objName string) error {
+	var uri string
+
+	uri = "/api/healthmonitor?name=" + objName + "&include_name=true"
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for healthmonitor %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal healthmonitor data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		hm := models.HealthMonitor{}
+		err = json.Unmarshal(elems[i], &hm)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal healthmonitor data, err: %v", err)
+			continue
+		}
+		if hm.Name == nil || hm.UUID == nil {
+			utils.AviLog.Warnf("Incomplete healthmonitor data unmarshalled, %s", utils.Stringify(hm))
+			continue
+		}
+		//Only cache a health monitor that belongs to this AKO.
+		if !strings.HasPrefix(*hm.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		hmCacheObj := AviHealthMonitorCache{
+			Name:             *hm.Name,
+			Tenant:           tenantNameFromRef(hm.TenantRef),
+			Uuid:             *hm.UUID,
+			CloudConfigCksum: HealthMonitorChecksum(&hm),
+		}
+		if hm.LastModified != nil {
+			hmCacheObj.LastModified = *hm.LastModified
+		}
+		k := NamespaceName{Namespace: tenantNameFromRef(hm.TenantRef), Name: *hm.Name}
+		c.HealthMonitorCache.AviCacheAdd(k, &hmCacheObj)
+		utils.AviLog.Debugf("Adding healthmonitor to Cache during refresh %s", k)
+	}
+	return nil
+}
+
+func (c *AviObjCache) AviPopulateOneAppProfileCache(client *clients.AviClient,
+	cloud string, objName string) error {
+	var uri string
+	akoUser := lib.AKOUser
+
+	uri = "/api/applicationprofile?name=" + objName + "&include_name=true" + "&created_by=" + akoUser
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for applicationprofile %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal applicationprofile data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		appProfile := models.ApplicationProfile{}
+		err = json.Unmarshal(elems[i], &appProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal applicationprofile data, err: %v", err)
+			continue
+		}
+		if appProfile.Name == nil || appProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete applicationprofile data unmarshalled, %s", utils.Stringify(appProfile))
+			continue
+		}
+		appProfileCacheObj := AviAppProfileCache{
+			Name:             *appProfile.Name,
+			Tenant:           tenantNameFromRef(appProfile.TenantRef),
+			Uuid:             *appProfile.UUID,
+			CloudConfigCksum: AppProfileChecksum(&appProfile),
+		}
+		if appProfile.LastModified != nil {
+			appProfileCacheObj.LastModified = *appProfile.LastModified
+		}
+		k := NamespaceName{Namespace: tenantNameFromRef(appProfile.TenantRef), Name: *appProfile.Name}
+		c.AppProfileCache.AviCacheAdd(k, &appProfileCacheObj)
+		utils.AviLog.Debugf("Adding applicationprofile to Cache during refresh %s", k)
+	}
+	return nil
+}
+
+// HealthMonitorKeyFromRefs returns the key of the health monitor created by AKO, among the health monitors of a pool.
+func (c *AviObjCache) HealthMonitorKeyFromRefs(tenant string, hmRefs []string) NamespaceName {
+	for _, hmRef := range hmRefs {
+		if refs := strings.Split(hmRef, "?name="); len(refs) == 2 {
+			hmKey := NamespaceName{Namespace: tenant, Name: refs[1]}
+			if _, found := c.HealthMonitorCache.AviCacheGet(hmKey); found {
+				return hmKey
+			}
+			continue
+		}
+		hmUuid := ExtractUuidWithoutHash(hmRef, "healthmonitor-.*")
+		if hmName, found := c.HealthMonitorCache.AviCacheGetNameByUuid(hmUuid); found {
+			return NamespaceName{Namespace: tenant, Name: hmName.(string)}
+		}
+	}
+	return NamespaceName{}
+}
+
+// HealthMonitorChecksum computes the checksum of a health monitor created by AKO, which matches the checksum of
+// the health monitor node it is created from.
+func HealthMonitorChecksum(hm *models.HealthMonitor) uint32 {
+	var hmName, hmType, httpRequest string
+	var sendInterval, receiveTimeout, successfulChecks, failedChecks, monitorPort int32
+	if hm.Name != nil {
+		hmName = *hm.Name
+	}
+	if hm.Type != nil {
+		hmType = *hm.Type
+	}
+	if hm.HTTPMonitor != nil && hm.HTTPMonitor.HTTPRequest != nil {
+		httpRequest = *hm.HTTPMonitor.HTTPRequest
+	} else if hm.HTTPSMonitor != nil && hm.HTTPSMonitor.HTTPRequest != nil {
+		httpRequest = *hm.HTTPSMonitor.HTTPRequest
+	}
+	if hm.SendInterval != nil {
+		sendInterval = *hm.SendInterval
+	}
+	if hm.ReceiveTimeout != nil {
+		receiveTimeout = *hm.ReceiveTimeout
+	}
+	if hm.SuccessfulChecks != nil {
+		successfulChecks = *hm.SuccessfulChecks
+	}
+	if hm.FailedChecks != nil {
+		failedChecks = *hm.FailedChecks
+	}
+	if hm.MonitorPort != nil {
+		monitorPort = *hm.MonitorPort
+	}
+	emptyIngestionMarkers := utils.AviObjectMarkers{}
+	return lib.HealthMonitorChecksum(hmName, hmType, httpRequest, sendInterval, receiveTimeout, successfulChecks,
+		failedChecks, monitorPort, emptyIngestionMarkers, hm.Markers, true)
+}
+
+// PKIProfileChecksum computes the checksum of a PKI profile created by AKO, which matches the checksum of the
+// PKI profile node it is created from.
+func PKIProfileChecksum(pki *models.PKIprofile) uint32 {
+	var pkiName, caCerts string
+	if pki.Name != nil {
+		pkiName = *pki.Name
+	}
+	for _, caCert := range pki.CaCerts {
+		if caCert != nil && caCert.Certificate != nil {
+			caCerts += *caCert.Certificate
+		}
+	}
+	for _, crl := range pki.Crls {
+		if crl != nil && crl.Body != nil {
+			caCerts += *crl.Body
+		}
+	}
+	emptyIngestionMarkers := utils.AviObjectMarkers{}
+	return lib.SSLKeyCertChecksum(pkiName, "", caCerts, emptyIngestionMarkers, pki.Markers, true)
+}
+
+// AppProfileChecksum computes the checksum of an application profile created by AKO, which matches the checksum
+// of the application profile node it is created from.
+func AppProfileChecksum(appProfile *models.ApplicationProfile) uint32 {
+	var appProfileName, clientCertMode, pkiProfileName string
+	var headers []string
+	if appProfile.Name != nil {
+		appProfileName = *appProfile.Name
+	}
+	if httpProfile := appProfile.HTTPProfile; httpProfile != nil {
+		if httpProfile.SslClientCertificateMode != nil {
+			clientCertMode = *httpProfile.SslClientCertificateMode
+		}
+		if httpProfile.PkiProfileRef != nil {
+			pkiProfileName = *httpProfile.PkiProfileRef
+			if refs := strings.Split(pkiProfileName, "?name="); len(refs) == 2 {
+				pkiProfileName = refs[1]
+			} else if refs := strings.Split(pkiProfileName, "#"); len(refs) == 2 {
+				pkiProfileName = refs[1]
+			}
+		}
+		if httpProfile.SslClientCertificateAction != nil {
+			for _, header := range httpProfile.SslClientCertificateAction.Headers {
+				if header != nil && header.RequestHeader != nil && header.RequestHeaderValue != nil {
+					headers = append(headers, *header.RequestHeader+":"+*header.RequestHeaderValue)
+				}
+			}
+		}
+	}
+	emptyIngestionMarkers := utils.AviObjectMarkers{}
+	return lib.AppProfileChecksum(appProfileName, clientCertMode, pkiProfileName, headers, emptyIngestionMarkers,
+		appProfile.Markers, true)
+}
+
 func (c *AviObjCache) AviPopulateOnePoolCache(client *clients.AviClient,
 	cloud string, objName string) error {
 	var uri string
 	akoUser := lib.AKOUser
 
-	uri = "/api/pool?name=" + objName + "&created_by=" + akoUser
+	uri = "/api/pool?name=" + objName + "&include_name=true" + "&created_by=" + akoUser
 
 	result, err := lib.AviGetCollectionRaw(client, uri)
 	if err != nil {
@@ -1132,24 +1578,28 @@ func (c *AviObjCache) AviPopulateOnePoolCache(client *clients.AviClient,
 			utils.AviLog.Warnf("Error parsing service metadata during pool cache :%v", err)
 		}
 
+		tenant := tenantNameFromRef(pool.TenantRef)
 		var pkiKey NamespaceName
 		if pool.PkiProfileRef != nil {
 			pkiUuid := ExtractUuid(*pool.PkiProfileRef, "pkiprofile-.*.#")
 			pkiName, foundPki := c.PKIProfileCache.AviCacheGetNameByUuid(pkiUuid)
 			if foundPki {
-				pkiKey = NamespaceName{Namespace: lib.GetTenant(), Name: pkiName.(string)}
+				pkiKey = NamespaceName{Namespace: tenant, Name: pkiName.(string)}
 			}
 		}
+		hmKey := c.HealthMonitorKeyFromRefs(tenant, pool.HealthMonitorRefs)
 
 		poolCacheObj := AviPoolCache{
-			Name:                 *pool.Name,
-			Uuid:                 *pool.UUID,
-			CloudConfigCksum:     *pool.CloudConfigCksum,
-			PkiProfileCollection: pkiKey,
-			ServiceMetadataObj:   svc_mdata_obj,
-			LastModified:         *pool.LastModified,
-		}
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: *pool.Name}
+			Name:                    *pool.Name,
+			Tenant:                  tenant,
+			Uuid:                    *pool.UUID,
+			CloudConfigCksum:        *pool.CloudConfigCksum,
+			PkiProfileCollection:    pkiKey,
+			HealthMonitorCollection: hmKey,
+			ServiceMetadataObj:      svc_mdata_obj,
+			LastModified:            *pool.LastModified,
+		}
+		k := NamespaceName{Namespace: tenantNameFromRef(pool.TenantRef), Name: *pool.Name}
 		c.PoolCache.AviCacheAdd(k, &poolCacheObj)
 		utils.AviLog.Debugf("Adding pool to Cache during refresh %s", k)
 	}
@@ -1161,7 +1611,7 @@ func (c *AviObjCache) AviPopulateOneVsDSCache(client *clients.AviClient,
 	var uri string
 	akoUser := lib.AKOUser
 
-	uri = "/api/vsdatascript?name=" + objName + "&created_by=" + akoUser
+	uri = "/api/vsdatascript?name=" + objName + "&include_name=true" + "&created_by=" + akoUser
 
 	result, err := lib.AviGetCollectionRaw(client, uri)
 	if err != nil {
@@ -1202,6 +1652,7 @@ func (c *AviObjCache) AviPopulateOneVsDSCache(client *clients.AviClient,
 		}
 		dsCacheObj := AviDSCache{
 			Name:       *ds.Name,
+			Tenant:     tenantNameFromRef(ds.TenantRef),
 			Uuid:       *ds.UUID,
 			PoolGroups: pgs,
 		}
@@ -1210,7 +1661,7 @@ func (c *AviObjCache) AviPopulateOneVsDSCache(client *clients.AviClient,
 			checksum = utils.Hash(fmt.Sprint(checksum) + utils.HTTP_DS_SCRIPT_MODIFIED)
 		}
 		dsCacheObj.CloudConfigCksum = checksum
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: *ds.Name}
+		k := NamespaceName{Namespace: tenantNameFromRef(ds.TenantRef), Name: *ds.Name}
 		c.DSCache.AviCacheAdd(k, &dsCacheObj)
 		utils.AviLog.Debugf("Adding ds to Cache during refresh %s", k)
 	}
@@ -1222,7 +1673,7 @@ func (c *AviObjCache) AviPopulateOnePGCache(client *clients.AviClient,
 	var uri string
 	akoUser := lib.AKOUser
 
-	uri = "/api/poolgroup?name=" + objName + "&created_by=" + akoUser
+	uri = "/api/poolgroup?name=" + objName + "&include_name=true" + "&created_by=" + akoUser
 
 	result, err := lib.AviGetCollectionRaw(client, uri)
 	if err != nil {
@@ -1264,12 +1715,13 @@ func (c *AviObjCache) AviPopulateOnePGCache(client *clients.AviClient,
 		}
 		pgCacheObj := AviPGCache{
 			Name:             *pg.Name,
+			Tenant:           tenantNameFromRef(pg.TenantRef),
 			Uuid:             *pg.UUID,
 			CloudConfigCksum: *pg.CloudConfigCksum,
 			LastModified:     *pg.LastModified,
 			Members:          pools,
 		}
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: *pg.Name}
+		k := NamespaceName{Namespace: tenantNameFromRef(pg.TenantRef), Name: *pg.Name}
 		c.PgCache.AviCacheAdd(k, &pgCacheObj)
 		utils.AviLog.Debugf("Adding pg to Cache during refresh %s", k)
 	}
@@ -1280,7 +1732,7 @@ func (c *AviObjCache) AviPopulateOneVsVipCache(client *clients.AviClient,
 	cloud string, objName string) error {
 	var uri string
 
-	uri = "/api/vsvip?name=" + objName + "&cloud_ref.name=" + cloud
+	uri = "/api/vsvip?name=" + objName + "&include_name=true" + "&cloud_ref.name=" + cloud
 
 	result, err := lib.AviGetCollectionRaw(client, uri)
 	if err != nil {
@@ -1318,7 +1770,12 @@ func (c *AviObjCache) AviPopulateOneVsVipCache(client *clients.AviClient,
 		var fips []string
 		var networkNames []string
 		for _, vip := range vsvip.Vip {
-			vips = append(vips, *vip.IPAddress.Addr)
+			if vip.IPAddress != nil {
+				vips = append(vips, *vip.IPAddress.Addr)
+			}
+			if vip.Ip6Address != nil {
+				vips = append(vips, *vip.Ip6Address.Addr)
+			}
 			if vip.FloatingIP != nil {
 				fips = append(vips, *vip.FloatingIP.Addr)
 			}
@@ -1340,6 +1797,7 @@ func (c *AviObjCache) AviPopulateOneVsVipCache(client *clients.AviClient,
 		}
 		vsVipCacheObj := AviVSVIPCache{
 			Name:             *vsvip.Name,
+			Tenant:           tenantNameFromRef(vsvip.TenantRef),
 			Uuid:             *vsvip.UUID,
 			FQDNs:            fqdns,
 			LastModified:     *vsvip.LastModified,
@@ -1348,7 +1806,7 @@ func (c *AviObjCache) AviPopulateOneVsVipCache(client *clients.AviClient,
 			NetworkNames:     networkNames,
 			CloudConfigCksum: checksum,
 		}
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: *vsvip.Name}
+		k := NamespaceName{Namespace: tenantNameFromRef(vsvip.TenantRef), Name: *vsvip.Name}
 		c.VSVIPCache.AviCacheAdd(k, &vsVipCacheObj)
 		utils.AviLog.Debugf("Adding vsvip to Cache during refresh %s", k)
 	}
@@ -1360,7 +1818,7 @@ func (c *AviObjCache) AviPopulateOneVsHttpPolCache(client *clients.AviClient,
 	var uri string
 	akoUser := lib.AKOUser
 
-	uri = "/api/httppolicyset?name=" + objName + "&created_by=" + akoUser
+	uri = "/api/httppolicyset?name=" + objName + "&include_name=true" + "&created_by=" + akoUser
 
 	result, err := lib.AviGetCollectionRaw(client, uri)
 	if err != nil {
@@ -1414,13 +1872,14 @@ func (c *AviObjCache) AviPopulateOneVsHttpPolCache(client *clients.AviClient,
 
 		httpPolCacheObj := AviHTTPPolicyCache{
 			Name:             *httppol.Name,
+			Tenant:           tenantNameFromRef(httppol.TenantRef),
 			Uuid:             *httppol.UUID,
 			CloudConfigCksum: *httppol.CloudConfigCksum,
 			PoolGroups:       poolGroups,
 			Pools:            pools,
 			LastModified:     *httppol.LastModified,
 		}
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: *httppol.Name}
+		k := NamespaceName{Namespace: tenantNameFromRef(httppol.TenantRef), Name: *httppol.Name}
 		c.HTTPPolicyCache.AviCacheAdd(k, &httpPolCacheObj)
 		utils.AviLog.Debugf("Adding httppolicy to Cache during refresh %s", k)
 	}
@@ -1432,7 +1891,7 @@ func (c *AviObjCache) AviPopulateOneVsL4PolCache(client *clients.AviClient,
 	var uri string
 	akoUser := lib.AKOUser
 
-	uri = "/api/l4policyset?name=" + objName + "&created_by=" + akoUser
+	uri = "/api/l4policyset?name=" + objName + "&include_name=true" + "&created_by=" + akoUser
 
 	result, err := lib.AviGetCollectionRaw(client, uri)
 	if err != nil {
@@ -1483,12 +1942,13 @@ func (c *AviObjCache) AviPopulateOneVsL4PolCache(client *clients.AviClient,
 		cksum := lib.L4PolicyChecksum(ports, protocols, emptyIngestionMarkers, l4pol.Markers, true)
 		l4PolCacheObj := AviL4PolicyCache{
 			Name:             *l4pol.Name,
+			Tenant:           tenantNameFromRef(l4pol.TenantRef),
 			Uuid:             *l4pol.UUID,
 			Pools:            pools,
 			LastModified:     *l4pol.LastModified,
 			CloudConfigCksum: cksum,
 		}
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: *l4pol.Name}
+		k := NamespaceName{Namespace: tenantNameFromRef(l4pol.TenantRef), Name: *l4pol.Name}
 		c.L4PolicyCache.AviCacheAdd(k, &l4PolCacheObj)
 		utils.AviLog.Infof("Adding l4pol to Cache during refresh %s", utils.Stringify(l4PolCacheObj))
 	}
@@ -1496,11 +1956,10 @@ func (c *AviObjCache) AviPopulateOneVsL4PolCache(client *clients.AviClient,
 }
 
 func (c *AviObjCache) PopulateSSLKeyToCache(client *clients.AviClient, cloud string, overrideUri ...NextPage) {
-	var SslKeyData []AviSSLCache
-	c.AviPopulateAllSSLKeys(client, cloud, &SslKeyData)
+	SslKeyData := c.fetchSSLKeys(client, cloud)
 	sslCacheData := c.SSLKeyCache.ShallowCopy()
 	for i, SslKeyCacheObj := range SslKeyData {
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: SslKeyCacheObj.Name}
+		k := NamespaceName{Namespace: SslKeyCacheObj.Tenant, Name: SslKeyCacheObj.Name}
 		oldSslkeyIntf, found := c.SSLKeyCache.AviCacheGet(k)
 		if found {
 			oldSslkeyData, ok := oldSslkeyIntf.(*AviSSLCache)
@@ -1583,6 +2042,7 @@ func (c *AviObjCache) AviPopulateAllHttpPolicySets(client *clients.AviClient, cl
 		}
 		httpPolCacheObj := AviHTTPPolicyCache{
 			Name:             *httppol.Name,
+			Tenant:           tenantNameFromRef(httppol.TenantRef),
 			Uuid:             *httppol.UUID,
 			CloudConfigCksum: *httppol.CloudConfigCksum,
 			PoolGroups:       poolGroups,
@@ -1607,14 +2067,13 @@ func (c *AviObjCache) AviPopulateAllHttpPolicySets(client *clients.AviClient, cl
 }
 
 func (c *AviObjCache) PopulateHttpPolicySetToCache(client *clients.AviClient, cloud string, overrideUri ...NextPage) {
-	var HttPolData []AviHTTPPolicyCache
-	_, count, err := c.AviPopulateAllHttpPolicySets(client, cloud, &HttPolData)
-	if err != nil || len(HttPolData) != count {
+	HttPolData, err := c.fetchHttpPolicySets(client, cloud)
+	if err != nil {
 		return
 	}
 	httpCacheData := c.HTTPPolicyCache.ShallowCopy()
 	for i, HttpPolCacheObj := range HttPolData {
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: HttpPolCacheObj.Name}
+		k := NamespaceName{Namespace: HttpPolCacheObj.Tenant, Name: HttpPolCacheObj.Name}
 		oldHttppolIntf, found := c.HTTPPolicyCache.AviCacheGet(k)
 		if found {
 			oldHttppolData, ok := oldHttppolIntf.(*AviHTTPPolicyCache)
@@ -1702,6 +2161,7 @@ func (c *AviObjCache) AviPopulateAllL4PolicySets(client *clients.AviClient, clou
 		cksum := lib.L4PolicyChecksum(ports, protocols, emptyIngestionMarkers, l4pol.Markers, true)
 		l4PolCacheObj := AviL4PolicyCache{
 			Name:             *l4pol.Name,
+			Tenant:           tenantNameFromRef(l4pol.TenantRef),
 			Uuid:             *l4pol.UUID,
 			Pools:            pools,
 			LastModified:     *l4pol.LastModified,
@@ -1727,14 +2187,13 @@ func (c *AviObjCache) AviPopulateAllL4PolicySets(client *clients.AviClient, clou
 }
 
 func (c *AviObjCache) PopulateL4PolicySetToCache(client *clients.AviClient, cloud string, overrideUri ...NextPage) {
-	var l4PolData []AviL4PolicyCache
-	_, count, err := c.AviPopulateAllL4PolicySets(client, cloud, &l4PolData)
-	if err != nil || len(l4PolData) != count {
+	l4PolData, err := c.fetchL4PolicySets(client, cloud)
+	if err != nil {
 		return
 	}
 	l4CacheData := c.L4PolicyCache.ShallowCopy()
 	for i, l4PolCacheObj := range l4PolData {
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: l4PolCacheObj.Name}
+		k := NamespaceName{Namespace: l4PolCacheObj.Tenant, Name: l4PolCacheObj.Name}
 		utils.AviLog.Debugf("Adding key to l4 cache :%s", utils.Stringify(l4PolCacheObj))
 		c.L4PolicyCache.AviCacheAdd(k, &l4PolData[i])
 		delete(l4CacheData, k)
@@ -1861,7 +2320,9 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 
 			}
 			if vs["cloud_config_cksum"] != nil {
-				k := NamespaceName{Namespace: lib.GetTenant(), Name: vs["name"].(string)}
+				tenantRef, _ := vs["tenant_ref"].(string)
+				tenant := tenantNameFromRef(&tenantRef)
+				k := NamespaceName{Namespace: tenant, Name: vs["name"].(string)}
 				*vsCacheCopy = RemoveNamespaceName(*vsCacheCopy, k)
 				var vsVipKey []NamespaceName
 				var sslKeys []NamespaceName
@@ -1882,7 +2343,7 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 						if foundVip {
 							vsVipData, ok := vsVip.(*AviVSVIPCache)
 							if ok {
-								vipKey := NamespaceName{Namespace: lib.GetTenant(), Name: vsVipData.Name}
+								vipKey := NamespaceName{Namespace: tenant, Name: vsVipData.Name}
 								vsVipKey = append(vsVipKey, vipKey)
 							}
 						}
@@ -1895,19 +2356,13 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 						sslUuid := ExtractUuid(ssl.(string), "sslkeyandcertificate-.*.#")
 						sslName, foundssl := c.SSLKeyCache.AviCacheGetNameByUuid(sslUuid)
 						if foundssl {
-							sslKey := NamespaceName{Namespace: lib.GetTenant(), Name: sslName.(string)}
+							sslKey := NamespaceName{Namespace: tenant, Name: sslName.(string)}
 							sslKeys = append(sslKeys, sslKey)
 
 							sslIntf, _ := c.SSLKeyCache.AviCacheGet(sslKey)
 							sslData := sslIntf.(*AviSSLCache)
-							// Populate CAcert if available
-							if sslData.CACertUUID != "" {
-								caName, found := c.SSLKeyCache.AviCacheGetNameByUuid(sslData.CACertUUID)
-								if found {
-									caCertKey := NamespaceName{Namespace: lib.GetTenant(), Name: caName.(string)}
-									sslKeys = append(sslKeys, caCertKey)
-								}
-							}
+							// Populate CAcerts of the chain if available
+							sslKeys = append(sslKeys, c.caCertChainKeys(sslData)...)
 						}
 					}
 				}
@@ -1920,14 +2375,14 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 
 							dsName, foundDs := c.DSCache.AviCacheGetNameByUuid(dsUuid)
 							if foundDs {
-								dsKey := NamespaceName{Namespace: lib.GetTenant(), Name: dsName.(string)}
+								dsKey := NamespaceName{Namespace: tenant, Name: dsName.(string)}
 								// Fetch the associated PGs with the DS.
 								dsObj, _ := c.DSCache.AviCacheGet(dsKey)
 								for _, pgName := range dsObj.(*AviDSCache).PoolGroups {
 									// For each PG, formulate the key and then populate the pg collection cache
-									pgKey := NamespaceName{Namespace: lib.GetTenant(), Name: pgName}
+									pgKey := NamespaceName{Namespace: tenant, Name: pgName}
 									poolgroupKeys = append(poolgroupKeys, pgKey)
-									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName)
+									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName, tenant)
 									poolKeys = append(poolKeys, pgpoolKeys...)
 								}
 								dsKeys = append(dsKeys, dsKey)
@@ -1946,9 +2401,9 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 
 							pgName, foundpg := c.PgCache.AviCacheGetNameByUuid(pgUuid)
 							if foundpg {
-								pgKey := NamespaceName{Namespace: lib.GetTenant(), Name: pgName.(string)}
+								pgKey := NamespaceName{Namespace: tenant, Name: pgName.(string)}
 								poolgroupKeys = append(poolgroupKeys, pgKey)
-								pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName.(string))
+								pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName.(string), tenant)
 								poolKeys = append(poolKeys, pgpoolKeys...)
 								sharedVsOrL4 = true
 							}
@@ -1963,10 +2418,10 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 							l4Name, foundl4pol := c.L4PolicyCache.AviCacheGetNameByUuid(l4PolUuid)
 							if foundl4pol {
 								sharedVsOrL4 = true
-								l4key := NamespaceName{Namespace: lib.GetTenant(), Name: l4Name.(string)}
+								l4key := NamespaceName{Namespace: tenant, Name: l4Name.(string)}
 								l4Obj, _ := c.L4PolicyCache.AviCacheGet(l4key)
 								for _, poolName := range l4Obj.(*AviL4PolicyCache).Pools {
-									poolKey := NamespaceName{Namespace: lib.GetTenant(), Name: poolName}
+									poolKey := NamespaceName{Namespace: tenant, Name: poolName}
 									poolKeys = append(poolKeys, poolKey)
 								}
 								l4Keys = append(l4Keys, l4key)
@@ -1992,13 +2447,13 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 								}
 							}
 							if foundhttp {
-								httpKey := NamespaceName{Namespace: lib.GetTenant(), Name: httpName.(string)}
+								httpKey := NamespaceName{Namespace: tenant, Name: httpName.(string)}
 								httpObj, _ := c.HTTPPolicyCache.AviCacheGet(httpKey)
 								for _, pgName := range httpObj.(*AviHTTPPolicyCache).PoolGroups {
 									// For each PG, formulate the key and then populate the pg collection cache
-									pgKey := NamespaceName{Namespace: lib.GetTenant(), Name: pgName}
+									pgKey := NamespaceName{Namespace: tenant, Name: pgName}
 									poolgroupKeys = append(poolgroupKeys, pgKey)
-									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName)
+									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName, tenant)
 									poolKeys = append(poolKeys, pgpoolKeys...)
 								}
 								httpKeys = append(httpKeys, httpKey)
@@ -2009,6 +2464,7 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 				// Populate the vscache meta object here.
 				vsMetaObj := AviVsCache{
 					Name:                 vs["name"].(string),
+					Tenant:               tenant,
 					Uuid:                 vs["uuid"].(string),
 					VSVipKeyCollection:   vsVipKey,
 					HTTPKeyCollection:    httpKeys,
@@ -2046,7 +2502,7 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 	return nil
 }
 
-func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud string, vsName string) error {
+func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud string, vsName, tenant string) error {
 	// This method should be called only from layer-3 during a retry.
 	var rest_response interface{}
 	akoUser := lib.AKOUser
@@ -2068,7 +2524,7 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 		}
 		utils.AviLog.Debugf("Vs Get uri %v returned %v vses", uri,
 			resp["count"])
-		k := NamespaceName{Namespace: lib.GetTenant(), Name: vsName}
+		k := NamespaceName{Namespace: tenant, Name: vsName}
 		objCount, _ := resp["count"]
 		if objCount == 0.0 {
 			utils.AviLog.Debugf("Empty response removing VS meta :%s", k)
@@ -2129,7 +2585,7 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 					if foundVip {
 						vsVipData, ok := vsVip.(*AviVSVIPCache)
 						if ok {
-							vipKey := NamespaceName{Namespace: lib.GetTenant(), Name: vsVipData.Name}
+							vipKey := NamespaceName{Namespace: tenant, Name: vsVipData.Name}
 							vsVipKey = append(vsVipKey, vipKey)
 						}
 					}
@@ -2141,19 +2597,13 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 						sslUuid := ExtractUuidWithoutHash(ssl.(string), "sslkeyandcertificate-.*.")
 						sslName, foundssl := c.SSLKeyCache.AviCacheGetNameByUuid(sslUuid)
 						if foundssl {
-							sslKey := NamespaceName{Namespace: lib.GetTenant(), Name: sslName.(string)}
+							sslKey := NamespaceName{Namespace: tenant, Name: sslName.(string)}
 							sslKeys = append(sslKeys, sslKey)
 
 							sslIntf, _ := c.SSLKeyCache.AviCacheGet(sslKey)
 							sslData := sslIntf.(*AviSSLCache)
-							// Populate CAcert if available
-							if sslData.CACertUUID != "" {
-								caName, found := c.SSLKeyCache.AviCacheGetNameByUuid(sslData.CACertUUID)
-								if found {
-									caCertKey := NamespaceName{Namespace: lib.GetTenant(), Name: caName.(string)}
-									sslKeys = append(sslKeys, caCertKey)
-								}
-							}
+							// Populate CAcerts of the chain if available
+							sslKeys = append(sslKeys, c.caCertChainKeys(sslData)...)
 						}
 					}
 				}
@@ -2166,14 +2616,14 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 
 							dsName, foundDs := c.DSCache.AviCacheGetNameByUuid(dsUuid)
 							if foundDs {
-								dsKey := NamespaceName{Namespace: lib.GetTenant(), Name: dsName.(string)}
+								dsKey := NamespaceName{Namespace: tenant, Name: dsName.(string)}
 								// Fetch the associated PGs with the DS.
 								dsObj, _ := c.DSCache.AviCacheGet(dsKey)
 								for _, pgName := range dsObj.(*AviDSCache).PoolGroups {
 									// For each PG, formulate the key and then populate the pg collection cache
-									pgKey := NamespaceName{Namespace: lib.GetTenant(), Name: pgName}
+									pgKey := NamespaceName{Namespace: tenant, Name: pgName}
 									poolgroupKeys = append(poolgroupKeys, pgKey)
-									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName)
+									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName, tenant)
 									poolKeys = append(poolKeys, pgpoolKeys...)
 								}
 								dsKeys = append(dsKeys, dsKey)
@@ -2191,9 +2641,9 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 
 							pgName, foundpg := c.PgCache.AviCacheGetNameByUuid(pgUuid)
 							if foundpg {
-								pgKey := NamespaceName{Namespace: lib.GetTenant(), Name: pgName.(string)}
+								pgKey := NamespaceName{Namespace: tenant, Name: pgName.(string)}
 								poolgroupKeys = append(poolgroupKeys, pgKey)
-								pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName.(string))
+								pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName.(string), tenant)
 								poolKeys = append(poolKeys, pgpoolKeys...)
 							}
 						}
@@ -2206,10 +2656,10 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 							l4PolUuid := ExtractUuid(l4map["l4_policy_set_ref"].(string), "l4policyset-.*.#")
 							l4Name, foundl4pol := c.L4PolicyCache.AviCacheGetNameByUuid(l4PolUuid)
 							if foundl4pol {
-								l4key := NamespaceName{Namespace: lib.GetTenant(), Name: l4Name.(string)}
+								l4key := NamespaceName{Namespace: tenant, Name: l4Name.(string)}
 								l4Obj, _ := c.L4PolicyCache.AviCacheGet(l4key)
 								for _, poolName := range l4Obj.(*AviL4PolicyCache).Pools {
-									poolKey := NamespaceName{Namespace: lib.GetTenant(), Name: poolName}
+									poolKey := NamespaceName{Namespace: tenant, Name: poolName}
 									poolKeys = append(poolKeys, poolKey)
 								}
 								l4Keys = append(l4Keys, l4key)
@@ -2226,13 +2676,13 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 
 							httpName, foundhttp := c.HTTPPolicyCache.AviCacheGetNameByUuid(httpUuid)
 							if foundhttp {
-								httpKey := NamespaceName{Namespace: lib.GetTenant(), Name: httpName.(string)}
+								httpKey := NamespaceName{Namespace: tenant, Name: httpName.(string)}
 								httpObj, _ := c.HTTPPolicyCache.AviCacheGet(httpKey)
 								for _, pgName := range httpObj.(*AviHTTPPolicyCache).PoolGroups {
 									// For each PG, formulate the key and then populate the pg collection cache
-									pgKey := NamespaceName{Namespace: lib.GetTenant(), Name: pgName}
+									pgKey := NamespaceName{Namespace: tenant, Name: pgName}
 									poolgroupKeys = append(poolgroupKeys, pgKey)
-									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName)
+									pgpoolKeys := c.AviPGPoolCachePopulate(client, cloud, pgName, tenant)
 									poolKeys = append(poolKeys, pgpoolKeys...)
 								}
 								httpKeys = append(httpKeys, httpKey)
@@ -2243,6 +2693,7 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 				// Populate the vscache meta object here.
 				vsMetaObj := AviVsCache{
 					Name:                 vs["name"].(string),
+					Tenant:               tenant,
 					Uuid:                 vs["uuid"].(string),
 					VSVipKeyCollection:   vsVipKey,
 					HTTPKeyCollection:    httpKeys,
@@ -2277,16 +2728,16 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 	return nil
 }
 
-func (c *AviObjCache) AviPGPoolCachePopulate(client *clients.AviClient, cloud string, pgName string) []NamespaceName {
+func (c *AviObjCache) AviPGPoolCachePopulate(client *clients.AviClient, cloud string, pgName, tenant string) []NamespaceName {
 	var poolKeyCollection []NamespaceName
 
-	k := NamespaceName{Namespace: lib.GetTenant(), Name: pgName}
+	k := NamespaceName{Namespace: tenant, Name: pgName}
 	// Find the pools associated with this PG and populate them
 	pgObj, ok := c.PgCache.AviCacheGet(k)
 	// Get the members from this and populate the VS ref
 	if ok {
 		for _, poolName := range pgObj.(*AviPGCache).Members {
-			k := NamespaceName{Namespace: lib.GetTenant(), Name: poolName}
+			k := NamespaceName{Namespace: tenant, Name: poolName}
 			poolKeyCollection = append(poolKeyCollection, k)
 		}
 	} else {
@@ -2296,7 +2747,7 @@ func (c *AviObjCache) AviPGPoolCachePopulate(client *clients.AviClient, cloud st
 		if ok {
 			utils.AviLog.Debugf("Found PG on refresh: %s", pgName)
 			for _, poolName := range pgObj.(*AviPGCache).Members {
-				k := NamespaceName{Namespace: lib.GetTenant(), Name: poolName}
+				k := NamespaceName{Namespace: tenant, Name: poolName}
 				poolKeyCollection = append(poolKeyCollection, k)
 			}
 		} else {
@@ -2711,6 +3162,10 @@ func ConfigureSeGroupLabels(client *clients.AviClient, seGroup *models.ServiceEn
 		seGroup.Labels = lib.GetLabels()
 		response := models.ServiceEngineGroupAPIResponse{}
 		err := lib.AviPut(client, uri, seGroup, response)
+		if err == lib.ErrDryRun {
+			utils.AviLog.Infof("dry-run, labels: %v not set on Service Engine Group :%v", utils.Stringify(lib.GetLabels()), segName)
+			return nil
+		}
 		if err != nil {
 			if aviError, ok := err.(session.AviError); ok && aviError.HttpStatusCode == 400 {
 				//SE in provider context
@@ -2770,6 +3225,10 @@ func DeConfigureSeGroupLabels() {
 	response := models.ServiceEngineGroupAPIResponse{}
 
 	err = lib.AviPut(client, uri, seGroup, response)
+	if err == lib.ErrDryRun {
+		utils.AviLog.Infof("dry-run, SE Group labels not deconfigured on %v", segName)
+		return
+	}
 	if err != nil {
 		if aviError, ok := err.(session.AviError); ok && aviError.HttpStatusCode == 400 {
 			//SE in provider context
@@ -3298,6 +3757,8 @@ func ExtractUuid(word, pattern string) string {
 }
 
 func ExtractUuidWithoutHash(word, pattern string) string {
+	// Drop the name of the object from refs fetched with include_name.
+	word = strings.Split(word, "#")[0]
 	r, _ := regexp.Compile(pattern)
 	result := r.FindAllString(word, -1)
 	if len(result) == 1 {
@@ -3305,3 +3766,22 @@ func ExtractUuidWithoutHash(word, pattern string) string {
 	}
 	return ""
 }
+
+// setSessionTenant switches the sessions of the clients to the tenant.
+func setSessionTenant(tenant string, aviClients ...*clients.AviClient) {
+	SetTenant := session.SetTenant(tenant)
+	for _, aviClient := range aviClients {
+		SetTenant(aviClient.AviSession)
+	}
+}
+
+// tenantNameFromRef returns the name of the tenant from a tenant_ref fetched with include_name, it defaults to the
+// tenant of AKO.
+func tenantNameFromRef(tenantRef *string) string {
+	if tenantRef != nil {
+		if ref := strings.Split(*tenantRef, "#"); len(ref) == 2 && ref[1] != "" {
+			return ref[1]
+		}
+	}
+	return lib.GetTenant()
+}
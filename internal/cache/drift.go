@@ -0,0 +1,339 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
+)
+
+// DriftedObject is an Avi object created by AKO, which was modified on the Avi controller outside of AKO.
+type DriftedObject struct {
+	// ObjectType is the Avi collection of the object, e.g. pool.
+	ObjectType string `json:"object_type"`
+	Name       string `json:"name"`
+	Tenant     string `json:"tenant"`
+	Uuid       string `json:"uuid"`
+	// VirtualService is the parent virtualservice referring to the object, empty if none refers to it.
+	VirtualService     string `json:"virtualservice,omitempty"`
+	CachedChecksum     string `json:"cached_checksum,omitempty"`
+	LiveChecksum       string `json:"live_checksum,omitempty"`
+	CachedLastModified string `json:"cached_last_modified,omitempty"`
+	LiveLastModified   string `json:"live_last_modified,omitempty"`
+}
+
+// driftState is the part of an object compared between the controller and the cache.
+type driftState struct {
+	uuid         string
+	checksum     string
+	lastModified string
+	invalidData  bool
+}
+
+type driftScan struct {
+	// avi collection/object name -> parent virtualservice name
+	parentVS map[string]string
+	drifted  []DriftedObject
+	// collections which failed to be fetched, and are not compared
+	failed []string
+}
+
+func (s *driftScan) setError(collection string, err error) {
+	if err != nil {
+		s.failed = append(s.failed, collection)
+	}
+}
+
+func (s *driftScan) compare(collection string, key NamespaceName, cached, live driftState) {
+	if cached.uuid != live.uuid {
+		// The object was recreated, the cache is refreshed by the next full sync.
+		return
+	}
+	if (live.checksum == "" || cached.checksum == live.checksum) && !cached.invalidData &&
+		(cached.lastModified == "" || cached.lastModified == live.lastModified) {
+		return
+	}
+	s.drifted = append(s.drifted, DriftedObject{
+		ObjectType:         collection,
+		Name:               key.Name,
+		Tenant:             key.Namespace,
+		Uuid:               live.uuid,
+		VirtualService:     s.parentVS[collection+"/"+key.Name],
+		CachedChecksum:     cached.checksum,
+		LiveChecksum:       live.checksum,
+		CachedLastModified: cached.lastModified,
+		LiveLastModified:   live.lastModified,
+	})
+}
+
+// driftCollections are the collections other than the virtualservices, compared with the cache by the drift scan.
+var driftCollections = []string{"vsvip", "pool", "poolgroup", "httppolicyset", "l4policyset"}
+
+// DetectDrift lists the uuid and _last_modified of the objects created by AKO on the Avi controller, and returns the
+// objects whose _last_modified differs from the cache. The checksum of the virtualservices, kept by the controller in
+// cloud_config_cksum, is compared as well. The objects are fetched entirely, to recompute their checksums the way
+// the cache does, only for the collections holding cached objects whose _last_modified is not known. The
+// collections which fail to be fetched are skipped, and are returned in the error along with the drifted objects
+// of the rest of the collections.
+func (c *AviObjCache) DetectDrift(client *clients.AviClient, cloud string) ([]DriftedObject, error) {
+	scan := &driftScan{parentVS: c.parentVSIndex()}
+	setSessionTenant(lib.GetCacheTenant(), client)
+	defer setSessionTenant(lib.GetTenant(), client)
+
+	liveVSes, err := c.fetchDriftStates(client, "virtualservice", cloud, "cloud_config_cksum")
+	scan.setError("virtualservice", err)
+	for key, live := range liveVSes {
+		vsCache, found := c.VsCacheMeta.AviCacheGet(key)
+		if !found {
+			continue
+		}
+		if vsCacheObj, ok := vsCache.(*AviVsCache); ok {
+			vsCacheObj.VSCacheLock.RLock()
+			cached := driftState{uuid: vsCacheObj.Uuid, checksum: vsCacheObj.CloudConfigCksum, lastModified: vsCacheObj.LastModified}
+			vsCacheObj.VSCacheLock.RUnlock()
+			scan.compare("virtualservice", key, cached, live)
+		}
+	}
+
+	for _, collection := range driftCollections {
+		live, err := c.fetchDriftStates(client, collection, cloud, "")
+		if err != nil {
+			scan.setError(collection, err)
+			continue
+		}
+		recompute := make(map[NamespaceName]driftState)
+		for key, liveState := range live {
+			cached, found := c.cachedDriftState(collection, key)
+			if !found {
+				continue
+			}
+			if cached.lastModified == "" && !cached.invalidData {
+				// The l4policysets are compared through _last_modified alone.
+				if collection != "l4policyset" {
+					recompute[key] = cached
+				}
+				continue
+			}
+			scan.compare(collection, key, cached, liveState)
+		}
+		if len(recompute) == 0 {
+			continue
+		}
+		liveChecksums, err := c.populateDriftStates(client, collection, cloud)
+		if err != nil {
+			scan.setError(collection, err)
+			continue
+		}
+		for key, cached := range recompute {
+			if liveState, found := liveChecksums[key]; found {
+				scan.compare(collection, key, cached, liveState)
+			}
+		}
+	}
+	if len(scan.failed) > 0 {
+		return scan.drifted, fmt.Errorf("failed to fetch %s from the Avi controller", strings.Join(scan.failed, ", "))
+	}
+	return scan.drifted, nil
+}
+
+// cachedDriftState returns the uuid, checksum and _last_modified of the object in the cache of the collection.
+func (c *AviObjCache) cachedDriftState(collection string, key NamespaceName) (driftState, bool) {
+	cache := c.collectionCache(collection)
+	if cache == nil {
+		return driftState{}, false
+	}
+	cached, found := cache.AviCacheGet(key)
+	if !found {
+		return driftState{}, false
+	}
+	switch cacheObj := cached.(type) {
+	case *AviVSVIPCache:
+		return driftState{cacheObj.Uuid, cacheObj.CloudConfigCksum, cacheObj.LastModified, cacheObj.InvalidData}, true
+	case *AviPoolCache:
+		return driftState{cacheObj.Uuid, cacheObj.CloudConfigCksum, cacheObj.LastModified, cacheObj.InvalidData}, true
+	case *AviPGCache:
+		return driftState{cacheObj.Uuid, cacheObj.CloudConfigCksum, cacheObj.LastModified, cacheObj.InvalidData}, true
+	case *AviHTTPPolicyCache:
+		return driftState{cacheObj.Uuid, cacheObj.CloudConfigCksum, cacheObj.LastModified, cacheObj.InvalidData}, true
+	case *AviL4PolicyCache:
+		return driftState{uuid: cacheObj.Uuid, lastModified: cacheObj.LastModified}, true
+	}
+	return driftState{}, false
+}
+
+// populateDriftStates fetches the objects of the collection entirely, and recomputes their checksums the way the
+// cache does.
+func (c *AviObjCache) populateDriftStates(client *clients.AviClient, collection, cloud string) (map[NamespaceName]driftState, error) {
+	states := make(map[NamespaceName]driftState)
+	var err error
+	switch collection {
+	case "vsvip":
+		var vsVips []AviVSVIPCache
+		_, err = c.AviPopulateAllVSVips(client, cloud, &vsVips)
+		for _, vsVip := range vsVips {
+			states[NamespaceName{Namespace: vsVip.Tenant, Name: vsVip.Name}] = driftState{uuid: vsVip.Uuid, checksum: vsVip.CloudConfigCksum, lastModified: vsVip.LastModified}
+		}
+	case "pool":
+		var pools []AviPoolCache
+		_, _, err = c.AviPopulateAllPools(client, cloud, &pools)
+		for _, pool := range pools {
+			states[NamespaceName{Namespace: pool.Tenant, Name: pool.Name}] = driftState{uuid: pool.Uuid, checksum: pool.CloudConfigCksum, lastModified: pool.LastModified}
+		}
+	case "poolgroup":
+		var pgs []AviPGCache
+		_, _, err = c.AviPopulateAllPGs(client, cloud, &pgs)
+		for _, pg := range pgs {
+			states[NamespaceName{Namespace: pg.Tenant, Name: pg.Name}] = driftState{uuid: pg.Uuid, checksum: pg.CloudConfigCksum, lastModified: pg.LastModified}
+		}
+	case "httppolicyset":
+		var httpPolicySets []AviHTTPPolicyCache
+		_, _, err = c.AviPopulateAllHttpPolicySets(client, cloud, &httpPolicySets)
+		for _, httpPolicySet := range httpPolicySets {
+			states[NamespaceName{Namespace: httpPolicySet.Tenant, Name: httpPolicySet.Name}] = driftState{uuid: httpPolicySet.Uuid, checksum: httpPolicySet.CloudConfigCksum, lastModified: httpPolicySet.LastModified}
+		}
+	}
+	return states, err
+}
+
+// fetchDriftStates lists the uuid and _last_modified of the objects of the collection created by AKO, along with
+// the checksum kept by the controller in checksumField, if set.
+func (c *AviObjCache) fetchDriftStates(client *clients.AviClient, collection, cloud, checksumField string) (map[NamespaceName]driftState, error) {
+	states := make(map[NamespaceName]driftState)
+	fields := "name,uuid,tenant_ref,_last_modified"
+	if checksumField != "" {
+		fields += "," + checksumField
+	}
+	var uri string
+	switch collection {
+	case "vsvip":
+		uri = "/api/vsvip/?name.contains=" + lib.GetNamePrefix() + "&include_name=true&cloud_ref.name=" + cloud
+	case "virtualservice", "pool", "poolgroup":
+		uri = collectionUri(collection, cloud, true)
+	default:
+		uri = collectionUri(collection, cloud, false)
+	}
+	uri += "&fields=" + fields + "&page_size=200"
+	for uri != "" {
+		result, err := lib.AviGetCollectionRaw(client, uri)
+		if err != nil {
+			utils.AviLog.Warnf("Get uri %v returned err for %s %v", uri, collection, err)
+			return nil, err
+		}
+		var elems []map[string]interface{}
+		if err = json.Unmarshal(result.Results, &elems); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s data, err: %v", collection, err)
+		}
+		for _, elem := range elems {
+			name, _ := elem["name"].(string)
+			uuid, _ := elem["uuid"].(string)
+			if name == "" || uuid == "" {
+				continue
+			}
+			state := driftState{uuid: uuid}
+			if checksumField != "" {
+				state.checksum, _ = elem[checksumField].(string)
+			}
+			state.lastModified, _ = elem["_last_modified"].(string)
+			tenantRef, _ := elem["tenant_ref"].(string)
+			states[NamespaceName{Namespace: tenantNameFromRef(&tenantRef), Name: name}] = state
+		}
+		uri = ""
+		if next := strings.Split(result.Next, "/api/"+collection); result.Next != "" && len(next) > 1 {
+			uri = "/api/" + collection + next[1]
+		}
+	}
+	return states, nil
+}
+
+// parentVSIndex maps the objects referred by the virtualservices in the cache to the parent virtualservices,
+// whose models own the objects.
+func (c *AviObjCache) parentVSIndex() map[string]string {
+	index := make(map[string]string)
+	for _, vsKey := range c.VsCacheMeta.AviGetAllKeys() {
+		vsCache, found := c.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			continue
+		}
+		vsCacheObj, ok := vsCache.(*AviVsCache)
+		if !ok {
+			continue
+		}
+		vsCacheObj.VSCacheLock.RLock()
+		parent := vsCacheObj.Name
+		if vsCacheObj.ParentVSRef.Name != "" {
+			parent = vsCacheObj.ParentVSRef.Name
+		}
+		index["virtualservice/"+vsCacheObj.Name] = parent
+		for collection, keys := range map[string][]NamespaceName{
+			"vsvip":         vsCacheObj.VSVipKeyCollection,
+			"pool":          vsCacheObj.PoolKeyCollection,
+			"poolgroup":     vsCacheObj.PGKeyCollection,
+			"httppolicyset": vsCacheObj.HTTPKeyCollection,
+			"l4policyset":   vsCacheObj.L4PolicyCollection,
+		} {
+			for _, key := range keys {
+				index[collection+"/"+key.Name] = parent
+			}
+		}
+		vsCacheObj.VSCacheLock.RUnlock()
+	}
+	return index
+}
+
+// InvalidateChecksum clears the checksum of the drifted object in the cache, so that the next sync of the model
+// owning the object updates the object on the Avi controller with the configuration of the model. Other than the
+// virtualservices, the cache objects are not locked by their readers, so the object is replaced by a copy with the
+// checksum cleared, the way the rest layer updates the cache.
+func (c *AviObjCache) InvalidateChecksum(obj DriftedObject) {
+	cache := c.collectionCache(obj.ObjectType)
+	if cache == nil {
+		return
+	}
+	cache.AviCacheUpdate(NamespaceName{Namespace: obj.Tenant, Name: obj.Name}, func(cached interface{}) interface{} {
+		switch cacheObj := cached.(type) {
+		case *AviVsCache:
+			cacheObj.VSCacheLock.Lock()
+			cacheObj.CloudConfigCksum = ""
+			cacheObj.VSCacheLock.Unlock()
+			return cacheObj
+		case *AviVSVIPCache:
+			objCopy := *cacheObj
+			objCopy.CloudConfigCksum = ""
+			return &objCopy
+		case *AviPoolCache:
+			objCopy := *cacheObj
+			objCopy.CloudConfigCksum = ""
+			return &objCopy
+		case *AviPGCache:
+			objCopy := *cacheObj
+			objCopy.CloudConfigCksum = ""
+			return &objCopy
+		case *AviHTTPPolicyCache:
+			objCopy := *cacheObj
+			objCopy.CloudConfigCksum = ""
+			return &objCopy
+		case *AviL4PolicyCache:
+			objCopy := *cacheObj
+			objCopy.CloudConfigCksum = 0
+			return &objCopy
+		}
+		return cached
+	})
+}
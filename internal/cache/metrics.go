@@ -0,0 +1,55 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var cacheObjectsDesc = prometheus.NewDesc(
+	"ako_avi_cache_objects",
+	"Current number of Avi objects in the AKO cache, by object type.",
+	[]string{"type"}, nil,
+)
+
+// cacheMetricsCollector implements prometheus.Collector, and reports the size of the object caches
+// of AviObjCache when the metrics are scraped.
+type cacheMetricsCollector struct {
+	cache *AviObjCache
+}
+
+func (c *cacheMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheObjectsDesc
+}
+
+func (c *cacheMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	caches := map[string]*AviCache{
+		"VirtualService":       c.cache.VsCacheMeta,
+		"Pool":                 c.cache.PoolCache,
+		"PoolGroup":            c.cache.PgCache,
+		"VSDataScriptSet":      c.cache.DSCache,
+		"HTTPPolicySet":        c.cache.HTTPPolicyCache,
+		"L4PolicySet":          c.cache.L4PolicyCache,
+		"SSLKeyAndCertificate": c.cache.SSLKeyCache,
+		"PKIProfile":           c.cache.PKIProfileCache,
+		"HealthMonitor":        c.cache.HealthMonitorCache,
+		"ApplicationProfile":   c.cache.AppProfileCache,
+		"VsVip":                c.cache.VSVIPCache,
+		"VrfContext":           c.cache.VrfCache,
+	}
+	for objType, cache := range caches {
+		ch <- prometheus.MustNewConstMetric(cacheObjectsDesc, prometheus.GaugeValue, float64(cache.AviCacheLen()), objType)
+	}
+}
@@ -0,0 +1,372 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// The found Avi objects are cached longer than the missing ones, so that an object created on the controller
+	// after a rejection is picked up soon.
+	refLookupTTL         = 5 * time.Minute
+	refLookupNotFoundTTL = 30 * time.Second
+)
+
+type refLookup struct {
+	err    error
+	expiry time.Time
+}
+
+// refLookupCache caches the lookups of the Avi objects referred in the CRDs, so that the admission requests are not
+// held up by the rest calls to the Avi controller.
+type refLookupCache struct {
+	lock    sync.Mutex
+	lookups map[string]refLookup
+}
+
+var admissionRefCache = &refLookupCache{lookups: make(map[string]refLookup)}
+
+var admissionWebhookReady int32
+
+// SetAdmissionWebhookReady sets whether this AKO replica serves the admission requests, which it does once it is the
+// leader and the bootup sync has built the listers the webhook validates against. The AKO pod is labelled accordingly,
+// for the ako-webhook Service to route the admission requests only to the pod serving them.
+func SetAdmissionWebhookReady(cs kubernetes.Interface, ready bool) {
+	var val int32
+	if ready {
+		val = 1
+	}
+	atomic.StoreInt32(&admissionWebhookReady, val)
+	utils.AviLog.Infof("Setting the admission webhook ready flag to: %v", ready)
+
+	podName := os.Getenv("POD_NAME")
+	if cs == nil || podName == "" {
+		return
+	}
+	label := "null"
+	if ready {
+		label = `"` + lib.AdmissionWebhookServingLabelValue + `"`
+	}
+	patch := fmt.Sprintf(`{"metadata":{"labels":{"%s":%s}}}`, lib.AdmissionWebhookServingLabel, label)
+	if _, err := cs.CoreV1().Pods(utils.GetAKONamespace()).Patch(context.TODO(), podName, types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+		utils.AviLog.Warnf("Unable to update the %s label of the AKO pod %s: %v", lib.AdmissionWebhookServingLabel, podName, err)
+	}
+}
+
+func isAdmissionWebhookReady() bool {
+	return atomic.LoadInt32(&admissionWebhookReady) == 1 && lib.IsLeader()
+}
+
+func (c *refLookupCache) checkRefs(key string, refMap map[string]string) error {
+	if avicache.AviClientInstance == nil || len(avicache.AviClientInstance.AviClient) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: Avi clients are not initialized, skipping the checks for the Avi object references", key)
+		return nil
+	}
+	refs := make([]string, 0, len(refMap))
+	for ref := range refMap {
+		if ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		if err := c.checkRef(key, refMap[ref], ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *refLookupCache) checkRef(key, refKey, refValue string) error {
+	lookupKey := refModelMap[refKey] + "/" + refValue
+	c.lock.Lock()
+	lookup, ok := c.lookups[lookupKey]
+	c.lock.Unlock()
+	if ok && time.Now().Before(lookup.expiry) {
+		return lookup.err
+	}
+
+	err := checkRefOnController(key, refKey, refValue)
+	lookup = refLookup{err: err, expiry: time.Now().Add(refLookupTTL)}
+	if err != nil {
+		lookup.expiry = time.Now().Add(refLookupNotFoundTTL)
+	}
+	c.lock.Lock()
+	c.lookups[lookupKey] = lookup
+	c.lock.Unlock()
+	return err
+}
+
+// ServeAdmissionReview serves the AdmissionReview requests of the validating admission webhook, for the HostRule,
+// HTTPRule and AviInfraSetting objects. The requests fail with 503 until this replica is ready to serve them, which the
+// failurePolicy of the webhook decides on.
+func ServeAdmissionReview(w http.ResponseWriter, r *http.Request) {
+	if !isAdmissionWebhookReady() {
+		utils.RespondError(w, http.StatusServiceUnavailable, "AKO is not ready to serve the admission requests")
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		utils.RespondError(w, http.StatusBadRequest, "invalid AdmissionReview")
+		return
+	}
+
+	response := admitRequest(review.Request)
+	response.UID = review.Request.UID
+	review.Request = nil
+	review.Response = response
+	utils.Respond(w, review)
+}
+
+func admitRequest(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	key := req.Kind.Kind + "/" + req.Namespace + "/" + req.Name
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	var err error
+	switch req.Kind.Kind {
+	case lib.HostRule:
+		err = admitHostRule(key, req)
+	case lib.HTTPRule:
+		err = admitHTTPRule(key, req)
+	case lib.AviInfraSetting:
+		err = admitAviInfraSetting(key, req)
+	}
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: rejected by the admission webhook: %v", key, err)
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+				Message: err.Error(),
+			},
+		}
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// decodeAdmissionObjects decodes the object of the request, and the old object for the updates. Returns false if the
+// spec of the object is not updated, in which case the object need not be validated again.
+func decodeAdmissionObjects(req *admissionv1.AdmissionRequest, obj, oldObj interface{}, spec func(interface{}) interface{}) (bool, error) {
+	if err := json.Unmarshal(req.Object.Raw, obj); err != nil {
+		return false, fmt.Errorf("unable to decode %s: %v", req.Kind.Kind, err)
+	}
+	if req.Operation != admissionv1.Update || len(req.OldObject.Raw) == 0 {
+		return true, nil
+	}
+	if err := json.Unmarshal(req.OldObject.Raw, oldObj); err != nil {
+		return true, nil
+	}
+	return !reflect.DeepEqual(spec(obj), spec(oldObj)), nil
+}
+
+func admitHostRule(key string, req *admissionv1.AdmissionRequest) error {
+	hostrule, oldHostrule := &akov1alpha1.HostRule{}, &akov1alpha1.HostRule{}
+	updated, err := decodeAdmissionObjects(req, hostrule, oldHostrule, func(obj interface{}) interface{} {
+		return obj.(*akov1alpha1.HostRule).Spec
+	})
+	if err != nil || !updated {
+		return err
+	}
+	if hostrule.Namespace == "" {
+		hostrule.Namespace = req.Namespace
+	}
+
+	refData, err := checkHostRuleSpec(hostrule)
+	if err != nil {
+		return err
+	}
+	if err := checkHostRuleCollisions(hostrule); err != nil {
+		return err
+	}
+	return admissionRefCache.checkRefs(key, refData)
+}
+
+// checkHostRuleCollisions checks the fqdn and the aliases of the hostrule against the other hostrules, including the
+// ones not yet processed by AKO, and the aliases against the hosts of the Ingresses and Routes.
+func checkHostRuleCollisions(hostrule *akov1alpha1.HostRule) error {
+	fqdn := hostrule.Spec.VirtualHost.Fqdn
+	aliases := hostrule.Spec.VirtualHost.Aliases
+	if lib.AKOControlConfig().CRDInformers() != nil && lib.AKOControlConfig().CRDInformers().HostRuleInformer != nil {
+		hostrules, err := lib.AKOControlConfig().CRDInformers().HostRuleInformer.Lister().List(labels.Everything())
+		if err != nil {
+			return err
+		}
+		for _, hr := range hostrules {
+			if hr.Namespace == hostrule.Namespace && hr.Name == hostrule.Name {
+				continue
+			}
+			hrName := hr.Namespace + "/" + hr.Name
+			if hr.Spec.VirtualHost.Fqdn == fqdn {
+				return fmt.Errorf("duplicate fqdn %s found in %s", fqdn, hrName)
+			}
+			for _, alias := range aliases {
+				if alias == hr.Spec.VirtualHost.Fqdn || utils.HasElem(hr.Spec.VirtualHost.Aliases, alias) {
+					return fmt.Errorf("%s is already in use by hostrule %s", alias, hrName)
+				}
+			}
+		}
+	}
+
+	// An alias which is a host of an Ingress or a Route would be served by two virtualservices.
+	nodes.SharedHostNameLister().RLock()
+	defer nodes.SharedHostNameLister().RUnlock()
+	for _, alias := range aliases {
+		found, hostPaths := nodes.SharedHostNameLister().GetHostPathStore(alias)
+		if !found || len(hostPaths) == 0 {
+			continue
+		}
+		var objs []string
+		for _, pathObjs := range hostPaths {
+			for _, obj := range pathObjs {
+				if !utils.HasElem(objs, obj) {
+					objs = append(objs, obj)
+				}
+			}
+		}
+		sort.Strings(objs)
+		return fmt.Errorf("alias %s is already a host of %s", alias, strings.Join(objs, ", "))
+	}
+	return nil
+}
+
+func admitHTTPRule(key string, req *admissionv1.AdmissionRequest) error {
+	httprule, oldHTTPRule := &akov1alpha1.HTTPRule{}, &akov1alpha1.HTTPRule{}
+	updated, err := decodeAdmissionObjects(req, httprule, oldHTTPRule, func(obj interface{}) interface{} {
+		return obj.(*akov1alpha1.HTTPRule).Spec
+	})
+	if err != nil || !updated {
+		return err
+	}
+
+	refData, err := checkHTTPRuleSpec(httprule)
+	if err != nil {
+		return err
+	}
+	return admissionRefCache.checkRefs(key, refData)
+}
+
+func admitAviInfraSetting(key string, req *admissionv1.AdmissionRequest) error {
+	infraSetting, oldInfraSetting := &akov1alpha1.AviInfraSetting{}, &akov1alpha1.AviInfraSetting{}
+	updated, err := decodeAdmissionObjects(req, infraSetting, oldInfraSetting, func(obj interface{}) interface{} {
+		return obj.(*akov1alpha1.AviInfraSetting).Spec
+	})
+	if err != nil || !updated {
+		return err
+	}
+
+	refData, err := checkAviInfraSettingSpec(infraSetting)
+	if err != nil {
+		return err
+	}
+	return admissionRefCache.checkRefs(key, refData)
+}
+
+// webhookCertificate loads the certificate of the webhook server from the mounted Secret, and reloads it when the
+// Secret is rotated.
+type webhookCertificate struct {
+	lock     sync.Mutex
+	certFile string
+	keyFile  string
+	modTime  time.Time
+	cert     *tls.Certificate
+}
+
+func (c *webhookCertificate) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	info, err := os.Stat(c.certFile)
+	if err != nil {
+		return nil, err
+	}
+	if c.cert == nil || !info.ModTime().Equal(c.modTime) {
+		cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err != nil {
+			return nil, err
+		}
+		utils.AviLog.Infof("Loaded the admission webhook certificate from %s", c.certFile)
+		c.cert, c.modTime = &cert, info.ModTime()
+	}
+	return c.cert, nil
+}
+
+// StartAdmissionWebhook starts the https server of the validating admission webhook, which is shut down when the
+// stop channel is closed. The label left on the AKO pod by an earlier run is removed, since the requests are served
+// only once SetAdmissionWebhookReady is called.
+func StartAdmissionWebhook(cs kubernetes.Interface, stopCh <-chan struct{}) {
+	SetAdmissionWebhookReady(cs, false)
+	cert := &webhookCertificate{
+		certFile: filepath.Join(lib.AdmissionWebhookCertDir, "tls.crt"),
+		keyFile:  filepath.Join(lib.AdmissionWebhookCertDir, "tls.key"),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(lib.AdmissionWebhookPath, ServeAdmissionReview)
+	server := &http.Server{
+		Addr:         ":" + lib.GetAdmissionWebhookPort(),
+		Handler:      utils.LogApi(mux),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: cert.getCertificate,
+		},
+	}
+	go func() {
+		utils.AviLog.Infof("Starting the admission webhook server at %s", server.Addr)
+		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			utils.AviLog.Errorf("Admission webhook server failed: %v", err)
+		}
+	}()
+	go func() {
+		<-stopCh
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+}
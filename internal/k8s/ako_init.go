@@ -49,42 +49,59 @@ import (
 )
 
 func PopulateCache() error {
-	var parentKeys []avicache.NamespaceName
-	var err error
+	if err := populateAviObjCache(); err != nil {
+		return err
+	}
+	cleanupStaleAviObjects()
+	return nil
+}
+
+// populateAviObjCache populates the Avi object cache from the Avi controller, without modifying any object.
+func populateAviObjCache() error {
 	avi_rest_client_pool := avicache.SharedAVIClients()
 	avi_obj_cache := avicache.SharedAviObjCache()
 	// Randomly pickup a client.
 	if avi_rest_client_pool != nil && len(avi_rest_client_pool.AviClient) > 0 {
-		_, parentKeys, err = avi_obj_cache.AviObjCachePopulate(avi_rest_client_pool.AviClient, utils.CtrlVersion, utils.CloudName)
+		// The controller cluster uuid is required to validate the checkpoint of the cache.
+		if err := avicache.SetControllerClusterUUID(avi_rest_client_pool); err != nil {
+			utils.AviLog.Warnf("Failed to set the controller cluster uuid with error: %v", err)
+		}
+		_, _, err := avi_obj_cache.AviObjCachePopulate(avi_rest_client_pool.AviClient, utils.CtrlVersion, utils.CloudName)
 		if err != nil {
 			utils.AviLog.Warnf("failed to populate avi cache with error: %v", err.Error())
 			return err
 		}
-		if lib.GetDeleteConfigMap() {
-			go SetDeleteSyncChannel()
-			deleteAviObjects(parentKeys, avi_obj_cache, avi_rest_client_pool)
-		}
-		if err = avicache.SetControllerClusterUUID(avi_rest_client_pool); err != nil {
-			utils.AviLog.Warnf("Failed to set the controller cluster uuid with error: %v", err)
-		}
+	}
+	return nil
+}
+
+// cleanupStaleAviObjects deletes the stale objects from the Avi controller, and all the objects created by AKO
+// if deleteConfig is set.
+func cleanupStaleAviObjects() {
+	avi_rest_client_pool := avicache.SharedAVIClients()
+	avi_obj_cache := avicache.SharedAviObjCache()
+	if avi_rest_client_pool != nil && len(avi_rest_client_pool.AviClient) > 0 && lib.GetDeleteConfigMap() {
+		go SetDeleteSyncChannel()
+		deleteAviObjects(avi_obj_cache.VsCacheMeta.AviCacheGetAllParentVSKeys(), avi_obj_cache, avi_rest_client_pool)
 	}
 
 	// Delete Stale objects by deleting model for dummy VS
 	aviclient := avicache.SharedAVIClients()
 	restlayer := rest.NewRestOperations(avi_obj_cache, aviclient)
-	staleVSKey := lib.GetTenant() + "/" + lib.DummyVSForStaleData
 	if _, err := lib.IsClusterNameValid(); err != nil {
 		utils.AviLog.Errorf("AKO cluster name is invalid.")
-		return nil
+		return
 	}
 	if aviclient != nil && len(aviclient.AviClient) > 0 {
 		utils.AviLog.Infof("Starting clean up of stale objects")
-		restlayer.CleanupVS(staleVSKey, true)
-		staleCacheKey := avicache.NamespaceName{
-			Name:      lib.DummyVSForStaleData,
-			Namespace: lib.GetTenant(),
+		// There is a dummy VS for the stale objects of each tenant.
+		for _, staleCacheKey := range avi_obj_cache.VsCacheMeta.AviGetAllKeys() {
+			if staleCacheKey.Name != lib.DummyVSForStaleData {
+				continue
+			}
+			restlayer.CleanupVS(staleCacheKey.Namespace+"/"+lib.DummyVSForStaleData, true)
+			avi_obj_cache.VsCacheMeta.AviCacheDelete(staleCacheKey)
 		}
-		avi_obj_cache.VsCacheMeta.AviCacheDelete(staleCacheKey)
 	}
 
 	vsKeysPending := avi_obj_cache.VsCacheMeta.AviGetAllKeys()
@@ -97,8 +114,6 @@ func PopulateCache() error {
 		close(lib.ConfigDeleteSyncChan)
 		lib.ConfigDeleteSyncChan = nil
 	}
-
-	return nil
 }
 
 func deleteAviObjects(parentVSKeys []avicache.NamespaceName, avi_obj_cache *avicache.AviObjCache, avi_rest_client_pool *utils.AviRestClientPool) {
@@ -358,7 +373,8 @@ func (c *AviController) HandleConfigMap(k8sinfo K8sinformers, ctrlCh chan struct
 			delModels := delConfigFromData(cm.Data)
 			c.DisableSync = !isValidUserInput || delModels
 			lib.SetDisableSync(c.DisableSync)
-			if isValidUserInput {
+			// The standby replicas leave the models to the leader.
+			if isValidUserInput && lib.IsLeader() {
 				if delModels {
 					c.DeleteModels()
 					SetDeleteSyncChannel()
@@ -467,6 +483,9 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 	// set up signals so we handle the first shutdown signal gracefully
 	var worker *utils.FullSyncThread
 	var tokenWorker *utils.FullSyncThread
+	var driftWorker *utils.FullSyncThread
+	var certExpiryWorker *utils.FullSyncThread
+	var gslbServiceWorker *utils.FullSyncThread
 	informersArg := make(map[string]interface{})
 	informersArg[utils.INFORMERS_OPENSHIFT_CLIENT] = informers.OshiftClient
 	if lib.GetNamespaceToSync() != "" {
@@ -495,25 +514,9 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 	  3. Initialize the ingestion layer queue for partial sync.
 	  **/
 	// start the go routines draining the queues in various layers
-	var graphQueue *utils.WorkerQueue
-	// This is the first time initialization of the queue. For hostname based sharding, we don't want layer 2 to process the queue using multiple go routines.
-	var retryQueueWorkers uint32
-	retryQueueWorkers = 1
-	slowRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.SLOW_RETRY_LAYER, SlowSyncTime: lib.SLOW_SYNC_TIME}
-	fastRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.FAST_RETRY_LAYER}
+	graphQueue := initWorkQueues()
 
-	numWorkers := uint32(1)
-	ingestionQueueParams := utils.WorkerQueue{NumWorkers: numWorkers, WorkqueueName: utils.ObjectIngestionLayer}
-	numGraphWorkers := lib.GetshardSize()
-	if numGraphWorkers == 0 {
-		// For dedicated VSes - we will have 8 threads layer 3
-		numGraphWorkers = 8
-	}
-	graphQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.GraphLayer}
-	statusQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.StatusQueue}
-	graphQueue = utils.SharedWorkQueue(&ingestionQueueParams, &graphQueueParams, &slowRetryQParams, &fastRetryQParams, &statusQueueParams).GetQueueByName(utils.GraphLayer)
-
-	err := PopulateCache()
+	err := populateAviObjCache()
 	if err != nil {
 		c.DisableSync = true
 		utils.AviLog.Errorf("failed to populate cache, disabling sync")
@@ -528,6 +531,14 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 	}
 	c.Start(stopCh)
 
+	// The standby replicas keep the informers and the avi cache warm, and start the layers once elected as the leader.
+	if !c.WaitForLeadership(ctrlCh) {
+		return
+	}
+	if err == nil {
+		cleanupStaleAviObjects()
+	}
+
 	// once the l3 cache is populated, we can call the updatestatus functions from here
 	restlayer := rest.NewRestOperations(avicache.SharedAviObjCache(), avicache.SharedAVIClients())
 	restlayer.SyncObjectStatuses()
@@ -571,8 +582,33 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 			tokenWorker.SyncFunction = c.RefreshAuthToken
 			go tokenWorker.Run()
 		}
+
+		if lib.GetDriftDetectionMode() != lib.DriftDetectionDisabled {
+			driftWorker = utils.NewFullSyncThread(time.Duration(lib.GetDriftScanInterval()) * time.Second)
+			driftWorker.SyncFunction = c.DetectDrift
+			go driftWorker.Run()
+		}
+
+		if lib.GetCertExpiryAlertDays() != 0 {
+			certExpiryWorker = utils.NewFullSyncThread(time.Duration(lib.CertExpiryCheckInterval) * time.Hour)
+			certExpiryWorker.SyncFunction = c.CheckCertificateExpiry
+			go certExpiryWorker.Run()
+			// The models are built by the bootup sync, check the certificates without waiting for the interval.
+			go c.CheckCertificateExpiry()
+		}
+
+		if lib.AKOControlConfig().HostRuleEnabled() {
+			gslbServiceWorker = utils.NewFullSyncThread(time.Duration(lib.GSLBServiceSyncInterval) * time.Second)
+			gslbServiceWorker.SyncFunction = c.SyncGSLBServices
+			// The HostRule events trigger a sync of the GSLBServices alone.
+			gslbServiceWorker.QuickSyncFunction = func() error { return nil }
+			go gslbServiceWorker.Run()
+		}
 	}
 	c.SetupEventHandlers(informers)
+	if lib.IsAdmissionWebhookEnabled() && !lib.GetAdvancedL4() {
+		SetAdmissionWebhookReady(informers.Cs, true)
+	}
 	if lib.DisableSync {
 		lib.AKOControlConfig().PodEventf(corev1.EventTypeNormal, lib.AKODeleteConfigSet, "AKO is in disable sync state")
 	} else {
@@ -600,6 +636,10 @@ LABEL:
 		select {
 		case <-quickSyncCh:
 			worker.QuickSync()
+		case <-gslbServiceSyncCh:
+			if gslbServiceWorker != nil {
+				gslbServiceWorker.QuickSync()
+			}
 		case <-ctrlCh:
 			break LABEL
 		}
@@ -607,6 +647,15 @@ LABEL:
 	if worker != nil {
 		worker.Shutdown()
 	}
+	if driftWorker != nil {
+		driftWorker.Shutdown()
+	}
+	if certExpiryWorker != nil {
+		certExpiryWorker.Shutdown()
+	}
+	if gslbServiceWorker != nil {
+		gslbServiceWorker.Shutdown()
+	}
 
 	ingestionQueue.StopWorkers(stopCh)
 	graphQueue.StopWorkers(stopCh)
@@ -615,6 +664,26 @@ LABEL:
 	statusQueue.StopWorkers(stopCh)
 }
 
+// initWorkQueues initializes the queues of all the layers, and returns the graph layer queue.
+func initWorkQueues() *utils.WorkerQueue {
+	// This is the first time initialization of the queue. For hostname based sharding, we don't want layer 2 to process the queue using multiple go routines.
+	var retryQueueWorkers uint32
+	retryQueueWorkers = 1
+	slowRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.SLOW_RETRY_LAYER, SlowSyncTime: lib.SLOW_SYNC_TIME}
+	fastRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.FAST_RETRY_LAYER}
+
+	numWorkers := uint32(1)
+	ingestionQueueParams := utils.WorkerQueue{NumWorkers: numWorkers, WorkqueueName: utils.ObjectIngestionLayer}
+	numGraphWorkers := lib.GetshardSize()
+	if numGraphWorkers == 0 {
+		// For dedicated VSes - we will have 8 threads layer 3
+		numGraphWorkers = 8
+	}
+	graphQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.GraphLayer}
+	statusQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.StatusQueue}
+	return utils.SharedWorkQueue(&ingestionQueueParams, &graphQueueParams, &slowRetryQParams, &fastRetryQParams, &statusQueueParams).GetQueueByName(utils.GraphLayer)
+}
+
 func (c *AviController) RefreshAuthToken() {
 	lib.RefreshAuthToken(c.informers.KubeClientIntf.ClientSet)
 }
@@ -652,6 +721,18 @@ func (c *AviController) addIndexers() {
 				}
 				return []string{}, nil
 			},
+			lib.L4RuleServicesIndex: func(obj interface{}) ([]string, error) {
+				service, ok := obj.(*corev1.Service)
+				if !ok {
+					return []string{}, nil
+				}
+				if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+					if val, ok := service.Annotations[lib.L4RuleAnnotation]; ok && val != "" {
+						return []string{service.Namespace + "/" + val}, nil
+					}
+				}
+				return []string{}, nil
+			},
 		},
 	)
 	if c.informers.RouteInformer != nil {
@@ -679,6 +760,8 @@ func (c *AviController) FullSync() {
 	avi_obj_cache := avicache.SharedAviObjCache()
 	// Randomly pickup a client.
 	if len(avi_rest_client_pool.AviClient) > 0 {
+		startTime := time.Now()
+		defer func() { utils.ObserveFullSync(time.Since(startTime)) }()
 		avi_obj_cache.AviClusterStatusPopulate(avi_rest_client_pool.AviClient[0])
 		if !lib.GetAdvancedL4() {
 			avi_obj_cache.AviCacheRefresh(avi_rest_client_pool.AviClient[0], utils.CloudName)
@@ -742,18 +825,21 @@ func (c *AviController) FullSyncK8s() error {
 			}
 			// Publish vrfcontext model now, this has to be processed first
 			vrfModelName = lib.GetModelName(lib.GetTenant(), lib.GetVrf())
-			utils.AviLog.Infof("Processing model for vrf context in full sync: %s", vrfModelName)
-			nodes.PublishKeyToRestLayer(vrfModelName, "fullsync", sharedQueue)
-			timeout := make(chan bool, 1)
-			go func() {
-				time.Sleep(20 * time.Second)
-				timeout <- true
-			}()
-			select {
-			case <-lib.StaticRouteSyncChan:
-				utils.AviLog.Infof("Processing done for VRF")
-			case <-timeout:
-				utils.AviLog.Warnf("Timed out while waiting for rest layer to respond, moving on with bootup")
+			// There is no rest layer to process the vrf context in the offline sync.
+			if !c.offlineSync {
+				utils.AviLog.Infof("Processing model for vrf context in full sync: %s", vrfModelName)
+				nodes.PublishKeyToRestLayer(vrfModelName, "fullsync", sharedQueue)
+				timeout := make(chan bool, 1)
+				go func() {
+					time.Sleep(20 * time.Second)
+					timeout <- true
+				}()
+				select {
+				case <-lib.StaticRouteSyncChan:
+					utils.AviLog.Infof("Processing done for VRF")
+				case <-timeout:
+					utils.AviLog.Warnf("Timed out while waiting for rest layer to respond, moving on with bootup")
+				}
 			}
 		} else {
 			utils.AviLog.Warnf("AKO is not primary instance, skipping vrf context publish in full sync.")
@@ -858,6 +944,32 @@ func (c *AviController) FullSyncK8s() error {
 		}
 
 	}
+
+	// Section for Gateway API
+	if lib.UseGatewayAPI() {
+		// The routes are synced before the Gateways, so that the route to Gateway relations are
+		// available while building the Gateways.
+		informer := lib.AKOControlConfig().GatewayAPIInformers()
+		gwApiObjTypes := []string{lib.HTTPRoute, lib.TLSRoute, lib.TCPRoute, lib.UDPRoute, lib.GatewayAPIGateway}
+		gwApiInformers := []cache.SharedIndexInformer{
+			informer.HTTPRouteInformer.Informer(),
+			informer.TLSRouteInformer.Informer(),
+			informer.TCPRouteInformer.Informer(),
+			informer.UDPRouteInformer.Informer(),
+			informer.GatewayInformer.Informer(),
+		}
+		for i, objType := range gwApiObjTypes {
+			for _, obj := range gwApiInformers[i].GetStore().List() {
+				meta, err := meta.Accessor(obj)
+				if err != nil || !utils.CheckIfNamespaceAccepted(meta.GetNamespace()) {
+					continue
+				}
+				key := objType + "/" + meta.GetNamespace() + "/" + meta.GetName()
+				objects.SharedResourceVerInstanceLister().Save(key, meta.GetResourceVersion())
+				nodes.DequeueIngestion(key, true)
+			}
+		}
+	}
 	if !lib.GetAdvancedL4() {
 		hostRuleObjs, err := lib.AKOControlConfig().CRDInformers().HostRuleInformer.Lister().HostRules(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
 		if err != nil {
@@ -895,6 +1007,26 @@ func (c *AviController) FullSyncK8s() error {
 			}
 		}
 
+		if lib.AKOControlConfig().L4RuleEnabled() {
+			l4RuleObjs, err := lib.AKOControlConfig().CRDInformers().L4RuleInformer.Lister().L4Rules(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+			if err != nil {
+				utils.AviLog.Errorf("Unable to retrieve the l4rules during full sync: %s", err)
+			} else {
+				for _, l4RuleObj := range l4RuleObjs {
+					key := lib.L4Rule + "/" + utils.ObjKey(l4RuleObj)
+					meta, err := meta.Accessor(l4RuleObj)
+					if err == nil {
+						resVer := meta.GetResourceVersion()
+						objects.SharedResourceVerInstanceLister().Save(key, resVer)
+					}
+					if err := validateL4RuleObj(key, l4RuleObj); err != nil {
+						utils.AviLog.Warnf("key: %s, Error retrieved during validation of L4Rule: %v", key, err)
+					}
+					nodes.DequeueIngestion(key, true)
+				}
+			}
+		}
+
 		aviInfraObjs, err := lib.AKOControlConfig().CRDInformers().AviInfraSettingInformer.Lister().List(labels.Set(nil).AsSelector())
 		if err != nil {
 			utils.AviLog.Errorf("Unable to retrieve the avinfrasettings during full sync: %s", err)
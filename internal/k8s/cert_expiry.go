@@ -0,0 +1,160 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const certExpiryKey = "certexpiry"
+
+// CertificateAlert is a certificate served by AKO found expiring, expired or not rotated by the expiry check.
+type CertificateAlert struct {
+	Name     string
+	Model    string
+	Hosts    []string
+	Secret   string
+	Reason   string
+	NotAfter time.Time
+}
+
+// CheckCertificateExpiry raises Warning Events on the Kubernetes objects and on the Secrets of the certificates
+// served by AKO, which expire within the configured number of days, or have expired. The Secrets managed by
+// cert-manager are reported as not rotated once two thirds of the lifetime of their certificate have elapsed,
+// which is when cert-manager renews them by default. The expiry of all the certificates is exported as metrics.
+func (c *AviController) CheckCertificateExpiry() {
+	if lib.GetCertExpiryAlertDays() == 0 || c.DisableSync || !lib.IsLeader() {
+		return
+	}
+	expiry, alerts := CertificateExpiryAlerts(time.Now())
+
+	alertCounts := map[string]int{lib.CertificateExpiring: 0, lib.CertificateExpired: 0, lib.SecretNotRotated: 0}
+	for _, alert := range alerts {
+		alertCounts[alert.Reason]++
+		message := certificateAlertMessage(alert)
+		utils.AviLog.Warnf("key: %s, msg: %s", certExpiryKey, message)
+		status.PublishCertificateStatus(alert.Model, alert.Hosts, alert.Reason, message)
+		if alert.Secret == "" {
+			continue
+		}
+		namespace, name := utils.ExtractNamespaceObjectName(alert.Secret)
+		secret, err := utils.GetInformers().SecretInformer.Lister().Secrets(namespace).Get(name)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: unable to get secret %s: %v", certExpiryKey, alert.Secret, err)
+			continue
+		}
+		lib.AKOControlConfig().EventRecorder().Event(secret, corev1.EventTypeWarning, alert.Reason, message)
+	}
+	utils.SetCertificateExpiry(expiry, alertCounts)
+}
+
+// CertificateExpiryAlerts returns the expiry of the certificates of all the models, and the certificates to alert.
+func CertificateExpiryAlerts(now time.Time) (map[string]utils.CertificateExpiry, []CertificateAlert) {
+	alertWindow := time.Duration(lib.GetCertExpiryAlertDays()) * 24 * time.Hour
+	expiry := make(map[string]utils.CertificateExpiry)
+	var alerts []CertificateAlert
+	for modelName, modelIntf := range objects.SharedAviGraphLister().GetAll().(map[string]interface{}) {
+		aviModel, ok := modelIntf.(*nodes.AviObjectGraph)
+		if !ok || aviModel == nil {
+			continue
+		}
+		for _, certNode := range modelCertificates(aviModel) {
+			if _, found := expiry[certNode.Name]; found || certNode.Type != lib.CertTypeVS {
+				continue
+			}
+			cert, err := lib.ParseLeafCertificate(certNode.Cert)
+			if err != nil {
+				utils.AviLog.Debugf("key: %s, msg: unable to parse the certificate %s: %v", certExpiryKey, certNode.Name, err)
+				continue
+			}
+			expiry[certNode.Name] = utils.CertificateExpiry{Secret: certNode.Secret, NotAfter: cert.NotAfter}
+
+			alert := CertificateAlert{
+				Name:     certNode.Name,
+				Model:    modelName,
+				Hosts:    certNode.AviMarkers.Host,
+				Secret:   certNode.Secret,
+				NotAfter: cert.NotAfter,
+			}
+			if !now.Before(cert.NotAfter) {
+				alert.Reason = lib.CertificateExpired
+			} else if cert.NotAfter.Sub(now) < alertWindow {
+				alert.Reason = lib.CertificateExpiring
+			} else if certNode.Secret != "" && isCertManagerSecret(certNode.Secret) &&
+				now.After(cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore)*2/3)) {
+				alert.Reason = lib.SecretNotRotated
+			}
+			if alert.Reason != "" {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+	return expiry, alerts
+}
+
+// modelCertificates returns the certificates of the virtualservices of the model, and of their child virtualservices.
+func modelCertificates(aviModel *nodes.AviObjectGraph) []*nodes.AviTLSKeyCertNode {
+	aviModel.Lock.RLock()
+	defer aviModel.Lock.RUnlock()
+	var certNodes []*nodes.AviTLSKeyCertNode
+	for _, vsNode := range aviModel.GetAviVS() {
+		certNodes = append(certNodes, vsNode.SSLKeyCertRefs...)
+		for _, sniNode := range vsNode.SniNodes {
+			certNodes = append(certNodes, sniNode.SSLKeyCertRefs...)
+		}
+	}
+	for _, vsNode := range aviModel.GetAviEvhVS() {
+		certNodes = append(certNodes, vsNode.SSLKeyCertRefs...)
+		for _, evhNode := range vsNode.EvhNodes {
+			certNodes = append(certNodes, evhNode.SSLKeyCertRefs...)
+		}
+	}
+	return certNodes
+}
+
+// isCertManagerSecret returns true if the Secret is issued by a cert-manager Certificate.
+func isCertManagerSecret(secretNSName string) bool {
+	namespace, name := utils.ExtractNamespaceObjectName(secretNSName)
+	secret, err := utils.GetInformers().SecretInformer.Lister().Secrets(namespace).Get(name)
+	if err != nil {
+		return false
+	}
+	_, ok := secret.Annotations[lib.CertManagerCertAnnotation]
+	return ok
+}
+
+func certificateAlertMessage(alert CertificateAlert) string {
+	notAfter := alert.NotAfter.UTC().Format(time.RFC3339)
+	hosts := strings.Join(alert.Hosts, ", ")
+	switch alert.Reason {
+	case lib.CertificateExpired:
+		return fmt.Sprintf("certificate %s for hosts %s expired on %s", alert.Name, hosts, notAfter)
+	case lib.SecretNotRotated:
+		return fmt.Sprintf("certificate %s for hosts %s was not renewed by cert-manager in secret %s, it expires on %s",
+			alert.Name, hosts, alert.Secret, notAfter)
+	}
+	return fmt.Sprintf("certificate %s for hosts %s expires on %s, in %d days", alert.Name, hosts, notAfter,
+		int(time.Until(alert.NotAfter).Hours()/24))
+}
@@ -27,6 +27,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	oshiftclient "github.com/openshift/client-go/route/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -59,6 +60,10 @@ type AviController struct {
 	dynamicInformers *lib.DynamicInformers
 	workqueue        []workqueue.RateLimitingInterface
 	DisableSync      bool
+	// leaderCh is closed once this replica is elected as the leader, nil if leader election is disabled.
+	leaderCh chan struct{}
+	// offlineSync is set when the models are built without the Avi controller, see OfflineSync.
+	offlineSync bool
 }
 
 type K8sinformers struct {
@@ -92,29 +97,28 @@ func isNodeUpdated(oldNode, newNode *corev1.Node) bool {
 		return false
 	}
 
-	var oldaddr, newaddr string
+	var oldaddrs, newaddrs []string
 	oldAddrs := oldNode.Status.Addresses
 	newAddrs := newNode.Status.Addresses
 	if len(oldAddrs) != len(newAddrs) {
 		return true
 	}
 
+	// dual-stack nodes have an IPv4 and an IPv6 InternalIP.
 	for _, addr := range oldAddrs {
 		if addr.Type == "InternalIP" {
-			oldaddr = addr.Address
-			break
+			oldaddrs = append(oldaddrs, addr.Address)
 		}
 	}
 	for _, addr := range newAddrs {
 		if addr.Type == "InternalIP" {
-			newaddr = addr.Address
-			break
+			newaddrs = append(newaddrs, addr.Address)
 		}
 	}
-	if oldaddr != newaddr {
+	if !reflect.DeepEqual(oldaddrs, newaddrs) {
 		return true
 	}
-	if oldNode.Spec.PodCIDR != newNode.Spec.PodCIDR {
+	if oldNode.Spec.PodCIDR != newNode.Spec.PodCIDR || !reflect.DeepEqual(oldNode.Spec.PodCIDRs, newNode.Spec.PodCIDRs) {
 		return true
 	}
 
@@ -143,10 +147,16 @@ func isIngressUpdated(oldIngress, newIngress *networkingv1.Ingress) bool {
 	oldAnnotation := oldIngress.DeepCopy().Annotations
 	delete(oldAnnotation, lib.VSAnnotation)
 	delete(oldAnnotation, lib.ControllerAnnotation)
+	delete(oldAnnotation, lib.SyncedConditionType)
 	newAnnotation := newIngress.DeepCopy().Annotations
 	delete(newAnnotation, lib.VSAnnotation)
 	delete(newAnnotation, lib.ControllerAnnotation)
+	delete(newAnnotation, lib.SyncedConditionType)
 
+	// An Ingress with only the annotations filled in by AKO is the same as one without annotations.
+	if len(oldAnnotation) == 0 && len(newAnnotation) == 0 {
+		oldAnnotation, newAnnotation = nil, nil
+	}
 	oldAnnotationHash := utils.Hash(utils.Stringify(oldAnnotation))
 	newAnnotationHash := utils.Hash(utils.Stringify(newAnnotation))
 
@@ -375,6 +385,29 @@ func AddNamespaceEventHandler(numWorkers uint32, c *AviController) cache.Resourc
 	return namespaceEventHandler
 }
 
+// AddNamespaceTenantEventHandler shuts down AKO when the Avi tenant of a namespace changes. On reboot the objects of
+// the namespace are created in the new tenant, and the ones in the old tenant are deleted as stale objects.
+func AddNamespaceTenantEventHandler(c *AviController) cache.ResourceEventHandler {
+	namespaceTenantEventHandler := cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			nsOld := old.(*corev1.Namespace)
+			nsCur := cur.(*corev1.Namespace)
+			oldTenant := lib.GetNamespaceTenant(nsOld)
+			curTenant := lib.GetNamespaceTenant(nsCur)
+			if oldTenant == curTenant {
+				return
+			}
+			utils.AviLog.Warnf("Avi tenant of namespace %s changed from %s to %s, shutting down AKO", nsCur.GetName(), oldTenant, curTenant)
+			lib.AKOControlConfig().EventRecorder().Eventf(nsCur, corev1.EventTypeNormal, lib.TenantChanged, "Avi tenant changed from %s to %s", oldTenant, curTenant)
+			lib.ShutdownApi()
+		},
+	}
+	return namespaceTenantEventHandler
+}
+
 func AddRouteEventHandler(numWorkers uint32, c *AviController) cache.ResourceEventHandler {
 	routeEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -513,6 +546,202 @@ func AddPodEventHandler(numWorkers uint32, c *AviController) cache.ResourceEvent
 	return podEventHandler
 }
 
+// enqueuePodServiceEndpoints syncs the Endpoints of the Services selecting the Pod, so that the pools of the
+// Services are rebuilt.
+func enqueuePodServiceEndpoints(c *AviController, pod *corev1.Pod, event string, numWorkers uint32) {
+	namespace := pod.Namespace
+	if !utils.IsServiceNSValid(namespace) {
+		return
+	}
+	svcs, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("Unable to list the Services in namespace %s: %v", namespace, err)
+		return
+	}
+	for _, svc := range svcs {
+		if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		key := utils.Endpoints + "/" + utils.ObjKey(svc)
+		bkt := utils.Bkt(namespace, numWorkers)
+		c.workqueue[bkt].AddRateLimited(key)
+		utils.AviLog.Debugf("key: %s, msg: Pod %s %s", key, pod.Name, event)
+	}
+}
+
+// getPodFromObj returns the Pod of a delete event, which can be wrapped in a tombstone.
+func getPodFromObj(obj interface{}) (*corev1.Pod, bool) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+			return nil, false
+		}
+		pod, ok = tombstone.Obj.(*corev1.Pod)
+		if !ok {
+			utils.AviLog.Errorf("Tombstone contained object that is not an Pod: %#v", obj)
+			return nil, false
+		}
+	}
+	return pod, true
+}
+
+// AddPodDrainEventHandler syncs the Endpoints of the Services selecting a Pod, when the Pod starts terminating or is
+// deleted, so that its server is drained from the pools before it is removed.
+func AddPodDrainEventHandler(numWorkers uint32, c *AviController) cache.ResourceEventHandler {
+	podDrainEventHandler := cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			pod, ok := getPodFromObj(obj)
+			if !ok {
+				return
+			}
+			enqueuePodServiceEndpoints(c, pod, "DELETE", numWorkers)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldPod := old.(*corev1.Pod)
+			newPod := cur.(*corev1.Pod)
+			if oldPod.DeletionTimestamp == nil && newPod.DeletionTimestamp != nil {
+				enqueuePodServiceEndpoints(c, newPod, "TERMINATING", numWorkers)
+			}
+		},
+	}
+	return podDrainEventHandler
+}
+
+// AddPodProbeEventHandler syncs the Endpoints of the Services selecting a Pod, when a Pod with probes is added or
+// deleted, or the probes of the Pod change, so that the health monitors generated from the probes are updated.
+func AddPodProbeEventHandler(numWorkers uint32, c *AviController) cache.ResourceEventHandler {
+	hasProbes := func(pod *corev1.Pod) bool {
+		for _, container := range pod.Spec.Containers {
+			if container.ReadinessProbe != nil || container.LivenessProbe != nil {
+				return true
+			}
+		}
+		return false
+	}
+	podProbes := func(pod *corev1.Pod) []*corev1.Probe {
+		var probes []*corev1.Probe
+		for _, container := range pod.Spec.Containers {
+			probes = append(probes, container.ReadinessProbe, container.LivenessProbe)
+		}
+		return probes
+	}
+	podProbeEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			pod := obj.(*corev1.Pod)
+			if hasProbes(pod) {
+				enqueuePodServiceEndpoints(c, pod, "ADD", numWorkers)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			pod, ok := getPodFromObj(obj)
+			if !ok {
+				return
+			}
+			if hasProbes(pod) {
+				enqueuePodServiceEndpoints(c, pod, "DELETE", numWorkers)
+			}
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldPod := old.(*corev1.Pod)
+			newPod := cur.(*corev1.Pod)
+			if !reflect.DeepEqual(podProbes(oldPod), podProbes(newPod)) || !reflect.DeepEqual(oldPod.Labels, newPod.Labels) {
+				enqueuePodServiceEndpoints(c, oldPod, "PROBE UPDATE", numWorkers)
+				enqueuePodServiceEndpoints(c, newPod, "PROBE UPDATE", numWorkers)
+			}
+		},
+	}
+	return podProbeEventHandler
+}
+
+// AddEndpointSliceEventHandler syncs the Endpoints key of the Service owning an EndpointSlice, on the changes to the
+// endpoints or the ports of the slice. The servers of the pools are populated from all the slices of the Service.
+func AddEndpointSliceEventHandler(numWorkers uint32, c *AviController) cache.ResourceEventHandler {
+	getEndpointsKey := func(epSlice *discovery.EndpointSlice) (string, bool) {
+		svcName, ok := epSlice.Labels[discovery.LabelServiceName]
+		if !ok || svcName == "" {
+			return "", false
+		}
+		return utils.Endpoints + "/" + epSlice.Namespace + "/" + svcName, true
+	}
+	epSliceEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			epSlice := obj.(*discovery.EndpointSlice)
+			key, ok := getEndpointsKey(epSlice)
+			if !ok {
+				return
+			}
+			bkt := utils.Bkt(epSlice.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD EndpointSlice %s", key, epSlice.Name)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			epSlice, ok := obj.(*discovery.EndpointSlice)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				epSlice, ok = tombstone.Obj.(*discovery.EndpointSlice)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not an EndpointSlice: %#v", obj)
+					return
+				}
+			}
+			key, ok := getEndpointsKey(epSlice)
+			if !ok {
+				return
+			}
+			bkt := utils.Bkt(epSlice.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE EndpointSlice %s", key, epSlice.Name)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldSlice := old.(*discovery.EndpointSlice)
+			curSlice := cur.(*discovery.EndpointSlice)
+			if oldSlice.AddressType == curSlice.AddressType &&
+				reflect.DeepEqual(oldSlice.Endpoints, curSlice.Endpoints) &&
+				reflect.DeepEqual(oldSlice.Ports, curSlice.Ports) {
+				return
+			}
+			key, ok := getEndpointsKey(curSlice)
+			if !ok {
+				return
+			}
+			bkt := utils.Bkt(curSlice.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: UPDATE EndpointSlice %s", key, curSlice.Name)
+		},
+	}
+	return epSliceEventHandler
+}
+
 func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 	mcpQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
 	c.workqueue = mcpQueue.Workqueue
@@ -682,7 +911,12 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		},
 	}
 
-	c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	if c.informers.EpSlicesInformer != nil {
+		epSliceEventHandler := AddEndpointSliceEventHandler(numWorkers, c)
+		c.informers.EpSlicesInformer.Informer().AddEventHandler(epSliceEventHandler)
+	} else {
+		c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	}
 
 	c.informers.ServiceInformer.Informer().AddEventHandler(svcEventHandler)
 
@@ -831,6 +1065,10 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		c.SetupSvcApiEventHandlers(numWorkers)
 	}
 
+	if lib.UseGatewayAPI() {
+		c.SetupGatewayAPIEventHandlers(numWorkers)
+	}
+
 	ingressEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			if c.DisableSync {
@@ -1055,7 +1293,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		c.informers.RouteInformer.Informer().AddEventHandler(routeEventHandler)
 	}
 
-	// Add CRD handlers HostRule/HTTPRule/AviInfraSettings
+	// Add CRD handlers HostRule/HTTPRule/AviInfraSettings/L4Rule
 	c.SetupAKOCRDEventHandlers(numWorkers)
 
 	if lib.IsIstioEnabled() {
@@ -1076,9 +1314,22 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		c.informers.NSInformer.Informer().AddEventHandler(namespaceEventHandler)
 	}
 
+	if lib.IsTenantsPerNamespace() && c.informers.NSInformer != nil {
+		utils.AviLog.Debug("Adding namespace tenant event handler")
+		namespaceTenantEventHandler := AddNamespaceTenantEventHandler(c)
+		c.informers.NSInformer.Informer().AddEventHandler(namespaceTenantEventHandler)
+	}
+
 	if lib.GetServiceType() == lib.NodePortLocal {
 		podEventHandler := AddPodEventHandler(numWorkers, c)
 		c.informers.PodInformer.Informer().AddEventHandler(podEventHandler)
+	} else if lib.GetPoolDrainTimeout() > 0 && c.informers.PodInformer != nil {
+		podDrainEventHandler := AddPodDrainEventHandler(numWorkers, c)
+		c.informers.PodInformer.Informer().AddEventHandler(podDrainEventHandler)
+	}
+	if lib.IsProbeHealthMonitorEnabled() && c.informers.PodInformer != nil {
+		podProbeEventHandler := AddPodProbeEventHandler(numWorkers, c)
+		c.informers.PodInformer.Informer().AddEventHandler(podProbeEventHandler)
 	}
 }
 
@@ -1107,19 +1358,27 @@ func checkAviSecretUpdateAndShutdown(secret *corev1.Secret) bool {
 
 func (c *AviController) Start(stopCh <-chan struct{}) {
 	go c.informers.ServiceInformer.Informer().Run(stopCh)
-	go c.informers.EpInformer.Informer().Run(stopCh)
 
 	informersList := []cache.InformerSynced{
-		c.informers.EpInformer.Informer().HasSynced,
 		c.informers.ServiceInformer.Informer().HasSynced,
 	}
+	if c.informers.EpSlicesInformer != nil {
+		go c.informers.EpSlicesInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpSlicesInformer.Informer().HasSynced)
+	} else {
+		go c.informers.EpInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpInformer.Informer().HasSynced)
+	}
 
 	if !lib.AviSecretInitialized {
 		go c.informers.SecretInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.SecretInformer.Informer().HasSynced)
 	}
 
-	if lib.GetServiceType() == lib.NodePortLocal {
+	// The Pods are required for NodePortLocal, the Istio DestinationRule subsets, the draining of the terminating Pods,
+	// and the health monitors generated from the probes of the Pods.
+	if c.informers.PodInformer != nil && (lib.GetServiceType() == lib.NodePortLocal || lib.IsIstioEnabled() ||
+		lib.GetPoolDrainTimeout() > 0 || lib.IsProbeHealthMonitorEnabled()) {
 		go c.informers.PodInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.PodInformer.Informer().HasSynced)
 	}
@@ -1145,6 +1404,12 @@ func (c *AviController) Start(stopCh <-chan struct{}) {
 			go lib.AKOControlConfig().SvcAPIInformers().GatewayInformer.Informer().Run(stopCh)
 			informersList = append(informersList, lib.AKOControlConfig().SvcAPIInformers().GatewayInformer.Informer().HasSynced)
 		}
+		if lib.UseGatewayAPI() {
+			for _, gwApiInformer := range gatewayAPIInformers() {
+				go gwApiInformer.Run(stopCh)
+				informersList = append(informersList, gwApiInformer.HasSynced)
+			}
+		}
 		if c.informers.IngressInformer != nil {
 			go c.informers.IngressInformer.Informer().Run(stopCh)
 			informersList = append(informersList, c.informers.IngressInformer.Informer().HasSynced)
@@ -1182,6 +1447,11 @@ func (c *AviController) Start(stopCh <-chan struct{}) {
 			go lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Informer().Run(stopCh)
 			informersList = append(informersList, lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Informer().HasSynced)
 		}
+
+		if lib.AKOControlConfig().L4RuleEnabled() {
+			go lib.AKOControlConfig().CRDInformers().L4RuleInformer.Informer().Run(stopCh)
+			informersList = append(informersList, lib.AKOControlConfig().CRDInformers().L4RuleInformer.Informer().HasSynced)
+		}
 		if lib.IsIstioEnabled() {
 			go lib.AKOControlConfig().IstioCRDInformers().VirtualServiceInformer.Informer().Run(stopCh)
 			informersList = append(informersList, lib.AKOControlConfig().IstioCRDInformers().VirtualServiceInformer.Informer().HasSynced)
@@ -17,8 +17,11 @@ package k8s
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"reflect"
 	"regexp"
+	"strings"
 	"time"
 
 	istiov1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
@@ -46,11 +49,13 @@ func NewCRDInformers(cs akocrd.Interface) {
 	hostRuleInformer := akoInformerFactory.Ako().V1alpha1().HostRules()
 	httpRuleInformer := akoInformerFactory.Ako().V1alpha1().HTTPRules()
 	aviSettingsInformer := akoInformerFactory.Ako().V1alpha1().AviInfraSettings()
+	l4RuleInformer := akoInformerFactory.Ako().V1alpha1().L4Rules()
 
 	lib.AKOControlConfig().SetCRDInformers(&lib.AKOCrdInformers{
 		HostRuleInformer:        hostRuleInformer,
 		HTTPRuleInformer:        httpRuleInformer,
 		AviInfraSettingInformer: aviSettingsInformer,
+		L4RuleInformer:          l4RuleInformer,
 	})
 }
 
@@ -84,6 +89,17 @@ func isHTTPRuleUpdated(oldHTTPRule, newHTTPRule *akov1alpha1.HTTPRule) bool {
 	return false
 }
 
+func isL4RuleUpdated(oldL4Rule, newL4Rule *akov1alpha1.L4Rule) bool {
+	if oldL4Rule.ResourceVersion == newL4Rule.ResourceVersion {
+		return false
+	}
+
+	oldSpecHash := utils.Hash(utils.Stringify(oldL4Rule.Spec))
+	newSpecHash := utils.Hash(utils.Stringify(newL4Rule.Spec))
+
+	return oldSpecHash != newSpecHash
+}
+
 func isAviInfraUpdated(oldAviInfra, newAviInfra *akov1alpha1.AviInfraSetting) bool {
 	if oldAviInfra.ResourceVersion == newAviInfra.ResourceVersion {
 		return false
@@ -123,6 +139,9 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 				utils.AviLog.Debugf("key: %s, msg: ADD", key)
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				if hostrule.Spec.VirtualHost.Gslb.Service != nil {
+					TriggerGSLBServiceSync()
+				}
 			},
 			UpdateFunc: func(old, new interface{}) {
 				if c.DisableSync {
@@ -130,6 +149,9 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 				}
 				oldObj := old.(*akov1alpha1.HostRule)
 				hostrule := new.(*akov1alpha1.HostRule)
+				if isGSLBHostRuleUpdated(oldObj, hostrule) {
+					TriggerGSLBServiceSync()
+				}
 				if !reflect.DeepEqual(oldObj.Spec, hostrule.Spec) {
 					namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(hostrule))
 					key := lib.HostRule + "/" + utils.ObjKey(hostrule)
@@ -164,6 +186,9 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 				objects.SharedResourceVerInstanceLister().Delete(key)
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				if hostrule.Spec.VirtualHost.Gslb.Service != nil {
+					TriggerGSLBServiceSync()
+				}
 			},
 		}
 
@@ -297,6 +322,69 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 
 		informer.AviInfraSettingInformer.Informer().AddEventHandler(aviInfraEventHandler)
 	}
+
+	if lib.AKOControlConfig().L4RuleEnabled() {
+		l4RuleEventHandler := cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if c.DisableSync {
+					return
+				}
+				l4Rule := obj.(*akov1alpha1.L4Rule)
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(l4Rule))
+				key := lib.L4Rule + "/" + utils.ObjKey(l4Rule)
+				if err := validateL4RuleObj(key, l4Rule); err != nil {
+					utils.AviLog.Warnf("key: %s, msg: Error retrieved during validation of L4Rule: %v", key, err)
+				}
+				utils.AviLog.Debugf("key: %s, msg: ADD", key)
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+			},
+			UpdateFunc: func(old, new interface{}) {
+				if c.DisableSync {
+					return
+				}
+				oldObj := old.(*akov1alpha1.L4Rule)
+				l4Rule := new.(*akov1alpha1.L4Rule)
+				if isL4RuleUpdated(oldObj, l4Rule) {
+					namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(l4Rule))
+					key := lib.L4Rule + "/" + utils.ObjKey(l4Rule)
+					if err := validateL4RuleObj(key, l4Rule); err != nil {
+						utils.AviLog.Warnf("key: %s, msg: Error retrieved during validation of L4Rule: %v", key, err)
+					}
+					utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+					bkt := utils.Bkt(namespace, numWorkers)
+					c.workqueue[bkt].AddRateLimited(key)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if c.DisableSync {
+					return
+				}
+				l4Rule, ok := obj.(*akov1alpha1.L4Rule)
+				if !ok {
+					tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+					if !ok {
+						utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+						return
+					}
+					l4Rule, ok = tombstone.Obj.(*akov1alpha1.L4Rule)
+					if !ok {
+						utils.AviLog.Errorf("Tombstone contained object that is not an L4Rule: %#v", obj)
+						return
+					}
+				}
+				key := lib.L4Rule + "/" + utils.ObjKey(l4Rule)
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(l4Rule))
+				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+				objects.SharedResourceVerInstanceLister().Delete(key)
+				// no need to validate for delete handler
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+			},
+		}
+
+		informer.L4RuleInformer.Informer().AddEventHandler(l4RuleEventHandler)
+	}
 	return
 }
 
@@ -660,13 +748,35 @@ func (c *AviController) SetupServiceImportEventHandlers(numWorkers uint32) {
 // validateHostRuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
+	refData, err := checkHostRuleSpec(hostrule)
+	if err != nil {
+		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
+		return err
+	}
+
+	if err := checkRefsOnController(key, refData); err != nil {
+		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
+		return err
+	}
+
+	// No need to update status of hostrule object as accepted since it was accepted before.
+	if hostrule.Status.Status == lib.StatusAccepted {
+		return nil
+	}
+
+	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
+	return nil
+}
+
+// checkHostRuleSpec validates the hostrule spec, and returns the Avi object references in it, which are to be checked
+// on the controller.
+func checkHostRuleSpec(hostrule *akov1alpha1.HostRule) (map[string]string, error) {
 	var err error
 	fqdn := hostrule.Spec.VirtualHost.Fqdn
 	foundHost, foundHR := objects.SharedCRDLister().GetFQDNToHostruleMapping(fqdn)
 	if foundHost && foundHR != hostrule.Namespace+"/"+hostrule.Name {
 		err = fmt.Errorf("duplicate fqdn %s found in %s", fqdn, foundHR)
-		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-		return err
+		return nil, err
 	}
 
 	// If it is not a Shared VS but TCP Settings are provided, then we reject it since these
@@ -674,27 +784,29 @@ func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
 	// TODO: move to translator?
 	// if !strings.Contains(fqdn, lib.ShardVSSubstring) && hostrule.Spec.VirtualHost.TCPSettings != nil {
 	// 	err = fmt.Errorf("Hostrule tcpSettings with fqdn %s cannot be applied to child Virtualservices", fqdn)
-	// 	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-	// 	return err
+	// 	return nil, err
 	// }
 
 	if hostrule.Spec.VirtualHost.TCPSettings != nil && hostrule.Spec.VirtualHost.TCPSettings.LoadBalancerIP != "" {
 		re := regexp.MustCompile(lib.IPRegex)
 		if !re.MatchString(hostrule.Spec.VirtualHost.TCPSettings.LoadBalancerIP) {
 			err = fmt.Errorf("loadBalancerIP %s is not a valid IP", hostrule.Spec.VirtualHost.TCPSettings.LoadBalancerIP)
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
+			return nil, err
 		}
 	}
 
 	if hostrule.Spec.VirtualHost.Gslb.Fqdn != "" {
 		if fqdn == hostrule.Spec.VirtualHost.Gslb.Fqdn {
 			err = fmt.Errorf("GSLB FQDN and local FQDN are same")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
+			return nil, err
 		}
 	}
 
+	if hostrule.Spec.VirtualHost.Gslb.Service != nil && hostrule.Spec.VirtualHost.Gslb.Fqdn == "" {
+		err = fmt.Errorf("GSLB FQDN is required to create the GSLB service")
+		return nil, err
+	}
+
 	if hostrule.Spec.VirtualHost.TCPSettings != nil {
 		sslEnabled := false
 		for _, listener := range hostrule.Spec.VirtualHost.TCPSettings.Listeners {
@@ -705,35 +817,30 @@ func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
 		}
 		if !sslEnabled {
 			err = fmt.Errorf("Hosting parent virtualservice must have SSL enabled")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
+			return nil, err
 		}
 	}
 
 	if hostrule.Spec.VirtualHost.Aliases != nil {
 		if hostrule.Spec.VirtualHost.FqdnType != akov1alpha1.Exact {
 			err = fmt.Errorf("Aliases is supported only when FQDN type is set as Exact")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
+			return nil, err
 		}
 
 		if utils.HasElem(hostrule.Spec.VirtualHost.Aliases, fqdn) {
 			err = fmt.Errorf("Duplicate entry found. Aliases field has same entry as the FQDN field")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
+			return nil, err
 		}
 
 		if utils.ContainsDuplicate(hostrule.Spec.VirtualHost.Aliases) {
 			err = fmt.Errorf("Aliases must be unique")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
+			return nil, err
 		}
 
 		if hostrule.Spec.VirtualHost.Gslb.Fqdn != "" &&
 			utils.HasElem(hostrule.Spec.VirtualHost.Aliases, hostrule.Spec.VirtualHost.Gslb.Fqdn) {
 			err = fmt.Errorf("Aliases must not contain GSLB FQDN")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
+			return nil, err
 		}
 
 		for cachedFQDN, cachedAliases := range objects.SharedCRDLister().GetAllFQDNToAliasesMapping() {
@@ -744,13 +851,30 @@ func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
 			for _, alias := range hostrule.Spec.VirtualHost.Aliases {
 				if utils.HasElem(aliases, alias) {
 					err = fmt.Errorf("%s is already in use by hostrule %s", alias, cachedFQDN)
-					status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-					return err
+					return nil, err
 				}
 			}
 		}
 	}
 
+	if hostrule.Spec.VirtualHost.Security != nil {
+		if err = validateHostRuleSecurity(hostrule.Spec.VirtualHost.Security); err != nil {
+			return nil, err
+		}
+	}
+
+	if hostrule.Spec.VirtualHost.TLS.OCSPStapling != nil {
+		if err = validateHostRuleOCSPStapling(hostrule.Spec.VirtualHost.TLS); err != nil {
+			return nil, err
+		}
+	}
+
+	if hostrule.Spec.VirtualHost.TLS.ClientAuth != nil {
+		if err = validateHostRuleClientAuth(hostrule); err != nil {
+			return nil, err
+		}
+	}
+
 	refData := map[string]string{
 		hostrule.Spec.VirtualHost.WAFPolicy:          "WafPolicy",
 		hostrule.Spec.VirtualHost.ApplicationProfile: "AppProfile",
@@ -765,8 +889,7 @@ func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
 	if hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.Type == akov1alpha1.HostRuleSecretTypeSecretReference {
 		_, err := utils.GetInformers().SecretInformer.Lister().Secrets(hostrule.Namespace).Get(hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.Name)
 		if err != nil {
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
+			return nil, err
 		}
 	}
 	if hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.AlternateCertificate.Type == akov1alpha1.HostRuleSecretTypeAviReference {
@@ -776,8 +899,7 @@ func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
 	if hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.AlternateCertificate.Type == akov1alpha1.HostRuleSecretTypeSecretReference {
 		_, err := utils.GetInformers().SecretInformer.Lister().Secrets(hostrule.Namespace).Get(hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.AlternateCertificate.Name)
 		if err != nil {
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-			return err
+			return nil, err
 		}
 	}
 
@@ -789,17 +911,116 @@ func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
 		refData[script] = "VsDatascript"
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-		return err
+	if hostrule.Spec.VirtualHost.Gslb.Service != nil {
+		for _, healthMonitor := range hostrule.Spec.VirtualHost.Gslb.Service.HealthMonitors {
+			refData[healthMonitor] = "HealthMonitor"
+		}
 	}
 
-	// No need to update status of hostrule object as accepted since it was accepted before.
-	if hostrule.Status.Status == lib.StatusAccepted {
-		return nil
+	if hostrule.Spec.VirtualHost.TLS.ClientAuth != nil && hostrule.Spec.VirtualHost.TLS.ClientAuth.PKIProfile != "" {
+		refData[hostrule.Spec.VirtualHost.TLS.ClientAuth.PKIProfile] = "PKIProfile"
 	}
 
-	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
+	if hostrule.Spec.VirtualHost.Security != nil {
+		for _, ipGroup := range hostrule.Spec.VirtualHost.Security.AllowedIPGroups {
+			refData[ipGroup] = "IPAddrGroup"
+		}
+		for _, ipGroup := range hostrule.Spec.VirtualHost.Security.DeniedIPGroups {
+			refData[ipGroup] = "IPAddrGroup"
+		}
+	}
+	return refData, nil
+}
+
+// validateHostRuleSecurity validates the rate limits and the CIDRs of the hostrule security settings.
+func validateHostRuleSecurity(security *akov1alpha1.HostRuleSecurity) error {
+	if security.RateLimits != nil {
+		rateLimits := map[string]*akov1alpha1.HostRuleRateLimit{
+			"connections": security.RateLimits.Connections,
+			"requests":    security.RateLimits.Requests,
+		}
+		for name, rateLimit := range rateLimits {
+			if rateLimit == nil {
+				continue
+			}
+			if rateLimit.Count <= 0 {
+				return fmt.Errorf("%s rate limit count must be greater than 0", name)
+			}
+			if rateLimit.Period < 0 || rateLimit.Burst < 0 {
+				return fmt.Errorf("%s rate limit period and burst must not be negative", name)
+			}
+		}
+		if security.RateLimits.Connections != nil && security.RateLimits.Connections.PerClientIP {
+			return fmt.Errorf("perClientIP is supported only for the requests rate limit")
+		}
+	}
+
+	for _, cidr := range append(append([]string{}, security.AllowedCIDRs...), security.DeniedCIDRs...) {
+		if _, _, err := lib.ParseCIDROrIP(cidr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateHostRuleOCSPStapling validates the OCSP stapling settings of the hostrule, which apply to the certificates
+// uploaded by AKO from the Secrets and the Routes only.
+func validateHostRuleOCSPStapling(tls akov1alpha1.HostRuleTLS) error {
+	if (tls.SSLKeyCertificate.Name != "" && tls.SSLKeyCertificate.Type == akov1alpha1.HostRuleSecretTypeAviReference) ||
+		(tls.SSLKeyCertificate.AlternateCertificate.Name != "" &&
+			tls.SSLKeyCertificate.AlternateCertificate.Type == akov1alpha1.HostRuleSecretTypeAviReference) {
+		return fmt.Errorf("ocspStapling cannot be used with sslKeyCertificate of type %s", akov1alpha1.HostRuleSecretTypeAviReference)
+	}
+	ocspStapling := tls.OCSPStapling
+	if ocspStapling.URLAction != "" && ocspStapling.URLAction != lib.OCSPResponderURLFailover &&
+		ocspStapling.URLAction != lib.OCSPResponderURLOverride {
+		return fmt.Errorf("ocspStapling urlAction must be one of %s, %s", lib.OCSPResponderURLFailover, lib.OCSPResponderURLOverride)
+	}
+	if ocspStapling.URLAction == lib.OCSPResponderURLOverride && len(ocspStapling.ResponderURLs) == 0 {
+		return fmt.Errorf("ocspStapling responderURLs are required with urlAction %s", lib.OCSPResponderURLOverride)
+	}
+	if ocspStapling.Frequency != 0 && ocspStapling.Frequency < 60 {
+		return fmt.Errorf("ocspStapling frequency %d must be at least 60 seconds", ocspStapling.Frequency)
+	}
+	for _, responderURL := range ocspStapling.ResponderURLs {
+		if u, err := url.Parse(responderURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("ocspStapling responderURL %s is not a valid http URL", responderURL)
+		}
+	}
+	return nil
+}
+
+// validateHostRuleClientAuth validates the client certificate settings of the hostrule. The CA certificates are
+// either read from a Secret in the namespace of the hostrule, or referred from a PKI profile on the controller.
+func validateHostRuleClientAuth(hostrule *akov1alpha1.HostRule) error {
+	clientAuth := hostrule.Spec.VirtualHost.TLS.ClientAuth
+	if hostrule.Spec.VirtualHost.ApplicationProfile != "" {
+		return fmt.Errorf("clientAuth cannot be used with applicationProfile")
+	}
+	if (clientAuth.CASecret == "") == (clientAuth.PKIProfile == "") {
+		return fmt.Errorf("clientAuth requires exactly one of caSecret or pkiProfile")
+	}
+	if clientAuth.Mode != "" && clientAuth.Mode != akov1alpha1.ClientAuthModeRequire &&
+		clientAuth.Mode != akov1alpha1.ClientAuthModeRequest {
+		return fmt.Errorf("clientAuth mode must be one of %s, %s", akov1alpha1.ClientAuthModeRequire, akov1alpha1.ClientAuthModeRequest)
+	}
+	for _, header := range clientAuth.Headers {
+		if header.Name == "" {
+			return fmt.Errorf("clientAuth header name is required")
+		}
+		if _, ok := lib.ClientCertHeaderVars[string(header.Value)]; !ok {
+			return fmt.Errorf("clientAuth header %s has an invalid value %s", header.Name, header.Value)
+		}
+	}
+	if clientAuth.CASecret != "" {
+		secret, err := utils.GetInformers().SecretInformer.Lister().Secrets(hostrule.Namespace).Get(clientAuth.CASecret)
+		if err != nil {
+			return err
+		}
+		if len(secret.Data[lib.ClientAuthCACertKey]) == 0 {
+			return fmt.Errorf("clientAuth caSecret %s does not have %s", clientAuth.CASecret, lib.ClientAuthCACertKey)
+		}
+	}
 	return nil
 }
 
@@ -875,10 +1096,18 @@ var refModelMap = map[string]string{
 	"PKIProfile":             "pkiprofile",
 	"ServiceEngineGroup":     "serviceenginegroup",
 	"Network":                "network",
+	"IPAddrGroup":            "ipaddrgroup",
+	"L4AppProfile":           "applicationprofile",
+	"NetworkProfile":         "networkprofile",
 }
 
 // checkRefOnController checks whether a provided ref on the controller
 func checkRefOnController(key, refKey, refValue string) error {
+	// There is no Avi controller to check the references against in the offline sync, they are assumed to be valid.
+	if controllerInstance != nil && controllerInstance.offlineSync {
+		utils.AviLog.Debugf("key: %s, msg: skipping the check of %s %s on the controller", key, refModelMap[refKey], refValue)
+		return nil
+	}
 	// assign the last avi client for ref checks
 	aviClientLen := lib.GetshardSize()
 	clients := avicache.SharedAVIClients()
@@ -936,6 +1165,12 @@ func checkRefOnController(key, refKey, refValue string) error {
 			return fmt.Errorf("%s \"%s\" found on controller is invalid, must be of type: %s",
 				refModelMap[refKey], refValue, lib.AllowedApplicationProfile)
 		}
+	case "L4AppProfile":
+		if appProfType, ok := item["type"].(string); ok && appProfType != lib.AllowedL4ApplicationProfile {
+			utils.AviLog.Warnf("key: %s, msg: applicationProfile: %s must be of type %s", key, refValue, lib.AllowedL4ApplicationProfile)
+			return fmt.Errorf("%s \"%s\" found on controller is invalid, must be of type: %s",
+				refModelMap[refKey], refValue, lib.AllowedL4ApplicationProfile)
+		}
 	case "ServiceEngineGroup":
 		if seGroupLabels, ok := item["labels"].([]map[string]string); ok {
 			if len(seGroupLabels) == 0 {
@@ -963,8 +1198,49 @@ func checkRefOnController(key, refKey, refValue string) error {
 // validateHTTPRuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func validateHTTPRuleObj(key string, httprule *akov1alpha1.HTTPRule) error {
+	refData, err := checkHTTPRuleSpec(httprule)
+	if err != nil {
+		status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+			Status: lib.StatusRejected,
+			Error:  err.Error(),
+		})
+		return err
+	}
+
+	if err := checkRefsOnController(key, refData); err != nil {
+		status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+			Status: lib.StatusRejected,
+			Error:  err.Error(),
+		})
+		return err
+	}
+
+	// No need to update status of httprule object as accepted since it was accepted before,
+	// unless the actions configured on the paths have changed.
+	if httprule.Status.Status == lib.StatusAccepted &&
+		reflect.DeepEqual(httprule.Status.PathActions, status.GetHTTPRulePathActions(httprule)) {
+		return nil
+	}
+
+	status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+		Status: lib.StatusAccepted,
+		Error:  "",
+	})
+	return nil
+}
+
+// checkHTTPRuleSpec validates the paths of the httprule, and returns the Avi object references in them, which are to be
+// checked on the controller.
+func checkHTTPRuleSpec(httprule *akov1alpha1.HTTPRule) (map[string]string, error) {
 	refData := make(map[string]string)
 	for _, path := range httprule.Spec.Paths {
+		if err := validateHTTPRulePathActions(path); err != nil {
+			return nil, err
+		}
+		if err := validateHTTPRulePathBackends(path); err != nil {
+			return nil, err
+		}
+
 		refData[path.TLS.SSLProfile] = "SslProfile"
 		refData[path.ApplicationPersistence] = "ApplicationPersistence"
 		if path.TLS.PKIProfile != "" {
@@ -975,33 +1251,171 @@ func validateHTTPRuleObj(key string, httprule *akov1alpha1.HTTPRule) error {
 			refData[hm] = "HealthMonitor"
 		}
 	}
+	return refData, nil
+}
+
+// validateHTTPRulePathActions validates the header, rewrite and redirect actions of a target path.
+func validateHTTPRulePathActions(path akov1alpha1.HTTPRulePaths) error {
+	headerActions := append(append([]akov1alpha1.HTTPRuleHeaderAction{}, path.RequestHeaders...), path.ResponseHeaders...)
+	for _, header := range headerActions {
+		if header.Name == "" {
+			return fmt.Errorf("header name is required for header actions on path %s", path.Target)
+		}
+		switch header.Action {
+		case akov1alpha1.HTTPRuleHeaderActionAdd, akov1alpha1.HTTPRuleHeaderActionReplace:
+			if header.Value == "" {
+				return fmt.Errorf("header value is required for %s action of header %s on path %s", header.Action, header.Name, path.Target)
+			}
+		case akov1alpha1.HTTPRuleHeaderActionRemove:
+			if header.Value != "" {
+				return fmt.Errorf("header value cannot be set for Remove action of header %s on path %s", header.Name, path.Target)
+			}
+		default:
+			return fmt.Errorf("invalid action %s for header %s on path %s", header.Action, header.Name, path.Target)
+		}
+	}
+
+	if path.RewritePrefix != "" && !strings.HasPrefix(path.RewritePrefix, "/") {
+		return fmt.Errorf("rewritePrefix %s on path %s must begin with /", path.RewritePrefix, path.Target)
+	}
+
+	if path.Redirect != nil {
+		if path.RewritePrefix != "" {
+			return fmt.Errorf("rewritePrefix and redirect cannot be set together on path %s", path.Target)
+		}
+		switch path.Redirect.StatusCode {
+		case 0, 301, 302, 307:
+		default:
+			return fmt.Errorf("redirect statusCode %d on path %s is not one of 301, 302 and 307", path.Redirect.StatusCode, path.Target)
+		}
+		if path.Redirect.Protocol != "" && path.Redirect.Protocol != "HTTP" && path.Redirect.Protocol != "HTTPS" {
+			return fmt.Errorf("redirect protocol %s on path %s is not one of HTTP and HTTPS", path.Redirect.Protocol, path.Target)
+		}
+		if path.Redirect.Path != "" && !strings.HasPrefix(path.Redirect.Path, "/") {
+			return fmt.Errorf("redirect path %s on path %s must begin with /", path.Redirect.Path, path.Target)
+		}
+	}
+	return nil
+}
+
+// validateHTTPRulePathBackends validates the additional backends, along with their match conditions, of a target path.
+func validateHTTPRulePathBackends(path akov1alpha1.HTTPRulePaths) error {
+	if len(path.Backends) > 0 && path.Redirect != nil {
+		return fmt.Errorf("backends and redirect cannot be set together on path %s", path.Target)
+	}
+	services := make(map[string]bool)
+	for _, backend := range path.Backends {
+		if backend.ServiceName == "" {
+			return fmt.Errorf("serviceName is required for backends on path %s", path.Target)
+		}
+		if services[backend.ServiceName] {
+			return fmt.Errorf("service %s is listed more than once in backends on path %s", backend.ServiceName, path.Target)
+		}
+		services[backend.ServiceName] = true
+		if backend.Weight != nil && (*backend.Weight < 0 || *backend.Weight > 256) {
+			return fmt.Errorf("weight %d of backend %s on path %s is not in the range 0-256", *backend.Weight, backend.ServiceName, path.Target)
+		}
+		if backend.Match == nil {
+			continue
+		}
+		if len(backend.Match.Headers) == 0 && len(backend.Match.Cookies) == 0 {
+			return fmt.Errorf("match of backend %s on path %s requires headers or cookies", backend.ServiceName, path.Target)
+		}
+		if len(backend.Match.Cookies) > 1 {
+			return fmt.Errorf("match of backend %s on path %s can have only one cookie", backend.ServiceName, path.Target)
+		}
+		conditions := append(append([]akov1alpha1.HTTPRuleMatchCondition{}, backend.Match.Headers...), backend.Match.Cookies...)
+		for _, condition := range conditions {
+			if condition.Name == "" || condition.Value == "" {
+				return fmt.Errorf("name and value are required for the match conditions of backend %s on path %s", backend.ServiceName, path.Target)
+			}
+		}
+	}
+	return nil
+}
+
+// validateL4RuleObj would do validation checks on the ingested L4Rule objects
+func validateL4RuleObj(key string, l4Rule *akov1alpha1.L4Rule) error {
+	if err := validateL4RuleSpec(l4Rule.Spec); err != nil {
+		status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
+			Status: lib.StatusRejected,
+			Error:  err.Error(),
+		})
+		return err
+	}
+
+	refData := map[string]string{
+		l4Rule.Spec.NetworkProfile:     "NetworkProfile",
+		l4Rule.Spec.ApplicationProfile: "L4AppProfile",
+		l4Rule.Spec.AnalyticsProfile:   "AnalyticsProfile",
+	}
+	backendProperties := []akov1alpha1.L4RuleBackendProperties{l4Rule.Spec.BackendProperties}
+	for _, portProperties := range l4Rule.Spec.PortProperties {
+		backendProperties = append(backendProperties, portProperties.L4RuleBackendProperties)
+	}
+	for _, properties := range backendProperties {
+		refData[properties.ApplicationPersistence] = "ApplicationPersistence"
+		for _, hm := range properties.HealthMonitors {
+			refData[hm] = "HealthMonitor"
+		}
+	}
 
 	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+		status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
 			Status: lib.StatusRejected,
 			Error:  err.Error(),
 		})
 		return err
 	}
 
-	// No need to update status of httprule object as accepted since it was accepted before.
-	if httprule.Status.Status == lib.StatusAccepted {
+	// No need to update status of l4rule object as accepted since it was accepted before.
+	if l4Rule.Status.Status == lib.StatusAccepted {
 		return nil
 	}
 
-	status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+	status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
 		Status: lib.StatusAccepted,
 		Error:  "",
 	})
 	return nil
 }
 
+// validateL4RuleSpec validates the VIP and the pool settings of the L4Rule.
+func validateL4RuleSpec(spec akov1alpha1.L4RuleSpec) error {
+	if spec.LoadBalancerIP != "" {
+		re := regexp.MustCompile(lib.IPRegex)
+		if !re.MatchString(spec.LoadBalancerIP) {
+			return fmt.Errorf("loadBalancerIP %s is not a valid IP", spec.LoadBalancerIP)
+		}
+	}
+
+	backendProperties := []akov1alpha1.L4RuleBackendProperties{spec.BackendProperties}
+	ports := make(map[int32]bool)
+	for _, portProperties := range spec.PortProperties {
+		if ports[portProperties.Port] {
+			return fmt.Errorf("duplicate portProperties found for port %d", portProperties.Port)
+		}
+		ports[portProperties.Port] = true
+		backendProperties = append(backendProperties, portProperties.L4RuleBackendProperties)
+	}
+
+	for _, properties := range backendProperties {
+		lbPolicy := properties.LoadBalancerPolicy
+		if lbPolicy.Hash != "" && lbPolicy.Algorithm != lib.LB_ALGORITHM_CONSISTENT_HASH {
+			return fmt.Errorf("hash %s is only applicable for algorithm %s", lbPolicy.Hash, lib.LB_ALGORITHM_CONSISTENT_HASH)
+		}
+		if lbPolicy.Hash == lib.LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER {
+			return fmt.Errorf("hash %s is not supported for L4 pools", lbPolicy.Hash)
+		}
+	}
+	return nil
+}
+
 // validateAviInfraSetting would do validaion checks on the
 // ingested AviInfraSetting objects
 func validateAviInfraSetting(key string, infraSetting *akov1alpha1.AviInfraSetting) error {
-	if ((infraSetting.Spec.Network.EnableRhi != nil && !*infraSetting.Spec.Network.EnableRhi) || infraSetting.Spec.Network.EnableRhi == nil) &&
-		len(infraSetting.Spec.Network.BgpPeerLabels) > 0 {
-		err := fmt.Errorf("BGPPeerLabels cannot be set if EnableRhi is false.")
+	refData, err := checkAviInfraSettingSpec(infraSetting)
+	if err != nil {
 		status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
 			Status: lib.StatusRejected,
 			Error:  err.Error(),
@@ -1009,26 +1423,6 @@ func validateAviInfraSetting(key string, infraSetting *akov1alpha1.AviInfraSetti
 		return err
 	}
 
-	refData := make(map[string]string)
-	for _, vipNetwork := range infraSetting.Spec.Network.VipNetworks {
-		if vipNetwork.Cidr != "" {
-			re := regexp.MustCompile(lib.IPCIDRRegex)
-			if !re.MatchString(vipNetwork.Cidr) {
-				err := fmt.Errorf("invalid CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.Cidr, vipNetwork.NetworkName)
-				status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  err.Error(),
-				})
-				return err
-			}
-		}
-		refData[vipNetwork.NetworkName] = "Network"
-	}
-
-	if infraSetting.Spec.SeGroup.Name != "" {
-		refData[infraSetting.Spec.SeGroup.Name] = "ServiceEngineGroup"
-	}
-
 	if err := checkRefsOnController(key, refData); err != nil {
 		status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
 			Status: lib.StatusRejected,
@@ -1056,6 +1450,36 @@ func validateAviInfraSetting(key string, infraSetting *akov1alpha1.AviInfraSetti
 	return nil
 }
 
+// checkAviInfraSettingSpec validates the network settings of the AviInfraSetting, and returns the Avi object
+// references in it, which are to be checked on the controller.
+func checkAviInfraSettingSpec(infraSetting *akov1alpha1.AviInfraSetting) (map[string]string, error) {
+	if ((infraSetting.Spec.Network.EnableRhi != nil && !*infraSetting.Spec.Network.EnableRhi) || infraSetting.Spec.Network.EnableRhi == nil) &&
+		len(infraSetting.Spec.Network.BgpPeerLabels) > 0 {
+		return nil, fmt.Errorf("BGPPeerLabels cannot be set if EnableRhi is false.")
+	}
+
+	refData := make(map[string]string)
+	for _, vipNetwork := range infraSetting.Spec.Network.VipNetworks {
+		if vipNetwork.Cidr != "" {
+			re := regexp.MustCompile(lib.IPCIDRRegex)
+			if !re.MatchString(vipNetwork.Cidr) {
+				return nil, fmt.Errorf("invalid CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.Cidr, vipNetwork.NetworkName)
+			}
+		}
+		if vipNetwork.V6Cidr != "" {
+			if ip, _, err := net.ParseCIDR(vipNetwork.V6Cidr); err != nil || ip.To4() != nil {
+				return nil, fmt.Errorf("invalid v6 CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.V6Cidr, vipNetwork.NetworkName)
+			}
+		}
+		refData[vipNetwork.NetworkName] = "Network"
+	}
+
+	if infraSetting.Spec.SeGroup.Name != "" {
+		refData[infraSetting.Spec.SeGroup.Name] = "ServiceEngineGroup"
+	}
+	return refData, nil
+}
+
 // addSeGroupLabel configures SEGroup with appropriate labels, during AviInfraSetting
 // creation/updates after ingestion
 func addSeGroupLabel(key, segName string) {
@@ -0,0 +1,214 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+const (
+	DriftAlerted       = "Alerted"
+	DriftReverted      = "Reverted"
+	DriftRevertSkipped = "RevertSkipped"
+	driftDetectionKey  = "driftdetection"
+)
+
+// DriftRecord is an Avi object found modified outside of AKO by the last drift scan, along with the model owning
+// the object and the action taken on the drift.
+type DriftRecord struct {
+	avicache.DriftedObject
+	Model      string    `json:"model,omitempty"`
+	Action     string    `json:"action"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// DriftReport is the result of the last drift scan, served by the /api/drift endpoint of the AKO API server.
+type DriftReport struct {
+	Mode     string        `json:"mode"`
+	LastScan *time.Time    `json:"last_scan,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Objects  []DriftRecord `json:"objects"`
+}
+
+var driftReport = DriftReport{Objects: []DriftRecord{}}
+var driftReportLock sync.RWMutex
+
+// GetDriftReport returns the result of the last drift scan.
+func GetDriftReport() DriftReport {
+	driftReportLock.RLock()
+	defer driftReportLock.RUnlock()
+	report := driftReport
+	report.Mode = lib.GetDriftDetectionMode()
+	report.Objects = append([]DriftRecord{}, driftReport.Objects...)
+	return report
+}
+
+// DetectDrift compares the objects created by AKO on the Avi controller with the cache, and reports the objects
+// modified outside of AKO as Events on the Kubernetes objects of the models owning them. In the Revert mode, the
+// checksums of the drifted objects are cleared in the cache and the models are synced again, so that the rest
+// layer updates the objects with the configuration of the models.
+func (c *AviController) DetectDrift() {
+	mode := lib.GetDriftDetectionMode()
+	aviRestClientPool := avicache.SharedAVIClients()
+	if mode == lib.DriftDetectionDisabled || c.DisableSync || !lib.IsLeader() ||
+		aviRestClientPool == nil || len(aviRestClientPool.AviClient) == 0 {
+		return
+	}
+
+	scanTime := time.Now()
+	aviObjCache := avicache.SharedAviObjCache()
+	drifted, err := aviObjCache.DetectDrift(aviRestClientPool.AviClient[0], utils.CloudName)
+	scanError := ""
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: drift scan is incomplete: %v", driftDetectionKey, err)
+		scanError = err.Error()
+	}
+
+	records := []DriftRecord{}
+	revertModels := make(map[string]bool)
+	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+	for _, obj := range drifted {
+		record := DriftRecord{DriftedObject: obj, Action: DriftAlerted, DetectedAt: scanTime}
+		utils.AviLog.Warnf("key: %s, msg: %s %s was modified outside of AKO, cached checksum: %s, last modified: %s, live checksum: %s, last modified: %s",
+			driftDetectionKey, obj.ObjectType, obj.Name, obj.CachedChecksum, obj.CachedLastModified, obj.LiveChecksum, obj.LiveLastModified)
+		if obj.VirtualService != "" {
+			modelName := lib.GetModelName(obj.Tenant, obj.VirtualService)
+			if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+				record.Model = modelName
+			}
+		}
+		if record.Model != "" && mode == lib.DriftDetectionRevert {
+			if status.IsDriftRevertSkipped(record.Model, driftedObjectMarkers(record.Model, obj)) {
+				record.Action = DriftRevertSkipped
+			} else {
+				record.Action = DriftReverted
+				aviObjCache.InvalidateChecksum(obj)
+				revertModels[record.Model] = true
+			}
+		}
+		if record.Model != "" {
+			message := fmt.Sprintf("%s %s was modified on the Avi controller outside of AKO", obj.ObjectType, obj.Name)
+			reason := lib.AviObjectDrifted
+			if record.Action == DriftReverted {
+				reason = lib.AviObjectDriftReverted
+				message += ", reverting it to the configuration of virtualservice " + obj.VirtualService
+			}
+			status.PublishDriftStatus(record.Model, reason, message)
+		}
+		records = append(records, record)
+	}
+	for modelName := range revertModels {
+		nodes.PublishKeyToRestLayer(modelName, driftDetectionKey, sharedQueue)
+	}
+	if len(drifted) > 0 {
+		utils.AviLog.Infof("key: %s, msg: drift scan found %d objects modified outside of AKO, reverting %d models",
+			driftDetectionKey, len(drifted), len(revertModels))
+	}
+
+	driftReportLock.Lock()
+	driftReport.LastScan = &scanTime
+	driftReport.Error = scanError
+	driftReport.Objects = records
+	driftReportLock.Unlock()
+}
+
+// driftedObjectMarkers returns the markers of the drifted Avi object in the model, which identify the Kubernetes
+// objects the Avi object is built from. Returns nil for the objects shared by all the objects of the model, like
+// the parent virtualservice and its vsvip.
+func driftedObjectMarkers(modelName string, obj avicache.DriftedObject) *utils.AviObjectMarkers {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil
+	}
+	graph := aviModel.(*nodes.AviObjectGraph)
+	graph.Lock.RLock()
+	defer graph.Lock.RUnlock()
+
+	var markers *utils.AviObjectMarkers
+	match := func(name string, nodeMarkers utils.AviObjectMarkers) {
+		if markers == nil && name == obj.Name {
+			objMarkers := nodeMarkers
+			markers = &objMarkers
+		}
+	}
+	var walkVS func(vsNode *nodes.AviVsNode)
+	walkVS = func(vsNode *nodes.AviVsNode) {
+		switch obj.ObjectType {
+		case "virtualservice":
+			if !vsNode.SNIParent {
+				match(vsNode.Name, vsNode.AviMarkers)
+			}
+		case "pool":
+			for _, pool := range vsNode.PoolRefs {
+				match(pool.Name, pool.AviMarkers)
+			}
+		case "poolgroup":
+			for _, pg := range vsNode.PoolGroupRefs {
+				match(pg.Name, pg.AviMarkers)
+			}
+		case "httppolicyset":
+			for _, httpPolicySet := range vsNode.HttpPolicyRefs {
+				match(httpPolicySet.Name, httpPolicySet.AviMarkers)
+			}
+		case "l4policyset":
+			for _, l4PolicySet := range vsNode.L4PolicyRefs {
+				match(l4PolicySet.Name, l4PolicySet.AviMarkers)
+			}
+		}
+		for _, childNode := range vsNode.SniNodes {
+			walkVS(childNode)
+		}
+	}
+	var walkEvhVS func(vsNode *nodes.AviEvhVsNode)
+	walkEvhVS = func(vsNode *nodes.AviEvhVsNode) {
+		switch obj.ObjectType {
+		case "virtualservice":
+			if !vsNode.EVHParent {
+				match(vsNode.Name, vsNode.AviMarkers)
+			}
+		case "pool":
+			for _, pool := range vsNode.PoolRefs {
+				match(pool.Name, pool.AviMarkers)
+			}
+		case "poolgroup":
+			for _, pg := range vsNode.PoolGroupRefs {
+				match(pg.Name, pg.AviMarkers)
+			}
+		case "httppolicyset":
+			for _, httpPolicySet := range vsNode.HttpPolicyRefs {
+				match(httpPolicySet.Name, httpPolicySet.AviMarkers)
+			}
+		}
+		for _, childNode := range vsNode.EvhNodes {
+			walkEvhVS(childNode)
+		}
+	}
+	for _, vsNode := range graph.GetAviVS() {
+		walkVS(vsNode)
+	}
+	for _, vsNode := range graph.GetAviEvhVS() {
+		walkEvhVS(vsNode)
+	}
+	return markers
+}
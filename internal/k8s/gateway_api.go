@@ -0,0 +1,155 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapicrd "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned"
+	gwapiinformers "sigs.k8s.io/gateway-api/pkg/client/informers/gateway/externalversions"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// Gateway API (gateway.networking.k8s.io/v1alpha2) related functions.
+
+func NewGatewayAPIInformers(cs gwapicrd.Interface) {
+	gwApiInformerFactory := gwapiinformers.NewSharedInformerFactory(cs, time.Second*30)
+	v1alpha2 := gwApiInformerFactory.Gateway().V1alpha2()
+	lib.AKOControlConfig().SetGatewayAPIInformers(&lib.GatewayAPIInformers{
+		GatewayClassInformer:    v1alpha2.GatewayClasses(),
+		GatewayInformer:         v1alpha2.Gateways(),
+		HTTPRouteInformer:       v1alpha2.HTTPRoutes(),
+		TLSRouteInformer:        v1alpha2.TLSRoutes(),
+		TCPRouteInformer:        v1alpha2.TCPRoutes(),
+		UDPRouteInformer:        v1alpha2.UDPRoutes(),
+		ReferencePolicyInformer: v1alpha2.ReferencePolicies(),
+	})
+}
+
+// getGatewayAPISpec returns the spec of the Gateway API object, which is compared on updates.
+func getGatewayAPISpec(obj interface{}) interface{} {
+	switch o := obj.(type) {
+	case *gwapiv1alpha2.GatewayClass:
+		return o.Spec
+	case *gwapiv1alpha2.Gateway:
+		return o.Spec
+	case *gwapiv1alpha2.HTTPRoute:
+		return o.Spec
+	case *gwapiv1alpha2.TLSRoute:
+		return o.Spec
+	case *gwapiv1alpha2.TCPRoute:
+		return o.Spec
+	case *gwapiv1alpha2.UDPRoute:
+		return o.Spec
+	case *gwapiv1alpha2.ReferencePolicy:
+		return o.Spec
+	}
+	return nil
+}
+
+// gatewayAPIEventHandler returns the event handler for the Gateway API objects of the objType. The keys are
+// of the form objType/namespace/name, and objType/name for the cluster scoped GatewayClass.
+func (c *AviController) gatewayAPIEventHandler(objType string, numWorkers uint32) cache.ResourceEventHandlerFuncs {
+	enqueue := func(obj interface{}, op string) {
+		objMeta, err := meta.Accessor(obj)
+		if err != nil {
+			utils.AviLog.Warnf("Unable to get the metadata of %s: %v", objType, err)
+			return
+		}
+		namespace := objMeta.GetNamespace()
+		if namespace != "" && !utils.CheckIfNamespaceAccepted(namespace) {
+			utils.AviLog.Debugf("%s %s event. Namespace %s didn't qualify filter.", objType, op, namespace)
+			return
+		}
+		key := objType + "/" + utils.ObjKey(objMeta)
+		utils.AviLog.Infof("key: %s, msg: %s", key, op)
+		bkt := utils.Bkt(namespace, numWorkers)
+		c.workqueue[bkt].AddRateLimited(key)
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			enqueue(obj, "ADD")
+		},
+		UpdateFunc: func(old, new interface{}) {
+			if c.DisableSync {
+				return
+			}
+			newMeta, err := meta.Accessor(new)
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(getGatewayAPISpec(old), getGatewayAPISpec(new)) || newMeta.GetDeletionTimestamp() != nil {
+				enqueue(new, "UPDATE")
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			if _, ok := obj.(runtime.Object); !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				obj = tombstone.Obj
+			}
+			if getGatewayAPISpec(obj) == nil {
+				utils.AviLog.Errorf("Tombstone contained object that is not a %s: %#v", objType, obj)
+				return
+			}
+			enqueue(obj, "DELETE")
+		},
+	}
+}
+
+// SetupGatewayAPIEventHandlers handles setting up of Gateway API event handlers
+func (c *AviController) SetupGatewayAPIEventHandlers(numWorkers uint32) {
+	utils.AviLog.Infof("Setting up Gateway API Event handlers")
+	informer := lib.AKOControlConfig().GatewayAPIInformers()
+
+	informer.GatewayClassInformer.Informer().AddEventHandler(c.gatewayAPIEventHandler(lib.GatewayAPIGatewayClass, numWorkers))
+	informer.GatewayInformer.Informer().AddEventHandler(c.gatewayAPIEventHandler(lib.GatewayAPIGateway, numWorkers))
+	informer.HTTPRouteInformer.Informer().AddEventHandler(c.gatewayAPIEventHandler(lib.HTTPRoute, numWorkers))
+	informer.TLSRouteInformer.Informer().AddEventHandler(c.gatewayAPIEventHandler(lib.TLSRoute, numWorkers))
+	informer.TCPRouteInformer.Informer().AddEventHandler(c.gatewayAPIEventHandler(lib.TCPRoute, numWorkers))
+	informer.UDPRouteInformer.Informer().AddEventHandler(c.gatewayAPIEventHandler(lib.UDPRoute, numWorkers))
+	informer.ReferencePolicyInformer.Informer().AddEventHandler(c.gatewayAPIEventHandler(lib.ReferencePolicy, numWorkers))
+}
+
+// gatewayAPIInformers returns the shared informers of the Gateway API objects.
+func gatewayAPIInformers() []cache.SharedIndexInformer {
+	informer := lib.AKOControlConfig().GatewayAPIInformers()
+	return []cache.SharedIndexInformer{
+		informer.GatewayClassInformer.Informer(),
+		informer.GatewayInformer.Informer(),
+		informer.HTTPRouteInformer.Informer(),
+		informer.TLSRouteInformer.Informer(),
+		informer.TCPRouteInformer.Informer(),
+		informer.UDPRouteInformer.Informer(),
+		informer.ReferencePolicyInformer.Informer(),
+	}
+}
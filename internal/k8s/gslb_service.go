@@ -0,0 +1,469 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"sort"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const gslbServiceKey = "gslbservice"
+
+// gslbServicesScanned is set once the GSLBServices are scanned for stale members, and syncedGSLBServices holds
+// the GSLBServices set by the HostRules in the last sync.
+var gslbServicesScanned bool
+var syncedGSLBServices map[string]bool
+
+// gslbServiceSyncCh holds a pending request to sync the GSLBServices, the requests made meanwhile are coalesced.
+var gslbServiceSyncCh = make(chan struct{}, 1)
+
+// TriggerGSLBServiceSync requests a sync of the GSLBServices ahead of the interval, on the HostRule events which
+// change the GSLBServices.
+func TriggerGSLBServiceSync() {
+	select {
+	case gslbServiceSyncCh <- struct{}{}:
+	default:
+	}
+}
+
+// isGSLBHostRuleUpdated returns true if the update of the HostRule changes its GSLBService, which is the case for the
+// updates of gslb, and of the status of a HostRule setting gslb.service.
+func isGSLBHostRuleUpdated(oldObj, hostrule *akov1alpha1.HostRule) bool {
+	if !reflect.DeepEqual(oldObj.Spec.VirtualHost.Gslb, hostrule.Spec.VirtualHost.Gslb) {
+		return true
+	}
+	return hostrule.Spec.VirtualHost.Gslb.Service != nil && oldObj.Status.Status != hostrule.Status.Status
+}
+
+// GSLBMember is a virtualservice of this cluster serving the GSLB FQDN of a HostRule.
+type GSLBMember struct {
+	VirtualService string
+	VsUuid         string
+	IP             string
+	PublicIP       string
+}
+
+// SyncGSLBServices creates and updates the GSLBServices of the HostRules with gslb.service set, with the
+// virtualservices of this cluster serving the GSLB FQDN as members, and removes the members of this cluster
+// from the GSLBServices of the HostRules which no longer set it. The members of the other clusters are left
+// untouched, so that the AKO of every cluster maintains its own members of a GSLBService shared by the clusters.
+// The GSLBServices are configured on the Avi controller of AKO, which must be the GSLB leader site.
+func (c *AviController) SyncGSLBServices() {
+	aviRestClientPool := avicache.SharedAVIClients()
+	if c.DisableSync || !lib.IsLeader() || !lib.AKOControlConfig().HostRuleEnabled() ||
+		aviRestClientPool == nil || len(aviRestClientPool.AviClient) == 0 {
+		return
+	}
+	hostRules, err := lib.AKOControlConfig().CRDInformers().HostRuleInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the hostrules: %v", gslbServiceKey, err)
+		return
+	}
+	desired := make(map[string]bool)
+	var gslbHostRules []*akov1alpha1.HostRule
+	for _, hostRule := range hostRules {
+		gslb := hostRule.Spec.VirtualHost.Gslb
+		if gslb.Service == nil || gslb.Fqdn == "" || hostRule.Status.Status != lib.StatusAccepted {
+			if hostRule.Status.Gslb != nil {
+				status.UpdateHostRuleGSLBStatus(lib.HostRule+"/"+utils.ObjKey(hostRule), hostRule, nil)
+			}
+			continue
+		}
+		desired[gslb.Fqdn] = true
+		gslbHostRules = append(gslbHostRules, hostRule)
+	}
+	// The GSLBServices are scanned for the stale members of this cluster once after the boot, and then only
+	// while or right after HostRules set gslb.service.
+	if gslbServicesScanned && len(desired) == 0 && len(syncedGSLBServices) == 0 {
+		return
+	}
+
+	client := aviRestClientPool.AviClient[0]
+	if err := avicache.SetControllerClusterUUID(aviRestClientPool); err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the uuid of the Avi controller cluster: %v", gslbServiceKey, err)
+		return
+	}
+	clusterUuid := avicache.GetControllerClusterUUID()
+	for _, hostRule := range gslbHostRules {
+		key := lib.HostRule + "/" + utils.ObjKey(hostRule)
+		gslb := hostRule.Spec.VirtualHost.Gslb
+		members := GSLBMembers(hostRule.Spec.VirtualHost.Fqdn, gslb.Fqdn)
+		gslbStatus, err := SyncGSLBService(client, clusterUuid, hostRule, members)
+		if err == lib.ErrDryRun {
+			// The GSLB service is not updated in the dry-run mode, neither is the status of the HostRule.
+			continue
+		}
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: unable to sync the GSLB service %s: %v", key, gslb.Fqdn, err)
+			gslbStatus = &akov1alpha1.HostRuleGSLBStatus{ServiceName: gslb.Fqdn, Error: err.Error()}
+		}
+		if !reflect.DeepEqual(hostRule.Status.Gslb, gslbStatus) {
+			status.UpdateHostRuleGSLBStatus(key, hostRule, gslbStatus)
+		}
+	}
+
+	if err := removeStaleGSLBMembers(client, clusterUuid, desired); err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to remove the stale GSLB service members: %v", gslbServiceKey, err)
+		return
+	}
+	gslbServicesScanned = true
+	syncedGSLBServices = desired
+}
+
+// SyncGSLBService updates the members of this cluster in the GSLBService of the HostRule, along with the
+// algorithm, health monitors and TTL of the HostRule, and returns the status of the members. The GSLBService
+// is created when missing, and deleted when it is left without members.
+func SyncGSLBService(client *clients.AviClient, clusterUuid string, hostRule *akov1alpha1.HostRule, members []GSLBMember) (*akov1alpha1.HostRuleGSLBStatus, error) {
+	gslb := hostRule.Spec.VirtualHost.Gslb
+	gsName := gslb.Fqdn
+	gs, err := getGSLBService(client, gsName)
+	if err != nil {
+		return nil, err
+	}
+	if gs == nil {
+		if len(members) == 0 {
+			return &akov1alpha1.HostRuleGSLBStatus{ServiceName: gsName}, nil
+		}
+		gs = &avimodels.GslbService{
+			Name:        &gsName,
+			DomainNames: []string{gsName},
+			TenantRef:   proto.String(fmt.Sprintf("/api/tenant/?name=%s", lib.GetTenant())),
+		}
+	}
+	removeGSLBMembers(gs, clusterUuid)
+	addGSLBMembers(gs, clusterUuid, gslb.Service, members)
+	removeEmptyGSLBPools(gs)
+	setGSLBServiceProperties(gs, gsName, gslb.Service)
+
+	gsStatus := &akov1alpha1.HostRuleGSLBStatus{ServiceName: gsName}
+	if len(gs.Groups) == 0 {
+		if gs.UUID != nil {
+			utils.AviLog.Infof("key: %s, msg: deleting the GSLB service %s without members", gslbServiceKey, gsName)
+			if err := lib.AviDelete(client, "/api/gslbservice/"+*gs.UUID); err != nil {
+				return nil, err
+			}
+		}
+		return gsStatus, nil
+	}
+
+	var response avimodels.GslbService
+	if gs.UUID == nil {
+		utils.AviLog.Infof("key: %s, msg: creating the GSLB service %s", gslbServiceKey, gsName)
+		err = lib.AviPost(client, "/api/gslbservice", gs, &response)
+	} else {
+		utils.AviLog.Infof("key: %s, msg: updating the GSLB service %s", gslbServiceKey, gsName)
+		err = lib.AviPut(client, "/api/gslbservice/"+*gs.UUID, gs, &response)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	operStatus := make(map[string]string)
+	if response.UUID != nil {
+		operStatus = getGSLBMemberOperStatus(client, *response.UUID, clusterUuid)
+	}
+	for _, group := range gs.Groups {
+		for _, member := range group.Members {
+			if !isClusterGSLBMember(member, clusterUuid) {
+				continue
+			}
+			memberStatus := akov1alpha1.HostRuleGSLBMemberStatus{Pool: *group.Name}
+			if member.IP != nil && member.IP.Addr != nil {
+				memberStatus.IP = *member.IP.Addr
+			}
+			if member.VsUUID != nil {
+				memberStatus.VirtualService = gslbMemberVSName(members, *member.VsUUID)
+				memberStatus.Status = operStatus[*member.VsUUID+"/"+memberStatus.IP]
+			}
+			if memberStatus.Status == "" {
+				memberStatus.Status = "OPER_UNKNOWN"
+			}
+			gsStatus.Members = append(gsStatus.Members, memberStatus)
+		}
+	}
+	return gsStatus, nil
+}
+
+// GSLBMembers returns the parent virtualservices in the cache, whose vsvip serves the host or the GSLB FQDN.
+func GSLBMembers(host, gslbFqdn string) []GSLBMember {
+	aviObjCache := avicache.SharedAviObjCache()
+	var members []GSLBMember
+	for _, vsKey := range aviObjCache.VsCacheMeta.AviGetAllKeys() {
+		vsCache, found := aviObjCache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			continue
+		}
+		vsCacheObj, ok := vsCache.(*avicache.AviVsCache)
+		if !ok {
+			continue
+		}
+		vsCacheObj.VSCacheLock.RLock()
+		if vsCacheObj.ParentVSRef.Name != "" || vsCacheObj.Uuid == "" {
+			vsCacheObj.VSCacheLock.RUnlock()
+			continue
+		}
+		for _, vsvipKey := range vsCacheObj.VSVipKeyCollection {
+			vsvipCache, found := aviObjCache.VSVIPCache.AviCacheGet(vsvipKey)
+			if !found {
+				continue
+			}
+			vsvipCacheObj, ok := vsvipCache.(*avicache.AviVSVIPCache)
+			if !ok || len(vsvipCacheObj.Vips) == 0 ||
+				(!utils.HasElem(vsvipCacheObj.FQDNs, host) && !utils.HasElem(vsvipCacheObj.FQDNs, gslbFqdn)) {
+				continue
+			}
+			member := GSLBMember{VirtualService: vsCacheObj.Name, VsUuid: vsCacheObj.Uuid, IP: vsvipCacheObj.Vips[0]}
+			if len(vsvipCacheObj.Fips) > 0 {
+				member.PublicIP = vsvipCacheObj.Fips[0]
+			}
+			members = append(members, member)
+		}
+		vsCacheObj.VSCacheLock.RUnlock()
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].VirtualService < members[j].VirtualService
+	})
+	return members
+}
+
+// gslbPoolName returns the name of the GSLB pool holding the members of this cluster. The members of all the
+// clusters share a pool with the RoundRobin and Geo algorithms, while every cluster has its own pool with the
+// Priority algorithm, so that the DNS queries fall over to the pool of the next cluster.
+func gslbPoolName(service *akov1alpha1.HostRuleGSLBService) string {
+	if service.Algorithm == akov1alpha1.GSLBAlgorithmPriority {
+		return lib.GetNamePrefix() + "gslb-pool"
+	}
+	return lib.GSLBSharedPoolName
+}
+
+// gslbMemberDescription marks the GSLB pool members created by the AKO of this cluster, as the clusters
+// sharing an Avi controller have the same cluster uuid.
+func gslbMemberDescription() string {
+	return lib.GetAKOUser()
+}
+
+func isClusterGSLBMember(member *avimodels.GslbPoolMember, clusterUuid string) bool {
+	return member.ClusterUUID != nil && *member.ClusterUUID == clusterUuid &&
+		member.Description != nil && *member.Description == gslbMemberDescription()
+}
+
+// removeGSLBMembers removes the members of this cluster from the pools of the GSLBService.
+func removeGSLBMembers(gs *avimodels.GslbService, clusterUuid string) bool {
+	removed := false
+	for _, group := range gs.Groups {
+		var members []*avimodels.GslbPoolMember
+		for _, member := range group.Members {
+			if isClusterGSLBMember(member, clusterUuid) {
+				removed = true
+				continue
+			}
+			members = append(members, member)
+		}
+		group.Members = members
+	}
+	return removed
+}
+
+// removeEmptyGSLBPools removes the pools without members from the GSLBService.
+func removeEmptyGSLBPools(gs *avimodels.GslbService) {
+	var groups []*avimodels.GslbPool
+	for _, group := range gs.Groups {
+		if len(group.Members) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	gs.Groups = groups
+}
+
+func addGSLBMembers(gs *avimodels.GslbService, clusterUuid string, service *akov1alpha1.HostRuleGSLBService, members []GSLBMember) {
+	if len(members) == 0 {
+		return
+	}
+	poolName := gslbPoolName(service)
+	var pool *avimodels.GslbPool
+	for _, group := range gs.Groups {
+		if group.Name != nil && *group.Name == poolName {
+			pool = group
+			break
+		}
+	}
+	if pool == nil {
+		pool = &avimodels.GslbPool{Name: proto.String(poolName), Enabled: proto.Bool(true)}
+		gs.Groups = append(gs.Groups, pool)
+	}
+	algorithm := lib.GSLBAlgorithmRoundRobin
+	if service.Algorithm == akov1alpha1.GSLBAlgorithmGeo {
+		algorithm = lib.GSLBAlgorithmGeo
+	}
+	pool.Algorithm = &algorithm
+	priority := int32(lib.DefaultGSLBPoolPriority)
+	if service.Algorithm == akov1alpha1.GSLBAlgorithmPriority && service.Priority != 0 {
+		priority = service.Priority
+	}
+	pool.Priority = &priority
+
+	for _, member := range members {
+		poolMember := &avimodels.GslbPoolMember{
+			ClusterUUID: proto.String(clusterUuid),
+			VsUUID:      proto.String(member.VsUuid),
+			IP:          gslbIPAddr(member.IP),
+			Enabled:     proto.Bool(true),
+			Description: proto.String(gslbMemberDescription()),
+		}
+		if member.PublicIP != "" {
+			poolMember.PublicIP = &avimodels.GslbIPAddr{IP: gslbIPAddr(member.PublicIP)}
+		}
+		pool.Members = append(pool.Members, poolMember)
+	}
+}
+
+func setGSLBServiceProperties(gs *avimodels.GslbService, gsName string, service *akov1alpha1.HostRuleGSLBService) {
+	if !utils.HasElem(gs.DomainNames, gsName) {
+		gs.DomainNames = append(gs.DomainNames, gsName)
+	}
+	poolAlgorithm := lib.GSLBServiceAlgorithmPriority
+	if service.Algorithm == akov1alpha1.GSLBAlgorithmGeo {
+		poolAlgorithm = lib.GSLBServiceAlgorithmGeo
+	}
+	gs.PoolAlgorithm = &poolAlgorithm
+	gs.TTL = service.TTL
+	gs.HealthMonitorRefs = nil
+	for _, healthMonitor := range service.HealthMonitors {
+		gs.HealthMonitorRefs = append(gs.HealthMonitorRefs, "/api/healthmonitor?name="+healthMonitor)
+	}
+	if gs.Enabled == nil {
+		gs.Enabled = proto.Bool(true)
+	}
+}
+
+// removeStaleGSLBMembers removes the members of this cluster from the GSLBServices, which are no longer set by
+// any HostRule, and deletes the GSLBServices left without members.
+func removeStaleGSLBMembers(client *clients.AviClient, clusterUuid string, desired map[string]bool) error {
+	uri := "/api/gslbservice?page_size=100"
+	for uri != "" {
+		result, err := lib.AviGetCollectionRaw(client, uri)
+		if err != nil {
+			return err
+		}
+		var gslbServices []*avimodels.GslbService
+		if err = json.Unmarshal(result.Results, &gslbServices); err != nil {
+			return fmt.Errorf("failed to unmarshal gslbservice data, err: %v", err)
+		}
+		for _, gs := range gslbServices {
+			if gs.Name == nil || gs.UUID == nil || desired[*gs.Name] || !removeGSLBMembers(gs, clusterUuid) {
+				continue
+			}
+			removeEmptyGSLBPools(gs)
+			if len(gs.Groups) == 0 {
+				utils.AviLog.Infof("key: %s, msg: deleting the stale GSLB service %s", gslbServiceKey, *gs.Name)
+				err = lib.AviDelete(client, "/api/gslbservice/"+*gs.UUID)
+			} else {
+				utils.AviLog.Infof("key: %s, msg: removing the members of this cluster from the GSLB service %s", gslbServiceKey, *gs.Name)
+				var response avimodels.GslbService
+				err = lib.AviPut(client, "/api/gslbservice/"+*gs.UUID, gs, &response)
+			}
+			if err != nil && err != lib.ErrDryRun {
+				utils.AviLog.Warnf("key: %s, msg: unable to update the GSLB service %s: %v", gslbServiceKey, *gs.Name, err)
+			}
+		}
+		uri = ""
+		if next, err := url.Parse(result.Next); result.Next != "" && err == nil {
+			uri = next.RequestURI()
+		}
+	}
+	return nil
+}
+
+func getGSLBService(client *clients.AviClient, name string) (*avimodels.GslbService, error) {
+	result, err := lib.AviGetCollectionRaw(client, "/api/gslbservice?name="+url.QueryEscape(name))
+	if err != nil {
+		return nil, err
+	}
+	var gslbServices []*avimodels.GslbService
+	if err = json.Unmarshal(result.Results, &gslbServices); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal gslbservice data, err: %v", err)
+	}
+	if len(gslbServices) == 0 {
+		return nil, nil
+	}
+	return gslbServices[0], nil
+}
+
+// getGSLBMemberOperStatus returns the operational state of the members of this cluster in the GSLBService,
+// keyed by the virtualservice uuid and the IP of the member.
+func getGSLBMemberOperStatus(client *clients.AviClient, gsUuid, clusterUuid string) map[string]string {
+	operStatus := make(map[string]string)
+	data, err := lib.AviGetRaw(client, "/api/gslbservice/"+gsUuid+"/runtime")
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: unable to get the runtime of the GSLB service %s: %v", gslbServiceKey, gsUuid, err)
+		return operStatus
+	}
+	var runtimes []*avimodels.GslbServiceRuntime
+	if err := json.Unmarshal(data, &runtimes); err != nil {
+		var runtime avimodels.GslbServiceRuntime
+		if err := json.Unmarshal(data, &runtime); err != nil {
+			return operStatus
+		}
+		runtimes = append(runtimes, &runtime)
+	}
+	for _, runtime := range runtimes {
+		for _, group := range runtime.Groups {
+			for _, member := range group.Members {
+				if member.ClusterUUID == nil || *member.ClusterUUID != clusterUuid || member.VsUUID == nil ||
+					member.OperStatus == nil || member.OperStatus.State == nil {
+					continue
+				}
+				ip := ""
+				if member.IP != nil && member.IP.Addr != nil {
+					ip = *member.IP.Addr
+				}
+				operStatus[*member.VsUUID+"/"+ip] = *member.OperStatus.State
+			}
+		}
+	}
+	return operStatus
+}
+
+func gslbMemberVSName(members []GSLBMember, vsUuid string) string {
+	for _, member := range members {
+		if member.VsUuid == vsUuid {
+			return member.VirtualService
+		}
+	}
+	return ""
+}
+
+func gslbIPAddr(ip string) *avimodels.IPAddr {
+	addrType := "V4"
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		addrType = "V6"
+	}
+	return &avimodels.IPAddr{Addr: proto.String(ip), Type: &addrType}
+}
@@ -0,0 +1,388 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// IntrospectionModel implements ApiModel, and serves read-only views of the models built by the graph layer,
+// the cache of the Avi controller objects, the Kubernetes objects mapped to a model and the Avi objects modified
+// outside of AKO.
+type IntrospectionModel struct{}
+
+type ModelSummary struct {
+	Name          string `json:"name"`
+	GraphChecksum uint32 `json:"graph_checksum"`
+	RetryCount    int    `json:"retry_count"`
+}
+
+type ModelDump struct {
+	ModelSummary
+	Nodes []nodes.AviModelNode `json:"nodes"`
+}
+
+type VirtualServiceCacheDump struct {
+	VirtualService interface{}               `json:"virtualservice"`
+	VsVips         []interface{}             `json:"vsvips"`
+	PoolGroups     []interface{}             `json:"poolgroups"`
+	Pools          []interface{}             `json:"pools"`
+	PKIProfiles    []interface{}             `json:"pkiprofiles"`
+	HealthMonitors []interface{}             `json:"healthmonitors"`
+	SSLKeyCerts    []interface{}             `json:"sslkeyandcertificates"`
+	HTTPPolicySets []interface{}             `json:"httppolicysets"`
+	DataScripts    []interface{}             `json:"vsdatascriptsets"`
+	L4PolicySets   []interface{}             `json:"l4policysets"`
+	ChildVSes      []VirtualServiceCacheDump `json:"child_virtualservices,omitempty"`
+}
+
+type ModelObjects struct {
+	Model           string                         `json:"model"`
+	VirtualServices []string                       `json:"virtualservices"`
+	Hosts           map[string]map[string][]string `json:"hosts"`
+	Ingresses       []string                       `json:"ingresses"`
+	Routes          []string                       `json:"routes"`
+	Services        []string                       `json:"services"`
+	Secrets         []string                       `json:"secrets"`
+	Gateways        []string                       `json:"gateways"`
+}
+
+func (a *IntrospectionModel) InitModel() {}
+
+func (a *IntrospectionModel) ApiOperationMap() []models.OperationMap {
+	return []models.OperationMap{
+		{
+			Route:   "/api/models",
+			Method:  "GET",
+			Handler: listModels,
+		},
+		{
+			Route:   "/api/models/{tenant}/{name}",
+			Method:  "GET",
+			Handler: getModel,
+		},
+		{
+			Route:   "/api/models/{tenant}/{name}/objects",
+			Method:  "GET",
+			Handler: getModelObjects,
+		},
+		{
+			Route:   "/api/cache/virtualservices/{tenant}/{name}",
+			Method:  "GET",
+			Handler: getVirtualServiceCache,
+		},
+		{
+			Route:   "/api/drift",
+			Method:  "GET",
+			Handler: getDriftReport,
+		},
+	}
+}
+
+func listModels(w http.ResponseWriter, r *http.Request) {
+	summaries := []ModelSummary{}
+	for modelName, modelIntf := range objects.SharedAviGraphLister().GetAll().(map[string]interface{}) {
+		summary := ModelSummary{Name: modelName}
+		if aviModel, ok := modelIntf.(*nodes.AviObjectGraph); ok && aviModel != nil {
+			aviModel.Lock.RLock()
+			summary.GraphChecksum = aviModel.GraphChecksum
+			summary.RetryCount = aviModel.RetryCount
+			aviModel.Lock.RUnlock()
+		}
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	utils.Respond(w, summaries)
+}
+
+func getAviModel(r *http.Request) (string, *nodes.AviObjectGraph) {
+	vars := mux.Vars(r)
+	modelName := vars["tenant"] + "/" + vars["name"]
+	found, modelIntf := objects.SharedAviGraphLister().Get(modelName)
+	if !found || modelIntf == nil {
+		return modelName, nil
+	}
+	aviModel, _ := modelIntf.(*nodes.AviObjectGraph)
+	return modelName, aviModel
+}
+
+func getModel(w http.ResponseWriter, r *http.Request) {
+	modelName, aviModel := getAviModel(r)
+	if aviModel == nil {
+		utils.RespondError(w, http.StatusNotFound, "model "+modelName+" not found")
+		return
+	}
+	aviModel.Lock.RLock()
+	defer aviModel.Lock.RUnlock()
+	utils.Respond(w, NewModelDump(modelName, aviModel))
+}
+
+// NewModelDump returns the nodes of a model to be served by the introspection API. The nodes are copied, and the
+// private keys of the TLS certificates are removed from the copies, as the API is not authenticated.
+func NewModelDump(modelName string, aviModel *nodes.AviObjectGraph) ModelDump {
+	dump := ModelDump{
+		ModelSummary: ModelSummary{
+			Name:          modelName,
+			GraphChecksum: aviModel.GraphChecksum,
+			RetryCount:    aviModel.RetryCount,
+		},
+		Nodes: []nodes.AviModelNode{},
+	}
+	for _, node := range aviModel.GetOrderedNodes() {
+		nodeCopy := node.CopyNode()
+		switch vsNode := nodeCopy.(type) {
+		case *nodes.AviVsNode:
+			redactVsNode(vsNode)
+		case *nodes.AviEvhVsNode:
+			redactEvhVsNode(vsNode)
+		case *nodes.AviTLSKeyCertNode:
+			vsNode.Key = nil
+		}
+		dump.Nodes = append(dump.Nodes, nodeCopy)
+	}
+	return dump
+}
+
+func redactKeyCerts(keyCerts []*nodes.AviTLSKeyCertNode) {
+	for _, keyCert := range keyCerts {
+		keyCert.Key = nil
+	}
+}
+
+func redactVsNode(vsNode *nodes.AviVsNode) {
+	redactKeyCerts(vsNode.SSLKeyCertRefs)
+	redactKeyCerts(vsNode.CACertRefs)
+	for _, sniNode := range vsNode.SniNodes {
+		redactVsNode(sniNode)
+	}
+}
+
+func redactEvhVsNode(evhNode *nodes.AviEvhVsNode) {
+	redactKeyCerts(evhNode.SSLKeyCertRefs)
+	redactKeyCerts(evhNode.CACertRefs)
+	for _, evhChild := range evhNode.EvhNodes {
+		redactEvhVsNode(evhChild)
+	}
+}
+
+// getModelObjects returns the Kubernetes objects mapped to the virtualservices of a model, using the hostnames,
+// the service metadata of the virtualservices and the ingress/route relations stored in the listers.
+func getModelObjects(w http.ResponseWriter, r *http.Request) {
+	modelName, aviModel := getAviModel(r)
+	if aviModel == nil {
+		utils.RespondError(w, http.StatusNotFound, "model "+modelName+" not found")
+		return
+	}
+
+	aviModel.Lock.RLock()
+	defer aviModel.Lock.RUnlock()
+	var vsNodes []nodes.AviVsEvhSniModel
+	for _, vsNode := range aviModel.GetAviVS() {
+		vsNodes = append(vsNodes, vsNode)
+		for _, sniNode := range vsNode.SniNodes {
+			vsNodes = append(vsNodes, sniNode)
+		}
+	}
+	for _, evhNode := range aviModel.GetAviEvhVS() {
+		vsNodes = append(vsNodes, evhNode)
+		for _, evhChild := range evhNode.EvhNodes {
+			vsNodes = append(vsNodes, evhChild)
+		}
+	}
+
+	response := ModelObjects{
+		Model:     modelName,
+		Hosts:     make(map[string]map[string][]string),
+		Ingresses: []string{},
+		Routes:    []string{},
+	}
+	ingresses, services, secrets, gateways := make(map[string]bool), make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	addServiceMetadata := func(serviceMetadata lib.ServiceMetadataObj, vhDomainNames ...string) {
+		var hosts []string
+		hosts = append(hosts, vhDomainNames...)
+		hosts = append(hosts, serviceMetadata.HostNames...)
+		for _, host := range hosts {
+			if _, ok := response.Hosts[host]; ok {
+				continue
+			}
+			pathIngresses := make(map[string][]string)
+			nodes.SharedHostNameLister().RLock()
+			_, hostPaths := nodes.SharedHostNameLister().GetHostPathStore(host)
+			for path, ings := range hostPaths {
+				pathIngresses[path] = append([]string{}, ings...)
+				for _, ing := range ings {
+					ingresses[ing] = true
+				}
+			}
+			nodes.SharedHostNameLister().RUnlock()
+			response.Hosts[host] = pathIngresses
+		}
+		for _, ing := range serviceMetadata.NamespaceIngressName {
+			ingresses[ing] = true
+		}
+		if serviceMetadata.IngressName != "" && serviceMetadata.Namespace != "" {
+			ingresses[serviceMetadata.Namespace+"/"+serviceMetadata.IngressName] = true
+		}
+		for _, svc := range serviceMetadata.NamespaceServiceName {
+			services[svc] = true
+		}
+		if serviceMetadata.Gateway != "" {
+			gateways[serviceMetadata.Gateway] = true
+		}
+	}
+
+	for _, vsNode := range vsNodes {
+		response.VirtualServices = append(response.VirtualServices, vsNode.GetName())
+		addServiceMetadata(vsNode.GetServiceMetadata(), vsNode.GetVHDomainNames()...)
+		for _, poolNode := range vsNode.GetPoolRefs() {
+			addServiceMetadata(poolNode.ServiceMetadata)
+		}
+	}
+
+	isRoute := utils.GetInformers().RouteInformer != nil
+	lister := objects.SharedSvcLister()
+	if isRoute {
+		lister = objects.OshiftRouteSvcLister()
+	}
+	for ing := range ingresses {
+		nsName := strings.SplitN(ing, "/", 2)
+		if len(nsName) != 2 {
+			continue
+		}
+		if isRoute {
+			response.Routes = append(response.Routes, ing)
+		} else {
+			response.Ingresses = append(response.Ingresses, ing)
+		}
+		if found, svcs := lister.IngressMappings(nsName[0]).GetIngToSvc(nsName[1]); found {
+			for _, svc := range svcs {
+				services[nsName[0]+"/"+svc] = true
+			}
+		}
+		if found, ingSecrets := lister.IngressMappings(nsName[0]).GetIngToSecret(nsName[1]); found {
+			for _, secret := range ingSecrets {
+				if !strings.Contains(secret, "/") {
+					secret = nsName[0] + "/" + secret
+				}
+				secrets[secret] = true
+			}
+		}
+	}
+	response.Services = sortedKeys(services)
+	response.Secrets = sortedKeys(secrets)
+	response.Gateways = sortedKeys(gateways)
+	sort.Strings(response.Ingresses)
+	sort.Strings(response.Routes)
+	utils.Respond(w, response)
+}
+
+func getVirtualServiceCache(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vsKey := avicache.NamespaceName{Namespace: vars["tenant"], Name: vars["name"]}
+	aviObjCache := avicache.SharedAviObjCache()
+	vsCache, found := aviObjCache.VsCacheMeta.AviCacheGet(vsKey)
+	if !found || vsCache == nil {
+		utils.RespondError(w, http.StatusNotFound, "virtualservice "+vsKey.Namespace+"/"+vsKey.Name+" not found in the cache")
+		return
+	}
+	vsCacheObj, ok := vsCache.(*avicache.AviVsCache)
+	if !ok {
+		utils.RespondError(w, http.StatusInternalServerError, "invalid cache entry for virtualservice "+vsKey.Namespace+"/"+vsKey.Name)
+		return
+	}
+	utils.Respond(w, dumpVirtualServiceCache(aviObjCache, vsCacheObj))
+}
+
+// dumpVirtualServiceCache returns copies of the cache objects of the virtualservice, the copies are made under the
+// locks of the cache objects, as the response is encoded while the rest layer keeps updating the cache.
+func dumpVirtualServiceCache(aviObjCache *avicache.AviObjCache, vsCacheObj *avicache.AviVsCache) VirtualServiceCacheDump {
+	vsCopy, done := vsCacheObj.GetVSCopy()
+	if !done {
+		return VirtualServiceCacheDump{}
+	}
+	dump := VirtualServiceCacheDump{
+		VirtualService: vsCopy,
+		VsVips:         getCacheEntries(aviObjCache.VSVIPCache, vsCopy.VSVipKeyCollection),
+		PoolGroups:     getCacheEntries(aviObjCache.PgCache, vsCopy.PGKeyCollection),
+		Pools:          getCacheEntries(aviObjCache.PoolCache, vsCopy.PoolKeyCollection),
+		SSLKeyCerts:    getCacheEntries(aviObjCache.SSLKeyCache, vsCopy.SSLKeyCertCollection),
+		HTTPPolicySets: getCacheEntries(aviObjCache.HTTPPolicyCache, vsCopy.HTTPKeyCollection),
+		DataScripts:    getCacheEntries(aviObjCache.DSCache, vsCopy.DSKeyCollection),
+		L4PolicySets:   getCacheEntries(aviObjCache.L4PolicyCache, vsCopy.L4PolicyCollection),
+	}
+
+	var pkiKeys, hmKeys []avicache.NamespaceName
+	for _, pool := range dump.Pools {
+		if poolCache, ok := pool.(*avicache.AviPoolCache); ok && poolCache.PkiProfileCollection.Name != "" {
+			pkiKeys = append(pkiKeys, poolCache.PkiProfileCollection)
+		}
+		if poolCache, ok := pool.(*avicache.AviPoolCache); ok && poolCache.HealthMonitorCollection.Name != "" {
+			hmKeys = append(hmKeys, poolCache.HealthMonitorCollection)
+		}
+	}
+	dump.PKIProfiles = getCacheEntries(aviObjCache.PKIProfileCache, pkiKeys)
+	dump.HealthMonitors = getCacheEntries(aviObjCache.HealthMonitorCache, hmKeys)
+
+	for _, childUuid := range vsCopy.SNIChildCollection {
+		childKey, found := aviObjCache.VsCacheMeta.AviCacheGetKeyByUuid(childUuid)
+		if !found {
+			continue
+		}
+		childCache, found := aviObjCache.VsCacheMeta.AviCacheGet(childKey)
+		if !found {
+			continue
+		}
+		if childCacheObj, ok := childCache.(*avicache.AviVsCache); ok {
+			dump.ChildVSes = append(dump.ChildVSes, dumpVirtualServiceCache(aviObjCache, childCacheObj))
+		}
+	}
+	return dump
+}
+
+func getDriftReport(w http.ResponseWriter, r *http.Request) {
+	utils.Respond(w, GetDriftReport())
+}
+
+func getCacheEntries(cache *avicache.AviCache, keys []avicache.NamespaceName) []interface{} {
+	entries := []interface{}{}
+	for _, key := range keys {
+		if entry, found := cache.AviCacheGetCopy(key); found {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"os"
+	"time"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// RunLeaderElection starts the election of the leader among the replicas of the AKO StatefulSet, using the
+// ako-leader Lease in the AKO namespace. Only the leader runs the graph, rest, status and retry layers, the
+// standby replicas keep their informers and the Avi object cache warm, to take over once the Lease expires.
+func (c *AviController) RunLeaderElection(cs kubernetes.Interface, stopCh <-chan struct{}) {
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		identity, _ = os.Hostname()
+	}
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      lib.AKOLeaderElectionLease,
+			Namespace: utils.GetAKONamespace(),
+		},
+		Client: cs.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	c.leaderCh = make(chan struct{})
+	lib.SetLeader(false)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            lib.AKOStatefulSet,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				utils.AviLog.Infof("AKO replica %s is elected as the leader", identity)
+				lib.AKOControlConfig().PodEventf(corev1.EventTypeNormal, lib.AKOLeaderElected, "AKO replica %s is elected as the leader", identity)
+				lib.SetLeader(true)
+				close(c.leaderCh)
+			},
+			OnStoppedLeading: func() {
+				if !lib.IsLeader() {
+					return
+				}
+				select {
+				case <-stopCh:
+					// The Lease is released while shutting down.
+					return
+				default:
+				}
+				// Another replica may be syncing already, hence AKO stops taking up the object updates, and shuts down
+				// the same way as on a SIGTERM, to be restarted as a standby replica.
+				lib.AKOControlConfig().PodEventf(corev1.EventTypeWarning, lib.AKOLeaderLost, "AKO replica %s lost the leadership", identity)
+				utils.AviLog.Warnf("AKO replica %s lost the leadership, shutting down AKO", identity)
+				c.DisableSync = true
+				lib.SetLeader(false)
+				if lib.IsAdmissionWebhookEnabled() && !lib.GetAdvancedL4() {
+					SetAdmissionWebhookReady(cs, false)
+				}
+				utils.RequestShutdown()
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					utils.AviLog.Infof("AKO replica %s is the leader", leader)
+				}
+			},
+		},
+	})
+	if err != nil {
+		utils.AviLog.Fatalf("Error setting up the leader election: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+	go elector.Run(ctx)
+}
+
+// WaitForLeadership blocks a standby replica until it is elected as the leader. The Avi object cache is refreshed
+// periodically meanwhile, so that the replica takes over without populating the cache from scratch, and once more
+// on being elected, as the objects may have been updated by the earlier leader since the last refresh.
+// Returns false if AKO is shutting down.
+func (c *AviController) WaitForLeadership(ctrlCh <-chan struct{}) bool {
+	if c.leaderCh == nil {
+		return true
+	}
+	select {
+	case <-c.leaderCh:
+		return true
+	default:
+	}
+
+	utils.AviLog.Infof("AKO is running as a standby replica, waiting to be elected as the leader")
+	lib.AKOControlConfig().PodEventf(corev1.EventTypeNormal, lib.AKOStandby, "AKO is running as a standby replica")
+	ticker := time.NewTicker(lib.StandbyCacheRefreshInterval * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.leaderCh:
+			refreshStandbyCache()
+			return true
+		case <-ctrlCh:
+			return false
+		case <-ticker.C:
+			refreshStandbyCache()
+		}
+	}
+}
+
+// refreshStandbyCache syncs the Avi object cache incrementally, fetching only the objects created or modified since the
+// last sync.
+func refreshStandbyCache() {
+	aviRestClientPool := avicache.SharedAVIClients()
+	if aviRestClientPool == nil || len(aviRestClientPool.AviClient) == 0 {
+		return
+	}
+	if err := avicache.SharedAviObjCache().AviObjCacheRefresh(aviRestClientPool.AviClient, utils.CloudName); err != nil {
+		utils.AviLog.Warnf("Failed to refresh the avi cache on the standby replica, error: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// OfflineSync builds the models for the objects in the informers, the way the bootup sync of AKO does, without
+// connecting to the Avi controller. The informers are expected to be initialized before the controller, and the Avi
+// object cache to be populated by the caller, if the models have to be built against the existing Avi objects.
+// The models are saved in the graph lister and are not processed by the rest layer. This is used by ako-sim to
+// preview the Avi objects for a set of Kubernetes objects.
+func (c *AviController) OfflineSync(stopCh <-chan struct{}) error {
+	if err := initOfflineConfig(); err != nil {
+		return err
+	}
+	c.DisableSync = false
+	c.offlineSync = true
+
+	initWorkQueues()
+	c.addIndexers()
+	c.AddCrdIndexer()
+	c.Start(stopCh)
+	c.InitializeNamespaceSync()
+	// The full sync picks the objects from the namespaces in the namespace filter, which are otherwise added by the
+	// event handler of the namespaces. All the namespaces are accepted if the namespace sync is disabled.
+	populateNamespaceList()
+
+	// The validation of the CRDs during the first pass updates their status, the models are built again once the
+	// informers have the CRDs with the updated status.
+	if err := c.FullSyncK8s(); err != nil {
+		return err
+	}
+	waitForCRDStatus()
+	return c.FullSyncK8s()
+}
+
+// initOfflineConfig sets the AKO configuration, which is otherwise set while validating the user input against the
+// Avi controller.
+func initOfflineConfig() error {
+	if _, err := lib.IsClusterNameValid(); err != nil {
+		return err
+	}
+	lib.SetNamePrefix()
+	lib.SetAKOUser()
+	lib.SetClusterLabelChecksum()
+
+	if vipList, err := lib.GetVipNetworkListEnv(); err != nil {
+		utils.AviLog.Warnf("Error in getting the VIP networks, the vsvips would not have a VIP network: %v", err)
+	} else {
+		lib.SetVipNetworkList(vipList)
+	}
+
+	seGroupToUse := lib.GetSEGNameEnv()
+	if seGroupToUse == "" {
+		seGroupToUse = lib.DEFAULT_SE_GROUP
+	}
+	lib.SetSEGName(seGroupToUse)
+	return nil
+}
+
+// waitForCRDStatus waits until the informers of the AKO CRDs have the status set by the validation of the CRDs.
+func waitForCRDStatus() {
+	crdInformers := lib.AKOControlConfig().CRDInformers()
+	if crdInformers == nil {
+		return
+	}
+	crdClient := lib.AKOControlConfig().CRDClientset().AkoV1alpha1()
+	crdStatusSynced := func() (bool, error) {
+		clientStatus, listerStatus := make(map[string]string), make(map[string]string)
+		if lib.AKOControlConfig().HostRuleEnabled() {
+			if hostRules, err := crdClient.HostRules("").List(context.TODO(), metav1.ListOptions{}); err == nil {
+				for _, hostRule := range hostRules.Items {
+					clientStatus[lib.HostRule+"/"+utils.ObjKey(&hostRule)] = utils.Stringify(hostRule.Status)
+				}
+			}
+			hostRules, _ := crdInformers.HostRuleInformer.Lister().List(labels.Everything())
+			for _, hostRule := range hostRules {
+				listerStatus[lib.HostRule+"/"+utils.ObjKey(hostRule)] = utils.Stringify(hostRule.Status)
+			}
+		}
+		if lib.AKOControlConfig().HttpRuleEnabled() {
+			if httpRules, err := crdClient.HTTPRules("").List(context.TODO(), metav1.ListOptions{}); err == nil {
+				for _, httpRule := range httpRules.Items {
+					clientStatus[lib.HTTPRule+"/"+utils.ObjKey(&httpRule)] = utils.Stringify(httpRule.Status)
+				}
+			}
+			httpRules, _ := crdInformers.HTTPRuleInformer.Lister().List(labels.Everything())
+			for _, httpRule := range httpRules {
+				listerStatus[lib.HTTPRule+"/"+utils.ObjKey(httpRule)] = utils.Stringify(httpRule.Status)
+			}
+		}
+		if lib.AKOControlConfig().AviInfraSettingEnabled() {
+			if infraSettings, err := crdClient.AviInfraSettings().List(context.TODO(), metav1.ListOptions{}); err == nil {
+				for _, infraSetting := range infraSettings.Items {
+					clientStatus[lib.AviInfraSetting+"/"+infraSetting.Name] = utils.Stringify(infraSetting.Status)
+				}
+			}
+			infraSettings, _ := crdInformers.AviInfraSettingInformer.Lister().List(labels.Everything())
+			for _, infraSetting := range infraSettings {
+				listerStatus[lib.AviInfraSetting+"/"+infraSetting.Name] = utils.Stringify(infraSetting.Status)
+			}
+		}
+		if lib.AKOControlConfig().L4RuleEnabled() {
+			if l4Rules, err := crdClient.L4Rules("").List(context.TODO(), metav1.ListOptions{}); err == nil {
+				for _, l4Rule := range l4Rules.Items {
+					clientStatus[lib.L4Rule+"/"+utils.ObjKey(&l4Rule)] = utils.Stringify(l4Rule.Status)
+				}
+			}
+			l4Rules, _ := crdInformers.L4RuleInformer.Lister().List(labels.Everything())
+			for _, l4Rule := range l4Rules {
+				listerStatus[lib.L4Rule+"/"+utils.ObjKey(l4Rule)] = utils.Stringify(l4Rule.Status)
+			}
+		}
+		return reflect.DeepEqual(clientStatus, listerStatus), nil
+	}
+	if err := wait.PollImmediate(100*time.Millisecond, 10*time.Second, crdStatusSynced); err != nil {
+		utils.AviLog.Warnf("Timed out while waiting for the status of the CRDs in the informers: %v", err)
+	}
+}
@@ -17,6 +17,7 @@ package lib
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"os"
@@ -40,7 +41,12 @@ func AviGetCollectionRaw(client *clients.AviClient, uri string, retryNum ...int)
 		}
 	}
 
-	result, err := client.AviSession.GetCollectionRaw(uri)
+	var result session.AviCollectionResult
+	err := utils.SharedAviRestLimiter().Do(func() error {
+		var err error
+		result, err = client.AviSession.GetCollectionRaw(uri)
+		return err
+	})
 	if err != nil {
 		utils.AviLog.Warnf("msg: Unable to fetch collection data from uri %s %v", uri, err)
 		checkForInvalidCredentials(uri, err)
@@ -64,7 +70,9 @@ func AviGet(client *clients.AviClient, uri string, response interface{}, retryNu
 		}
 	}
 
-	err := client.AviSession.Get(uri, &response)
+	err := utils.SharedAviRestLimiter().Do(func() error {
+		return client.AviSession.Get(uri, &response)
+	})
 	if err != nil {
 		utils.AviLog.Warnf("msg: Unable to fetch data from uri %s %v", uri, err)
 		checkForInvalidCredentials(uri, err)
@@ -89,7 +97,12 @@ func AviGetRaw(client *clients.AviClient, uri string, retryNum ...int) ([]byte,
 		}
 	}
 
-	rawData, err := client.AviSession.GetRaw(uri)
+	var rawData []byte
+	err := utils.SharedAviRestLimiter().Do(func() error {
+		var err error
+		rawData, err = client.AviSession.GetRaw(uri)
+		return err
+	})
 	if err != nil {
 		utils.AviLog.Warnf("msg: Unable to fetch data from uri %s %v", uri, err)
 		checkForInvalidCredentials(uri, err)
@@ -114,7 +127,14 @@ func AviPut(client *clients.AviClient, uri string, payload interface{}, response
 		}
 	}
 
-	err := client.AviSession.Put(uri, payload, &response)
+	if IsDryRun() {
+		recordDryRunRequest("PUT", uri, payload)
+		return ErrDryRun
+	}
+
+	err := utils.SharedAviRestLimiter().Do(func() error {
+		return client.AviSession.Put(uri, payload, &response)
+	})
 	if err != nil {
 		utils.AviLog.Warnf("msg: Unable to execute Put on uri %s %v", uri, err)
 		checkForInvalidCredentials(uri, err)
@@ -139,7 +159,14 @@ func AviPost(client *clients.AviClient, uri string, payload interface{}, respons
 		}
 	}
 
-	err := client.AviSession.Post(uri, payload, &response)
+	if IsDryRun() {
+		recordDryRunRequest("POST", uri, payload)
+		return ErrDryRun
+	}
+
+	err := utils.SharedAviRestLimiter().Do(func() error {
+		return client.AviSession.Post(uri, payload, &response)
+	})
 	if err != nil {
 		utils.AviLog.Warnf("msg: Unable to execute Post on uri %s %v", uri, err)
 		checkForInvalidCredentials(uri, err)
@@ -164,7 +191,14 @@ func AviDelete(client *clients.AviClient, uri string, retryNum ...int) error {
 		}
 	}
 
-	err := client.AviSession.Delete(uri)
+	if IsDryRun() {
+		recordDryRunRequest("DELETE", uri, nil)
+		return ErrDryRun
+	}
+
+	err := utils.SharedAviRestLimiter().Do(func() error {
+		return client.AviSession.Delete(uri)
+	})
 	if err != nil {
 		utils.AviLog.Warnf("msg: Unable to execute Delete on uri %s %v", uri, err)
 		checkForInvalidCredentials(uri, err)
@@ -241,3 +275,29 @@ func NewAviRestClientWithToken(api_ep string, username string, authToken string)
 	SetVersion(aviClient.AviSession)
 	return aviClient
 }
+
+// ErrDryRun is returned by AviPut, AviPost and AviDelete in the dry-run mode, as the request is only recorded and
+// there is no response from the Avi controller.
+var ErrDryRun = errors.New("dry-run, the request is recorded and not executed on the Avi controller")
+
+// recordDryRunRequest records the rest call in place of executing it on the Avi controller, in the dry-run mode.
+func recordDryRunRequest(method, uri string, payload interface{}) {
+	// uri is of the form /api/<object type>/<uuid>
+	parts := strings.Split(strings.Trim(strings.SplitN(uri, "?", 2)[0], "/"), "/")
+	record := apimodels.DryRunRecord{
+		Method: method,
+		Model:  parts[len(parts)-1],
+		Tenant: GetTenant(),
+		Name:   parts[len(parts)-1],
+		Path:   uri,
+		Diff:   []apimodels.DryRunDiff{},
+	}
+	if len(parts) > 2 {
+		record.Model = parts[1]
+	}
+	if payload != nil {
+		record.Object, _ = json.Marshal(payload)
+	}
+	utils.AviLog.Infof("msg: dry-run, skipping %s on uri %s", method, uri)
+	apimodels.DryRun.Record(record)
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package lib
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vmware/alb-sdk/go/models"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+const pemTypeCertificate = "CERTIFICATE"
+
+// decodeCertificateBlocks returns the PEM certificate blocks in data, ignoring the other blocks.
+func decodeCertificateBlocks(data []byte) []*pem.Block {
+	var blocks []*pem.Block
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return blocks
+		}
+		if block.Type == pemTypeCertificate {
+			blocks = append(blocks, block)
+		}
+	}
+}
+
+// SplitCertificateChain splits the certificate of a Secret or a Route into the leaf certificate, and the chain of
+// CA certificates ordered from the issuer of the leaf to the root. The chain is made of the intermediates following
+// the leaf in cert, and of the certificates in cacert (ca.crt of the cert-manager Secrets), without duplicates and
+// without the leaf. cert and cacert which are not PEM encoded are returned as is.
+func SplitCertificateChain(cert, cacert []byte) ([]byte, []string) {
+	var chain []string
+	leaf := cert
+	seen := make(map[string]bool)
+	certBlocks := decodeCertificateBlocks(cert)
+	if len(certBlocks) > 1 {
+		leaf = pem.EncodeToMemory(certBlocks[0])
+	}
+	for i, block := range certBlocks {
+		if i == 0 {
+			seen[string(block.Bytes)] = true
+		} else if !seen[string(block.Bytes)] {
+			seen[string(block.Bytes)] = true
+			chain = append(chain, string(pem.EncodeToMemory(block)))
+		}
+	}
+	caBlocks := decodeCertificateBlocks(cacert)
+	if len(caBlocks) == 0 && len(bytes.TrimSpace(cacert)) != 0 {
+		return leaf, append(chain, string(cacert))
+	}
+	for _, block := range caBlocks {
+		if !seen[string(block.Bytes)] {
+			seen[string(block.Bytes)] = true
+			chain = append(chain, string(pem.EncodeToMemory(block)))
+		}
+	}
+	return leaf, chain
+}
+
+// ParseLeafCertificate parses the first PEM encoded certificate in cert.
+func ParseLeafCertificate(cert []byte) (*x509.Certificate, error) {
+	blocks := decodeCertificateBlocks(cert)
+	if len(blocks) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(blocks[0].Bytes)
+}
+
+// GetCACertChainNodeName returns the name of the CA cert at depth index in the chain of the certificate of the
+// host. The issuer of the certificate, at depth 0, keeps the name of the single CA cert of the earlier releases.
+func GetCACertChainNodeName(infrasetting, sniHostName string, index int) string {
+	if index == 0 {
+		return GetCACertNodeName(infrasetting, sniHostName)
+	}
+	namePrefix := NamePrefix
+	if infrasetting != "" {
+		namePrefix += infrasetting + "-"
+	}
+	keycertname := namePrefix + sniHostName
+	return Encode(fmt.Sprintf("%s-cacert-%d", keycertname, index), CACert)
+}
+
+// OCSPConfigChecksum returns the checksum of the OCSP stapling settings of a SSLKeyAndCertificate, using the
+// fields set by AKO only, as the Avi controller fills in the defaults of the other fields.
+func OCSPConfigChecksum(ocspConfig *models.OCSPConfig) uint32 {
+	if ocspConfig == nil {
+		return 0
+	}
+	var urlAction string
+	var interval int32
+	if ocspConfig.URLAction != nil {
+		urlAction = *ocspConfig.URLAction
+	}
+	if ocspConfig.OcspReqInterval != nil {
+		interval = *ocspConfig.OcspReqInterval
+	}
+	return utils.Hash(fmt.Sprintf("%s:%s:%d", strings.Join(ocspConfig.ResponderURLLists, ","), urlAction, interval))
+}
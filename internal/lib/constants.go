@@ -42,6 +42,48 @@ const (
 	DEFAULT_DOMAIN                             = "DEFAULT_DOMAIN"
 	ADVANCED_L4                                = "ADVANCED_L4"
 	SERVICES_API                               = "SERVICES_API"
+	GATEWAY_API                                = "GATEWAY_API"
+	DRY_RUN                                    = "DRY_RUN"
+	DRY_RUN_FILE                               = "DRY_RUN_FILE"
+	AVI_REST_QPS                               = "AVI_REST_QPS"
+	AVI_REST_MAX_CONCURRENCY                   = "AVI_REST_MAX_CONCURRENCY"
+	DRIFT_DETECTION                            = "DRIFT_DETECTION"
+	DRIFT_SCAN_INTERVAL                        = "DRIFT_SCAN_INTERVAL"
+	DriftDetectionDisabled                     = "Disabled"
+	DriftDetectionAlert                        = "Alert"
+	DriftDetectionRevert                       = "Revert"
+	DefaultDriftScanInterval                   = 300 // Seconds
+	CERT_EXPIRY_ALERT_DAYS                     = "CERT_EXPIRY_ALERT_DAYS"
+	DefaultCertExpiryAlertDays                 = 30
+	CertExpiryCheckInterval                    = 1 // Hours
+	MaxCACertChainDepth                        = 4
+	DefaultOCSPRequestInterval                 = 86400 // Seconds
+	OCSPResponderURLFailover                   = "OCSP_RESPONDER_URL_FAILOVER"
+	OCSPResponderURLOverride                   = "OCSP_RESPONDER_URL_OVERRIDE"
+	SSLClientCertificateRequire                = "SSL_CLIENT_CERTIFICATE_REQUIRE"
+	SSLClientCertificateRequest                = "SSL_CLIENT_CERTIFICATE_REQUEST"
+	ClientAuthCACertKey                        = "ca.crt"
+	ClientAuthCRLKey                           = "ca.crl"
+	TENANTS_PER_NAMESPACE                      = "TENANTS_PER_NAMESPACE"
+	AllTenants                                 = "*"
+	GSLBServiceSyncInterval                    = 60 // Seconds
+	GSLBSharedPoolName                         = "ako-gslb-pool"
+	DefaultGSLBPoolPriority                    = 10
+	GSLBAlgorithmRoundRobin                    = "GSLB_ALGORITHM_ROUND_ROBIN"
+	GSLBAlgorithmGeo                           = "GSLB_ALGORITHM_GEO"
+	GSLBServiceAlgorithmPriority               = "GSLB_SERVICE_ALGORITHM_PRIORITY"
+	GSLBServiceAlgorithmGeo                    = "GSLB_SERVICE_ALGORITHM_GEO"
+	POOL_DRAIN_TIMEOUT                         = "POOL_DRAIN_TIMEOUT"
+	PROBE_HEALTH_MONITOR                       = "PROBE_HEALTH_MONITOR"
+	ADMISSION_WEBHOOK                          = "ADMISSION_WEBHOOK"
+	ADMISSION_WEBHOOK_PORT                     = "ADMISSION_WEBHOOK_PORT"
+	PUBLISH_DNS_ENDPOINTS                      = "PUBLISH_DNS_ENDPOINTS"
+	DefaultAdmissionWebhookPort                = "9443"
+	AdmissionWebhookPath                       = "/validate"
+	AdmissionWebhookCertDir                    = "/etc/ako/webhook"
+	AdmissionWebhookServingLabel               = "ako.vmware.com/admission-webhook"
+	AdmissionWebhookServingLabelValue          = "serving"
+	LEADER_ELECTION                            = "LEADER_ELECTION"
 	CLUSTER_NAME                               = "CLUSTER_NAME"
 	CLUSTER_ID                                 = "CLUSTER_ID"
 	CLOUD_VCENTER                              = "CLOUD_VCENTER"
@@ -58,6 +100,15 @@ const (
 	FAST_RETRY_LAYER                           = "FastRetryLayer"
 	NOT_FOUND                                  = "HTTP code: 404"
 	STATUS_REDIRECT                            = "HTTP_REDIRECT_STATUS_CODE_302"
+	STATUS_REDIRECT_PERMANENT                  = "HTTP_REDIRECT_STATUS_CODE_301"
+	STATUS_REDIRECT_TEMPORARY                  = "HTTP_REDIRECT_STATUS_CODE_307"
+	HTTPAddHeader                              = "HTTP_ADD_HDR"
+	HTTPReplaceHeader                          = "HTTP_REPLACE_HDR"
+	HTTPRemoveHeader                           = "HTTP_REMOVE_HDR"
+	HTTPSecurityActionSendResponse             = "HTTP_SECURITY_ACTION_SEND_RESPONSE"
+	HTTPSecurityActionRateLimit                = "HTTP_SECURITY_ACTION_RATE_LIMIT"
+	HTTPLocalResponseStatusForbidden           = "HTTP_LOCAL_RESPONSE_STATUS_CODE_403"
+	URITokenEndIndex                           = 65535
 	CLOSE_CONNECTION                           = "HTTP_SECURITY_ACTION_CLOSE_CONN"
 	IS_IN                                      = "IS_IN"
 	SLOW_SYNC_TIME                             = 90 // seconds
@@ -89,10 +140,13 @@ const (
 	CertTypeCA                                 = "SSL_CERTIFICATE_TYPE_CA"
 	HostRule                                   = "HostRule"
 	HTTPRule                                   = "HTTPRule"
+	L4Rule                                     = "L4Rule"
 	AviInfraSetting                            = "AviInfraSetting"
 	IstioVirtualService                        = "IstioVirtualService"
 	IstioDestinationRule                       = "DestinationRule"
 	IstioGateway                               = "IstioGateway"
+	IstioVS                                    = "Istio VirtualService"
+	IstioPrefix                                = "istio"
 	MultiClusterIngress                        = "MultiClusterIngress"
 	ServiceImport                              = "ServiceImport"
 	DummySecret                                = "@avisslkeycertrefdummy"
@@ -100,12 +154,30 @@ const (
 	StatusRejected                             = "Rejected"
 	StatusAccepted                             = "Accepted"
 	AllowedApplicationProfile                  = "APPLICATION_PROFILE_TYPE_HTTP"
+	AllowedL4ApplicationProfile                = "APPLICATION_PROFILE_TYPE_L4"
 	TypeTLSReencrypt                           = "reencrypt"
 	DefaultPoolSSLProfile                      = "System-Standard"
 	LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER = "LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER"
 	LB_ALGORITHM_CONSISTENT_HASH               = "LB_ALGORITHM_CONSISTENT_HASH"
+	LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP     = "LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS"
+	LB_ALGORITHM_ROUND_ROBIN                   = "LB_ALGORITHM_ROUND_ROBIN"
+	LB_ALGORITHM_LEAST_CONNECTIONS             = "LB_ALGORITHM_LEAST_CONNECTIONS"
+	LB_ALGORITHM_RANDOM                        = "LB_ALGORITHM_RANDOM"
+	HEALTH_MONITOR_HTTP                        = "HEALTH_MONITOR_HTTP"
+	HEALTH_MONITOR_HTTPS                       = "HEALTH_MONITOR_HTTPS"
+	HEALTH_MONITOR_TCP                         = "HEALTH_MONITOR_TCP"
 	Gateway                                    = "Gateway"
 	GatewayClass                               = "GatewayClass"
+	GatewayAPIGateway                          = "GatewayAPIGateway"
+	GatewayAPIGatewayClass                     = "GatewayAPIGatewayClass"
+	GatewayAPIListener                         = "GatewayAPIListener"
+	HTTPRoute                                  = "HTTPRoute"
+	TLSRoute                                   = "TLSRoute"
+	TCPRoute                                   = "TCPRoute"
+	UDPRoute                                   = "UDPRoute"
+	ReferencePolicy                            = "ReferencePolicy"
+	GatewayAPIVS                               = "Gateway API VirtualService"
+	GatewayAPIPrefix                           = "gwapi"
 	DuplicateBackends                          = "MultipleBackendsWithSameServiceError"
 	DummyVSForStaleData                        = "DummyVSForStaleData"
 	ControllerReqWaitTime                      = 300
@@ -115,6 +187,8 @@ const (
 	ClusterStatusCacheKey                      = "cluster-runtime"
 	AviObjDeletionTime                         = 30 // Minutes
 	AKOStatefulSet                             = "ako"
+	AKOLeaderElectionLease                     = "ako-leader"
+	StandbyCacheRefreshInterval                = 300 // Seconds
 	ObjectDeletionStartStatus                  = "Started"
 	ObjectDeletionDoneStatus                   = "Done"
 	ObjectDeletionTimeoutStatus                = "Timeout"
@@ -124,7 +198,11 @@ const (
 	UpdateStatus                               = "UpdateStatus"
 	DeleteStatus                               = "DeleteStatus"
 	NPLService                                 = "NPLService"
+	SyncStatus                                 = "SyncStatus"
 	SyncStatusKey                              = "syncstatus"
+	DriftStatus                                = "DriftStatus"
+	CertificateStatus                          = "CertificateStatus"
+	DNSEndpointStatus                          = "DNSEndpointStatus"
 	NoFreeIPError                              = "No available free IPs"
 	ConfigDisallowedDuringUpgradeError         = "Configuration is disallowed during upgrade"
 	DataScript                                 = "Vsdatascript"
@@ -135,6 +213,7 @@ const (
 	HTTPRequestRule                            = "HTTP Request Rule"
 	HTTPRedirectRule                           = "HTTP Redirect Rule"
 	HTTPRewriteRule                            = "HTTP Header Rewrite Rule"
+	HTTPResponseRule                           = "HTTP Response Rule"
 	HTTPRedirectPolicy                         = "HTTP Redirect Policy"
 	HeaderRewritePolicy                        = "Header Rewrite Policy"
 	L4VS                                       = "L4 Virtual Service"
@@ -149,6 +228,8 @@ const (
 	PriorityLabel                              = "PriorityLabel"
 	SSLKeyCert                                 = "SSLKeyandCertificate"
 	PKIProfile                                 = "PKI Profile"
+	HealthMonitor                              = "Health Monitor"
+	ApplicationProfile                         = "Application Profile"
 	PassthroughPG                              = "Passthrough PG"
 	Passthroughpool                            = "Passthrough pool"
 	PassthroughVS                              = "Passthrough VirtualService"
@@ -168,6 +249,11 @@ const (
 	SSLPort                                    = 443
 	IPAMProviderInfoblox                       = "IPAMDNS_TYPE_INFOBLOX"
 	IPAMProviderCustom                         = "IPAMDNS_TYPE_CUSTOM"
+	IPTypeV4                                   = "V4"
+	IPTypeV6                                   = "V6"
+	VipIPTypeV4Only                            = "V4_ONLY"
+	VipIPTypeV6Only                            = "V6_ONLY"
+	VipIPTypeDualStack                         = "V4_V6"
 
 	// AKO Event constants
 	AKOEventComponent      = "avi-kubernetes-operator"
@@ -176,12 +262,23 @@ const (
 	ValidatedUserInput     = "ValidatedUserInput"
 	StatusSync             = "StatusSync"
 	AKOReady               = "AKOReady"
+	AKOStandby             = "AKOStandby"
+	AKOLeaderElected       = "AKOLeaderElected"
+	AKOLeaderLost          = "AKOLeaderLost"
 	AKOPause               = "AKOPause"
 	DuplicateHostPath      = "DuplicateHostPath"
 	DuplicateHost          = "DuplicateHost"
 	Removed                = "Removed"
 	Synced                 = "Synced"
 	Attached               = "Attached"
+	SyncFailed             = "SyncFailed"
+	SyncRetrying           = "SyncRetrying"
+	AviObjectDrifted       = "AviObjectDrifted"
+	AviObjectDriftReverted = "AviObjectDriftReverted"
+	TenantChanged          = "TenantChanged"
+	CertificateExpiring    = "CertificateExpiring"
+	CertificateExpired     = "CertificateExpired"
+	SecretNotRotated       = "SecretNotRotated"
 	Detached               = "Detached"
 	AKODeleteConfigSet     = "AKODeleteConfigSet"
 	AKODeleteConfigUnset   = "AKODeleteConfigUnset"
@@ -195,6 +292,7 @@ const (
 	AkoGroup                       = "ako.vmware.com"
 	AviIngressController           = "ako.vmware.com/avi-lb"
 	AKOConditionType               = "ako.vmware.com/ObjectDeletionInProgress"
+	SyncedConditionType            = "ako.vmware.com/Synced"
 	DefaultSecretEnabled           = "ako.vmware.com/enable-tls"
 	GatewayNameLabelKey            = "service.route.lbapi.run.tanzu.vmware.com/gateway-name"
 	GatewayNamespaceLabelKey       = "service.route.lbapi.run.tanzu.vmware.com/gateway-namespace"
@@ -203,10 +301,17 @@ const (
 	SvcApiGatewayNameLabelKey      = "ako.vmware.com/gateway-name"
 	SvcApiGatewayNamespaceLabelKey = "ako.vmware.com/gateway-namespace"
 	SvcApiAviGatewayController     = "ako.vmware.com/avi-lb"
+	GatewayAPIAviGatewayController = "ako.vmware.com/gateway-controller"
+	GatewayAPIGroup                = "gateway.networking.k8s.io"
 	NPLPodAnnotation               = "nodeportlocal.antrea.io"
 	NPLSvcAnnotation               = "nodeportlocal.antrea.io/enabled"
 	InfraSettingNameAnnotation     = "aviinfrasetting.ako.vmware.com/name"
+	L4RuleAnnotation               = "ako.vmware.com/l4rule"
 	SkipNodePortAnnotation         = "skipnodeport.ako.vmware.com/enabled"
+	SkipDriftRevertAnnotation      = "skipdriftrevert.ako.vmware.com/enabled"
+	ProbeHealthMonitorAnnotation   = "probehealthmonitor.ako.vmware.com/enabled"
+	CertManagerCertAnnotation      = "cert-manager.io/certificate-name"
+	TenantAnnotation               = "ako.vmware.com/tenant-name"
 	PassthroughAnnotation          = "passthrough.ako.vmware.com/enabled"
 	StaticRouteAnnotation          = "ako.vmware.com/pod-cidrs"
 	WCPSEGroup                     = "ako.vmware.com/wcp-se-group"
@@ -243,6 +348,11 @@ const (
 	// with a given AviInfraSetting.
 	AviSettingServicesIndex = "aviSettingServices"
 
+	// L4RuleServicesIndex maintains a map of L4Rule Namespace/Name to
+	// Service Objects. This helps in fetching all Services referring to
+	// a given L4Rule.
+	L4RuleServicesIndex = "l4RuleServices"
+
 	// AviSettingIngClassIndex maintains a map of AviInfraSetting Name to
 	// IngressClass Objects. This helps in fetching all IngressClasses with a
 	// given AviinfraSetting Name.
@@ -254,7 +364,7 @@ const (
 	AviSettingRouteIndex = "aviSettingRoute"
 )
 
-//Passthrough deployment same in EVH and SNI. Not changing log messages.
+// Passthrough deployment same in EVH and SNI. Not changing log messages.
 const (
 	PassthroughDatascript = `local avi_tls = require "Default-TLS"
 	buffered = avi.l4.collect(20)
@@ -23,6 +23,8 @@ import (
 
 	istiocrd "istio.io/client-go/pkg/clientset/versioned"
 	istioInformer "istio.io/client-go/pkg/informers/externalversions/networking/v1alpha3"
+	gwapicrd "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned"
+	gwapiinformer "sigs.k8s.io/gateway-api/pkg/client/informers/gateway/externalversions/apis/v1alpha2"
 	svcapi "sigs.k8s.io/service-apis/pkg/client/clientset/versioned"
 	svcInformer "sigs.k8s.io/service-apis/pkg/client/informers/externalversions/apis/v1alpha1"
 
@@ -43,10 +45,21 @@ type ServicesAPIInformers struct {
 	GatewayClassInformer svcInformer.GatewayClassInformer
 }
 
+type GatewayAPIInformers struct {
+	GatewayClassInformer    gwapiinformer.GatewayClassInformer
+	GatewayInformer         gwapiinformer.GatewayInformer
+	HTTPRouteInformer       gwapiinformer.HTTPRouteInformer
+	TLSRouteInformer        gwapiinformer.TLSRouteInformer
+	TCPRouteInformer        gwapiinformer.TCPRouteInformer
+	UDPRouteInformer        gwapiinformer.UDPRouteInformer
+	ReferencePolicyInformer gwapiinformer.ReferencePolicyInformer
+}
+
 type AKOCrdInformers struct {
 	HostRuleInformer        akoinformer.HostRuleInformer
 	HTTPRuleInformer        akoinformer.HTTPRuleInformer
 	AviInfraSettingInformer akoinformer.AviInfraSettingInformer
+	L4RuleInformer          akoinformer.L4RuleInformer
 }
 
 type IstioCRDInformers struct {
@@ -77,6 +90,10 @@ type akoControlConfig struct {
 	istioClientset istiocrd.Interface
 	istioInformers *IstioCRDInformers
 
+	// client-set and informer for v1alpha2 gateway.networking.k8s.io Gateway API.
+	gatewayAPICS        gwapicrd.Interface
+	gatewayAPIInformers *GatewayAPIInformers
+
 	// akoEventRecorder is used to store record.akoEventRecorder
 	// that allows AKO to broadcast kubernetes Events.
 	akoEventRecorder *utils.EventRecorder
@@ -93,6 +110,9 @@ type akoControlConfig struct {
 	// httpRuleEnabled is set to true if the cluster has
 	// HTTPRule CRD installed.
 	httpRuleEnabled bool
+	// l4RuleEnabled is set to true if the cluster has
+	// L4Rule CRD installed.
+	l4RuleEnabled bool
 	// primaryaAKO is set to true/false if as per primaryaAKO value
 	//in values.yaml
 	primaryaAKO bool
@@ -146,6 +166,22 @@ func (c *akoControlConfig) SvcAPIInformers() *ServicesAPIInformers {
 	return c.svcAPIInformers
 }
 
+func (c *akoControlConfig) SetGatewayAPIClientset(cs gwapicrd.Interface) {
+	c.gatewayAPICS = cs
+}
+
+func (c *akoControlConfig) GatewayAPIClientset() gwapicrd.Interface {
+	return c.gatewayAPICS
+}
+
+func (c *akoControlConfig) SetGatewayAPIInformers(i *GatewayAPIInformers) {
+	c.gatewayAPIInformers = i
+}
+
+func (c *akoControlConfig) GatewayAPIInformers() *GatewayAPIInformers {
+	return c.gatewayAPIInformers
+}
+
 func (c *akoControlConfig) SetCRDClientset(cs akocrd.Interface) {
 	c.crdClientset = cs
 	c.SetCRDEnabledParams(cs)
@@ -183,6 +219,15 @@ func (c *akoControlConfig) SetCRDEnabledParams(cs akocrd.Interface) {
 		utils.AviLog.Infof("ako.vmware.com/v1alpha1/HTTPRule enabled on cluster")
 		c.httpRuleEnabled = true
 	}
+
+	_, l4RulesError := cs.AkoV1alpha1().L4Rules(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{TimeoutSeconds: &timeout})
+	if l4RulesError != nil {
+		utils.AviLog.Infof("ako.vmware.com/v1alpha1/L4Rule not found/enabled on cluster: %v", l4RulesError)
+		c.l4RuleEnabled = false
+	} else {
+		utils.AviLog.Infof("ako.vmware.com/v1alpha1/L4Rule enabled on cluster")
+		c.l4RuleEnabled = true
+	}
 }
 
 func (c *akoControlConfig) AviInfraSettingEnabled() bool {
@@ -197,6 +242,10 @@ func (c *akoControlConfig) HttpRuleEnabled() bool {
 	return c.httpRuleEnabled
 }
 
+func (c *akoControlConfig) L4RuleEnabled() bool {
+	return c.l4RuleEnabled
+}
+
 func (c *akoControlConfig) SetIstioClientset(cs istiocrd.Interface) {
 	c.istioClientset = cs
 }
@@ -64,6 +64,13 @@ var (
 		Version:  "v1alpha1",
 		Resource: "namespacenetworkinfos",
 	}
+
+	// DNSEndpointGVR : external-dns's DNSEndpoint CRD resource identifier
+	DNSEndpointGVR = schema.GroupVersionResource{
+		Group:    "externaldns.k8s.io",
+		Version:  "v1alpha1",
+		Resource: "dnsendpoints",
+	}
 )
 
 type BootstrapCRData struct {
@@ -72,8 +79,8 @@ type BootstrapCRData struct {
 
 // NewDynamicClientSet initializes dynamic client set instance
 func NewDynamicClientSet(config *rest.Config) (dynamic.Interface, error) {
-	// do not instantiate the dynamic client set if the CNI being used is NOT calico
-	if GetCNIPlugin() != CALICO_CNI && GetCNIPlugin() != OPENSHIFT_CNI && !utils.IsVCFCluster() {
+	// do not instantiate the dynamic client set if the CNI being used is NOT calico, unless the DNSEndpoints are published
+	if GetCNIPlugin() != CALICO_CNI && GetCNIPlugin() != OPENSHIFT_CNI && !utils.IsVCFCluster() && !IsDNSEndpointPublishEnabled() {
 		return nil, nil
 	}
 
@@ -88,6 +95,10 @@ func NewDynamicClientSet(config *rest.Config) (dynamic.Interface, error) {
 	return dynamicClientSet, nil
 }
 
+func SetDynamicClientSet(dc dynamic.Interface) {
+	dynamicClientSet = dc
+}
+
 // GetDynamicClientSet returns dynamic client set instance
 func GetDynamicClientSet() dynamic.Interface {
 	if dynamicClientSet == nil {
@@ -413,6 +424,9 @@ func GetPodCIDR(node *v1.Node) ([]string, error) {
 				}
 				podCIDRs = append(podCIDRs, cidr)
 			}
+		} else if len(node.Spec.PodCIDRs) > 0 {
+			// dual-stack nodes carry one podCIDR for each of the IPv4 and IPv6 families.
+			podCIDRs = append(podCIDRs, node.Spec.PodCIDRs...)
 		} else {
 			if node.Spec.PodCIDR == "" {
 				utils.AviLog.Errorf("Error in fetching Pod CIDR from NodeSpec %v", node.ObjectMeta.Name)
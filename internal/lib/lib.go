@@ -21,12 +21,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
@@ -41,10 +44,12 @@ import (
 	"github.com/vmware/alb-sdk/go/models"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 var ShardSchemeMap = map[string]string{
@@ -185,7 +190,7 @@ func GetNSXTTransportZone() string {
 	return NsxTTzType
 }
 
-func GetFqdns(vsName, key string, subDomains []string) ([]string, string) {
+func GetFqdns(vsName, key, tenant string, subDomains []string) ([]string, string) {
 	var fqdns []string
 	var fqdn string
 	autoFQDN := true
@@ -207,12 +212,12 @@ func GetFqdns(vsName, key string, subDomains []string) ([]string, string) {
 		}
 		if GetL4FqdnFormat() == AutoFQDNDefault {
 			// Generate the FQDN based on the logic: <svc_name>.<namespace>.<sub-domain>
-			fqdn = vsName + "." + GetTenant() + "." + subdomain
+			fqdn = vsName + "." + tenant + "." + subdomain
 		} else if GetL4FqdnFormat() == AutoFQDNFlat {
 			// Generate the FQDN based on the logic: <svc_name>-<namespace>.<sub-domain>
-			fqdn = vsName + "-" + GetTenant() + "." + subdomain
+			fqdn = vsName + "-" + tenant + "." + subdomain
 		}
-		objects.SharedCRDLister().UpdateFQDNSharedVSModelMappings(fqdn, GetModelName(GetTenant(), vsName))
+		objects.SharedCRDLister().UpdateFQDNSharedVSModelMappings(fqdn, GetModelName(tenant, vsName))
 		utils.AviLog.Infof("key: %s, msg: Configured the shared VS with default fqdn as: %s", key, fqdn)
 		fqdns = append(fqdns, fqdn)
 	}
@@ -394,6 +399,43 @@ func GetHeaderRewritePolicy(vsName, localHost string) string {
 	return headerWriterPolicy
 }
 
+// GetHTTPRulePolicySetName returns the name of the httppolicyset carrying the header, rewrite and redirect
+// actions of the HTTPRule path target for the host.
+func GetHTTPRulePolicySetName(vsName, host, path string) string {
+	path = strings.ReplaceAll(path, "/", "_")
+	return Encode(vsName+"-"+host+path+"-httprule", HTTPPS)
+}
+
+// GetHTTPRuleBackendPolicySetName returns the name of the httppolicyset selecting the pools of the HTTPRule
+// backends with match conditions, for the path target of the host.
+func GetHTTPRuleBackendPolicySetName(vsName, host, path string) string {
+	path = strings.ReplaceAll(path, "/", "_")
+	return Encode(vsName+"-"+host+path+"-httprule-backends", HTTPPS)
+}
+
+// GetHostRuleSecurityPolicySetName returns the name of the httppolicyset carrying the
+// rate limit and client IP security rules of the hostrule applied on the virtualservice.
+func GetHostRuleSecurityPolicySetName(vsName string) string {
+	return Encode(vsName+"-hostrule-security", HTTPPS)
+}
+
+// ParseCIDROrIP returns the address and the prefix length of a CIDR, or of a single IP
+// address, which is treated as a host prefix.
+func ParseCIDROrIP(cidr string) (string, int32, error) {
+	if ip := net.ParseIP(cidr); ip != nil {
+		if ip.To4() != nil {
+			return ip.String(), 32, nil
+		}
+		return ip.String(), 128, nil
+	}
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s is not a valid IP address or CIDR", cidr)
+	}
+	ones, _ := ipNet.Mask.Size()
+	return ip.Mask(ipNet.Mask).String(), int32(ones), nil
+}
+
 func GetSniNodeName(infrasetting, sniHostName string) string {
 	namePrefix := NamePrefix
 	if infrasetting != "" {
@@ -498,6 +540,77 @@ func GetEvhPGName(ingName, namespace, host, path, infrasetting string, dedicated
 	return Encode(evhPG, PG)
 }
 
+// All Istio Gateway object names.
+func GetIstioGatewayVSName(gwName, namespace string) string {
+	return Encode(NamePrefix+IstioPrefix+"-"+namespace+"-"+gwName, IstioVS)
+}
+
+func GetIstioHttpPolName(gwName, namespace string) string {
+	return Encode(NamePrefix+IstioPrefix+"-"+namespace+"-"+gwName, HTTPPS)
+}
+
+func GetIstioPGName(gwName, namespace, vsName, vsNamespace string, routeIndex int) string {
+	pgName := NamePrefix + IstioPrefix + "-" + namespace + "-" + gwName + "-" + vsNamespace + "-" + vsName + "-" + strconv.Itoa(routeIndex)
+	return Encode(pgName, PG)
+}
+
+func GetIstioPoolName(gwName, namespace, vsName, vsNamespace string, routeIndex int, svcName, svcNamespace, subset string, port int32) string {
+	poolName := NamePrefix + IstioPrefix + "-" + namespace + "-" + gwName + "-" + vsNamespace + "-" + vsName + "-" + strconv.Itoa(routeIndex) + "-" + svcNamespace + "-" + svcName
+	if subset != "" {
+		poolName += "-" + subset
+	}
+	poolName += "--" + strconv.Itoa(int(port))
+	return Encode(poolName, Pool)
+}
+
+func GetIstioTLSKeyCertName(gwName, namespace, secretName string) string {
+	return Encode(NamePrefix+IstioPrefix+"-"+namespace+"-"+gwName+"-"+secretName, TLSKeyCert)
+}
+
+// All Gateway API (gateway.networking.k8s.io) object names.
+func GetGatewayAPIVSName(gwName, namespace string) string {
+	return Encode(NamePrefix+GatewayAPIPrefix+"-"+namespace+"-"+gwName, GatewayAPIVS)
+}
+
+func GetGatewayAPIHttpPolName(gwName, namespace string) string {
+	return Encode(NamePrefix+GatewayAPIPrefix+"-"+namespace+"-"+gwName, HTTPPS)
+}
+
+func GetGatewayAPIPGName(gwName, namespace, routeName, routeNamespace string, ruleIndex int) string {
+	pgName := NamePrefix + GatewayAPIPrefix + "-" + namespace + "-" + gwName + "-" + routeNamespace + "-" + routeName + "-" + strconv.Itoa(ruleIndex)
+	return Encode(pgName, PG)
+}
+
+func GetGatewayAPIPoolName(gwName, namespace, routeName, routeNamespace string, ruleIndex int, svcName, svcNamespace string, port int32) string {
+	poolName := NamePrefix + GatewayAPIPrefix + "-" + namespace + "-" + gwName + "-" + routeNamespace + "-" + routeName + "-" + strconv.Itoa(ruleIndex) + "-" + svcNamespace + "-" + svcName + "--" + strconv.Itoa(int(port))
+	return Encode(poolName, Pool)
+}
+
+func GetGatewayAPIL4PoolName(gwName, namespace, protocol string, listenerPort int32, svcName, svcNamespace string, port int32) string {
+	poolName := NamePrefix + GatewayAPIPrefix + "-" + namespace + "-" + gwName + "-" + protocol + "-" + strconv.Itoa(int(listenerPort)) + "-" + svcNamespace + "-" + svcName + "--" + strconv.Itoa(int(port))
+	return Encode(poolName, L4AdvPool)
+}
+
+func GetGatewayAPITLSKeyCertName(gwName, namespace, secretName, secretNamespace string) string {
+	return Encode(NamePrefix+GatewayAPIPrefix+"-"+namespace+"-"+gwName+"-"+secretNamespace+"-"+secretName, TLSKeyCert)
+}
+
+// GatewayAPIParentRefName returns the namespace/name of the Gateway referred by the parentRef,
+// an empty string is returned if the parentRef does not refer to a Gateway.
+func GatewayAPIParentRefName(parentRef gwapiv1alpha2.ParentRef, routeNamespace string) string {
+	if parentRef.Group != nil && string(*parentRef.Group) != GatewayAPIGroup {
+		return ""
+	}
+	if parentRef.Kind != nil && string(*parentRef.Kind) != "Gateway" {
+		return ""
+	}
+	namespace := routeNamespace
+	if parentRef.Namespace != nil && *parentRef.Namespace != "" {
+		namespace = string(*parentRef.Namespace)
+	}
+	return namespace + "/" + string(parentRef.Name)
+}
+
 func IsSecretK8sSecretRef(secret string) bool {
 	re := regexp.MustCompile(fmt.Sprintf(`^%s.*`, DummySecretK8s))
 	if re.MatchString(secret) {
@@ -541,6 +654,29 @@ func GetPoolPKIProfileName(poolName string) string {
 	return Encode(poolName+"-pkiprofile", PKIProfile)
 }
 
+func GetPoolHealthMonitorName(poolName string) string {
+	return Encode(poolName+"-healthmonitor", HealthMonitor)
+}
+
+func GetClientAuthAppProfileName(vsName string) string {
+	return Encode(vsName+"-clientauth", ApplicationProfile)
+}
+
+func GetClientAuthPKIProfileName(vsName string) string {
+	return Encode(vsName+"-clientauth-pkiprofile", PKIProfile)
+}
+
+// ClientCertHeaderVars maps the client certificate fields of the HostRule clientAuth headers to the Avi SSL variables.
+var ClientCertHeaderVars = map[string]string{
+	"Subject":        "HTTP_POLICY_VAR_SSL_CLIENT_SUBJECT",
+	"Issuer":         "HTTP_POLICY_VAR_SSL_CLIENT_ISSUER",
+	"Serial":         "HTTP_POLICY_VAR_SSL_CLIENT_SERIAL",
+	"Fingerprint":    "HTTP_POLICY_VAR_SSL_CLIENT_FINGERPRINT",
+	"NotValidBefore": "HTTP_POLICY_VAR_SSL_CLIENT_NOTVALIDBEFORE",
+	"NotValidAfter":  "HTTP_POLICY_VAR_SSL_CLIENT_NOTVALIDAFTER",
+	"Certificate":    "HTTP_POLICY_VAR_SSL_CLIENT_RAW",
+}
+
 var VRFContext string
 var VRFUuid string
 
@@ -582,6 +718,47 @@ func GetTenant() string {
 	return utils.ADMIN_NS
 }
 
+// IsTenantsPerNamespace returns true if the objects of each namespace are created in the Avi tenant mapped to the
+// namespace, instead of the tenant of AKO.
+func IsTenantsPerNamespace() bool {
+	ok, _ := strconv.ParseBool(os.Getenv(TENANTS_PER_NAMESPACE))
+	return ok
+}
+
+// GetTenantInNamespace returns the Avi tenant in which the objects of the namespace are created. In the tenants per
+// namespace mode, this is the tenant set on the namespace, else the tenant of AKO.
+func GetTenantInNamespace(namespace string) string {
+	if !IsTenantsPerNamespace() || utils.GetInformers().NSInformer == nil {
+		return GetTenant()
+	}
+	nsObj, err := utils.GetInformers().NSInformer.Lister().Get(namespace)
+	if err != nil {
+		return GetTenant()
+	}
+	return GetNamespaceTenant(nsObj)
+}
+
+// GetNamespaceTenant returns the tenant set on the namespace with the ako.vmware.com/tenant-name annotation, or else
+// with the label of the same name. The namespaces without either map to the tenant of AKO.
+func GetNamespaceTenant(nsObj *corev1.Namespace) string {
+	if tenant := nsObj.GetAnnotations()[TenantAnnotation]; tenant != "" {
+		return tenant
+	}
+	if tenant := nsObj.GetLabels()[TenantAnnotation]; tenant != "" {
+		return tenant
+	}
+	return GetTenant()
+}
+
+// GetCacheTenant returns the tenant context in which the Avi objects created by AKO are read from the controller,
+// which covers all the tenants in the tenants per namespace mode.
+func GetCacheTenant() string {
+	if IsTenantsPerNamespace() {
+		return AllTenants
+	}
+	return GetTenant()
+}
+
 func IsIstioEnabled() bool {
 	if ok, _ := strconv.ParseBool(os.Getenv("ISTIO_ENABLED")); ok {
 		utils.AviLog.Debugf("Istio is enabled")
@@ -591,6 +768,22 @@ func IsIstioEnabled() bool {
 	return false
 }
 
+// GetIstioGatewaySelector returns the labels of the Istio ingress gateway workload that AKO replaces,
+// configured as comma separated key=value pairs in ISTIO_GATEWAY_SELECTOR. Only the Istio Gateways whose
+// spec.selector matches these labels are programmed. It defaults to the label of the istio-ingressgateway.
+func GetIstioGatewaySelector() labels.Set {
+	selector := os.Getenv("ISTIO_GATEWAY_SELECTOR")
+	if selector == "" {
+		return labels.Set{"istio": "ingressgateway"}
+	}
+	gwLabels, err := labels.ConvertSelectorToLabelsMap(selector)
+	if err != nil {
+		utils.AviLog.Warnf("Invalid ISTIO_GATEWAY_SELECTOR %s, using the default selector: %v", selector, err)
+		return labels.Set{"istio": "ingressgateway"}
+	}
+	return gwLabels
+}
+
 func GetDefaultIngController() bool {
 	defaultIngCtrl := os.Getenv("DEFAULT_ING_CONTROLLER")
 	if defaultIngCtrl != "false" {
@@ -670,6 +863,56 @@ func GetVipNetworkListEnv() ([]akov1alpha1.AviInfraSettingVipNetwork, error) {
 	return vipNetworkList, nil
 }
 
+// GetVipNetworksIPType returns the ip type of the vips to be allocated from the vip networks,
+// based on the v4 and v6 CIDRs configured for them. An empty string is returned when no v6
+// CIDR is configured, in which case the vips are allocated from the v4 range.
+func GetVipNetworksIPType(vipNetworks []akov1alpha1.AviInfraSettingVipNetwork) string {
+	var v4, v6 bool
+	for _, vipNetwork := range vipNetworks {
+		v4 = v4 || vipNetwork.Cidr != ""
+		v6 = v6 || vipNetwork.V6Cidr != ""
+	}
+	if v4 && v6 {
+		return VipIPTypeDualStack
+	} else if v6 {
+		return VipIPTypeV6Only
+	}
+	return ""
+}
+
+// GetServiceIPType returns the ip type of the vip to be allocated for a Service of type
+// LoadBalancer, based on the ipFamilies and the ipFamilyPolicy of the Service.
+func GetServiceIPType(svc *corev1.Service) string {
+	ipFamilies := svc.Spec.IPFamilies
+	if svc.Spec.IPFamilyPolicy != nil && *svc.Spec.IPFamilyPolicy == corev1.IPFamilyPolicySingleStack && len(ipFamilies) > 1 {
+		ipFamilies = ipFamilies[:1]
+	}
+	var v4, v6 bool
+	for _, ipFamily := range ipFamilies {
+		v4 = v4 || ipFamily == corev1.IPv4Protocol
+		v6 = v6 || ipFamily == corev1.IPv6Protocol
+	}
+	if v4 && v6 {
+		return VipIPTypeDualStack
+	} else if v6 {
+		return VipIPTypeV6Only
+	} else if v4 {
+		return VipIPTypeV4Only
+	}
+	return ""
+}
+
+// GetIPType returns the Avi ip type, V4 or V6, of an address or a CIDR.
+func GetIPType(addr string) string {
+	if ip, _, err := net.ParseCIDR(addr); err == nil {
+		addr = ip.String()
+	}
+	if ip := net.ParseIP(addr); ip != nil && ip.To4() == nil {
+		return IPTypeV6
+	}
+	return IPTypeV4
+}
+
 func GetGlobalBgpPeerLabels() []string {
 	var bgpPeerLabels []string
 	bgpPeerLabelsStr := os.Getenv(BGP_PEER_LABELS)
@@ -844,13 +1087,172 @@ func IsEvhEnabled() bool {
 }
 
 // If this flag is set to true, then AKO uses services API. Currently the support is limited for layer 4 Virtualservices
+// Deprecated: the services API (networking.x-k8s.io/v1alpha1) is superseded by the Gateway API, the flag is
+// ignored when the Gateway API is enabled.
 func UseServicesAPI() bool {
-	if ok, _ := strconv.ParseBool(os.Getenv(SERVICES_API)); ok {
+	if ok, _ := strconv.ParseBool(os.Getenv(SERVICES_API)); ok && !UseGatewayAPI() {
+		return true
+	}
+	return false
+}
+
+// If this flag is set to true, then AKO watches the gateway.networking.k8s.io GatewayClass, Gateway
+// and the HTTPRoute, TLSRoute, TCPRoute and UDPRoute objects attached to the Gateways.
+func UseGatewayAPI() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(GATEWAY_API)); ok && !GetAdvancedL4() {
+		return true
+	}
+	return false
+}
+
+// If this flag is set to true, then AKO records the rest operations for the Avi objects, instead of
+// executing them on the Avi controller.
+func IsDryRun() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(DRY_RUN)); ok {
+		return true
+	}
+	return false
+}
+
+// GetDryRunFilePath returns the file to which the rest operations are recorded in the dry-run mode.
+// Defaults to ako-dryrun.json in the log directory.
+func GetDryRunFilePath() string {
+	if filePath := os.Getenv(DRY_RUN_FILE); filePath != "" {
+		return filePath
+	}
+	return filepath.Join(os.Getenv("LOG_FILE_PATH"), "ako-dryrun.json")
+}
+
+// GetCacheCheckpointFilePath returns the file in which the checkpoint of the avi object cache is persisted, so that
+// the cache is synced incrementally after a restart. The checkpoint is persisted only if USE_PVC is enabled.
+func GetCacheCheckpointFilePath() string {
+	if os.Getenv("USE_PVC") != "true" {
+		return ""
+	}
+	return filepath.Join(os.Getenv("LOG_FILE_PATH"), "ako-cache-checkpoint.json")
+}
+
+// GetAviRestQPS returns the number of rest requests per second AKO sends to the Avi controller, 0 for no limit.
+func GetAviRestQPS() float32 {
+	qps, err := strconv.ParseFloat(os.Getenv(AVI_REST_QPS), 32)
+	if err != nil || qps < 0 {
+		return 0
+	}
+	return float32(qps)
+}
+
+// GetAviRestMaxConcurrency returns the number of concurrent rest requests AKO sends to the Avi controller,
+// 0 for no limit.
+func GetAviRestMaxConcurrency() int {
+	maxConcurrency, err := strconv.Atoi(os.Getenv(AVI_REST_MAX_CONCURRENCY))
+	if err != nil || maxConcurrency < 0 {
+		return 0
+	}
+	return maxConcurrency
+}
+
+// IsLeaderElectionEnabled returns true if the AKO replicas elect a leader using a Lease, so that more than one
+// replica of the AKO StatefulSet can run.
+func IsLeaderElectionEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(LEADER_ELECTION))
+	return enabled
+}
+
+var akoLeader int32
+
+// SetLeader sets whether this AKO replica is the elected leader.
+func SetLeader(leader bool) {
+	var val int32
+	if leader {
+		val = 1
+	}
+	atomic.StoreInt32(&akoLeader, val)
+	utils.SetAKOLeader(leader)
+	utils.AviLog.Infof("Setting the AKO leader flag to: %v", leader)
+}
+
+// IsLeader returns true if this AKO replica syncs the objects to the Avi controller, which is always the case
+// when leader election is disabled.
+func IsLeader() bool {
+	return !IsLeaderElectionEnabled() || atomic.LoadInt32(&akoLeader) == 1
+}
+
+// GetDriftDetectionMode returns whether the Avi objects modified outside of AKO are only reported, or are
+// reverted as well. The drift detection is disabled by default.
+func GetDriftDetectionMode() string {
+	switch mode := os.Getenv(DRIFT_DETECTION); mode {
+	case DriftDetectionAlert, DriftDetectionRevert:
+		return mode
+	}
+	return DriftDetectionDisabled
+}
+
+// GetDriftScanInterval returns the interval in seconds between the scans for the Avi objects modified outside of AKO.
+func GetDriftScanInterval() int {
+	interval, err := strconv.Atoi(os.Getenv(DRIFT_SCAN_INTERVAL))
+	if err != nil || interval <= 0 {
+		return DefaultDriftScanInterval
+	}
+	return interval
+}
+
+// GetCertExpiryAlertDays returns the number of days before the expiry of a certificate served by AKO, from which
+// the certificate is reported as expiring. 0 disables the certificate expiry checks.
+func GetCertExpiryAlertDays() int {
+	days, err := strconv.Atoi(os.Getenv(CERT_EXPIRY_ALERT_DAYS))
+	if err != nil || days < 0 {
+		return DefaultCertExpiryAlertDays
+	}
+	return days
+}
+
+// GetPoolDrainTimeout returns the time in seconds for which the servers of the terminating Pods are kept disabled
+// in the pools, so that the connections to them are drained gracefully. 0 disables the draining.
+func GetPoolDrainTimeout() int {
+	timeout, err := strconv.Atoi(os.Getenv(POOL_DRAIN_TIMEOUT))
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// IsProbeHealthMonitorEnabled returns true if the health monitors can be generated from the probes of the Pods, for
+// the Services and the HTTPRule paths that opt in for them. AKO watches the Pods only when this is enabled.
+func IsProbeHealthMonitorEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(PROBE_HEALTH_MONITOR)); ok {
 		return true
 	}
 	return false
 }
 
+// GetPoolGracefulDisableTimeout returns the graceful disable timeout of the pools in minutes, which the Avi
+// controller waits for before closing the connections to the disabled servers.
+func GetPoolGracefulDisableTimeout() int32 {
+	return int32((GetPoolDrainTimeout() + 59) / 60)
+}
+
+// IsAdmissionWebhookEnabled returns true if AKO serves the validating admission webhook for the HostRule, HTTPRule and
+// AviInfraSetting objects.
+func IsAdmissionWebhookEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(ADMISSION_WEBHOOK))
+	return enabled
+}
+
+// GetAdmissionWebhookPort returns the port of the https server of the admission webhook.
+func GetAdmissionWebhookPort() string {
+	if port := os.Getenv(ADMISSION_WEBHOOK_PORT); port != "" {
+		return port
+	}
+	return DefaultAdmissionWebhookPort
+}
+
+// IsDNSEndpointPublishEnabled returns true if AKO publishes the fqdns of the virtualservices with their vips as the
+// DNSEndpoint objects of external-dns.
+func IsDNSEndpointPublishEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(PUBLISH_DNS_ENDPOINTS))
+	return enabled
+}
+
 // CompareVersions compares version v1 against version v2.
 func CompareVersions(v1, cmpSign, v2 string) bool {
 	if c, err := semver.NewConstraint(cmpSign + v2); err == nil {
@@ -1105,6 +1507,22 @@ func PopulateAdvL4PoolNodeMarkers(namespace, svcName, gatewayName string, port i
 	return markers
 }
 
+func PopulateIstioGatewayNodeMarkers(namespace, gatewayName string) utils.AviObjectMarkers {
+	var markers utils.AviObjectMarkers
+	markers.Namespace = namespace
+	markers.IstioGateway = gatewayName
+	return markers
+}
+
+func PopulateIstioPoolNodeMarkers(namespace, svcName, gatewayName string, port int) utils.AviObjectMarkers {
+	var markers utils.AviObjectMarkers
+	markers.Namespace = namespace
+	markers.IstioGateway = gatewayName
+	markers.ServiceName = svcName
+	markers.Port = strconv.Itoa(port)
+	return markers
+}
+
 func PopulateSvcApiL4PoolNodeMarkers(namespace, svcName, gatewayName, protocol string, port int) utils.AviObjectMarkers {
 	var markers utils.AviObjectMarkers
 	markers.Namespace = namespace
@@ -1153,16 +1571,29 @@ func PopulatePassthroughPoolMarkers(host, svcName string) utils.AviObjectMarkers
 	return markers
 }
 
+// IsEndpointSliceEnabled returns true if the servers of the pools are populated from the EndpointSlices, instead of
+// the Endpoints of the Services.
+func IsEndpointSliceEnabled() bool {
+	return utils.GetInformers().EpSlicesInformer != nil
+}
+
 func InformersToRegister(kclient *kubernetes.Clientset, oclient *oshiftclient.Clientset, akoInfra bool) ([]string, error) {
 	var isOshift bool
 	allInformers := []string{
 		utils.ServiceInformer,
-		utils.EndpointInformer,
 		utils.SecretInformer,
 		utils.ConfigMapInformer,
 		utils.PodInformer,
 	}
 
+	// The EndpointSlices are watched instead of the Endpoints, on the clusters serving the discovery.k8s.io/v1 API.
+	if _, err := kclient.Discovery().ServerResourcesForGroupVersion(discovery.SchemeGroupVersion.String()); err == nil {
+		allInformers = append(allInformers, utils.EndpointSlicesInformer)
+	} else {
+		utils.AviLog.Infof("%s API is not available, watching Endpoints: %v", discovery.SchemeGroupVersion.String(), err)
+		allInformers = append(allInformers, utils.EndpointInformer)
+	}
+
 	if GetServiceType() == NodePortLocal {
 		allInformers = append(allInformers, utils.PodInformer)
 	}
@@ -1234,6 +1665,34 @@ func SSLKeyCertChecksum(sslName, certificate, cacert string, ingestionMarkers ut
 	return checksum
 }
 
+func HealthMonitorChecksum(hmName, hmType, httpRequest string, sendInterval, receiveTimeout, successfulChecks, failedChecks, monitorPort int32, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	checksum := utils.Hash(hmName + hmType + httpRequest)
+	checksum += utils.Hash(utils.Stringify([]int32{sendInterval, receiveTimeout, successfulChecks, failedChecks, monitorPort}))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
+func AppProfileChecksum(appProfileName, clientCertMode, pkiProfileName string, headers []string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	checksum := utils.Hash(appProfileName + clientCertMode + pkiProfileName)
+	if len(headers) > 0 {
+		checksum += utils.Hash(utils.Stringify(headers))
+	}
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
 func L4PolicyChecksum(ports []int64, protocols []string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
 	var portsInt []int
 	for _, port := range ports {
@@ -50,6 +50,16 @@ func ExtractTypeNameNamespace(key string) (string, string, string) {
 	return "", "", segments[0]
 }
 
+// IsSyncStatusObjType returns true for the types of the objects on which the sync status
+// of the virtualservices is reported.
+func IsSyncStatusObjType(objType string) bool {
+	switch objType {
+	case utils.Ingress, utils.OshiftRoute, utils.L4LBService, Gateway:
+		return true
+	}
+	return false
+}
+
 func isServiceLBType(svcObj *corev1.Service) bool {
 	// If we don't find a service or it is not of type loadbalancer - return false.
 	if svcObj.Spec.Type == "LoadBalancer" {
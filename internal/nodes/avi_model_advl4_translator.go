@@ -70,7 +70,7 @@ func (o *AviObjectGraph) ConstructAdvL4VsNode(gatewayName, namespace, key string
 
 		avi_vs_meta := &AviVsNode{
 			Name:       vsName,
-			Tenant:     lib.GetTenant(),
+			Tenant:     lib.GetTenantInNamespace(namespace),
 			VrfContext: lib.GetVrf(),
 			ServiceMetadata: lib.ServiceMetadataObj{
 				NamespaceServiceName: serviceNSNames,
@@ -114,7 +114,7 @@ func (o *AviObjectGraph) ConstructAdvL4VsNode(gatewayName, namespace, key string
 
 		vsVipNode := &AviVSVIPNode{
 			Name:        lib.GetL4VSVipName(gatewayName, namespace),
-			Tenant:      lib.GetTenant(),
+			Tenant:      avi_vs_meta.Tenant,
 			VrfContext:  lib.GetVrf(),
 			VipNetworks: lib.GetVipNetworkList(),
 		}
@@ -184,7 +184,7 @@ func (o *AviObjectGraph) ConstructSvcApiL4VsNode(gatewayName, namespace, key str
 
 		avi_vs_meta := &AviVsNode{
 			Name:       vsName,
-			Tenant:     lib.GetTenant(),
+			Tenant:     lib.GetTenantInNamespace(namespace),
 			VrfContext: lib.GetVrf(),
 			ServiceMetadata: lib.ServiceMetadataObj{
 				Gateway:   namespace + "/" + gatewayName,
@@ -227,7 +227,7 @@ func (o *AviObjectGraph) ConstructSvcApiL4VsNode(gatewayName, namespace, key str
 
 		vsVipNode := &AviVSVIPNode{
 			Name:        lib.GetL4VSVipName(gatewayName, namespace),
-			Tenant:      lib.GetTenant(),
+			Tenant:      avi_vs_meta.Tenant,
 			VrfContext:  lib.GetVrf(),
 			FQDNs:       fqdns,
 			VipNetworks: lib.GetVipNetworkList(),
@@ -315,7 +315,7 @@ func (o *AviObjectGraph) ConstructAdvL4PolPoolNodes(vsNode *AviVsNode, gwName, n
 		}
 		poolNode := &AviPoolNode{
 			Name:     poolName,
-			Tenant:   lib.GetTenant(),
+			Tenant:   vsNode.Tenant,
 			Protocol: portProto[0],
 			PortName: "",
 			ServiceMetadata: lib.ServiceMetadataObj{
@@ -379,7 +379,7 @@ func (o *AviObjectGraph) ConstructAdvL4PolPoolNodes(vsNode *AviVsNode, gwName, n
 
 	l4policyNode := &AviL4PolicyNode{
 		Name:       vsNode.Name,
-		Tenant:     lib.GetTenant(),
+		Tenant:     vsNode.Tenant,
 		PortPool:   portPoolSet,
 		AviMarkers: lib.PopulateAdvL4VSNodeMarkers(namespace, gwName),
 	}
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"sort"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// endpointAddress is an address of a Service endpoint, along with the Pod backing it.
+type endpointAddress struct {
+	ip      string
+	podName string
+}
+
+// getServiceEndpointSlices returns the EndpointSlices of the Service, sorted by name.
+func getServiceEndpointSlices(namespace, serviceName string) ([]*discovery.EndpointSlice, error) {
+	selector := labels.SelectorFromSet(labels.Set{discovery.LabelServiceName: serviceName})
+	epSlices, err := utils.GetInformers().EpSlicesInformer.Lister().EndpointSlices(namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(epSlices, func(i, j int) bool {
+		return epSlices[i].Name < epSlices[j].Name
+	})
+	return epSlices, nil
+}
+
+// getServiceAddressTypes returns the address types of the EndpointSlices used for the pools of the Service. The
+// dual-stack Services have slices per IP family, and the endpoints of both the families are added to the pools.
+func getServiceAddressTypes(namespace, serviceName string) map[discovery.AddressType]bool {
+	addressTypes := make(map[discovery.AddressType]bool)
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(serviceName)
+	if err == nil {
+		for _, ipFamily := range svcObj.Spec.IPFamilies {
+			if ipFamily == corev1.IPv6Protocol {
+				addressTypes[discovery.AddressTypeIPv6] = true
+			} else if ipFamily == corev1.IPv4Protocol {
+				addressTypes[discovery.AddressTypeIPv4] = true
+			}
+		}
+	}
+	if len(addressTypes) == 0 {
+		addressTypes[discovery.AddressTypeIPv4] = true
+	}
+	return addressTypes
+}
+
+// getServicePortCount returns the number of ports of the Service.
+func getServicePortCount(namespace, serviceName string) int {
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(serviceName)
+	if err != nil {
+		return 0
+	}
+	return len(svcObj.Spec.Ports)
+}
+
+// matchEndpointSlicePort returns the port of the slice serving the pool.
+func matchEndpointSlicePort(poolNode *AviPoolNode, epSlice *discovery.EndpointSlice, singlePort bool) (int32, bool) {
+	for _, epp := range epSlice.Ports {
+		if epp.Port == nil {
+			continue
+		}
+		if (epp.Name != nil && poolNode.PortName == *epp.Name) || int32(poolNode.TargetPort.IntValue()) == *epp.Port {
+			return *epp.Port, true
+		}
+	}
+	if singlePort && len(epSlice.Ports) == 1 && epSlice.Ports[0].Port != nil {
+		// If it's just a single port then we make that as the server port.
+		return *epSlice.Ports[0].Port, true
+	}
+	return 0, false
+}
+
+// PopulateServersFromEndpointSlices returns the servers of the pool from the EndpointSlices of the Service. The ready
+// endpoints are added as the servers. The terminating endpoints are added as disabled servers until the drain
+// timeout, if the draining is enabled. The servers are rebuilt from all the slices of the Service, which are read from
+// the informer cache, on an update to any of the slices, and the unchanged pools are skipped by the model checksum.
+func PopulateServersFromEndpointSlices(poolNode *AviPoolNode, ns string, serviceName string, key string) []AviPoolMetaServer {
+	epSlices, err := getServiceEndpointSlices(ns, serviceName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpointslices: %s", key, err)
+		return nil
+	}
+	addressTypes := getServiceAddressTypes(ns, serviceName)
+	singlePort := getServicePortCount(ns, serviceName) == 1
+	drainEnabled := lib.GetPoolDrainTimeout() > 0
+	// The servers of the terminating Pods are drained before they are removed from the pool.
+	poolKey := poolNode.Tenant + "/" + poolNode.Name
+	drainResyncKey := utils.Endpoints + "/" + ns + "/" + serviceName
+
+	var poolMeta []AviPoolMetaServer
+	// An endpoint may be present in two slices while it is moved across the slices.
+	seen := make(map[string]bool)
+	for _, epSlice := range epSlices {
+		if !addressTypes[epSlice.AddressType] {
+			continue
+		}
+		atype := "V4"
+		if epSlice.AddressType == discovery.AddressTypeIPv6 {
+			atype = "V6"
+		}
+		port, portMatch := matchEndpointSlicePort(poolNode, epSlice, singlePort)
+		if !portMatch {
+			continue
+		}
+		poolNode.Port = port
+		for _, ep := range epSlice.Endpoints {
+			if len(ep.Addresses) == 0 {
+				continue
+			}
+			ready := ep.Conditions.Ready == nil || *ep.Conditions.Ready
+			terminating := ep.Conditions.Terminating != nil && *ep.Conditions.Terminating
+			if !ready && !(terminating && drainEnabled) {
+				continue
+			}
+			// The addresses of an endpoint are fungible, the first one is used.
+			ip := ep.Addresses[0]
+			if seen[ip] {
+				continue
+			}
+			seen[ip] = true
+			server := AviPoolMetaServer{Ip: avimodels.IPAddr{Type: &atype, Addr: &ip}}
+			if ep.NodeName != nil {
+				server.ServerNode = *ep.NodeName
+			}
+			// The zones of the nodes are the availability zones of the public clouds. The topology hints are meant for
+			// kube-proxy, and are not applicable to the Avi Service Engines, which are outside the cluster.
+			if ep.Zone != nil && lib.IsPublicCloud() {
+				server.AvailabilityZone = *ep.Zone
+			}
+			if drainEnabled {
+				server.Draining = terminating
+				if ep.TargetRef != nil && ep.TargetRef.Kind == utils.Pod {
+					if !drainPodServer(&server, ns, ep.TargetRef.Name, drainResyncKey, key) {
+						continue
+					}
+					drainTracker.recordServer(poolKey, server, ns, ep.TargetRef.Name)
+				}
+			}
+			poolMeta = append(poolMeta, server)
+		}
+	}
+	if drainEnabled {
+		poolMeta = drainTracker.drainRemovedServers(poolKey, poolMeta, drainResyncKey, key)
+	}
+	utils.AviLog.Infof("key: %s, msg: servers for port: %v, are: %v", key, poolNode.Port, utils.Stringify(poolMeta))
+	return poolMeta
+}
+
+// getServiceEndpointAddresses returns the ready addresses of the Service endpoints, from the EndpointSlices or the
+// Endpoints of the Service.
+func getServiceEndpointAddresses(namespace, serviceName string) ([]endpointAddress, error) {
+	var addresses []endpointAddress
+	if lib.IsEndpointSliceEnabled() {
+		epSlices, err := getServiceEndpointSlices(namespace, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		for _, epSlice := range epSlices {
+			for _, ep := range epSlice.Endpoints {
+				if len(ep.Addresses) == 0 || (ep.Conditions.Ready != nil && !*ep.Conditions.Ready) {
+					continue
+				}
+				address := endpointAddress{ip: ep.Addresses[0]}
+				if ep.TargetRef != nil && ep.TargetRef.Kind == utils.Pod {
+					address.podName = ep.TargetRef.Name
+				}
+				addresses = append(addresses, address)
+			}
+		}
+		return addresses, nil
+	}
+
+	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(namespace).Get(serviceName)
+	if err != nil {
+		return nil, err
+	}
+	for _, ss := range epObj.Subsets {
+		for _, addr := range ss.Addresses {
+			address := endpointAddress{ip: addr.IP}
+			if addr.TargetRef != nil && addr.TargetRef.Kind == utils.Pod {
+				address.podName = addr.TargetRef.Name
+			}
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
@@ -40,6 +40,8 @@ type AviVsEvhSniModel interface {
 	GetName() string
 	SetName(string)
 
+	GetTenant() string
+
 	IsSharedVS() bool
 	IsDedicatedVS() bool
 
@@ -91,6 +93,12 @@ type AviVsEvhSniModel interface {
 	GetAnalyticsPolicy() *avimodels.AnalyticsPolicy
 	SetAnalyticsPolicy(*avimodels.AnalyticsPolicy)
 
+	GetConnectionsRateLimit() *avimodels.RateProfile
+	SetConnectionsRateLimit(*avimodels.RateProfile)
+
+	GetClientAuthAppProfile() *AviAppProfileNode
+	SetClientAuthAppProfile(*AviAppProfileNode)
+
 	GetVSVIPLoadBalancerIP() string
 	SetVSVIPLoadBalancerIP(string)
 
@@ -106,41 +114,43 @@ type AviEvhVsNode struct {
 	EvhHostName   string
 	AviMarkers    utils.AviObjectMarkers
 	// props from avi vs node
-	Name                string
-	Tenant              string
-	ServiceEngineGroup  string
-	ApplicationProfile  string
-	NetworkProfile      string
-	EnableRhi           *bool
-	Enabled             *bool
-	PortProto           []AviPortHostProtocol // for listeners
-	DefaultPool         string
-	CloudConfigCksum    uint32
-	DefaultPoolGroup    string
-	HTTPChecksum        uint32
-	PoolGroupRefs       []*AviPoolGroupNode
-	PoolRefs            []*AviPoolNode
-	HTTPDSrefs          []*AviHTTPDataScriptNode
-	SharedVS            bool
-	CACertRefs          []*AviTLSKeyCertNode
-	SSLKeyCertRefs      []*AviTLSKeyCertNode
-	HttpPolicyRefs      []*AviHttpPolicySetNode
-	VSVIPRefs           []*AviVSVIPNode
-	TLSType             string
-	ServiceMetadata     lib.ServiceMetadataObj
-	VrfContext          string
-	WafPolicyRef        string
-	AppProfileRef       string
-	AnalyticsProfileRef string
-	ErrorPageProfileRef string
-	HttpPolicySetRefs   []string
-	VsDatascriptRefs    []string
-	SSLProfileRef       string
-	SSLKeyCertAviRef    []string
-	Paths               []string
-	IngressNames        []string
-	AnalyticsPolicy     *avimodels.AnalyticsPolicy
-	Dedicated           bool
+	Name                 string
+	Tenant               string
+	ServiceEngineGroup   string
+	ApplicationProfile   string
+	NetworkProfile       string
+	EnableRhi            *bool
+	Enabled              *bool
+	PortProto            []AviPortHostProtocol // for listeners
+	DefaultPool          string
+	CloudConfigCksum     uint32
+	DefaultPoolGroup     string
+	HTTPChecksum         uint32
+	PoolGroupRefs        []*AviPoolGroupNode
+	PoolRefs             []*AviPoolNode
+	HTTPDSrefs           []*AviHTTPDataScriptNode
+	SharedVS             bool
+	CACertRefs           []*AviTLSKeyCertNode
+	SSLKeyCertRefs       []*AviTLSKeyCertNode
+	HttpPolicyRefs       []*AviHttpPolicySetNode
+	VSVIPRefs            []*AviVSVIPNode
+	TLSType              string
+	ServiceMetadata      lib.ServiceMetadataObj
+	VrfContext           string
+	WafPolicyRef         string
+	AppProfileRef        string
+	AnalyticsProfileRef  string
+	ErrorPageProfileRef  string
+	HttpPolicySetRefs    []string
+	VsDatascriptRefs     []string
+	SSLProfileRef        string
+	SSLKeyCertAviRef     []string
+	Paths                []string
+	IngressNames         []string
+	AnalyticsPolicy      *avimodels.AnalyticsPolicy
+	ConnectionsRateLimit *avimodels.RateProfile
+	ClientAuthAppProfile *AviAppProfileNode
+	Dedicated            bool
 }
 
 // Implementing AviVsEvhSniModel
@@ -153,6 +163,10 @@ func (v *AviEvhVsNode) SetName(name string) {
 	v.Name = name
 }
 
+func (v *AviEvhVsNode) GetTenant() string {
+	return v.Tenant
+}
+
 func (v *AviEvhVsNode) IsSharedVS() bool {
 	return v.SharedVS
 }
@@ -289,6 +303,22 @@ func (v *AviEvhVsNode) SetAnalyticsPolicy(policy *avimodels.AnalyticsPolicy) {
 	v.AnalyticsPolicy = policy
 }
 
+func (v *AviEvhVsNode) GetConnectionsRateLimit() *avimodels.RateProfile {
+	return v.ConnectionsRateLimit
+}
+
+func (v *AviEvhVsNode) SetConnectionsRateLimit(rateLimit *avimodels.RateProfile) {
+	v.ConnectionsRateLimit = rateLimit
+}
+
+func (v *AviEvhVsNode) GetClientAuthAppProfile() *AviAppProfileNode {
+	return v.ClientAuthAppProfile
+}
+
+func (v *AviEvhVsNode) SetClientAuthAppProfile(appProfile *AviAppProfileNode) {
+	v.ClientAuthAppProfile = appProfile
+}
+
 func (v *AviEvhVsNode) GetVSVIPLoadBalancerIP() string {
 	if len(v.VSVIPRefs) > 0 {
 		return v.VSVIPRefs[0].IPAddress
@@ -431,6 +461,13 @@ func (o *AviEvhVsNode) DeleteCACertRefInEVHNode(cacertNodeName, key string) {
 	}
 }
 
+// DeleteCACertChainInEVHNode deletes the CA certs of the chain of the keycert of the host.
+func (o *AviEvhVsNode) DeleteCACertChainInEVHNode(infraSettingName, host, key string) {
+	for i := 0; i < lib.MaxCACertChainDepth; i++ {
+		o.DeleteCACertRefInEVHNode(lib.GetCACertChainNodeName(infraSettingName, host, i), key)
+	}
+}
+
 func (o *AviEvhVsNode) ReplaceCACertRefInEVHNode(cacertNode *AviTLSKeyCertNode, key string) {
 	for i, cacert := range o.CACertRefs {
 		if cacert.Name == cacertNode.Name {
@@ -477,7 +514,7 @@ func (vsNode *AviEvhVsNode) AddSSLPort(key string) {
 	vsNode.PortProto = append(vsNode.PortProto, httpsPort)
 }
 
-//TODO: Next PR Opt: make part of Avivsevhsni model interface
+// TODO: Next PR Opt: make part of Avivsevhsni model interface
 func (vsNode *AviEvhVsNode) DeleteSSLPort(key string) {
 	for i, port := range vsNode.PortProto {
 		if port.Port == lib.SSLPort {
@@ -486,7 +523,7 @@ func (vsNode *AviEvhVsNode) DeleteSSLPort(key string) {
 	}
 }
 
-//TODO: Next PR opt: make part of Avivs model interface
+// TODO: Next PR opt: make part of Avivs model interface
 func (vsNode *AviEvhVsNode) DeletSSLRefInDedicatedNode(key string) {
 	vsNode.SSLKeyCertRefs = []*AviTLSKeyCertNode{}
 	vsNode.SSLProfileRef = ""
@@ -654,6 +691,10 @@ func (v *AviEvhVsNode) CalculateCheckSum() {
 		checksum += lib.GetAnalyticsPolicyChecksum(v.AnalyticsPolicy)
 	}
 
+	if v.ConnectionsRateLimit != nil {
+		checksum += utils.Hash(utils.Stringify(v.ConnectionsRateLimit))
+	}
+
 	v.CloudConfigCksum = checksum
 }
 
@@ -715,7 +756,7 @@ func (o *AviObjectGraph) ConstructAviL7SharedVsNodeForEvh(vsName, key string, ro
 	// Default case
 	avi_vs_meta := &AviEvhVsNode{
 		Name:               vsName,
-		Tenant:             lib.GetTenant(),
+		Tenant:             lib.GetTenantInNamespace(routeIgrObj.GetNamespace()),
 		ServiceEngineGroup: lib.GetSEGName(),
 		PortProto: []AviPortHostProtocol{
 			{Port: 80, Protocol: utils.HTTP},
@@ -745,12 +786,12 @@ func (o *AviObjectGraph) ConstructAviL7SharedVsNodeForEvh(vsName, key string, ro
 	o.AddModelNode(avi_vs_meta)
 
 	subDomains := GetDefaultSubDomain()
-	fqdns, fqdn := lib.GetFqdns(vsName, key, subDomains)
+	fqdns, fqdn := lib.GetFqdns(vsName, key, avi_vs_meta.Tenant, subDomains)
 	configuredSharedVSFqdn := fqdn
 
 	vsVipNode := &AviVSVIPNode{
 		Name:        lib.GetVsVipName(vsName),
-		Tenant:      lib.GetTenant(),
+		Tenant:      avi_vs_meta.Tenant,
 		FQDNs:       fqdns,
 		VrfContext:  vrfcontext,
 		VipNetworks: lib.GetVipNetworkList(),
@@ -801,7 +842,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForEVH(vsNode []*AviEvhVsNode, childN
 		}
 	}
 	if policyNode == nil {
-		policyNode = &AviHttpPolicySetNode{Name: httppolname, Tenant: lib.GetTenant()}
+		policyNode = &AviHttpPolicySetNode{Name: httppolname, Tenant: childNode.Tenant}
 		childNode.HttpPolicyRefs = append(childNode.HttpPolicyRefs, policyNode)
 	}
 
@@ -828,7 +869,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForEVH(vsNode []*AviEvhVsNode, childN
 		// In that case, make sure we are creating only one PG per path
 		pgNode, pgfound := localPGList[pgName]
 		if !pgfound {
-			pgNode = &AviPoolGroupNode{Name: pgName, Tenant: lib.GetTenant()}
+			pgNode = &AviPoolGroupNode{Name: pgName, Tenant: childNode.Tenant}
 			localPGList[pgName] = pgNode
 			httpPGPath.PoolGroup = pgNode.Name
 			httpPGPath.Host = allFqdns
@@ -839,7 +880,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForEVH(vsNode []*AviEvhVsNode, childN
 		poolNode := &AviPoolNode{
 			Name:       poolName,
 			PortName:   path.PortName,
-			Tenant:     lib.GetTenant(),
+			Tenant:     childNode.Tenant,
 			VrfContext: lib.GetVrf(),
 			Port:       path.Port,
 			TargetPort: intstr.FromInt(int(path.TargetPort)),
@@ -953,7 +994,7 @@ func ProcessInsecureHostsForEVH(routeIgrObj RouteIngressModel, key string, parse
 		hostsMap[host].PathSvc = getPathSvc(pathsvcmap.ingressHPSvc)
 
 		_, shardVsName := DeriveShardVSForEvh(host, key, routeIgrObj)
-		modelName := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
 		if !found || aviModel == nil {
 			utils.AviLog.Infof("key: %s, msg: model not found, generating new model with name: %s", key, modelName)
@@ -1010,7 +1051,7 @@ func (o *AviObjectGraph) BuildModelGraphForInsecureEVH(routeIgrObj RouteIngressM
 			evhNode = &AviEvhVsNode{
 				Name:         evhNodeName,
 				VHParentName: vsNode[0].Name,
-				Tenant:       lib.GetTenant(),
+				Tenant:       vsNode[0].Tenant,
 				EVHParent:    false,
 				EvhHostName:  host,
 				ServiceMetadata: lib.ServiceMetadataObj{
@@ -1103,21 +1144,15 @@ func (o *AviObjectGraph) BuildModelGraphForInsecureEVH(routeIgrObj RouteIngressM
 
 // secure ingress graph functions
 
-// BuildCACertNode : Build a new node to store CA cert, this would be referred by the corresponding keycert
-func (o *AviObjectGraph) BuildCACertNodeForEvh(tlsNode *AviEvhVsNode, cacert, infraSettingName, host, key string) string {
-	cacertNode := &AviTLSKeyCertNode{Name: lib.GetCACertNodeName(infraSettingName, host), Tenant: lib.GetTenant()}
-	cacertNode.Type = lib.CertTypeCA
-	cacertNode.Cert = []byte(cacert)
-	cacertNode.AviMarkers = lib.PopulateTLSKeyCertNode(host, infraSettingName)
-	if tlsNode.CheckCACertNodeNameNChecksum(cacertNode.Name, cacertNode.GetCheckSum()) {
-		if len(tlsNode.CACertRefs) == 1 {
-			tlsNode.CACertRefs[0] = cacertNode
-			utils.AviLog.Warnf("key: %s, msg: duplicate cacerts detected for %s, overwriting", key, cacertNode.Name)
-		} else {
-			tlsNode.ReplaceCACertRefInEVHNode(cacertNode, key)
-		}
+// BuildCACertNodeForEvh : Build the nodes to store the CA certs of the chain, these would be referred by the corresponding keycert.
+// The nodes are added root first, so that the CA certs are created before the CA certs referring to them.
+func (o *AviObjectGraph) BuildCACertNodeForEvh(tlsNode *AviEvhVsNode, chain []string, infraSettingName, host, key string) string {
+	cacertNodes := buildCACertChainNodes(chain, tlsNode.Tenant, infraSettingName, host, key)
+	tlsNode.DeleteCACertChainInEVHNode(infraSettingName, host, key)
+	for i := len(cacertNodes) - 1; i >= 0; i-- {
+		tlsNode.CACertRefs = append(tlsNode.CACertRefs, cacertNodes[i])
 	}
-	return cacertNode.Name
+	return cacertNodes[0].Name
 }
 
 func (o *AviObjectGraph) BuildTlsCertNodeForEvh(svcLister *objects.SvcLister, tlsNode *AviEvhVsNode, namespace string, tlsData TlsSettings, key, infraSettingName, host string) bool {
@@ -1153,7 +1188,7 @@ func (o *AviObjectGraph) BuildTlsCertNodeForEvh(svcLister *objects.SvcLister, tl
 	if !foundTLSKeyCertNode {
 		certNode = &AviTLSKeyCertNode{
 			Name:   lib.GetTLSKeyCertNodeName(infraSettingName, host, tlsData.SecretName),
-			Tenant: lib.GetTenant(),
+			Tenant: tlsNode.Tenant,
 			Type:   lib.CertTypeVS,
 		}
 		certNode.AviMarkers = lib.PopulateTLSKeyCertNode(host, infraSettingName)
@@ -1163,12 +1198,14 @@ func (o *AviObjectGraph) BuildTlsCertNodeForEvh(svcLister *objects.SvcLister, tl
 	// Routes can refer to secrets only in case of using default secret in ako NS or using hostrule secret.
 	if strings.HasPrefix(secretName, lib.RouteSecretsPrefix) {
 		if tlsData.cert != "" && tlsData.key != "" {
-			certNode.Cert = []byte(tlsData.cert)
+			cert, chain := lib.SplitCertificateChain([]byte(tlsData.cert), []byte(tlsData.cacert))
+			certNode.Cert = cert
 			certNode.Key = []byte(tlsData.key)
-			if tlsData.cacert != "" {
-				certNode.CACert = o.BuildCACertNodeForEvh(tlsNode, tlsData.cacert, infraSettingName, host, key)
+			if len(chain) > 0 {
+				certNode.CACert = o.BuildCACertNodeForEvh(tlsNode, chain, infraSettingName, host, key)
 			} else {
-				tlsNode.DeleteCACertRefInEVHNode(lib.GetCACertNodeName(infraSettingName, host), key)
+				certNode.CACert = ""
+				tlsNode.DeleteCACertChainInEVHNode(infraSettingName, host, key)
 			}
 		} else {
 			ok, _ := svcLister.IngressMappings(namespace).GetSecretToIng(secretName)
@@ -1195,9 +1232,7 @@ func (o *AviObjectGraph) BuildTlsCertNodeForEvh(svcLister *objects.SvcLister, tl
 		}
 		keycertMap := secretObj.Data
 		cert, ok := keycertMap[utils.K8S_TLS_SECRET_CERT]
-		if ok {
-			certNode.Cert = cert
-		} else {
+		if !ok {
 			utils.AviLog.Infof("key: %s, msg: certificate not found for secret: %s", key, secretObj.Name)
 			return false
 		}
@@ -1208,6 +1243,17 @@ func (o *AviObjectGraph) BuildTlsCertNodeForEvh(svcLister *objects.SvcLister, tl
 			utils.AviLog.Infof("key: %s, msg: key not found for secret: %s", key, secretObj.Name)
 			return false
 		}
+		// The intermediates following the certificate in tls.crt, and ca.crt of the cert-manager Secrets are
+		// uploaded as CA certs.
+		var chain []string
+		certNode.Cert, chain = lib.SplitCertificateChain(cert, keycertMap[utils.K8S_TLS_SECRET_CA])
+		if len(chain) > 0 {
+			certNode.CACert = o.BuildCACertNodeForEvh(tlsNode, chain, infraSettingName, host, key)
+		} else {
+			certNode.CACert = ""
+			tlsNode.DeleteCACertChainInEVHNode(infraSettingName, host, key)
+		}
+		certNode.Secret = secretNS + "/" + secretName
 		altCert, ok := keycertMap[utils.K8S_TLS_SECRET_ALT_CERT]
 		if ok {
 			altKey, ok := keycertMap[utils.K8S_TLS_SECRET_ALT_CERT]
@@ -1224,7 +1270,7 @@ func (o *AviObjectGraph) BuildTlsCertNodeForEvh(svcLister *objects.SvcLister, tl
 				if !foundTLSKeyCertNode {
 					altCertNode = &AviTLSKeyCertNode{
 						Name:       lib.GetTLSKeyCertNodeName(infraSettingName, host, tlsData.SecretName+"-alt"),
-						Tenant:     lib.GetTenant(),
+						Tenant:     tlsNode.Tenant,
 						Type:       lib.CertTypeVS,
 						AviMarkers: certNode.AviMarkers,
 						Cert:       altCert,
@@ -1235,6 +1281,10 @@ func (o *AviObjectGraph) BuildTlsCertNodeForEvh(svcLister *objects.SvcLister, tl
 		}
 		utils.AviLog.Infof("key: %s, msg: Added the secret object to tlsnode: %s", key, secretObj.Name)
 	}
+	certNode.OCSPConfig = getHostRuleOCSPConfig(host, key)
+	if altCertNode != nil {
+		altCertNode.OCSPConfig = certNode.OCSPConfig
+	}
 	// If this SSLCertRef is already present don't add it.
 	if tlsNode.CheckSSLCertNodeNameNChecksum(lib.GetTLSKeyCertNodeName(infraSettingName, host, tlsData.SecretName), certNode.GetCheckSum()) {
 		tlsNode.ReplaceEvhSSLRefInEVHNode(certNode, key)
@@ -1305,7 +1355,7 @@ func evhNodeHostName(routeIgrObj RouteIngressModel, tlssetting TlsSettings, ingN
 		_, shardVsName := DeriveShardVSForEvh(host, key, routeIgrObj)
 		// For each host, create a EVH node with the secret giving us the key and cert.
 		// construct a EVH child VS node per tls setting which corresponds to one secret
-		model_name := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		model_name := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(model_name)
 		if !found || aviModel == nil {
 			utils.AviLog.Infof("key: %s, msg: model not found, generating new model with name: %s", key, model_name)
@@ -1362,7 +1412,7 @@ func (o *AviObjectGraph) BuildModelGraphForSecureEVH(routeIgrObj RouteIngressMod
 			evhNode = &AviEvhVsNode{
 				Name:         childVSName,
 				VHParentName: vsNode[0].Name,
-				Tenant:       lib.GetTenant(),
+				Tenant:       vsNode[0].Tenant,
 				EVHParent:    false,
 				EvhHostName:  host,
 				ServiceMetadata: lib.ServiceMetadataObj{
@@ -1416,11 +1466,8 @@ func (o *AviObjectGraph) BuildModelGraphForSecureEVH(routeIgrObj RouteIngressMod
 		certsBuilt = o.BuildTlsCertNodeForEvh(routeIgrObj.GetSvcLister(), vsNode[0], namespace, tlssetting, key, infraSettingName, host)
 	} else {
 		//Delete sslcertref object if host crd sslcertref (sslcertAviRef) is present for given host
-		secretName := tlssetting.SecretName
-		if strings.HasPrefix(secretName, lib.RouteSecretsPrefix) {
-			//Openshift: Remove CA cert if present
-			vsNode[0].DeleteCACertRefInEVHNode(lib.GetCACertNodeName(infraSettingName, host), key)
-		}
+		// Remove the CA certs of the chain if present
+		vsNode[0].DeleteCACertChainInEVHNode(infraSettingName, host, key)
 		vsNode[0].DeleteSSLRefInEVHNode(lib.GetTLSKeyCertNodeName(infraSettingName, host, tlssetting.SecretName), key)
 		vsNode[0].DeleteSSLRefInEVHNode(lib.GetTLSKeyCertNodeName(infraSettingName, host, tlssetting.SecretName+"-alt"), key)
 	}
@@ -1576,7 +1623,7 @@ func RemoveRedirectHTTPPolicyInModelForEvh(vsNode *AviEvhVsNode, hostnames []str
 	}
 }
 
-//DeleteStaleData : delete pool, EVH VS and redirect policy which are present in the object store but no longer required.
+// DeleteStaleData : delete pool, EVH VS and redirect policy which are present in the object store but no longer required.
 func DeleteStaleDataForEvh(routeIgrObj RouteIngressModel, key string, modelList *[]string, Storedhosts map[string]*objects.RouteIngrhost, hostsMap map[string]*objects.RouteIngrhost) {
 	utils.AviLog.Debugf("key: %s, msg: About to delete stale data EVH Stored hosts: %v, hosts map: %v", key, utils.Stringify(Storedhosts), utils.Stringify(hostsMap))
 	var infraSettingName string
@@ -1590,7 +1637,7 @@ func DeleteStaleDataForEvh(routeIgrObj RouteIngressModel, key string, modelList
 		if hostData.SecurePolicy == lib.PolicyPass {
 			shardVsName.Name = lib.GetPassthroughShardVSName(host, key)
 		}
-		modelName := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
 		if !found || aviModel == nil {
 			utils.AviLog.Warnf("key: %s, msg: model not found during delete: %s", key, modelName)
@@ -1889,7 +1936,7 @@ func (o *AviObjectGraph) BuildPolicyRedirectForVSForEvh(vsNode *AviEvhVsNode, ho
 	}
 
 	redirectPolicy := &AviHttpPolicySetNode{
-		Tenant:        lib.GetTenant(),
+		Tenant:        vsNode.Tenant,
 		Name:          policyname,
 		RedirectPorts: []AviRedirectPort{myHppMap},
 	}
@@ -1912,7 +1959,7 @@ func (o *AviObjectGraph) BuildHTTPSecurityPolicyForVSForEvh(vsNode *AviEvhVsNode
 	}
 
 	securityPolicy := &AviHttpPolicySetNode{
-		Tenant:        lib.GetTenant(),
+		Tenant:        vsNode.Tenant,
 		Name:          policyname,
 		SecurityRules: []AviHTTPSecurity{securityRule},
 	}
@@ -1945,7 +1992,7 @@ func RouteIngrDeletePoolsByHostnameForEvh(routeIgrObj RouteIngressModel, namespa
 			shardVsName.Name = lib.GetPassthroughShardVSName(host, key)
 		}
 
-		modelName := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
 		if !found || aviModel == nil {
 			utils.AviLog.Warnf("key: %s, msg: model not found during delete: %s", key, modelName)
@@ -1998,7 +2045,7 @@ func DeleteStaleDataForModelChangeForEvh(routeIgrObj RouteIngressModel, namespac
 		}
 
 		_, infraSettingName := objects.InfraSettingL7Lister().GetIngRouteToInfraSetting(routeIgrObj.GetNamespace() + "/" + routeIgrObj.GetName())
-		modelName := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
 		if !found || aviModel == nil {
 			utils.AviLog.Warnf("key: %s, msg: model not found during delete: %s", key, modelName)
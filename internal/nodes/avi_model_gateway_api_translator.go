@@ -0,0 +1,1075 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// Each gateway.networking.k8s.io Gateway is realised as a dedicated VS. A Gateway with any HTTP or
+// HTTPS listener is realised as a L7 VS, where every listener becomes a listener port on the VS and
+// the rules of the attached HTTPRoutes are translated into a single httppolicyset, with a poolgroup
+// per rule and a pool per backend. Otherwise the Gateway is realised as a L4 VS, where every TLS
+// (passthrough), TCP and UDP listener is mapped to a pool built from the first attached route.
+
+// gatewayAPIListener holds the evaluated state of a Gateway listener.
+type gatewayAPIListener struct {
+	Spec           gwapiv1alpha2.Listener
+	SupportedKinds []gwapiv1alpha2.RouteGroupKind
+	Conditions     []metav1.Condition
+	Valid          bool
+	AttachedRoutes int32
+	// SecretName is the namespace/name of the certificate of a HTTPS listener.
+	SecretName string
+}
+
+// gatewayAPIAttachment is a route attached to a listener, with the hostnames applicable on the listener.
+type gatewayAPIAttachment struct {
+	Route     *gatewayAPIRoute
+	Listener  *gatewayAPIListener
+	Hostnames []string
+}
+
+// GatewayAPIStatus holds the statuses computed while translating a Gateway, which are
+// published to the status layer by the caller.
+type GatewayAPIStatus struct {
+	Listeners  []gwapiv1alpha2.ListenerStatus
+	Conditions []metav1.Condition
+	// route key -> status of the route for the Gateway.
+	RouteParents map[string][]gwapiv1alpha2.RouteParentStatus
+	Secrets      []string
+}
+
+var gatewayAPIL7Protocols = []gwapiv1alpha2.ProtocolType{gwapiv1alpha2.HTTPProtocolType, gwapiv1alpha2.HTTPSProtocolType}
+var gatewayAPIL4Protocols = []gwapiv1alpha2.ProtocolType{gwapiv1alpha2.TLSProtocolType, gwapiv1alpha2.TCPProtocolType, gwapiv1alpha2.UDPProtocolType}
+
+func (o *AviObjectGraph) BuildGatewayAPIGatewayGraph(gateway *gwapiv1alpha2.Gateway, gwClass *gwapiv1alpha2.GatewayClass, key string) *GatewayAPIStatus {
+	o.Lock.Lock()
+	defer o.Lock.Unlock()
+
+	isL7 := false
+	for _, listener := range gateway.Spec.Listeners {
+		if gatewayAPIProtocolIn(listener.Protocol, gatewayAPIL7Protocols) {
+			isL7 = true
+			break
+		}
+	}
+	listeners, secrets := evaluateGatewayAPIListeners(gateway, isL7, key)
+	attachments, routeParents := attachGatewayAPIRoutes(gateway, listeners, key)
+
+	gwStatus := &GatewayAPIStatus{RouteParents: routeParents, Secrets: secrets}
+	var validListeners int
+	for _, listener := range listeners {
+		gwStatus.Listeners = append(gwStatus.Listeners, gwapiv1alpha2.ListenerStatus{
+			Name:           listener.Spec.Name,
+			SupportedKinds: listener.SupportedKinds,
+			AttachedRoutes: listener.AttachedRoutes,
+			Conditions:     listener.Conditions,
+		})
+		if listener.Valid {
+			validListeners++
+		}
+	}
+	if validListeners == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no valid listeners found in Gateway %s/%s", key, gateway.Namespace, gateway.Name)
+		gwStatus.Conditions = []metav1.Condition{{
+			Type:    string(gwapiv1alpha2.GatewayConditionScheduled),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(gwapiv1alpha2.GatewayReasonListenersNotValid),
+			Message: "None of the listeners are valid",
+		}, {
+			Type:    string(gwapiv1alpha2.GatewayConditionReady),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(gwapiv1alpha2.GatewayReasonListenersNotValid),
+			Message: "None of the listeners are valid",
+		}}
+		return gwStatus
+	}
+	gwStatus.Conditions = []metav1.Condition{{
+		Type:   string(gwapiv1alpha2.GatewayConditionScheduled),
+		Status: metav1.ConditionTrue,
+		Reason: string(gwapiv1alpha2.GatewayReasonScheduled),
+	}}
+
+	infraSetting, err := getGatewayAPIInfraSetting(gwClass, key)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: infrasetting of GatewayClass %s not applied: %v", key, gwClass.Name, err)
+	}
+	var vsNode *AviVsNode
+	if isL7 {
+		vsNode = o.constructGatewayAPIL7VsNode(gateway, listeners, attachments, infraSetting, key)
+	} else {
+		vsNode = o.constructGatewayAPIL4VsNode(gateway, listeners, attachments, infraSetting, key)
+	}
+	o.AddModelNode(vsNode)
+	utils.AviLog.Infof("key: %s, msg: checksum for AVI VS object %v", key, vsNode.GetCheckSum())
+	return gwStatus
+}
+
+// evaluateGatewayAPIListeners validates the listeners of the Gateway and computes their conditions.
+func evaluateGatewayAPIListeners(gateway *gwapiv1alpha2.Gateway, isL7 bool, key string) ([]*gatewayAPIListener, []string) {
+	var listeners []*gatewayAPIListener
+	var secrets []string
+	// port -> listeners evaluated on the port, to detect the conflicts.
+	portListeners := make(map[gwapiv1alpha2.PortNumber][]*gatewayAPIListener)
+	for _, spec := range gateway.Spec.Listeners {
+		listener := &gatewayAPIListener{Spec: spec}
+		listeners = append(listeners, listener)
+
+		detached := metav1.Condition{
+			Type:   string(gwapiv1alpha2.ListenerConditionDetached),
+			Status: metav1.ConditionFalse,
+			Reason: string(gwapiv1alpha2.ListenerReasonAttached),
+		}
+		conflicted := metav1.Condition{
+			Type:   string(gwapiv1alpha2.ListenerConditionConflicted),
+			Status: metav1.ConditionFalse,
+			Reason: string(gwapiv1alpha2.ListenerReasonNoConflicts),
+		}
+		resolvedRefs := metav1.Condition{
+			Type:   string(gwapiv1alpha2.ListenerConditionResolvedRefs),
+			Status: metav1.ConditionTrue,
+			Reason: string(gwapiv1alpha2.ListenerReasonResolvedRefs),
+		}
+
+		supported := (isL7 && gatewayAPIProtocolIn(spec.Protocol, gatewayAPIL7Protocols)) ||
+			(!isL7 && gatewayAPIProtocolIn(spec.Protocol, gatewayAPIL4Protocols))
+		if !supported {
+			detached.Status = metav1.ConditionTrue
+			detached.Reason = string(gwapiv1alpha2.ListenerReasonUnsupportedProtocol)
+			detached.Message = fmt.Sprintf("Protocol %s is not supported on a Gateway with HTTP/HTTPS listeners", spec.Protocol)
+			if !gatewayAPIProtocolIn(spec.Protocol, gatewayAPIL7Protocols) && !gatewayAPIProtocolIn(spec.Protocol, gatewayAPIL4Protocols) {
+				detached.Message = fmt.Sprintf("Protocol %s is not supported", spec.Protocol)
+			}
+		} else if spec.Protocol == gwapiv1alpha2.TLSProtocolType && (spec.TLS == nil || spec.TLS.Mode == nil || *spec.TLS.Mode != gwapiv1alpha2.TLSModePassthrough) {
+			detached.Status = metav1.ConditionTrue
+			detached.Reason = string(gwapiv1alpha2.ListenerReasonUnsupportedProtocol)
+			detached.Message = "Only Passthrough mode is supported for TLS listeners"
+		} else if reason, message := gatewayAPIListenerConflict(listener, portListeners[spec.Port]); reason != "" {
+			conflicted.Status = metav1.ConditionTrue
+			conflicted.Reason = reason
+			conflicted.Message = message
+		}
+		portListeners[spec.Port] = append(portListeners[spec.Port], listener)
+
+		var invalidKinds bool
+		listener.SupportedKinds, invalidKinds = gatewayAPISupportedKinds(spec)
+		if invalidKinds {
+			resolvedRefs.Status = metav1.ConditionFalse
+			resolvedRefs.Reason = string(gwapiv1alpha2.ListenerReasonInvalidRouteKinds)
+			resolvedRefs.Message = "Unsupported route kinds are specified in allowedRoutes"
+		}
+
+		if spec.Protocol == gwapiv1alpha2.HTTPSProtocolType && detached.Status == metav1.ConditionFalse {
+			secretName, reason, message := resolveGatewayAPICertificateRef(gateway, spec)
+			if secretName != "" && !utils.HasElem(secrets, secretName) {
+				// the secret to gateway mapping is stored even if the secret is not found,
+				// so that the gateway gets synced once the secret gets created.
+				secrets = append(secrets, secretName)
+			}
+			if reason != "" {
+				resolvedRefs.Status = metav1.ConditionFalse
+				resolvedRefs.Reason = reason
+				resolvedRefs.Message = message
+			} else {
+				listener.SecretName = secretName
+			}
+		}
+
+		listener.Valid = detached.Status == metav1.ConditionFalse && conflicted.Status == metav1.ConditionFalse &&
+			resolvedRefs.Status == metav1.ConditionTrue
+		ready := metav1.Condition{
+			Type:   string(gwapiv1alpha2.ListenerConditionReady),
+			Status: metav1.ConditionTrue,
+			Reason: string(gwapiv1alpha2.ListenerReasonReady),
+		}
+		if !listener.Valid {
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = string(gwapiv1alpha2.ListenerReasonInvalid)
+			utils.AviLog.Warnf("key: %s, msg: listener %s of Gateway %s/%s is invalid", key, spec.Name, gateway.Namespace, gateway.Name)
+		}
+		listener.Conditions = []metav1.Condition{detached, conflicted, resolvedRefs, ready}
+	}
+	return listeners, secrets
+}
+
+// gatewayAPIListenerConflict checks the listener against the listeners which are evaluated earlier on the same port.
+// HTTP listeners and HTTPS listeners may share a port with the ones of the same protocol if the hostnames are distinct,
+// while a L4 port can have a single listener per transport protocol.
+func gatewayAPIListenerConflict(listener *gatewayAPIListener, portListeners []*gatewayAPIListener) (string, string) {
+	for _, existing := range portListeners {
+		if !gatewayAPIProtocolIn(existing.Spec.Protocol, gatewayAPIL7Protocols) {
+			if gatewayAPITransportProtocol(existing.Spec.Protocol) == gatewayAPITransportProtocol(listener.Spec.Protocol) {
+				return string(gwapiv1alpha2.ListenerReasonProtocolConflict), fmt.Sprintf("Port %d is used by listener %s", listener.Spec.Port, existing.Spec.Name)
+			}
+			continue
+		}
+		if existing.Spec.Protocol != listener.Spec.Protocol {
+			return string(gwapiv1alpha2.ListenerReasonProtocolConflict), fmt.Sprintf("Port %d is used by listener %s with protocol %s", listener.Spec.Port, existing.Spec.Name, existing.Spec.Protocol)
+		}
+		if gatewayAPIHostname(existing.Spec.Hostname) == gatewayAPIHostname(listener.Spec.Hostname) {
+			return string(gwapiv1alpha2.ListenerReasonHostnameConflict), fmt.Sprintf("Hostname is used by listener %s on port %d", existing.Spec.Name, listener.Spec.Port)
+		}
+	}
+	return "", ""
+}
+
+// gatewayAPISupportedKinds returns the route kinds supported on the listener, and whether any of the
+// kinds in allowedRoutes is not supported.
+func gatewayAPISupportedKinds(spec gwapiv1alpha2.Listener) ([]gwapiv1alpha2.RouteGroupKind, bool) {
+	var kind string
+	switch spec.Protocol {
+	case gwapiv1alpha2.HTTPProtocolType, gwapiv1alpha2.HTTPSProtocolType:
+		kind = lib.HTTPRoute
+	case gwapiv1alpha2.TLSProtocolType:
+		kind = lib.TLSRoute
+	case gwapiv1alpha2.TCPProtocolType:
+		kind = lib.TCPRoute
+	case gwapiv1alpha2.UDPProtocolType:
+		kind = lib.UDPRoute
+	default:
+		return []gwapiv1alpha2.RouteGroupKind{}, false
+	}
+	group := gwapiv1alpha2.Group(lib.GatewayAPIGroup)
+	supportedKind := gwapiv1alpha2.RouteGroupKind{Group: &group, Kind: gwapiv1alpha2.Kind(kind)}
+	if spec.AllowedRoutes == nil || len(spec.AllowedRoutes.Kinds) == 0 {
+		return []gwapiv1alpha2.RouteGroupKind{supportedKind}, false
+	}
+	var supportedKinds []gwapiv1alpha2.RouteGroupKind
+	var invalidKinds bool
+	for _, routeKind := range spec.AllowedRoutes.Kinds {
+		if (routeKind.Group == nil || string(*routeKind.Group) == lib.GatewayAPIGroup) && string(routeKind.Kind) == kind {
+			supportedKinds = []gwapiv1alpha2.RouteGroupKind{supportedKind}
+		} else {
+			invalidKinds = true
+		}
+	}
+	if supportedKinds == nil {
+		supportedKinds = []gwapiv1alpha2.RouteGroupKind{}
+	}
+	return supportedKinds, invalidKinds
+}
+
+// resolveGatewayAPICertificateRef returns the namespace/name of the secret referred by a HTTPS listener, and
+// the listener condition reason and message if the certificate can not be used.
+func resolveGatewayAPICertificateRef(gateway *gwapiv1alpha2.Gateway, spec gwapiv1alpha2.Listener) (string, string, string) {
+	if spec.TLS == nil || (spec.TLS.Mode != nil && *spec.TLS.Mode != gwapiv1alpha2.TLSModeTerminate) || len(spec.TLS.CertificateRefs) == 0 || spec.TLS.CertificateRefs[0] == nil {
+		return "", string(gwapiv1alpha2.ListenerReasonInvalidCertificateRef), "HTTPS listener requires Terminate mode with a certificateRef"
+	}
+	certRef := spec.TLS.CertificateRefs[0]
+	if (certRef.Group != nil && *certRef.Group != "") || (certRef.Kind != nil && *certRef.Kind != utils.Secret) {
+		return "", string(gwapiv1alpha2.ListenerReasonInvalidCertificateRef), "Only Secrets are supported as certificateRefs"
+	}
+	namespace := gateway.Namespace
+	if certRef.Namespace != nil && *certRef.Namespace != "" {
+		namespace = string(*certRef.Namespace)
+	}
+	secretName := namespace + "/" + string(certRef.Name)
+	if namespace != gateway.Namespace && !isGatewayAPIReferencePermitted(lib.GatewayAPIGroup, "Gateway", gateway.Namespace, "", utils.Secret, namespace, string(certRef.Name)) {
+		return secretName, string(gwapiv1alpha2.ListenerReasonRefNotPermitted), fmt.Sprintf("Reference to Secret %s is not permitted by any ReferencePolicy", secretName)
+	}
+	secretObj, err := utils.GetInformers().SecretInformer.Lister().Secrets(namespace).Get(string(certRef.Name))
+	if err != nil || secretObj == nil {
+		return secretName, string(gwapiv1alpha2.ListenerReasonInvalidCertificateRef), fmt.Sprintf("Secret %s not found", secretName)
+	}
+	if len(secretObj.Data[utils.K8S_TLS_SECRET_CERT]) == 0 || len(secretObj.Data[utils.K8S_TLS_SECRET_KEY]) == 0 {
+		return secretName, string(gwapiv1alpha2.ListenerReasonInvalidCertificateRef), fmt.Sprintf("Secret %s does not have a certificate and key", secretName)
+	}
+	return secretName, "", ""
+}
+
+// isGatewayAPIReferencePermitted checks if any ReferencePolicy in the namespace of the referent allows the reference.
+func isGatewayAPIReferencePermitted(fromGroup, fromKind, fromNamespace, toGroup, toKind, toNamespace, toName string) bool {
+	rps, err := lib.AKOControlConfig().GatewayAPIInformers().ReferencePolicyInformer.Lister().ReferencePolicies(toNamespace).List(labels.Everything())
+	if err != nil {
+		return false
+	}
+	for _, rp := range rps {
+		fromAllowed := false
+		for _, from := range rp.Spec.From {
+			if string(from.Group) == fromGroup && string(from.Kind) == fromKind && string(from.Namespace) == fromNamespace {
+				fromAllowed = true
+				break
+			}
+		}
+		if !fromAllowed {
+			continue
+		}
+		for _, to := range rp.Spec.To {
+			if string(to.Group) == toGroup && string(to.Kind) == toKind && (to.Name == nil || string(*to.Name) == toName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// attachGatewayAPIRoutes evaluates the routes referring to the Gateway, in the order of their precedence,
+// and returns the routes attached to the listeners and the route statuses for the Gateway.
+func attachGatewayAPIRoutes(gateway *gwapiv1alpha2.Gateway, listeners []*gatewayAPIListener, key string) ([]gatewayAPIAttachment, map[string][]gwapiv1alpha2.RouteParentStatus) {
+	gwNSName := gateway.Namespace + "/" + gateway.Name
+	_, routeKeys := objects.SharedGatewayAPILister().GetGatewayToRoutes(gwNSName)
+	var routes []*gatewayAPIRoute
+	for _, routeKey := range routeKeys {
+		kind, namespace, name := lib.ExtractTypeNameNamespace(routeKey)
+		route, err := getGatewayAPIRoute(kind, namespace, name)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: %s not found: %v", key, routeKey, err)
+			continue
+		}
+		if !utils.CheckIfNamespaceAccepted(namespace) {
+			continue
+		}
+		routes = append(routes, route)
+	}
+	// The oldest route takes precedence, the ties are broken by the namespace/name of the route.
+	sort.Slice(routes, func(i, j int) bool {
+		ti, tj := routes[i].Meta.CreationTimestamp, routes[j].Meta.CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return routes[i].Key() < routes[j].Key()
+	})
+
+	var attachments []gatewayAPIAttachment
+	routeParents := make(map[string][]gwapiv1alpha2.RouteParentStatus)
+	for _, route := range routes {
+		resolvedRefs := evaluateGatewayAPIBackendRefs(route)
+		for _, parentRef := range route.ParentRefs {
+			if lib.GatewayAPIParentRefName(parentRef, route.Meta.Namespace) != gwNSName {
+				continue
+			}
+			accepted := metav1.Condition{
+				Type:               string(gwapiv1alpha2.ConditionRouteAccepted),
+				Status:             metav1.ConditionFalse,
+				Reason:             "NotAllowedByListeners",
+				Message:            "No listener of the Gateway allows the route",
+				ObservedGeneration: route.Meta.Generation,
+			}
+			for _, listener := range listeners {
+				if parentRef.SectionName != nil && *parentRef.SectionName != listener.Spec.Name {
+					continue
+				}
+				hostnames, ok := gatewayAPIRouteAllowed(gateway, listener, route)
+				if !ok {
+					continue
+				}
+				if route.Kind != lib.HTTPRoute && listener.AttachedRoutes > 0 {
+					// a L4 listener forwards the traffic to the backend of a single route.
+					accepted.Reason = string(gwapiv1alpha2.ListenerReasonRouteConflict)
+					accepted.Message = fmt.Sprintf("Listener %s is used by another route", listener.Spec.Name)
+					continue
+				}
+				listener.AttachedRoutes++
+				accepted.Status = metav1.ConditionTrue
+				accepted.Reason = string(gwapiv1alpha2.ConditionRouteAccepted)
+				accepted.Message = ""
+				attachments = append(attachments, gatewayAPIAttachment{Route: route, Listener: listener, Hostnames: hostnames})
+			}
+			routeResolvedRefs := resolvedRefs
+			routeResolvedRefs.ObservedGeneration = route.Meta.Generation
+			routeParents[route.Key()] = append(routeParents[route.Key()], gwapiv1alpha2.RouteParentStatus{
+				ParentRef:      parentRef,
+				ControllerName: gwapiv1alpha2.GatewayController(lib.GatewayAPIAviGatewayController),
+				Conditions:     []metav1.Condition{accepted, routeResolvedRefs},
+			})
+		}
+	}
+	return attachments, routeParents
+}
+
+// gatewayAPIRouteAllowed checks if the route can be attached to the listener, and returns the hostnames
+// of the route applicable on the listener. Nil hostnames imply that all the hostnames are allowed.
+func gatewayAPIRouteAllowed(gateway *gwapiv1alpha2.Gateway, listener *gatewayAPIListener, route *gatewayAPIRoute) ([]string, bool) {
+	if !listener.Valid {
+		return nil, false
+	}
+	kindAllowed := false
+	for _, kind := range listener.SupportedKinds {
+		if string(kind.Kind) == route.Kind {
+			kindAllowed = true
+			break
+		}
+	}
+	if !kindAllowed {
+		return nil, false
+	}
+
+	from := gwapiv1alpha2.NamespacesFromSame
+	if listener.Spec.AllowedRoutes != nil && listener.Spec.AllowedRoutes.Namespaces != nil && listener.Spec.AllowedRoutes.Namespaces.From != nil {
+		from = *listener.Spec.AllowedRoutes.Namespaces.From
+	}
+	switch from {
+	case gwapiv1alpha2.NamespacesFromSame:
+		if route.Meta.Namespace != gateway.Namespace {
+			return nil, false
+		}
+	case gwapiv1alpha2.NamespacesFromSelector:
+		if listener.Spec.AllowedRoutes.Namespaces.Selector == nil || utils.GetInformers().NSInformer == nil {
+			return nil, false
+		}
+		selector, err := metav1.LabelSelectorAsSelector(listener.Spec.AllowedRoutes.Namespaces.Selector)
+		if err != nil {
+			return nil, false
+		}
+		nsObj, err := utils.GetInformers().NSInformer.Lister().Get(route.Meta.Namespace)
+		if err != nil || !selector.Matches(labels.Set(nsObj.Labels)) {
+			return nil, false
+		}
+	}
+
+	if route.Kind != lib.HTTPRoute && route.Kind != lib.TLSRoute {
+		return nil, true
+	}
+	listenerHostname := gatewayAPIHostname(listener.Spec.Hostname)
+	if len(route.Hostnames) == 0 {
+		if listenerHostname == "" {
+			return nil, true
+		}
+		return []string{listenerHostname}, true
+	}
+	var hostnames []string
+	for _, hostname := range route.Hostnames {
+		routeHostname := string(hostname)
+		var matched string
+		if listenerHostname == "" || gatewayAPIHostnameMatches(listenerHostname, routeHostname) {
+			matched = routeHostname
+		} else if gatewayAPIHostnameMatches(routeHostname, listenerHostname) {
+			matched = listenerHostname
+		}
+		if matched != "" && !utils.HasElem(hostnames, matched) {
+			hostnames = append(hostnames, matched)
+		}
+	}
+	return hostnames, len(hostnames) > 0
+}
+
+// gatewayAPIHostnameMatches checks if the hostname is covered by the pattern, which can be a wildcard hostname.
+func gatewayAPIHostnameMatches(pattern, hostname string) bool {
+	if pattern == hostname {
+		return true
+	}
+	if !strings.HasPrefix(pattern, "*.") {
+		return false
+	}
+	suffix := pattern[1:]
+	return strings.HasSuffix(hostname, suffix) && len(hostname) > len(suffix)
+}
+
+// evaluateGatewayAPIBackendRefs returns the ResolvedRefs condition of the route.
+func evaluateGatewayAPIBackendRefs(route *gatewayAPIRoute) metav1.Condition {
+	condition := metav1.Condition{
+		Type:   string(gwapiv1alpha2.ConditionRouteResolvedRefs),
+		Status: metav1.ConditionTrue,
+		Reason: string(gwapiv1alpha2.ConditionRouteResolvedRefs),
+	}
+	for _, backendRefs := range route.BackendRefs {
+		for _, backendRef := range backendRefs {
+			if _, reason, message := resolveGatewayAPIBackendRef(route, backendRef.BackendObjectReference); reason != "" {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = reason
+				condition.Message = message
+				return condition
+			}
+		}
+	}
+	return condition
+}
+
+// resolveGatewayAPIBackendRef returns the Service referred by the backendRef, and the route condition reason and
+// message if the backend can not be used.
+func resolveGatewayAPIBackendRef(route *gatewayAPIRoute, backendRef gwapiv1alpha2.BackendObjectReference) (*corev1.Service, string, string) {
+	svcNSName := getGatewayAPIBackendServiceName(backendRef, route.Meta.Namespace)
+	if svcNSName == "" {
+		return nil, "InvalidKind", "Only Services are supported as backendRefs"
+	}
+	svcNamespace, _, svcName := lib.ExtractTypeNameNamespace(svcNSName)
+	if svcNamespace != route.Meta.Namespace && !isGatewayAPIReferencePermitted(lib.GatewayAPIGroup, route.Kind, route.Meta.Namespace, "", utils.Service, svcNamespace, svcName) {
+		return nil, "RefNotPermitted", fmt.Sprintf("Reference to Service %s is not permitted by any ReferencePolicy", svcNSName)
+	}
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(svcNamespace).Get(svcName)
+	if err != nil {
+		return nil, "BackendNotFound", fmt.Sprintf("Service %s not found", svcNSName)
+	}
+	if backendRef.Port == nil {
+		return nil, "BackendNotFound", fmt.Sprintf("Port is not specified for Service %s", svcNSName)
+	}
+	return svcObj, "", ""
+}
+
+func (o *AviObjectGraph) constructGatewayAPIVsNode(gateway *gwapiv1alpha2.Gateway, fqdns []string, infraSetting *akov1alpha1.AviInfraSetting, key string) *AviVsNode {
+	vsName := lib.GetGatewayAPIVSName(gateway.Name, gateway.Namespace)
+	avi_vs_meta := &AviVsNode{
+		Name:               vsName,
+		Tenant:             lib.GetTenantInNamespace(gateway.Namespace),
+		ServiceEngineGroup: lib.GetSEGName(),
+		EnableRhi:          proto.Bool(lib.GetEnableRHI()),
+		ServiceMetadata: lib.ServiceMetadataObj{
+			Gateway:   gateway.Namespace + "/" + gateway.Name,
+			HostNames: fqdns,
+		},
+	}
+	avi_vs_meta.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gateway.Namespace, gateway.Name)
+
+	var vrfcontext string
+	// If NSX-T LR path is empty, set vrfContext
+	if lib.GetT1LRPath() == "" {
+		vrfcontext = lib.GetVrf()
+		avi_vs_meta.VrfContext = vrfcontext
+	}
+
+	vsVipNode := &AviVSVIPNode{
+		Name:        lib.GetVsVipName(vsName),
+		Tenant:      avi_vs_meta.Tenant,
+		FQDNs:       fqdns,
+		VrfContext:  vrfcontext,
+		VipNetworks: lib.GetVipNetworkList(),
+	}
+	if lib.GetT1LRPath() != "" {
+		vsVipNode.T1Lr = lib.GetT1LRPath()
+	}
+	if avi_vs_meta.EnableRhi != nil && *avi_vs_meta.EnableRhi {
+		vsVipNode.BGPPeerLabels = lib.GetGlobalBgpPeerLabels()
+	}
+	// configures VS and VsVip nodes using infraSetting object (via CRD).
+	buildWithInfraSetting(key, avi_vs_meta, vsVipNode, infraSetting)
+
+	for _, address := range gateway.Spec.Addresses {
+		if address.Type == nil || *address.Type == gwapiv1alpha2.IPAddressType {
+			vsVipNode.IPAddress = address.Value
+			break
+		}
+	}
+	avi_vs_meta.VSVIPRefs = append(avi_vs_meta.VSVIPRefs, vsVipNode)
+	return avi_vs_meta
+}
+
+func (o *AviObjectGraph) constructGatewayAPIL7VsNode(gateway *gwapiv1alpha2.Gateway, listeners []*gatewayAPIListener, attachments []gatewayAPIAttachment,
+	infraSetting *akov1alpha1.AviInfraSetting, key string) *AviVsNode {
+	var fqdns []string
+	for _, attachment := range attachments {
+		for _, hostname := range attachment.Hostnames {
+			if !strings.HasPrefix(hostname, "*") && !utils.HasElem(fqdns, hostname) {
+				fqdns = append(fqdns, hostname)
+			}
+		}
+	}
+	for _, listener := range listeners {
+		if hostname := gatewayAPIHostname(listener.Spec.Hostname); listener.Valid && hostname != "" && !strings.HasPrefix(hostname, "*") && !utils.HasElem(fqdns, hostname) {
+			fqdns = append(fqdns, hostname)
+		}
+	}
+	sort.Strings(fqdns)
+
+	vsNode := o.constructGatewayAPIVsNode(gateway, fqdns, infraSetting, key)
+	vsNode.NetworkProfile = utils.DEFAULT_TCP_NW_PROFILE
+	vsNode.ApplicationProfile = utils.DEFAULT_L7_APP_PROFILE
+	vsNode.Dedicated = true
+
+	ports := make(map[int32]bool)
+	for _, listener := range listeners {
+		if !listener.Valid {
+			continue
+		}
+		port := int32(listener.Spec.Port)
+		if listener.SecretName != "" {
+			o.buildGatewayAPITlsCertNode(vsNode, gateway, listener.SecretName, key)
+			vsNode.ApplicationProfile = utils.DEFAULT_L7_SECURE_APP_PROFILE
+		}
+		if ports[port] {
+			continue
+		}
+		ports[port] = true
+		vsNode.PortProto = append(vsNode.PortProto, AviPortHostProtocol{
+			Port:      port,
+			Protocol:  utils.HTTP,
+			EnableSSL: listener.Spec.Protocol == gwapiv1alpha2.HTTPSProtocolType,
+		})
+	}
+	sort.Slice(vsNode.PortProto, func(i, j int) bool {
+		return vsNode.PortProto[i].Port < vsNode.PortProto[j].Port
+	})
+
+	o.buildGatewayAPIPolicyPGPools(vsNode, gateway, attachments, infraSetting, key)
+	return vsNode
+}
+
+func (o *AviObjectGraph) buildGatewayAPITlsCertNode(vsNode *AviVsNode, gateway *gwapiv1alpha2.Gateway, secretNSName, key string) {
+	secretNamespace, _, secretName := lib.ExtractTypeNameNamespace(secretNSName)
+	certName := lib.GetGatewayAPITLSKeyCertName(gateway.Name, gateway.Namespace, secretName, secretNamespace)
+	for _, certNode := range vsNode.SSLKeyCertRefs {
+		if certNode.Name == certName {
+			return
+		}
+	}
+	secretObj, err := utils.GetInformers().SecretInformer.Lister().Secrets(secretNamespace).Get(secretName)
+	if err != nil || secretObj == nil {
+		utils.AviLog.Warnf("key: %s, msg: secret %s for Gateway not found", key, secretNSName)
+		return
+	}
+	certNode := &AviTLSKeyCertNode{
+		Name:   certName,
+		Tenant: vsNode.Tenant,
+		Type:   lib.CertTypeVS,
+		Cert:   secretObj.Data[utils.K8S_TLS_SECRET_CERT],
+		Key:    secretObj.Data[utils.K8S_TLS_SECRET_KEY],
+	}
+	certNode.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gateway.Namespace, gateway.Name)
+	vsNode.SSLKeyCertRefs = append(vsNode.SSLKeyCertRefs, certNode)
+}
+
+// gatewayAPIHppEntry is a httppolicyset rule along with the attributes deciding its precedence.
+type gatewayAPIHppEntry struct {
+	hpp        AviHostPathPortPoolPG
+	exactPath  bool
+	hdrMatches int
+	order      int
+}
+
+func (o *AviObjectGraph) buildGatewayAPIPolicyPGPools(vsNode *AviVsNode, gateway *gwapiv1alpha2.Gateway, attachments []gatewayAPIAttachment,
+	infraSetting *akov1alpha1.AviInfraSetting, key string) {
+	policyNode := &AviHttpPolicySetNode{
+		Name:   lib.GetGatewayAPIHttpPolName(gateway.Name, gateway.Namespace),
+		Tenant: vsNode.Tenant,
+	}
+	policyNode.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gateway.Namespace, gateway.Name)
+
+	var entries []gatewayAPIHppEntry
+	pgs := make(map[string]bool)
+	for _, attachment := range attachments {
+		route := attachment.Route
+		if route.HTTPRoute == nil {
+			continue
+		}
+		for ruleIndex, rule := range route.HTTPRoute.Spec.Rules {
+			pgName := lib.GetGatewayAPIPGName(gateway.Name, gateway.Namespace, route.Meta.Name, route.Meta.Namespace, ruleIndex)
+			requestHeaders, redirect := o.buildGatewayAPIFilters(rule.Filters, attachment.Listener, route, key)
+			if redirect == nil && !pgs[pgName] {
+				pgs[pgName] = true
+				o.buildGatewayAPIPoolGroup(vsNode, gateway, route, ruleIndex, rule, pgName, infraSetting, key)
+			}
+
+			matches := rule.Matches
+			if len(matches) == 0 {
+				// a rule without any match conditions matches all the requests.
+				matches = []gwapiv1alpha2.HTTPRouteMatch{{}}
+			}
+			for matchIndex, match := range matches {
+				hpp, exactPath, ok := buildGatewayAPIHostPath(match, route, key)
+				if !ok {
+					continue
+				}
+				hpp.Name = pgName + "-" + strconv.Itoa(matchIndex) + "-" + string(attachment.Listener.Spec.Name)
+				hpp.Port = uint32(attachment.Listener.Spec.Port)
+				hpp.IngName = route.Meta.Namespace + "/" + route.Meta.Name
+				hpp.RequestHeaders = requestHeaders
+				if redirect != nil {
+					hpp.Redirect = redirect
+				} else {
+					hpp.PoolGroup = pgName
+				}
+				for _, hostHpp := range splitGatewayAPIHostnames(hpp, attachment.Hostnames) {
+					hostHpp.CalculateCheckSum()
+					entries = append(entries, gatewayAPIHppEntry{
+						hpp:        hostHpp,
+						exactPath:  exactPath,
+						hdrMatches: len(hostHpp.HdrMatches),
+						order:      len(entries),
+					})
+				}
+			}
+		}
+	}
+
+	// Rules with an exact path match take precedence over the prefix matches of the same path, followed by
+	// the rules with more header matches. Otherwise the order of the routes is retained. Rules are ordered by
+	// the length of the paths while building the httppolicyset.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].exactPath != entries[j].exactPath {
+			return entries[i].exactPath
+		}
+		if entries[i].hdrMatches != entries[j].hdrMatches {
+			return entries[i].hdrMatches > entries[j].hdrMatches
+		}
+		return entries[i].order < entries[j].order
+	})
+	for _, entry := range entries {
+		policyNode.HppMap = append(policyNode.HppMap, entry.hpp)
+	}
+	if len(policyNode.HppMap) == 0 {
+		return
+	}
+	policyNode.CalculateCheckSum()
+	vsNode.HttpPolicyRefs = append(vsNode.HttpPolicyRefs, policyNode)
+}
+
+// buildGatewayAPIHostPath translates the path and header matches of a HTTPRoute match, the match is skipped if
+// any of the conditions can not be translated.
+func buildGatewayAPIHostPath(match gwapiv1alpha2.HTTPRouteMatch, route *gatewayAPIRoute, key string) (AviHostPathPortPoolPG, bool, bool) {
+	var hpp AviHostPathPortPoolPG
+	routeNSName := route.Meta.Namespace + "/" + route.Meta.Name
+	if len(match.QueryParams) > 0 || match.Method != nil {
+		utils.AviLog.Warnf("key: %s, msg: query param and method matches in HTTPRoute %s are not supported", key, routeNSName)
+		return hpp, false, false
+	}
+	pathType, pathValue := gwapiv1alpha2.PathMatchPathPrefix, "/"
+	if match.Path != nil {
+		if match.Path.Type != nil {
+			pathType = *match.Path.Type
+		}
+		if match.Path.Value != nil {
+			pathValue = *match.Path.Value
+		}
+	}
+	exactPath := false
+	switch pathType {
+	case gwapiv1alpha2.PathMatchExact:
+		hpp.MatchCriteria = "EQUALS"
+		exactPath = true
+	case gwapiv1alpha2.PathMatchPathPrefix:
+		hpp.MatchCriteria = "BEGINS_WITH"
+	default:
+		utils.AviLog.Warnf("key: %s, msg: path match type %s in HTTPRoute %s is not supported", key, pathType, routeNSName)
+		return hpp, false, false
+	}
+	hpp.Path = []string{pathValue}
+
+	for _, header := range match.Headers {
+		if header.Type != nil && *header.Type != gwapiv1alpha2.HeaderMatchExact {
+			utils.AviLog.Warnf("key: %s, msg: header match type %s in HTTPRoute %s is not supported", key, *header.Type, routeNSName)
+			return hpp, false, false
+		}
+		hpp.HdrMatches = append(hpp.HdrMatches, AviHTTPHeaderMatch{
+			Name:          string(header.Name),
+			MatchCriteria: "HDR_EQUALS",
+			MatchCase:     "SENSITIVE",
+			Value:         []string{header.Value},
+		})
+	}
+	return hpp, exactPath, true
+}
+
+// splitGatewayAPIHostnames returns the rules for the hostnames, the exact hostnames are matched with the host
+// header match of the rule, while the wildcard hostnames are matched by the suffix of the Host header.
+func splitGatewayAPIHostnames(hpp AviHostPathPortPoolPG, hostnames []string) []AviHostPathPortPoolPG {
+	if len(hostnames) == 0 {
+		return []AviHostPathPortPoolPG{hpp}
+	}
+	var exactHosts, wildcardSuffixes []string
+	for _, hostname := range hostnames {
+		if strings.HasPrefix(hostname, "*.") {
+			wildcardSuffixes = append(wildcardSuffixes, hostname[1:])
+		} else {
+			exactHosts = append(exactHosts, hostname)
+		}
+	}
+	var hpps []AviHostPathPortPoolPG
+	if len(exactHosts) > 0 {
+		exactHpp := hpp
+		exactHpp.Host = exactHosts
+		hpps = append(hpps, exactHpp)
+	}
+	if len(wildcardSuffixes) > 0 {
+		wildcardHpp := hpp
+		wildcardHpp.Name = hpp.Name + "-wildcard"
+		wildcardHpp.HdrMatches = append([]AviHTTPHeaderMatch{{
+			Name:          "Host",
+			MatchCriteria: "HDR_ENDS_WITH",
+			MatchCase:     "INSENSITIVE",
+			Value:         wildcardSuffixes,
+		}}, hpp.HdrMatches...)
+		hpps = append(hpps, wildcardHpp)
+	}
+	return hpps
+}
+
+// buildGatewayAPIFilters translates the RequestHeaderModifier and RequestRedirect filters of a HTTPRoute rule.
+func (o *AviObjectGraph) buildGatewayAPIFilters(filters []gwapiv1alpha2.HTTPRouteFilter, listener *gatewayAPIListener, route *gatewayAPIRoute, key string) ([]AviHTTPHeaderAction, *AviHTTPRedirect) {
+	var actions []AviHTTPHeaderAction
+	var redirect *AviHTTPRedirect
+	routeNSName := route.Meta.Namespace + "/" + route.Meta.Name
+	for _, filter := range filters {
+		switch filter.Type {
+		case gwapiv1alpha2.HTTPRouteFilterRequestHeaderModifier:
+			if filter.RequestHeaderModifier == nil {
+				continue
+			}
+			for _, header := range filter.RequestHeaderModifier.Set {
+				actions = append(actions, AviHTTPHeaderAction{Action: lib.HTTPReplaceHeader, Name: string(header.Name), Value: header.Value})
+			}
+			for _, header := range filter.RequestHeaderModifier.Add {
+				actions = append(actions, AviHTTPHeaderAction{Action: lib.HTTPAddHeader, Name: string(header.Name), Value: header.Value})
+			}
+			for _, name := range filter.RequestHeaderModifier.Remove {
+				actions = append(actions, AviHTTPHeaderAction{Action: lib.HTTPRemoveHeader, Name: name})
+			}
+		case gwapiv1alpha2.HTTPRouteFilterRequestRedirect:
+			if filter.RequestRedirect == nil {
+				continue
+			}
+			redirect = &AviHTTPRedirect{
+				Protocol:   string(listener.Spec.Protocol),
+				StatusCode: lib.STATUS_REDIRECT,
+			}
+			if filter.RequestRedirect.Scheme != nil {
+				redirect.Protocol = strings.ToUpper(*filter.RequestRedirect.Scheme)
+			}
+			if filter.RequestRedirect.Hostname != nil {
+				redirect.Host = string(*filter.RequestRedirect.Hostname)
+			}
+			if filter.RequestRedirect.Port != nil {
+				redirect.Port = int32(*filter.RequestRedirect.Port)
+			}
+			if filter.RequestRedirect.StatusCode != nil && *filter.RequestRedirect.StatusCode == 301 {
+				redirect.StatusCode = lib.STATUS_REDIRECT_PERMANENT
+			}
+		default:
+			utils.AviLog.Warnf("key: %s, msg: filter %s in HTTPRoute %s is not supported", key, filter.Type, routeNSName)
+		}
+	}
+	return actions, redirect
+}
+
+func (o *AviObjectGraph) buildGatewayAPIPoolGroup(vsNode *AviVsNode, gateway *gwapiv1alpha2.Gateway, route *gatewayAPIRoute, ruleIndex int, rule gwapiv1alpha2.HTTPRouteRule,
+	pgName string, infraSetting *akov1alpha1.AviInfraSetting, key string) {
+	pgNode := &AviPoolGroupNode{Name: pgName, Tenant: vsNode.Tenant}
+	pgNode.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gateway.Namespace, gateway.Name)
+	for _, backendRef := range rule.BackendRefs {
+		if len(backendRef.Filters) > 0 {
+			utils.AviLog.Warnf("key: %s, msg: backendRef filters in HTTPRoute %s/%s are not supported", key, route.Meta.Namespace, route.Meta.Name)
+		}
+		ratio := int32(1)
+		if backendRef.Weight != nil {
+			ratio = *backendRef.Weight
+		}
+		if ratio == 0 {
+			continue
+		}
+		svcObj, _, message := resolveGatewayAPIBackendRef(route, backendRef.BackendObjectReference)
+		if svcObj == nil {
+			utils.AviLog.Warnf("key: %s, msg: backend of HTTPRoute %s/%s skipped: %s", key, route.Meta.Namespace, route.Meta.Name, message)
+			continue
+		}
+		svcPort := getGatewayAPIServicePort(svcObj, int32(*backendRef.Port))
+		if svcPort == nil {
+			utils.AviLog.Warnf("key: %s, msg: port %d not found in service %s/%s", key, *backendRef.Port, svcObj.Namespace, svcObj.Name)
+			continue
+		}
+		poolName := lib.GetGatewayAPIPoolName(gateway.Name, gateway.Namespace, route.Meta.Name, route.Meta.Namespace, ruleIndex, svcObj.Name, svcObj.Namespace, svcPort.Port)
+		poolNode := buildGatewayAPIPoolNode(gateway, poolName, svcObj, svcPort, "", infraSetting, key)
+		pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
+		pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, Ratio: &ratio})
+		vsNode.PoolRefs = append(vsNode.PoolRefs, poolNode)
+	}
+	vsNode.PoolGroupRefs = append(vsNode.PoolGroupRefs, pgNode)
+}
+
+func getGatewayAPIServicePort(svcObj *corev1.Service, port int32) *corev1.ServicePort {
+	for i := range svcObj.Spec.Ports {
+		if svcObj.Spec.Ports[i].Port == port {
+			return &svcObj.Spec.Ports[i]
+		}
+	}
+	return nil
+}
+
+func buildGatewayAPIPoolNode(gateway *gwapiv1alpha2.Gateway, poolName string, svcObj *corev1.Service, svcPort *corev1.ServicePort, protocol string,
+	infraSetting *akov1alpha1.AviInfraSetting, key string) *AviPoolNode {
+	poolNode := &AviPoolNode{
+		Name:       poolName,
+		Tenant:     lib.GetTenantInNamespace(gateway.Namespace),
+		Port:       svcPort.Port,
+		TargetPort: svcPort.TargetPort,
+		PortName:   svcPort.Name,
+		Protocol:   protocol,
+		ServiceMetadata: lib.ServiceMetadataObj{
+			Namespace: svcObj.Namespace,
+		},
+		VrfContext: lib.GetVrf(),
+	}
+	poolNode.NetworkPlacementSettings, _ = lib.GetNodeNetworkMap()
+	if lib.GetT1LRPath() != "" {
+		poolNode.T1Lr = lib.GetT1LRPath()
+		// Unset the poolnode's vrfcontext.
+		poolNode.VrfContext = ""
+	}
+
+	serviceType := lib.GetServiceType()
+	if serviceType == lib.NodePortLocal {
+		if servers := PopulateServersForNPL(poolNode, svcObj.Namespace, svcObj.Name, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	} else if serviceType == lib.NodePort {
+		if servers := PopulateServersForNodePort(poolNode, svcObj.Namespace, svcObj.Name, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	} else {
+		if servers := PopulateServers(poolNode, svcObj.Namespace, svcObj.Name, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	}
+	poolNode.AviMarkers = lib.PopulateAdvL4PoolNodeMarkers(gateway.Namespace, svcObj.Name, gateway.Name, int(svcPort.Port))
+	buildPoolWithInfraSetting(key, poolNode, infraSetting)
+	return poolNode
+}
+
+func (o *AviObjectGraph) constructGatewayAPIL4VsNode(gateway *gwapiv1alpha2.Gateway, listeners []*gatewayAPIListener, attachments []gatewayAPIAttachment,
+	infraSetting *akov1alpha1.AviInfraSetting, key string) *AviVsNode {
+	var fqdns []string
+	for _, listener := range listeners {
+		if hostname := gatewayAPIHostname(listener.Spec.Hostname); listener.Valid && hostname != "" && !strings.HasPrefix(hostname, "*") && !utils.HasElem(fqdns, hostname) {
+			fqdns = append(fqdns, hostname)
+		}
+	}
+	sort.Strings(fqdns)
+
+	vsNode := o.constructGatewayAPIVsNode(gateway, fqdns, infraSetting, key)
+	vsNode.ApplicationProfile = utils.DEFAULT_L4_APP_PROFILE
+
+	isTCP, isUDP := false, false
+	for _, listener := range listeners {
+		if !listener.Valid {
+			continue
+		}
+		protocol := gatewayAPITransportProtocol(listener.Spec.Protocol)
+		vsNode.PortProto = append(vsNode.PortProto, AviPortHostProtocol{Port: int32(listener.Spec.Port), Protocol: protocol})
+		if protocol == utils.UDP {
+			isUDP = true
+		} else {
+			isTCP = true
+		}
+	}
+	sort.Slice(vsNode.PortProto, func(i, j int) bool {
+		if vsNode.PortProto[i].Port != vsNode.PortProto[j].Port {
+			return vsNode.PortProto[i].Port < vsNode.PortProto[j].Port
+		}
+		return vsNode.PortProto[i].Protocol < vsNode.PortProto[j].Protocol
+	})
+
+	// In case the VS has listeners that are a mix of TCP and UDP sockets, the VS is created with
+	// the internally used network profile (MIXED_NET_PROFILE), as done for the L4 Gateways.
+	if isTCP && !isUDP {
+		vsNode.NetworkProfile = utils.TCP_NW_FAST_PATH
+	} else if isUDP && !isTCP {
+		vsNode.NetworkProfile = utils.SYSTEM_UDP_FAST_PATH
+	} else {
+		vsNode.NetworkProfile = utils.MIXED_NET_PROFILE
+	}
+
+	var portPoolSet []AviHostPathPortPoolPG
+	for _, attachment := range attachments {
+		route, listener := attachment.Route, attachment.Listener
+		protocol := gatewayAPITransportProtocol(listener.Spec.Protocol)
+		var poolNode *AviPoolNode
+		for _, backendRefs := range route.BackendRefs {
+			for _, backendRef := range backendRefs {
+				if backendRef.Weight != nil && *backendRef.Weight == 0 {
+					continue
+				}
+				svcObj, _, message := resolveGatewayAPIBackendRef(route, backendRef.BackendObjectReference)
+				if svcObj == nil {
+					utils.AviLog.Warnf("key: %s, msg: backend of %s %s/%s skipped: %s", key, route.Kind, route.Meta.Namespace, route.Meta.Name, message)
+					continue
+				}
+				svcPort := getGatewayAPIServicePort(svcObj, int32(*backendRef.Port))
+				if svcPort == nil {
+					utils.AviLog.Warnf("key: %s, msg: port %d not found in service %s/%s", key, *backendRef.Port, svcObj.Namespace, svcObj.Name)
+					continue
+				}
+				poolName := lib.GetGatewayAPIL4PoolName(gateway.Name, gateway.Namespace, protocol, int32(listener.Spec.Port), svcObj.Name, svcObj.Namespace, svcPort.Port)
+				poolNode = buildGatewayAPIPoolNode(gateway, poolName, svcObj, svcPort, protocol, infraSetting, key)
+				break
+			}
+			if poolNode != nil {
+				break
+			}
+		}
+		if poolNode == nil {
+			continue
+		}
+		if len(route.BackendRefs) > 1 || len(route.BackendRefs[0]) > 1 {
+			utils.AviLog.Warnf("key: %s, msg: only the first backend of %s %s/%s is used", key, route.Kind, route.Meta.Namespace, route.Meta.Name)
+		}
+		vsNode.PoolRefs = append(vsNode.PoolRefs, poolNode)
+		portPoolSet = append(portPoolSet, AviHostPathPortPoolPG{
+			Port:     uint32(listener.Spec.Port),
+			Pool:     fmt.Sprintf("/api/pool?name=%s", poolNode.Name),
+			Protocol: protocol,
+		})
+	}
+
+	vsNode.L4PolicyRefs = []*AviL4PolicyNode{{
+		Name:       vsNode.Name,
+		Tenant:     vsNode.Tenant,
+		PortPool:   portPoolSet,
+		AviMarkers: lib.PopulateAdvL4VSNodeMarkers(gateway.Namespace, gateway.Name),
+	}}
+	utils.AviLog.Infof("key: %s, msg: evaluated L4 pool policies :%v", key, utils.Stringify(vsNode.L4PolicyRefs))
+	return vsNode
+}
+
+// getGatewayAPIInfraSetting returns the AviInfraSetting referred by the parametersRef of the GatewayClass.
+func getGatewayAPIInfraSetting(gwClass *gwapiv1alpha2.GatewayClass, key string) (*akov1alpha1.AviInfraSetting, error) {
+	paramsRef := gwClass.Spec.ParametersRef
+	if paramsRef == nil || string(paramsRef.Group) != lib.AkoGroup || string(paramsRef.Kind) != lib.AviInfraSetting {
+		return nil, nil
+	}
+	infraSetting, err := lib.AKOControlConfig().CRDInformers().AviInfraSettingInformer.Lister().Get(paramsRef.Name)
+	if err != nil {
+		return nil, err
+	}
+	if infraSetting.Status.Status != lib.StatusAccepted {
+		return nil, fmt.Errorf("Referred AviInfraSetting %s is invalid", infraSetting.Name)
+	}
+	return infraSetting, nil
+}
+
+func gatewayAPIProtocolIn(protocol gwapiv1alpha2.ProtocolType, protocols []gwapiv1alpha2.ProtocolType) bool {
+	for _, p := range protocols {
+		if p == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+func gatewayAPITransportProtocol(protocol gwapiv1alpha2.ProtocolType) string {
+	if protocol == gwapiv1alpha2.UDPProtocolType {
+		return utils.UDP
+	}
+	return utils.TCP
+}
+
+func gatewayAPIHostname(hostname *gwapiv1alpha2.Hostname) string {
+	if hostname == nil {
+		return ""
+	}
+	return string(*hostname)
+}
@@ -0,0 +1,739 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	"google.golang.org/protobuf/proto"
+	istionetworking "istio.io/api/networking/v1alpha3"
+	istiov1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Each Istio Gateway is realised as a dedicated L7 VS. Every server in the Gateway becomes a listener
+// port on the VS, and the http routes of the VirtualServices bound to the Gateway are translated
+// into a single httppolicyset, with a poolgroup per route and a pool per route destination.
+// DestinationRules of the destination Services are applied on the pools.
+
+var istioL7Protocols = []string{"HTTP", "HTTPS", "HTTP2", "GRPC"}
+
+func (o *AviObjectGraph) BuildIstioGatewayGraph(gateway *istiov1alpha3.Gateway, key string) {
+	o.Lock.Lock()
+	defer o.Lock.Unlock()
+	vsNode := o.ConstructIstioGatewayVsNode(gateway, key)
+	if vsNode == nil {
+		return
+	}
+	o.BuildIstioGatewayPolicyPGPools(vsNode, gateway, key)
+	o.AddModelNode(vsNode)
+	utils.AviLog.Infof("key: %s, msg: checksum for AVI VS object %v", key, vsNode.GetCheckSum())
+}
+
+func (o *AviObjectGraph) ConstructIstioGatewayVsNode(gateway *istiov1alpha3.Gateway, key string) *AviVsNode {
+	gwNSName := gateway.Namespace + "/" + gateway.Name
+	if !labels.SelectorFromSet(gateway.Spec.Selector).Matches(lib.GetIstioGatewaySelector()) {
+		utils.AviLog.Infof("key: %s, msg: selector %v of Istio Gateway %s does not select the gateway workload of AKO, skipping", key, gateway.Spec.Selector, gwNSName)
+		objects.SharedIstioLister().UpdateGatewaySecretMappings(gwNSName, nil)
+		return nil
+	}
+	vsName := lib.GetIstioGatewayVSName(gateway.Name, gateway.Namespace)
+	avi_vs_meta := &AviVsNode{
+		Name:               vsName,
+		Tenant:             lib.GetTenantInNamespace(gateway.Namespace),
+		ServiceEngineGroup: lib.GetSEGName(),
+		EnableRhi:          proto.Bool(lib.GetEnableRHI()),
+		NetworkProfile:     utils.DEFAULT_TCP_NW_PROFILE,
+		ApplicationProfile: utils.DEFAULT_L7_APP_PROFILE,
+		Dedicated:          true,
+	}
+	avi_vs_meta.AviMarkers = lib.PopulateIstioGatewayNodeMarkers(gateway.Namespace, gateway.Name)
+
+	var fqdns, secrets, certSecrets []string
+	var redirectPorts []AviRedirectPort
+	var httpsPort int32
+	ports := make(map[int32]bool)
+	for _, server := range gateway.Spec.Servers {
+		if server.Port == nil {
+			continue
+		}
+		protocol := strings.ToUpper(server.Port.Protocol)
+		if !utils.HasElem(istioL7Protocols, protocol) {
+			utils.AviLog.Warnf("key: %s, msg: protocol %s of Istio Gateway server %s is not supported", key, server.Port.Protocol, server.Port.Name)
+			continue
+		}
+		port := int32(server.Port.Number)
+		if _, ok := ports[port]; ok {
+			utils.AviLog.Warnf("key: %s, msg: port %d is used by multiple servers in Istio Gateway, using the first one", key, port)
+			continue
+		}
+		hosts := getIstioServerHosts(server)
+		pp := AviPortHostProtocol{Port: port, Protocol: utils.HTTP}
+		if protocol == "HTTPS" {
+			if server.Tls == nil || server.Tls.Mode != istionetworking.ServerTLSSettings_SIMPLE || server.Tls.CredentialName == "" {
+				utils.AviLog.Warnf("key: %s, msg: only SIMPLE tls mode with a credentialName is supported for Istio Gateway server %s", key, server.Port.Name)
+				continue
+			}
+			secretNSName := gateway.Namespace + "/" + server.Tls.CredentialName
+			if !utils.HasElem(secrets, secretNSName) {
+				// the secret to gateway mapping is stored even if the secret is not found,
+				// so that the gateway gets synced once the secret gets created.
+				secrets = append(secrets, secretNSName)
+				if o.BuildIstioTlsCertNode(avi_vs_meta, gateway, server.Tls.CredentialName, key) {
+					certSecrets = append(certSecrets, secretNSName)
+				}
+			}
+			if !utils.HasElem(certSecrets, secretNSName) {
+				continue
+			}
+			pp.EnableSSL = true
+			if httpsPort == 0 {
+				httpsPort = port
+			}
+		} else if server.Tls != nil && server.Tls.HttpsRedirect {
+			if len(hosts) == 0 {
+				utils.AviLog.Warnf("key: %s, msg: httpsRedirect requires explicit hosts in Istio Gateway server %s", key, server.Port.Name)
+			} else {
+				redirectPorts = append(redirectPorts, AviRedirectPort{
+					Hosts:      hosts,
+					StatusCode: lib.STATUS_REDIRECT_PERMANENT,
+					VsPort:     port,
+				})
+			}
+		}
+		ports[port] = true
+		avi_vs_meta.PortProto = append(avi_vs_meta.PortProto, pp)
+		for _, host := range hosts {
+			if !utils.HasElem(fqdns, host) {
+				fqdns = append(fqdns, host)
+			}
+		}
+	}
+	objects.SharedIstioLister().UpdateGatewaySecretMappings(gwNSName, secrets)
+
+	if len(avi_vs_meta.PortProto) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no supported servers found in Istio Gateway %s", key, gwNSName)
+		return nil
+	}
+	sort.Slice(avi_vs_meta.PortProto, func(i, j int) bool {
+		return avi_vs_meta.PortProto[i].Port < avi_vs_meta.PortProto[j].Port
+	})
+	if httpsPort != 0 {
+		avi_vs_meta.ApplicationProfile = utils.DEFAULT_L7_SECURE_APP_PROFILE
+	}
+
+	var vrfcontext string
+	// If NSX-T LR path is empty, set vrfContext
+	if lib.GetT1LRPath() == "" {
+		vrfcontext = lib.GetVrf()
+		avi_vs_meta.VrfContext = vrfcontext
+	}
+
+	vsVipNode := &AviVSVIPNode{
+		Name:        lib.GetVsVipName(vsName),
+		Tenant:      avi_vs_meta.Tenant,
+		FQDNs:       fqdns,
+		VrfContext:  vrfcontext,
+		VipNetworks: lib.GetVipNetworkList(),
+	}
+	if lib.GetT1LRPath() != "" {
+		vsVipNode.T1Lr = lib.GetT1LRPath()
+	}
+	if avi_vs_meta.EnableRhi != nil && *avi_vs_meta.EnableRhi {
+		vsVipNode.BGPPeerLabels = lib.GetGlobalBgpPeerLabels()
+	}
+	avi_vs_meta.VSVIPRefs = append(avi_vs_meta.VSVIPRefs, vsVipNode)
+
+	if len(redirectPorts) > 0 {
+		if httpsPort == 0 {
+			utils.AviLog.Warnf("key: %s, msg: httpsRedirect set without a HTTPS server in Istio Gateway %s", key, gwNSName)
+		} else {
+			for i := range redirectPorts {
+				redirectPorts[i].RedirectPort = httpsPort
+			}
+			redirectPolicy := &AviHttpPolicySetNode{
+				Name:          lib.GetL7HttpRedirPolicy(vsName),
+				Tenant:        avi_vs_meta.Tenant,
+				RedirectPorts: redirectPorts,
+			}
+			redirectPolicy.AviMarkers = lib.PopulateIstioGatewayNodeMarkers(gateway.Namespace, gateway.Name)
+			redirectPolicy.CalculateCheckSum()
+			avi_vs_meta.HttpPolicyRefs = append(avi_vs_meta.HttpPolicyRefs, redirectPolicy)
+		}
+	}
+	return avi_vs_meta
+}
+
+func (o *AviObjectGraph) BuildIstioTlsCertNode(vsNode *AviVsNode, gateway *istiov1alpha3.Gateway, secretName, key string) bool {
+	secretObj, err := utils.GetInformers().SecretInformer.Lister().Secrets(gateway.Namespace).Get(secretName)
+	if err != nil || secretObj == nil {
+		utils.AviLog.Warnf("key: %s, msg: secret %s/%s for Istio Gateway not found", key, gateway.Namespace, secretName)
+		return false
+	}
+	cert, certKey, _ := getIstioSecretData(secretObj)
+	if cert == "" || certKey == "" {
+		utils.AviLog.Warnf("key: %s, msg: secret %s/%s for Istio Gateway does not have a certificate and key", key, gateway.Namespace, secretName)
+		return false
+	}
+	certNode := &AviTLSKeyCertNode{
+		Name:   lib.GetIstioTLSKeyCertName(gateway.Name, gateway.Namespace, secretName),
+		Tenant: vsNode.Tenant,
+		Type:   lib.CertTypeVS,
+		Cert:   []byte(cert),
+		Key:    []byte(certKey),
+	}
+	certNode.AviMarkers = lib.PopulateIstioGatewayNodeMarkers(gateway.Namespace, gateway.Name)
+	vsNode.SSLKeyCertRefs = append(vsNode.SSLKeyCertRefs, certNode)
+	return true
+}
+
+func (o *AviObjectGraph) BuildIstioGatewayPolicyPGPools(vsNode *AviVsNode, gateway *istiov1alpha3.Gateway, key string) {
+	_, vsNSNames := objects.SharedIstioLister().GetGatewayToVses(gateway.Namespace + "/" + gateway.Name)
+	sort.Strings(vsNSNames)
+
+	policyNode := &AviHttpPolicySetNode{
+		Name:   lib.GetIstioHttpPolName(gateway.Name, gateway.Namespace),
+		Tenant: vsNode.Tenant,
+	}
+	policyNode.AviMarkers = lib.PopulateIstioGatewayNodeMarkers(gateway.Namespace, gateway.Name)
+
+	for _, vsNSName := range vsNSNames {
+		vsNamespace, _, vsName := lib.ExtractTypeNameNamespace(vsNSName)
+		vs, err := lib.AKOControlConfig().IstioCRDInformers().VirtualServiceInformer.Lister().VirtualServices(vsNamespace).Get(vsName)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: Istio VirtualService %s not found: %v", key, vsNSName, err)
+			continue
+		}
+		if !utils.CheckIfNamespaceAccepted(vsNamespace) {
+			continue
+		}
+		var hosts []string
+		for _, host := range vs.Spec.Hosts {
+			if host == "*" {
+				// a wildcard host matches all the requests, hence no host match is added.
+				hosts = nil
+				break
+			}
+			hosts = append(hosts, host)
+		}
+		for i, httpRoute := range vs.Spec.Http {
+			o.buildIstioHTTPRoute(vsNode, policyNode, gateway, vs, i, httpRoute, hosts, key)
+		}
+	}
+
+	if len(policyNode.HppMap) == 0 {
+		return
+	}
+	policyNode.CalculateCheckSum()
+	vsNode.HttpPolicyRefs = append(vsNode.HttpPolicyRefs, policyNode)
+}
+
+func (o *AviObjectGraph) buildIstioHTTPRoute(vsNode *AviVsNode, policyNode *AviHttpPolicySetNode, gateway *istiov1alpha3.Gateway,
+	vs *istiov1alpha3.VirtualService, routeIndex int, httpRoute *istionetworking.HTTPRoute, hosts []string, key string) {
+	if httpRoute.Delegate != nil {
+		utils.AviLog.Warnf("key: %s, msg: delegate in Istio VirtualService %s/%s is not supported", key, vs.Namespace, vs.Name)
+		return
+	}
+	if httpRoute.Redirect == nil && len(httpRoute.Route) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: route %d in Istio VirtualService %s/%s has neither a destination nor a redirect", key, routeIndex, vs.Namespace, vs.Name)
+		return
+	}
+
+	pgName := lib.GetIstioPGName(gateway.Name, gateway.Namespace, vs.Name, vs.Namespace, routeIndex)
+	var requestHeaders, responseHeaders []AviHTTPHeaderAction
+	if httpRoute.Headers != nil {
+		requestHeaders = buildIstioHeaderActions(httpRoute.Headers.Request)
+		responseHeaders = buildIstioHeaderActions(httpRoute.Headers.Response)
+	}
+	var pgNode *AviPoolGroupNode
+	var poolNodes []*AviPoolNode
+	if httpRoute.Redirect == nil {
+		pgNode = &AviPoolGroupNode{Name: pgName, Tenant: vsNode.Tenant}
+		pgNode.AviMarkers = lib.PopulateIstioGatewayNodeMarkers(gateway.Namespace, gateway.Name)
+		for _, destination := range httpRoute.Route {
+			poolNode := o.buildIstioPoolNode(gateway, vs, routeIndex, destination, key)
+			if poolNode == nil {
+				continue
+			}
+			ratio := destination.Weight
+			if ratio == 0 && len(httpRoute.Route) == 1 {
+				ratio = 100
+			}
+			pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
+			pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, Ratio: &ratio})
+			poolNodes = append(poolNodes, poolNode)
+
+			// Header operations of a destination can only be applied on the route,
+			// when it is the only destination of the route.
+			if destination.Headers != nil {
+				if len(httpRoute.Route) > 1 {
+					utils.AviLog.Warnf("key: %s, msg: headers on a weighted destination of Istio VirtualService %s/%s are not supported", key, vs.Namespace, vs.Name)
+				} else {
+					requestHeaders = append(requestHeaders, buildIstioHeaderActions(destination.Headers.Request)...)
+					responseHeaders = append(responseHeaders, buildIstioHeaderActions(destination.Headers.Response)...)
+				}
+			}
+		}
+		if len(pgNode.Members) == 0 {
+			utils.AviLog.Warnf("key: %s, msg: none of the destinations of route %d in Istio VirtualService %s/%s are found", key, routeIndex, vs.Namespace, vs.Name)
+			return
+		}
+	}
+
+	var hppMap []AviHostPathPortPoolPG
+	matches := httpRoute.Match
+	if len(matches) == 0 {
+		// a route without any match conditions matches all the requests.
+		matches = []*istionetworking.HTTPMatchRequest{nil}
+	}
+	for matchIndex, match := range matches {
+		httpPGPath := AviHostPathPortPoolPG{
+			Name:            pgName + "-" + strconv.Itoa(matchIndex),
+			Host:            hosts,
+			IngName:         vs.Namespace + "/" + vs.Name,
+			RequestHeaders:  requestHeaders,
+			ResponseHeaders: responseHeaders,
+		}
+		var prefixMatch string
+		if match != nil {
+			if len(match.Gateways) > 0 && !istioMatchAppliesToGateway(match.Gateways, vs.Namespace, gateway) {
+				continue
+			}
+			if match.Uri != nil {
+				switch uri := match.Uri.MatchType.(type) {
+				case *istionetworking.StringMatch_Exact:
+					httpPGPath.MatchCriteria = "EQUALS"
+					httpPGPath.Path = []string{uri.Exact}
+				case *istionetworking.StringMatch_Prefix:
+					httpPGPath.MatchCriteria = "BEGINS_WITH"
+					httpPGPath.Path = []string{uri.Prefix}
+					prefixMatch = uri.Prefix
+				default:
+					utils.AviLog.Warnf("key: %s, msg: regex uri match in Istio VirtualService %s/%s is not supported", key, vs.Namespace, vs.Name)
+					continue
+				}
+			}
+			hdrMatches, ok := buildIstioHeaderMatches(match.Headers)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: regex header match in Istio VirtualService %s/%s is not supported", key, vs.Namespace, vs.Name)
+				continue
+			}
+			httpPGPath.HdrMatches = hdrMatches
+			httpPGPath.Port = match.Port
+		}
+
+		if httpRoute.Redirect != nil {
+			httpPGPath.Redirect = buildIstioRedirect(vsNode, httpRoute.Redirect, key)
+		} else {
+			httpPGPath.PoolGroup = pgName
+			if httpRoute.Rewrite != nil && (httpRoute.Rewrite.Uri != "" || httpRoute.Rewrite.Authority != "") {
+				httpPGPath.RewriteURL = &AviHTTPRewriteURL{
+					Host: httpRoute.Rewrite.Authority,
+					Path: httpRoute.Rewrite.Uri,
+				}
+				if prefixMatch != "" && httpRoute.Rewrite.Uri != "" {
+					// Only the matched prefix is replaced by the rewrite uri.
+					httpPGPath.RewriteURL.ReplacePrefix = true
+					httpPGPath.RewriteURL.PrefixTokens = int32(len(strings.FieldsFunc(prefixMatch, func(c rune) bool { return c == '/' })))
+				}
+			}
+		}
+		httpPGPath.CalculateCheckSum()
+		hppMap = append(hppMap, httpPGPath)
+	}
+
+	// The pools and the poolgroup are added only if the route applies to the gateway.
+	if len(hppMap) == 0 {
+		return
+	}
+	policyNode.HppMap = append(policyNode.HppMap, hppMap...)
+	if pgNode != nil {
+		vsNode.PoolRefs = append(vsNode.PoolRefs, poolNodes...)
+		vsNode.PoolGroupRefs = append(vsNode.PoolGroupRefs, pgNode)
+	}
+}
+
+func (o *AviObjectGraph) buildIstioPoolNode(gateway *istiov1alpha3.Gateway, vs *istiov1alpha3.VirtualService, routeIndex int, destination *istionetworking.HTTPRouteDestination, key string) *AviPoolNode {
+	if destination.Destination == nil {
+		return nil
+	}
+	svcNamespace, svcName, ok := resolveIstioServiceHost(destination.Destination.Host, vs.Namespace)
+	if !ok {
+		utils.AviLog.Warnf("key: %s, msg: host %s of Istio VirtualService %s/%s is not an in-cluster service, skipping the destination", key, destination.Destination.Host, vs.Namespace, vs.Name)
+		return nil
+	}
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(svcNamespace).Get(svcName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: service %s/%s of Istio VirtualService %s/%s not found: %v", key, svcNamespace, svcName, vs.Namespace, vs.Name, err)
+		return nil
+	}
+
+	var destPort uint32
+	if destination.Destination.Port != nil {
+		destPort = destination.Destination.Port.Number
+	}
+	var svcPort *corev1.ServicePort
+	for i, port := range svcObj.Spec.Ports {
+		if uint32(port.Port) == destPort || (destPort == 0 && len(svcObj.Spec.Ports) == 1) {
+			svcPort = &svcObj.Spec.Ports[i]
+			break
+		}
+	}
+	if svcPort == nil {
+		utils.AviLog.Warnf("key: %s, msg: port %d not found in service %s/%s", key, destPort, svcNamespace, svcName)
+		return nil
+	}
+
+	subset := destination.Destination.Subset
+	poolNode := &AviPoolNode{
+		Name:       lib.GetIstioPoolName(gateway.Name, gateway.Namespace, vs.Name, vs.Namespace, routeIndex, svcName, svcNamespace, subset, svcPort.Port),
+		Tenant:     lib.GetTenantInNamespace(gateway.Namespace),
+		Port:       svcPort.Port,
+		TargetPort: svcPort.TargetPort,
+		PortName:   svcPort.Name,
+		ServiceMetadata: lib.ServiceMetadataObj{
+			Namespace: svcNamespace,
+		},
+		VrfContext: lib.GetVrf(),
+	}
+	poolNode.NetworkPlacementSettings, _ = lib.GetNodeNetworkMap()
+	if lib.GetT1LRPath() != "" {
+		poolNode.T1Lr = lib.GetT1LRPath()
+		// Unset the poolnode's vrfcontext.
+		poolNode.VrfContext = ""
+	}
+
+	serviceType := lib.GetServiceType()
+	if serviceType == lib.NodePortLocal {
+		if servers := PopulateServersForNPL(poolNode, svcNamespace, svcName, true, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	} else if serviceType == lib.NodePort {
+		if servers := PopulateServersForNodePort(poolNode, svcNamespace, svcName, true, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	} else {
+		if servers := PopulateServers(poolNode, svcNamespace, svcName, true, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	}
+	poolNode.AviMarkers = lib.PopulateIstioPoolNodeMarkers(gateway.Namespace, svcName, gateway.Name, int(svcPort.Port))
+
+	dr := getIstioDestinationRule(svcNamespace, svcName, key)
+	if dr == nil {
+		if subset != "" {
+			utils.AviLog.Warnf("key: %s, msg: no DestinationRule found for subset %s of service %s/%s", key, subset, svcNamespace, svcName)
+		}
+		return poolNode
+	}
+	applyIstioTrafficPolicy(poolNode, dr, dr.Spec.TrafficPolicy, uint32(svcPort.Port), key)
+	if subset != "" {
+		var drSubset *istionetworking.Subset
+		for _, s := range dr.Spec.Subsets {
+			if s.Name == subset {
+				drSubset = s
+				break
+			}
+		}
+		if drSubset == nil {
+			utils.AviLog.Warnf("key: %s, msg: subset %s not found in DestinationRule %s/%s", key, subset, dr.Namespace, dr.Name)
+			return poolNode
+		}
+		if serviceType == lib.NodePort {
+			utils.AviLog.Warnf("key: %s, msg: subset %s can not be applied on pool %s in NodePort mode", key, subset, poolNode.Name)
+		} else {
+			poolNode.Servers = filterServersForIstioSubset(poolNode.Servers, svcNamespace, svcName, drSubset.Labels, key)
+		}
+		applyIstioTrafficPolicy(poolNode, dr, drSubset.TrafficPolicy, uint32(svcPort.Port), key)
+	}
+	return poolNode
+}
+
+// getIstioDestinationRule returns the DestinationRule for the service. A DestinationRule in the
+// namespace of the service takes precedence over the ones in other namespaces.
+func getIstioDestinationRule(svcNamespace, svcName, key string) *istiov1alpha3.DestinationRule {
+	drs, err := lib.AKOControlConfig().IstioCRDInformers().DestinationRuleInformer.Lister().DestinationRules(metav1.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list Istio DestinationRules: %v", key, err)
+		return nil
+	}
+	sort.Slice(drs, func(i, j int) bool {
+		return drs[i].Namespace+"/"+drs[i].Name < drs[j].Namespace+"/"+drs[j].Name
+	})
+	var selected *istiov1alpha3.DestinationRule
+	for _, dr := range drs {
+		drSvcNamespace, drSvcName, ok := resolveIstioServiceHost(dr.Spec.Host, dr.Namespace)
+		if !ok || drSvcNamespace != svcNamespace || drSvcName != svcName {
+			continue
+		}
+		if dr.Namespace == svcNamespace {
+			return dr
+		}
+		if selected == nil {
+			selected = dr
+		}
+	}
+	return selected
+}
+
+func applyIstioTrafficPolicy(poolNode *AviPoolNode, dr *istiov1alpha3.DestinationRule, trafficPolicy *istionetworking.TrafficPolicy, port uint32, key string) {
+	if trafficPolicy == nil {
+		return
+	}
+	loadBalancer := trafficPolicy.LoadBalancer
+	tls := trafficPolicy.Tls
+	for _, portSetting := range trafficPolicy.PortLevelSettings {
+		if portSetting.Port == nil || portSetting.Port.Number != port {
+			continue
+		}
+		if portSetting.LoadBalancer != nil {
+			loadBalancer = portSetting.LoadBalancer
+		}
+		if portSetting.Tls != nil {
+			tls = portSetting.Tls
+		}
+	}
+
+	if loadBalancer != nil {
+		switch lbPolicy := loadBalancer.LbPolicy.(type) {
+		case *istionetworking.LoadBalancerSettings_Simple:
+			switch lbPolicy.Simple {
+			case istionetworking.LoadBalancerSettings_ROUND_ROBIN:
+				poolNode.LbAlgorithm = lib.LB_ALGORITHM_ROUND_ROBIN
+			case istionetworking.LoadBalancerSettings_LEAST_CONN:
+				poolNode.LbAlgorithm = lib.LB_ALGORITHM_LEAST_CONNECTIONS
+			case istionetworking.LoadBalancerSettings_RANDOM:
+				poolNode.LbAlgorithm = lib.LB_ALGORITHM_RANDOM
+			default:
+				utils.AviLog.Warnf("key: %s, msg: load balancer %s in DestinationRule %s/%s is not supported", key, lbPolicy.Simple.String(), dr.Namespace, dr.Name)
+			}
+		case *istionetworking.LoadBalancerSettings_ConsistentHash:
+			switch hashKey := lbPolicy.ConsistentHash.GetHashKey().(type) {
+			case *istionetworking.LoadBalancerSettings_ConsistentHashLB_HttpHeaderName:
+				poolNode.LbAlgorithm = lib.LB_ALGORITHM_CONSISTENT_HASH
+				poolNode.LbAlgorithmHash = lib.LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER
+				poolNode.LbAlgoHostHeader = hashKey.HttpHeaderName
+			case *istionetworking.LoadBalancerSettings_ConsistentHashLB_UseSourceIp:
+				poolNode.LbAlgorithm = lib.LB_ALGORITHM_CONSISTENT_HASH
+				poolNode.LbAlgorithmHash = lib.LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP
+			default:
+				utils.AviLog.Warnf("key: %s, msg: consistent hash key in DestinationRule %s/%s is not supported", key, dr.Namespace, dr.Name)
+			}
+		}
+	}
+
+	if tls == nil {
+		return
+	}
+	switch tls.Mode {
+	case istionetworking.ClientTLSSettings_DISABLE:
+		poolNode.SniEnabled = false
+		poolNode.SslProfileRef = ""
+		poolNode.PkiProfile = nil
+	case istionetworking.ClientTLSSettings_SIMPLE:
+		poolNode.SniEnabled = true
+		poolNode.SslProfileRef = fmt.Sprintf("/api/sslprofile?name=%s", lib.DefaultPoolSSLProfile)
+		if tls.CredentialName == "" {
+			return
+		}
+		secretObj, err := utils.GetInformers().SecretInformer.Lister().Secrets(dr.Namespace).Get(tls.CredentialName)
+		if err != nil || secretObj == nil {
+			utils.AviLog.Warnf("key: %s, msg: secret %s/%s of DestinationRule not found", key, dr.Namespace, tls.CredentialName)
+			return
+		}
+		if _, _, caCert := getIstioSecretData(secretObj); caCert != "" {
+			poolNode.PkiProfile = &AviPkiProfileNode{
+				Name:       lib.GetPoolPKIProfileName(poolNode.Name),
+				Tenant:     poolNode.Tenant,
+				CACert:     caCert,
+				AviMarkers: poolNode.AviMarkers,
+			}
+		}
+	default:
+		utils.AviLog.Warnf("key: %s, msg: tls mode %s in DestinationRule %s/%s is not supported", key, tls.Mode.String(), dr.Namespace, dr.Name)
+	}
+}
+
+// filterServersForIstioSubset retains the servers that belong to the pods selected by the subset labels.
+func filterServersForIstioSubset(servers []AviPoolMetaServer, namespace, svcName string, subsetLabels map[string]string, key string) []AviPoolMetaServer {
+	if len(subsetLabels) == 0 || utils.GetInformers().PodInformer == nil {
+		return servers
+	}
+	addresses, err := getServiceEndpointAddresses(namespace, svcName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
+		return nil
+	}
+	selector := labels.SelectorFromSet(subsetLabels)
+	// For NodePortLocal the servers are the node IPs, hence the pods are matched with the host IP.
+	isNPL := lib.GetServiceType() == lib.NodePortLocal
+	subsetIPs := make(map[string]bool)
+	for _, addr := range addresses {
+		if addr.podName == "" {
+			continue
+		}
+		pod, err := utils.GetInformers().PodInformer.Lister().Pods(namespace).Get(addr.podName)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if isNPL {
+			subsetIPs[pod.Status.HostIP] = true
+		} else {
+			subsetIPs[addr.ip] = true
+		}
+	}
+	var filteredServers []AviPoolMetaServer
+	for _, server := range servers {
+		if server.Ip.Addr != nil && subsetIPs[*server.Ip.Addr] {
+			filteredServers = append(filteredServers, server)
+		}
+	}
+	return filteredServers
+}
+
+func istioMatchAppliesToGateway(gateways []string, namespace string, gateway *istiov1alpha3.Gateway) bool {
+	for _, gw := range gateways {
+		if resolveIstioGatewayName(gw, namespace) == gateway.Namespace+"/"+gateway.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// getIstioServerHosts returns the hostnames of a Gateway server, the namespace prefix
+// in the hosts is dropped and the wildcard host is ignored.
+func getIstioServerHosts(server *istionetworking.Server) []string {
+	var hosts []string
+	for _, host := range server.Hosts {
+		if idx := strings.Index(host, "/"); idx != -1 {
+			host = host[idx+1:]
+		}
+		if host == "*" || host == "" || utils.HasElem(hosts, host) {
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// getIstioSecretData returns the certificate, key and the CA certificate from a secret. Istio supports
+// both the kubernetes TLS secret format and the generic secret format.
+func getIstioSecretData(secretObj *corev1.Secret) (string, string, string) {
+	getData := func(keys ...string) string {
+		for _, k := range keys {
+			if val, ok := secretObj.Data[k]; ok && len(val) > 0 {
+				return string(val)
+			}
+		}
+		return ""
+	}
+	return getData(utils.K8S_TLS_SECRET_CERT, "cert"), getData(utils.K8S_TLS_SECRET_KEY, "key"), getData("ca.crt", "cacert")
+}
+
+func buildIstioHeaderActions(headerOps *istionetworking.Headers_HeaderOperations) []AviHTTPHeaderAction {
+	if headerOps == nil {
+		return nil
+	}
+	var actions []AviHTTPHeaderAction
+	for _, name := range sortedKeys(headerOps.Set) {
+		actions = append(actions, AviHTTPHeaderAction{Action: lib.HTTPReplaceHeader, Name: name, Value: headerOps.Set[name]})
+	}
+	for _, name := range sortedKeys(headerOps.Add) {
+		actions = append(actions, AviHTTPHeaderAction{Action: lib.HTTPAddHeader, Name: name, Value: headerOps.Add[name]})
+	}
+	for _, name := range headerOps.Remove {
+		actions = append(actions, AviHTTPHeaderAction{Action: lib.HTTPRemoveHeader, Name: name})
+	}
+	return actions
+}
+
+// buildIstioHeaderMatches returns false, if any of the header matches can not be translated.
+func buildIstioHeaderMatches(headers map[string]*istionetworking.StringMatch) ([]AviHTTPHeaderMatch, bool) {
+	var hdrMatches []AviHTTPHeaderMatch
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hdrMatch := AviHTTPHeaderMatch{Name: name, MatchCase: "SENSITIVE"}
+		switch match := headers[name].GetMatchType().(type) {
+		case *istionetworking.StringMatch_Exact:
+			hdrMatch.MatchCriteria = "HDR_EQUALS"
+			hdrMatch.Value = []string{match.Exact}
+		case *istionetworking.StringMatch_Prefix:
+			hdrMatch.MatchCriteria = "HDR_BEGINS_WITH"
+			hdrMatch.Value = []string{match.Prefix}
+		case nil:
+			hdrMatch.MatchCriteria = "HDR_EXISTS"
+			hdrMatch.MatchCase = ""
+		default:
+			return nil, false
+		}
+		hdrMatches = append(hdrMatches, hdrMatch)
+	}
+	return hdrMatches, true
+}
+
+func buildIstioRedirect(vsNode *AviVsNode, redirect *istionetworking.HTTPRedirect, key string) *AviHTTPRedirect {
+	aviRedirect := &AviHTTPRedirect{
+		Host:     redirect.Authority,
+		Path:     redirect.Uri,
+		Protocol: "HTTP",
+	}
+	// The scheme of the request is retained by Istio, since the protocol of the
+	// redirect has to be set, use HTTPS only if the VS does not listen on plain HTTP.
+	secureOnly := true
+	for _, pp := range vsNode.PortProto {
+		if !pp.EnableSSL {
+			secureOnly = false
+			break
+		}
+	}
+	if secureOnly {
+		aviRedirect.Protocol = "HTTPS"
+	}
+	switch redirect.RedirectCode {
+	case 0, 301:
+		aviRedirect.StatusCode = lib.STATUS_REDIRECT_PERMANENT
+	case 302:
+		aviRedirect.StatusCode = lib.STATUS_REDIRECT
+	case 307:
+		aviRedirect.StatusCode = lib.STATUS_REDIRECT_TEMPORARY
+	default:
+		utils.AviLog.Warnf("key: %s, msg: redirect code %d is not supported, using 301", key, redirect.RedirectCode)
+		aviRedirect.StatusCode = lib.STATUS_REDIRECT_PERMANENT
+	}
+	return aviRedirect
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -57,7 +57,7 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 	vsName := lib.GetL4VSName(svcObj.ObjectMeta.Name, svcObj.ObjectMeta.Namespace)
 	avi_vs_meta = &AviVsNode{
 		Name:   vsName,
-		Tenant: lib.GetTenant(),
+		Tenant: lib.GetTenantInNamespace(svcObj.ObjectMeta.Namespace),
 		ServiceMetadata: lib.ServiceMetadataObj{
 			NamespaceServiceName: []string{svcObj.ObjectMeta.Namespace + "/" + svcObj.ObjectMeta.Name},
 			HostNames:            fqdns,
@@ -94,7 +94,7 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 	vsVipName := lib.GetL4VSVipName(svcObj.ObjectMeta.Name, svcObj.ObjectMeta.Namespace)
 	vsVipNode := &AviVSVIPNode{
 		Name:        vsVipName,
-		Tenant:      lib.GetTenant(),
+		Tenant:      avi_vs_meta.Tenant,
 		FQDNs:       fqdns,
 		VrfContext:  vrfcontext,
 		VipNetworks: lib.GetVipNetworkList(),
@@ -116,6 +116,15 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 		vsVipNode.IPAddress = svcObj.Spec.LoadBalancerIP
 	}
 
+	// configures VS and VsVip nodes using the L4Rule referred by the Service, the loadBalancerIP
+	// of the L4Rule takes precedence over the one of the Service.
+	if l4Rule, err := getL4Rule(key, svcObj); err == nil {
+		buildWithL4Rule(key, avi_vs_meta, vsVipNode, l4Rule)
+	}
+
+	// the ipFamilies of the Service decide the ip type of the vip, over the vip networks.
+	vsVipNode.IPType = lib.GetServiceIPType(svcObj)
+
 	avi_vs_meta.VSVIPRefs = append(avi_vs_meta.VSVIPRefs, vsVipNode)
 	return avi_vs_meta
 }
@@ -129,12 +138,13 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 		utils.AviLog.Warnf("key: %s, msg: Error while fetching infrasetting for Gateway %s", key, err.Error())
 		return
 	}
+	l4Rule, _ := getL4Rule(key, svcObj)
 
 	for _, portProto := range vsNode.PortProto {
 		filterPort := portProto.Port
 		poolNode := &AviPoolNode{
 			Name:       lib.GetL4PoolName(svcObj.ObjectMeta.Name, svcObj.ObjectMeta.Namespace, filterPort),
-			Tenant:     lib.GetTenant(),
+			Tenant:     vsNode.Tenant,
 			Protocol:   portProto.Protocol,
 			PortName:   portProto.Name,
 			Port:       portProto.Port,
@@ -180,12 +190,13 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 		portPoolSet = append(portPoolSet, portPool)
 
 		buildPoolWithInfraSetting(key, poolNode, infraSetting)
+		buildPoolWithL4Rule(key, poolNode, l4Rule)
 
 		vsNode.PoolRefs = append(vsNode.PoolRefs, poolNode)
 		utils.AviLog.Infof("key: %s, msg: evaluated L4 pool values :%v", key, utils.Stringify(poolNode))
 	}
 
-	l4policyNode := &AviL4PolicyNode{Name: vsNode.Name, Tenant: lib.GetTenant(), PortPool: portPoolSet}
+	l4policyNode := &AviL4PolicyNode{Name: vsNode.Name, Tenant: vsNode.Tenant, PortPool: portPoolSet}
 	l4policyNode.AviMarkers = lib.PopulateL4VSNodeMarkers(svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name)
 	l4Policies = append(l4Policies, l4policyNode)
 	vsNode.L4PolicyRefs = l4Policies
@@ -200,6 +211,7 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 			return nil
 		}
 	}
+	populateProbeHealthMonitor(poolNode, ns, serviceName, key)
 	pods, targetPort := lib.GetPodsFromService(ns, serviceName, poolNode.TargetPort)
 	if len(pods) == 0 {
 		utils.AviLog.Infof("key: %s, msg: got no Pod for Service %s", key, serviceName)
@@ -230,6 +242,10 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 						Addr: &a.NodeIP,
 						Type: &atype,
 					}}
+				// The NPL annotations are present on the terminating Pods, which are drained until the drain timeout.
+				if !drainPodServer(&server, ns, pod.Name, utils.Pod+"/"+ns+"/"+pod.Name, key) {
+					continue
+				}
 				poolMeta = append(poolMeta, server)
 			}
 		}
@@ -261,6 +277,7 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 		utils.AviLog.Debugf("key: %s, msg: ClusterIP is not processed in NodePort: %s", key, serviceName)
 		return poolMeta
 	}
+	populateProbeHealthMonitor(poolNode, ns, serviceName, key)
 	for _, port := range svcObj.Spec.Ports {
 		if port.Name != poolNode.PortName && len(svcObj.Spec.Ports) != 1 {
 			// continue only if port name does not match and its multiport svcobj
@@ -322,12 +339,19 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 			return nil
 		}
 	}
+	populateProbeHealthMonitor(poolNode, ns, serviceName, key)
+	if lib.IsEndpointSliceEnabled() {
+		return PopulateServersFromEndpointSlices(poolNode, ns, serviceName, key)
+	}
 	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
 	if err != nil {
 		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
 		return nil
 	}
 	var pool_meta []AviPoolMetaServer
+	// The servers of the terminating Pods are drained before they are removed from the pool.
+	poolKey := poolNode.Tenant + "/" + poolNode.Name
+	drainResyncKey := utils.Endpoints + "/" + ns + "/" + serviceName
 	for _, ss := range epObj.Subsets {
 		port_match := false
 		for _, epp := range ss.Ports {
@@ -358,10 +382,19 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 				if addr.NodeName != nil {
 					server.ServerNode = *addr.NodeName
 				}
+				if lib.GetPoolDrainTimeout() > 0 && addr.TargetRef != nil && addr.TargetRef.Kind == "Pod" {
+					if !drainPodServer(&server, ns, addr.TargetRef.Name, drainResyncKey, key) {
+						continue
+					}
+					drainTracker.recordServer(poolKey, server, ns, addr.TargetRef.Name)
+				}
 				pool_meta = append(pool_meta, server)
 			}
 		}
 	}
+	if lib.GetPoolDrainTimeout() > 0 {
+		pool_meta = drainTracker.drainRemovedServers(poolKey, pool_meta, drainResyncKey, key)
+	}
 	utils.AviLog.Infof("key: %s, msg: servers for port: %v, are: %v", key, poolNode.Port, utils.Stringify(pool_meta))
 	return pool_meta
 }
@@ -502,3 +535,79 @@ func getL4InfraSetting(key string, svc *corev1.Service, advl4GWClassName *string
 
 	return infraSetting, nil
 }
+
+// getL4Rule returns the L4Rule referred by the Service of type LoadBalancer via annotation, the L4Rule must
+// be in the namespace of the Service. A nil L4Rule is returned if the Service does not refer to any.
+func getL4Rule(key string, svc *corev1.Service) (*akov1alpha1.L4Rule, error) {
+	l4RuleName, ok := svc.GetAnnotations()[lib.L4RuleAnnotation]
+	if !ok || l4RuleName == "" || !lib.AKOControlConfig().L4RuleEnabled() {
+		return nil, nil
+	}
+
+	l4Rule, err := lib.AKOControlConfig().CRDInformers().L4RuleInformer.Lister().L4Rules(svc.Namespace).Get(l4RuleName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: Unable to get corresponding L4Rule via annotation %s", key, err.Error())
+		return nil, err
+	}
+
+	if l4Rule.Status.Status != lib.StatusAccepted {
+		utils.AviLog.Warnf("key: %s, msg: Referred L4Rule %s/%s is invalid", key, l4Rule.Namespace, l4Rule.Name)
+		return nil, fmt.Errorf("Referred L4Rule %s/%s is invalid", l4Rule.Namespace, l4Rule.Name)
+	}
+
+	return l4Rule, nil
+}
+
+// buildWithL4Rule overrides the profiles of the L4 VS and the VIP of the VsVip with the ones set in the L4Rule.
+func buildWithL4Rule(key string, vs *AviVsNode, vsvip *AviVSVIPNode, l4Rule *akov1alpha1.L4Rule) {
+	if l4Rule == nil {
+		return
+	}
+
+	if l4Rule.Spec.NetworkProfile != "" {
+		vs.NetworkProfile = l4Rule.Spec.NetworkProfile
+	}
+	if l4Rule.Spec.ApplicationProfile != "" {
+		vs.AppProfileRef = fmt.Sprintf("/api/applicationprofile?name=%s", l4Rule.Spec.ApplicationProfile)
+	}
+	if l4Rule.Spec.AnalyticsProfile != "" {
+		vs.AnalyticsProfileRef = fmt.Sprintf("/api/analyticsprofile?name=%s", l4Rule.Spec.AnalyticsProfile)
+	}
+	if l4Rule.Spec.LoadBalancerIP != "" {
+		vsvip.IPAddress = l4Rule.Spec.LoadBalancerIP
+	}
+	utils.AviLog.Infof("key: %s, msg: applied L4Rule %s/%s configs to VS %s", key, l4Rule.Namespace, l4Rule.Name, vs.Name)
+}
+
+// buildPoolWithL4Rule configures the L4 pool of a Service port with the backend properties of the L4Rule, the
+// properties set for the port take precedence over the ones set for all the ports.
+func buildPoolWithL4Rule(key string, pool *AviPoolNode, l4Rule *akov1alpha1.L4Rule) {
+	if l4Rule == nil {
+		return
+	}
+
+	backendProperties := l4Rule.Spec.BackendProperties
+	for _, portProperties := range l4Rule.Spec.PortProperties {
+		if portProperties.Port == pool.Port {
+			backendProperties = portProperties.L4RuleBackendProperties
+			break
+		}
+	}
+
+	if backendProperties.LoadBalancerPolicy.Algorithm != "" {
+		pool.LbAlgorithm = backendProperties.LoadBalancerPolicy.Algorithm
+		if pool.LbAlgorithm == lib.LB_ALGORITHM_CONSISTENT_HASH {
+			pool.LbAlgorithmHash = backendProperties.LoadBalancerPolicy.Hash
+		}
+	}
+	if backendProperties.ApplicationPersistence != "" {
+		pool.ApplicationPersistence = fmt.Sprintf("/api/applicationpersistenceprofile?name=%s", backendProperties.ApplicationPersistence)
+	}
+	for _, hm := range backendProperties.HealthMonitors {
+		hmRef := fmt.Sprintf("/api/healthmonitor?name=%s", hm)
+		if !utils.HasElem(pool.HealthMonitors, hmRef) {
+			pool.HealthMonitors = append(pool.HealthMonitors, hmRef)
+		}
+	}
+	utils.AviLog.Infof("key: %s, msg: applied L4Rule %s/%s configs to pool %s", key, l4Rule.Namespace, l4Rule.Name, pool.Name)
+}
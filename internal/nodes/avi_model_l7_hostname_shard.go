@@ -136,7 +136,7 @@ func (o *AviObjectGraph) BuildPoolPGPolicyForDedicatedVS(vsNode []*AviVsNode, na
 		}
 	}
 	if policyNode == nil {
-		policyNode = &AviHttpPolicySetNode{Name: httpPolName, Tenant: lib.GetTenant()}
+		policyNode = &AviHttpPolicySetNode{Name: httpPolName, Tenant: vsNode[0].Tenant}
 		vsNode[0].HttpPolicyRefs = append(vsNode[0].HttpPolicyRefs, policyNode)
 	}
 
@@ -159,8 +159,10 @@ func (o *AviObjectGraph) BuildPoolPGPolicyForDedicatedVS(vsNode []*AviVsNode, na
 		} else {
 			priorityLabel = hostname
 		}
-		if isIngr {
+		if isIngr && !obj.httpRuleBackend {
 			poolName = lib.GetSniPoolName(ingName, namespace, hostname, obj.Path, infraSettingName, vsNode[0].Dedicated)
+			// The pools of the HTTPRule backends of the path follow the pool of the path, and are built again.
+			vsNode[0].RemoveHTTPRuleBackendPoolRefs(ingName, namespace, priorityLabel)
 		} else {
 			poolName = lib.GetSniPoolName(ingName, namespace, hostname, obj.Path, infraSettingName, vsNode[0].Dedicated, obj.ServiceName)
 		}
@@ -177,7 +179,7 @@ func (o *AviObjectGraph) BuildPoolPGPolicyForDedicatedVS(vsNode []*AviVsNode, na
 			//var pgfound bool
 			pgNode, pgfound = localPGList[pgName]
 			if !pgfound {
-				pgNode = &AviPoolGroupNode{Name: pgName, Tenant: lib.GetTenant()}
+				pgNode = &AviPoolGroupNode{Name: pgName, Tenant: vsNode[0].Tenant}
 			}
 			localPGList[pgName] = pgNode
 			httpPGPath.PoolGroup = pgNode.Name
@@ -253,10 +255,12 @@ func (o *AviObjectGraph) BuildL7VSGraphHostNameShard(vsName, hostname string, ro
 		}
 
 		// Using servicename in poolname for routes, but not in ingress for consistency with existing naming convention.
-		// If possible, we would make this uniform
-		if routeIgrObj.GetType() == utils.Ingress {
+		// If possible, we would make this uniform. The HTTPRule backends of an ingress path use the servicename too.
+		if routeIgrObj.GetType() == utils.Ingress && !obj.httpRuleBackend {
 			poolName = lib.GetL7PoolName(priorityLabel, namespace, ingName, infraSettingName)
 			serviceName = ""
+			// The pools of the HTTPRule backends of the path follow the pool of the path, and are built again.
+			vsNode[0].RemoveHTTPRuleBackendPoolRefs(ingName, namespace, priorityLabel)
 		} else {
 			poolName = lib.GetL7PoolName(priorityLabel, namespace, ingName, infraSettingName, obj.ServiceName)
 			serviceName = obj.ServiceName
@@ -305,7 +309,7 @@ func buildPoolNode(key, poolName, ingName, namespace, priorityLabel, hostname st
 		Name:          poolName,
 		IngressName:   ingName,
 		PortName:      obj.PortName,
-		Tenant:        lib.GetTenant(),
+		Tenant:        lib.GetTenantInNamespace(namespace),
 		PriorityLabel: priorityLabel,
 		Port:          obj.Port,
 		TargetPort:    intstr.FromInt(int(obj.TargetPort)),
@@ -316,7 +320,8 @@ func buildPoolNode(key, poolName, ingName, namespace, priorityLabel, hostname st
 			PoolRatio:             obj.weight,
 			InsecureEdgeTermAllow: insecureEdgeTermAllow,
 		},
-		VrfContext: lib.GetVrf(),
+		VrfContext:      lib.GetVrf(),
+		HTTPRuleBackend: obj.httpRuleBackend,
 	}
 
 	poolNode.NetworkPlacementSettings, _ = lib.GetNodeNetworkMap()
@@ -389,6 +394,11 @@ func (o *AviObjectGraph) DeletePoolForHostname(vsName, hostname string, routeIgr
 			}
 			// It might be safe to remove all the pools for this VS for this ingress in one shot.
 		}
+		if routeIgrObj.GetType() == utils.Ingress {
+			for path := range pathSvc {
+				vsNode[0].RemoveHTTPRuleBackendPoolRefs(ingName, namespace, hostname+path)
+			}
+		}
 		pgName := lib.GetL7SharedPGName(vsName)
 		pgNode := o.GetPoolGroupByName(pgName)
 		if pgNode != nil {
@@ -460,6 +470,11 @@ func (o *AviObjectGraph) manipulateVsNode(vsNode *AviVsNode, ingName, namespace,
 			o.RemovePoolNodeRefsFromSni(sniPool, vsNode)
 			o.RemovePoolRefsFromPG(sniPool, pgNode)
 		}
+		if isIngr {
+			for _, backendPool := range vsNode.RemoveHTTPRuleBackendPoolRefs(ingName, namespace, hostname+path) {
+				o.RemovePoolRefsFromPG(backendPool, pgNode)
+			}
+		}
 		// Remove the SNI PG if it has no member
 		if pgNode != nil {
 			if len(pgNode.Members) == 0 {
@@ -555,7 +570,7 @@ func sniNodeHostName(routeIgrObj RouteIngressModel, tlssetting TlsSettings, ingN
 		dedicated = shardVsName.Dedicated
 		// For each host, create a SNI node with the secret giving us the key and cert.
 		// construct a SNI VS node per tls setting which corresponds to one secret
-		model_name := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		model_name := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(model_name)
 		if !found || aviModel == nil {
 			utils.AviLog.Infof("key: %s, msg: model not found, generating new model with name: %s", key, model_name)
@@ -612,7 +627,7 @@ func (o *AviObjectGraph) BuildModelGraphForSNI(routeIgrObj RouteIngressModel, in
 			sniNode = &AviVsNode{
 				Name:         sniNodeName,
 				VHParentName: vsNode[0].Name,
-				Tenant:       lib.GetTenant(),
+				Tenant:       vsNode[0].Tenant,
 				IsSNIChild:   true,
 				ServiceMetadata: lib.ServiceMetadataObj{
 					NamespaceIngressName: ingressHostMap.GetIngressesForHostName(sniHost),
@@ -65,7 +65,7 @@ func (o *AviObjectGraph) ConstructAviL7VsNode(vsName string, key string, routeIg
 	var vrfcontext string
 	avi_vs_meta := &AviVsNode{
 		Name:               vsName,
-		Tenant:             lib.GetTenant(),
+		Tenant:             lib.GetTenantInNamespace(routeIgrObj.GetNamespace()),
 		ServiceEngineGroup: lib.GetSEGName(),
 		EnableRhi:          proto.Bool(lib.GetEnableRHI()),
 		NetworkProfile:     utils.DEFAULT_TCP_NW_PROFILE,
@@ -103,12 +103,12 @@ func (o *AviObjectGraph) ConstructAviL7VsNode(vsName string, key string, routeIg
 	o.AddModelNode(avi_vs_meta)
 
 	subDomains := GetDefaultSubDomain()
-	fqdns, fqdn := lib.GetFqdns(vsName, key, subDomains)
+	fqdns, fqdn := lib.GetFqdns(vsName, key, avi_vs_meta.Tenant, subDomains)
 	configuredSharedVSFqdn := fqdn
 
 	vsVipNode := &AviVSVIPNode{
 		Name:        lib.GetVsVipName(vsName),
-		Tenant:      lib.GetTenant(),
+		Tenant:      avi_vs_meta.Tenant,
 		FQDNs:       fqdns,
 		VrfContext:  vrfcontext,
 		VipNetworks: lib.GetVipNetworkList(),
@@ -135,7 +135,7 @@ func (o *AviObjectGraph) ConstructAviL7VsNode(vsName string, key string, routeIg
 
 func (o *AviObjectGraph) ConstructShardVsPGNode(vsName string, key string, vsNode *AviVsNode) *AviPoolGroupNode {
 	pgName := lib.GetL7SharedPGName(vsName)
-	pgNode := &AviPoolGroupNode{Name: pgName, Tenant: lib.GetTenant(), ImplicitPriorityLabel: true}
+	pgNode := &AviPoolGroupNode{Name: pgName, Tenant: vsNode.Tenant, ImplicitPriorityLabel: true}
 	pgNode.AttachedToSharedVS = vsNode.SharedVS
 	vsNode.PoolGroupRefs = append(vsNode.PoolGroupRefs, pgNode)
 	o.AddModelNode(pgNode)
@@ -150,7 +150,7 @@ func (o *AviObjectGraph) ConstructHTTPDataScript(vsName string, key string, vsNo
 	poolGroupRefs = append(poolGroupRefs, pgName)
 	dsName := lib.GetL7InsecureDSName(vsName)
 	script := &DataScript{Script: scriptStr, Evt: evt}
-	dsScriptNode := &AviHTTPDataScriptNode{Name: dsName, Tenant: lib.GetTenant(), DataScript: script, PoolGroupRefs: poolGroupRefs}
+	dsScriptNode := &AviHTTPDataScriptNode{Name: dsName, Tenant: vsNode.Tenant, DataScript: script, PoolGroupRefs: poolGroupRefs}
 	if len(dsScriptNode.PoolGroupRefs) > 0 {
 		dsScriptNode.Script = strings.Replace(dsScriptNode.Script, "POOLGROUP", dsScriptNode.PoolGroupRefs[0], 1)
 	}
@@ -159,21 +159,38 @@ func (o *AviObjectGraph) ConstructHTTPDataScript(vsName string, key string, vsNo
 	return dsScriptNode
 }
 
-// BuildCACertNode : Build a new node to store CA cert, this would be referred by the corresponding keycert
-func (o *AviObjectGraph) BuildCACertNode(tlsNode *AviVsNode, cacert, infraSettingName, host, key string) string {
-	cacertNode := &AviTLSKeyCertNode{Name: lib.GetCACertNodeName(infraSettingName, host), Tenant: lib.GetTenant()}
-	cacertNode.Type = lib.CertTypeCA
-	cacertNode.Cert = []byte(cacert)
-	cacertNode.AviMarkers = lib.PopulateTLSKeyCertNode(host, infraSettingName)
-	if tlsNode.CheckCACertNodeNameNChecksum(cacertNode.Name, cacertNode.GetCheckSum()) {
-		if len(tlsNode.CACertRefs) == 1 {
-			tlsNode.CACertRefs[0] = cacertNode
-			utils.AviLog.Warnf("key: %s, msg: duplicate cacerts detected for %s, overwriting", key, cacertNode.Name)
-		} else {
-			tlsNode.ReplaceCACertRefInSNINode(cacertNode, key)
+// buildCACertChainNodes builds a node per CA cert in the chain of the keycert of the host, ordered from the issuer
+// of the keycert to the root, with each CA cert referring to the CA cert of its issuer.
+func buildCACertChainNodes(chain []string, tenant, infraSettingName, host, key string) []*AviTLSKeyCertNode {
+	if len(chain) > lib.MaxCACertChainDepth {
+		utils.AviLog.Warnf("key: %s, msg: certificate chain of host %s has %d CA certs, using the first %d", key, host, len(chain), lib.MaxCACertChainDepth)
+		chain = chain[:lib.MaxCACertChainDepth]
+	}
+	cacertNodes := make([]*AviTLSKeyCertNode, len(chain))
+	for i, cacert := range chain {
+		cacertNodes[i] = &AviTLSKeyCertNode{
+			Name:       lib.GetCACertChainNodeName(infraSettingName, host, i),
+			Tenant:     tenant,
+			Type:       lib.CertTypeCA,
+			Cert:       []byte(cacert),
+			AviMarkers: lib.PopulateTLSKeyCertNode(host, infraSettingName),
+		}
+		if i > 0 {
+			cacertNodes[i-1].CACert = cacertNodes[i].Name
 		}
 	}
-	return cacertNode.Name
+	return cacertNodes
+}
+
+// BuildCACertNode : Build the nodes to store the CA certs of the chain, these would be referred by the corresponding keycert.
+// The nodes are added root first, so that the CA certs are created before the CA certs referring to them.
+func (o *AviObjectGraph) BuildCACertNode(tlsNode *AviVsNode, chain []string, infraSettingName, host, key string) string {
+	cacertNodes := buildCACertChainNodes(chain, tlsNode.Tenant, infraSettingName, host, key)
+	tlsNode.DeleteCACertChainInSNINode(infraSettingName, host, key)
+	for i := len(cacertNodes) - 1; i >= 0; i-- {
+		tlsNode.CACertRefs = append(tlsNode.CACertRefs, cacertNodes[i])
+	}
+	return cacertNodes[0].Name
 }
 
 func (o *AviObjectGraph) BuildTlsCertNode(svcLister *objects.SvcLister, tlsNode *AviVsNode, namespace string, tlsData TlsSettings, key, infraSettingName, sniHost string) bool {
@@ -208,7 +225,7 @@ func (o *AviObjectGraph) BuildTlsCertNode(svcLister *objects.SvcLister, tlsNode
 	if !foundTLSKeyCertNode {
 		certNode = &AviTLSKeyCertNode{
 			Name:   lib.GetTLSKeyCertNodeName(infraSettingName, sniHost, tlsData.SecretName),
-			Tenant: lib.GetTenant(),
+			Tenant: tlsNode.Tenant,
 			Type:   lib.CertTypeVS,
 		}
 		certNode.AviMarkers = lib.PopulateTLSKeyCertNode(sniHost, infraSettingName)
@@ -217,12 +234,14 @@ func (o *AviObjectGraph) BuildTlsCertNode(svcLister *objects.SvcLister, tlsNode
 	// Routes can refer to secrets only in case of using default secret in ako NS or using hostrule secret.
 	if strings.HasPrefix(secretName, lib.RouteSecretsPrefix) {
 		if tlsData.cert != "" && tlsData.key != "" {
-			certNode.Cert = []byte(tlsData.cert)
+			cert, chain := lib.SplitCertificateChain([]byte(tlsData.cert), []byte(tlsData.cacert))
+			certNode.Cert = cert
 			certNode.Key = []byte(tlsData.key)
-			if tlsData.cacert != "" {
-				certNode.CACert = o.BuildCACertNode(tlsNode, tlsData.cacert, infraSettingName, sniHost, key)
+			if len(chain) > 0 {
+				certNode.CACert = o.BuildCACertNode(tlsNode, chain, infraSettingName, sniHost, key)
 			} else {
-				tlsNode.DeleteCACertRefInSNINode(lib.GetCACertNodeName(infraSettingName, sniHost), key)
+				certNode.CACert = ""
+				tlsNode.DeleteCACertChainInSNINode(infraSettingName, sniHost, key)
 			}
 		} else {
 			ok, _ := svcLister.IngressMappings(namespace).GetSecretToIng(secretName)
@@ -249,9 +268,7 @@ func (o *AviObjectGraph) BuildTlsCertNode(svcLister *objects.SvcLister, tlsNode
 		}
 		keycertMap := secretObj.Data
 		cert, ok := keycertMap[utils.K8S_TLS_SECRET_CERT]
-		if ok {
-			certNode.Cert = cert
-		} else {
+		if !ok {
 			utils.AviLog.Infof("key: %s, msg: certificate not found for secret: %s", key, secretObj.Name)
 			return false
 		}
@@ -262,6 +279,17 @@ func (o *AviObjectGraph) BuildTlsCertNode(svcLister *objects.SvcLister, tlsNode
 			utils.AviLog.Infof("key: %s, msg: key not found for secret: %s", key, secretObj.Name)
 			return false
 		}
+		// The intermediates following the certificate in tls.crt, and ca.crt of the cert-manager Secrets are
+		// uploaded as CA certs.
+		var chain []string
+		certNode.Cert, chain = lib.SplitCertificateChain(cert, keycertMap[utils.K8S_TLS_SECRET_CA])
+		if len(chain) > 0 {
+			certNode.CACert = o.BuildCACertNode(tlsNode, chain, infraSettingName, sniHost, key)
+		} else {
+			certNode.CACert = ""
+			tlsNode.DeleteCACertChainInSNINode(infraSettingName, sniHost, key)
+		}
+		certNode.Secret = secretNS + "/" + secretName
 		altCert, ok := keycertMap[utils.K8S_TLS_SECRET_ALT_CERT]
 		if ok {
 			altKey, ok := keycertMap[utils.K8S_TLS_SECRET_ALT_CERT]
@@ -278,7 +306,7 @@ func (o *AviObjectGraph) BuildTlsCertNode(svcLister *objects.SvcLister, tlsNode
 				if !foundTLSKeyCertNode {
 					altCertNode = &AviTLSKeyCertNode{
 						Name:       lib.GetTLSKeyCertNodeName(infraSettingName, sniHost, tlsData.SecretName+"-alt"),
-						Tenant:     lib.GetTenant(),
+						Tenant:     tlsNode.Tenant,
 						Type:       lib.CertTypeVS,
 						AviMarkers: certNode.AviMarkers,
 						Cert:       altCert,
@@ -289,6 +317,10 @@ func (o *AviObjectGraph) BuildTlsCertNode(svcLister *objects.SvcLister, tlsNode
 		}
 		utils.AviLog.Infof("key: %s, msg: Added the secret object to tlsnode: %s", key, secretObj.Name)
 	}
+	certNode.OCSPConfig = getHostRuleOCSPConfig(sniHost, key)
+	if altCertNode != nil {
+		altCertNode.OCSPConfig = certNode.OCSPConfig
+	}
 	// If this SSLCertRef is already present don't add it.
 	if tlsNode.CheckSSLCertNodeNameNChecksum(lib.GetTLSKeyCertNodeName(infraSettingName, sniHost, tlsData.SecretName), certNode.GetCheckSum()) {
 		if len(tlsNode.SSLKeyCertRefs) == 1 {
@@ -349,7 +381,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 			}
 		}
 		if policyNode == nil {
-			policyNode = &AviHttpPolicySetNode{Name: httpPolName, Tenant: lib.GetTenant()}
+			policyNode = &AviHttpPolicySetNode{Name: httpPolName, Tenant: tlsNode.Tenant}
 			tlsNode.HttpPolicyRefs = append(tlsNode.HttpPolicyRefs, policyNode)
 		}
 
@@ -374,9 +406,11 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 			var poolName string
 			var pgfound bool
 			var pgNode *AviPoolGroupNode
-			// Do not use serviceName in SNI Pool Name for ingress for backward compatibility
-			if isIngr {
+			// Do not use serviceName in SNI Pool Name for ingress for backward compatibility, except for the HTTPRule backends.
+			if isIngr && !path.httpRuleBackend {
 				poolName = lib.GetSniPoolName(ingName, namespace, host, path.Path, infraSettingName, vsNode[0].Dedicated)
+				// The pools of the HTTPRule backends of the path follow the pool of the path, and are built again.
+				tlsNode.RemoveHTTPRuleBackendPoolRefs(ingName, namespace, priorityLabel)
 			} else {
 				poolName = lib.GetSniPoolName(ingName, namespace, host, path.Path, infraSettingName, vsNode[0].Dedicated, path.ServiceName)
 			}
@@ -394,7 +428,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 				pgName := lib.GetSniPGName(ingName, namespace, host, path.Path, infraSettingName, vsNode[0].Dedicated)
 				pgNode, pgfound = localPGList[pgName]
 				if !pgfound {
-					pgNode = &AviPoolGroupNode{Name: pgName, Tenant: lib.GetTenant()}
+					pgNode = &AviPoolGroupNode{Name: pgName, Tenant: tlsNode.Tenant}
 				}
 				localPGList[pgName] = pgNode
 				httpPGPath.PoolGroup = pgNode.Name
@@ -406,7 +440,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 				Name:          poolName,
 				IngressName:   ingName,
 				PortName:      path.PortName,
-				Tenant:        lib.GetTenant(),
+				Tenant:        tlsNode.Tenant,
 				PriorityLabel: priorityLabel,
 				Port:          path.Port,
 				TargetPort:    intstr.FromInt(int(path.TargetPort)),
@@ -416,7 +450,8 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 					HostNames:   hostSlice,
 					PoolRatio:   path.weight,
 				},
-				VrfContext: lib.GetVrf(),
+				VrfContext:      lib.GetVrf(),
+				HTTPRuleBackend: path.httpRuleBackend,
 			}
 
 			poolNode.NetworkPlacementSettings, _ = lib.GetNodeNetworkMap()
@@ -498,7 +533,7 @@ func (o *AviObjectGraph) BuildPoolSecurity(poolNode *AviPoolNode, tlsData TlsSet
 	}
 	pkiProfile := AviPkiProfileNode{
 		Name:   lib.GetPoolPKIProfileName(poolNode.Name),
-		Tenant: lib.GetTenant(),
+		Tenant: poolNode.Tenant,
 		CACert: tlsData.destCA,
 	}
 	pkiProfile.AviMarkers = lib.PopulatePoolNodeMarkers(aviMarkers.Namespace, aviMarkers.Host[0],
@@ -517,7 +552,7 @@ func (o *AviObjectGraph) BuildPolicyRedirectForVS(vsNode []*AviVsNode, hostnames
 	}
 
 	redirectPolicy := &AviHttpPolicySetNode{
-		Tenant:        lib.GetTenant(),
+		Tenant:        vsNode[0].Tenant,
 		Name:          policyname,
 		RedirectPorts: []AviRedirectPort{myHppMap},
 	}
@@ -541,7 +576,7 @@ func (o *AviObjectGraph) BuildHeaderRewrite(vsNode []*AviVsNode, gslbHost, local
 	}
 
 	rewritePolicy := &AviHttpPolicySetNode{
-		Tenant:        lib.GetTenant(),
+		Tenant:        vsNode[0].Tenant,
 		Name:          policyname,
 		HeaderReWrite: &rewriteRule,
 	}
@@ -175,6 +175,12 @@ func (v *AviVsNode) CalculateForGraphChecksum() uint32 {
 	for _, l4pol := range v.L4PolicyRefs {
 		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(l4pol.GetCheckSum()))
 	}
+	if v.ClientAuthAppProfile != nil {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(v.ClientAuthAppProfile.GetCheckSum()))
+		if v.ClientAuthAppProfile.PkiProfile != nil {
+			checksumStringSlice = append(checksumStringSlice, fmt.Sprint(v.ClientAuthAppProfile.PkiProfile.GetCheckSum()))
+		}
+	}
 
 	return utils.Hash(strings.Join(checksumStringSlice, ":"))
 }
@@ -205,6 +211,12 @@ func (v *AviEvhVsNode) CalculateForGraphChecksum() uint32 {
 	for _, vsvip := range v.VSVIPRefs {
 		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(vsvip.GetCheckSum()))
 	}
+	if v.ClientAuthAppProfile != nil {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(v.ClientAuthAppProfile.GetCheckSum()))
+		if v.ClientAuthAppProfile.PkiProfile != nil {
+			checksumStringSlice = append(checksumStringSlice, fmt.Sprint(v.ClientAuthAppProfile.PkiProfile.GetCheckSum()))
+		}
+	}
 
 	return utils.Hash(strings.Join(checksumStringSlice, ":"))
 }
@@ -280,6 +292,25 @@ func (o *AviObjectGraph) RemovePoolNodeRefsFromSni(poolName string, sniNode *Avi
 
 }
 
+// RemoveHTTPRuleBackendPoolRefs removes the pools of the HTTPRule backends of the ingress path, identified by
+// the priority label of the path, and returns the names of the removed pools.
+func (v *AviVsNode) RemoveHTTPRuleBackendPoolRefs(ingName, namespace, priorityLabel string) []string {
+	var poolNames []string
+	var poolRefs []*AviPoolNode
+	for _, pool := range v.PoolRefs {
+		if pool.HTTPRuleBackend && pool.IngressName == ingName && pool.ServiceMetadata.Namespace == namespace && pool.PriorityLabel == priorityLabel {
+			poolNames = append(poolNames, pool.Name)
+			continue
+		}
+		poolRefs = append(poolRefs, pool)
+	}
+	if len(poolNames) > 0 {
+		utils.AviLog.Debugf("Removing httprule backend pool refs: %v", poolNames)
+		v.PoolRefs = poolRefs
+	}
+	return poolNames
+}
+
 func (o *AviObjectGraph) RemovePoolRefsFromPG(poolName string, pgNode *AviPoolGroupNode) {
 	if pgNode == nil {
 		utils.AviLog.Warnf("cannot delete pool %s from nil PG node", poolName)
@@ -388,6 +419,8 @@ type AviVsNode struct {
 	Paths                 []string
 	IngressNames          []string
 	AnalyticsPolicy       *avimodels.AnalyticsPolicy
+	ConnectionsRateLimit  *avimodels.RateProfile
+	ClientAuthAppProfile  *AviAppProfileNode
 	Dedicated             bool
 }
 
@@ -401,6 +434,10 @@ func (v *AviVsNode) SetName(Name string) {
 	v.Name = Name
 }
 
+func (v *AviVsNode) GetTenant() string {
+	return v.Tenant
+}
+
 func (v *AviVsNode) IsSharedVS() bool {
 	return v.SharedVS
 }
@@ -537,6 +574,22 @@ func (v *AviVsNode) SetAnalyticsPolicy(policy *avimodels.AnalyticsPolicy) {
 	v.AnalyticsPolicy = policy
 }
 
+func (v *AviVsNode) GetConnectionsRateLimit() *avimodels.RateProfile {
+	return v.ConnectionsRateLimit
+}
+
+func (v *AviVsNode) SetConnectionsRateLimit(rateLimit *avimodels.RateProfile) {
+	v.ConnectionsRateLimit = rateLimit
+}
+
+func (v *AviVsNode) GetClientAuthAppProfile() *AviAppProfileNode {
+	return v.ClientAuthAppProfile
+}
+
+func (v *AviVsNode) SetClientAuthAppProfile(appProfile *AviAppProfileNode) {
+	v.ClientAuthAppProfile = appProfile
+}
+
 func (v *AviVsNode) GetVSVIPLoadBalancerIP() string {
 	if len(v.VSVIPRefs) > 0 {
 		return v.VSVIPRefs[0].IPAddress
@@ -697,6 +750,13 @@ func (o *AviVsNode) DeleteCACertRefInSNINode(cacertNodeName, key string) {
 	}
 }
 
+// DeleteCACertChainInSNINode deletes the CA certs of the chain of the keycert of the host.
+func (o *AviVsNode) DeleteCACertChainInSNINode(infraSettingName, host, key string) {
+	for i := 0; i < lib.MaxCACertChainDepth; i++ {
+		o.DeleteCACertRefInSNINode(lib.GetCACertChainNodeName(infraSettingName, host, i), key)
+	}
+}
+
 func (o *AviVsNode) ReplaceCACertRefInSNINode(cacertNode *AviTLSKeyCertNode, key string) {
 	for i, cacert := range o.CACertRefs {
 		if cacert.Name == cacertNode.Name {
@@ -930,6 +990,10 @@ func (v *AviVsNode) CalculateCheckSum() {
 		checksum += lib.GetAnalyticsPolicyChecksum(v.AnalyticsPolicy)
 	}
 
+	if v.ConnectionsRateLimit != nil {
+		checksum += utils.Hash(utils.Stringify(v.ConnectionsRateLimit))
+	}
+
 	v.CloudConfigCksum = checksum
 }
 
@@ -1004,6 +1068,8 @@ type AviHttpPolicySetNode struct {
 	SecurityRules      []AviHTTPSecurity
 	AviMarkers         utils.AviObjectMarkers
 	AttachedToSharedVS bool
+	// HTTPRuleHost is set for the httppolicysets built from the path actions of the HTTPRules of the host.
+	HTTPRuleHost string
 }
 
 func (v *AviHttpPolicySetNode) GetCheckSum() uint32 {
@@ -1028,6 +1094,9 @@ func (v *AviHttpPolicySetNode) CalculateCheckSum() {
 	for _, sec_rule := range v.SecurityRules {
 		checksum = checksum + utils.Hash(sec_rule.Action) + utils.Hash(sec_rule.MatchCriteria)
 		checksum = checksum + uint32(sec_rule.Port)
+		if sec_rule.ClientIP != nil || sec_rule.RateLimit != nil {
+			checksum = checksum + utils.Hash(utils.Stringify(sec_rule.ClientIP)) + utils.Hash(utils.Stringify(sec_rule.RateLimit)) + utils.Hash(sec_rule.StatusCode)
+		}
 	}
 	if v.HeaderReWrite != nil {
 		checksum = checksum + utils.Hash(utils.Stringify(v.HeaderReWrite))
@@ -1075,6 +1144,51 @@ type AviHostPathPortPoolPG struct {
 	MatchCriteria string
 	Protocol      string
 	IngName       string
+
+	// Optional request match conditions and actions, rendered on the same HTTP request rule.
+	HdrMatches      []AviHTTPHeaderMatch  `json:",omitempty"`
+	CookieMatch     *AviHTTPCookieMatch   `json:",omitempty"`
+	RequestHeaders  []AviHTTPHeaderAction `json:",omitempty"`
+	ResponseHeaders []AviHTTPHeaderAction `json:",omitempty"`
+	RewriteURL      *AviHTTPRewriteURL    `json:",omitempty"`
+	Redirect        *AviHTTPRedirect      `json:",omitempty"`
+}
+
+type AviHTTPHeaderMatch struct {
+	Name          string
+	MatchCriteria string
+	MatchCase     string
+	Value         []string
+}
+
+type AviHTTPCookieMatch struct {
+	Name          string
+	MatchCriteria string
+	MatchCase     string
+	Value         string
+}
+
+type AviHTTPHeaderAction struct {
+	Action string
+	Name   string
+	Value  string
+}
+
+type AviHTTPRewriteURL struct {
+	Host string
+	Path string
+	// If ReplacePrefix is set, Path replaces only the first PrefixTokens tokens
+	// of the request path, otherwise Path replaces the complete request path.
+	ReplacePrefix bool
+	PrefixTokens  int32
+}
+
+type AviHTTPRedirect struct {
+	Host       string
+	Path       string
+	Protocol   string
+	Port       int32
+	StatusCode string
 }
 
 type AviRedirectPort struct {
@@ -1090,6 +1204,23 @@ type AviHTTPSecurity struct {
 	MatchCriteria string
 	Enable        bool
 	Port          int64
+	// ClientIP matches the client addresses against the prefixes and the Avi IP address groups.
+	ClientIP   *AviHTTPSecurityClientIPMatch
+	StatusCode string
+	RateLimit  *AviHTTPSecurityRateLimit
+}
+
+type AviHTTPSecurityClientIPMatch struct {
+	MatchCriteria string
+	Prefixes      []string
+	IPGroups      []string
+}
+
+type AviHTTPSecurityRateLimit struct {
+	Count       int32
+	Period      int32
+	Burst       int32
+	PerClientIP bool
 }
 type AviHostHeaderRewrite struct {
 	Name       string
@@ -1107,11 +1238,16 @@ type AviTLSKeyCertNode struct {
 	Port             int32
 	Type             string
 	AviMarkers       utils.AviObjectMarkers
+	// OCSPConfig enables OCSP stapling for the certificate, using the HostRule of the host.
+	OCSPConfig *avimodels.OCSPConfig
+	// Secret is the namespace/name of the Secret holding the certificate, empty for the Route certificates.
+	Secret string
 }
 
 func (v *AviTLSKeyCertNode) CalculateCheckSum() {
 	// A sum of fields for this SSL cert.
 	checksum := lib.SSLKeyCertChecksum(v.Name, string(v.Cert), v.CACert, v.AviMarkers, nil, false)
+	checksum += lib.OCSPConfigChecksum(v.OCSPConfig)
 	v.CloudConfigCksum = checksum
 }
 
@@ -1158,6 +1294,7 @@ type AviVSVIPNode struct {
 	FQDNs                   []string
 	VrfContext              string
 	IPAddress               string
+	IPType                  string
 	VipNetworks             []akov1alpha1.AviInfraSettingVipNetwork
 	EnablePublicIP          *bool
 	BGPPeerLabels           []string
@@ -1183,10 +1320,19 @@ func (v *AviVSVIPNode) CalculateCheckSum() {
 		checksum += utils.Hash(v.IPAddress)
 	}
 
+	// V4_ONLY is the default ip type of the vips, and is not part of the checksum.
+	if v.IPType != "" && v.IPType != lib.VipIPTypeV4Only {
+		checksum += utils.Hash(v.IPType)
+	}
+
 	if len(v.VipNetworks) > 0 {
 		var vipNetworkStringList []string
 		for _, vipNetwork := range v.VipNetworks {
-			vipNetworkStringList = append(vipNetworkStringList, vipNetwork.NetworkName+":"+vipNetwork.Cidr)
+			vipNetworkString := vipNetwork.NetworkName + ":" + vipNetwork.Cidr
+			if vipNetwork.V6Cidr != "" {
+				vipNetworkString += ":" + vipNetwork.V6Cidr
+			}
+			vipNetworkStringList = append(vipNetworkStringList, vipNetworkString)
 		}
 		sort.Strings(vipNetworkStringList)
 		checksum += utils.Hash(utils.Stringify(vipNetworkStringList))
@@ -1344,6 +1490,7 @@ type AviPkiProfileNode struct {
 	Tenant           string
 	CloudConfigCksum uint32
 	CACert           string
+	CRL              string
 	AviMarkers       utils.AviObjectMarkers
 }
 
@@ -1354,7 +1501,76 @@ func (v *AviPkiProfileNode) GetCheckSum() uint32 {
 }
 
 func (v *AviPkiProfileNode) CalculateCheckSum() {
-	checksum := lib.SSLKeyCertChecksum(v.Name, "", v.CACert, v.AviMarkers, nil, false)
+	checksum := lib.SSLKeyCertChecksum(v.Name, "", v.CACert+v.CRL, v.AviMarkers, nil, false)
+	v.CloudConfigCksum = checksum
+}
+
+// AviAppProfileNode is an application profile owned by a virtualservice, which carries the client certificate
+// settings of the HostRule of the host. The PKI profile is either created from the CA Secret, or referred by name.
+type AviAppProfileNode struct {
+	Name              string
+	Tenant            string
+	BaseProfile       string
+	CloudConfigCksum  uint32
+	ClientCertMode    string
+	PkiProfileName    string
+	PkiProfile        *AviPkiProfileNode
+	ClientCertHeaders []AviClientCertHeader
+	AviMarkers        utils.AviObjectMarkers
+}
+
+// AviClientCertHeader is a request header set with a field of the client certificate.
+type AviClientCertHeader struct {
+	Name  string
+	Value string
+}
+
+func (v *AviAppProfileNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviAppProfileNode) GetPkiProfileName() string {
+	if v.PkiProfile != nil {
+		return v.PkiProfile.Name
+	}
+	return v.PkiProfileName
+}
+
+func (v *AviAppProfileNode) CalculateCheckSum() {
+	var headers []string
+	for _, header := range v.ClientCertHeaders {
+		headers = append(headers, header.Name+":"+header.Value)
+	}
+	checksum := lib.AppProfileChecksum(v.Name, v.ClientCertMode, v.GetPkiProfileName(), headers, v.AviMarkers, nil, false)
+	v.CloudConfigCksum = checksum
+}
+
+// AviHealthMonitorNode is a health monitor owned by a pool, translated from the probes of the backend Pods.
+type AviHealthMonitorNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	Type             string
+	SendInterval     int32
+	ReceiveTimeout   int32
+	SuccessfulChecks int32
+	FailedChecks     int32
+	MonitorPort      int32
+	HTTPRequest      string
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviHealthMonitorNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviHealthMonitorNode) CalculateCheckSum() {
+	checksum := lib.HealthMonitorChecksum(v.Name, v.Type, v.HTTPRequest, v.SendInterval, v.ReceiveTimeout,
+		v.SuccessfulChecks, v.FailedChecks, v.MonitorPort, v.AviMarkers, nil, false)
 	v.CloudConfigCksum = checksum
 }
 
@@ -1379,11 +1595,14 @@ type AviPoolNode struct {
 	PkiProfile               *AviPkiProfileNode
 	NetworkPlacementSettings map[string][]string
 	HealthMonitors           []string
+	ProbeHealthMonitor       *AviHealthMonitorNode
 	ApplicationPersistence   string
 	VrfContext               string
 	T1Lr                     string // Only applicable to NSX-T cloud, if this value is set, we automatically should unset the VRF context value.
 	AviMarkers               utils.AviObjectMarkers
 	AttachedWithSharedVS     bool
+	// HTTPRuleBackend is set for the pools of the additional backends of an ingress path, configured via HTTPRule.
+	HTTPRuleBackend bool
 }
 
 func (v *AviPoolNode) GetCheckSum() uint32 {
@@ -1439,6 +1658,10 @@ func (v *AviPoolNode) CalculateCheckSum() {
 		checksum += v.PkiProfile.GetCheckSum()
 	}
 
+	if v.ProbeHealthMonitor != nil {
+		checksum += v.ProbeHealthMonitor.GetCheckSum()
+	}
+
 	if v.ApplicationPersistence != "" {
 		checksum += utils.Hash(v.ApplicationPersistence)
 	}
@@ -1449,6 +1672,10 @@ func (v *AviPoolNode) CalculateCheckSum() {
 		checksum += utils.Hash(v.T1Lr)
 	}
 
+	if gracefulDisableTimeout := lib.GetPoolGracefulDisableTimeout(); gracefulDisableTimeout > 0 {
+		checksum += utils.Hash(strconv.Itoa(int(gracefulDisableTimeout)))
+	}
+
 	v.CloudConfigCksum = checksum
 }
 
@@ -1502,6 +1729,9 @@ type AviPoolMetaServer struct {
 	Ip         avimodels.IPAddr
 	ServerNode string
 	Port       int32
+	// Draining is set for the servers of the terminating Pods, which are disabled in the pool.
+	Draining         bool   `json:",omitempty"`
+	AvailabilityZone string `json:",omitempty"`
 }
 
 type IngressHostPathSvc struct {
@@ -1514,6 +1744,8 @@ type IngressHostPathSvc struct {
 	TargetPort     int32
 	clusterContext string // required for Multi-cluster ingress
 	svcNamespace   string // required for Multi-cluster ingress
+	// httpRuleBackend is set for the additional backends of an ingress path from the HTTPRule of the host.
+	httpRuleBackend bool
 }
 
 type IngressHostMap map[string]HostMetadata
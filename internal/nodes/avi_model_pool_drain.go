@@ -0,0 +1,194 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// poolServerPod is a server of a pool along with the Pod backing it.
+type poolServerPod struct {
+	server    AviPoolMetaServer
+	namespace string
+	podName   string
+}
+
+// poolDrainTracker remembers the Pods backing the servers of the pools, so that the servers of the terminating Pods
+// are kept disabled in the pools for the drain timeout, after the Pods are removed from the Endpoints.
+type poolDrainTracker struct {
+	lock    sync.Mutex
+	servers map[string]map[string]poolServerPod
+	resyncs map[string]bool
+}
+
+var drainTracker = &poolDrainTracker{
+	servers: make(map[string]map[string]poolServerPod),
+	resyncs: make(map[string]bool),
+}
+
+func poolServerKey(server AviPoolMetaServer) string {
+	return *server.Ip.Addr + ":" + strconv.Itoa(int(server.Port))
+}
+
+// getTerminatingPod returns the Pod if it exists, and whether it is terminating.
+func getTerminatingPod(namespace, podName string) (*corev1.Pod, bool) {
+	if utils.GetInformers().PodInformer == nil {
+		return nil, false
+	}
+	pod, err := utils.GetInformers().PodInformer.Lister().Pods(namespace).Get(podName)
+	if err != nil {
+		return nil, false
+	}
+	return pod, pod.DeletionTimestamp != nil
+}
+
+// podDrainDeadline returns the time until which the server of the terminating Pod is kept disabled in the pool.
+// The deletionTimestamp of the Pod is set to the end of its grace period, so the drain starts from the deletion
+// request.
+func podDrainDeadline(pod *corev1.Pod) time.Time {
+	start := pod.DeletionTimestamp.Time
+	if pod.DeletionGracePeriodSeconds != nil {
+		start = start.Add(-time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+	}
+	return start.Add(time.Duration(lib.GetPoolDrainTimeout()) * time.Second)
+}
+
+// drainPodServer marks the server of the Pod as draining if the Pod is terminating. Returns false if the drain
+// timeout of the terminating Pod has passed, and the server should be removed from the pool.
+func drainPodServer(server *AviPoolMetaServer, namespace, podName, resyncKey, key string) bool {
+	if lib.GetPoolDrainTimeout() == 0 {
+		return true
+	}
+	pod, terminating := getTerminatingPod(namespace, podName)
+	if !terminating {
+		return true
+	}
+	deadline := podDrainDeadline(pod)
+	if !time.Now().Before(deadline) {
+		utils.AviLog.Infof("key: %s, msg: drain timeout of the terminating Pod %s/%s has passed, removing server %s", key,
+			namespace, podName, *server.Ip.Addr)
+		return false
+	}
+	server.Draining = true
+	drainTracker.scheduleResync(resyncKey, namespace, deadline)
+	return true
+}
+
+// recordServer remembers the Pod backing the server of the pool.
+func (t *poolDrainTracker) recordServer(poolKey string, server AviPoolMetaServer, namespace, podName string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if _, ok := t.servers[poolKey]; !ok {
+		t.servers[poolKey] = make(map[string]poolServerPod)
+	}
+	t.servers[poolKey][poolServerKey(server)] = poolServerPod{server: server, namespace: namespace, podName: podName}
+}
+
+// drainRemovedServers returns the servers of the pool, along with the disabled servers of the terminating Pods which
+// are removed from the Endpoints and are within the drain timeout. The servers of the Pods which do not exist
+// anymore are forgotten.
+func (t *poolDrainTracker) drainRemovedServers(poolKey string, servers []AviPoolMetaServer, resyncKey, key string) []AviPoolMetaServer {
+	t.lock.Lock()
+	known := make(map[string]poolServerPod, len(t.servers[poolKey]))
+	for serverKey, serverPod := range t.servers[poolKey] {
+		known[serverKey] = serverPod
+	}
+	t.lock.Unlock()
+	if len(known) == 0 {
+		return servers
+	}
+
+	current := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		current[poolServerKey(server)] = true
+	}
+	var forget []string
+	for serverKey, serverPod := range known {
+		if current[serverKey] {
+			continue
+		}
+		pod, terminating := getTerminatingPod(serverPod.namespace, serverPod.podName)
+		if pod == nil {
+			forget = append(forget, serverKey)
+			continue
+		}
+		if !terminating {
+			// The Pod may be marked for deletion after it is removed from the Endpoints.
+			continue
+		}
+		server := serverPod.server
+		if drainPodServer(&server, serverPod.namespace, serverPod.podName, resyncKey, key) {
+			utils.AviLog.Infof("key: %s, msg: draining server %s of the terminating Pod %s/%s", key, *server.Ip.Addr,
+				serverPod.namespace, serverPod.podName)
+			servers = append(servers, server)
+		} else {
+			forget = append(forget, serverKey)
+		}
+	}
+
+	if len(forget) > 0 {
+		t.lock.Lock()
+		for _, serverKey := range forget {
+			delete(t.servers[poolKey], serverKey)
+		}
+		if len(t.servers[poolKey]) == 0 {
+			delete(t.servers, poolKey)
+		}
+		t.lock.Unlock()
+	}
+	return servers
+}
+
+// ForgetPoolDrainServers removes the servers remembered for the pool, when the pool is deleted.
+func ForgetPoolDrainServers(tenant, poolName string) {
+	drainTracker.lock.Lock()
+	defer drainTracker.lock.Unlock()
+	delete(drainTracker.servers, tenant+"/"+poolName)
+}
+
+// GetPoolDrainServerCount returns the number of the servers remembered for the pool.
+func GetPoolDrainServerCount(tenant, poolName string) int {
+	drainTracker.lock.Lock()
+	defer drainTracker.lock.Unlock()
+	return len(drainTracker.servers[tenant+"/"+poolName])
+}
+
+// scheduleResync adds the key to the ingestion layer after the deadline, so that the servers whose drain timeout has
+// passed are removed from the pools.
+func (t *poolDrainTracker) scheduleResync(resyncKey, namespace string, deadline time.Time) {
+	timerKey := resyncKey + "/" + strconv.FormatInt(deadline.Unix(), 10)
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.resyncs[timerKey] {
+		return
+	}
+	t.resyncs[timerKey] = true
+	time.AfterFunc(time.Until(deadline)+time.Second, func() {
+		t.lock.Lock()
+		delete(t.resyncs, timerKey)
+		t.lock.Unlock()
+		ingestionQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
+		bkt := utils.Bkt(namespace, ingestionQueue.NumWorkers)
+		ingestionQueue.Workqueue[bkt].AddRateLimited(resyncKey)
+		utils.AviLog.Infof("key: %s, msg: drain timeout passed, syncing the servers", resyncKey)
+	})
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Defaults of the Kubernetes probe settings, and the limits of the Avi health monitor settings.
+const (
+	defaultProbePeriodSeconds    = 10
+	defaultProbeTimeoutSeconds   = 1
+	defaultProbeSuccessThreshold = 1
+	defaultProbeFailureThreshold = 3
+	maxHealthMonitorChecks       = 50
+)
+
+// isProbeHealthMonitorEnabled returns true if the Service opts in for a health monitor generated from the
+// probes of its Pods.
+func isProbeHealthMonitorEnabled(namespace, serviceName string) bool {
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(serviceName)
+	if err != nil {
+		return false
+	}
+	return svcObj.Annotations[lib.ProbeHealthMonitorAnnotation] == "true"
+}
+
+// populateProbeHealthMonitor sets the health monitor of the pool from the probes of the Pods, if the Service
+// opts in for it.
+func populateProbeHealthMonitor(poolNode *AviPoolNode, namespace, serviceName, key string) {
+	if !isProbeHealthMonitorEnabled(namespace, serviceName) {
+		return
+	}
+	poolNode.ProbeHealthMonitor = BuildProbeHealthMonitor(poolNode, namespace, serviceName, key)
+}
+
+// BuildProbeHealthMonitor translates the readinessProbe of the Pods backing the Service into a health monitor
+// for the pool, the livenessProbe is used for the Pods without a readinessProbe. Returns nil if the Pods have no
+// httpGet or tcpSocket probe, or if the probe port can not be reached on the servers of the pool.
+func BuildProbeHealthMonitor(poolNode *AviPoolNode, namespace, serviceName, key string) *AviHealthMonitorNode {
+	if !lib.IsProbeHealthMonitorEnabled() || utils.GetInformers().PodInformer == nil {
+		return nil
+	}
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(serviceName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error in obtaining the object for service: %s", key, serviceName)
+		return nil
+	}
+	servicePort := getPoolServicePort(poolNode, svcObj)
+	if servicePort == nil || len(svcObj.Spec.Selector) == 0 {
+		return nil
+	}
+	pods, err := utils.GetInformers().PodInformer.Lister().Pods(namespace).List(labels.SelectorFromSet(labels.Set(svcObj.Spec.Selector)))
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while listing the Pods of service %s: %v", key, serviceName, err)
+		return nil
+	}
+	// Pick the probe of the same Pod across the syncs, the Pods of a Service usually share the probe.
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	for _, pod := range pods {
+		container, servingPort := getServingContainer(pod, servicePort.TargetPort)
+		if container == nil {
+			continue
+		}
+		probe := container.ReadinessProbe
+		if probe == nil {
+			probe = container.LivenessProbe
+		}
+		if probe == nil {
+			continue
+		}
+		hmNode := probeToHealthMonitor(probe, container, servingPort, key)
+		if hmNode == nil {
+			return nil
+		}
+		hmNode.Name = lib.GetPoolHealthMonitorName(poolNode.Name)
+		hmNode.Tenant = poolNode.Tenant
+		hmNode.AviMarkers = utils.AviObjectMarkers{Namespace: namespace, ServiceName: serviceName}
+		utils.AviLog.Infof("key: %s, msg: health monitor for pool %s from the probe of Pod %s/%s: %s", key, poolNode.Name,
+			namespace, pod.Name, utils.Stringify(hmNode))
+		return hmNode
+	}
+	utils.AviLog.Infof("key: %s, msg: no probe found in the Pods of service %s/%s for pool %s", key, namespace, serviceName, poolNode.Name)
+	return nil
+}
+
+// getPoolServicePort returns the port of the Service the pool is created for.
+func getPoolServicePort(poolNode *AviPoolNode, svcObj *corev1.Service) *corev1.ServicePort {
+	if len(svcObj.Spec.Ports) == 1 {
+		return &svcObj.Spec.Ports[0]
+	}
+	for i, port := range svcObj.Spec.Ports {
+		if poolNode.PortName != "" && port.Name == poolNode.PortName {
+			return &svcObj.Spec.Ports[i]
+		}
+	}
+	for i, port := range svcObj.Spec.Ports {
+		if port.Port == poolNode.Port ||
+			(poolNode.TargetPort.IntValue() != 0 && port.TargetPort.IntValue() == poolNode.TargetPort.IntValue()) {
+			return &svcObj.Spec.Ports[i]
+		}
+	}
+	return nil
+}
+
+// getServingContainer returns the container of the Pod which serves the target port of the Service, along with
+// the container port.
+func getServingContainer(pod *corev1.Pod, targetPort intstr.IntOrString) (*corev1.Container, int32) {
+	for i, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if (targetPort.Type == intstr.String && port.Name == targetPort.StrVal) ||
+				(targetPort.Type == intstr.Int && port.ContainerPort == targetPort.IntVal) {
+				return &pod.Spec.Containers[i], port.ContainerPort
+			}
+		}
+	}
+	// The container ports need not be declared in the Pod spec.
+	if targetPort.Type == intstr.Int && targetPort.IntVal != 0 && len(pod.Spec.Containers) == 1 {
+		return &pod.Spec.Containers[0], targetPort.IntVal
+	}
+	return nil, 0
+}
+
+func resolveProbePort(port intstr.IntOrString, container *corev1.Container) int32 {
+	if port.Type == intstr.Int {
+		return port.IntVal
+	}
+	for _, containerPort := range container.Ports {
+		if containerPort.Name == port.StrVal {
+			return containerPort.ContainerPort
+		}
+	}
+	return 0
+}
+
+func probeToHealthMonitor(probe *corev1.Probe, container *corev1.Container, servingPort int32, key string) *AviHealthMonitorNode {
+	hmNode := &AviHealthMonitorNode{}
+	var probePort int32
+	if probe.HTTPGet != nil {
+		hmNode.Type = lib.HEALTH_MONITOR_HTTP
+		if probe.HTTPGet.Scheme == corev1.URISchemeHTTPS {
+			hmNode.Type = lib.HEALTH_MONITOR_HTTPS
+		}
+		hmNode.HTTPRequest = probeHTTPRequest(probe.HTTPGet)
+		probePort = resolveProbePort(probe.HTTPGet.Port, container)
+	} else if probe.TCPSocket != nil {
+		hmNode.Type = lib.HEALTH_MONITOR_TCP
+		probePort = resolveProbePort(probe.TCPSocket.Port, container)
+	} else {
+		utils.AviLog.Warnf("key: %s, msg: only httpGet and tcpSocket probes are supported for health monitors, container: %s", key, container.Name)
+		return nil
+	}
+	if probePort == 0 {
+		utils.AviLog.Warnf("key: %s, msg: port of the probe not found in container %s", key, container.Name)
+		return nil
+	}
+	if probePort != servingPort {
+		// The health monitor checks the servers of the pool, which are the Pods only in ClusterIP mode.
+		serviceType := lib.GetServiceType()
+		if serviceType == lib.NodePort || serviceType == lib.NodePortLocal {
+			utils.AviLog.Warnf("key: %s, msg: probe port %d of container %s is not reachable in %s mode", key, probePort, container.Name, serviceType)
+			return nil
+		}
+		hmNode.MonitorPort = probePort
+	}
+
+	hmNode.SendInterval = probe.PeriodSeconds
+	if hmNode.SendInterval == 0 {
+		hmNode.SendInterval = defaultProbePeriodSeconds
+	}
+	hmNode.ReceiveTimeout = probe.TimeoutSeconds
+	if hmNode.ReceiveTimeout == 0 {
+		hmNode.ReceiveTimeout = defaultProbeTimeoutSeconds
+	}
+	// The controller requires the receive timeout to be less than the send interval.
+	if hmNode.SendInterval <= hmNode.ReceiveTimeout {
+		hmNode.SendInterval = hmNode.ReceiveTimeout + 1
+	}
+	hmNode.SuccessfulChecks = probe.SuccessThreshold
+	if hmNode.SuccessfulChecks == 0 {
+		hmNode.SuccessfulChecks = defaultProbeSuccessThreshold
+	}
+	hmNode.FailedChecks = probe.FailureThreshold
+	if hmNode.FailedChecks == 0 {
+		hmNode.FailedChecks = defaultProbeFailureThreshold
+	}
+	if hmNode.SuccessfulChecks > maxHealthMonitorChecks {
+		hmNode.SuccessfulChecks = maxHealthMonitorChecks
+	}
+	if hmNode.FailedChecks > maxHealthMonitorChecks {
+		hmNode.FailedChecks = maxHealthMonitorChecks
+	}
+	return hmNode
+}
+
+// probeHTTPRequest builds the request line and the headers of the http health monitor from the httpGet probe.
+func probeHTTPRequest(httpGet *corev1.HTTPGetAction) string {
+	path := httpGet.Path
+	if path == "" {
+		path = "/"
+	}
+	request := []string{"GET " + path + " HTTP/1.0"}
+	for _, header := range httpGet.HTTPHeaders {
+		request = append(request, header.Name+": "+header.Value)
+	}
+	return strings.Join(request, "\r\n")
+}
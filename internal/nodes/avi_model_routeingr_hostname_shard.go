@@ -81,6 +81,7 @@ func HostNameShardAndPublish(objType, objname, namespace, key string, fullsync b
 			} else {
 				RouteIngrDeletePoolsByHostname(routeIgrObj, namespace, objname, key, fullsync, sharedQueue)
 			}
+			objects.SharedModelKeyLister().RestrictKey(key, nil)
 		}
 		return
 	}
@@ -117,6 +118,7 @@ func HostNameShardAndPublish(objType, objname, namespace, key string, fullsync b
 				PublishKeyToRestLayer(modelName, key, sharedQueue)
 			}
 		}
+		objects.SharedModelKeyLister().RestrictKey(key, getRouteIngrModels(routeIgrObj, key, hostsMap, true))
 		return
 	}
 
@@ -143,6 +145,28 @@ func HostNameShardAndPublish(objType, objname, namespace, key string, fullsync b
 			PublishKeyToRestLayer(modelName, key, sharedQueue)
 		}
 	}
+	objects.SharedModelKeyLister().RestrictKey(key, getRouteIngrModels(routeIgrObj, key, hostsMap, false))
+}
+
+// getRouteIngrModels returns the models the hosts of the ingress or route are processed into, excluding the models
+// the stale hosts are only removed from.
+func getRouteIngrModels(routeIgrObj RouteIngressModel, key string, hostsMap map[string]*objects.RouteIngrhost, evh bool) []string {
+	var modelNames []string
+	for host, hostData := range hostsMap {
+		var shardVsName lib.VSNameMetadata
+		if hostData.SecurePolicy == lib.PolicyPass {
+			shardVsName.Name = lib.GetPassthroughShardVSName(host, key)
+		} else if evh {
+			_, shardVsName = DeriveShardVSForEvh(host, key, routeIgrObj)
+		} else {
+			_, shardVsName = DeriveShardVS(host, key, routeIgrObj)
+		}
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
+		if !utils.HasElem(modelNames, modelName) {
+			modelNames = append(modelNames, modelName)
+		}
+	}
+	return modelNames
 }
 
 func getPathSvc(currentPathSvc []IngressHostPathSvc) map[string][]string {
@@ -182,7 +206,7 @@ func ProcessInsecureHosts(routeIgrObj RouteIngressModel, key string, parsedIng I
 		hostsMap[host].InsecurePolicy = lib.PolicyAllow
 		hostsMap[host].PathSvc = getPathSvc(pathsvcmap.ingressHPSvc)
 
-		modelName := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
 		if !found || aviModel == nil {
 			utils.AviLog.Infof("key: %s, msg: model not found, generating new model with name: %s", key, modelName)
@@ -279,7 +303,7 @@ func ProcessPassthroughHosts(routeIgrObj RouteIngressModel, key string, parsedIn
 		}
 
 		shardVsName := lib.GetPassthroughShardVSName(host, key)
-		modelName := lib.GetModelName(lib.GetTenant(), shardVsName)
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName)
 		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
 		if !found || aviModel == nil {
 			aviModel = NewAviObjectGraph()
@@ -310,7 +334,7 @@ func DeleteStaleData(routeIgrObj RouteIngressModel, key string, modelList *[]str
 			shardVsName.Name = lib.GetPassthroughShardVSName(host, key)
 		}
 
-		modelName := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
 		if !found || aviModel == nil {
 			utils.AviLog.Warnf("key: %s, msg: model not found during delete: %s", key, modelName)
@@ -360,7 +384,7 @@ func DeleteStaleDataForModelChange(routeIgrObj RouteIngressModel, namespace, obj
 		}
 
 		_, infraSettingName := objects.InfraSettingL7Lister().GetIngRouteToInfraSetting(routeIgrObj.GetNamespace() + "/" + routeIgrObj.GetName())
-		modelName := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
 		if !found || aviModel == nil {
 			utils.AviLog.Warnf("key: %s, msg: model not found during delete: %s", key, modelName)
@@ -404,7 +428,7 @@ func RouteIngrDeletePoolsByHostname(routeIgrObj RouteIngressModel, namespace, ob
 			shardVsName.Name = lib.GetPassthroughShardVSName(host, key)
 		}
 
-		modelName := lib.GetModelName(lib.GetTenant(), shardVsName.Name)
+		modelName := lib.GetModelName(lib.GetTenantInNamespace(routeIgrObj.GetNamespace()), shardVsName.Name)
 		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
 		if !found || aviModel == nil {
 			utils.AviLog.Warnf("key: %s, msg: model not found during delete: %s", key, modelName)
@@ -34,7 +34,7 @@ func (o *AviObjectGraph) BuildVSForPassthrough(vsName, namespace, hostname, key
 	// create the secured shared VS to listen on port 443
 	avi_vs_meta = &AviVsNode{
 		Name:               vsName,
-		Tenant:             lib.GetTenant(),
+		Tenant:             lib.GetTenantInNamespace(namespace),
 		SharedVS:           true,
 		ServiceEngineGroup: lib.GetSEGName(),
 	}
@@ -64,7 +64,7 @@ func (o *AviObjectGraph) BuildVSForPassthrough(vsName, namespace, hostname, key
 	// VSvip node to be shared by the secure and insecure VS
 	vsVipNode := &AviVSVIPNode{
 		Name:        lib.GetVsVipName(vsName),
-		Tenant:      lib.GetTenant(),
+		Tenant:      avi_vs_meta.Tenant,
 		FQDNs:       fqdns,
 		VrfContext:  vrfcontext,
 		VipNetworks: lib.GetVipNetworkList(),
@@ -102,7 +102,7 @@ func (o *AviObjectGraph) BuildGraphForPassthrough(svclist []IngressHostPathSvc,
 	pgName := lib.GetClusterName() + "--" + hostname
 	pgNode := o.GetPoolGroupByName(pgName)
 	if pgNode == nil {
-		pgNode = &AviPoolGroupNode{Name: pgName, Tenant: lib.GetTenant()}
+		pgNode = &AviPoolGroupNode{Name: pgName, Tenant: secureSharedVS.Tenant}
 		o.AddModelNode(pgNode)
 		pgNode.AviMarkers = lib.PopulatePassthroughPGMarkers(hostname)
 		utils.AviLog.Infof("key: %s, msg: adding PG %s for the passthrough VS: %s", key, pgName, secureSharedVS.Name)
@@ -133,7 +133,7 @@ func (o *AviObjectGraph) BuildGraphForPassthrough(svclist []IngressHostPathSvc,
 		if poolNode == nil {
 			poolNode = &AviPoolNode{
 				Name:       poolName,
-				Tenant:     lib.GetTenant(),
+				Tenant:     secureSharedVS.Tenant,
 				VrfContext: vrfContext,
 			}
 			poolNode.NetworkPlacementSettings, _ = lib.GetNodeNetworkMap()
@@ -203,7 +203,7 @@ func (o *AviObjectGraph) BuildGraphForPassthrough(svclist []IngressHostPathSvc,
 	if passChildVS == nil {
 		passChildVS = &AviVsNode{
 			Name:               secureSharedVS.Name + lib.PassthroughInsecure,
-			Tenant:             lib.GetTenant(),
+			Tenant:             secureSharedVS.Tenant,
 			VrfContext:         vrfContext,
 			ServiceEngineGroup: lib.GetSEGName(),
 			ApplicationProfile: utils.DEFAULT_L7_APP_PROFILE,
@@ -224,7 +224,7 @@ func (o *AviObjectGraph) BuildGraphForPassthrough(svclist []IngressHostPathSvc,
 func (o *AviObjectGraph) ConstructL4DataScript(vsName string, key string, vsNode *AviVsNode) *AviHTTPDataScriptNode {
 	dsScriptNode := &AviHTTPDataScriptNode{
 		Name:   lib.GetL7InsecureDSName(vsName),
-		Tenant: lib.GetTenant(),
+		Tenant: vsNode.Tenant,
 		DataScript: &DataScript{
 			Script: lib.PassthroughDatascript,
 			Evt:    "VS_DATASCRIPT_EVT_L4_REQUEST",
@@ -79,17 +79,20 @@ func findRoutePrefix(nodeRoutes, aviRoutes []*models.StaticRoute, key string) bo
 }
 
 func (o *AviObjectGraph) addRouteForNode(node *v1.Node, vrfName string, routeid int) ([]*models.StaticRoute, error) {
-	var nodeIP string
 	var nodeRoutes []*models.StaticRoute
 
+	// the next hop of a route is the InternalIP of the node of the same ip type as the podCIDR.
+	nodeIPs := make(map[string]string)
 	nodeAddrs := node.Status.Addresses
 	for _, addr := range nodeAddrs {
 		if addr.Type == "InternalIP" {
-			nodeIP = addr.Address
-			break
+			ipType := lib.GetIPType(addr.Address)
+			if _, ok := nodeIPs[ipType]; !ok {
+				nodeIPs[ipType] = addr.Address
+			}
 		}
 	}
-	if nodeIP == "" {
+	if len(nodeIPs) == 0 {
 		utils.AviLog.Errorf("Error in fetching nodeIP for %v", node.ObjectMeta.Name)
 		return nil, errors.New("nodeip not found")
 	}
@@ -99,8 +102,6 @@ func (o *AviObjectGraph) addRouteForNode(node *v1.Node, vrfName string, routeid
 		utils.AviLog.Errorf("Error in fetching Pod CIDR for %v: %s", node.ObjectMeta.Name, err.Error())
 		return nil, errors.New("podcidr not found")
 	}
-	nodeipType := "V4"
-
 	for _, podCIDR := range podCIDRs {
 		s := strings.Split(podCIDR, "/")
 		if len(s) != 2 {
@@ -114,9 +115,15 @@ func (o *AviObjectGraph) addRouteForNode(node *v1.Node, vrfName string, routeid
 			return nil, err
 		}
 
+		prefixipType := lib.GetIPType(s[0])
+		nodeIP, ok := nodeIPs[prefixipType]
+		if !ok {
+			utils.AviLog.Warnf("%s nodeIP not found for Pod CIDR %s of %v, skipping the static route", prefixipType, podCIDR, node.ObjectMeta.Name)
+			continue
+		}
+
 		clusterName := lib.GetClusterName()
 		labels := lib.GetLabels()
-		prefixipType := "V4"
 		mask := int32(m)
 		routeIDString := clusterName + "-" + strconv.Itoa(routeid)
 		nodeRoute := models.StaticRoute{
@@ -130,7 +137,7 @@ func (o *AviObjectGraph) addRouteForNode(node *v1.Node, vrfName string, routeid
 			},
 			NextHop: &models.IPAddr{
 				Addr: &nodeIP,
-				Type: &nodeipType,
+				Type: &prefixipType,
 			},
 			Labels: labels,
 		}
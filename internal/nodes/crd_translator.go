@@ -17,12 +17,14 @@ package nodes
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/vmware/alb-sdk/go/models"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
@@ -63,6 +65,9 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 	vsHTTPPolicySets := []string{}
 	vsDatascripts := []string{}
 	var analyticsPolicy *models.AnalyticsPolicy
+	var connectionsRateLimit *models.RateProfile
+	var securityRules []AviHTTPSecurity
+	var clientAuthAppProfile *AviAppProfileNode
 
 	// Get the existing VH domain names and then manipulate it based on the aliases in Hostrule CRD.
 	VHDomainNames := vsNode.GetVHDomainNames()
@@ -97,6 +102,15 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 			vsAppProfile = fmt.Sprintf("/api/applicationprofile?name=%s", hostrule.Spec.VirtualHost.ApplicationProfile)
 		}
 
+		// The client certificate settings are applied on the SNI/EVH child virtualservices of the host.
+		if hostrule.Spec.VirtualHost.TLS.ClientAuth != nil && !vsNode.IsSharedVS() && !vsNode.IsDedicatedVS() {
+			clientAuthAppProfile = buildClientAuthAppProfile(host, key, hostrule, vsNode)
+			if clientAuthAppProfile != nil {
+				vsAppProfile = fmt.Sprintf("/api/applicationprofile?name=%s", clientAuthAppProfile.Name)
+			}
+		}
+		updateHostRuleClientAuthStatus(key, hostrule, vsNode)
+
 		if hostrule.Spec.VirtualHost.ErrorPageProfile != "" {
 			vsErrorPageProfile = fmt.Sprintf("/api/errorpageprofile?name=%s", hostrule.Spec.VirtualHost.ErrorPageProfile)
 		}
@@ -122,6 +136,25 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 			}
 		}
 
+		if hostrule.Spec.VirtualHost.Security != nil {
+			securityRules = buildHostRuleSecurityRules(hostrule.Spec.VirtualHost.Security)
+			if hostrule.Spec.VirtualHost.Security.RateLimits != nil && hostrule.Spec.VirtualHost.Security.RateLimits.Connections != nil {
+				connections := *hostrule.Spec.VirtualHost.Security.RateLimits.Connections
+				if connections.Period == 0 {
+					connections.Period = 1
+				}
+				actionType := "RL_ACTION_CLOSE_CONN"
+				connectionsRateLimit = &models.RateProfile{
+					Action: &models.RateLimiterAction{Type: &actionType},
+					RateLimiter: &models.RateLimiter{
+						Count:   &connections.Count,
+						Period:  &connections.Period,
+						BurstSz: &connections.Burst,
+					},
+				}
+			}
+		}
+
 		if hostrule.Spec.VirtualHost.TCPSettings != nil {
 			if vsNode.IsSharedVS() || vsNode.IsDedicatedVS() {
 				portProtocols = []AviPortHostProtocol{}
@@ -183,12 +216,15 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 	vsNode.SetWafPolicyRef(vsWafPolicy)
 	vsNode.SetHttpPolicySetRefs(vsHTTPPolicySets)
 	vsNode.SetAppProfileRef(vsAppProfile)
+	vsNode.SetClientAuthAppProfile(clientAuthAppProfile)
 	vsNode.SetAnalyticsProfileRef(vsAnalyticsProfile)
 	vsNode.SetErrorPageProfileRef(vsErrorPageProfile)
 	vsNode.SetSSLProfileRef(vsSslProfile)
 	vsNode.SetVsDatascriptRefs(vsDatascripts)
 	vsNode.SetEnabled(vsEnabled)
 	vsNode.SetAnalyticsPolicy(analyticsPolicy)
+	vsNode.SetConnectionsRateLimit(connectionsRateLimit)
+	setHostRuleSecurityPolicySet(host, vsNode, securityRules)
 	vsNode.SetPortProtocols(portProtocols)
 	vsNode.SetVSVIPLoadBalancerIP(lbIP)
 	vsNode.SetVHDomainNames(VHDomainNames)
@@ -198,6 +234,179 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 	vsNode.SetServiceMetadata(serviceMetadataObj)
 }
 
+// buildHostRuleSecurityRules returns the http security rules for the client IP restrictions and the
+// requests rate limit of the hostrule. Clients from the denied CIDRs and IP groups, or from outside
+// the allowed ones, are responded with 403 Forbidden, ahead of the rate limit.
+func buildHostRuleSecurityRules(security *akov1alpha1.HostRuleSecurity) []AviHTTPSecurity {
+	var securityRules []AviHTTPSecurity
+	if len(security.DeniedCIDRs) > 0 || len(security.DeniedIPGroups) > 0 {
+		securityRules = append(securityRules, AviHTTPSecurity{
+			Action:     lib.HTTPSecurityActionSendResponse,
+			StatusCode: lib.HTTPLocalResponseStatusForbidden,
+			Enable:     true,
+			ClientIP: &AviHTTPSecurityClientIPMatch{
+				MatchCriteria: "IS_IN",
+				Prefixes:      security.DeniedCIDRs,
+				IPGroups:      security.DeniedIPGroups,
+			},
+		})
+	}
+	if len(security.AllowedCIDRs) > 0 || len(security.AllowedIPGroups) > 0 {
+		securityRules = append(securityRules, AviHTTPSecurity{
+			Action:     lib.HTTPSecurityActionSendResponse,
+			StatusCode: lib.HTTPLocalResponseStatusForbidden,
+			Enable:     true,
+			ClientIP: &AviHTTPSecurityClientIPMatch{
+				MatchCriteria: "IS_NOT_IN",
+				Prefixes:      security.AllowedCIDRs,
+				IPGroups:      security.AllowedIPGroups,
+			},
+		})
+	}
+	if security.RateLimits != nil && security.RateLimits.Requests != nil {
+		requests := security.RateLimits.Requests
+		rateLimit := &AviHTTPSecurityRateLimit{
+			Count:       requests.Count,
+			Period:      requests.Period,
+			Burst:       requests.Burst,
+			PerClientIP: requests.PerClientIP,
+		}
+		if rateLimit.Period == 0 {
+			rateLimit.Period = 1
+		}
+		securityRules = append(securityRules, AviHTTPSecurity{
+			Action:    lib.HTTPSecurityActionRateLimit,
+			Enable:    true,
+			RateLimit: rateLimit,
+		})
+	}
+	return securityRules
+}
+
+// setHostRuleSecurityPolicySet replaces the httppolicyset of the hostrule security rules in the vsNode.
+func setHostRuleSecurityPolicySet(host string, vsNode AviVsEvhSniModel, securityRules []AviHTTPSecurity) {
+	policyName := lib.GetHostRuleSecurityPolicySetName(vsNode.GetName())
+	var policyRefs []*AviHttpPolicySetNode
+	if len(securityRules) > 0 {
+		policyNode := &AviHttpPolicySetNode{
+			Name:          policyName,
+			Tenant:        vsNode.GetTenant(),
+			SecurityRules: securityRules,
+		}
+		policyNode.AviMarkers = lib.PopulateHTTPPolicysetNodeMarkers("", host, "", nil, nil)
+		policyRefs = append(policyRefs, policyNode)
+	}
+	for _, policy := range vsNode.GetHttpPolicyRefs() {
+		if policy.Name != policyName {
+			policyRefs = append(policyRefs, policy)
+		}
+	}
+	vsNode.SetHttpPolicyRefs(policyRefs)
+}
+
+// updateHostRuleClientAuthStatus reports in the status of the hostrule that its clientAuth is not applied, when the
+// host is served by a shared or a dedicated virtualservice.
+func updateHostRuleClientAuthStatus(key string, hostrule *akov1alpha1.HostRule, vsNode AviVsEvhSniModel) {
+	if hostrule.Status.Status != lib.StatusAccepted {
+		return
+	}
+	var errMsg string
+	if hostrule.Spec.VirtualHost.TLS.ClientAuth != nil && (vsNode.IsSharedVS() || vsNode.IsDedicatedVS()) {
+		errMsg = fmt.Sprintf("clientAuth is not supported on the shared or dedicated virtualservice %s, and is not applied", vsNode.GetName())
+		utils.AviLog.Warnf("key: %s, msg: hostrule %s/%s: %s", key, hostrule.Namespace, hostrule.Name, errMsg)
+	}
+	if hostrule.Status.Error == errMsg {
+		return
+	}
+	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{
+		Status: lib.StatusAccepted,
+		Error:  errMsg,
+	})
+}
+
+// buildClientAuthAppProfile returns the application profile with the client certificate settings of the hostrule.
+// With a caSecret, the PKI profile is created from the CA certificates in the Secret, along with the CRL if present.
+func buildClientAuthAppProfile(host, key string, hostrule *akov1alpha1.HostRule, vsNode AviVsEvhSniModel) *AviAppProfileNode {
+	clientAuth := hostrule.Spec.VirtualHost.TLS.ClientAuth
+	// The profile is based on the one the virtualservice uses otherwise.
+	baseProfile := utils.DEFAULT_L7_SECURE_APP_PROFILE
+	if evhNode, ok := vsNode.(*AviEvhVsNode); ok && evhNode.ApplicationProfile != "" {
+		baseProfile = evhNode.ApplicationProfile
+	}
+	appProfile := &AviAppProfileNode{
+		Name:           lib.GetClientAuthAppProfileName(vsNode.GetName()),
+		Tenant:         vsNode.GetTenant(),
+		BaseProfile:    baseProfile,
+		ClientCertMode: lib.SSLClientCertificateRequire,
+		PkiProfileName: clientAuth.PKIProfile,
+		AviMarkers:     lib.PopulateVSNodeMarkers("", host, ""),
+	}
+	if clientAuth.Mode == akov1alpha1.ClientAuthModeRequest {
+		appProfile.ClientCertMode = lib.SSLClientCertificateRequest
+	}
+
+	if clientAuth.CASecret != "" {
+		secret, err := utils.GetInformers().SecretInformer.Lister().Secrets(hostrule.Namespace).Get(clientAuth.CASecret)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: unable to get the CA secret %s/%s of hostrule %s: %v", key, hostrule.Namespace, clientAuth.CASecret, hostrule.Name, err)
+			return nil
+		}
+		caCert := string(secret.Data[lib.ClientAuthCACertKey])
+		if caCert == "" {
+			utils.AviLog.Warnf("key: %s, msg: %s not found in the CA secret %s/%s of hostrule %s", key, lib.ClientAuthCACertKey, hostrule.Namespace, clientAuth.CASecret, hostrule.Name)
+			return nil
+		}
+		appProfile.PkiProfile = &AviPkiProfileNode{
+			Name:       lib.GetClientAuthPKIProfileName(vsNode.GetName()),
+			Tenant:     vsNode.GetTenant(),
+			CACert:     caCert,
+			CRL:        string(secret.Data[lib.ClientAuthCRLKey]),
+			AviMarkers: appProfile.AviMarkers,
+		}
+	}
+
+	for _, header := range clientAuth.Headers {
+		appProfile.ClientCertHeaders = append(appProfile.ClientCertHeaders, AviClientCertHeader{
+			Name:  header.Name,
+			Value: lib.ClientCertHeaderVars[string(header.Value)],
+		})
+	}
+	utils.AviLog.Infof("key: %s, msg: added client auth application profile %s for host %s", key, appProfile.Name, host)
+	return appProfile
+}
+
+// getHostRuleOCSPConfig returns the OCSP stapling settings of the certificate of the host, from the HostRule
+// of the host. AKO sets the URL action and the request interval explicitly, so that the checksum of the settings
+// matches the SSLKeyAndCertificate read back from the Avi controller.
+func getHostRuleOCSPConfig(host, key string) *models.OCSPConfig {
+	found, hrNamespaceName := objects.SharedCRDLister().GetFQDNToHostruleMappingWithType(host)
+	if !found {
+		return nil
+	}
+	hrNSName := strings.Split(hrNamespaceName, "/")
+	hostrule, err := lib.AKOControlConfig().CRDInformers().HostRuleInformer.Lister().HostRules(hrNSName[0]).Get(hrNSName[1])
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: hostrule not found err: %+v", key, err)
+		return nil
+	} else if hostrule.Status.Status == lib.StatusRejected || hostrule.Spec.VirtualHost.TLS.OCSPStapling == nil {
+		return nil
+	}
+	ocspStapling := hostrule.Spec.VirtualHost.TLS.OCSPStapling
+	urlAction := ocspStapling.URLAction
+	if urlAction == "" {
+		urlAction = lib.OCSPResponderURLFailover
+	}
+	frequency := ocspStapling.Frequency
+	if frequency == 0 {
+		frequency = lib.DefaultOCSPRequestInterval
+	}
+	return &models.OCSPConfig{
+		ResponderURLLists: ocspStapling.ResponderURLs,
+		URLAction:         &urlAction,
+		OcspReqInterval:   &frequency,
+	}
+}
+
 // BuildPoolHTTPRule notes
 // when we get an ingress update and we are building the corresponding pools of that ingress
 // we need to get all httprules which match ingress's host/path
@@ -205,6 +414,7 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 	found, pathRules := objects.SharedCRDLister().GetFqdnHTTPRulesMapping(host)
 	if !found {
 		utils.AviLog.Debugf("key: %s, msg: HTTPRules for fqdn %s not found", key, host)
+		buildHTTPRulePolicySets(host, key, vsNode, nil, false)
 		return
 	}
 
@@ -234,12 +444,20 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 
 	// iterate through httpRule which we get from GetFqdnHTTPRulesMapping
 	// must contain fqdn.com: {path1: rr1, path2: rr1, path3: rr2}
+	actionPaths := make(map[string]akov1alpha1.HTTPRulePaths)
+	unpinnedPaths := make(map[string][]string)
 	for path, rule := range pathRules {
 		rrNamespace := strings.Split(rule, "/")[0]
 		httpRulePath, ok := httpruleNameObjMap[rule+path]
 		if !ok {
 			continue
 		}
+		if hasHTTPRulePathActions(httpRulePath) || hasHTTPRuleBackendMatches(httpRulePath) {
+			actionPaths[path] = httpRulePath
+		}
+		if !isSNI && !isDedicated && hasHTTPRuleBackendMatches(httpRulePath) {
+			unpinnedPaths[rule] = append(unpinnedPaths[rule], path)
+		}
 		if httpRulePath.TLS.Type != "" && httpRulePath.TLS.Type != lib.TypeTLSReencrypt {
 			continue
 		}
@@ -250,6 +468,7 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 			pathPkiProfile := pool.PkiProfileRef
 			destinationCertNode := pool.PkiProfile
 			pathHMs := pool.HealthMonitors
+			pathProbeHM := pool.ProbeHealthMonitor
 			if poolPath == "" && path == "/" {
 				// In case of openfhit Route, the path could be empty, in that case, treat
 				// httprule targt path / as that of empty path, to match the pool appropriately.
@@ -290,7 +509,7 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 					if httpRulePath.TLS.DestinationCA != "" {
 						destinationCertNode = &AviPkiProfileNode{
 							Name:   lib.GetPoolPKIProfileName(poolName),
-							Tenant: lib.GetTenant(),
+							Tenant: pool.Tenant,
 							CACert: httpRulePath.TLS.DestinationCA,
 						}
 						destinationCertNode.AviMarkers = lib.PopulatePoolNodeMarkers(namespace, host, "", pool.AviMarkers.ServiceName, []string{ingName}, []string{path})
@@ -314,11 +533,16 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 					}
 				}
 
+				if httpRulePath.ProbeHealthMonitor && pathProbeHM == nil {
+					pathProbeHM = BuildProbeHealthMonitor(pool, namespace, pool.AviMarkers.ServiceName, key)
+				}
+
 				pool.SniEnabled = isPathSniEnabled
 				pool.SslProfileRef = pathSslProfile
 				pool.PkiProfileRef = pathPkiProfile
 				pool.PkiProfile = destinationCertNode
 				pool.HealthMonitors = pathHMs
+				pool.ProbeHealthMonitor = pathProbeHM
 				pool.ApplicationPersistence = persistenceProfile
 
 				// from this path, generate refs to this pool node
@@ -350,4 +574,242 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 		}
 	}
 
+	// The pools are selected via httppolicysets only in the SNI, EVH and dedicated virtualservices.
+	buildHTTPRulePolicySets(host, key, vsNode, actionPaths, isSNI || isDedicated)
+	for _, rule := range getHTTPRules {
+		updateHTTPRuleBackendMatchStatus(host, rule, key, unpinnedPaths[rule])
+	}
+}
+
+// updateHTTPRuleBackendMatchStatus reports the paths of the HTTPRule with backend match conditions, which are not applied
+// on the Shared virtualservice of the insecure host, as its pools are selected by the poolgroup priority labels.
+// The error is cleared from the HTTPRule status once the match conditions are applied again.
+func updateHTTPRuleBackendMatchStatus(host, rule, key string, unpinnedPaths []string) {
+	pathNSName := strings.Split(rule, "/")
+	httpRuleObj, err := lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Lister().HTTPRules(pathNSName[0]).Get(pathNSName[1])
+	if err != nil || httpRuleObj.Status.Status != lib.StatusAccepted {
+		return
+	}
+
+	var errMsg string
+	if len(unpinnedPaths) > 0 {
+		sort.Strings(unpinnedPaths)
+		errMsg = fmt.Sprintf("backend match conditions on paths %s are not supported for the insecure host %s, the backends are selected by weight",
+			strings.Join(unpinnedPaths, ", "), host)
+		utils.AviLog.Warnf("key: %s, msg: httprule %s: %s", key, rule, errMsg)
+	}
+	if httpRuleObj.Status.Error == errMsg {
+		return
+	}
+	status.UpdateHTTPRuleStatus(key, httpRuleObj, status.UpdateCRDStatusOptions{
+		Status: lib.StatusAccepted,
+		Error:  errMsg,
+	})
+}
+
+// getHTTPRuleBackends returns the additional backends of the HTTPRule path target of the host, which is the same as
+// the ingress path.
+func getHTTPRuleBackends(host, path, key string) []akov1alpha1.HTTPRuleBackend {
+	found, pathRules := objects.SharedCRDLister().GetFqdnHTTPRulesMapping(host)
+	if !found {
+		return nil
+	}
+	rule, ok := pathRules[path]
+	if !ok {
+		return nil
+	}
+	pathNSName := strings.Split(rule, "/")
+	httpRuleObj, err := lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Lister().HTTPRules(pathNSName[0]).Get(pathNSName[1])
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: httprule not found err: %+v", key, err)
+		return nil
+	} else if httpRuleObj.Status.Status != lib.StatusAccepted {
+		return nil
+	}
+	for _, rulePath := range httpRuleObj.Spec.Paths {
+		if rulePath.Target == path {
+			return rulePath.Backends
+		}
+	}
+	return nil
+}
+
+func hasHTTPRulePathActions(httpRulePath akov1alpha1.HTTPRulePaths) bool {
+	return len(httpRulePath.RequestHeaders) > 0 ||
+		len(httpRulePath.ResponseHeaders) > 0 ||
+		httpRulePath.RewritePrefix != "" ||
+		httpRulePath.Redirect != nil
+}
+
+func hasHTTPRuleBackendMatches(httpRulePath akov1alpha1.HTTPRulePaths) bool {
+	for _, backend := range httpRulePath.Backends {
+		if backend.Match != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// buildHTTPRulePolicySets replaces the httppolicysets of the HTTPRule path actions for the host in the vsNode,
+// with one httppolicyset per path target in actionPaths. These follow the other httppolicysets, ordered by the
+// longest path first, so that the pools are selected on the request path before it is rewritten, and the redirect
+// of the most specific path takes precedence. If pinBackends is set, the httppolicysets selecting the pools of
+// the backends with match conditions are placed ahead of the pool selection of the paths.
+func buildHTTPRulePolicySets(host, key string, vsNode AviVsEvhSniModel, actionPaths map[string]akov1alpha1.HTTPRulePaths, pinBackends bool) {
+	var policyRefs []*AviHttpPolicySetNode
+	for _, policy := range vsNode.GetHttpPolicyRefs() {
+		if policy.HTTPRuleHost != host {
+			policyRefs = append(policyRefs, policy)
+		}
+	}
+
+	paths := make([]string, 0, len(actionPaths))
+	for path := range actionPaths {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) > len(paths[j])
+		}
+		return paths[i] < paths[j]
+	})
+
+	var rulePolicyRefs, backendPolicyRefs []*AviHttpPolicySetNode
+	for _, path := range paths {
+		httpRulePath := actionPaths[path]
+		if pinBackends && hasHTTPRuleBackendMatches(httpRulePath) {
+			if policyNode := buildHTTPRuleBackendPolicySet(host, path, key, vsNode, httpRulePath); policyNode != nil {
+				backendPolicyRefs = append(backendPolicyRefs, policyNode)
+			}
+		}
+		if !hasHTTPRulePathActions(httpRulePath) {
+			continue
+		}
+		httpPGPath := AviHostPathPortPoolPG{
+			Host:            []string{host},
+			RequestHeaders:  buildHTTPRuleHeaderActions(httpRulePath.RequestHeaders),
+			ResponseHeaders: buildHTTPRuleHeaderActions(httpRulePath.ResponseHeaders),
+		}
+		if path != "" {
+			httpPGPath.Path = []string{path}
+			httpPGPath.MatchCriteria = "BEGINS_WITH"
+		}
+		if httpRulePath.Redirect != nil {
+			httpPGPath.Redirect = &AviHTTPRedirect{
+				Protocol: httpRulePath.Redirect.Protocol,
+				Host:     httpRulePath.Redirect.Host,
+				Path:     httpRulePath.Redirect.Path,
+				Port:     httpRulePath.Redirect.Port,
+			}
+			switch httpRulePath.Redirect.StatusCode {
+			case 301:
+				httpPGPath.Redirect.StatusCode = lib.STATUS_REDIRECT_PERMANENT
+			case 307:
+				httpPGPath.Redirect.StatusCode = lib.STATUS_REDIRECT_TEMPORARY
+			default:
+				httpPGPath.Redirect.StatusCode = lib.STATUS_REDIRECT
+			}
+		} else if httpRulePath.RewritePrefix != "" {
+			// Only the path target prefix is replaced, the rest of the request path is retained.
+			httpPGPath.RewriteURL = &AviHTTPRewriteURL{
+				Path:          httpRulePath.RewritePrefix,
+				ReplacePrefix: true,
+				PrefixTokens:  int32(len(strings.FieldsFunc(path, func(c rune) bool { return c == '/' }))),
+			}
+		}
+
+		policyNode := &AviHttpPolicySetNode{
+			Name:         lib.GetHTTPRulePolicySetName(vsNode.GetName(), host, path),
+			Tenant:       vsNode.GetTenant(),
+			HppMap:       []AviHostPathPortPoolPG{httpPGPath},
+			HTTPRuleHost: host,
+		}
+		policyNode.AviMarkers = lib.PopulateHTTPPolicysetNodeMarkers("", host, "", nil, []string{path})
+		rulePolicyRefs = append(rulePolicyRefs, policyNode)
+		utils.AviLog.Debugf("key: %s, msg: built httppolicyset %s for the httprule actions of %s%s", key, policyNode.Name, host, path)
+	}
+
+	backendPolicyRefs = append(backendPolicyRefs, policyRefs...)
+	vsNode.SetHttpPolicyRefs(append(backendPolicyRefs, rulePolicyRefs...))
+}
+
+// buildHTTPRuleBackendPolicySet builds the httppolicyset selecting the pools of the HTTPRule backends of the path
+// target, for the requests matching all the headers and the cookie of the backends.
+func buildHTTPRuleBackendPolicySet(host, path, key string, vsNode AviVsEvhSniModel, httpRulePath akov1alpha1.HTTPRulePaths) *AviHttpPolicySetNode {
+	var hppMap []AviHostPathPortPoolPG
+	for _, backend := range httpRulePath.Backends {
+		if backend.Match == nil {
+			continue
+		}
+		var poolName string
+		for _, pool := range vsNode.GetPoolRefs() {
+			if len(pool.AviMarkers.Host) > 0 && pool.AviMarkers.Host[0] == host &&
+				len(pool.AviMarkers.Path) > 0 && pool.AviMarkers.Path[0] == path &&
+				pool.AviMarkers.ServiceName == backend.ServiceName {
+				poolName = pool.Name
+				break
+			}
+		}
+		if poolName == "" {
+			utils.AviLog.Debugf("key: %s, msg: pool for httprule backend %s of %s%s not found", key, backend.ServiceName, host, path)
+			continue
+		}
+
+		httpPGPath := AviHostPathPortPoolPG{
+			Host: []string{host},
+			Pool: poolName,
+		}
+		if path != "" {
+			httpPGPath.Path = []string{path}
+			httpPGPath.MatchCriteria = "BEGINS_WITH"
+		}
+		for _, header := range backend.Match.Headers {
+			httpPGPath.HdrMatches = append(httpPGPath.HdrMatches, AviHTTPHeaderMatch{
+				Name:          header.Name,
+				MatchCriteria: "HDR_EQUALS",
+				MatchCase:     "SENSITIVE",
+				Value:         []string{header.Value},
+			})
+		}
+		if len(backend.Match.Cookies) > 0 {
+			httpPGPath.CookieMatch = &AviHTTPCookieMatch{
+				Name:          backend.Match.Cookies[0].Name,
+				MatchCriteria: "HDR_EQUALS",
+				MatchCase:     "SENSITIVE",
+				Value:         backend.Match.Cookies[0].Value,
+			}
+		}
+		hppMap = append(hppMap, httpPGPath)
+	}
+	if len(hppMap) == 0 {
+		return nil
+	}
+
+	policyNode := &AviHttpPolicySetNode{
+		Name:         lib.GetHTTPRuleBackendPolicySetName(vsNode.GetName(), host, path),
+		Tenant:       vsNode.GetTenant(),
+		HppMap:       hppMap,
+		HTTPRuleHost: host,
+	}
+	policyNode.AviMarkers = lib.PopulateHTTPPolicysetNodeMarkers("", host, "", nil, []string{path})
+	utils.AviLog.Debugf("key: %s, msg: built httppolicyset %s for the httprule backends of %s%s", key, policyNode.Name, host, path)
+	return policyNode
+}
+
+func buildHTTPRuleHeaderActions(headers []akov1alpha1.HTTPRuleHeaderAction) []AviHTTPHeaderAction {
+	var headerActions []AviHTTPHeaderAction
+	for _, header := range headers {
+		headerAction := AviHTTPHeaderAction{Name: header.Name, Value: header.Value}
+		switch header.Action {
+		case akov1alpha1.HTTPRuleHeaderActionAdd:
+			headerAction.Action = lib.HTTPAddHeader
+		case akov1alpha1.HTTPRuleHeaderActionReplace:
+			headerAction.Action = lib.HTTPReplaceHeader
+		case akov1alpha1.HTTPRuleHeaderActionRemove:
+			headerAction.Action = lib.HTTPRemoveHeader
+			headerAction.Value = ""
+		}
+		headerActions = append(headerActions, headerAction)
+	}
+	return headerActions
 }
@@ -27,6 +27,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 func DequeueIngestion(key string, fullsync bool) {
@@ -38,6 +39,11 @@ func DequeueIngestion(key string, fullsync bool) {
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 
 	objType, namespace, name := lib.ExtractTypeNameNamespace(key)
+	if !fullsync && lib.IsSyncStatusObjType(objType) {
+		// An object moved to another model is not reported the sync status of the previous model.
+		objects.SharedModelKeyLister().BeginKey(key)
+		defer objects.SharedModelKeyLister().EndKey(key)
+	}
 	if objType == utils.Pod {
 		handlePod(key, namespace, name, fullsync)
 	}
@@ -109,7 +115,7 @@ func DequeueIngestion(key string, fullsync bool) {
 		if found {
 			objects.SharedlbLister().Delete(namespace + "/" + name)
 			utils.AviLog.Infof("key: %s, msg: service transitioned from type loadbalancer to ClusterIP or NodePort, will delete model", name)
-			model_name := lib.GetModelName(lib.GetTenant(), lib.Encode(lib.GetNamePrefix()+namespace+"-"+name, lib.L4VS))
+			model_name := lib.GetModelName(lib.GetTenantInNamespace(namespace), lib.Encode(lib.GetNamePrefix()+namespace+"-"+name, lib.L4VS))
 			objects.SharedAviGraphLister().Save(model_name, nil)
 			if !fullsync {
 				PublishKeyToRestLayer(model_name, key, sharedQueue)
@@ -131,6 +137,16 @@ func DequeueIngestion(key string, fullsync bool) {
 		}
 	}
 
+	// Push Services referring to the L4Rule via annotation.
+	if objType == lib.L4Rule && !lib.GetAdvancedL4() {
+		svcNames, svcFound := schema.GetParentServices(name, namespace, key)
+		if svcFound && utils.CheckIfNamespaceAccepted(namespace) {
+			for _, svcNSNameKey := range svcNames {
+				handleL4Service(utils.L4LBService+"/"+svcNSNameKey, fullsync)
+			}
+		}
+	}
+
 	if !ingressFound && !lib.GetAdvancedL4() && !mciFound {
 		// If ingress is not found, let's do the other checks.
 		if objType == utils.L4LBService {
@@ -148,7 +164,7 @@ func DequeueIngestion(key string, fullsync bool) {
 				aviModelGraph := NewAviObjectGraph()
 				aviModelGraph.BuildL4LBGraph(namespace, name, key)
 				if len(aviModelGraph.GetOrderedNodes()) > 0 {
-					model_name := lib.GetModelName(lib.GetTenant(), aviModelGraph.GetAviVS()[0].Name)
+					model_name := lib.GetModelName(aviModelGraph.GetAviVS()[0].Tenant, aviModelGraph.GetAviVS()[0].Name)
 					ok := saveAviModel(model_name, aviModelGraph, key)
 					if ok && !fullsync {
 						PublishKeyToRestLayer(model_name, key, sharedQueue)
@@ -179,7 +195,7 @@ func DequeueIngestion(key string, fullsync bool) {
 			for _, gatewayKey := range gateways {
 				// Check the gateway has a valid subscription or not. If not, delete it.
 				namespace, _, gwName := lib.ExtractTypeNameNamespace(gatewayKey)
-				modelName := lib.GetModelName(lib.GetTenant(), lib.Encode(lib.GetNamePrefix()+namespace+"-"+gwName, lib.ADVANCED_L4))
+				modelName := lib.GetModelName(lib.GetTenantInNamespace(namespace), lib.Encode(lib.GetNamePrefix()+namespace+"-"+gwName, lib.ADVANCED_L4))
 				if isGatewayDelete(gatewayKey, key) {
 					// Check if a model corresponding to the gateway exists or not in memory.
 					if found, _ := objects.SharedAviGraphLister().Get(modelName); found {
@@ -199,6 +215,146 @@ func DequeueIngestion(key string, fullsync bool) {
 			}
 		}
 	}
+
+	// handle the Istio Gateway and the objects related to it.
+	if lib.IsIstioEnabled() && valid && schema.GetParentIstioGateways != nil {
+		gateways, gatewayFound := schema.GetParentIstioGateways(name, namespace, key)
+		if gatewayFound {
+			for _, gatewayKey := range gateways {
+				handleIstioGateway(gatewayKey, key, fullsync, sharedQueue)
+			}
+		}
+	}
+
+	// handle the gateway.networking.k8s.io Gateway and the objects related to it.
+	if lib.UseGatewayAPI() && objType == utils.L4LBService {
+		// backend Services of type LoadBalancer are related to the Gateways like any other Service.
+		schema, valid = ConfigDescriptor().GetByType(utils.Service)
+	}
+	if lib.UseGatewayAPI() && valid && schema.GetParentGatewayAPIGateways != nil {
+		gateways, gatewayFound := schema.GetParentGatewayAPIGateways(name, namespace, key)
+		if gatewayFound {
+			for _, gatewayKey := range gateways {
+				handleGatewayAPIGateway(gatewayKey, key, fullsync, sharedQueue)
+			}
+		}
+	}
+}
+
+func handleGatewayAPIGateway(gatewayKey, key string, fullsync bool, sharedQueue *utils.WorkerQueue) {
+	namespace, _, gwName := lib.ExtractTypeNameNamespace(gatewayKey)
+	modelName := lib.GetModelName(lib.GetTenantInNamespace(namespace), lib.GetGatewayAPIVSName(gwName, namespace))
+	utils.AviLog.Infof("key: %s, msg: processing Gateway: %s", key, gatewayKey)
+
+	var aviModelGraph *AviObjectGraph
+	var gwStatus *GatewayAPIStatus
+	gateway, err := lib.AKOControlConfig().GatewayAPIInformers().GatewayInformer.Lister().Gateways(namespace).Get(gwName)
+	if err != nil && !errors.IsNotFound(err) {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving Gateway %s: %v", key, gatewayKey, err)
+		return
+	}
+	if err == nil && gateway.GetDeletionTimestamp() == nil && utils.CheckIfNamespaceAccepted(namespace) {
+		gwClass, err := lib.AKOControlConfig().GatewayAPIInformers().GatewayClassInformer.Lister().Get(string(gateway.Spec.GatewayClassName))
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: GatewayClass %s of Gateway %s not found", key, gateway.Spec.GatewayClassName, gatewayKey)
+		} else if string(gwClass.Spec.ControllerName) != lib.GatewayAPIAviGatewayController {
+			utils.AviLog.Debugf("key: %s, msg: Gateway %s is not handled by AKO", key, gatewayKey)
+		} else {
+			aviModelGraph = NewAviObjectGraph()
+			gwStatus = aviModelGraph.BuildGatewayAPIGatewayGraph(gateway, gwClass, key)
+		}
+	}
+
+	var routeParents map[string][]gwapiv1alpha2.RouteParentStatus
+	if gwStatus != nil {
+		objects.SharedGatewayAPILister().UpdateGatewaySecretMappings(gatewayKey, gwStatus.Secrets)
+		routeParents = gwStatus.RouteParents
+		statusOption := status.StatusOptions{
+			ObjType:   lib.GatewayAPIListener,
+			Op:        lib.UpdateStatus,
+			ObjName:   gwName,
+			Namespace: namespace,
+			Key:       key,
+			Options: &status.UpdateOptions{
+				GatewayAPIListeners:  gwStatus.Listeners,
+				GatewayAPIConditions: gwStatus.Conditions,
+				Key:                  key,
+			},
+		}
+		status.PublishToStatusQueue(gatewayKey, statusOption)
+	} else {
+		objects.SharedGatewayAPILister().UpdateGatewaySecretMappings(gatewayKey, nil)
+	}
+
+	// The statuses of the routes referring to the Gateway are updated, for the routes which are
+	// not evaluated for the Gateway, the status written earlier for the Gateway is removed.
+	_, routeKeys := objects.SharedGatewayAPILister().GetGatewayToRoutes(gatewayKey)
+	for _, routeKey := range routeKeys {
+		kind, routeNamespace, routeName := lib.ExtractTypeNameNamespace(routeKey)
+		statusOption := status.StatusOptions{
+			ObjType:   kind,
+			Op:        lib.UpdateStatus,
+			ObjName:   routeName,
+			Namespace: routeNamespace,
+			Key:       key,
+			Options: &status.UpdateOptions{
+				ServiceMetadata:        lib.ServiceMetadataObj{Gateway: gatewayKey},
+				GatewayAPIRouteParents: routeParents[routeKey],
+				Key:                    key,
+			},
+		}
+		status.PublishToStatusQueue(routeKey, statusOption)
+	}
+
+	if aviModelGraph == nil || len(aviModelGraph.GetOrderedNodes()) == 0 {
+		// Check if a model corresponding to the gateway exists or not in memory.
+		if found, _ := objects.SharedAviGraphLister().Get(modelName); found {
+			objects.SharedAviGraphLister().Save(modelName, nil)
+			if !fullsync {
+				PublishKeyToRestLayer(modelName, key, sharedQueue)
+			}
+		}
+		return
+	}
+	ok := saveAviModel(modelName, aviModelGraph, key)
+	if ok && !fullsync {
+		PublishKeyToRestLayer(modelName, key, sharedQueue)
+	}
+}
+
+func handleIstioGateway(gatewayKey, key string, fullsync bool, sharedQueue *utils.WorkerQueue) {
+	namespace, _, gwName := lib.ExtractTypeNameNamespace(gatewayKey)
+	modelName := lib.GetModelName(lib.GetTenantInNamespace(namespace), lib.GetIstioGatewayVSName(gwName, namespace))
+	utils.AviLog.Infof("key: %s, msg: processing Istio Gateway: %s", key, gatewayKey)
+
+	var aviModelGraph *AviObjectGraph
+	gateway, err := lib.AKOControlConfig().IstioCRDInformers().GatewayInformer.Lister().Gateways(namespace).Get(gwName)
+	if err != nil && !errors.IsNotFound(err) {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving Istio Gateway %s: %v", key, gatewayKey, err)
+		return
+	}
+	if err == nil && gateway.GetDeletionTimestamp() == nil && utils.CheckIfNamespaceAccepted(namespace) {
+		aviModelGraph = NewAviObjectGraph()
+		aviModelGraph.BuildIstioGatewayGraph(gateway, key)
+	}
+
+	if aviModelGraph == nil || len(aviModelGraph.GetOrderedNodes()) == 0 {
+		if aviModelGraph == nil {
+			objects.SharedIstioLister().UpdateGatewaySecretMappings(gatewayKey, nil)
+		}
+		// Check if a model corresponding to the gateway exists or not in memory.
+		if found, _ := objects.SharedAviGraphLister().Get(modelName); found {
+			objects.SharedAviGraphLister().Save(modelName, nil)
+			if !fullsync {
+				PublishKeyToRestLayer(modelName, key, sharedQueue)
+			}
+		}
+		return
+	}
+	ok := saveAviModel(modelName, aviModelGraph, key)
+	if ok && !fullsync {
+		PublishKeyToRestLayer(modelName, key, sharedQueue)
+	}
 }
 
 func handleHostRuleForSharedVS(key string, fullsync bool) {
@@ -453,7 +609,7 @@ func handleL4Service(key string, fullsync bool) {
 		// Save the LB service in memory
 		objects.SharedlbLister().Save(namespace+"/"+name, name)
 		if len(aviModelGraph.GetOrderedNodes()) > 0 {
-			model_name := lib.GetModelName(lib.GetTenant(), aviModelGraph.GetAviVS()[0].Name)
+			model_name := lib.GetModelName(aviModelGraph.GetAviVS()[0].Tenant, aviModelGraph.GetAviVS()[0].Name)
 			ok := saveAviModel(model_name, aviModelGraph, key)
 			if ok && !fullsync {
 				PublishKeyToRestLayer(model_name, key, sharedQueue)
@@ -474,7 +630,7 @@ func handleL4Service(key string, fullsync bool) {
 	}
 	// This is a DELETE event. The avi graph is set to nil.
 	utils.AviLog.Debugf("key: %s, msg: received DELETE event for service", key)
-	model_name := lib.GetModelName(lib.GetTenant(), lib.Encode(lib.GetNamePrefix()+namespace+"-"+name, lib.L4VS))
+	model_name := lib.GetModelName(lib.GetTenantInNamespace(namespace), lib.Encode(lib.GetNamePrefix()+namespace+"-"+name, lib.L4VS))
 	objects.SharedAviGraphLister().Save(model_name, nil)
 	if !fullsync {
 		bkt := utils.Bkt(model_name, sharedQueue.NumWorkers)
@@ -520,6 +676,7 @@ func getIngressNSNameForIngestion(objType, namespace, nsname string) (string, st
 
 func saveAviModel(model_name string, aviGraph *AviObjectGraph, key string) bool {
 	utils.AviLog.Debugf("key: %s, msg: Evaluating model :%s", key, model_name)
+	objects.SharedModelKeyLister().RetainKey(model_name, key)
 	if lib.DisableSync {
 		// Note: This is not thread safe, however locking is expensive and the condition for locking should happen rarely
 		utils.AviLog.Infof("key: %s, msg: Disable Sync is True, model %s can not be saved", key, model_name)
@@ -582,6 +739,10 @@ func processNodeObj(key, nodename string, sharedQueue *utils.WorkerQueue, fullsy
 }
 
 func PublishKeyToRestLayer(modelName string, key string, sharedQueue *utils.WorkerQueue) {
+	// Track the objects contributing to the model, to report the sync status of the model on them.
+	if objType, _, _ := lib.ExtractTypeNameNamespace(key); lib.IsSyncStatusObjType(objType) {
+		objects.SharedModelKeyLister().AddKey(modelName, key)
+	}
 	bkt := utils.Bkt(modelName, sharedQueue.NumWorkers)
 	sharedQueue.Workqueue[bkt].AddRateLimited(modelName)
 	utils.AviLog.Infof("key: %s, msg: Published key with modelName: %s", key, modelName)
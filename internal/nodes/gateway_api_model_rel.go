@@ -0,0 +1,283 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"errors"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+var (
+	GatewayAPIGateway = GraphSchema{
+		Type:                        lib.GatewayAPIGateway,
+		GetParentGatewayAPIGateways: GatewayAPIGatewayChanges,
+	}
+	GatewayAPIGatewayClass = GraphSchema{
+		Type:                        lib.GatewayAPIGatewayClass,
+		GetParentGatewayAPIGateways: GatewayAPIGatewayClassToGateway,
+	}
+	HTTPRoute = GraphSchema{
+		Type:                        lib.HTTPRoute,
+		GetParentGatewayAPIGateways: HTTPRouteToGateway,
+	}
+	TLSRoute = GraphSchema{
+		Type:                        lib.TLSRoute,
+		GetParentGatewayAPIGateways: TLSRouteToGateway,
+	}
+	TCPRoute = GraphSchema{
+		Type:                        lib.TCPRoute,
+		GetParentGatewayAPIGateways: TCPRouteToGateway,
+	}
+	UDPRoute = GraphSchema{
+		Type:                        lib.UDPRoute,
+		GetParentGatewayAPIGateways: UDPRouteToGateway,
+	}
+	ReferencePolicy = GraphSchema{
+		Type:                        lib.ReferencePolicy,
+		GetParentGatewayAPIGateways: ReferencePolicyToGateway,
+	}
+)
+
+// gatewayAPIRoute is the common view of the HTTPRoute, TLSRoute, TCPRoute and UDPRoute objects.
+type gatewayAPIRoute struct {
+	Kind       string
+	Meta       metav1.ObjectMeta
+	ParentRefs []gwapiv1alpha2.ParentRef
+	Hostnames  []gwapiv1alpha2.Hostname
+	// BackendRefs of every rule of the route, for HTTPRoutes the rules are available in HTTPRoute.
+	BackendRefs [][]gwapiv1alpha2.BackendRef
+	HTTPRoute   *gwapiv1alpha2.HTTPRoute
+}
+
+func (r *gatewayAPIRoute) Key() string {
+	return r.Kind + "/" + r.Meta.Namespace + "/" + r.Meta.Name
+}
+
+func getGatewayAPIRoute(kind, namespace, name string) (*gatewayAPIRoute, error) {
+	informers := lib.AKOControlConfig().GatewayAPIInformers()
+	route := &gatewayAPIRoute{Kind: kind}
+	switch kind {
+	case lib.HTTPRoute:
+		obj, err := informers.HTTPRouteInformer.Lister().HTTPRoutes(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		route.Meta, route.ParentRefs, route.Hostnames, route.HTTPRoute = obj.ObjectMeta, obj.Spec.ParentRefs, obj.Spec.Hostnames, obj
+		for _, rule := range obj.Spec.Rules {
+			var backendRefs []gwapiv1alpha2.BackendRef
+			for _, backendRef := range rule.BackendRefs {
+				backendRefs = append(backendRefs, backendRef.BackendRef)
+			}
+			route.BackendRefs = append(route.BackendRefs, backendRefs)
+		}
+	case lib.TLSRoute:
+		obj, err := informers.TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		route.Meta, route.ParentRefs, route.Hostnames = obj.ObjectMeta, obj.Spec.ParentRefs, obj.Spec.Hostnames
+		for _, rule := range obj.Spec.Rules {
+			route.BackendRefs = append(route.BackendRefs, rule.BackendRefs)
+		}
+	case lib.TCPRoute:
+		obj, err := informers.TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		route.Meta, route.ParentRefs = obj.ObjectMeta, obj.Spec.ParentRefs
+		for _, rule := range obj.Spec.Rules {
+			route.BackendRefs = append(route.BackendRefs, rule.BackendRefs)
+		}
+	case lib.UDPRoute:
+		obj, err := informers.UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		route.Meta, route.ParentRefs = obj.ObjectMeta, obj.Spec.ParentRefs
+		for _, rule := range obj.Spec.Rules {
+			route.BackendRefs = append(route.BackendRefs, rule.BackendRefs)
+		}
+	default:
+		return nil, errors.New("unsupported route kind " + kind)
+	}
+	return route, nil
+}
+
+// getGatewayAPIBackendServiceName returns the namespace/name of the Service referred by the backendRef,
+// an empty string is returned if the backendRef does not refer to a Service.
+func getGatewayAPIBackendServiceName(backendRef gwapiv1alpha2.BackendObjectReference, routeNamespace string) string {
+	if backendRef.Group != nil && *backendRef.Group != "" {
+		return ""
+	}
+	if backendRef.Kind != nil && *backendRef.Kind != utils.Service {
+		return ""
+	}
+	namespace := routeNamespace
+	if backendRef.Namespace != nil && *backendRef.Namespace != "" {
+		namespace = string(*backendRef.Namespace)
+	}
+	return namespace + "/" + string(backendRef.Name)
+}
+
+func GatewayAPIGatewayChanges(gwName string, namespace string, key string) ([]string, bool) {
+	return []string{namespace + "/" + gwName}, true
+}
+
+func GatewayAPIGatewayClassToGateway(gwClassName string, namespace string, key string) ([]string, bool) {
+	gwList, err := lib.AKOControlConfig().GatewayAPIInformers().GatewayInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list Gateways: %v", key, err)
+		return nil, false
+	}
+	var gateways []string
+	for _, gw := range gwList {
+		if string(gw.Spec.GatewayClassName) == gwClassName {
+			gateways = append(gateways, gw.Namespace+"/"+gw.Name)
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gateways)
+	return gateways, len(gateways) > 0
+}
+
+func HTTPRouteToGateway(routeName string, namespace string, key string) ([]string, bool) {
+	return gatewayAPIRouteToGateway(lib.HTTPRoute, routeName, namespace, key)
+}
+
+func TLSRouteToGateway(routeName string, namespace string, key string) ([]string, bool) {
+	return gatewayAPIRouteToGateway(lib.TLSRoute, routeName, namespace, key)
+}
+
+func TCPRouteToGateway(routeName string, namespace string, key string) ([]string, bool) {
+	return gatewayAPIRouteToGateway(lib.TCPRoute, routeName, namespace, key)
+}
+
+func UDPRouteToGateway(routeName string, namespace string, key string) ([]string, bool) {
+	return gatewayAPIRouteToGateway(lib.UDPRoute, routeName, namespace, key)
+}
+
+func gatewayAPIRouteToGateway(kind, routeName, namespace, key string) ([]string, bool) {
+	routeKey := kind + "/" + namespace + "/" + routeName
+	_, oldGateways := objects.SharedGatewayAPILister().GetRouteToGateways(routeKey)
+
+	route, err := getGatewayAPIRoute(kind, namespace, routeName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Remove the references of this route from the Gateways and Services.
+			utils.AviLog.Debugf("key: %s, msg: %s deleted", key, kind)
+			objects.SharedGatewayAPILister().RemoveRouteMappings(routeKey)
+			return oldGateways, len(oldGateways) > 0
+		}
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving %s: %v", key, kind, err)
+		return oldGateways, len(oldGateways) > 0
+	}
+
+	var gateways, svcs []string
+	for _, parentRef := range route.ParentRefs {
+		if gateway := lib.GatewayAPIParentRefName(parentRef, namespace); gateway != "" && !utils.HasElem(gateways, gateway) {
+			gateways = append(gateways, gateway)
+		}
+	}
+	for _, backendRefs := range route.BackendRefs {
+		for _, backendRef := range backendRefs {
+			if svc := getGatewayAPIBackendServiceName(backendRef.BackendObjectReference, namespace); svc != "" && !utils.HasElem(svcs, svc) {
+				svcs = append(svcs, svc)
+			}
+		}
+	}
+	objects.SharedGatewayAPILister().UpdateRouteGatewayMappings(routeKey, gateways)
+	objects.SharedGatewayAPILister().UpdateRouteServiceMappings(routeKey, svcs)
+
+	allGateways := gateways
+	for _, gateway := range oldGateways {
+		if utils.HasElem(gateways, gateway) {
+			continue
+		}
+		// The route is detached from the Gateway, the status written for the Gateway is removed.
+		statusOption := status.StatusOptions{
+			ObjType:   kind,
+			Op:        lib.UpdateStatus,
+			ObjName:   routeName,
+			Namespace: namespace,
+			Key:       key,
+			Options: &status.UpdateOptions{
+				ServiceMetadata: lib.ServiceMetadataObj{Gateway: gateway},
+				Key:             key,
+			},
+		}
+		status.PublishToStatusQueue(routeKey, statusOption)
+		allGateways = append(allGateways, gateway)
+	}
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, allGateways)
+	return allGateways, len(allGateways) > 0
+}
+
+// AviSettingToGatewayAPIGateway returns the Gateways of the GatewayClasses whose parametersRef refers to the AviInfraSetting.
+func AviSettingToGatewayAPIGateway(infraSettingName string, namespace string, key string) ([]string, bool) {
+	gwClassList, err := lib.AKOControlConfig().GatewayAPIInformers().GatewayClassInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list GatewayClasses: %v", key, err)
+		return nil, false
+	}
+	var gateways []string
+	for _, gwClass := range gwClassList {
+		paramsRef := gwClass.Spec.ParametersRef
+		if paramsRef == nil || string(paramsRef.Group) != lib.AkoGroup || string(paramsRef.Kind) != lib.AviInfraSetting || paramsRef.Name != infraSettingName {
+			continue
+		}
+		if classGateways, found := GatewayAPIGatewayClassToGateway(gwClass.Name, namespace, key); found {
+			gateways = append(gateways, classGateways...)
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gateways)
+	return gateways, len(gateways) > 0
+}
+
+// ReferencePolicyToGateway returns the Gateways which refer to the secrets or services in the
+// namespace of the ReferencePolicy, since those references may get allowed or denied by the change.
+func ReferencePolicyToGateway(rpName string, namespace string, key string) ([]string, bool) {
+	gateways := objects.SharedGatewayAPILister().GetNamespaceRefToGateways(namespace)
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gateways)
+	return gateways, len(gateways) > 0
+}
+
+func SvcToGatewayAPIGateway(svcName string, namespace string, key string) ([]string, bool) {
+	_, routes := objects.SharedGatewayAPILister().GetServiceToRoutes(namespace + "/" + svcName)
+	var gateways []string
+	for _, route := range routes {
+		_, routeGateways := objects.SharedGatewayAPILister().GetRouteToGateways(route)
+		for _, gateway := range routeGateways {
+			if !utils.HasElem(gateways, gateway) {
+				gateways = append(gateways, gateway)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gateways)
+	return gateways, len(gateways) > 0
+}
+
+func SecretToGatewayAPIGateway(secretName string, namespace string, key string) ([]string, bool) {
+	found, gateways := objects.SharedGatewayAPILister().GetSecretToGateways(namespace + "/" + secretName)
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gateways)
+	return gateways, found
+}
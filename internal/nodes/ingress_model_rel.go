@@ -33,6 +33,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	servicesapi "sigs.k8s.io/service-apis/apis/v1alpha1"
 	svcapiv1alpha1 "sigs.k8s.io/service-apis/apis/v1alpha1"
 )
@@ -44,6 +45,8 @@ var (
 		GetParentRoutes:                SvcToRoute,
 		GetParentGateways:              SvcToGateway,
 		GetParentMultiClusterIngresses: SvcToMultiClusterIng,
+		GetParentIstioGateways:         SvcToIstioGateway,
+		GetParentGatewayAPIGateways:    SvcToGatewayAPIGateway,
 	}
 	Ingress = GraphSchema{
 		Type:               "Ingress",
@@ -54,10 +57,12 @@ var (
 		GetParentIngresses: IngClassToIng,
 	}
 	Endpoint = GraphSchema{
-		Type:               "Endpoints",
-		GetParentIngresses: EPToIng,
-		GetParentRoutes:    EPToRoute,
-		GetParentGateways:  EPToGateway,
+		Type:                        "Endpoints",
+		GetParentIngresses:          EPToIng,
+		GetParentRoutes:             EPToRoute,
+		GetParentGateways:           EPToGateway,
+		GetParentIstioGateways:      SvcToIstioGateway,
+		GetParentGatewayAPIGateways: SvcToGatewayAPIGateway,
 	}
 	Pod = GraphSchema{
 		Type:               "Pod",
@@ -74,6 +79,8 @@ var (
 		GetParentRoutes:                SecretToRoute,
 		GetParentGateways:              SecretToGateway,
 		GetParentMultiClusterIngresses: SecretToMultiClusterIng,
+		GetParentIstioGateways:         SecretToIstioGateway,
+		GetParentGatewayAPIGateways:    SecretToGatewayAPIGateway,
 	}
 	Route = GraphSchema{
 		Type:            utils.OshiftRoute,
@@ -89,6 +96,10 @@ var (
 		GetParentIngresses: HTTPRuleToIng,
 		GetParentRoutes:    HTTPRuleToIng,
 	}
+	L4Rule = GraphSchema{
+		Type:              lib.L4Rule,
+		GetParentServices: L4RuleToSvc,
+	}
 	Gateway = GraphSchema{
 		Type:              "Gateway",
 		GetParentGateways: GatewayChanges,
@@ -103,6 +114,8 @@ var (
 		GetParentGateways:  AviSettingToGateway,
 		GetParentServices:  AviSettingToSvc,
 		GetParentRoutes:    AviSettingToRoute,
+
+		GetParentGatewayAPIGateways: AviSettingToGatewayAPIGateway,
 	}
 	MultiClusterIngress = GraphSchema{
 		Type:                           lib.MultiClusterIngress,
@@ -123,11 +136,22 @@ var (
 		Node,
 		HostRule,
 		HTTPRule,
+		L4Rule,
 		Gateway,
 		GatewayClass,
 		AviInfraSetting,
 		MultiClusterIngress,
 		ServiceImport,
+		IstioVirtualService,
+		IstioGateway,
+		IstioDestinationRule,
+		GatewayAPIGateway,
+		GatewayAPIGatewayClass,
+		HTTPRoute,
+		TLSRoute,
+		TCPRoute,
+		UDPRoute,
+		ReferencePolicy,
 	}
 )
 
@@ -138,6 +162,8 @@ type GraphSchema struct {
 	GetParentGateways              func(string, string, string) ([]string, bool)
 	GetParentServices              func(string, string, string) ([]string, bool)
 	GetParentMultiClusterIngresses func(string, string, string) ([]string, bool)
+	GetParentIstioGateways         func(string, string, string) ([]string, bool)
+	GetParentGatewayAPIGateways    func(string, string, string) ([]string, bool)
 }
 
 type GraphDescriptor []GraphSchema
@@ -360,53 +386,60 @@ func IngressChanges(ingName string, namespace string, key string) ([]string, boo
 			return ingresses, true
 		}
 
-		_, oldSvcs := objects.SharedSvcLister().IngressMappings(namespace).GetIngToSvc(ingName)
-		currSvcs := parseServicesForIngress(ingObj.Spec, key)
+		updateIngressServiceMappings(ingObj, key)
+		secrets := parseSecretsForIngress(ingObj.Spec, key)
+		if len(secrets) > 0 {
+			for _, secret := range secrets {
+				objects.SharedSvcLister().IngressMappings(namespace).AddIngressToSecretsMappings(namespace, ingName, secret)
+				objects.SharedSvcLister().IngressMappings(namespace).AddSecretsToIngressMappings(namespace, ingName, secret)
+			}
+		}
+	}
+	return ingresses, true
+}
+
+// updateIngressServiceMappings updates the mappings of the ingress to the Services of its backends, including the
+// additional backends configured via HTTPRule.
+func updateIngressServiceMappings(ingObj *networkingv1.Ingress, key string) {
+	namespace, ingName := ingObj.Namespace, ingObj.Name
+	_, oldSvcs := objects.SharedSvcLister().IngressMappings(namespace).GetIngToSvc(ingName)
+	currSvcs := parseServicesForIngress(ingObj.Spec, key)
+
+	svcToDel := lib.Difference(oldSvcs, currSvcs)
+	for _, svc := range svcToDel {
+		_, ingrforSvc := objects.SharedSvcLister().IngressMappings(namespace).GetSvcToIng(svc)
+		ingrforSvc = utils.Remove(ingrforSvc, ingName)
+		if lib.AutoAnnotateNPLSvc() && len(ingrforSvc) == 0 {
+			statusOption := status.StatusOptions{
+				ObjType:   lib.NPLService,
+				Op:        lib.DeleteStatus,
+				ObjName:   svc,
+				Namespace: namespace,
+				Key:       key,
+			}
+			status.PublishToStatusQueue(svc, statusOption)
+		}
+		objects.SharedSvcLister().IngressMappings(namespace).RemoveSvcFromIngressMappings(ingName, svc)
+	}
 
-		svcToDel := lib.Difference(oldSvcs, currSvcs)
-		for _, svc := range svcToDel {
-			_, ingrforSvc := objects.SharedSvcLister().IngressMappings(namespace).GetSvcToIng(svc)
-			ingrforSvc = utils.Remove(ingrforSvc, ingName)
-			if lib.AutoAnnotateNPLSvc() && len(ingrforSvc) == 0 {
+	svcToAdd := lib.Difference(currSvcs, oldSvcs)
+	for _, svc := range svcToAdd {
+		utils.AviLog.Debugf("key: %s, msg: updating ingress relationship for service:  %s", key, svc)
+		objects.SharedSvcLister().IngressMappings(namespace).UpdateIngressMappings(ingName, svc)
+		// Check and update NPl annotation for svc
+		if lib.AutoAnnotateNPLSvc() {
+			if !status.CheckNPLSvcAnnotation(key, namespace, svc) {
 				statusOption := status.StatusOptions{
 					ObjType:   lib.NPLService,
-					Op:        lib.DeleteStatus,
+					Op:        lib.UpdateStatus,
 					ObjName:   svc,
 					Namespace: namespace,
 					Key:       key,
 				}
 				status.PublishToStatusQueue(svc, statusOption)
 			}
-			objects.SharedSvcLister().IngressMappings(namespace).RemoveSvcFromIngressMappings(ingName, svc)
-		}
-
-		svcToAdd := lib.Difference(currSvcs, oldSvcs)
-		for _, svc := range svcToAdd {
-			utils.AviLog.Debugf("key: %s, msg: updating ingress relationship for service:  %s", key, svc)
-			objects.SharedSvcLister().IngressMappings(namespace).UpdateIngressMappings(ingName, svc)
-			// Check and update NPl annotation for svc
-			if lib.AutoAnnotateNPLSvc() {
-				if !status.CheckNPLSvcAnnotation(key, namespace, svc) {
-					statusOption := status.StatusOptions{
-						ObjType:   lib.NPLService,
-						Op:        lib.UpdateStatus,
-						ObjName:   svc,
-						Namespace: namespace,
-						Key:       key,
-					}
-					status.PublishToStatusQueue(svc, statusOption)
-				}
-			}
-		}
-		secrets := parseSecretsForIngress(ingObj.Spec, key)
-		if len(secrets) > 0 {
-			for _, secret := range secrets {
-				objects.SharedSvcLister().IngressMappings(namespace).AddIngressToSecretsMappings(namespace, ingName, secret)
-				objects.SharedSvcLister().IngressMappings(namespace).AddSecretsToIngressMappings(namespace, ingName, secret)
-			}
 		}
 	}
-	return ingresses, true
 }
 
 func IngClassToIng(ingClassName string, namespace string, key string) ([]string, bool) {
@@ -518,8 +551,9 @@ func EPToIng(epName string, namespace string, key string) ([]string, bool) {
 
 func SecretToIng(secretName string, namespace string, key string) ([]string, bool) {
 	ok, ingNames := objects.SharedSvcLister().IngressMappings(namespace).GetSecretToIng(secretName)
+	ingNames = appendClientAuthSecretIngresses(ingNames, secretName, namespace, key)
 	utils.AviLog.Debugf("key: %s, msg: Ingresses retrieved %s", key, ingNames)
-	if ok {
+	if ok || len(ingNames) > 0 {
 		return ingNames, true
 	}
 	return nil, false
@@ -527,13 +561,51 @@ func SecretToIng(secretName string, namespace string, key string) ([]string, boo
 
 func SecretToRoute(secretName string, namespace string, key string) ([]string, bool) {
 	ok, ingNames := objects.OshiftRouteSvcLister().IngressMappings(namespace).GetSecretToIng(secretName)
+	ingNames = appendClientAuthSecretIngresses(ingNames, secretName, namespace, key)
 	utils.AviLog.Debugf("key: %s, msg: Ingresses retrieved %s", key, ingNames)
-	if ok {
+	if ok || len(ingNames) > 0 {
 		return ingNames, true
 	}
 	return nil, false
 }
 
+// appendClientAuthSecretIngresses adds the ingresses/routes of the hosts whose hostrules refer to the Secret as
+// the clientAuth caSecret, so that the PKI profiles are updated along with the CA certificates in the Secret.
+func appendClientAuthSecretIngresses(ingNames []string, secretName string, namespace string, key string) []string {
+	if lib.AKOControlConfig().CRDInformers().HostRuleInformer == nil {
+		return ingNames
+	}
+	hostrules, err := lib.AKOControlConfig().CRDInformers().HostRuleInformer.Lister().HostRules(namespace).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list hostrules: %v", key, err)
+		return ingNames
+	}
+	for _, hostrule := range hostrules {
+		clientAuth := hostrule.Spec.VirtualHost.TLS.ClientAuth
+		if hostrule.Status.Status != lib.StatusAccepted || clientAuth == nil || clientAuth.CASecret != secretName {
+			continue
+		}
+		fqdnType := string(hostrule.Spec.VirtualHost.FqdnType)
+		if fqdnType == "" {
+			fqdnType = string(akov1alpha1.Exact)
+		}
+		for _, host := range SharedHostNameLister().GetHostsFromHostPathStore(hostrule.Spec.VirtualHost.Fqdn, fqdnType) {
+			found, obj := SharedHostNameLister().GetHostPathStore(host)
+			if !found {
+				continue
+			}
+			for _, ingresses := range obj {
+				for _, ing := range ingresses {
+					if !utils.HasElem(ingNames, ing) {
+						ingNames = append(ingNames, ing)
+					}
+				}
+			}
+		}
+	}
+	return ingNames
+}
+
 func SecretToGateway(secretName string, namespace string, key string) ([]string, bool) {
 	return nil, false
 }
@@ -718,6 +790,19 @@ func HTTPRuleToIng(rrname string, namespace string, key string) ([]string, bool)
 		}
 	}
 
+	// The Services of the additional backends of the HTTPRule are mapped to the ingresses, so that the ingresses are
+	// synced along with the changes of the Services.
+	if utils.GetInformers().IngressInformer != nil {
+		for _, ing := range allIngresses {
+			ingNamespace, ingName := utils.ExtractNamespaceObjectName(ing)
+			ingObj, err := utils.GetInformers().IngressInformer.Lister().Ingresses(ingNamespace).Get(ingName)
+			if err != nil || !lib.ValidateIngressForClass(key, ingObj) {
+				continue
+			}
+			updateIngressServiceMappings(ingObj, key)
+		}
+	}
+
 	utils.AviLog.Debugf("key: %s, msg: Ingresses retrieved %s", key, allIngresses)
 	return allIngresses, true
 }
@@ -815,6 +900,26 @@ func AviSettingToSvc(infraSettingName string, namespace string, key string) ([]s
 	return allSvcs, true
 }
 
+// L4RuleToSvc returns the Services of type LoadBalancer referring to the L4Rule via annotation.
+func L4RuleToSvc(l4RuleName string, namespace string, key string) ([]string, bool) {
+	allSvcs := make([]string, 0)
+
+	services, err := utils.GetInformers().ServiceInformer.Informer().GetIndexer().ByIndex(lib.L4RuleServicesIndex, namespace+"/"+l4RuleName)
+	if err != nil {
+		return allSvcs, false
+	}
+
+	for _, svc := range services {
+		svcObj, isSvc := svc.(*corev1.Service)
+		if isSvc {
+			allSvcs = append(allSvcs, svcObj.Namespace+"/"+svcObj.Name)
+		}
+	}
+
+	utils.AviLog.Debugf("key: %s, msg: total services retrieved from L4Rule: %s", key, allSvcs)
+	return allSvcs, true
+}
+
 func parseServicesForIngress(ingSpec networkingv1.IngressSpec, key string) []string {
 	// Figure out the service names that are part of this ingress
 	var services []string
@@ -822,6 +927,12 @@ func parseServicesForIngress(ingSpec networkingv1.IngressSpec, key string) []str
 		if rule.IngressRuleValue.HTTP != nil {
 			for _, path := range rule.IngressRuleValue.HTTP.Paths {
 				services = append(services, path.Backend.Service.Name)
+				// Services of the additional backends configured via HTTPRule for the path.
+				for _, backend := range getHTTPRuleBackends(rule.Host, path.Path, key) {
+					if !utils.HasElem(services, backend.ServiceName) {
+						services = append(services, backend.ServiceName)
+					}
+				}
 			}
 		}
 	}
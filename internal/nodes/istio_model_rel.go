@@ -0,0 +1,204 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	istiov1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var (
+	IstioVirtualService = GraphSchema{
+		Type:                   lib.IstioVirtualService,
+		GetParentIstioGateways: IstioVirtualServiceToGateway,
+	}
+	IstioGateway = GraphSchema{
+		Type:                   lib.IstioGateway,
+		GetParentIstioGateways: IstioGatewayChanges,
+	}
+	IstioDestinationRule = GraphSchema{
+		Type:                   lib.IstioDestinationRule,
+		GetParentIstioGateways: IstioDestinationRuleToGateway,
+	}
+)
+
+// istioMeshGateway is the reserved gateway name, which applies the VirtualService
+// to the sidecars in the mesh. Such references are not handled by AKO.
+const istioMeshGateway = "mesh"
+
+func IstioGatewayChanges(gwName string, namespace string, key string) ([]string, bool) {
+	return []string{namespace + "/" + gwName}, true
+}
+
+func IstioVirtualServiceToGateway(vsName string, namespace string, key string) ([]string, bool) {
+	vsNSName := namespace + "/" + vsName
+	_, allGateways := objects.SharedIstioLister().GetVsToGateways(vsNSName)
+
+	vs, err := lib.AKOControlConfig().IstioCRDInformers().VirtualServiceInformer.Lister().VirtualServices(namespace).Get(vsName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Remove the references of this VirtualService from the Gateways and Services.
+			utils.AviLog.Debugf("key: %s, msg: Istio VirtualService deleted", key)
+			objects.SharedIstioLister().RemoveVsMappings(vsNSName)
+			return allGateways, len(allGateways) > 0
+		}
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving Istio VirtualService: %v", key, err)
+		return allGateways, len(allGateways) > 0
+	}
+
+	gateways := getIstioVsGateways(vs)
+	objects.SharedIstioLister().UpdateVsGatewayMappings(vsNSName, gateways)
+	objects.SharedIstioLister().UpdateVsServiceMappings(vsNSName, getIstioVsServices(vs))
+	for _, gateway := range gateways {
+		if !utils.HasElem(allGateways, gateway) {
+			allGateways = append(allGateways, gateway)
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Istio Gateways retrieved %s", key, allGateways)
+	return allGateways, len(allGateways) > 0
+}
+
+func IstioDestinationRuleToGateway(drName string, namespace string, key string) ([]string, bool) {
+	drNSName := namespace + "/" + drName
+	var services []string
+	if found, oldSvc := objects.SharedIstioLister().GetDrToService(drNSName); found {
+		services = append(services, oldSvc)
+	}
+
+	dr, err := lib.AKOControlConfig().IstioCRDInformers().DestinationRuleInformer.Lister().DestinationRules(namespace).Get(drName)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			utils.AviLog.Warnf("key: %s, msg: error while retrieving Istio DestinationRule: %v", key, err)
+			return nil, false
+		}
+		utils.AviLog.Debugf("key: %s, msg: Istio DestinationRule deleted", key)
+		objects.SharedIstioLister().DeleteDrServiceMapping(drNSName)
+	} else {
+		svcNamespace, svcName, ok := resolveIstioServiceHost(dr.Spec.Host, namespace)
+		if ok {
+			svcNSName := svcNamespace + "/" + svcName
+			objects.SharedIstioLister().UpdateDrServiceMapping(drNSName, svcNSName)
+			if !utils.HasElem(services, svcNSName) {
+				services = append(services, svcNSName)
+			}
+		} else {
+			utils.AviLog.Debugf("key: %s, msg: host %s of Istio DestinationRule %s is not an in-cluster service", key, dr.Spec.Host, drNSName)
+			objects.SharedIstioLister().DeleteDrServiceMapping(drNSName)
+		}
+	}
+
+	var allGateways []string
+	for _, svcNSName := range services {
+		svcNamespace, _, svcName := lib.ExtractTypeNameNamespace(svcNSName)
+		gateways, _ := SvcToIstioGateway(svcName, svcNamespace, key)
+		for _, gateway := range gateways {
+			if !utils.HasElem(allGateways, gateway) {
+				allGateways = append(allGateways, gateway)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Istio Gateways retrieved %s", key, allGateways)
+	return allGateways, len(allGateways) > 0
+}
+
+func SvcToIstioGateway(svcName string, namespace string, key string) ([]string, bool) {
+	var allGateways []string
+	_, vses := objects.SharedIstioLister().GetServiceToVses(namespace + "/" + svcName)
+	for _, vs := range vses {
+		_, gateways := objects.SharedIstioLister().GetVsToGateways(vs)
+		for _, gateway := range gateways {
+			if !utils.HasElem(allGateways, gateway) {
+				allGateways = append(allGateways, gateway)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Istio Gateways retrieved %s", key, allGateways)
+	return allGateways, len(allGateways) > 0
+}
+
+func SecretToIstioGateway(secretName string, namespace string, key string) ([]string, bool) {
+	found, gateways := objects.SharedIstioLister().GetSecretToGateways(namespace + "/" + secretName)
+	utils.AviLog.Debugf("key: %s, msg: Istio Gateways retrieved %s", key, gateways)
+	return gateways, found
+}
+
+// getIstioVsGateways returns the namespace/name of the Gateways that the VirtualService is bound to.
+// Gateways without a namespace refer to the namespace of the VirtualService.
+func getIstioVsGateways(vs *istiov1alpha3.VirtualService) []string {
+	var gateways []string
+	for _, gateway := range vs.Spec.Gateways {
+		gwNSName := resolveIstioGatewayName(gateway, vs.Namespace)
+		if gwNSName != "" && !utils.HasElem(gateways, gwNSName) {
+			gateways = append(gateways, gwNSName)
+		}
+	}
+	return gateways
+}
+
+func resolveIstioGatewayName(gateway, namespace string) string {
+	if gateway == istioMeshGateway || gateway == "" {
+		return ""
+	}
+	if strings.Contains(gateway, "/") {
+		return gateway
+	}
+	return namespace + "/" + gateway
+}
+
+// getIstioVsServices returns the namespace/name of all the Services used as destinations in the VirtualService.
+func getIstioVsServices(vs *istiov1alpha3.VirtualService) []string {
+	var services []string
+	for _, httpRoute := range vs.Spec.Http {
+		for _, route := range httpRoute.Route {
+			if route.Destination == nil {
+				continue
+			}
+			svcNamespace, svcName, ok := resolveIstioServiceHost(route.Destination.Host, vs.Namespace)
+			if ok && !utils.HasElem(services, svcNamespace+"/"+svcName) {
+				services = append(services, svcNamespace+"/"+svcName)
+			}
+		}
+	}
+	return services
+}
+
+// istioClusterDomain is the DNS domain of the cluster used in the fully qualified Service hosts.
+const istioClusterDomain = "cluster.local"
+
+// resolveIstioServiceHost resolves the host of a destination to a Service, the way Istio does. A short
+// name (reviews) is interpreted relative to the namespace of the Istio object, and a fully qualified
+// name (reviews.default.svc.cluster.local) refers to the Service in that namespace. Any other host,
+// like example.com or a ServiceEntry host, does not refer to an in-cluster Service and false is returned.
+func resolveIstioServiceHost(host, namespace string) (string, string, bool) {
+	parts := strings.Split(host, ".")
+	for _, part := range parts {
+		if part == "" || part == "*" {
+			return "", "", false
+		}
+	}
+	if len(parts) == 1 {
+		return namespace, parts[0], true
+	}
+	if len(parts) > 3 && parts[2] == "svc" && strings.Join(parts[3:], ".") == istioClusterDomain {
+		return parts[1], parts[0], true
+	}
+	return "", "", false
+}
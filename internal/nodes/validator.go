@@ -283,7 +283,12 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 				}
 				// for ingress use 100 as default weight
 				hostPathMapSvc.weight = 100
+				var httpRuleBackends []IngressHostPathSvc
+				if !passthroughEnabled {
+					httpRuleBackends = v.parseHTTPRuleBackends(ns, ingName, hostName, &hostPathMapSvc, key)
+				}
 				hostPathMapSvcList.ingressHPSvc = append(hostPathMapSvcList.ingressHPSvc, hostPathMapSvc)
+				hostPathMapSvcList.ingressHPSvc = append(hostPathMapSvcList.ingressHPSvc, httpRuleBackends...)
 			}
 		}
 
@@ -400,6 +405,55 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 	return ingressConfig
 }
 
+// parseHTTPRuleBackends returns the additional backends of the ingress path, from the HTTPRule path target of the host
+// matching the ingress path. The backend Service of the ingress path can be listed too, to set its weight.
+func (v *Validator) parseHTTPRuleBackends(ns, ingName, hostName string, hostPathMapSvc *IngressHostPathSvc, key string) []IngressHostPathSvc {
+	backends := getHTTPRuleBackends(hostName, hostPathMapSvc.Path, key)
+	if len(backends) == 0 {
+		return nil
+	}
+	if lib.GetNoPGForSNI() {
+		utils.AviLog.Warnf("key: %s, msg: HTTPRule backends for %s%s are not supported without poolgroups for SNI", key, hostName, hostPathMapSvc.Path)
+		return nil
+	}
+
+	var backendSvcs []IngressHostPathSvc
+	for _, backend := range backends {
+		weight := int32(100)
+		if backend.Weight != nil {
+			weight = *backend.Weight
+		}
+		if backend.ServiceName == hostPathMapSvc.ServiceName {
+			hostPathMapSvc.weight = weight
+			continue
+		}
+		backendSvc := IngressHostPathSvc{
+			Path:            hostPathMapSvc.Path,
+			PathType:        hostPathMapSvc.PathType,
+			ServiceName:     backend.ServiceName,
+			Port:            backend.ServicePort,
+			weight:          weight,
+			httpRuleBackend: true,
+		}
+		serviceBackendPort := networkingv1.ServiceBackendPort{Number: backend.ServicePort}
+		if backend.ServicePort == 0 {
+			// Default to the port of the ingress path, as the backends are usually versions of the same application.
+			backendSvc.Port = hostPathMapSvc.Port
+			backendSvc.PortName = hostPathMapSvc.PortName
+			serviceBackendPort = networkingv1.ServiceBackendPort{Number: hostPathMapSvc.Port, Name: hostPathMapSvc.PortName}
+		}
+		if backendSvc.PortName == "" {
+			backendSvc.PortName = v.findPortName(backend.ServiceName, ns, backendSvc.Port, key)
+		}
+		backendSvc.TargetPort = v.findTargetPort(backend.ServiceName, ns, &serviceBackendPort, key)
+		backendSvcs = append(backendSvcs, backendSvc)
+		// The service to ingress mapping is updated here, since the ingress is processed again on HTTPRule updates.
+		objects.SharedSvcLister().IngressMappings(ns).UpdateIngressMappings(ingName, backend.ServiceName)
+	}
+	utils.AviLog.Debugf("key: %s, msg: HTTPRule backends for %s%s: %s", key, hostName, hostPathMapSvc.Path, utils.Stringify(backendSvcs))
+	return backendSvcs
+}
+
 func (v *Validator) findTargetPort(serviceName, ns string, serviceBackendPort *networkingv1.ServiceBackendPort, key string) int32 {
 	// Query the service and obtain the targetPort
 	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(ns).Get(serviceName)
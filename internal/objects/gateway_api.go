@@ -0,0 +1,157 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package objects
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+var gwapilister *GatewayAPILister
+var gwapionce sync.Once
+
+// This file builds cache relations for all gateway.networking.k8s.io objects.
+// Relationships stored are: gateway to route, route to service and gateway to secret.
+// Gateway, service and secret keys are of the form namespace/name, route keys
+// are of the form kind/namespace/name.
+
+func SharedGatewayAPILister() *GatewayAPILister {
+	gwapionce.Do(func() {
+		gwapilister = &GatewayAPILister{
+			GwRouteStore:  NewObjectMapStore(),
+			RouteGwStore:  NewObjectMapStore(),
+			RouteSvcStore: NewObjectMapStore(),
+			SvcRouteStore: NewObjectMapStore(),
+			GwSecretStore: NewObjectMapStore(),
+			SecretGwStore: NewObjectMapStore(),
+		}
+	})
+	return gwapilister
+}
+
+type GatewayAPILister struct {
+	GatewayAPILock sync.RWMutex
+
+	// nsX/gw1 -> [HTTPRoute/ns1/route1, TCPRoute/ns2/route2]
+	GwRouteStore *ObjectMapStore
+
+	// HTTPRoute/ns1/route1 -> [nsX/gw1, nsY/gw2]
+	RouteGwStore *ObjectMapStore
+
+	// HTTPRoute/ns1/route1 -> [ns1/svc1, ns2/svc2]
+	RouteSvcStore *ObjectMapStore
+
+	// ns1/svc1 -> [HTTPRoute/ns1/route1, TCPRoute/ns3/route3]
+	SvcRouteStore *ObjectMapStore
+
+	// nsX/gw1 -> [nsX/secret1, nsY/secret2]
+	GwSecretStore *ObjectMapStore
+
+	// nsX/secret1 -> [nsX/gw1, nsX/gw2]
+	SecretGwStore *ObjectMapStore
+}
+
+// Gateway <-> Route
+func (g *GatewayAPILister) GetRouteToGateways(route string) (bool, []string) {
+	g.GatewayAPILock.RLock()
+	defer g.GatewayAPILock.RUnlock()
+	return getListFromStore(g.RouteGwStore, route)
+}
+
+func (g *GatewayAPILister) GetGatewayToRoutes(gateway string) (bool, []string) {
+	g.GatewayAPILock.RLock()
+	defer g.GatewayAPILock.RUnlock()
+	return getListFromStore(g.GwRouteStore, gateway)
+}
+
+func (g *GatewayAPILister) UpdateRouteGatewayMappings(route string, gateways []string) {
+	g.GatewayAPILock.Lock()
+	defer g.GatewayAPILock.Unlock()
+	updateOneToManyMappings(g.RouteGwStore, g.GwRouteStore, route, gateways)
+}
+
+// Route <-> Service
+func (g *GatewayAPILister) GetRouteToServices(route string) (bool, []string) {
+	g.GatewayAPILock.RLock()
+	defer g.GatewayAPILock.RUnlock()
+	return getListFromStore(g.RouteSvcStore, route)
+}
+
+func (g *GatewayAPILister) GetServiceToRoutes(svc string) (bool, []string) {
+	g.GatewayAPILock.RLock()
+	defer g.GatewayAPILock.RUnlock()
+	return getListFromStore(g.SvcRouteStore, svc)
+}
+
+func (g *GatewayAPILister) UpdateRouteServiceMappings(route string, svcs []string) {
+	g.GatewayAPILock.Lock()
+	defer g.GatewayAPILock.Unlock()
+	updateOneToManyMappings(g.RouteSvcStore, g.SvcRouteStore, route, svcs)
+}
+
+// Route delete removes both the gateway and the service relations.
+func (g *GatewayAPILister) RemoveRouteMappings(route string) {
+	g.GatewayAPILock.Lock()
+	defer g.GatewayAPILock.Unlock()
+	updateOneToManyMappings(g.RouteGwStore, g.GwRouteStore, route, nil)
+	updateOneToManyMappings(g.RouteSvcStore, g.SvcRouteStore, route, nil)
+}
+
+// Gateway <-> Secret
+func (g *GatewayAPILister) GetSecretToGateways(secret string) (bool, []string) {
+	g.GatewayAPILock.RLock()
+	defer g.GatewayAPILock.RUnlock()
+	return getListFromStore(g.SecretGwStore, secret)
+}
+
+func (g *GatewayAPILister) UpdateGatewaySecretMappings(gateway string, secrets []string) {
+	g.GatewayAPILock.Lock()
+	defer g.GatewayAPILock.Unlock()
+	updateOneToManyMappings(g.GwSecretStore, g.SecretGwStore, gateway, secrets)
+}
+
+// GetNamespaceRefToGateways returns the Gateways which refer to the secrets or services in the namespace,
+// either directly or through their routes. This is used to sync the Gateways on ReferencePolicy changes.
+func (g *GatewayAPILister) GetNamespaceRefToGateways(namespace string) []string {
+	g.GatewayAPILock.RLock()
+	defer g.GatewayAPILock.RUnlock()
+	var gateways []string
+	addGateways := func(gws []string) {
+		for _, gw := range gws {
+			if !utils.HasElem(gateways, gw) {
+				gateways = append(gateways, gw)
+			}
+		}
+	}
+	for _, secret := range g.SecretGwStore.GetAllKeys() {
+		if strings.HasPrefix(secret, namespace+"/") {
+			_, gws := getListFromStore(g.SecretGwStore, secret)
+			addGateways(gws)
+		}
+	}
+	for _, svc := range g.SvcRouteStore.GetAllKeys() {
+		if !strings.HasPrefix(svc, namespace+"/") {
+			continue
+		}
+		_, routes := getListFromStore(g.SvcRouteStore, svc)
+		for _, route := range routes {
+			_, gws := getListFromStore(g.RouteGwStore, route)
+			addGateways(gws)
+		}
+	}
+	return gateways
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package objects
+
+import (
+	"sync"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+var istiolister *IstioLister
+var istioonce sync.Once
+
+// This file builds cache relations for all Istio objects.
+// Relationships stored are: istio gateway to virtualservice, virtualservice to service,
+// destinationrule to service and secret to istio gateway.
+// All keys are of the form namespace/name.
+
+func SharedIstioLister() *IstioLister {
+	istioonce.Do(func() {
+		istiolister = &IstioLister{
+			GwVsStore:     NewObjectMapStore(),
+			VsGwStore:     NewObjectMapStore(),
+			VsSvcStore:    NewObjectMapStore(),
+			SvcVsStore:    NewObjectMapStore(),
+			DrSvcStore:    NewObjectMapStore(),
+			GwSecretStore: NewObjectMapStore(),
+			SecretGwStore: NewObjectMapStore(),
+		}
+	})
+	return istiolister
+}
+
+type IstioLister struct {
+	IstioLock sync.RWMutex
+
+	// nsX/gw1 -> [ns1/vs1, ns2/vs2]
+	GwVsStore *ObjectMapStore
+
+	// ns1/vs1 -> [nsX/gw1, nsY/gw2]
+	VsGwStore *ObjectMapStore
+
+	// ns1/vs1 -> [ns1/svc1, ns2/svc2]
+	VsSvcStore *ObjectMapStore
+
+	// ns1/svc1 -> [ns1/vs1, ns3/vs3]
+	SvcVsStore *ObjectMapStore
+
+	// ns1/dr1 -> ns1/svc1
+	DrSvcStore *ObjectMapStore
+
+	// nsX/gw1 -> [nsX/secret1]
+	GwSecretStore *ObjectMapStore
+
+	// nsX/secret1 -> [nsX/gw1, nsX/gw2]
+	SecretGwStore *ObjectMapStore
+}
+
+// updateOneToManyMappings replaces the values stored for objKey in the forward store and
+// keeps the reverse store in sync, removing objKey from values that are no longer referred.
+func updateOneToManyMappings(forward, reverse *ObjectMapStore, objKey string, values []string) {
+	_, oldValues := getListFromStore(forward, objKey)
+	for _, oldValue := range oldValues {
+		if utils.HasElem(values, oldValue) {
+			continue
+		}
+		if found, objList := getListFromStore(reverse, oldValue); found {
+			objList = utils.Remove(objList, objKey)
+			if len(objList) == 0 {
+				reverse.Delete(oldValue)
+			} else {
+				reverse.AddOrUpdate(oldValue, objList)
+			}
+		}
+	}
+	for _, value := range values {
+		_, objList := getListFromStore(reverse, value)
+		if !utils.HasElem(objList, objKey) {
+			objList = append(objList, objKey)
+		}
+		reverse.AddOrUpdate(value, objList)
+	}
+	if len(values) == 0 {
+		forward.Delete(objKey)
+		return
+	}
+	forward.AddOrUpdate(objKey, values)
+}
+
+func getListFromStore(store *ObjectMapStore, objKey string) (bool, []string) {
+	found, objList := store.Get(objKey)
+	if !found {
+		return false, make([]string, 0)
+	}
+	// return a copy, so that callers can modify the list without affecting the store.
+	return true, append([]string{}, objList.([]string)...)
+}
+
+// VirtualService <-> Gateway
+func (v *IstioLister) GetVsToGateways(vs string) (bool, []string) {
+	v.IstioLock.RLock()
+	defer v.IstioLock.RUnlock()
+	return getListFromStore(v.VsGwStore, vs)
+}
+
+func (v *IstioLister) GetGatewayToVses(gateway string) (bool, []string) {
+	v.IstioLock.RLock()
+	defer v.IstioLock.RUnlock()
+	return getListFromStore(v.GwVsStore, gateway)
+}
+
+func (v *IstioLister) UpdateVsGatewayMappings(vs string, gateways []string) {
+	v.IstioLock.Lock()
+	defer v.IstioLock.Unlock()
+	updateOneToManyMappings(v.VsGwStore, v.GwVsStore, vs, gateways)
+}
+
+// VirtualService <-> Service
+func (v *IstioLister) GetVsToServices(vs string) (bool, []string) {
+	v.IstioLock.RLock()
+	defer v.IstioLock.RUnlock()
+	return getListFromStore(v.VsSvcStore, vs)
+}
+
+func (v *IstioLister) GetServiceToVses(svc string) (bool, []string) {
+	v.IstioLock.RLock()
+	defer v.IstioLock.RUnlock()
+	return getListFromStore(v.SvcVsStore, svc)
+}
+
+func (v *IstioLister) UpdateVsServiceMappings(vs string, svcs []string) {
+	v.IstioLock.Lock()
+	defer v.IstioLock.Unlock()
+	updateOneToManyMappings(v.VsSvcStore, v.SvcVsStore, vs, svcs)
+}
+
+// VirtualService delete removes both the gateway and the service relations.
+func (v *IstioLister) RemoveVsMappings(vs string) {
+	v.IstioLock.Lock()
+	defer v.IstioLock.Unlock()
+	updateOneToManyMappings(v.VsGwStore, v.GwVsStore, vs, nil)
+	updateOneToManyMappings(v.VsSvcStore, v.SvcVsStore, vs, nil)
+}
+
+// DestinationRule -> Service
+func (v *IstioLister) GetDrToService(dr string) (bool, string) {
+	found, svc := v.DrSvcStore.Get(dr)
+	if !found {
+		return false, ""
+	}
+	return true, svc.(string)
+}
+
+func (v *IstioLister) UpdateDrServiceMapping(dr, svc string) {
+	v.DrSvcStore.AddOrUpdate(dr, svc)
+}
+
+func (v *IstioLister) DeleteDrServiceMapping(dr string) bool {
+	return v.DrSvcStore.Delete(dr)
+}
+
+// Gateway <-> Secret
+func (v *IstioLister) GetSecretToGateways(secret string) (bool, []string) {
+	v.IstioLock.RLock()
+	defer v.IstioLock.RUnlock()
+	return getListFromStore(v.SecretGwStore, secret)
+}
+
+func (v *IstioLister) UpdateGatewaySecretMappings(gateway string, secrets []string) {
+	v.IstioLock.Lock()
+	defer v.IstioLock.Unlock()
+	updateOneToManyMappings(v.GwSecretStore, v.SecretGwStore, gateway, secrets)
+}
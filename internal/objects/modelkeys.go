@@ -0,0 +1,189 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package objects
+
+import (
+	"sort"
+	"sync"
+)
+
+// This file maintains the relationship between the models and the keys of the kubernetes objects
+// which were processed into the models, along with the sync errors of the models.
+
+var modelkeyinstance *ModelKeyLister
+var modelkeyonce sync.Once
+
+func SharedModelKeyLister() *ModelKeyLister {
+	modelkeyonce.Do(func() {
+		modelkeyinstance = &ModelKeyLister{
+			modelKeys:   make(map[string]map[string]bool),
+			keyModels:   make(map[string]map[string]bool),
+			keyPasses:   make(map[string]map[string]bool),
+			modelErrors: make(map[string]map[string]string),
+		}
+	})
+	return modelkeyinstance
+}
+
+type ModelKeyLister struct {
+	// model name -> set of object keys
+	modelKeys map[string]map[string]bool
+	// object key -> set of model names
+	keyModels map[string]map[string]bool
+	// object key -> set of model names the key is processed into, in the ongoing processing of the key
+	keyPasses map[string]map[string]bool
+	// model name -> avi virtualservice name -> error
+	modelErrors map[string]map[string]string
+	lock        sync.RWMutex
+}
+
+func (m *ModelKeyLister) AddKey(modelName, key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.modelKeys[modelName]; !ok {
+		m.modelKeys[modelName] = make(map[string]bool)
+	}
+	m.modelKeys[modelName][key] = true
+	if _, ok := m.keyModels[key]; !ok {
+		m.keyModels[key] = make(map[string]bool)
+	}
+	m.keyModels[key][modelName] = true
+	if pass, ok := m.keyPasses[key]; ok {
+		pass[modelName] = true
+	}
+}
+
+func (m *ModelKeyLister) RemoveKey(modelName, key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.removeKey(modelName, key)
+}
+
+func (m *ModelKeyLister) removeKey(modelName, key string) {
+	delete(m.modelKeys[modelName], key)
+	if len(m.modelKeys[modelName]) == 0 {
+		delete(m.modelKeys, modelName)
+	}
+	delete(m.keyModels[key], modelName)
+	if len(m.keyModels[key]) == 0 {
+		delete(m.keyModels, key)
+	}
+}
+
+// BeginKey starts tracking the models the key is processed into. The models the key was added to before,
+// and is neither added to nor retained in until EndKey, are not associated with the key anymore.
+func (m *ModelKeyLister) BeginKey(key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.keyPasses[key] = make(map[string]bool)
+}
+
+// RetainKey keeps the key associated with the model, when the key is processed into an unchanged model.
+func (m *ModelKeyLister) RetainKey(modelName, key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if pass, ok := m.keyPasses[key]; ok {
+		pass[modelName] = true
+	}
+}
+
+// RestrictKey limits the models the key is processed into since BeginKey to the given models, for the models the key
+// was only removed from.
+func (m *ModelKeyLister) RestrictKey(key string, modelNames []string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	pass, ok := m.keyPasses[key]
+	if !ok {
+		return
+	}
+	restricted := make(map[string]bool)
+	for _, modelName := range modelNames {
+		if pass[modelName] {
+			restricted[modelName] = true
+		}
+	}
+	m.keyPasses[key] = restricted
+}
+
+// EndKey removes the key from the models it is not processed into since BeginKey.
+func (m *ModelKeyLister) EndKey(key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	pass, ok := m.keyPasses[key]
+	if !ok {
+		return
+	}
+	delete(m.keyPasses, key)
+	for modelName := range m.keyModels[key] {
+		if !pass[modelName] {
+			m.removeKey(modelName, key)
+		}
+	}
+}
+
+// GetKeys returns the sorted keys of the objects which were processed into the model.
+func (m *ModelKeyLister) GetKeys(modelName string) []string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	keys := make([]string, 0, len(m.modelKeys[modelName]))
+	for key := range m.modelKeys[modelName] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SetError saves the error of the virtualservice vsName of the model.
+func (m *ModelKeyLister) SetError(modelName, vsName, errMsg string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.modelErrors[modelName]; !ok {
+		m.modelErrors[modelName] = make(map[string]string)
+	}
+	m.modelErrors[modelName][vsName] = errMsg
+}
+
+// ClearError removes the error of the virtualservice vsName of the model, and returns true if the
+// model had errors before and has none now.
+func (m *ModelKeyLister) ClearError(modelName, vsName string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	errors, ok := m.modelErrors[modelName]
+	if !ok {
+		return false
+	}
+	delete(errors, vsName)
+	if len(errors) == 0 {
+		delete(m.modelErrors, modelName)
+		return true
+	}
+	return false
+}
+
+// GetError returns an error of the model, sorted by the virtualservice name, and false if the model has no errors.
+func (m *ModelKeyLister) GetError(modelName string) (string, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	errors, ok := m.modelErrors[modelName]
+	if !ok || len(errors) == 0 {
+		return "", false
+	}
+	vsNames := make([]string, 0, len(errors))
+	for vsName := range errors {
+		vsNames = append(vsNames, vsName)
+	}
+	sort.Strings(vsNames)
+	return errors[vsNames[0]], true
+}
@@ -52,6 +52,8 @@ func (rest *RestOperations) RestOperationForEvh(vsName string, namespace string,
 			publishKey = splitKeys[1]
 		}
 	}
+	// The retry layer publishes the model of the virtualservice, which is keyed by its tenant.
+	publishKey = lib.GetModelName(namespace, publishKey)
 	// Order would be this: 1. Pools 2. PGs  3. DS. 4. SSLKeyCert 5. VS
 	if vs_cache_obj != nil {
 		var rest_ops []*utils.RestOp
@@ -168,6 +170,7 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 	var sni_pgs_to_delete []avicache.NamespaceName
 	var http_policies_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
+	var app_profiles_to_delete, pki_profiles_to_delete []avicache.NamespaceName
 	if vs_cache_obj != nil {
 		sni_key := avicache.NamespaceName{Namespace: namespace, Name: sni_node.Name}
 		// Search the VS cache and obtain the UUID of this VS. Then see if this UUID is part of the SNIChildCollection or not.
@@ -186,6 +189,7 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 				sni_pools_to_delete, rest_ops = rest.PoolCU(sni_node.PoolRefs, sni_cache_obj, namespace, rest_ops, key)
 				sni_pgs_to_delete, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, sni_cache_obj, namespace, rest_ops, key)
 				http_policies_to_delete, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, sni_cache_obj, namespace, rest_ops, key)
+				app_profiles_to_delete, pki_profiles_to_delete, rest_ops = rest.AppProfileCU(sni_node.GetClientAuthAppProfile(), sni_node.Name, namespace, rest_ops, key)
 
 				// The checksums are different, so it should be a PUT call.
 				if sni_cache_obj.CloudConfigCksum != strconv.Itoa(int(sni_node.GetCheckSum())) {
@@ -204,6 +208,7 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 			_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+			_, _, rest_ops = rest.AppProfileCU(sni_node.GetClientAuthAppProfile(), sni_node.Name, namespace, rest_ops, key)
 
 			// Not found - it should be a POST call.
 			restOp := rest.AviVsBuildForEvh(sni_node, utils.RestPost, nil, key)
@@ -215,6 +220,8 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 		rest_ops = rest.HTTPPolicyDelete(http_policies_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(sni_pgs_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(sni_pools_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.AppProfileDelete(app_profiles_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.PkiProfileDelete(pki_profiles_to_delete, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: the EVH VSes to be deleted are: %s", key, cache_sni_nodes)
 	} else {
 		utils.AviLog.Debugf("key: %s, msg: EVH child %s not found in cache and EVH parent also does not exist in cache", key, sni_node.Name)
@@ -223,6 +230,7 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 		_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+		_, _, rest_ops = rest.AppProfileCU(sni_node.GetClientAuthAppProfile(), sni_node.Name, namespace, rest_ops, key)
 
 		// Not found - it should be a POST call.
 		restOp := rest.AviVsBuildForEvh(sni_node, utils.RestPost, nil, key)
@@ -418,6 +426,7 @@ func (rest *RestOperations) AviVsBuildForEvh(vs_meta *nodes.AviEvhVsNode, rest_m
 			vs.RemoveListeningPortOnVsDown = &vsDownOnPoolDown
 		}
 		vs.AnalyticsPolicy = vs_meta.GetAnalyticsPolicy()
+		vs.ConnectionsRateLimit = vs_meta.GetConnectionsRateLimit()
 
 		var rest_ops []*utils.RestOp
 
@@ -544,6 +553,7 @@ func (rest *RestOperations) AviVsChildEvhBuild(vs_meta *nodes.AviEvhVsNode, rest
 		evhChild.HTTPPolicies = AviVsHttpPSAdd(vs_meta, true)
 	}
 	evhChild.AnalyticsPolicy = vs_meta.GetAnalyticsPolicy()
+	evhChild.ConnectionsRateLimit = vs_meta.GetConnectionsRateLimit()
 
 	var rest_ops []*utils.RestOp
 	var rest_op utils.RestOp
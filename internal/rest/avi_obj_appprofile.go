@@ -0,0 +1,209 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/davecgh/go-spew/spew"
+	avimodels "github.com/vmware/alb-sdk/go/models"
+)
+
+func (rest *RestOperations) AviAppProfileBuild(appProfileNode *nodes.AviAppProfileNode, cache_obj *avicache.AviAppProfileCache) *utils.RestOp {
+	if lib.CheckObjectNameLength(appProfileNode.Name, lib.ApplicationProfile) {
+		utils.AviLog.Warnf("Not processing application profile")
+		return nil
+	}
+	name := appProfileNode.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", appProfileNode.Tenant)
+	profileType := "APPLICATION_PROFILE_TYPE_HTTP"
+	cr := lib.AKOUser
+	clientCertMode := appProfileNode.ClientCertMode
+	pkiProfileRef := fmt.Sprintf("/api/pkiprofile?name=%s", appProfileNode.GetPkiProfileName())
+
+	// The profile is cloned from the one the virtualservice would use otherwise, with the client certificate
+	// settings added.
+	appProfileObject, err := rest.getBaseAppProfile(appProfileNode.BaseProfile)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to get the application profile %s to clone %s from: %v", appProfileNode.BaseProfile,
+			appProfileNode.Name, err)
+		return nil
+	}
+	if appProfileObject.HTTPProfile == nil {
+		appProfileObject.HTTPProfile = &avimodels.HTTPApplicationProfile{}
+	}
+	httpProfile := appProfileObject.HTTPProfile
+	httpProfile.SslClientCertificateMode = &clientCertMode
+	httpProfile.PkiProfileRef = &pkiProfileRef
+	httpProfile.SslClientCertificateAction = nil
+	if len(appProfileNode.ClientCertHeaders) > 0 {
+		httpProfile.SslClientCertificateAction = &avimodels.SSLClientCertificateAction{}
+		for i := range appProfileNode.ClientCertHeaders {
+			header := appProfileNode.ClientCertHeaders[i]
+			httpProfile.SslClientCertificateAction.Headers = append(httpProfile.SslClientCertificateAction.Headers,
+				&avimodels.SSLClientRequestHeader{
+					RequestHeader:      &header.Name,
+					RequestHeaderValue: &header.Value,
+				})
+		}
+	}
+
+	appProfileObject.Name = &name
+	appProfileObject.Type = &profileType
+	appProfileObject.CreatedBy = &cr
+	appProfileObject.TenantRef = &tenant
+	appProfileObject.Markers = lib.GetAllMarkers(appProfileNode.AviMarkers)
+
+	var path string
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		path = "/api/applicationprofile/" + cache_obj.Uuid
+		rest_op = utils.RestOp{
+			ObjName: appProfileNode.Name,
+			Path:    path,
+			Method:  utils.RestPut,
+			Obj:     appProfileObject,
+			Tenant:  appProfileNode.Tenant,
+			Model:   "ApplicationProfile",
+		}
+	} else {
+		path = "/api/applicationprofile/"
+		rest_op = utils.RestOp{
+			ObjName: appProfileNode.Name,
+			Path:    path,
+			Method:  utils.RestPost,
+			Obj:     appProfileObject,
+			Tenant:  appProfileNode.Tenant,
+			Model:   "ApplicationProfile",
+		}
+	}
+	return &rest_op
+}
+
+// baseAppProfiles holds the application profiles the client auth profiles are cloned from, keyed by the name.
+var baseAppProfiles sync.Map
+
+// getBaseAppProfile returns a copy of the application profile, which is fetched from the Avi controller once. The
+// fields identifying the profile are cleared, for the copy to be created as a profile of its own.
+func (rest *RestOperations) getBaseAppProfile(name string) (avimodels.ApplicationProfile, error) {
+	var appProfile avimodels.ApplicationProfile
+	data, ok := baseAppProfiles.Load(name)
+	if !ok {
+		if rest.aviRestPoolClient == nil || len(rest.aviRestPoolClient.AviClient) == 0 {
+			return appProfile, errors.New("avi client not initialized")
+		}
+		result, err := lib.AviGetCollectionRaw(rest.aviRestPoolClient.AviClient[0], "/api/applicationprofile/?name="+name)
+		if err != nil {
+			return appProfile, err
+		}
+		var profiles []json.RawMessage
+		if err = json.Unmarshal(result.Results, &profiles); err != nil {
+			return appProfile, err
+		}
+		if len(profiles) == 0 {
+			return appProfile, fmt.Errorf("application profile %s not found", name)
+		}
+		data = []byte(profiles[0])
+		baseAppProfiles.Store(name, data)
+	}
+	if err := json.Unmarshal(data.([]byte), &appProfile); err != nil {
+		return appProfile, err
+	}
+	appProfile.UUID = nil
+	appProfile.URL = nil
+	appProfile.LastModified = nil
+	appProfile.CloudConfigCksum = nil
+	appProfile.Description = nil
+	appProfile.Markers = nil
+	return appProfile, nil
+}
+
+func (rest *RestOperations) AviAppProfileDel(uuid string, tenant string) *utils.RestOp {
+	path := "/api/applicationprofile/" + uuid
+	rest_op := utils.RestOp{
+		Path:   path,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "ApplicationProfile",
+	}
+	utils.AviLog.Info(spew.Sprintf("ApplicationProfile DELETE Restop %v ",
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviAppProfileAdd(rest_op *utils.RestOp, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for ApplicationProfileObj", key)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := RestRespArrToObjByType(rest_op, "applicationprofile", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, unable to find ApplicationProfile obj in resp %v", key, rest_op.Response)
+		return errors.New("ApplicationProfile not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var appProfileObj avimodels.ApplicationProfile
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			appProfileObj = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationProfile)
+		case avimodels.ApplicationProfile:
+			appProfileObj = rest_op.Obj.(avimodels.ApplicationProfile)
+		}
+		lastModifiedStr, _ := resp["_last_modified"].(string)
+		app_profile_cache_obj := avicache.AviAppProfileCache{
+			Name:             name,
+			Tenant:           rest_op.Tenant,
+			Uuid:             uuid,
+			CloudConfigCksum: avicache.AppProfileChecksum(&appProfileObj),
+			LastModified:     lastModifiedStr,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.AppProfileCache.AviCacheAdd(k, &app_profile_cache_obj)
+		utils.AviLog.Info(spew.Sprintf("key: %s, msg: added ApplicationProfile cache k %v val %v", key, k,
+			app_profile_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviAppProfileCacheDel(rest_op *utils.RestOp, key string) error {
+	appProfileKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	utils.AviLog.Infof("key: %s, msg: deleting ApplicationProfile cache %v", key, appProfileKey)
+	rest.cache.AppProfileCache.AviCacheDelete(appProfileKey)
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/davecgh/go-spew/spew"
+	avimodels "github.com/vmware/alb-sdk/go/models"
+)
+
+func (rest *RestOperations) AviHealthMonitorBuild(hm_node *nodes.AviHealthMonitorNode, cache_obj *avicache.AviHealthMonitorCache) *utils.RestOp {
+	if lib.CheckObjectNameLength(hm_node.Name, lib.HealthMonitor) {
+		utils.AviLog.Warnf("Not processing health monitor")
+		return nil
+	}
+	name := hm_node.Name
+	hmType := hm_node.Type
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", hm_node.Tenant)
+	sendInterval := hm_node.SendInterval
+	receiveTimeout := hm_node.ReceiveTimeout
+	successfulChecks := hm_node.SuccessfulChecks
+	failedChecks := hm_node.FailedChecks
+
+	hmobject := avimodels.HealthMonitor{
+		Name:             &name,
+		Type:             &hmType,
+		TenantRef:        &tenant,
+		SendInterval:     &sendInterval,
+		ReceiveTimeout:   &receiveTimeout,
+		SuccessfulChecks: &successfulChecks,
+		FailedChecks:     &failedChecks,
+	}
+	if hm_node.MonitorPort != 0 {
+		monitorPort := hm_node.MonitorPort
+		hmobject.MonitorPort = &monitorPort
+	}
+
+	// The kubelet considers the response codes from 200 to 399 as success.
+	httpRequest := hm_node.HTTPRequest
+	httpMonitor := &avimodels.HealthMonitorHTTP{
+		HTTPRequest:      &httpRequest,
+		HTTPResponseCode: []string{"HTTP_2XX", "HTTP_3XX"},
+	}
+	switch hmType {
+	case lib.HEALTH_MONITOR_HTTP:
+		hmobject.HTTPMonitor = httpMonitor
+	case lib.HEALTH_MONITOR_HTTPS:
+		hmobject.HTTPSMonitor = httpMonitor
+	case lib.HEALTH_MONITOR_TCP:
+		hmobject.TCPMonitor = &avimodels.HealthMonitorTCP{}
+	}
+
+	hmobject.Markers = lib.GetAllMarkers(hm_node.AviMarkers)
+
+	var path string
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		path = "/api/healthmonitor/" + cache_obj.Uuid
+		rest_op = utils.RestOp{
+			ObjName: hm_node.Name,
+			Path:    path,
+			Method:  utils.RestPut,
+			Obj:     hmobject,
+			Tenant:  hm_node.Tenant,
+			Model:   "HealthMonitor",
+		}
+	} else {
+		path = "/api/healthmonitor/"
+		rest_op = utils.RestOp{
+			ObjName: hm_node.Name,
+			Path:    path,
+			Method:  utils.RestPost,
+			Obj:     hmobject,
+			Tenant:  hm_node.Tenant,
+			Model:   "HealthMonitor",
+		}
+	}
+	return &rest_op
+}
+
+func (rest *RestOperations) AviHealthMonitorDel(uuid string, tenant string) *utils.RestOp {
+	path := "/api/healthmonitor/" + uuid
+	rest_op := utils.RestOp{
+		Path:   path,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "HealthMonitor",
+	}
+	utils.AviLog.Info(spew.Sprintf("HealthMonitor DELETE Restop %v ",
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviHealthMonitorAdd(rest_op *utils.RestOp, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for HealthMonitorObj", key)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := RestRespArrToObjByType(rest_op, "healthmonitor", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, unable to find HealthMonitor obj in resp %v", key, rest_op.Response)
+		return errors.New("HealthMonitor not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var hmObj avimodels.HealthMonitor
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			hmObj = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HealthMonitor)
+		case avimodels.HealthMonitor:
+			hmObj = rest_op.Obj.(avimodels.HealthMonitor)
+		}
+		lastModifiedStr, _ := resp["_last_modified"].(string)
+		hm_cache_obj := avicache.AviHealthMonitorCache{
+			Name:             name,
+			Tenant:           rest_op.Tenant,
+			Uuid:             uuid,
+			CloudConfigCksum: avicache.HealthMonitorChecksum(&hmObj),
+			LastModified:     lastModifiedStr,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.HealthMonitorCache.AviCacheAdd(k, &hm_cache_obj)
+		utils.AviLog.Info(spew.Sprintf("key: %s, msg: added HealthMonitor cache k %v val %v", key, k,
+			hm_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviHealthMonitorCacheDel(rest_op *utils.RestOp, key string) error {
+	hmKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	utils.AviLog.Infof("key: %s, msg: deleting HealthMonitor cache %v", key, hmKey)
+	rest.cache.HealthMonitorCache.AviCacheDelete(hmKey)
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
@@ -62,7 +63,9 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 		}
 		httpPresentIng.Insert(hppmap.IngName)
 	}
-	sort.Slice(hppmapWithPath, func(i, j int) bool {
+	// Keep the relative order of rules with paths of the same length, since
+	// rules with additional match conditions are placed ahead of the generic ones.
+	sort.SliceStable(hppmapWithPath, func(i, j int) bool {
 		return len(hppmapWithPath[i].Path[0]) > len(hppmapWithPath[j].Path[0])
 	})
 	hppmapAllPaths = append(hppmapAllPaths, hppmapWithPath...)
@@ -89,15 +92,26 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 			utils.AviLog.Warnf("key: %s not adding rule to HTTPS object", key)
 			continue
 		}
+		sec_rule := sec_rule
 		action := avimodels.HttpsecurityAction{
 			Action: &sec_rule.Action,
 		}
-		portMatch := avimodels.PortMatch{
-			MatchCriteria: &sec_rule.MatchCriteria,
-			Ports:         []int64{sec_rule.Port},
+		if sec_rule.StatusCode != "" {
+			action.StatusCode = &sec_rule.StatusCode
 		}
-		match := avimodels.MatchTarget{
-			VsPort: &portMatch,
+		if sec_rule.RateLimit != nil {
+			action.RateProfile = buildSecurityRateProfile(sec_rule.RateLimit)
+		}
+		match := avimodels.MatchTarget{}
+		if sec_rule.Port != 0 {
+			portMatch := avimodels.PortMatch{
+				MatchCriteria: &sec_rule.MatchCriteria,
+				Ports:         []int64{sec_rule.Port},
+			}
+			match.VsPort = &portMatch
+		}
+		if sec_rule.ClientIP != nil {
+			match.ClientIP = buildClientIPMatch(sec_rule.ClientIP)
 		}
 		var j int32
 		j = idx
@@ -150,6 +164,25 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 			}
 			match_target.VsPort = &vsport_match
 		}
+		match_target.Hdrs = buildHdrMatches(hppmap.HdrMatches)
+		if hppmap.CookieMatch != nil {
+			match_target.Cookie = buildCookieMatch(hppmap.CookieMatch)
+		}
+
+		if hppmap.Redirect != nil {
+			var j int32
+			j = idx
+			rule := avimodels.HTTPRequestRule{
+				Index:          &j,
+				Enable:         &enable,
+				Name:           &name,
+				Match:          &match_target,
+				RedirectAction: buildRedirectAction(hppmap.Redirect),
+			}
+			http_req_pol.Rules = append(http_req_pol.Rules, &rule)
+			idx = idx + 1
+			continue
+		}
 
 		sw_action := avimodels.HttpswitchingAction{}
 		if hppmap.Pool != "" {
@@ -167,15 +200,54 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 		var j int32
 		j = idx
 		rule := avimodels.HTTPRequestRule{
-			Index:           &j,
-			Enable:          &enable,
-			Name:            &name,
-			Match:           &match_target,
-			SwitchingAction: &sw_action,
+			Index:  &j,
+			Enable: &enable,
+			Name:   &name,
+			Match:  &match_target,
+		}
+		if len(hppmap.RequestHeaders) > 0 {
+			rule.HdrAction = buildHdrActions(hppmap.RequestHeaders)
+		}
+		if hppmap.RewriteURL != nil {
+			rule.RewriteURLAction = buildRewriteURLAction(hppmap.RewriteURL)
+		}
+		// The rules of the HTTPRule path actions only modify the request or the response, the pool is
+		// selected by the httppolicysets that precede.
+		if hppmap.Pool != "" || hppmap.PoolGroup != "" || (rule.HdrAction == nil && rule.RewriteURLAction == nil && len(hppmap.ResponseHeaders) == 0) {
+			rule.SwitchingAction = &sw_action
+		}
+		if rule.SwitchingAction != nil || rule.HdrAction != nil || rule.RewriteURLAction != nil {
+			http_req_pol.Rules = append(http_req_pol.Rules, &rule)
+			idx = idx + 1
 		}
-		http_req_pol.Rules = append(http_req_pol.Rules, &rule)
-		idx = idx + 1
 
+		if len(hppmap.ResponseHeaders) > 0 {
+			rspName := fmt.Sprintf("%s-%d", hps_meta.Name, idx)
+			if lib.CheckObjectNameLength(rspName, lib.HTTPResponseRule) {
+				utils.AviLog.Warnf("key: %s not adding response rule to HTTPS object", key)
+				continue
+			}
+			rsp_match_target := avimodels.ResponseMatchTarget{
+				HostHdr: match_target.HostHdr,
+				Path:    match_target.Path,
+				VsPort:  match_target.VsPort,
+				Hdrs:    match_target.Hdrs,
+			}
+			var k int32
+			k = idx
+			rspRule := avimodels.HTTPResponseRule{
+				Index:     &k,
+				Enable:    &enable,
+				Name:      &rspName,
+				Match:     &rsp_match_target,
+				HdrAction: buildHdrActions(hppmap.ResponseHeaders),
+			}
+			if hps.HTTPResponsePolicy == nil {
+				hps.HTTPResponsePolicy = &avimodels.HTTPResponsePolicy{}
+			}
+			hps.HTTPResponsePolicy.Rules = append(hps.HTTPResponsePolicy.Rules, &rspRule)
+			idx = idx + 1
+		}
 	}
 
 	for _, hppmap := range hps_meta.RedirectPorts {
@@ -413,3 +485,164 @@ func (rest *RestOperations) AviHTTPPolicyCacheDel(rest_op *utils.RestOp, vsKey a
 
 	return nil
 }
+
+func buildHdrMatches(hdrMatches []nodes.AviHTTPHeaderMatch) []*avimodels.HdrMatch {
+	var hdrs []*avimodels.HdrMatch
+	for i := range hdrMatches {
+		hdrMatch := &avimodels.HdrMatch{
+			Hdr:           &hdrMatches[i].Name,
+			MatchCriteria: &hdrMatches[i].MatchCriteria,
+			Value:         hdrMatches[i].Value,
+		}
+		if hdrMatches[i].MatchCase != "" {
+			hdrMatch.MatchCase = &hdrMatches[i].MatchCase
+		}
+		hdrs = append(hdrs, hdrMatch)
+	}
+	return hdrs
+}
+
+func buildCookieMatch(cookieMatch *nodes.AviHTTPCookieMatch) *avimodels.CookieMatch {
+	cookie := &avimodels.CookieMatch{
+		Name:          &cookieMatch.Name,
+		MatchCriteria: &cookieMatch.MatchCriteria,
+		Value:         &cookieMatch.Value,
+	}
+	if cookieMatch.MatchCase != "" {
+		cookie.MatchCase = &cookieMatch.MatchCase
+	}
+	return cookie
+}
+
+func buildClientIPMatch(clientIP *nodes.AviHTTPSecurityClientIPMatch) *avimodels.IPAddrMatch {
+	ipMatch := &avimodels.IPAddrMatch{
+		MatchCriteria: &clientIP.MatchCriteria,
+	}
+	for _, cidr := range clientIP.Prefixes {
+		addr, mask, err := lib.ParseCIDROrIP(cidr)
+		if err != nil {
+			utils.AviLog.Warnf("Skipping client IP match %s: %v", cidr, err)
+			continue
+		}
+		addrType := "V4"
+		if strings.Contains(addr, ":") {
+			addrType = "V6"
+		}
+		ipMatch.Prefixes = append(ipMatch.Prefixes, &avimodels.IPAddrPrefix{
+			IPAddr: &avimodels.IPAddr{Addr: &addr, Type: &addrType},
+			Mask:   &mask,
+		})
+	}
+	for _, ipGroup := range clientIP.IPGroups {
+		ipMatch.GroupRefs = append(ipMatch.GroupRefs, fmt.Sprintf("/api/ipaddrgroup?name=%s", ipGroup))
+	}
+	return ipMatch
+}
+
+// buildSecurityRateProfile returns the rate profile of a rate limit security rule, which
+// responds with 429 Too Many Requests on exceeding the limit.
+func buildSecurityRateProfile(rateLimit *nodes.AviHTTPSecurityRateLimit) *avimodels.HttpsecurityActionRateProfile {
+	count, period, burst := rateLimit.Count, rateLimit.Period, rateLimit.Burst
+	perClientIP := rateLimit.PerClientIP
+	actionType := "RL_ACTION_LOCAL_RSP"
+	statusCode := "HTTP_LOCAL_RESPONSE_STATUS_CODE_429"
+	return &avimodels.HttpsecurityActionRateProfile{
+		Action: &avimodels.RateLimiterAction{
+			Type:       &actionType,
+			StatusCode: &statusCode,
+		},
+		PerClientIP: &perClientIP,
+		RateLimiter: &avimodels.RateLimiter{
+			Count:   &count,
+			Period:  &period,
+			BurstSz: &burst,
+		},
+	}
+}
+
+func buildHdrActions(hdrActions []nodes.AviHTTPHeaderAction) []*avimodels.HTTPHdrAction {
+	var actions []*avimodels.HTTPHdrAction
+	for i := range hdrActions {
+		headerData := avimodels.HTTPHdrData{Name: &hdrActions[i].Name}
+		if hdrActions[i].Action != lib.HTTPRemoveHeader {
+			headerData.Value = &avimodels.HTTPHdrValue{Val: &hdrActions[i].Value}
+		}
+		actions = append(actions, &avimodels.HTTPHdrAction{
+			Action: &hdrActions[i].Action,
+			Hdr:    &headerData,
+		})
+	}
+	return actions
+}
+
+// buildTokenizedURIParam returns a tokenized uri param with the string value as the only token.
+func buildTokenizedURIParam(value string) *avimodels.URIParam {
+	paramType := "URI_PARAM_TYPE_TOKENIZED"
+	tokenType := "URI_TOKEN_TYPE_STRING"
+	return &avimodels.URIParam{
+		Type: &paramType,
+		Tokens: []*avimodels.URIParamToken{
+			{Type: &tokenType, StrValue: &value},
+		},
+	}
+}
+
+func buildRewriteURLAction(rewrite *nodes.AviHTTPRewriteURL) *avimodels.HTTPRewriteURLAction {
+	action := &avimodels.HTTPRewriteURLAction{}
+	if rewrite.Host != "" {
+		action.HostHdr = buildTokenizedURIParam(rewrite.Host)
+	}
+	if rewrite.Path == "" && !rewrite.ReplacePrefix {
+		return action
+	}
+
+	// The tokenized path does not carry the leading "/", the Avi controller adds it.
+	path := strings.Trim(rewrite.Path, "/")
+	if !rewrite.ReplacePrefix {
+		action.Path = buildTokenizedURIParam(path)
+		return action
+	}
+	paramType := "URI_PARAM_TYPE_TOKENIZED"
+	action.Path = &avimodels.URIParam{Type: &paramType}
+	if path != "" {
+		action.Path.Tokens = buildTokenizedURIParam(path).Tokens
+	}
+	// Retain the rest of the request path, following the replaced prefix.
+	tokenType := "URI_TOKEN_TYPE_PATH"
+	startIndex := rewrite.PrefixTokens
+	endIndex := int32(lib.URITokenEndIndex)
+	action.Path.Tokens = append(action.Path.Tokens, &avimodels.URIParamToken{
+		Type:       &tokenType,
+		StartIndex: &startIndex,
+		EndIndex:   &endIndex,
+	})
+	return action
+}
+
+func buildRedirectAction(redirect *nodes.AviHTTPRedirect) *avimodels.HTTPRedirectAction {
+	protocol := redirect.Protocol
+	if protocol == "" {
+		protocol = "HTTP"
+	}
+	statusCode := redirect.StatusCode
+	if statusCode == "" {
+		statusCode = lib.STATUS_REDIRECT
+	}
+	keepQuery := true
+	action := &avimodels.HTTPRedirectAction{
+		Protocol:   &protocol,
+		StatusCode: &statusCode,
+		KeepQuery:  &keepQuery,
+	}
+	if redirect.Host != "" {
+		action.Host = buildTokenizedURIParam(redirect.Host)
+	}
+	if redirect.Path != "" {
+		action.Path = buildTokenizedURIParam(strings.TrimPrefix(redirect.Path, "/"))
+	}
+	if redirect.Port != 0 {
+		port := redirect.Port
+		action.Port = &port
+	}
+	return action
+}
@@ -147,13 +147,27 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 			sn := server.ServerNode
 			s.ServerNode = &sn
 		}
+		if server.Draining {
+			s.Enabled = proto.Bool(false)
+		}
+		if server.AvailabilityZone != "" {
+			s.AvailabilityZone = proto.String(server.AvailabilityZone)
+		}
 		pool.Servers = append(pool.Servers, &s)
 	}
+	// The disabled servers keep serving the existing connections until the graceful disable timeout.
+	if gracefulDisableTimeout := lib.GetPoolGracefulDisableTimeout(); gracefulDisableTimeout > 0 {
+		pool.GracefulDisableTimeout = &gracefulDisableTimeout
+	}
 
 	// overwrite with healthmonitors provided by CRD
 	if len(pool_meta.HealthMonitors) > 0 {
 		pool.HealthMonitorRefs = pool_meta.HealthMonitors
-	} else {
+	}
+	if pool_meta.ProbeHealthMonitor != nil {
+		pool.HealthMonitorRefs = append(pool.HealthMonitorRefs, "/api/healthmonitor?name="+pool_meta.ProbeHealthMonitor.Name)
+	}
+	if len(pool.HealthMonitorRefs) == 0 {
 		var hm string
 		if pool_meta.Protocol == utils.UDP {
 			hm = fmt.Sprintf("/api/healthmonitor/?name=%s", utils.AVI_DEFAULT_UDP_HM)
@@ -273,9 +287,19 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 			pkiUuid := avicache.ExtractUuid(pkiprof.(string), "pkiprofile-.*.#")
 			pkiName, foundPki := rest.cache.PKIProfileCache.AviCacheGetNameByUuid(pkiUuid)
 			if foundPki {
-				pkiKey = avicache.NamespaceName{Namespace: lib.GetTenant(), Name: pkiName.(string)}
+				pkiKey = avicache.NamespaceName{Namespace: rest_op.Tenant, Name: pkiName.(string)}
+			}
+		}
+
+		var hmRefs []string
+		if refs, ok := resp["health_monitor_refs"].([]interface{}); ok {
+			for _, ref := range refs {
+				if hmRef, ok := ref.(string); ok {
+					hmRefs = append(hmRefs, hmRef)
+				}
 			}
 		}
+		hmKey := rest.cache.HealthMonitorKeyFromRefs(rest_op.Tenant, hmRefs)
 
 		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
 		oldCacheServiceMetadataCRD := lib.CRDMetadata{}
@@ -286,13 +310,14 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 		}
 
 		pool_cache_obj := avicache.AviPoolCache{
-			Name:                 name,
-			Tenant:               rest_op.Tenant,
-			Uuid:                 uuid,
-			CloudConfigCksum:     cksum,
-			ServiceMetadataObj:   svc_mdata_obj,
-			PkiProfileCollection: pkiKey,
-			LastModified:         lastModifiedStr,
+			Name:                    name,
+			Tenant:                  rest_op.Tenant,
+			Uuid:                    uuid,
+			CloudConfigCksum:        cksum,
+			ServiceMetadataObj:      svc_mdata_obj,
+			PkiProfileCollection:    pkiKey,
+			HealthMonitorCollection: hmKey,
+			LastModified:            lastModifiedStr,
 		}
 		if lastModifiedStr == "" {
 			pool_cache_obj.InvalidData = true
@@ -387,6 +412,7 @@ func (rest *RestOperations) AviPoolCacheDel(rest_op *utils.RestOp, vsKey avicach
 		}
 	}
 	rest.cache.PoolCache.AviCacheDelete(poolKey)
+	nodes.ForgetPoolDrainServers(poolKey.Namespace, poolKey.Name)
 	if (cacheServiceMetadataCRD != lib.CRDMetadata{}) {
 		status.HttpRuleEventBroadcast(poolKey.Name, cacheServiceMetadataCRD, lib.CRDMetadata{})
 	}
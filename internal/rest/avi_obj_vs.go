@@ -236,6 +236,7 @@ func (rest *RestOperations) AviVsBuild(vs_meta *nodes.AviVsNode, rest_method uti
 			vs.L4Policies = l4Policies
 		}
 		vs.AnalyticsPolicy = vs_meta.GetAnalyticsPolicy()
+		vs.ConnectionsRateLimit = vs_meta.GetConnectionsRateLimit()
 
 		var rest_ops []*utils.RestOp
 
@@ -308,6 +309,7 @@ func (rest *RestOperations) AviVsSniBuild(vs_meta *nodes.AviVsNode, rest_method
 		Enabled:               vs_meta.Enabled,
 	}
 	sniChild.AnalyticsPolicy = vs_meta.GetAnalyticsPolicy()
+	sniChild.ConnectionsRateLimit = vs_meta.GetConnectionsRateLimit()
 	if lib.GetT1LRPath() != "" {
 		// Clear the vrfContextRef
 		sniChild.VrfContextRef = nil
@@ -523,6 +525,8 @@ func (rest *RestOperations) StatusUpdateForVS(restMethod utils.RestMethod, vsCac
 		}
 		if lib.UseServicesAPI() {
 			statusOption.ObjType = lib.SERVICES_API
+		} else if lib.UseGatewayAPI() {
+			statusOption.ObjType = lib.GatewayAPIGateway
 		}
 		utils.AviLog.Infof("key: %s Publishing to status queue, options: %v", updateOptions.ServiceMetadata.Gateway, utils.Stringify(statusOption))
 		status.PublishToStatusQueue(updateOptions.ServiceMetadata.Gateway, statusOption)
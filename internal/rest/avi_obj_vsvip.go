@@ -34,6 +34,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 func GetIPAMProviderType() string {
@@ -62,7 +63,7 @@ func (rest *RestOperations) AviVsVipBuild(vsvip_meta *nodes.AviVSVIPNode, vsCach
 	var dns_info_arr []*avimodels.DNSInfo
 	var path string
 	var rest_op utils.RestOp
-	vipId, ipType := "0", "V4"
+	vipId := "0"
 
 	cksum := vsvip_meta.CloudConfigCksum
 	cksumstr := strconv.Itoa(int(cksum))
@@ -103,9 +104,7 @@ func (rest *RestOperations) AviVsVipBuild(vsvip_meta *nodes.AviVSVIPNode, vsCach
 			}
 
 			// This would throw an error for advl4 the error is propagated to the gateway status.
-			if vsvip_meta.IPAddress != "" {
-				vip.IPAddress = &avimodels.IPAddr{Type: &ipType, Addr: &vsvip_meta.IPAddress}
-			}
+			buildVipAddress(vip, vsvip_meta, key)
 
 			if lib.IsPublicCloud() && lib.GetCloudType() != lib.CLOUD_GCP {
 				vips := networkNamesToVips(vsvip_meta.VipNetworks, vsvip_meta.EnablePublicIP)
@@ -161,9 +160,7 @@ func (rest *RestOperations) AviVsVipBuild(vsvip_meta *nodes.AviVSVIPNode, vsCach
 		}
 
 		// configuring static IP, from gateway.Addresses (advl4, svcapi) and service.loadBalancerIP (l4)
-		if vsvip_meta.IPAddress != "" {
-			vip.IPAddress = &avimodels.IPAddr{Type: &ipType, Addr: &vsvip_meta.IPAddress}
-		}
+		buildVipAddress(&vip, vsvip_meta, key)
 
 		// selecting network with user input, in case user input is not provided AKO relies on
 		// usable network configuration in ipamdnsproviderprofile
@@ -179,26 +176,13 @@ func (rest *RestOperations) AviVsVipBuild(vsvip_meta *nodes.AviVSVIPNode, vsCach
 				networkRef := "/api/network/?name=" + vipNetwork.NetworkName
 				vip.IPAMNetworkSubnet.NetworkRef = &networkRef
 
-				// setting IPAMNetworkSubnet.Subnet value in case subnetCIDR is provided
-				if vipNetwork.Cidr == "" {
+				// setting IPAMNetworkSubnet.Subnet and IPAMNetworkSubnet.Subnet6 values in case subnetCIDRs are provided
+				if vipNetwork.Cidr == "" && vipNetwork.V6Cidr == "" {
 					utils.AviLog.Warnf("key: %s, msg: Incomplete values provided for CIDR, will not use IPAMNetworkSubnet in vsvip", key)
-				} else {
-					ipPrefixSlice := strings.Split(vipNetwork.Cidr, "/")
-					mask, _ := strconv.Atoi(ipPrefixSlice[1])
-					if lib.IsPublicCloud() && lib.GetCloudType() == lib.CLOUD_GCP {
-						vip.IPAMNetworkSubnet = &avimodels.IPNetworkSubnet{
-							Subnet: &avimodels.IPAddrPrefix{
-								IPAddr: &avimodels.IPAddr{Type: &ipType, Addr: &ipPrefixSlice[0]},
-								Mask:   proto.Int32(int32(mask)),
-							},
-						}
-					} else if !lib.GetAdvancedL4() {
-						vip.IPAMNetworkSubnet = &avimodels.IPNetworkSubnet{
-							Subnet: &avimodels.IPAddrPrefix{
-								IPAddr: &avimodels.IPAddr{Type: &ipType, Addr: &ipPrefixSlice[0]},
-								Mask:   proto.Int32(int32(mask)),
-							},
-						}
+				} else if (lib.IsPublicCloud() && lib.GetCloudType() == lib.CLOUD_GCP) || !lib.GetAdvancedL4() {
+					vip.IPAMNetworkSubnet = &avimodels.IPNetworkSubnet{
+						Subnet:  cidrToIPAddrPrefix(vipNetwork.Cidr),
+						Subnet6: cidrToIPAddrPrefix(vipNetwork.V6Cidr),
 					}
 				}
 			}
@@ -416,6 +400,13 @@ func (rest *RestOperations) AviVsVipCacheAdd(rest_op *utils.RestOp, vsKey avicac
 						Message: rest_op.Message,
 					})
 					status.UpdateSvcApiGatewayStatusObject(key, gw, gwStatus)
+				} else if lib.UseGatewayAPI() {
+					status.UpdateGatewayAPIGatewayConditionStatus(key, vs_cache_obj.ServiceMetadataObj.Gateway, metav1.Condition{
+						Type:    string(gwapiv1alpha2.GatewayConditionReady),
+						Status:  metav1.ConditionFalse,
+						Reason:  string(gwapiv1alpha2.GatewayReasonAddressNotAssigned),
+						Message: rest_op.Message,
+					})
 				}
 				utils.AviLog.Warnf("key: %s, msg: IPAddress Updates on gateway not supported, Please recreate gateway object with the new preferred IPAddress", key)
 				return errors.New(rest_op.Message)
@@ -488,16 +479,26 @@ func (rest *RestOperations) AviVsVipCacheAdd(rest_op *utils.RestOp, vsKey avicac
 						continue
 					}
 					ip_address, valid := vip["ip_address"].(map[string]interface{})
-					if !valid {
+					ip6_address, valid6 := vip["ip6_address"].(map[string]interface{})
+					if !valid && !valid6 {
 						utils.AviLog.Infof("key: %s, msg: invalid type for ip_address in vsvip: %s", key, name)
 						continue
 					}
-					addr, valid := ip_address["addr"].(string)
-					if !valid {
-						utils.AviLog.Infof("key: %s, msg: invalid type for addr in vsvip: %s", key, name)
-						continue
+					// the v4 and v6 addresses of a dual-stack vip are picked independently.
+					if valid {
+						if addr, ok := ip_address["addr"].(string); ok {
+							vsvipVips = append(vsvipVips, addr)
+						} else {
+							utils.AviLog.Infof("key: %s, msg: invalid type for addr in vsvip: %s", key, name)
+						}
+					}
+					if valid6 {
+						if addr, ok := ip6_address["addr"].(string); ok {
+							vsvipVips = append(vsvipVips, addr)
+						} else {
+							utils.AviLog.Infof("key: %s, msg: invalid type for ip6_address addr in vsvip: %s", key, name)
+						}
 					}
-					vsvipVips = append(vsvipVips, addr)
 					floating_ip, valid := vip["floating_ip"].(map[string]interface{})
 					if !valid {
 						utils.AviLog.Warnf("key: %s, msg: invalid type for floating_ip in vsvip: %s", key, name)
@@ -544,22 +545,25 @@ func (rest *RestOperations) AviVsVipCacheAdd(rest_op *utils.RestOp, vsKey avicac
 		}
 
 		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		// the vips of the existing cache decide if the status of the k8s objects needs an update.
+		var oldVsVips, oldVsFips, oldFQDNs []string
+		if oldVsVipCache, oldVsVipFound := rest.cache.VSVIPCache.AviCacheGet(k); oldVsVipFound {
+			if oldVsVipCacheObj, ok := oldVsVipCache.(*avicache.AviVSVIPCache); ok {
+				oldVsVips = oldVsVipCacheObj.Vips
+				oldVsFips = oldVsVipCacheObj.Fips
+				oldFQDNs = oldVsVipCacheObj.FQDNs
+			}
+		}
 		rest.cache.VSVIPCache.AviCacheAdd(k, &vsvip_cache_obj)
+		if rest_op.Method == utils.RestPost || !reflect.DeepEqual(vsvip_cache_obj.FQDNs, oldFQDNs) ||
+			!reflect.DeepEqual(vsvip_cache_obj.Vips, oldVsVips) || !reflect.DeepEqual(vsvip_cache_obj.Fips, oldVsFips) {
+			status.PublishDNSEndpoint(key, rest_op.Tenant, name, vsvipFQDNs, getDNSAddrsFromVsVipCache(&vsvip_cache_obj))
+		}
 		// Update the VS object
 		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
 		if ok {
 			vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
 			if found {
-				oldVsVipCache, oldVsVipFound := rest.cache.VSVIPCache.AviCacheGet(k)
-				var oldVsVips, oldVsFips []string
-				if oldVsVipFound {
-					oldVsVipCacheObj, ok := oldVsVipCache.(*avicache.AviVSVIPCache)
-					if ok {
-						oldVsVips = oldVsVipCacheObj.Vips
-						oldVsFips = oldVsVipCacheObj.Fips
-					}
-				}
-
 				vs_cache_obj.AddToVSVipKeyCollection(k)
 				utils.AviLog.Debugf("key: %s, msg: modified the VS cache object for VSVIP collection. The cache now is :%v", key, utils.Stringify(vs_cache_obj))
 				if rest_op.Method == utils.RestPut {
@@ -582,6 +586,10 @@ func (rest *RestOperations) AviVsVipCacheAdd(rest_op *utils.RestOp, vsKey avicac
 					if !reflect.DeepEqual(vsvip_cache_obj.Vips, oldVsVips) || !reflect.DeepEqual(vsvip_cache_obj.Fips, oldVsFips) {
 						rest.StatusUpdateForPool(rest_op.Method, vs_cache_obj, key)
 						// rest.StatusUpdateForVS(vs_cache_obj, key)
+						// the status of Services of type LoadBalancer is not derived from the pools.
+						if vs_cache_obj.ServiceMetadataObj.ServiceMetadataMapping("VS") == lib.ServiceTypeLBVS {
+							rest.StatusUpdateForVS(rest_op.Method, vs_cache_obj, key)
+						}
 					}
 				}
 			}
@@ -621,6 +629,7 @@ func (rest *RestOperations) AviVsVipCacheAdd(rest_op *utils.RestOp, vsKey avicac
 func (rest *RestOperations) AviVsVipCacheDel(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
 	vsvipkey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
 	rest.cache.VSVIPCache.AviCacheDelete(vsvipkey)
+	status.PublishDNSEndpointDelete(key, rest_op.Tenant, rest_op.ObjName)
 	if vsKey != (avicache.NamespaceName{}) {
 		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
 		if ok {
@@ -634,6 +643,15 @@ func (rest *RestOperations) AviVsVipCacheDel(rest_op *utils.RestOp, vsKey avicac
 	return nil
 }
 
+// getDNSAddrsFromVsVipCache returns the addresses which the fqdns of the vsvip resolve to, the floating ips when
+// the vsvip has them, and the vips otherwise.
+func getDNSAddrsFromVsVipCache(vsvipCacheObj *avicache.AviVSVIPCache) []string {
+	if len(vsvipCacheObj.Fips) > 0 {
+		return vsvipCacheObj.Fips
+	}
+	return vsvipCacheObj.Vips
+}
+
 func networkNamesToVips(vipNetworks []akov1alpha1.AviInfraSettingVipNetwork, enablePublicIP *bool) []*avimodels.Vip {
 	var vipList []*avimodels.Vip
 	autoAllocate := true
@@ -651,3 +669,58 @@ func networkNamesToVips(vipNetworks []akov1alpha1.AviInfraSettingVipNetwork, ena
 
 	return vipList
 }
+
+// buildVipAddress sets the ip type of the vip to be allocated, and the static IPv4 or IPv6
+// address of the vip, if provided. The ip type of the vsvip node is derived from the vip
+// networks, in case it is not set by the k8s object. The vip is allocated from the v4 range,
+// when the k8s object asks for an IPv6 vip and the vip networks have no v6 CIDR.
+func buildVipAddress(vip *avimodels.Vip, vsvip_meta *nodes.AviVSVIPNode, key string) {
+	autoAllocateIPType := vsvip_meta.IPType
+	if autoAllocateIPType == "" {
+		autoAllocateIPType = lib.GetVipNetworksIPType(vsvip_meta.VipNetworks)
+	} else if (autoAllocateIPType == lib.VipIPTypeV6Only || autoAllocateIPType == lib.VipIPTypeDualStack) &&
+		!vipNetworksAllowV6(vsvip_meta.VipNetworks) {
+		utils.AviLog.Warnf("key: %s, msg: the vip networks of vsvip %s have no v6 CIDR, allocating the vip of type %s from the v4 range",
+			key, vsvip_meta.Name, autoAllocateIPType)
+		autoAllocateIPType = ""
+	}
+	if autoAllocateIPType == lib.VipIPTypeV6Only || autoAllocateIPType == lib.VipIPTypeDualStack {
+		vip.AutoAllocateIPType = &autoAllocateIPType
+	}
+
+	if vsvip_meta.IPAddress == "" {
+		return
+	}
+	ipType := lib.GetIPType(vsvip_meta.IPAddress)
+	if ipType == lib.IPTypeV6 {
+		vip.Ip6Address = &avimodels.IPAddr{Type: &ipType, Addr: &vsvip_meta.IPAddress}
+	} else {
+		vip.IPAddress = &avimodels.IPAddr{Type: &ipType, Addr: &vsvip_meta.IPAddress}
+	}
+}
+
+// vipNetworksAllowV6 returns false if the vip networks have CIDRs configured, none of which is a v6 CIDR. The vip
+// networks without CIDRs leave the ip ranges to the IPAM of the cloud.
+func vipNetworksAllowV6(vipNetworks []akov1alpha1.AviInfraSettingVipNetwork) bool {
+	var v4 bool
+	for _, vipNetwork := range vipNetworks {
+		if vipNetwork.V6Cidr != "" {
+			return true
+		}
+		v4 = v4 || vipNetwork.Cidr != ""
+	}
+	return !v4
+}
+
+func cidrToIPAddrPrefix(cidr string) *avimodels.IPAddrPrefix {
+	if cidr == "" {
+		return nil
+	}
+	ipPrefixSlice := strings.Split(cidr, "/")
+	mask, _ := strconv.Atoi(ipPrefixSlice[1])
+	ipType := lib.GetIPType(ipPrefixSlice[0])
+	return &avimodels.IPAddrPrefix{
+		IPAddr: &avimodels.IPAddr{Type: &ipType, Addr: &ipPrefixSlice[0]},
+		Mask:   proto.Int32(int32(mask)),
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
@@ -176,8 +177,8 @@ func (rest *RestOperations) vrfCU(key, vrfName string, avimodel *nodes.AviObject
 	}
 }
 
-// CheckAndPublishForRetry : Check if the error is of type 401, has string "Rest request error" or was timed out,
-// then publish the key to retry layer. These error do not depend on the objet state, hence cache refresh is not required.
+// CheckAndPublishForRetry : Check if the error is of type 401 or 429, has string "Rest request error", was timed out
+// or was not sent since the circuit breaker is open, then publish the key to retry layer. These error do not depend on the objet state, hence cache refresh is not required.
 func (rest *RestOperations) CheckAndPublishForRetry(err error, publishKey, key string, avimodel *nodes.AviObjectGraph) bool {
 	if err == nil {
 		return false
@@ -209,7 +210,15 @@ func (rest *RestOperations) CheckAndPublishForRetry(err error, publishKey, key s
 					rest.PublishKeyToSlowRetryLayer(publishKey, key)
 					return true
 				}
+			case 429:
+				utils.AviLog.Warnf("key: %s, msg: controller is rate limiting the rest requests, adding to slow retry queue", key)
+				rest.PublishKeyToSlowRetryLayer(publishKey, key)
+				return true
 			}
+		} else if webSyncErr.GetWebAPIError() == utils.ErrAviCircuitOpen {
+			utils.AviLog.Warnf("key: %s, msg: circuit breaker is open for the controller, adding to slow retry queue", key)
+			rest.PublishKeyToSlowRetryLayer(publishKey, key)
+			return true
 		}
 	}
 	if strings.Contains(err.Error(), "Rest request error") || strings.Contains(err.Error(), "timed out waiting for rest response") {
@@ -244,6 +253,8 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 			publishKey = splitKeys[1]
 		}
 	}
+	// The retry layer publishes the model of the virtualservice, which is keyed by its tenant.
+	publishKey = lib.GetModelName(namespace, publishKey)
 	// Order would be this: 1. Pools 2. PGs  3. DS. 4. SSLKeyCert 5. VS
 	if vs_cache_obj != nil {
 		var rest_ops []*utils.RestOp
@@ -502,6 +513,11 @@ func (rest *RestOperations) deleteSniVs(vsKey avicache.NamespaceName, vs_cache_o
 		rest_ops = rest.HTTPPolicyDelete(vs_cache_obj.HTTPKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(vs_cache_obj.PGKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(vs_cache_obj.PoolKeyCollection, namespace, rest_ops, key)
+		// The application profile and the PKI profile for the client certificates are not referred in the VS
+		// cache, and are deleted by their names.
+		appProfiles, pkiProfiles := rest.managedAppProfileKeys(vsKey.Name, namespace)
+		rest_ops = rest.AppProfileDelete(appProfiles, namespace, rest_ops, key)
+		rest_ops = rest.PkiProfileDelete(pkiProfiles, namespace, rest_ops, key)
 		success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, avimodel, key, false)
 		return success
 	}
@@ -518,6 +534,12 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 	var retry, fastRetry, processNextObj bool
 	if shardSize != 0 {
 		bkt := utils.Bkt(key, shardSize)
+		if lib.IsDryRun() && len(rest_ops) > 0 {
+			// In the dry-run mode the rest operations are only recorded, the cache is left untouched
+			// so that the diffs are always computed against the objects present in the Avi controller.
+			rest.RecordDryRun(rest_ops, key)
+			return true, true
+		}
 		if len(rest.aviRestPoolClient.AviClient) > 0 && len(rest_ops) > 0 {
 			utils.AviLog.Infof("key: %s, msg: processing in rest queue number: %v", key, bkt)
 			aviclient := rest.aviRestPoolClient.AviClient[bkt]
@@ -530,6 +552,9 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 				for _, rest_op := range rest_ops {
 					rest.PopulateOneCache(rest_op, aviObjKey, key)
 				}
+				if objects.SharedModelKeyLister().ClearError(key, aviObjKey.Name) {
+					status.PublishSyncStatus(key, lib.Synced, "")
+				}
 
 			} else if aviObjKey.Name == lib.DummyVSForStaleData {
 				utils.AviLog.Warnf("key: %s, msg: error in rest request %v, for %s, won't retry", key, err.Error(), lib.DummyVSForStaleData)
@@ -549,7 +574,10 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 						publishKey = splitKeys[1]
 					}
 				}
+				publishKey = lib.GetModelName(aviObjKey.Namespace, publishKey)
 
+				objects.SharedModelKeyLister().SetError(key, aviObjKey.Name, err.Error())
+				status.PublishSyncStatus(key, lib.SyncFailed, err.Error())
 				if rest.CheckAndPublishForRetry(err, publishKey, key, avimodel) {
 					return false, processNextObj
 				}
@@ -644,6 +672,10 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 		utils.AviLog.Infof("key: %s, msg: creating/updating %s cache, method: %s", key, rest_op.Model, rest_op.Method)
 		if rest_op.Model == "PKIprofile" {
 			rest.AviPkiProfileAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "HealthMonitor" {
+			rest.AviHealthMonitorAdd(rest_op, key)
+		} else if rest_op.Model == "ApplicationProfile" {
+			rest.AviAppProfileAdd(rest_op, key)
 		} else if rest_op.Model == "Pool" {
 			rest.AviPoolCacheAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VirtualService" {
@@ -668,6 +700,10 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 		utils.AviLog.Infof("key: %s, msg: deleting %s cache", key, rest_op.Model)
 		if rest_op.Model == "PKIprofile" {
 			rest.AviPkiProfileCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "HealthMonitor" {
+			rest.AviHealthMonitorCacheDel(rest_op, key)
+		} else if rest_op.Model == "ApplicationProfile" {
+			rest.AviAppProfileCacheDel(rest_op, key)
 		} else if rest_op.Model == "Pool" {
 			rest.AviPoolCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VirtualService" {
@@ -707,6 +743,7 @@ func (rest *RestOperations) PublishKeyToRetryLayer(parentVsKey string, key strin
 	bkt = 0
 	fastRetryQueue := utils.SharedWorkQueue().GetQueueByName(lib.FAST_RETRY_LAYER)
 	fastRetryQueue.Workqueue[bkt].AddRateLimited(parentVsKey)
+	utils.IncRetryPublishes(lib.FAST_RETRY_LAYER)
 	utils.AviLog.Infof("key: %s, msg: Published key with vs_key to fast path retry queue: %s", key, parentVsKey)
 }
 
@@ -715,7 +752,11 @@ func (rest *RestOperations) PublishKeyToSlowRetryLayer(parentVsKey string, key s
 	bkt = 0
 	slowRetryQueue := utils.SharedWorkQueue().GetQueueByName(lib.SLOW_RETRY_LAYER)
 	slowRetryQueue.Workqueue[bkt].AddRateLimited(parentVsKey)
+	utils.IncRetryPublishes(lib.SLOW_RETRY_LAYER)
 	utils.AviLog.Infof("key: %s, msg: Published key with vs_key to slow path retry queue: %s", key, parentVsKey)
+	if errMsg, ok := objects.SharedModelKeyLister().GetError(key); ok {
+		status.PublishSyncStatus(key, lib.SyncRetrying, errMsg)
+	}
 }
 
 func AviRestOperateWrapper(aviClient *clients.AviClient, rest_ops []*utils.RestOp) error {
@@ -778,7 +819,7 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					// PG error with pool object not found.
 					aviObjCache.AviPopulateOnePGCache(c, utils.CloudName, pgObjName)
 					// After the refresh - get the members
-					pgKey := avicache.NamespaceName{Namespace: lib.GetTenant(), Name: pgObjName}
+					pgKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: pgObjName}
 					pgCache, ok := rest.cache.PgCache.AviCacheGet(pgKey)
 					if ok {
 						pgCacheObj, _ := pgCache.(*avicache.AviPGCache)
@@ -884,6 +925,30 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					rest_op.ObjName = PKIprofile
 				}
 				rest.AviPkiProfileCacheDel(rest_op, aviObjKey, key)
+			case "HealthMonitor":
+				var HealthMonitor string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					HealthMonitor = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HealthMonitor).Name
+				case avimodels.HealthMonitor:
+					HealthMonitor = *rest_op.Obj.(avimodels.HealthMonitor).Name
+				}
+				if HealthMonitor != "" {
+					rest_op.ObjName = HealthMonitor
+				}
+				rest.AviHealthMonitorCacheDel(rest_op, key)
+			case "ApplicationProfile":
+				var ApplicationProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationProfile).Name
+				case avimodels.ApplicationProfile:
+					ApplicationProfile = *rest_op.Obj.(avimodels.ApplicationProfile).Name
+				}
+				if ApplicationProfile != "" {
+					rest_op.ObjName = ApplicationProfile
+				}
+				rest.AviAppProfileCacheDel(rest_op, key)
 			case "VirtualService":
 				rest.AviVsCacheDel(rest_op, aviObjKey, key)
 			case "VSDataScriptSet":
@@ -969,8 +1034,26 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					PKIprofile = *rest_op.Obj.(avimodels.PKIprofile).Name
 				}
 				aviObjCache.AviPopulateOnePKICache(c, utils.CloudName, PKIprofile)
+			case "HealthMonitor":
+				var HealthMonitor string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					HealthMonitor = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HealthMonitor).Name
+				case avimodels.HealthMonitor:
+					HealthMonitor = *rest_op.Obj.(avimodels.HealthMonitor).Name
+				}
+				aviObjCache.AviPopulateOneHealthMonitorCache(c, utils.CloudName, HealthMonitor)
+			case "ApplicationProfile":
+				var ApplicationProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationProfile).Name
+				case avimodels.ApplicationProfile:
+					ApplicationProfile = *rest_op.Obj.(avimodels.ApplicationProfile).Name
+				}
+				aviObjCache.AviPopulateOneAppProfileCache(c, utils.CloudName, ApplicationProfile)
 			case "VirtualService":
-				aviObjCache.AviObjOneVSCachePopulate(c, utils.CloudName, aviObjKey.Name)
+				aviObjCache.AviObjOneVSCachePopulate(c, utils.CloudName, aviObjKey.Name, aviObjKey.Namespace)
 				vsObjMeta, ok := rest.cache.VsCacheMeta.AviCacheGet(aviObjKey)
 				if !ok {
 					// Object deleted
@@ -1040,6 +1123,11 @@ func (rest *RestOperations) PoolDelete(pools_to_delete []avicache.NamespaceName,
 			if pkiProfile.Name != "" {
 				rest_ops = rest.PkiProfileDelete([]avicache.NamespaceName{pkiProfile}, namespace, rest_ops, key)
 			}
+
+			healthMonitor := pool_cache_obj.HealthMonitorCollection
+			if healthMonitor.Name != "" {
+				rest_ops = rest.HealthMonitorDelete([]avicache.NamespaceName{healthMonitor}, namespace, rest_ops, key)
+			}
 		}
 	}
 	return rest_ops
@@ -1125,6 +1213,7 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 				pool_key := avicache.NamespaceName{Namespace: namespace, Name: pool.Name}
 				found := utils.HasElem(cache_pool_nodes, pool_key)
 				utils.AviLog.Debugf("key: %s, msg: processing pool key: %v", key, pool_key)
+				var pool_healthmonitor_delete []avicache.NamespaceName
 				if found {
 					cache_pool_nodes = avicache.RemoveNamespaceName(cache_pool_nodes, pool_key)
 					utils.AviLog.Debugf("key: %s, key: the cache pool nodes are: %v", key, cache_pool_nodes)
@@ -1132,6 +1221,7 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 					if ok {
 						pool_cache_obj, _ := pool_cache.(*avicache.AviPoolCache)
 						pool_pkiprofile_delete, rest_ops = rest.PkiProfileCU(pool.PkiProfile, pool_cache_obj, namespace, rest_ops, key)
+						pool_healthmonitor_delete, rest_ops = rest.HealthMonitorCU(pool.ProbeHealthMonitor, pool_cache_obj, namespace, rest_ops, key)
 
 						// Cache found. Let's compare the checksums
 						utils.AviLog.Debugf("key: %s, msg: poolcache: %v", key, pool_cache_obj)
@@ -1149,6 +1239,7 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 				} else {
 					utils.AviLog.Debugf("key: %s, msg: pool %s not found in cache, operation: POST", key, pool.Name)
 					_, rest_ops = rest.PkiProfileCU(pool.PkiProfile, nil, namespace, rest_ops, key)
+					_, rest_ops = rest.HealthMonitorCU(pool.ProbeHealthMonitor, nil, namespace, rest_ops, key)
 					// Not found - it should be a POST call.
 					restOp := rest.AviPoolBuild(pool, nil, key)
 					if restOp != nil {
@@ -1158,12 +1249,16 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 				if len(pool_pkiprofile_delete) > 0 {
 					rest_ops = rest.PkiProfileDelete(pool_pkiprofile_delete, namespace, rest_ops, key)
 				}
+				if len(pool_healthmonitor_delete) > 0 {
+					rest_ops = rest.HealthMonitorDelete(pool_healthmonitor_delete, namespace, rest_ops, key)
+				}
 			}
 		}
 	} else {
 		// Everything is a POST call
 		for _, pool := range pool_nodes {
 			_, rest_ops = rest.PkiProfileCU(pool.PkiProfile, nil, namespace, rest_ops, key)
+			_, rest_ops = rest.HealthMonitorCU(pool.ProbeHealthMonitor, nil, namespace, rest_ops, key)
 
 			utils.AviLog.Debugf("key: %s, msg: pool cache does not exist %s, operation: POST", key, pool.Name)
 			restOp := rest.AviPoolBuild(pool, nil, key)
@@ -1198,7 +1293,7 @@ func (rest *RestOperations) SNINodeDelete(del_sni avicache.NamespaceName, namesp
 				utils.AviLog.Warnf("key: %s, msg: corrupted sni cache found, retrying in bkt: %v", key, bkt)
 				if len(rest.aviRestPoolClient.AviClient) > 0 {
 					aviclient := rest.aviRestPoolClient.AviClient[bkt]
-					aviObjCache.AviObjOneVSCachePopulate(aviclient, utils.CloudName, del_sni.Name)
+					aviObjCache.AviObjOneVSCachePopulate(aviclient, utils.CloudName, del_sni.Name, sni_key.Namespace)
 					vsObjMeta, ok := rest.cache.VsCacheMeta.AviCacheGet(sni_key)
 					if !ok {
 						// Object deleted
@@ -1225,6 +1320,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 	var sni_pgs_to_delete []avicache.NamespaceName
 	var http_policies_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
+	var app_profiles_to_delete, pki_profiles_to_delete []avicache.NamespaceName
 	if vs_cache_obj != nil {
 		sni_key := avicache.NamespaceName{Namespace: namespace, Name: sni_node.Name}
 		// Search the VS cache and obtain the UUID of this VS. Then see if this UUID is part of the SNIChildCollection or not.
@@ -1243,6 +1339,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 				sni_pools_to_delete, rest_ops = rest.PoolCU(sni_node.PoolRefs, sni_cache_obj, namespace, rest_ops, key)
 				sni_pgs_to_delete, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, sni_cache_obj, namespace, rest_ops, key)
 				http_policies_to_delete, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, sni_cache_obj, namespace, rest_ops, key)
+				app_profiles_to_delete, pki_profiles_to_delete, rest_ops = rest.AppProfileCU(sni_node.GetClientAuthAppProfile(), sni_node.Name, namespace, rest_ops, key)
 				// The checksums are different, so it should be a PUT call.
 				if sni_cache_obj.CloudConfigCksum != strconv.Itoa(int(sni_node.GetCheckSum())) {
 					restOp := rest.AviVsBuild(sni_node, utils.RestPut, sni_cache_obj, key)
@@ -1260,6 +1357,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 			_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+			_, _, rest_ops = rest.AppProfileCU(sni_node.GetClientAuthAppProfile(), sni_node.Name, namespace, rest_ops, key)
 
 			// Not found - it should be a POST call.
 			restOp := rest.AviVsBuild(sni_node, utils.RestPost, nil, key)
@@ -1271,6 +1369,8 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 		rest_ops = rest.HTTPPolicyDelete(http_policies_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(sni_pgs_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(sni_pools_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.AppProfileDelete(app_profiles_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.PkiProfileDelete(pki_profiles_to_delete, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: the SNI VSes to be deleted are: %s", key, cache_sni_nodes)
 	} else {
 		utils.AviLog.Debugf("key: %s, msg: sni child %s not found in cache and SNI parent also does not exist in cache", key, sni_node.Name)
@@ -1279,6 +1379,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 		_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+		_, _, rest_ops = rest.AppProfileCU(sni_node.GetClientAuthAppProfile(), sni_node.Name, namespace, rest_ops, key)
 
 		// Not found - it should be a POST call.
 		restOp := rest.AviVsBuild(sni_node, utils.RestPost, nil, key)
@@ -1636,23 +1737,49 @@ func (rest *RestOperations) KeyCertCU(sslkey_nodes []*nodes.AviTLSKeyCertNode, c
 
 func (rest *RestOperations) SSLKeyCertDelete(ssl_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
 	utils.AviLog.Debugf("key: %s, msg: about to delete ssl keycert %s", key, utils.Stringify(ssl_to_delete))
-	var noCARefRestOps []*utils.RestOp
+	var ssl_cache_objs []*avicache.AviSSLCache
 	for _, del_ssl := range ssl_to_delete {
 		ssl_key := avicache.NamespaceName{Namespace: namespace, Name: del_ssl.Name}
 		ssl_cache, ok := rest.cache.SSLKeyCache.AviCacheGet(ssl_key)
 		if ok {
-			ssl_cache_obj, _ := ssl_cache.(*avicache.AviSSLCache)
+			ssl_cache_objs = append(ssl_cache_objs, ssl_cache.(*avicache.AviSSLCache))
+		}
+	}
+	// Objects with a CA ref should be deleted before the CA, so a chain is deleted from the keycert to the root.
+	for len(ssl_cache_objs) > 0 {
+		referredCAs := make(map[string]bool)
+		for _, ssl_cache_obj := range ssl_cache_objs {
+			if ssl_cache_obj.HasCARef {
+				referredCAs[ssl_cache_obj.CACertUUID] = true
+			}
+		}
+		var pending []*avicache.AviSSLCache
+		var noCARefRestOps []*utils.RestOp
+		for _, ssl_cache_obj := range ssl_cache_objs {
+			if referredCAs[ssl_cache_obj.Uuid] {
+				pending = append(pending, ssl_cache_obj)
+				continue
+			}
 			restOp := rest.AviSSLKeyCertDel(ssl_cache_obj.Uuid, namespace)
-			restOp.ObjName = del_ssl.Name
-			//Objects with a CA ref should be deleted first
+			restOp.ObjName = ssl_cache_obj.Name
 			if !ssl_cache_obj.HasCARef {
 				noCARefRestOps = append(noCARefRestOps, restOp)
 			} else {
 				rest_ops = append(rest_ops, restOp)
 			}
 		}
+		rest_ops = append(rest_ops, noCARefRestOps...)
+		if len(pending) == len(ssl_cache_objs) {
+			// The CAs refer to each other, delete them as is.
+			for _, ssl_cache_obj := range pending {
+				restOp := rest.AviSSLKeyCertDel(ssl_cache_obj.Uuid, namespace)
+				restOp.ObjName = ssl_cache_obj.Name
+				rest_ops = append(rest_ops, restOp)
+			}
+			break
+		}
+		ssl_cache_objs = pending
 	}
-	rest_ops = append(rest_ops, noCARefRestOps...)
 	return rest_ops
 }
 
@@ -1702,6 +1829,140 @@ func (rest *RestOperations) PkiProfileCU(pki_node *nodes.AviPkiProfileNode, pool
 	return cache_pki_nodes, rest_ops
 }
 
+func (rest *RestOperations) HealthMonitorCU(hm_node *nodes.AviHealthMonitorNode, pool_cache_obj *avicache.AviPoolCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	// Default is POST
+	var cache_hm_nodes []avicache.NamespaceName
+	if pool_cache_obj != nil && pool_cache_obj.HealthMonitorCollection.Name != "" {
+		cache_hm_nodes = []avicache.NamespaceName{pool_cache_obj.HealthMonitorCollection}
+	}
+	if hm_node == nil {
+		return cache_hm_nodes, rest_ops
+	}
+
+	hm_key := avicache.NamespaceName{Namespace: namespace, Name: hm_node.Name}
+	cache_hm_nodes = avicache.RemoveNamespaceName(cache_hm_nodes, hm_key)
+	// The health monitor may be cached, without being referred by the pool, if the pool update failed.
+	hm_cache, ok := rest.cache.HealthMonitorCache.AviCacheGet(hm_key)
+	if ok {
+		hm_cache_obj, _ := hm_cache.(*avicache.AviHealthMonitorCache)
+		if hm_cache_obj.CloudConfigCksum == hm_node.GetCheckSum() {
+			utils.AviLog.Debugf("key: %s, msg: the checksums are same for health monitor %s, not doing anything", key, hm_cache_obj.Name)
+		} else {
+			// The checksums are different, so it should be a PUT call.
+			restOp := rest.AviHealthMonitorBuild(hm_node, hm_cache_obj)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	} else {
+		restOp := rest.AviHealthMonitorBuild(hm_node, nil)
+		if restOp != nil {
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return cache_hm_nodes, rest_ops
+}
+
+func (rest *RestOperations) HealthMonitorDelete(hmDelete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	utils.AviLog.Debugf("key: %s, msg: about to delete health monitor %s", key, utils.Stringify(hmDelete))
+	for _, delHM := range hmDelete {
+		hmKey := avicache.NamespaceName{Namespace: namespace, Name: delHM.Name}
+		hmCache, ok := rest.cache.HealthMonitorCache.AviCacheGet(hmKey)
+		if ok {
+			hmCacheObj, _ := hmCache.(*avicache.AviHealthMonitorCache)
+			restOp := rest.AviHealthMonitorDel(hmCacheObj.Uuid, namespace)
+			restOp.ObjName = delHM.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
+// AppProfileCU creates or updates the application profile with the client certificate settings of a virtualservice,
+// along with the PKI profile created from the CA Secret. The application profile and the PKI profile owned by the
+// virtualservice, which are not required anymore, are returned to be deleted after the virtualservice is updated.
+func (rest *RestOperations) AppProfileCU(appProfileNode *nodes.AviAppProfileNode, vsName, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []avicache.NamespaceName, []*utils.RestOp) {
+	cache_app_profiles, cache_pki_profiles := rest.managedAppProfileKeys(vsName, namespace)
+	if appProfileNode == nil {
+		return cache_app_profiles, cache_pki_profiles, rest_ops
+	}
+
+	if pki_node := appProfileNode.PkiProfile; pki_node != nil {
+		pki_key := avicache.NamespaceName{Namespace: namespace, Name: pki_node.Name}
+		cache_pki_profiles = avicache.RemoveNamespaceName(cache_pki_profiles, pki_key)
+		pki_cache, ok := rest.cache.PKIProfileCache.AviCacheGet(pki_key)
+		if ok {
+			pki_cache_obj, _ := pki_cache.(*avicache.AviPkiProfileCache)
+			if pki_cache_obj.CloudConfigCksum == pki_node.GetCheckSum() {
+				utils.AviLog.Debugf("key: %s, msg: the checksums are same for pki profile %s, not doing anything", key, pki_cache_obj.Name)
+			} else {
+				// The checksums are different, so it should be a PUT call.
+				restOp := rest.AviPkiProfileBuild(pki_node, pki_cache_obj)
+				if restOp != nil {
+					rest_ops = append(rest_ops, restOp)
+				}
+			}
+		} else {
+			restOp := rest.AviPkiProfileBuild(pki_node, nil)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+
+	app_profile_key := avicache.NamespaceName{Namespace: namespace, Name: appProfileNode.Name}
+	cache_app_profiles = avicache.RemoveNamespaceName(cache_app_profiles, app_profile_key)
+	app_profile_cache, ok := rest.cache.AppProfileCache.AviCacheGet(app_profile_key)
+	if ok {
+		app_profile_cache_obj, _ := app_profile_cache.(*avicache.AviAppProfileCache)
+		if app_profile_cache_obj.CloudConfigCksum == appProfileNode.GetCheckSum() {
+			utils.AviLog.Debugf("key: %s, msg: the checksums are same for application profile %s, not doing anything", key, app_profile_cache_obj.Name)
+		} else {
+			// The checksums are different, so it should be a PUT call.
+			restOp := rest.AviAppProfileBuild(appProfileNode, app_profile_cache_obj)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	} else {
+		restOp := rest.AviAppProfileBuild(appProfileNode, nil)
+		if restOp != nil {
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return cache_app_profiles, cache_pki_profiles, rest_ops
+}
+
+// managedAppProfileKeys returns the keys of the application profile and the PKI profile for the client certificates
+// of a virtualservice, which are present in the cache.
+func (rest *RestOperations) managedAppProfileKeys(vsName, namespace string) ([]avicache.NamespaceName, []avicache.NamespaceName) {
+	var appProfiles, pkiProfiles []avicache.NamespaceName
+	appProfileKey := avicache.NamespaceName{Namespace: namespace, Name: lib.GetClientAuthAppProfileName(vsName)}
+	if _, found := rest.cache.AppProfileCache.AviCacheGet(appProfileKey); found {
+		appProfiles = append(appProfiles, appProfileKey)
+	}
+	pkiProfileKey := avicache.NamespaceName{Namespace: namespace, Name: lib.GetClientAuthPKIProfileName(vsName)}
+	if _, found := rest.cache.PKIProfileCache.AviCacheGet(pkiProfileKey); found {
+		pkiProfiles = append(pkiProfiles, pkiProfileKey)
+	}
+	return appProfiles, pkiProfiles
+}
+
+func (rest *RestOperations) AppProfileDelete(appProfileDelete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	utils.AviLog.Debugf("key: %s, msg: about to delete application profile %s", key, utils.Stringify(appProfileDelete))
+	for _, delAppProfile := range appProfileDelete {
+		appProfileKey := avicache.NamespaceName{Namespace: namespace, Name: delAppProfile.Name}
+		appProfileCache, ok := rest.cache.AppProfileCache.AviCacheGet(appProfileKey)
+		if ok {
+			appProfileCacheObj, _ := appProfileCache.(*avicache.AviAppProfileCache)
+			restOp := rest.AviAppProfileDel(appProfileCacheObj.Uuid, namespace)
+			restOp.ObjName = delAppProfile.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
 func (rest *RestOperations) PkiProfileDelete(pkiProfileDelete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
 	utils.AviLog.Debugf("key: %s, msg: about to delete pki profile %s", key, utils.Stringify(pkiProfileDelete))
 	for _, delPki := range pkiProfileDelete {
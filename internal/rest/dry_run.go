@@ -0,0 +1,183 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// RecordDryRun records the rest operations along with the diff of every object against its cache entry,
+// in place of executing them on the Avi controller.
+func (rest *RestOperations) RecordDryRun(rest_ops []*utils.RestOp, key string) {
+	var records []models.DryRunRecord
+	for _, rest_op := range rest_ops {
+		desired := dryRunObject(rest_op.Obj)
+		name := rest_op.ObjName
+		if name == "" {
+			if objName, ok := desired["name"].(string); ok {
+				name = objName
+			} else {
+				// Delete calls carry only the uuid of the object in the path.
+				name = rest_op.Path[strings.LastIndex(rest_op.Path, "/")+1:]
+			}
+		}
+
+		current := rest.dryRunCacheObject(rest_op.Model, rest_op.Tenant, name)
+		var diff []models.DryRunDiff
+		switch rest_op.Method {
+		case utils.RestPost:
+			diff = dryRunDiff(nil, desired)
+		case utils.RestDelete:
+			diff = dryRunDiff(current, nil)
+		default:
+			diff = dryRunDiff(current, desired)
+		}
+
+		var object json.RawMessage
+		if rest_op.Obj != nil {
+			object, _ = json.Marshal(rest_op.Obj)
+		}
+		utils.AviLog.Infof("key: %s, msg: dry-run, skipping %s of %s %s/%s, changed fields: %d", key, rest_op.Method, rest_op.Model, rest_op.Tenant, name, len(diff))
+		records = append(records, models.DryRunRecord{
+			Method: string(rest_op.Method),
+			Model:  rest_op.Model,
+			Tenant: rest_op.Tenant,
+			Name:   name,
+			Path:   rest_op.Path,
+			Key:    key,
+			Diff:   diff,
+			Object: object,
+		})
+	}
+	models.DryRun.Record(records...)
+}
+
+// dryRunCacheObject returns the cache entry of the object as a map, nil if the object is not cached.
+func (rest *RestOperations) dryRunCacheObject(model, tenant, name string) map[string]interface{} {
+	var cache *avicache.AviCache
+	var cacheKey interface{} = avicache.NamespaceName{Namespace: tenant, Name: name}
+	switch model {
+	case "PKIprofile":
+		cache = rest.cache.PKIProfileCache
+	case "HealthMonitor":
+		cache = rest.cache.HealthMonitorCache
+	case "ApplicationProfile":
+		cache = rest.cache.AppProfileCache
+	case "Pool":
+		cache = rest.cache.PoolCache
+	case "VirtualService":
+		cache = rest.cache.VsCacheMeta
+	case "PoolGroup":
+		cache = rest.cache.PgCache
+	case "VSDataScriptSet":
+		cache = rest.cache.DSCache
+	case "HTTPPolicySet":
+		cache = rest.cache.HTTPPolicyCache
+	case "SSLKeyAndCertificate":
+		cache = rest.cache.SSLKeyCache
+	case "L4PolicySet":
+		cache = rest.cache.L4PolicyCache
+	case "VsVip":
+		cache = rest.cache.VSVIPCache
+	case "VrfContext":
+		cache = rest.cache.VrfCache
+		cacheKey = name
+	default:
+		return nil
+	}
+
+	cacheObj, ok := cache.AviCacheGet(cacheKey)
+	if !ok || cacheObj == nil {
+		return nil
+	}
+	if vsCacheObj, ok := cacheObj.(*avicache.AviVsCache); ok {
+		vsCacheObj.VSCacheLock.RLock()
+		defer vsCacheObj.VSCacheLock.RUnlock()
+	}
+	data, err := json.Marshal(cacheObj)
+	if err != nil {
+		return nil
+	}
+	current := make(map[string]interface{})
+	if err := json.Unmarshal(data, &current); err != nil {
+		return nil
+	}
+	return current
+}
+
+// dryRunObject returns the object of the rest operation as a map.
+func dryRunObject(obj interface{}) map[string]interface{} {
+	if obj == nil {
+		return nil
+	}
+	if macro, ok := obj.(utils.AviRestObjMacro); ok {
+		obj = macro.Data
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil
+	}
+	desired := make(map[string]interface{})
+	if err := json.Unmarshal(data, &desired); err != nil {
+		return nil
+	}
+	return desired
+}
+
+// dryRunDiff compares the top level fields of the current and desired objects. The cache entries use
+// the Go field names while the Avi objects use the json field names, hence the fields are matched
+// ignoring the case and underscores. When both the objects are present, only the fields known to
+// both are compared, as the cache keeps a subset of the fields of the Avi objects.
+func dryRunDiff(current, desired map[string]interface{}) []models.DryRunDiff {
+	normalize := func(obj map[string]interface{}) map[string]string {
+		fields := make(map[string]string, len(obj))
+		for field := range obj {
+			fields[strings.ToLower(strings.ReplaceAll(field, "_", ""))] = field
+		}
+		return fields
+	}
+
+	diff := []models.DryRunDiff{}
+	currentFields, desiredFields := normalize(current), normalize(desired)
+	switch {
+	case current == nil:
+		for _, field := range desiredFields {
+			diff = append(diff, models.DryRunDiff{Field: field, Desired: desired[field]})
+		}
+	case desired == nil:
+		for _, field := range currentFields {
+			diff = append(diff, models.DryRunDiff{Field: field, Current: current[field]})
+		}
+	default:
+		for normalized, field := range desiredFields {
+			currentField, ok := currentFields[normalized]
+			if !ok || reflect.DeepEqual(current[currentField], desired[field]) {
+				continue
+			}
+			diff = append(diff, models.DryRunDiff{Field: field, Current: current[currentField], Desired: desired[field]})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Field < diff[j].Field
+	})
+	return diff
+}
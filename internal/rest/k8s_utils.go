@@ -142,12 +142,38 @@ func (rest *RestOperations) SyncObjectStatuses() {
 			status.UpdateSvcApiGatewayStatusAddress(allGatewayUpdateOptions, true)
 			status.UpdateL4LBStatus(allServiceLBUpdateOptions, true)
 		}
+		if lib.UseGatewayAPI() {
+			status.UpdateGatewayAPIGatewayStatusAddress(allGatewayUpdateOptions, true)
+		}
 		status.UpdateRouteIngressStatus(allIngressUpdateOptions, true)
 		if !lib.GetLayer7Only() {
 			status.UpdateL4LBStatus(allServiceLBUpdateOptions, true)
 		}
 	}
 
+	if lib.IsDNSEndpointPublishEnabled() {
+		var allDNSEndpointUpdateOptions []status.UpdateOptions
+		for _, vsvipKey := range rest.cache.VSVIPCache.AviGetAllKeys() {
+			vsvipCache, ok := rest.cache.VSVIPCache.AviCacheGet(vsvipKey)
+			if !ok {
+				continue
+			}
+			vsvipCacheObj, found := vsvipCache.(*avicache.AviVSVIPCache)
+			if !found {
+				continue
+			}
+			allDNSEndpointUpdateOptions = append(allDNSEndpointUpdateOptions,
+				status.UpdateOptions{
+					VSName: vsvipCacheObj.Name,
+					Tenant: vsvipCacheObj.Tenant,
+					FQDNs:  vsvipCacheObj.FQDNs,
+					Vip:    getDNSAddrsFromVsVipCache(vsvipCacheObj),
+					Key:    lib.SyncStatusKey,
+				})
+		}
+		status.SyncDNSEndpoints(allDNSEndpointUpdateOptions)
+	}
+
 	utils.AviLog.Infof("Status syncing completed")
 	lib.AKOControlConfig().PodEventf(v1.EventTypeNormal, lib.StatusSync, "Status syncing completed")
 
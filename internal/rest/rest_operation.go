@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
@@ -229,7 +230,7 @@ func removeObjRefFromRestOps(restOps []*utils.RestOp, objName, objType string) b
 
 func isErrorRetryable(statusCode int, errMsg string) bool {
 	// List of status codes for which we support retry
-	if (statusCode >= 500 && statusCode < 599) || statusCode == 404 || statusCode == 401 || statusCode == 408 || statusCode == 409 {
+	if (statusCode >= 500 && statusCode < 599) || statusCode == 404 || statusCode == 401 || statusCode == 408 || statusCode == 409 || statusCode == 429 {
 		return true
 	}
 	if statusCode == 400 && strings.Contains(errMsg, lib.NoFreeIPError) {
@@ -253,22 +254,26 @@ func AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp) error {
 			SetVersion := session.SetVersion(op.Version)
 			SetVersion(c.AviSession)
 		}
-		switch op.Method {
-		case utils.RestPost:
-			op.Err = c.AviSession.Post(op.Path, op.Obj, &op.Response)
-		case utils.RestPut:
-			op.Err = c.AviSession.Put(op.Path, op.Obj, &op.Response)
-		case utils.RestGet:
-			op.Err = c.AviSession.Get(op.Path, &op.Response)
-		case utils.RestPatch:
-			op.Err = c.AviSession.Patch(op.Path, op.Obj, op.PatchOp,
-				&op.Response)
-		case utils.RestDelete:
-			op.Err = c.AviSession.Delete(op.Path)
-		default:
-			utils.AviLog.Errorf("Unknown RestOp %v", op.Method)
-			op.Err = fmt.Errorf("Unknown RestOp %v", op.Method)
-		}
+		startTime := time.Now()
+		op.Err = utils.SharedAviRestLimiter().Do(func() error {
+			switch op.Method {
+			case utils.RestPost:
+				return c.AviSession.Post(op.Path, op.Obj, &op.Response)
+			case utils.RestPut:
+				return c.AviSession.Put(op.Path, op.Obj, &op.Response)
+			case utils.RestGet:
+				return c.AviSession.Get(op.Path, &op.Response)
+			case utils.RestPatch:
+				return c.AviSession.Patch(op.Path, op.Obj, op.PatchOp,
+					&op.Response)
+			case utils.RestDelete:
+				return c.AviSession.Delete(op.Path)
+			default:
+				utils.AviLog.Errorf("Unknown RestOp %v", op.Method)
+				return fmt.Errorf("Unknown RestOp %v", op.Method)
+			}
+		})
+		observeAviRestOp(op, time.Since(startTime))
 		if op.Err != nil {
 			utils.AviLog.Warnf(`RestOp method %v path %v tenant %v Obj %s returned err %s with response %s`,
 				op.Method, op.Path, op.Tenant, utils.Stringify(op.Obj), utils.Stringify(op.Err), utils.Stringify(op.Response))
@@ -302,3 +307,14 @@ func AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp) error {
 	}
 	return nil
 }
+
+func observeAviRestOp(op *utils.RestOp, duration time.Duration) {
+	statusCode := 0
+	if op.Err != nil {
+		statusCode = -1
+		if aviErr, ok := op.Err.(session.AviError); ok {
+			statusCode = aviErr.HttpStatusCode
+		}
+	}
+	utils.ObserveAviRestCall(op.Model, string(op.Method), statusCode, duration)
+}
@@ -52,6 +52,12 @@ func (rest *RestOperations) AviSSLBuild(ssl_node *nodes.AviTLSKeyCertNode, cache
 
 	sslkeycert.Markers = lib.GetAllMarkers(ssl_node.AviMarkers)
 
+	if certType == lib.CertTypeVS {
+		enableOcspStapling := ssl_node.OCSPConfig != nil
+		sslkeycert.EnableOcspStapling = &enableOcspStapling
+		sslkeycert.OcspConfig = ssl_node.OCSPConfig
+	}
+
 	if ssl_node.CACert != "" {
 		cacertRef := "/api/sslkeyandcertificate/?name=" + ssl_node.CACert
 		caName := ssl_node.CACert
@@ -154,19 +160,29 @@ func (rest *RestOperations) AviSSLKeyCertAdd(rest_op *utils.RestOp, vsKey avicac
 		}
 		cert = *SSLKeyAndCertificate.Certificate.Certificate
 		hasCA := false
+		var cacertUUID string
 		if len(SSLKeyAndCertificate.CaCerts) > 0 {
 			if SSLKeyAndCertificate.CaCerts[0].CaRef != nil {
 				cacert = strings.TrimPrefix(*SSLKeyAndCertificate.CaCerts[0].CaRef, "/api/sslkeyandcertificate/?name=")
 				hasCA = true
+				// The CA cert is created ahead of the certs referring to it.
+				if cacertCache, found := rest.cache.SSLKeyCache.AviCacheGet(avicache.NamespaceName{Namespace: rest_op.Tenant, Name: cacert}); found {
+					cacertUUID = cacertCache.(*avicache.AviSSLCache).Uuid
+				}
 			}
 		}
 		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		checksum := lib.SSLKeyCertChecksum(name, cert, cacert, emptyIngestionMarkers, SSLKeyAndCertificate.Markers, true)
+		if SSLKeyAndCertificate.EnableOcspStapling != nil && *SSLKeyAndCertificate.EnableOcspStapling {
+			checksum += lib.OCSPConfigChecksum(SSLKeyAndCertificate.OcspConfig)
+		}
 		ssl_cache_obj := avicache.AviSSLCache{
 			Name:             name,
 			Tenant:           rest_op.Tenant,
 			Uuid:             uuid,
-			CloudConfigCksum: lib.SSLKeyCertChecksum(name, cert, cacert, emptyIngestionMarkers, SSLKeyAndCertificate.Markers, true),
+			CloudConfigCksum: checksum,
 			HasCARef:         hasCA,
+			CACertUUID:       cacertUUID,
 		}
 
 		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
@@ -234,6 +250,12 @@ func (rest *RestOperations) AviPkiProfileBuild(pki_node *nodes.AviPkiProfileNode
 		}),
 	}
 
+	if pki_node.CRL != "" {
+		crl := pki_node.CRL
+		crlcheck = true
+		pkiobject.Crls = []*avimodels.CRL{{Body: &crl}}
+	}
+
 	pkiobject.Markers = lib.GetAllMarkers(pki_node.AviMarkers)
 
 	var path string
@@ -300,22 +322,18 @@ func (rest *RestOperations) AviPkiProfileAdd(rest_op *utils.RestOp, poolKey avic
 			continue
 		}
 
-		var pkiCertificate string
-		var pkiMarkers []*avimodels.RoleFilterMatchLabel
+		var pkiObj avimodels.PKIprofile
 		switch rest_op.Obj.(type) {
 		case utils.AviRestObjMacro:
-			pkiCertificate = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.PKIprofile).CaCerts[0].Certificate
-			pkiMarkers = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.PKIprofile).Markers
+			pkiObj = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.PKIprofile)
 		case avimodels.PKIprofile:
-			pkiCertificate = *rest_op.Obj.(avimodels.PKIprofile).CaCerts[0].Certificate
-			pkiMarkers = rest_op.Obj.(avimodels.PKIprofile).Markers
+			pkiObj = rest_op.Obj.(avimodels.PKIprofile)
 		}
-		emptyIngestionMarkers := utils.AviObjectMarkers{}
 		pki_cache_obj := avicache.AviPkiProfileCache{
 			Name:             name,
 			Tenant:           rest_op.Tenant,
 			Uuid:             uuid,
-			CloudConfigCksum: lib.SSLKeyCertChecksum(name, pkiCertificate, "", emptyIngestionMarkers, pkiMarkers, true),
+			CloudConfigCksum: avicache.PKIProfileChecksum(&pkiObj),
 		}
 
 		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
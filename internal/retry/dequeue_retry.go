@@ -14,24 +14,23 @@
 package retry
 
 import (
-	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
-func DequeueFastRetry(vsKey string) {
-	utils.AviLog.Infof("Retrieved the key for fast retry: %s", vsKey)
+// DequeueFastRetry publishes the model of the virtualservice, tenant/vsName, to the rest layer again.
+func DequeueFastRetry(modelName string) {
+	utils.AviLog.Infof("Retrieved the key for fast retry: %s", modelName)
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
-	modelName := lib.GetTenant() + "/" + vsKey
 	nodes.PublishKeyToRestLayer(modelName, "retry", sharedQueue)
 
 }
 
-func DequeueSlowRetry(vsKey string) {
-	utils.AviLog.Infof("Retrieved the key for slow retry: %s", vsKey)
+// DequeueSlowRetry publishes the model of the virtualservice, tenant/vsName, to the rest layer again.
+func DequeueSlowRetry(modelName string) {
+	utils.AviLog.Infof("Retrieved the key for slow retry: %s", modelName)
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
-	modelName := lib.GetTenant() + "/" + vsKey
 	nodes.PublishKeyToRestLayer(modelName, "retry", sharedQueue)
 
 }
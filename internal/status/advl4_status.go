@@ -123,6 +123,8 @@ func DeleteGatewayStatusAddress(svcMetadataObj lib.ServiceMetadataObj, key strin
 		UpdateGatewayStatusObject(key, gw, gwStatus)
 	} else if lib.UseServicesAPI() {
 		return DeleteSvcApiStatus(key, svcMetadataObj)
+	} else if lib.UseGatewayAPI() {
+		return DeleteGatewayAPIGatewayStatusAddress(key, svcMetadataObj)
 	}
 	utils.AviLog.Infof("key: %s, msg: Successfully reset the address status of gateway: %s", key, svcMetadataObj.Gateway)
 	return nil
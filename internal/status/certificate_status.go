@@ -0,0 +1,82 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// PublishCertificateStatus reports a certificate of the model, expiring or not rotated, to the objects processed
+// into the model which serve one of the hosts of the certificate. reason is one of lib.CertificateExpiring,
+// lib.CertificateExpired and lib.SecretNotRotated.
+func PublishCertificateStatus(modelName string, hosts []string, reason, message string) {
+	for _, objKey := range objects.SharedModelKeyLister().GetKeys(modelName) {
+		objType, namespace, name := lib.ExtractTypeNameNamespace(objKey)
+		obj, err := getSyncStatusObject(objType, namespace, name)
+		if err != nil || !servesHosts(obj, hosts) {
+			continue
+		}
+		statusOption := StatusOptions{
+			ObjType: lib.CertificateStatus,
+			Op:      reason,
+			ObjName: objKey,
+			Key:     modelName,
+			Message: message,
+		}
+		PublishToStatusQueue(objKey, statusOption)
+	}
+}
+
+// UpdateCertificateStatus raises a Warning Event with the certificate alert of the model on the object objKey.
+func UpdateCertificateStatus(modelName, objKey, reason, message string) {
+	objType, namespace, name := lib.ExtractTypeNameNamespace(objKey)
+	obj, err := getSyncStatusObject(objType, namespace, name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: unable to get %s for certificate status update: %v", modelName, objKey, err)
+		return
+	}
+	lib.AKOControlConfig().EventRecorder().Event(obj, corev1.EventTypeWarning, reason, message)
+}
+
+// servesHosts returns true if the Ingress or the Route serves one of the hosts. The other objects are not host
+// specific, and serve all the hosts of their model.
+func servesHosts(obj runtime.Object, hosts []string) bool {
+	switch o := obj.(type) {
+	case *networkingv1.Ingress:
+		for _, rule := range o.Spec.Rules {
+			if utils.HasElem(hosts, rule.Host) {
+				return true
+			}
+		}
+		for _, tls := range o.Spec.TLS {
+			for _, host := range tls.Hosts {
+				if utils.HasElem(hosts, host) {
+					return true
+				}
+			}
+		}
+		return false
+	case *routev1.Route:
+		return utils.HasElem(hosts, o.Spec.Host)
+	}
+	return true
+}
@@ -46,7 +46,7 @@ func UpdateHostRuleStatus(key string, hr *akov1alpha1.HostRule, updateStatus Upd
 	}
 
 	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1alpha1.HostRuleStatus(updateStatus),
+		"status": akov1alpha1.HostRuleStatus{Status: updateStatus.Status, Error: updateStatus.Error},
 	})
 
 	_, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().HostRules(hr.Namespace).Patch(context.TODO(), hr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
@@ -66,6 +66,23 @@ func UpdateHostRuleStatus(key string, hr *akov1alpha1.HostRule, updateStatus Upd
 	utils.AviLog.Infof("key: %s, msg: Successfully updated the hostrule %s/%s status %+v", key, hr.Namespace, hr.Name, utils.Stringify(updateStatus))
 }
 
+// UpdateHostRuleGSLBStatus updates the status of the GSLBService maintained by AKO for the HostRule, a nil
+// gslbStatus removes it from the HostRule status.
+func UpdateHostRuleGSLBStatus(key string, hr *akov1alpha1.HostRule, gslbStatus *akov1alpha1.HostRuleGSLBStatus) {
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"gslb": gslbStatus,
+		},
+	})
+
+	_, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().HostRules(hr.Namespace).Patch(context.TODO(), hr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Errorf("key: %s, msg: there was an error in updating the hostrule gslb status: %+v", key, err)
+		return
+	}
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the hostrule %s/%s gslb status %s", key, hr.Namespace, hr.Name, utils.Stringify(gslbStatus))
+}
+
 // HostRuleEventBroadcast is responsible from broadcasting HostRule specific events when the VS Cache is Added/Updated/Deleted.
 func HostRuleEventBroadcast(vsName string, vsCacheMetadataOld, vsMetadataNew lib.CRDMetadata) {
 	if vsCacheMetadataOld.Value != vsMetadataNew.Value {
@@ -115,8 +132,24 @@ func UpdateHTTPRuleStatus(key string, rr *akov1alpha1.HTTPRule, updateStatus Upd
 		}
 	}
 
+	httpRuleStatus := akov1alpha1.HTTPRuleStatus{
+		Status: updateStatus.Status,
+		Error:  updateStatus.Error,
+	}
+	if updateStatus.Status == lib.StatusAccepted {
+		httpRuleStatus.PathActions = GetHTTPRulePathActions(rr)
+	}
+	// error and pathActions are set to null explicitly when empty, for the merge patch to reset them.
+	statusMap := make(map[string]interface{})
+	statusJSON, _ := json.Marshal(httpRuleStatus)
+	json.Unmarshal(statusJSON, &statusMap)
+	for _, field := range []string{"error", "pathActions"} {
+		if _, ok := statusMap[field]; !ok {
+			statusMap[field] = nil
+		}
+	}
 	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1alpha1.HTTPRuleStatus(updateStatus),
+		"status": statusMap,
 	})
 
 	_, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().HTTPRules(rr.Namespace).Patch(context.TODO(), rr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
@@ -136,6 +169,36 @@ func UpdateHTTPRuleStatus(key string, rr *akov1alpha1.HTTPRule, updateStatus Upd
 	utils.AviLog.Infof("key: %s, msg: Successfully updated the httprule %s/%s status %+v", key, rr.Namespace, rr.Name, utils.Stringify(updateStatus))
 }
 
+// GetHTTPRulePathActions returns the header, rewrite, redirect and backend actions configured on the target paths of the HTTPRule.
+func GetHTTPRulePathActions(rr *akov1alpha1.HTTPRule) []akov1alpha1.HTTPRulePathActionStatus {
+	var pathActions []akov1alpha1.HTTPRulePathActionStatus
+	for _, path := range rr.Spec.Paths {
+		var actions []string
+		if len(path.RequestHeaders) > 0 {
+			actions = append(actions, "RequestHeaders")
+		}
+		if len(path.ResponseHeaders) > 0 {
+			actions = append(actions, "ResponseHeaders")
+		}
+		if path.RewritePrefix != "" {
+			actions = append(actions, "RewritePrefix")
+		}
+		if path.Redirect != nil {
+			actions = append(actions, "Redirect")
+		}
+		if len(path.Backends) > 0 {
+			actions = append(actions, "Backends")
+		}
+		if len(actions) > 0 {
+			pathActions = append(pathActions, akov1alpha1.HTTPRulePathActionStatus{
+				Target:  path.Target,
+				Actions: actions,
+			})
+		}
+	}
+	return pathActions
+}
+
 // HttpRuleEventBroadcast is responsible from broadcasting HttpRule specific events when the Pool Cache is Added/Updated/Deleted.
 func HttpRuleEventBroadcast(poolName string, poolCacheMetadataOld, vsMetadataNew lib.CRDMetadata) {
 	if poolCacheMetadataOld.Value != vsMetadataNew.Value {
@@ -205,3 +268,35 @@ func UpdateAviInfraSettingStatus(key string, infraSetting *akov1alpha1.AviInfraS
 
 	utils.AviLog.Infof("key: %s, msg: Successfully updated the aviinfrasetting %s status %+v", key, infraSetting.Name, utils.Stringify(updateStatus))
 }
+
+// UpdateL4RuleStatus L4Rule status updates
+func UpdateL4RuleStatus(key string, l4Rule *akov1alpha1.L4Rule, updateStatus UpdateCRDStatusOptions, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 3 {
+			utils.AviLog.Errorf("key: %s, msg: UpdateL4RuleStatus retried 3 times, aborting", key)
+			return
+		}
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": akov1alpha1.L4RuleStatus(updateStatus),
+	})
+
+	_, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().L4Rules(l4Rule.Namespace).Patch(context.TODO(), l4Rule.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the l4rule status: %+v", key, retry, err)
+		updatedL4Rule, err := lib.AKOControlConfig().CRDInformers().L4RuleInformer.Lister().L4Rules(l4Rule.Namespace).Get(l4Rule.Name)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: l4rule not found %v", key, err)
+			if strings.Contains(err.Error(), utils.K8S_ETIMEDOUT) {
+				UpdateL4RuleStatus(key, updatedL4Rule, updateStatus, retry+1)
+			}
+			return
+		}
+		UpdateL4RuleStatus(key, updatedL4Rule, updateStatus, retry+1)
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the l4rule %s/%s status %+v", key, l4Rule.Namespace, l4Rule.Name, utils.Stringify(updateStatus))
+}
@@ -0,0 +1,245 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	dnsRecordTypeA    = "A"
+	dnsRecordTypeAAAA = "AAAA"
+	// dnsEndpointTenantAnnotation records the tenant of the vsvip on its DNSEndpoint.
+	dnsEndpointTenantAnnotation = "ako.vmware.com/tenant"
+)
+
+var dnsEndpointNameInvalidChars = regexp.MustCompile(`[^a-z0-9.-]`)
+
+// PublishDNSEndpoint publishes the fqdns of a vsvip along with its vips, so that they are written to the DNSEndpoint
+// of the vsvip. The DNSEndpoint is deleted once the vsvip has no fqdns or no vips.
+func PublishDNSEndpoint(key, tenant, vsvipName string, fqdns, vips []string) {
+	if !lib.IsDNSEndpointPublishEnabled() {
+		return
+	}
+	statusOption := StatusOptions{
+		ObjType: lib.DNSEndpointStatus,
+		Op:      lib.UpdateStatus,
+		ObjName: vsvipName,
+		Key:     key,
+		Options: &UpdateOptions{
+			Vip:    vips,
+			FQDNs:  fqdns,
+			Tenant: tenant,
+			Key:    key,
+		},
+	}
+	// the vsvips of different tenants can share the name.
+	PublishToStatusQueue(tenant+"/"+vsvipName, statusOption)
+}
+
+// PublishDNSEndpointDelete publishes the deletion of the DNSEndpoint of a deleted vsvip.
+func PublishDNSEndpointDelete(key, tenant, vsvipName string) {
+	if !lib.IsDNSEndpointPublishEnabled() {
+		return
+	}
+	statusOption := StatusOptions{
+		ObjType: lib.DNSEndpointStatus,
+		Op:      lib.DeleteStatus,
+		ObjName: vsvipName,
+		Key:     key,
+		Options: &UpdateOptions{Tenant: tenant, Key: key},
+	}
+	PublishToStatusQueue(tenant+"/"+vsvipName, statusOption)
+}
+
+// UpdateDNSEndpoint creates or updates the DNSEndpoint of a vsvip in the AKO namespace, with an A and an AAAA record
+// for every fqdn of the vsvip, pointing to its IPv4 and IPv6 vips respectively.
+func UpdateDNSEndpoint(key, tenant, vsvipName string, fqdns, vips []string) {
+	endpoints := buildDNSEndpoints(fqdns, vips)
+	if len(endpoints) == 0 {
+		DeleteDNSEndpoint(key, tenant, vsvipName)
+		return
+	}
+	dnsEndpointClient := getDNSEndpointClient()
+	if dnsEndpointClient == nil {
+		return
+	}
+
+	name := dnsEndpointName(tenant, vsvipName)
+	dnsEndpoint, err := dnsEndpointClient.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			utils.AviLog.Warnf("key: %s, msg: unable to get DNSEndpoint %s: %v", key, name, err)
+			return
+		}
+		dnsEndpoint = &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": lib.DNSEndpointGVR.GroupVersion().String(),
+				"kind":       "DNSEndpoint",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": utils.GetAKONamespace(),
+					"labels": map[string]interface{}{
+						lib.ClusterNameLabelKey: lib.GetClusterName(),
+					},
+					"annotations": map[string]interface{}{
+						dnsEndpointTenantAnnotation: tenant,
+					},
+				},
+				"spec": map[string]interface{}{
+					"endpoints": endpoints,
+				},
+			},
+		}
+		if _, err = dnsEndpointClient.Create(context.TODO(), dnsEndpoint, metav1.CreateOptions{}); err != nil {
+			utils.AviLog.Warnf("key: %s, msg: unable to create DNSEndpoint %s: %v", key, name, err)
+			return
+		}
+		utils.AviLog.Infof("key: %s, msg: created DNSEndpoint %s for fqdns %v", key, name, fqdns)
+		return
+	}
+
+	if currentEndpoints, _, _ := unstructured.NestedSlice(dnsEndpoint.Object, "spec", "endpoints"); reflect.DeepEqual(currentEndpoints, endpoints) {
+		utils.AviLog.Debugf("key: %s, msg: DNSEndpoint %s is up to date", key, name)
+		return
+	}
+	if err = unstructured.SetNestedSlice(dnsEndpoint.Object, endpoints, "spec", "endpoints"); err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to set the endpoints of DNSEndpoint %s: %v", key, name, err)
+		return
+	}
+	if _, err = dnsEndpointClient.Update(context.TODO(), dnsEndpoint, metav1.UpdateOptions{}); err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to update DNSEndpoint %s: %v", key, name, err)
+		return
+	}
+	utils.AviLog.Infof("key: %s, msg: updated DNSEndpoint %s for fqdns %v", key, name, fqdns)
+}
+
+// DeleteDNSEndpoint deletes the DNSEndpoint of a vsvip, which removes its records from the DNS provider.
+func DeleteDNSEndpoint(key, tenant, vsvipName string) {
+	dnsEndpointClient := getDNSEndpointClient()
+	if dnsEndpointClient == nil {
+		return
+	}
+	name := dnsEndpointName(tenant, vsvipName)
+	if err := dnsEndpointClient.Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			utils.AviLog.Warnf("key: %s, msg: unable to delete DNSEndpoint %s: %v", key, name, err)
+		}
+		return
+	}
+	utils.AviLog.Infof("key: %s, msg: deleted DNSEndpoint %s", key, name)
+}
+
+// SyncDNSEndpoints brings the DNSEndpoints in sync with the vsvips in the cache on bootup. options carry the vsvip
+// name in VSName, along with its tenant, fqdns and vips. The DNSEndpoints of this cluster, for which no vsvip exists,
+// are deleted.
+func SyncDNSEndpoints(options []UpdateOptions) {
+	if !lib.IsDNSEndpointPublishEnabled() {
+		return
+	}
+	dnsEndpointClient := getDNSEndpointClient()
+	if dnsEndpointClient == nil {
+		return
+	}
+
+	validNames := make(map[string]struct{})
+	for _, option := range options {
+		UpdateDNSEndpoint(lib.SyncStatusKey, option.Tenant, option.VSName, option.FQDNs, option.Vip)
+		if len(buildDNSEndpoints(option.FQDNs, option.Vip)) > 0 {
+			validNames[dnsEndpointName(option.Tenant, option.VSName)] = struct{}{}
+		}
+	}
+
+	dnsEndpoints, err := dnsEndpointClient.List(context.TODO(), metav1.ListOptions{
+		LabelSelector: lib.ClusterNameLabelKey + "=" + lib.GetClusterName(),
+	})
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list DNSEndpoints: %v", lib.SyncStatusKey, err)
+		return
+	}
+	for _, dnsEndpoint := range dnsEndpoints.Items {
+		if _, found := validNames[dnsEndpoint.GetName()]; found {
+			continue
+		}
+		if err := dnsEndpointClient.Delete(context.TODO(), dnsEndpoint.GetName(), metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			utils.AviLog.Warnf("key: %s, msg: unable to delete stale DNSEndpoint %s: %v", lib.SyncStatusKey, dnsEndpoint.GetName(), err)
+			continue
+		}
+		utils.AviLog.Infof("key: %s, msg: deleted stale DNSEndpoint %s", lib.SyncStatusKey, dnsEndpoint.GetName())
+	}
+}
+
+func getDNSEndpointClient() dynamic.ResourceInterface {
+	dynamicClient := lib.GetDynamicClientSet()
+	if dynamicClient == nil {
+		return nil
+	}
+	return dynamicClient.Resource(lib.DNSEndpointGVR).Namespace(utils.GetAKONamespace())
+}
+
+// dnsEndpointName returns the name of the DNSEndpoint of a vsvip, which is a valid Kubernetes object name. The name
+// is prefixed with the tenant of the vsvip, since the vsvips of different tenants can share the name.
+func dnsEndpointName(tenant, vsvipName string) string {
+	name := strings.ToLower(tenant + "--" + vsvipName)
+	name = dnsEndpointNameInvalidChars.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-.")
+}
+
+// buildDNSEndpoints returns the endpoints of the DNSEndpoint spec, sorted by the fqdn.
+func buildDNSEndpoints(fqdns, vips []string) []interface{} {
+	targets := make(map[string][]interface{})
+	for _, vip := range vips {
+		recordType := dnsRecordTypeA
+		if lib.GetIPType(vip) == lib.IPTypeV6 {
+			recordType = dnsRecordTypeAAAA
+		}
+		targets[recordType] = append(targets[recordType], vip)
+	}
+
+	sortedFQDNs := make([]string, 0, len(fqdns))
+	for _, fqdn := range fqdns {
+		if fqdn != "" && !utils.HasElem(sortedFQDNs, fqdn) {
+			sortedFQDNs = append(sortedFQDNs, fqdn)
+		}
+	}
+	sort.Strings(sortedFQDNs)
+
+	var endpoints []interface{}
+	for _, fqdn := range sortedFQDNs {
+		for _, recordType := range []string{dnsRecordTypeA, dnsRecordTypeAAAA} {
+			if len(targets[recordType]) == 0 {
+				continue
+			}
+			endpoints = append(endpoints, map[string]interface{}{
+				"dnsName":    fqdn,
+				"recordType": recordType,
+				"targets":    targets[recordType],
+			})
+		}
+	}
+	return endpoints
+}
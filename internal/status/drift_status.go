@@ -0,0 +1,105 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// PublishDriftStatus reports an Avi object of the model, modified outside of AKO, to all the objects which were
+// processed into the model. reason is one of lib.AviObjectDrifted and lib.AviObjectDriftReverted.
+func PublishDriftStatus(modelName, reason, message string) {
+	for _, objKey := range objects.SharedModelKeyLister().GetKeys(modelName) {
+		statusOption := StatusOptions{
+			ObjType: lib.DriftStatus,
+			Op:      reason,
+			ObjName: objKey,
+			Key:     modelName,
+			Message: message,
+		}
+		PublishToStatusQueue(objKey, statusOption)
+	}
+}
+
+// UpdateDriftStatus raises a Warning Event with the drift of an Avi object of the model on the object objKey.
+func UpdateDriftStatus(modelName, objKey, reason, message string) {
+	objType, namespace, name := lib.ExtractTypeNameNamespace(objKey)
+	obj, err := getSyncStatusObject(objType, namespace, name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: unable to get %s for drift status update: %v", modelName, objKey, err)
+		return
+	}
+	lib.AKOControlConfig().EventRecorder().Event(obj, corev1.EventTypeWarning, reason, message)
+}
+
+// IsDriftRevertSkipped returns true if the drifted Avi object of the model is opted out of reverting, using the
+// skipdriftrevert.ako.vmware.com/enabled annotation. An Avi object built from specific objects of the model, as
+// identified by its markers, is skipped if any of those objects opts out. An Avi object shared by all the objects of
+// the model, like the parent virtualservice, is skipped only if all of them opt out.
+func IsDriftRevertSkipped(modelName string, markers *utils.AviObjectMarkers) bool {
+	var owners, allObjects []runtime.Object
+	for _, objKey := range objects.SharedModelKeyLister().GetKeys(modelName) {
+		objType, namespace, name := lib.ExtractTypeNameNamespace(objKey)
+		obj, err := getSyncStatusObject(objType, namespace, name)
+		if err != nil {
+			continue
+		}
+		allObjects = append(allObjects, obj)
+		if markers != nil && ownsAviObject(objType, namespace, name, markers) {
+			owners = append(owners, obj)
+		}
+	}
+	if len(owners) > 0 {
+		for _, obj := range owners {
+			if isDriftRevertSkipped(obj) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, obj := range allObjects {
+		if !isDriftRevertSkipped(obj) {
+			return false
+		}
+	}
+	return len(allObjects) > 0
+}
+
+// ownsAviObject checks whether the Avi object with the markers is built from the object.
+func ownsAviObject(objType, namespace, name string, markers *utils.AviObjectMarkers) bool {
+	if markers.Namespace != "" && markers.Namespace != namespace {
+		return false
+	}
+	switch objType {
+	case utils.Ingress, utils.OshiftRoute:
+		return utils.HasElem(markers.IngressName, name)
+	case utils.L4LBService:
+		return markers.GatewayName == "" && markers.ServiceName == name
+	case lib.Gateway:
+		return markers.GatewayName == name
+	}
+	return false
+}
+
+func isDriftRevertSkipped(obj runtime.Object) bool {
+	accessor, err := meta.Accessor(obj)
+	return err == nil && accessor.GetAnnotations()[lib.SkipDriftRevertAnnotation] == "true"
+}
@@ -0,0 +1,434 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// The status of a gateway.networking.k8s.io Gateway is updated from two places. The rest layer
+// updates the addresses and the Ready condition once the VS is realised in Avi, while the graph
+// layer updates the listener statuses and the Scheduled condition, since those are computed while
+// translating the Gateway. The route statuses are computed by the graph layer per parent Gateway.
+
+func UpdateGatewayAPIGatewayStatusAddress(options []UpdateOptions, bulk bool) {
+	gatewaysToUpdate, updateGWOptions := parseOptionsFromMetadata(options, bulk)
+
+	gatewayMap := getGatewayAPIGateways(gatewaysToUpdate, bulk)
+	skipDelete := map[string]bool{}
+	for _, option := range updateGWOptions {
+		gw := gatewayMap[option.IngSvc]
+		if gw == nil || len(option.Vip) == 0 {
+			continue
+		}
+		gwStatus := gw.Status.DeepCopy()
+		addressType := gwapiv1alpha2.IPAddressType
+		gwStatus.Addresses = nil
+		for _, vip := range option.Vip {
+			gwStatus.Addresses = append(gwStatus.Addresses, gwapiv1alpha2.GatewayAddress{
+				Type:  &addressType,
+				Value: vip,
+			})
+		}
+		setGatewayAPICondition(&gwStatus.Conditions, metav1.Condition{
+			Type:               string(gwapiv1alpha2.GatewayConditionReady),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(gwapiv1alpha2.GatewayReasonReady),
+			Message:            "Virtual service configured in Avi",
+			ObservedGeneration: gw.Generation,
+		})
+		UpdateGatewayAPIGatewayStatusObject(option.Key, gw, gwStatus)
+		skipDelete[option.IngSvc] = true
+	}
+
+	// reset the addresses of the Gateways that do not have a corresponding VS in cache
+	if bulk {
+		for gwNSName := range gatewayMap {
+			if skipDelete[gwNSName] {
+				continue
+			}
+			DeleteGatewayAPIGatewayStatusAddress(lib.SyncStatusKey, lib.ServiceMetadataObj{
+				Gateway: gwNSName,
+			})
+		}
+	}
+}
+
+// getGatewayAPIGateways returns a map: {"namespace/name": gatewayObj...}, if bulk is set to true,
+// all the Gateways of the GatewayClasses with AKO as the controller are returned.
+func getGatewayAPIGateways(gwNSNames []string, bulk bool) map[string]*gwapiv1alpha2.Gateway {
+	gwMap := make(map[string]*gwapiv1alpha2.Gateway)
+	informers := lib.AKOControlConfig().GatewayAPIInformers()
+	if bulk {
+		aviGWClasses := make(map[string]bool)
+		gwClassList, err := informers.GatewayClassInformer.Lister().List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Warnf("Could not get the GatewayClass objects for UpdateStatus: %s", err)
+			return gwMap
+		}
+		for _, gwClass := range gwClassList {
+			if string(gwClass.Spec.ControllerName) == lib.GatewayAPIAviGatewayController {
+				aviGWClasses[gwClass.Name] = true
+			}
+		}
+		if len(aviGWClasses) == 0 {
+			return gwMap
+		}
+
+		gwList, err := informers.GatewayInformer.Lister().List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Warnf("Could not get the Gateway objects for UpdateStatus: %s", err)
+			return gwMap
+		}
+		for _, gw := range gwList {
+			if aviGWClasses[string(gw.Spec.GatewayClassName)] && utils.CheckIfNamespaceAccepted(gw.Namespace) {
+				gwMap[gw.Namespace+"/"+gw.Name] = gw.DeepCopy()
+			}
+		}
+		return gwMap
+	}
+
+	for _, namespaceName := range gwNSNames {
+		nsNameSplit := strings.Split(namespaceName, "/")
+		if len(nsNameSplit) != 2 {
+			continue
+		}
+		gw, err := informers.GatewayInformer.Lister().Gateways(nsNameSplit[0]).Get(nsNameSplit[1])
+		if err != nil {
+			utils.AviLog.Warnf("Could not get the Gateway object for UpdateStatus: %s", err)
+			continue
+		}
+		gwMap[gw.Namespace+"/"+gw.Name] = gw.DeepCopy()
+	}
+	return gwMap
+}
+
+func DeleteGatewayAPIGatewayStatusAddress(key string, svcMetadataObj lib.ServiceMetadataObj) error {
+	gwNSName := strings.Split(svcMetadataObj.Gateway, "/")
+	if len(gwNSName) != 2 {
+		return nil
+	}
+	gw, err := lib.AKOControlConfig().GatewayAPIInformers().GatewayInformer.Lister().Gateways(gwNSName[0]).Get(gwNSName[1])
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: there was a problem in resetting the gateway address status: %s", key, err)
+		return err
+	}
+	if len(gw.Status.Addresses) == 0 {
+		return nil
+	}
+
+	gwStatus := gw.Status.DeepCopy()
+	gwStatus.Addresses = nil
+	setGatewayAPICondition(&gwStatus.Conditions, metav1.Condition{
+		Type:               string(gwapiv1alpha2.GatewayConditionReady),
+		Status:             metav1.ConditionFalse,
+		Reason:             string(gwapiv1alpha2.GatewayReasonAddressNotAssigned),
+		Message:            "Virtual service deleted/not found",
+		ObservedGeneration: gw.Generation,
+	})
+	UpdateGatewayAPIGatewayStatusObject(key, gw.DeepCopy(), gwStatus)
+	utils.AviLog.Infof("key: %s, msg: Successfully reset the address status of gateway: %s", key, svcMetadataObj.Gateway)
+	return nil
+}
+
+// UpdateGatewayAPIGatewayConditionStatus sets a Gateway condition, used when the address
+// requested in the Gateway can not be assigned to the VS.
+func UpdateGatewayAPIGatewayConditionStatus(key, gwNSName string, condition metav1.Condition) error {
+	gwNSNameSplit := strings.Split(gwNSName, "/")
+	if len(gwNSNameSplit) != 2 {
+		return nil
+	}
+	gw, err := lib.AKOControlConfig().GatewayAPIInformers().GatewayInformer.Lister().Gateways(gwNSNameSplit[0]).Get(gwNSNameSplit[1])
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: Gateway object not found, skipping status update %v", key, err)
+		return err
+	}
+	gwStatus := gw.Status.DeepCopy()
+	condition.ObservedGeneration = gw.Generation
+	setGatewayAPICondition(&gwStatus.Conditions, condition)
+	UpdateGatewayAPIGatewayStatusObject(key, gw.DeepCopy(), gwStatus)
+	return nil
+}
+
+// UpdateGatewayAPIListenerStatus updates the listener statuses and the Scheduled condition of a Gateway,
+// as computed by the graph layer.
+func UpdateGatewayAPIListenerStatus(key, namespace, name string, options *UpdateOptions) {
+	gw, err := lib.AKOControlConfig().GatewayAPIInformers().GatewayInformer.Lister().Gateways(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: Gateway object %s/%s not found, skipping status update %v", key, namespace, name, err)
+		return
+	}
+	gwStatus := gw.Status.DeepCopy()
+	currentListeners := make(map[gwapiv1alpha2.SectionName]gwapiv1alpha2.ListenerStatus)
+	for _, listener := range gwStatus.Listeners {
+		currentListeners[listener.Name] = listener
+	}
+	gwStatus.Listeners = nil
+	for _, listener := range options.GatewayAPIListeners {
+		// retain the transition times of the conditions which have not changed.
+		if current, ok := currentListeners[listener.Name]; ok {
+			conditions := append([]metav1.Condition(nil), current.Conditions...)
+			for _, condition := range listener.Conditions {
+				setGatewayAPICondition(&conditions, condition)
+			}
+			listener.Conditions = pruneGatewayAPIConditions(conditions, listener.Conditions)
+		}
+		gwStatus.Listeners = append(gwStatus.Listeners, listener)
+	}
+	for _, condition := range options.GatewayAPIConditions {
+		condition.ObservedGeneration = gw.Generation
+		setGatewayAPICondition(&gwStatus.Conditions, condition)
+	}
+	UpdateGatewayAPIGatewayStatusObject(key, gw.DeepCopy(), gwStatus)
+}
+
+func UpdateGatewayAPIGatewayStatusObject(key string, gw *gwapiv1alpha2.Gateway, updateStatus *gwapiv1alpha2.GatewayStatus, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 5 {
+			utils.AviLog.Errorf("key: %s, msg: UpdateGatewayAPIGatewayStatusObject retried 5 times, aborting", key)
+			return
+		}
+	}
+
+	if compareGatewayAPIGatewayStatuses(&gw.Status, updateStatus) {
+		return
+	}
+
+	// the empty lists are omitted while marshalling the status, hence those are explicitly
+	// set to null, for the merge patch to reset them.
+	statusMap := make(map[string]interface{})
+	statusJSON, _ := json.Marshal(updateStatus)
+	json.Unmarshal(statusJSON, &statusMap)
+	for _, field := range []string{"addresses", "listeners"} {
+		if _, ok := statusMap[field]; !ok {
+			statusMap[field] = nil
+		}
+	}
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": statusMap,
+	})
+	_, err := lib.AKOControlConfig().GatewayAPIClientset().GatewayV1alpha2().Gateways(gw.Namespace).Patch(context.TODO(), gw.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: %d there was an error in updating the gateway status: %+v", key, retry, err)
+		updatedGW, err := lib.AKOControlConfig().GatewayAPIInformers().GatewayInformer.Lister().Gateways(gw.Namespace).Get(gw.Name)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: gateway not found %v", key, err)
+			return
+		}
+		UpdateGatewayAPIGatewayStatusObject(key, updatedGW.DeepCopy(), updateStatus, retry+1)
+		return
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the gateway %s/%s status %+v", key, gw.Namespace, gw.Name, utils.Stringify(updateStatus))
+}
+
+// UpdateGatewayAPIRouteStatus replaces the parent statuses of the route, which refer to the Gateway in
+// options.ServiceMetadata.Gateway, with options.GatewayAPIRouteParents. The parent statuses written by
+// AKO for the parentRefs which are no longer present in the route are removed, while the ones written
+// by other controllers are retained.
+func UpdateGatewayAPIRouteStatus(routeType, key, namespace, name string, options *UpdateOptions, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 5 {
+			utils.AviLog.Errorf("key: %s, msg: UpdateGatewayAPIRouteStatus retried 5 times, aborting", key)
+			return
+		}
+	}
+
+	parentRefs, routeStatus, err := getGatewayAPIRoute(routeType, namespace, name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: %s %s/%s not found, skipping status update %v", key, routeType, namespace, name, err)
+		return
+	}
+
+	var parents []gwapiv1alpha2.RouteParentStatus
+	for _, parent := range routeStatus.Parents {
+		if string(parent.ControllerName) == lib.GatewayAPIAviGatewayController {
+			if options.ServiceMetadata.Gateway != "" && lib.GatewayAPIParentRefName(parent.ParentRef, namespace) == options.ServiceMetadata.Gateway {
+				continue
+			}
+			if !hasGatewayAPIParentRef(parentRefs, parent.ParentRef) {
+				continue
+			}
+		}
+		parents = append(parents, parent)
+	}
+	for _, parent := range options.GatewayAPIRouteParents {
+		// retain the transition times of the conditions which have not changed.
+		for _, current := range routeStatus.Parents {
+			if string(current.ControllerName) == lib.GatewayAPIAviGatewayController && reflect.DeepEqual(current.ParentRef, parent.ParentRef) {
+				conditions := append([]metav1.Condition(nil), current.Conditions...)
+				for _, condition := range parent.Conditions {
+					setGatewayAPICondition(&conditions, condition)
+				}
+				parent.Conditions = pruneGatewayAPIConditions(conditions, parent.Conditions)
+				break
+			}
+		}
+		parents = append(parents, parent)
+	}
+
+	updateStatus := gwapiv1alpha2.RouteStatus{Parents: parents}
+	if compareGatewayAPIRouteStatuses(routeStatus, &updateStatus) {
+		return
+	}
+	if updateStatus.Parents == nil {
+		updateStatus.Parents = []gwapiv1alpha2.RouteParentStatus{}
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": updateStatus,
+	})
+	cs := lib.AKOControlConfig().GatewayAPIClientset().GatewayV1alpha2()
+	switch routeType {
+	case lib.HTTPRoute:
+		_, err = cs.HTTPRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	case lib.TLSRoute:
+		_, err = cs.TLSRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	case lib.TCPRoute:
+		_, err = cs.TCPRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	case lib.UDPRoute:
+		_, err = cs.UDPRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	}
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: %d there was an error in updating the %s status: %+v", key, retry, routeType, err)
+		UpdateGatewayAPIRouteStatus(routeType, key, namespace, name, options, retry+1)
+		return
+	}
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the %s %s/%s status %+v", key, routeType, namespace, name, utils.Stringify(updateStatus))
+}
+
+func getGatewayAPIRoute(routeType, namespace, name string) ([]gwapiv1alpha2.ParentRef, *gwapiv1alpha2.RouteStatus, error) {
+	informers := lib.AKOControlConfig().GatewayAPIInformers()
+	switch routeType {
+	case lib.HTTPRoute:
+		route, err := informers.HTTPRouteInformer.Lister().HTTPRoutes(namespace).Get(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return route.Spec.ParentRefs, route.Status.RouteStatus.DeepCopy(), nil
+	case lib.TLSRoute:
+		route, err := informers.TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return route.Spec.ParentRefs, route.Status.RouteStatus.DeepCopy(), nil
+	case lib.TCPRoute:
+		route, err := informers.TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return route.Spec.ParentRefs, route.Status.RouteStatus.DeepCopy(), nil
+	default:
+		route, err := informers.UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		return route.Spec.ParentRefs, route.Status.RouteStatus.DeepCopy(), nil
+	}
+}
+
+func hasGatewayAPIParentRef(parentRefs []gwapiv1alpha2.ParentRef, parentRef gwapiv1alpha2.ParentRef) bool {
+	for _, ref := range parentRefs {
+		if reflect.DeepEqual(ref, parentRef) {
+			return true
+		}
+	}
+	return false
+}
+
+// setGatewayAPICondition adds or updates the condition, the transition time is updated
+// only if the status of the condition changes.
+func setGatewayAPICondition(conditions *[]metav1.Condition, condition metav1.Condition) {
+	for i := range *conditions {
+		existing := &(*conditions)[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status != condition.Status || existing.LastTransitionTime.IsZero() {
+			existing.LastTransitionTime = metav1.Now()
+		}
+		existing.Status = condition.Status
+		existing.Reason = condition.Reason
+		existing.Message = condition.Message
+		existing.ObservedGeneration = condition.ObservedGeneration
+		return
+	}
+	condition.LastTransitionTime = metav1.Now()
+	*conditions = append(*conditions, condition)
+}
+
+// pruneGatewayAPIConditions retains the conditions whose types are present in desired.
+func pruneGatewayAPIConditions(conditions, desired []metav1.Condition) []metav1.Condition {
+	var pruned []metav1.Condition
+	for _, condition := range conditions {
+		for _, d := range desired {
+			if d.Type == condition.Type {
+				pruned = append(pruned, condition)
+				break
+			}
+		}
+	}
+	return pruned
+}
+
+// do not compare lastTransitionTime updates in gateway
+func compareGatewayAPIGatewayStatuses(old, new *gwapiv1alpha2.GatewayStatus) bool {
+	oldStatus, newStatus := old.DeepCopy(), new.DeepCopy()
+	resetGatewayAPIConditionTimes(oldStatus.Conditions)
+	resetGatewayAPIConditionTimes(newStatus.Conditions)
+	for i := range oldStatus.Listeners {
+		resetGatewayAPIConditionTimes(oldStatus.Listeners[i].Conditions)
+	}
+	for i := range newStatus.Listeners {
+		resetGatewayAPIConditionTimes(newStatus.Listeners[i].Conditions)
+	}
+	return reflect.DeepEqual(oldStatus, newStatus)
+}
+
+// do not compare lastTransitionTime updates in routes
+func compareGatewayAPIRouteStatuses(old, new *gwapiv1alpha2.RouteStatus) bool {
+	oldStatus, newStatus := old.DeepCopy(), new.DeepCopy()
+	if len(oldStatus.Parents) == 0 && len(newStatus.Parents) == 0 {
+		return true
+	}
+	for i := range oldStatus.Parents {
+		resetGatewayAPIConditionTimes(oldStatus.Parents[i].Conditions)
+	}
+	for i := range newStatus.Parents {
+		resetGatewayAPIConditionTimes(newStatus.Parents[i].Conditions)
+	}
+	return reflect.DeepEqual(oldStatus, newStatus)
+}
+
+func resetGatewayAPIConditionTimes(conditions []metav1.Condition) {
+	for i := range conditions {
+		conditions[i].LastTransitionTime = metav1.Time{}
+	}
+}
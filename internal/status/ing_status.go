@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	types "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 type UpdateOptions struct {
@@ -43,6 +44,15 @@ type UpdateOptions struct {
 	Key                string
 	VirtualServiceUUID string
 	VSName             string
+	// FQDNs and tenant of the vsvip, for the DNSEndpoint updates.
+	FQDNs  []string
+	Tenant string
+
+	// Listener, Gateway condition and route parent statuses computed by the graph layer,
+	// for the gateway.networking.k8s.io objects.
+	GatewayAPIListeners    []gwapiv1alpha2.ListenerStatus
+	GatewayAPIConditions   []metav1.Condition
+	GatewayAPIRouteParents []gwapiv1alpha2.RouteParentStatus
 }
 
 // VSUuidAnnotation is maps a hostname to the UUID of the virtual service where it is placed.
@@ -27,6 +27,9 @@ type StatusOptions struct {
 	Namespace string
 	Key       string
 	Options   *UpdateOptions
+	// Message is the sync error of the virtualservice for the SyncStatus updates, the drift of the Avi
+	// object for the DriftStatus updates, and the certificate alert for the CertificateStatus updates.
+	Message string
 }
 
 func PublishToStatusQueue(key string, statusOption StatusOptions) {
@@ -72,12 +75,34 @@ func DequeueStatus(objIntf interface{}) error {
 		} else if obj.Op == lib.DeleteStatus {
 			DeleteSvcApiGatewayStatusAddress(obj.Options.Key, obj.Options.ServiceMetadata)
 		}
+	case lib.GatewayAPIGateway:
+		if obj.Op == lib.UpdateStatus {
+			UpdateGatewayAPIGatewayStatusAddress([]UpdateOptions{*obj.Options}, false)
+		} else if obj.Op == lib.DeleteStatus {
+			DeleteGatewayAPIGatewayStatusAddress(obj.Options.Key, obj.Options.ServiceMetadata)
+		}
+	case lib.GatewayAPIListener:
+		UpdateGatewayAPIListenerStatus(obj.Key, obj.Namespace, obj.ObjName, obj.Options)
+	case lib.HTTPRoute, lib.TLSRoute, lib.TCPRoute, lib.UDPRoute:
+		UpdateGatewayAPIRouteStatus(obj.ObjType, obj.Key, obj.Namespace, obj.ObjName, obj.Options)
 	case lib.NPLService:
 		if obj.Op == lib.UpdateStatus {
 			UpdateNPLAnnotation(obj.Key, obj.Namespace, obj.ObjName)
 		} else if obj.Op == lib.DeleteStatus {
 			DeleteNPLAnnotation(obj.Key, obj.Namespace, obj.ObjName)
 		}
+	case lib.SyncStatus:
+		UpdateSyncStatus(obj.Key, obj.ObjName, obj.Op, obj.Message)
+	case lib.DriftStatus:
+		UpdateDriftStatus(obj.Key, obj.ObjName, obj.Op, obj.Message)
+	case lib.CertificateStatus:
+		UpdateCertificateStatus(obj.Key, obj.ObjName, obj.Op, obj.Message)
+	case lib.DNSEndpointStatus:
+		if obj.Op == lib.UpdateStatus {
+			UpdateDNSEndpoint(obj.Key, obj.Options.Tenant, obj.ObjName, obj.Options.FQDNs, obj.Options.Vip)
+		} else if obj.Op == lib.DeleteStatus {
+			DeleteDNSEndpoint(obj.Key, obj.Options.Tenant, obj.ObjName)
+		}
 	case lib.MultiClusterIngress:
 		if obj.Op == lib.UpdateStatus {
 			UpdateMultiClusterIngressStatusAndAnnotation(obj.Key, obj.Options)
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PublishSyncStatus publishes the sync status of the model to all the objects which were processed into
+// the model. reason is one of lib.SyncFailed, lib.SyncRetrying and lib.Synced.
+func PublishSyncStatus(modelName, reason, message string) {
+	for _, objKey := range objects.SharedModelKeyLister().GetKeys(modelName) {
+		statusOption := StatusOptions{
+			ObjType: lib.SyncStatus,
+			Op:      reason,
+			ObjName: objKey,
+			Key:     modelName,
+			Message: message,
+		}
+		PublishToStatusQueue(objKey, statusOption)
+	}
+}
+
+// UpdateSyncStatus raises an Event with the sync status of the model on the object objKey, and sets the
+// ako.vmware.com/Synced condition of the object in case of Ingresses and Routes.
+func UpdateSyncStatus(modelName, objKey, reason, message string) {
+	objType, namespace, name := lib.ExtractTypeNameNamespace(objKey)
+	obj, err := getSyncStatusObject(objType, namespace, name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// The object is deleted, it no longer contributes to the model.
+			objects.SharedModelKeyLister().RemoveKey(modelName, objKey)
+			return
+		}
+		utils.AviLog.Warnf("key: %s, msg: unable to get %s for sync status update: %v", modelName, objKey, err)
+		return
+	}
+
+	_, vsName := utils.ExtractNamespaceObjectName(modelName)
+	switch reason {
+	case lib.SyncFailed:
+		lib.AKOControlConfig().EventRecorder().Eventf(obj, corev1.EventTypeWarning, lib.SyncFailed,
+			"Failed to sync virtualservice %s to the Avi controller: %s", vsName, message)
+	case lib.SyncRetrying:
+		lib.AKOControlConfig().EventRecorder().Eventf(obj, corev1.EventTypeWarning, lib.SyncRetrying,
+			"Sync of virtualservice %s to the Avi controller would be retried every %d seconds: %s", vsName, lib.SLOW_SYNC_TIME, message)
+	default:
+		lib.AKOControlConfig().EventRecorder().Eventf(obj, corev1.EventTypeNormal, lib.Synced,
+			"Synced virtualservice %s to the Avi controller", vsName)
+	}
+
+	condition := metav1.Condition{
+		Type:               lib.SyncedConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	if reason == lib.Synced {
+		condition.Status = metav1.ConditionTrue
+		condition.Message = ""
+	}
+
+	switch o := obj.(type) {
+	case *networkingv1.Ingress:
+		err = updateIngressSyncCondition(o, condition)
+	case *routev1.Route:
+		err = updateRouteSyncCondition(o, condition)
+	}
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to update the sync condition of %s: %v", modelName, objKey, err)
+	}
+}
+
+func getSyncStatusObject(objType, namespace, name string) (runtime.Object, error) {
+	switch objType {
+	case utils.Ingress:
+		return utils.GetInformers().IngressInformer.Lister().Ingresses(namespace).Get(name)
+	case utils.OshiftRoute:
+		return utils.GetInformers().RouteInformer.Lister().Routes(namespace).Get(name)
+	case utils.L4LBService:
+		return utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(name)
+	case lib.Gateway:
+		if lib.GetAdvancedL4() {
+			return lib.AKOControlConfig().AdvL4Informers().GatewayInformer.Lister().Gateways(namespace).Get(name)
+		}
+		return lib.AKOControlConfig().SvcAPIInformers().GatewayInformer.Lister().Gateways(namespace).Get(name)
+	}
+	return nil, k8serrors.NewNotFound(corev1.Resource(objType), name)
+}
+
+// updateIngressSyncCondition saves the condition in the ako.vmware.com/Synced annotation, since the
+// Ingress status has no conditions.
+func updateIngressSyncCondition(ing *networkingv1.Ingress, condition metav1.Condition) error {
+	var oldCondition metav1.Condition
+	if value, ok := ing.Annotations[lib.SyncedConditionType]; ok {
+		if err := json.Unmarshal([]byte(value), &oldCondition); err == nil && sameSyncCondition(oldCondition, condition) {
+			return nil
+		}
+	} else if condition.Status == metav1.ConditionTrue {
+		// The Ingress never failed to sync.
+		return nil
+	}
+
+	value, err := json.Marshal(condition)
+	if err != nil {
+		return err
+	}
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				lib.SyncedConditionType: string(value),
+			},
+		},
+	})
+	_, err = utils.GetInformers().ClientSet.NetworkingV1().Ingresses(ing.Namespace).Patch(context.TODO(), ing.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{})
+	return err
+}
+
+// updateRouteSyncCondition sets the condition in the status of the Route, for the hosts admitted by AKO.
+func updateRouteSyncCondition(route *routev1.Route, condition metav1.Condition) error {
+	routeCondition := routev1.RouteIngressCondition{
+		Type:               routev1.RouteIngressConditionType(condition.Type),
+		Status:             corev1.ConditionStatus(condition.Status),
+		Reason:             condition.Reason,
+		Message:            condition.Message,
+		LastTransitionTime: &condition.LastTransitionTime,
+	}
+
+	mRoute := route.DeepCopy()
+	updated, found := false, false
+	for i := range mRoute.Status.Ingress {
+		if mRoute.Status.Ingress[i].RouterName != lib.AKOUser {
+			continue
+		}
+		found = true
+		conditions := mRoute.Status.Ingress[i].Conditions
+		j := 0
+		for ; j < len(conditions); j++ {
+			if conditions[j].Type == routeCondition.Type {
+				break
+			}
+		}
+		if j == len(conditions) {
+			if routeCondition.Status == corev1.ConditionTrue {
+				continue
+			}
+			mRoute.Status.Ingress[i].Conditions = append(conditions, routeCondition)
+			updated = true
+			continue
+		}
+		if conditions[j].Status == routeCondition.Status && conditions[j].Reason == routeCondition.Reason &&
+			conditions[j].Message == routeCondition.Message {
+			continue
+		}
+		conditions[j] = routeCondition
+		updated = true
+	}
+
+	if !found && routeCondition.Status == corev1.ConditionFalse {
+		// The Route was never admitted by AKO, the Admitted condition is kept first as the
+		// rest of the status updates rely on it.
+		mRoute.Status.Ingress = append(mRoute.Status.Ingress, routev1.RouteIngress{
+			Host:       mRoute.Spec.Host,
+			RouterName: lib.AKOUser,
+			Conditions: []routev1.RouteIngressCondition{
+				{
+					Type:               routev1.RouteAdmitted,
+					Status:             corev1.ConditionFalse,
+					Reason:             routeCondition.Reason,
+					LastTransitionTime: routeCondition.LastTransitionTime,
+				},
+				routeCondition,
+			},
+		})
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": mRoute.Status,
+	})
+	_, err := utils.GetInformers().OshiftClient.RouteV1().Routes(mRoute.Namespace).Patch(context.TODO(), mRoute.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	return err
+}
+
+func sameSyncCondition(a, b metav1.Condition) bool {
+	return a.Status == b.Status && a.Reason == b.Reason && a.Message == b.Message
+}
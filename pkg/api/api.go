@@ -77,6 +77,8 @@ func (a *ApiServer) initModels() {
 	// add common models in ApiServer
 	genericModels := []models.ApiModel{
 		models.RestStatus,
+		models.DryRun,
+		models.Metrics,
 	}
 	a.Models = append(a.Models, genericModels...)
 
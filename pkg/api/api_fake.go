@@ -31,6 +31,8 @@ func (a *FakeApiServer) initModels() {
 	// add common models in ApiServer
 	genericModels := []models.ApiModel{
 		models.RestStatus,
+		models.DryRun,
+		models.Metrics,
 	}
 	a.Models = append(a.Models, genericModels...)
 
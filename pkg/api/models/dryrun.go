@@ -0,0 +1,199 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// DryRunRecord is a rest operation recorded in place of being sent to the Avi controller.
+type DryRunRecord struct {
+	Method    string          `json:"method"`
+	Model     string          `json:"model"`
+	Tenant    string          `json:"tenant"`
+	Name      string          `json:"name"`
+	Path      string          `json:"path"`
+	Key       string          `json:"key,omitempty"`
+	Diff      []DryRunDiff    `json:"diff"`
+	Object    json.RawMessage `json:"object,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// DryRunDiff is a field of the object which differs from the cached entry of the object.
+type DryRunDiff struct {
+	Field   string      `json:"field"`
+	Current interface{} `json:"current,omitempty"`
+	Desired interface{} `json:"desired,omitempty"`
+}
+
+var DryRun *DryRunModel
+var dryrunonce sync.Once
+
+// DryRunModel implements ApiModel, and holds the latest rest operation recorded for every object.
+type DryRunModel struct {
+	records  map[string]DryRunRecord
+	filePath string
+	lock     sync.RWMutex
+}
+
+func (a *DryRunModel) InitModel() {
+	dryrunonce.Do(func() {
+		DryRun = &DryRunModel{
+			records: make(map[string]DryRunRecord),
+		}
+	})
+}
+
+func (a *DryRunModel) ApiOperationMap() []OperationMap {
+	var operationMapList []OperationMap
+
+	get := OperationMap{
+		Route:  "/api/dryrun",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			response := DryRun.GetRecords()
+			utils.Respond(w, response)
+		},
+	}
+
+	operationMapList = append(operationMapList, get)
+	return operationMapList
+}
+
+// SetFilePath sets the file to which the records are written on every update.
+func (a *DryRunModel) SetFilePath(filePath string) {
+	if a == nil {
+		return
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.filePath = filePath
+}
+
+// GetRecords returns the recorded rest operations, sorted by the object type, tenant and name.
+func (a *DryRunModel) GetRecords() []DryRunRecord {
+	records := []DryRunRecord{}
+	if a == nil {
+		return records
+	}
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	for _, record := range a.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Model != records[j].Model {
+			return records[i].Model < records[j].Model
+		}
+		if records[i].Tenant != records[j].Tenant {
+			return records[i].Tenant < records[j].Tenant
+		}
+		return records[i].Name < records[j].Name
+	})
+	return records
+}
+
+// Record stores the rest operations, replacing the earlier records of the same objects, and writes
+// all the records to the file, if set.
+func (a *DryRunModel) Record(records ...DryRunRecord) {
+	// The model is not initialized in case of the components which don't use the API server.
+	if a == nil || len(records) == 0 {
+		return
+	}
+	a.lock.Lock()
+	for _, record := range records {
+		if record.Timestamp.IsZero() {
+			record.Timestamp = time.Now()
+		}
+		redactKeys(&record)
+		a.records[record.Model+"/"+record.Tenant+"/"+record.Name] = record
+	}
+	filePath := a.filePath
+	a.lock.Unlock()
+
+	if filePath != "" {
+		a.writeFile(filePath)
+	}
+}
+
+// dryRunKeyFields are the fields of the SSLKeyAndCertificate objects with the private key. These are not recorded,
+// as the records are served by the API server and written to the file.
+var dryRunKeyFields = []string{"key", "key_passphrase"}
+
+// redactKeys removes the private key of the SSLKeyAndCertificate objects from the record.
+func redactKeys(record *DryRunRecord) {
+	if !strings.EqualFold(record.Model, "SSLKeyAndCertificate") {
+		return
+	}
+	diff := []DryRunDiff{}
+	for _, field := range record.Diff {
+		if !utils.HasElem(dryRunKeyFields, field.Field) {
+			diff = append(diff, field)
+		}
+	}
+	record.Diff = diff
+
+	if len(record.Object) == 0 {
+		return
+	}
+	object := make(map[string]interface{})
+	if err := json.Unmarshal(record.Object, &object); err != nil {
+		record.Object = nil
+		return
+	}
+	// The macro calls have the object in the data field.
+	data, _ := object["data"].(map[string]interface{})
+	for _, field := range dryRunKeyFields {
+		delete(object, field)
+		delete(data, field)
+	}
+	record.Object, _ = json.Marshal(object)
+}
+
+func (a *DryRunModel) writeFile(filePath string) {
+	data, err := json.MarshalIndent(a.GetRecords(), "", "  ")
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal the dry-run records: %v", err)
+		return
+	}
+	// Write to a temporary file first, so that the readers never see a partially written file.
+	tmpFile, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".tmp")
+	if err != nil {
+		utils.AviLog.Warnf("Unable to write the dry-run records to %s: %v", filePath, err)
+		return
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err = tmpFile.Write(data); err == nil {
+		err = tmpFile.Close()
+	} else {
+		tmpFile.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), filePath)
+	}
+	if err != nil {
+		utils.AviLog.Warnf("Unable to write the dry-run records to %s: %v", filePath, err)
+	}
+}
@@ -39,8 +39,10 @@ var reststatusonce sync.Once
 
 // StatusModel implements ApiModel
 type StatusModel struct {
-	AviApi     AviApiRestStatus `json:"avi_api"`
-	statusLock sync.RWMutex
+	AviApi AviApiRestStatus `json:"avi_api"`
+	// AviRestLimiter is the state of the rate limiter and the circuit breaker for the rest requests to Avi.
+	AviRestLimiter utils.AviRestLimiterStatus `json:"avi_rest_limiter"`
+	statusLock     sync.RWMutex
 }
 
 func (a *StatusModel) InitModel() {
@@ -61,6 +63,9 @@ func (a *StatusModel) ApiOperationMap() []OperationMap {
 		Route:  "/api/status",
 		Method: "GET",
 		Handler: func(w http.ResponseWriter, r *http.Request) {
+			RestStatus.statusLock.Lock()
+			RestStatus.AviRestLimiter = utils.SharedAviRestLimiter().Status()
+			RestStatus.statusLock.Unlock()
 			response := &RestStatus
 			utils.Respond(w, response)
 		},
@@ -74,6 +74,32 @@ type HostRuleVirtualHost struct {
 	AnalyticsPolicy    *HostRuleAnalyticsPolicy `json:"analyticsPolicy,omitempty"`
 	TCPSettings        *HostRuleTCPSettings     `json:"tcpSettings,omitempty"`
 	Aliases            []string                 `json:"aliases,omitempty"`
+	Security           *HostRuleSecurity        `json:"security,omitempty"`
+}
+
+// HostRuleSecurity holds the client rate limits and the source IP
+// restrictions for the host
+type HostRuleSecurity struct {
+	RateLimits      *HostRuleRateLimits `json:"rateLimits,omitempty"`
+	AllowedCIDRs    []string            `json:"allowedCIDRs,omitempty"`
+	DeniedCIDRs     []string            `json:"deniedCIDRs,omitempty"`
+	AllowedIPGroups []string            `json:"allowedIPGroups,omitempty"`
+	DeniedIPGroups  []string            `json:"deniedIPGroups,omitempty"`
+}
+
+// HostRuleRateLimits holds the connection and request rate limits
+type HostRuleRateLimits struct {
+	Connections *HostRuleRateLimit `json:"connections,omitempty"`
+	Requests    *HostRuleRateLimit `json:"requests,omitempty"`
+}
+
+// HostRuleRateLimit allows count connections or requests every period
+// seconds, with an additional burst
+type HostRuleRateLimit struct {
+	Count       int32 `json:"count,omitempty"`
+	Period      int32 `json:"period,omitempty"`
+	Burst       int32 `json:"burst,omitempty"`
+	PerClientIP bool  `json:"perClientIP,omitempty"`
 }
 
 // HostRuleTCPSettings allows for customizing TCP settings
@@ -94,6 +120,58 @@ type HostRuleTLS struct {
 	SSLKeyCertificate HostRuleSSLKeyCertificate `json:"sslKeyCertificate,omitempty"`
 	SSLProfile        string                    `json:"sslProfile,omitempty"`
 	Termination       string                    `json:"termination,omitempty"`
+	OCSPStapling      *HostRuleOCSPStapling     `json:"ocspStapling,omitempty"`
+	ClientAuth        *HostRuleClientAuth       `json:"clientAuth,omitempty"`
+}
+
+// HostRuleClientAuth requires or requests the client certificates for the host,
+// validated with the CA certificates in a Secret or with an Avi PKI profile, and
+// inserts the details of the client certificate as headers to the backends
+type HostRuleClientAuth struct {
+	Mode       ClientAuthMode             `json:"mode,omitempty"`
+	CASecret   string                     `json:"caSecret,omitempty"`
+	PKIProfile string                     `json:"pkiProfile,omitempty"`
+	Headers    []HostRuleClientAuthHeader `json:"headers,omitempty"`
+}
+
+// HostRuleClientAuthHeader inserts a header with a field of the client
+// certificate in the requests to the backends
+type HostRuleClientAuthHeader struct {
+	Name  string                `json:"name,omitempty"`
+	Value ClientCertHeaderValue `json:"value,omitempty"`
+}
+
+type ClientAuthMode string
+
+const (
+	// Rejects the clients without a valid certificate.
+	ClientAuthModeRequire ClientAuthMode = "Require"
+
+	// Requests the client certificate, and allows the clients without one.
+	ClientAuthModeRequest ClientAuthMode = "Request"
+)
+
+type ClientCertHeaderValue string
+
+const (
+	ClientCertSubject        ClientCertHeaderValue = "Subject"
+	ClientCertIssuer         ClientCertHeaderValue = "Issuer"
+	ClientCertSerial         ClientCertHeaderValue = "Serial"
+	ClientCertFingerprint    ClientCertHeaderValue = "Fingerprint"
+	ClientCertNotValidBefore ClientCertHeaderValue = "NotValidBefore"
+	ClientCertNotValidAfter  ClientCertHeaderValue = "NotValidAfter"
+
+	// The whole client certificate in the PEM format.
+	ClientCertCertificate ClientCertHeaderValue = "Certificate"
+)
+
+// HostRuleOCSPStapling enables OCSP stapling for the certificate of the host,
+// with the OCSP responders to query and the interval in seconds between the
+// OCSP requests
+type HostRuleOCSPStapling struct {
+	ResponderURLs []string `json:"responderURLs,omitempty"`
+	URLAction     string   `json:"urlAction,omitempty"`
+	Frequency     int32    `json:"frequency,omitempty"`
 }
 
 // HostRuleSecret is required to provide distinction between Avi SSLKeyCertificate
@@ -123,14 +201,55 @@ type HostRuleHTTPPolicy struct {
 
 // HostRuleHTTPPolicy holds knobs and refs for httpPolicySets
 type HostRuleGSLB struct {
-	Fqdn           string `json:"fqdn,omitempty"`
-	IncludeAliases bool   `json:"includeAliases,omitempty"`
+	Fqdn           string               `json:"fqdn,omitempty"`
+	IncludeAliases bool                 `json:"includeAliases,omitempty"`
+	Service        *HostRuleGSLBService `json:"service,omitempty"`
 }
 
+// HostRuleGSLBService makes AKO create and maintain the GSLBService for the
+// GSLB FQDN, with the virtualservices of this cluster as members
+type HostRuleGSLBService struct {
+	Algorithm      GSLBAlgorithm `json:"algorithm,omitempty"`
+	Priority       int32         `json:"priority,omitempty"`
+	HealthMonitors []string      `json:"healthMonitors,omitempty"`
+	TTL            *int32        `json:"ttl,omitempty"`
+}
+
+type GSLBAlgorithm string
+
+const (
+	// Distributes the DNS queries across the members of all the clusters.
+	GSLBAlgorithmRoundRobin GSLBAlgorithm = "RoundRobin"
+
+	// Answers the DNS queries with the members closest to the client.
+	GSLBAlgorithmGeo GSLBAlgorithm = "Geo"
+
+	// Answers the DNS queries with the members of the cluster with the
+	// highest priority, falling over to the next cluster when they are down.
+	GSLBAlgorithmPriority GSLBAlgorithm = "Priority"
+)
+
 // HostRuleStatus holds the status of the HostRule
 type HostRuleStatus struct {
-	Status string `json:"status,omitempty"`
-	Error  string `json:"error"`
+	Status string              `json:"status,omitempty"`
+	Error  string              `json:"error"`
+	Gslb   *HostRuleGSLBStatus `json:"gslb,omitempty"`
+}
+
+// HostRuleGSLBStatus holds the status of the GSLBService maintained by AKO
+// and of the members of this cluster
+type HostRuleGSLBStatus struct {
+	ServiceName string                     `json:"serviceName,omitempty"`
+	Members     []HostRuleGSLBMemberStatus `json:"members,omitempty"`
+	Error       string                     `json:"error,omitempty"`
+}
+
+// HostRuleGSLBMemberStatus holds the operational status of a GSLBService member
+type HostRuleGSLBMemberStatus struct {
+	VirtualService string `json:"virtualService,omitempty"`
+	IP             string `json:"ip,omitempty"`
+	Pool           string `json:"pool,omitempty"`
+	Status         string `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
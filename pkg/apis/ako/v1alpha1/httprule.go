@@ -43,7 +43,59 @@ type HTTPRulePaths struct {
 	LoadBalancerPolicy     HTTPRuleLBPolicy `json:"loadBalancerPolicy,omitempty"`
 	TLS                    HTTPRuleTLS      `json:"tls,omitempty"`
 	HealthMonitors         []string         `json:"healthMonitors,omitempty"`
+	ProbeHealthMonitor     bool             `json:"probeHealthMonitor,omitempty"`
 	ApplicationPersistence string           `json:"applicationPersistence,omitempty"`
+
+	RequestHeaders  []HTTPRuleHeaderAction `json:"requestHeaders,omitempty"`
+	ResponseHeaders []HTTPRuleHeaderAction `json:"responseHeaders,omitempty"`
+	RewritePrefix   string                 `json:"rewritePrefix,omitempty"`
+	Redirect        *HTTPRuleRedirect      `json:"redirect,omitempty"`
+
+	Backends []HTTPRuleBackend `json:"backends,omitempty"`
+}
+
+const (
+	HTTPRuleHeaderActionAdd     = "Add"
+	HTTPRuleHeaderActionRemove  = "Remove"
+	HTTPRuleHeaderActionReplace = "Replace"
+)
+
+// HTTPRuleHeaderAction adds, removes or replaces a request/response header on a target path
+type HTTPRuleHeaderAction struct {
+	Action string `json:"action,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// HTTPRuleRedirect redirects the requests on a target path, the unset fields retain the
+// values of the incoming request
+type HTTPRuleRedirect struct {
+	Protocol   string `json:"protocol,omitempty"`
+	Host       string `json:"host,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Port       int32  `json:"port,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty"`
+}
+
+// HTTPRuleBackend is an additional Service the traffic of a target path is split to, along with
+// the backend Service of the Ingress path
+type HTTPRuleBackend struct {
+	ServiceName string                `json:"serviceName,omitempty"`
+	ServicePort int32                 `json:"servicePort,omitempty"`
+	Weight      *int32                `json:"weight,omitempty"`
+	Match       *HTTPRuleBackendMatch `json:"match,omitempty"`
+}
+
+// HTTPRuleBackendMatch pins the requests carrying all the headers and cookies to the backend
+type HTTPRuleBackendMatch struct {
+	Headers []HTTPRuleMatchCondition `json:"headers,omitempty"`
+	Cookies []HTTPRuleMatchCondition `json:"cookies,omitempty"`
+}
+
+// HTTPRuleMatchCondition matches a request header or cookie with the exact value
+type HTTPRuleMatchCondition struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
 }
 
 // HTTPRuleLBPolicy holds a path/pool's load balancer policies
@@ -63,8 +115,16 @@ type HTTPRuleTLS struct {
 
 // HTTPRuleStatus holds the status of the HTTPRule
 type HTTPRuleStatus struct {
-	Status string `json:"status,omitempty"`
-	Error  string `json:"error,omitempty"`
+	Status      string                     `json:"status,omitempty"`
+	Error       string                     `json:"error,omitempty"`
+	PathActions []HTTPRulePathActionStatus `json:"pathActions,omitempty"`
+}
+
+// HTTPRulePathActionStatus lists the header, rewrite and redirect actions
+// configured for a target path
+type HTTPRulePathActionStatus struct {
+	Target  string   `json:"target,omitempty"`
+	Actions []string `json:"actions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
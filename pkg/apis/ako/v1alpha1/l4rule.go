@@ -0,0 +1,83 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// L4Rule is a top-level type
+type L4Rule struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Status L4RuleStatus `json:"status,omitempty"`
+
+	Spec L4RuleSpec `json:"spec,omitempty"`
+}
+
+// L4RuleSpec consists of the settings of the L4 virtualservice and pools of the
+// LoadBalancer Services referring to the L4Rule
+type L4RuleSpec struct {
+	NetworkProfile     string `json:"networkProfile,omitempty"`
+	ApplicationProfile string `json:"applicationProfile,omitempty"`
+	AnalyticsProfile   string `json:"analyticsProfile,omitempty"`
+	LoadBalancerIP     string `json:"loadBalancerIP,omitempty"`
+
+	// BackendProperties apply to the pools of all the Service ports, unless
+	// overridden for a specific port in PortProperties
+	BackendProperties L4RuleBackendProperties `json:"backendProperties,omitempty"`
+	PortProperties    []L4RulePortProperties  `json:"portProperties,omitempty"`
+}
+
+// L4RuleBackendProperties holds the load balancer policy, persistence and health
+// monitors of L4 pools
+type L4RuleBackendProperties struct {
+	LoadBalancerPolicy     L4RuleLBPolicy `json:"loadBalancerPolicy,omitempty"`
+	ApplicationPersistence string         `json:"applicationPersistence,omitempty"`
+	HealthMonitors         []string       `json:"healthMonitors,omitempty"`
+}
+
+// L4RulePortProperties holds the pool settings for a specific Service port
+type L4RulePortProperties struct {
+	Port                    int32 `json:"port,omitempty"`
+	L4RuleBackendProperties `json:",inline"`
+}
+
+// L4RuleLBPolicy holds a pool's load balancer policies
+type L4RuleLBPolicy struct {
+	Algorithm string `json:"algorithm,omitempty"`
+	Hash      string `json:"hash,omitempty"`
+}
+
+// L4RuleStatus holds the status of the L4Rule
+type L4RuleStatus struct {
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// L4RuleList has the list of L4Rule objects
+type L4RuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []L4Rule `json:"items"`
+}
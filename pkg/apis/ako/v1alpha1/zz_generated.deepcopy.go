@@ -431,7 +431,7 @@ func (in *HTTPRule) DeepCopyInto(out *HTTPRule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
@@ -503,6 +503,111 @@ func (in *HTTPRuleList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleBackend) DeepCopyInto(out *HTTPRuleBackend) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(HTTPRuleBackendMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleBackend.
+func (in *HTTPRuleBackend) DeepCopy() *HTTPRuleBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleBackendMatch) DeepCopyInto(out *HTTPRuleBackendMatch) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HTTPRuleMatchCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cookies != nil {
+		in, out := &in.Cookies, &out.Cookies
+		*out = make([]HTTPRuleMatchCondition, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleBackendMatch.
+func (in *HTTPRuleBackendMatch) DeepCopy() *HTTPRuleBackendMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleBackendMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleHeaderAction) DeepCopyInto(out *HTTPRuleHeaderAction) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleHeaderAction.
+func (in *HTTPRuleHeaderAction) DeepCopy() *HTTPRuleHeaderAction {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleHeaderAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleMatchCondition) DeepCopyInto(out *HTTPRuleMatchCondition) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleMatchCondition.
+func (in *HTTPRuleMatchCondition) DeepCopy() *HTTPRuleMatchCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleMatchCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRulePathActionStatus) DeepCopyInto(out *HTTPRulePathActionStatus) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRulePathActionStatus.
+func (in *HTTPRulePathActionStatus) DeepCopy() *HTTPRulePathActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRulePathActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRulePaths) DeepCopyInto(out *HTTPRulePaths) {
 	*out = *in
@@ -513,6 +618,28 @@ func (in *HTTPRulePaths) DeepCopyInto(out *HTTPRulePaths) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RequestHeaders != nil {
+		in, out := &in.RequestHeaders, &out.RequestHeaders
+		*out = make([]HTTPRuleHeaderAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = make([]HTTPRuleHeaderAction, len(*in))
+		copy(*out, *in)
+	}
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(HTTPRuleRedirect)
+		**out = **in
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]HTTPRuleBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -526,6 +653,22 @@ func (in *HTTPRulePaths) DeepCopy() *HTTPRulePaths {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleRedirect) DeepCopyInto(out *HTTPRuleRedirect) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleRedirect.
+func (in *HTTPRuleRedirect) DeepCopy() *HTTPRuleRedirect {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleRedirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRuleSpec) DeepCopyInto(out *HTTPRuleSpec) {
 	*out = *in
@@ -552,6 +695,13 @@ func (in *HTTPRuleSpec) DeepCopy() *HTTPRuleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRuleStatus) DeepCopyInto(out *HTTPRuleStatus) {
 	*out = *in
+	if in.PathActions != nil {
+		in, out := &in.PathActions, &out.PathActions
+		*out = make([]HTTPRulePathActionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -586,8 +736,8 @@ func (in *HostRule) DeepCopyInto(out *HostRule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -635,9 +785,51 @@ func (in *HostRuleAnalyticsPolicy) DeepCopy() *HostRuleAnalyticsPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleClientAuth) DeepCopyInto(out *HostRuleClientAuth) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HostRuleClientAuthHeader, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleClientAuth.
+func (in *HostRuleClientAuth) DeepCopy() *HostRuleClientAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleClientAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleClientAuthHeader) DeepCopyInto(out *HostRuleClientAuthHeader) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleClientAuthHeader.
+func (in *HostRuleClientAuthHeader) DeepCopy() *HostRuleClientAuthHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleClientAuthHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleGSLB) DeepCopyInto(out *HostRuleGSLB) {
 	*out = *in
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(HostRuleGSLBService)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -651,6 +843,69 @@ func (in *HostRuleGSLB) DeepCopy() *HostRuleGSLB {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleGSLBMemberStatus) DeepCopyInto(out *HostRuleGSLBMemberStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleGSLBMemberStatus.
+func (in *HostRuleGSLBMemberStatus) DeepCopy() *HostRuleGSLBMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleGSLBMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleGSLBService) DeepCopyInto(out *HostRuleGSLBService) {
+	*out = *in
+	if in.HealthMonitors != nil {
+		in, out := &in.HealthMonitors, &out.HealthMonitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleGSLBService.
+func (in *HostRuleGSLBService) DeepCopy() *HostRuleGSLBService {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleGSLBService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleGSLBStatus) DeepCopyInto(out *HostRuleGSLBStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]HostRuleGSLBMemberStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleGSLBStatus.
+func (in *HostRuleGSLBStatus) DeepCopy() *HostRuleGSLBStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleGSLBStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleHTTPPolicy) DeepCopyInto(out *HostRuleHTTPPolicy) {
 	*out = *in
@@ -705,6 +960,69 @@ func (in *HostRuleList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleOCSPStapling) DeepCopyInto(out *HostRuleOCSPStapling) {
+	*out = *in
+	if in.ResponderURLs != nil {
+		in, out := &in.ResponderURLs, &out.ResponderURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleOCSPStapling.
+func (in *HostRuleOCSPStapling) DeepCopy() *HostRuleOCSPStapling {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleOCSPStapling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleRateLimit) DeepCopyInto(out *HostRuleRateLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleRateLimit.
+func (in *HostRuleRateLimit) DeepCopy() *HostRuleRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleRateLimits) DeepCopyInto(out *HostRuleRateLimits) {
+	*out = *in
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(HostRuleRateLimit)
+		**out = **in
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = new(HostRuleRateLimit)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleRateLimits.
+func (in *HostRuleRateLimits) DeepCopy() *HostRuleRateLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleRateLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleSecret) DeepCopyInto(out *HostRuleSecret) {
 	*out = *in
@@ -721,6 +1039,47 @@ func (in *HostRuleSecret) DeepCopy() *HostRuleSecret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleSecurity) DeepCopyInto(out *HostRuleSecurity) {
+	*out = *in
+	if in.RateLimits != nil {
+		in, out := &in.RateLimits, &out.RateLimits
+		*out = new(HostRuleRateLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedCIDRs != nil {
+		in, out := &in.DeniedCIDRs, &out.DeniedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedIPGroups != nil {
+		in, out := &in.AllowedIPGroups, &out.AllowedIPGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedIPGroups != nil {
+		in, out := &in.DeniedIPGroups, &out.DeniedIPGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleSecurity.
+func (in *HostRuleSecurity) DeepCopy() *HostRuleSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleSpec) DeepCopyInto(out *HostRuleSpec) {
 	*out = *in
@@ -741,6 +1100,11 @@ func (in *HostRuleSpec) DeepCopy() *HostRuleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleStatus) DeepCopyInto(out *HostRuleStatus) {
 	*out = *in
+	if in.Gslb != nil {
+		in, out := &in.Gslb, &out.Gslb
+		*out = new(HostRuleGSLBStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -795,6 +1159,16 @@ func (in *HostRuleTCPSettings) DeepCopy() *HostRuleTCPSettings {
 func (in *HostRuleTLS) DeepCopyInto(out *HostRuleTLS) {
 	*out = *in
 	out.SSLKeyCertificate = in.SSLKeyCertificate
+	if in.OCSPStapling != nil {
+		in, out := &in.OCSPStapling, &out.OCSPStapling
+		*out = new(HostRuleOCSPStapling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientAuth != nil {
+		in, out := &in.ClientAuth, &out.ClientAuth
+		*out = new(HostRuleClientAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -822,8 +1196,8 @@ func (in *HostRuleVirtualHost) DeepCopyInto(out *HostRuleVirtualHost) {
 		**out = **in
 	}
 	in.HTTPPolicy.DeepCopyInto(&out.HTTPPolicy)
-	out.Gslb = in.Gslb
-	out.TLS = in.TLS
+	in.Gslb.DeepCopyInto(&out.Gslb)
+	in.TLS.DeepCopyInto(&out.TLS)
 	if in.AnalyticsPolicy != nil {
 		in, out := &in.AnalyticsPolicy, &out.AnalyticsPolicy
 		*out = new(HostRuleAnalyticsPolicy)
@@ -839,6 +1213,11 @@ func (in *HostRuleVirtualHost) DeepCopyInto(out *HostRuleVirtualHost) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(HostRuleSecurity)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -884,6 +1263,162 @@ func (in *IngressStatus) DeepCopy() *IngressStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4Rule) DeepCopyInto(out *L4Rule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4Rule.
+func (in *L4Rule) DeepCopy() *L4Rule {
+	if in == nil {
+		return nil
+	}
+	out := new(L4Rule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *L4Rule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RuleBackendProperties) DeepCopyInto(out *L4RuleBackendProperties) {
+	*out = *in
+	out.LoadBalancerPolicy = in.LoadBalancerPolicy
+	if in.HealthMonitors != nil {
+		in, out := &in.HealthMonitors, &out.HealthMonitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RuleBackendProperties.
+func (in *L4RuleBackendProperties) DeepCopy() *L4RuleBackendProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RuleBackendProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RuleLBPolicy) DeepCopyInto(out *L4RuleLBPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RuleLBPolicy.
+func (in *L4RuleLBPolicy) DeepCopy() *L4RuleLBPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RuleLBPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RuleList) DeepCopyInto(out *L4RuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]L4Rule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RuleList.
+func (in *L4RuleList) DeepCopy() *L4RuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *L4RuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RulePortProperties) DeepCopyInto(out *L4RulePortProperties) {
+	*out = *in
+	in.L4RuleBackendProperties.DeepCopyInto(&out.L4RuleBackendProperties)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RulePortProperties.
+func (in *L4RulePortProperties) DeepCopy() *L4RulePortProperties {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RulePortProperties)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RuleSpec) DeepCopyInto(out *L4RuleSpec) {
+	*out = *in
+	in.BackendProperties.DeepCopyInto(&out.BackendProperties)
+	if in.PortProperties != nil {
+		in, out := &in.PortProperties, &out.PortProperties
+		*out = make([]L4RulePortProperties, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RuleSpec.
+func (in *L4RuleSpec) DeepCopy() *L4RuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RuleStatus) DeepCopyInto(out *L4RuleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RuleStatus.
+func (in *L4RuleStatus) DeepCopy() *L4RuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancer) DeepCopyInto(out *LoadBalancer) {
 	*out = *in
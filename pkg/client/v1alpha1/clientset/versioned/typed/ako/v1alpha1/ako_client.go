@@ -30,6 +30,7 @@ type AkoV1alpha1Interface interface {
 	ClusterSetsGetter
 	HTTPRulesGetter
 	HostRulesGetter
+	L4RulesGetter
 	MultiClusterIngressesGetter
 	ServiceImportsGetter
 }
@@ -55,6 +56,10 @@ func (c *AkoV1alpha1Client) HostRules(namespace string) HostRuleInterface {
 	return newHostRules(c, namespace)
 }
 
+func (c *AkoV1alpha1Client) L4Rules(namespace string) L4RuleInterface {
+	return newL4Rules(c, namespace)
+}
+
 func (c *AkoV1alpha1Client) MultiClusterIngresses(namespace string) MultiClusterIngressInterface {
 	return newMultiClusterIngresses(c, namespace)
 }
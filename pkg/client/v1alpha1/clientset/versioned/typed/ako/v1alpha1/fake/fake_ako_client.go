@@ -44,6 +44,10 @@ func (c *FakeAkoV1alpha1) HostRules(namespace string) v1alpha1.HostRuleInterface
 	return &FakeHostRules{c, namespace}
 }
 
+func (c *FakeAkoV1alpha1) L4Rules(namespace string) v1alpha1.L4RuleInterface {
+	return &FakeL4Rules{c, namespace}
+}
+
 func (c *FakeAkoV1alpha1) MultiClusterIngresses(namespace string) v1alpha1.MultiClusterIngressInterface {
 	return &FakeMultiClusterIngresses{c, namespace}
 }
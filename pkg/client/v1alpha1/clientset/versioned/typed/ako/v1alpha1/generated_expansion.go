@@ -26,6 +26,8 @@ type HTTPRuleExpansion interface{}
 
 type HostRuleExpansion interface{}
 
+type L4RuleExpansion interface{}
+
 type MultiClusterIngressExpansion interface{}
 
 type ServiceImportExpansion interface{}
@@ -32,6 +32,8 @@ type Interface interface {
 	HTTPRules() HTTPRuleInformer
 	// HostRules returns a HostRuleInformer.
 	HostRules() HostRuleInformer
+	// L4Rules returns a L4RuleInformer.
+	L4Rules() L4RuleInformer
 	// MultiClusterIngresses returns a MultiClusterIngressInformer.
 	MultiClusterIngresses() MultiClusterIngressInformer
 	// ServiceImports returns a ServiceImportInformer.
@@ -69,6 +71,11 @@ func (v *version) HostRules() HostRuleInformer {
 	return &hostRuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// L4Rules returns a L4RuleInformer.
+func (v *version) L4Rules() L4RuleInformer {
+	return &l4RuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // MultiClusterIngresses returns a MultiClusterIngressInformer.
 func (v *version) MultiClusterIngresses() MultiClusterIngressInformer {
 	return &multiClusterIngressInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
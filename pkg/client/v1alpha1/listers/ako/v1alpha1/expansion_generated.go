@@ -46,6 +46,14 @@ type HostRuleListerExpansion interface{}
 // HostRuleNamespaceLister.
 type HostRuleNamespaceListerExpansion interface{}
 
+// L4RuleListerExpansion allows custom methods to be added to
+// L4RuleLister.
+type L4RuleListerExpansion interface{}
+
+// L4RuleNamespaceListerExpansion allows custom methods to be added to
+// L4RuleNamespaceLister.
+type L4RuleNamespaceListerExpansion interface{}
+
 // MultiClusterIngressListerExpansion allows custom methods to be added to
 // MultiClusterIngressLister.
 type MultiClusterIngressListerExpansion interface{}
@@ -0,0 +1,301 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/session"
+
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+const (
+	CircuitClosed   = "CLOSED"
+	CircuitOpen     = "OPEN"
+	CircuitHalfOpen = "HALF_OPEN"
+
+	// Number of consecutive overload errors from the Avi controller after which the circuit is opened.
+	circuitFailureThreshold = 5
+	// Time for which the circuit is kept open, before a probe request is let through.
+	circuitOpenDuration = 30 * time.Second
+
+	aviRestBackoffBase = 500 * time.Millisecond
+	aviRestBackoffMax  = 30 * time.Second
+	// Number of times a request rejected with 429 or 503 is retried by the limiter.
+	aviRestMaxAttempts = 3
+)
+
+// ErrAviCircuitOpen is returned for the rest requests which are not sent to the Avi controller, since the
+// circuit is open after the controller failed to respond to the previous requests.
+var ErrAviCircuitOpen = errors.New("circuit breaker is open for the Avi controller, rest request not sent")
+
+var aviRestStatusCodeRegex = regexp.MustCompile(`Rest request error.*status code: (\d+)`)
+
+// AviRestLimiterStatus is the state of the limiter, as shown by the /api/status endpoint.
+type AviRestLimiterStatus struct {
+	CircuitState        string     `json:"circuit_state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	QPS                 float32    `json:"qps"`
+	MaxConcurrency      int        `json:"max_concurrency"`
+	InFlight            int        `json:"in_flight"`
+	BackoffUntil        *time.Time `json:"backoff_until,omitempty"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+}
+
+// AviRestLimiter limits the rate and the concurrency of the rest requests sent to the Avi controller, backs off
+// when the controller is overloaded, and stops sending requests for a while when the controller keeps failing.
+// The limiter is shared by all the sessions of the client pool, since they all talk to the same controller.
+type AviRestLimiter struct {
+	rateLimiter    flowcontrol.RateLimiter
+	qps            float32
+	concurrency    chan struct{}
+	maxConcurrency int
+
+	lock                sync.Mutex
+	state               string
+	consecutiveFailures int
+	backoffUntil        time.Time
+	openedAt            time.Time
+	probeInFlight       bool
+	// bulkSyncs is the number of syncs of the cache in progress, during which the circuit is not checked.
+	bulkSyncs int
+}
+
+var aviRestLimiterInstance *AviRestLimiter
+var aviRestLimiterOnce sync.Once
+
+// SharedAviRestLimiter returns the limiter of the Avi controller, which does not limit the requests until
+// Configure is called.
+func SharedAviRestLimiter() *AviRestLimiter {
+	aviRestLimiterOnce.Do(func() {
+		aviRestLimiterInstance = &AviRestLimiter{
+			rateLimiter: flowcontrol.NewFakeAlwaysRateLimiter(),
+			state:       CircuitClosed,
+		}
+		SetAviRestCircuitState(CircuitClosed)
+	})
+	return aviRestLimiterInstance
+}
+
+// Configure sets the number of requests per second and the number of concurrent requests allowed to the
+// Avi controller. A value of 0 disables the respective limit.
+func (l *AviRestLimiter) Configure(qps float32, burst, maxConcurrency int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if qps > 0 {
+		if burst <= 0 {
+			burst = int(qps)
+			if burst < 1 {
+				burst = 1
+			}
+		}
+		l.rateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+	} else {
+		l.rateLimiter = flowcontrol.NewFakeAlwaysRateLimiter()
+	}
+	l.qps = qps
+	l.maxConcurrency = maxConcurrency
+	l.concurrency = nil
+	if maxConcurrency > 0 {
+		l.concurrency = make(chan struct{}, maxConcurrency)
+	}
+	AviLog.Infof("Avi rest requests limited to %v per second and %d concurrent requests, 0 means unlimited", qps, maxConcurrency)
+}
+
+// Do executes the rest request call within the limits. Requests rejected by the Avi controller with 429 or 503
+// are retried with an exponential backoff, other overload errors, such as timeouts, are returned to the caller
+// since the request may have been processed by the controller.
+func (l *AviRestLimiter) Do(call func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = l.allow(); err != nil {
+			return err
+		}
+		l.waitForBackoff()
+
+		l.lock.Lock()
+		rateLimiter, concurrency := l.rateLimiter, l.concurrency
+		l.lock.Unlock()
+		rateLimiter.Accept()
+		if concurrency != nil {
+			concurrency <- struct{}{}
+		}
+		err = call()
+		if concurrency != nil {
+			<-concurrency
+		}
+
+		overloaded, retryable := classifyAviRestError(err)
+		l.record(overloaded)
+		if !retryable || attempt >= aviRestMaxAttempts {
+			return err
+		}
+		AviLog.Warnf("Avi controller is overloaded, retrying the rest request, attempt: %d, err: %v", attempt, err)
+	}
+}
+
+// BeginBulkSync exempts the rest requests from the circuit breaker while the cache is synced from the Avi
+// controller, until EndBulkSync is called. A failed sync is retried as a whole by its caller, hence the requests of
+// the sync neither wait for an open circuit nor open it. They are still rate limited and backed off.
+func (l *AviRestLimiter) BeginBulkSync() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.bulkSyncs++
+}
+
+// EndBulkSync ends the exemption of BeginBulkSync.
+func (l *AviRestLimiter) EndBulkSync() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.bulkSyncs > 0 {
+		l.bulkSyncs--
+	}
+}
+
+// allow checks the circuit, and lets a single probe request through once the circuit has been open for
+// circuitOpenDuration.
+func (l *AviRestLimiter) allow() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.bulkSyncs > 0 {
+		return nil
+	}
+	switch l.state {
+	case CircuitOpen:
+		if time.Since(l.openedAt) < circuitOpenDuration {
+			return ErrAviCircuitOpen
+		}
+		AviLog.Infof("Avi rest circuit breaker is half open, sending a probe request")
+		l.state = CircuitHalfOpen
+		l.probeInFlight = true
+		SetAviRestCircuitState(CircuitHalfOpen)
+	case CircuitHalfOpen:
+		if l.probeInFlight {
+			return ErrAviCircuitOpen
+		}
+		l.probeInFlight = true
+	}
+	return nil
+}
+
+func (l *AviRestLimiter) waitForBackoff() {
+	l.lock.Lock()
+	wait := time.Until(l.backoffUntil)
+	l.lock.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// record updates the backoff and the circuit with the result of a request.
+func (l *AviRestLimiter) record(overloaded bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.probeInFlight = false
+	if !overloaded {
+		if l.state != CircuitClosed {
+			AviLog.Infof("Avi controller is responding, closing the rest circuit breaker")
+			l.state = CircuitClosed
+			SetAviRestCircuitState(CircuitClosed)
+		}
+		l.consecutiveFailures = 0
+		l.backoffUntil = time.Time{}
+		return
+	}
+
+	l.consecutiveFailures++
+	backoff := aviRestBackoffBase << uint(l.consecutiveFailures-1)
+	if backoff > aviRestBackoffMax || backoff <= 0 {
+		backoff = aviRestBackoffMax
+	}
+	// Full jitter, so that the AKO instances sharing the controller don't retry in lockstep.
+	backoff = time.Duration(rand.Int63n(int64(backoff)) + 1)
+	l.backoffUntil = time.Now().Add(backoff)
+
+	if l.bulkSyncs > 0 {
+		return
+	}
+	if l.state == CircuitHalfOpen || l.consecutiveFailures >= circuitFailureThreshold {
+		if l.state != CircuitOpen {
+			AviLog.Warnf("Avi controller failed %d consecutive rest requests, opening the rest circuit breaker for %v", l.consecutiveFailures, circuitOpenDuration)
+			SetAviRestCircuitState(CircuitOpen)
+		}
+		l.state = CircuitOpen
+		l.openedAt = time.Now()
+	}
+}
+
+// Status returns the current state of the limiter.
+func (l *AviRestLimiter) Status() AviRestLimiterStatus {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	status := AviRestLimiterStatus{
+		CircuitState:        l.state,
+		ConsecutiveFailures: l.consecutiveFailures,
+		QPS:                 l.qps,
+		MaxConcurrency:      l.maxConcurrency,
+	}
+	if l.state != CircuitClosed {
+		openedAt := l.openedAt
+		status.OpenedAt = &openedAt
+	}
+	if l.concurrency != nil {
+		status.InFlight = len(l.concurrency)
+	}
+	if time.Now().Before(l.backoffUntil) {
+		backoffUntil := l.backoffUntil
+		status.BackoffUntil = &backoffUntil
+	}
+	return status
+}
+
+// classifyAviRestError returns whether the error indicates that the Avi controller is overloaded, and whether the
+// request can be safely retried, i.e. the controller rejected it without processing it. Only 429, 503 and 504
+// responses and timeouts are overload signals, other errors open neither the backoff nor the circuit. The Avi
+// sessions of AKO don't check the controller status, hence 5xx responses are returned as a "Rest request error"
+// carrying the status code, and transport errors are wrapped in it.
+func classifyAviRestError(err error) (bool, bool) {
+	if err == nil {
+		return false, false
+	}
+	statusCode := 0
+	if aviErr, ok := err.(session.AviError); ok {
+		statusCode = aviErr.HttpStatusCode
+	} else if matches := aviRestStatusCodeRegex.FindStringSubmatch(err.Error()); len(matches) == 2 {
+		statusCode, _ = strconv.Atoi(matches[1])
+	}
+	switch statusCode {
+	case 429, 503:
+		return true, true
+	case 504:
+		return true, false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true, false
+	}
+	if strings.Contains(err.Error(), "Client.Timeout") {
+		return true, false
+	}
+	return false, false
+}
@@ -27,6 +27,7 @@ const (
 	SecretInformer                = "SecretInformer"
 	NodeInformer                  = "NodeInformer"
 	EndpointInformer              = "EndpointInformer"
+	EndpointSlicesInformer        = "EndpointSlicesInformer"
 	ConfigMapInformer             = "ConfigMapInformer"
 	MultiClusterIngressInformer   = "MultiClusterIngressInformer"
 	ServiceImportInformer         = "ServiceImportInformer"
@@ -34,6 +35,7 @@ const (
 	K8S_TLS_SECRET_KEY            = "tls.key"
 	K8S_TLS_SECRET_ALT_CERT       = "alt.crt"
 	K8S_TLS_SECRET_ALT_KEY        = "alt.key"
+	K8S_TLS_SECRET_CA             = "ca.crt"
 	IngressInformer               = "IngressInformer"
 	RouteInformer                 = "RouteInformer"
 	IngressClassInformer          = "IngressClassInformer"
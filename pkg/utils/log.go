@@ -16,6 +16,7 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -91,6 +92,25 @@ func (aviLogger *AviLogger) SetLevel(l string) {
 	aviLogger.atom.SetLevel(LogLevelMap[l])
 }
 
+// SetOutput changes the destination of the logs, which are otherwise written to stdout or to the log file in the PVC.
+func (aviLogger *AviLogger) SetOutput(w io.Writer) {
+	logger := zap.New(zapcore.NewCore(
+		zapcore.NewConsoleEncoder(newEncoderConfig()),
+		zapcore.Lock(zapcore.AddSync(w)),
+		aviLogger.atom,
+	), zap.AddCaller(), zap.AddCallerSkip(1))
+	aviLogger.logger = logger
+	aviLogger.sugar = logger.Sugar()
+}
+
+func newEncoderConfig() zapcore.EncoderConfig {
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder // colored capital case LEVEL
+	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder        // format 2020-05-08T03:26:08.943+0530
+	encoderCfg.EncodeCaller = zapcore.ShortCallerEncoder      // caller format package_name/filename.go
+	return encoderCfg
+}
+
 // log file sample name /log/ako-12345.avi.log
 func getFileName() string {
 	input := os.Getenv("LOG_FILE_NAME")
@@ -122,10 +142,7 @@ func init() {
 
 	usePVC := os.Getenv("USE_PVC")
 
-	encoderCfg := zap.NewProductionEncoderConfig()
-	encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder // colored capital case LEVEL
-	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder        // format 2020-05-08T03:26:08.943+0530
-	encoderCfg.EncodeCaller = zapcore.ShortCallerEncoder      // caller format package_name/filename.go
+	encoderCfg := newEncoderConfig()
 
 	if usePVC != "true" {
 		logger := zap.New(zapcore.NewCore(
@@ -0,0 +1,329 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	metricsNamespace = "ako"
+
+	// AviRestStatusSuccess is the status code label for the Avi rest calls which succeeded, since the
+	// exact status code of a successful call is not returned by the Avi session.
+	AviRestStatusSuccess = "2xx"
+	// AviRestStatusError is the status code label for the Avi rest calls which failed without a
+	// response from the Avi controller, e.g. timeouts.
+	AviRestStatusError = "error"
+)
+
+// MetricsRegistry holds all the metrics exposed by the /metrics endpoint of the API server.
+var MetricsRegistry = prometheus.NewRegistry()
+
+var (
+	workqueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "depth",
+		Help:      "Current number of keys waiting in the workqueue.",
+	}, []string{"name"})
+	workqueueAdds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "adds_total",
+		Help:      "Total number of keys added to the workqueue.",
+	}, []string{"name"})
+	workqueueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "queue_duration_seconds",
+		Help:      "Time in seconds a key stays in the workqueue before being processed.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"name"})
+	workqueueWorkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "work_duration_seconds",
+		Help:      "Time in seconds taken to process a key from the workqueue.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"name"})
+	workqueueUnfinishedWork = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "unfinished_work_seconds",
+		Help:      "Time in seconds the keys being processed have been in progress. A large value indicates a stuck worker.",
+	}, []string{"name"})
+	workqueueLongestRunning = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "longest_running_processor_seconds",
+		Help:      "Time in seconds the longest running worker of the workqueue has been processing a key.",
+	}, []string{"name"})
+	workqueueRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "retries_total",
+		Help:      "Total number of keys re-added to the workqueue with rate limiting.",
+	}, []string{"name"})
+
+	aviRestRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "avi_rest",
+		Name:      "requests_total",
+		Help:      "Total number of rest calls to the Avi controller, by object type, method and status code.",
+	}, []string{"model", "method", "status_code"})
+	aviRestLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "avi_rest",
+		Name:      "request_duration_seconds",
+		Help:      "Time in seconds taken by the rest calls to the Avi controller, by object type and method.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"model", "method"})
+
+	aviRestCircuitState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "avi_rest",
+		Name:      "circuit_state",
+		Help:      "State of the circuit breaker for the rest calls to the Avi controller, 1 for the current state.",
+	}, []string{"state"})
+
+	retryPublishes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "retry",
+		Name:      "publishes_total",
+		Help:      "Total number of keys published to the retry layers.",
+	}, []string{"queue"})
+
+	fullSyncDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "full_sync",
+		Name:      "duration_seconds",
+		Help:      "Time in seconds taken by the periodic full sync with the Avi controller.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12),
+	})
+	fullSyncLastTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "full_sync",
+		Name:      "last_completion_timestamp_seconds",
+		Help:      "Unix time at which the last full sync with the Avi controller completed.",
+	})
+
+	certificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "certificate",
+		Name:      "expiry_timestamp_seconds",
+		Help:      "Unix time at which the certificates served by AKO expire, by Avi SSLKeyAndCertificate and Secret.",
+	}, []string{"name", "secret"})
+	certificateAlerts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "certificate",
+		Name:      "alerts",
+		Help:      "Number of certificates served by AKO found expiring, expired or not rotated by the last expiry check, by reason.",
+	}, []string{"reason"})
+
+	leader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "leader",
+		Help:      "1 if this AKO replica is the elected leader syncing the objects to the Avi controller, 0 for a standby replica.",
+	})
+)
+
+// The unfinished work and longest running processor gauges are set periodically by every workqueue of a layer,
+// hence these are aggregated across the workqueues, instead of being overwritten by the last workqueue.
+var (
+	workqueueUnfinishedWorkGauges = newAggregatedGaugeVec(workqueueUnfinishedWork, func(values []float64) float64 {
+		var sum float64
+		for _, value := range values {
+			sum += value
+		}
+		return sum
+	})
+	workqueueLongestRunningGauges = newAggregatedGaugeVec(workqueueLongestRunning, func(values []float64) float64 {
+		var max float64
+		for _, value := range values {
+			if value > max {
+				max = value
+			}
+		}
+		return max
+	})
+)
+
+func init() {
+	MetricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		workqueueDepth,
+		workqueueAdds,
+		workqueueLatency,
+		workqueueWorkDuration,
+		workqueueUnfinishedWork,
+		workqueueLongestRunning,
+		workqueueRetries,
+		aviRestRequests,
+		aviRestLatency,
+		aviRestCircuitState,
+		retryPublishes,
+		fullSyncDuration,
+		fullSyncLastTimestamp,
+		certificateExpiry,
+		certificateAlerts,
+		leader,
+	)
+	// The provider has to be set before the workqueues are created, the queues created earlier don't report metrics.
+	workqueue.SetProvider(workqueueMetricsProvider{})
+}
+
+// workqueueMetricsProvider implements workqueue.MetricsProvider. All the workqueues of a layer share the
+// name of the layer, hence the metrics of a layer are aggregated across its workers.
+type workqueueMetricsProvider struct{}
+
+func (workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return workqueueDepth.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return workqueueAdds.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return workqueueLatency.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return workqueueWorkDuration.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return workqueueUnfinishedWorkGauges.newGauge(name)
+}
+
+func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return workqueueLongestRunningGauges.newGauge(name)
+}
+
+func (workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return workqueueRetries.WithLabelValues(name)
+}
+
+// aggregatedGaugeVec sets the gauge of a workqueue name to the aggregate of the values set by all the workqueues
+// sharing the name.
+type aggregatedGaugeVec struct {
+	lock      sync.Mutex
+	vec       *prometheus.GaugeVec
+	values    map[string][]float64
+	aggregate func(values []float64) float64
+}
+
+func newAggregatedGaugeVec(vec *prometheus.GaugeVec, aggregate func(values []float64) float64) *aggregatedGaugeVec {
+	return &aggregatedGaugeVec{
+		vec:       vec,
+		values:    make(map[string][]float64),
+		aggregate: aggregate,
+	}
+}
+
+// newGauge returns the gauge of a new workqueue with the name.
+func (v *aggregatedGaugeVec) newGauge(name string) workqueue.SettableGaugeMetric {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.values[name] = append(v.values[name], 0)
+	return &aggregatedGauge{vec: v, name: name, index: len(v.values[name]) - 1}
+}
+
+// aggregatedGauge is the gauge of a single workqueue, in an aggregatedGaugeVec.
+type aggregatedGauge struct {
+	vec   *aggregatedGaugeVec
+	name  string
+	index int
+}
+
+func (g *aggregatedGauge) Set(value float64) {
+	g.vec.lock.Lock()
+	defer g.vec.lock.Unlock()
+	values := g.vec.values[g.name]
+	values[g.index] = value
+	g.vec.vec.WithLabelValues(g.name).Set(g.vec.aggregate(values))
+}
+
+// ObserveAviRestCall records a rest call to the Avi controller. statusCode is the http status code of a
+// failed call, 0 for a successful call and -1 for a call which failed without a response.
+func ObserveAviRestCall(model, method string, statusCode int, duration time.Duration) {
+	status := AviRestStatusSuccess
+	if statusCode > 0 {
+		status = strconv.Itoa(statusCode)
+	} else if statusCode < 0 {
+		status = AviRestStatusError
+	}
+	aviRestRequests.WithLabelValues(model, method, status).Inc()
+	aviRestLatency.WithLabelValues(model, method).Observe(duration.Seconds())
+}
+
+// SetAviRestCircuitState records the current state of the circuit breaker for the Avi rest calls.
+func SetAviRestCircuitState(state string) {
+	for _, s := range []string{CircuitClosed, CircuitOpen, CircuitHalfOpen} {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		aviRestCircuitState.WithLabelValues(s).Set(value)
+	}
+}
+
+// IncRetryPublishes records a key published to the retry layer queueName.
+func IncRetryPublishes(queueName string) {
+	retryPublishes.WithLabelValues(queueName).Inc()
+}
+
+// ObserveFullSync records the duration of a full sync with the Avi controller.
+func ObserveFullSync(duration time.Duration) {
+	fullSyncDuration.Observe(duration.Seconds())
+	fullSyncLastTimestamp.SetToCurrentTime()
+}
+
+// SetCertificateExpiry records the expiry of the certificates served by AKO, replacing the earlier records. expiry
+// maps the name of the Avi SSLKeyAndCertificate to its Secret, and alerts maps the alert reasons to the number of
+// certificates alerted.
+func SetCertificateExpiry(expiry map[string]CertificateExpiry, alerts map[string]int) {
+	certificateExpiry.Reset()
+	for name, cert := range expiry {
+		certificateExpiry.WithLabelValues(name, cert.Secret).Set(float64(cert.NotAfter.Unix()))
+	}
+	certificateAlerts.Reset()
+	for reason, count := range alerts {
+		certificateAlerts.WithLabelValues(reason).Set(float64(count))
+	}
+}
+
+// CertificateExpiry is the expiry of a certificate served by AKO, and the namespace/name of its Secret.
+type CertificateExpiry struct {
+	Secret   string
+	NotAfter time.Time
+}
+
+// SetAKOLeader records whether this AKO replica is the elected leader.
+func SetAKOLeader(isLeader bool) {
+	value := 0.0
+	if isLeader {
+		value = 1
+	}
+	leader.Set(value)
+}
@@ -23,6 +23,8 @@ import (
 
 var onlyOneSignalHandler = make(chan struct{})
 
+var shutdownSignalCh = make(chan os.Signal, 2)
+
 // SetupSignalHandler registered for SIGTERM and SIGINT. A stop channel is returned
 // which is closed on one of these signals. If a second signal is caught, the program
 // is terminated with exit code 1.
@@ -30,7 +32,7 @@ func SetupSignalHandler() (stopCh <-chan struct{}) {
 	close(onlyOneSignalHandler) // panics when called twice
 
 	stop := make(chan struct{})
-	c := make(chan os.Signal, 2)
+	c := shutdownSignalCh
 	signal.Notify(c, shutdownSignals...)
 	go func() {
 		<-c
@@ -41,3 +43,12 @@ func SetupSignalHandler() (stopCh <-chan struct{}) {
 
 	return stop
 }
+
+// RequestShutdown shuts AKO down the same way as SIGTERM does, by closing the stop channel returned by
+// SetupSignalHandler.
+func RequestShutdown() {
+	select {
+	case shutdownSignalCh <- os.Interrupt:
+	default:
+	}
+}
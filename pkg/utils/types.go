@@ -23,6 +23,7 @@ import (
 	oshiftinformers "github.com/openshift/client-go/route/informers/externalversions/route/v1"
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
 	netinformers "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/kubernetes"
 
@@ -74,6 +75,7 @@ type Informers struct {
 	ConfigMapInformer           coreinformers.ConfigMapInformer
 	ServiceInformer             coreinformers.ServiceInformer
 	EpInformer                  coreinformers.EndpointsInformer
+	EpSlicesInformer            discoveryinformers.EndpointSliceInformer
 	PodInformer                 coreinformers.PodInformer
 	NSInformer                  coreinformers.NamespaceInformer
 	SecretInformer              coreinformers.SecretInformer
@@ -215,6 +217,7 @@ type AviObjectMarkers struct {
 	Protocol         string
 	IngressName      []string
 	GatewayName      string
+	IstioGateway     string
 }
 
 /*
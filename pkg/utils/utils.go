@@ -182,6 +182,8 @@ func instantiateInformers(kubeClient KubeClientIntf, registeredInformers []strin
 			informers.PodInformer = kubeInformerFactory.Core().V1().Pods()
 		case EndpointInformer:
 			informers.EpInformer = kubeInformerFactory.Core().V1().Endpoints()
+		case EndpointSlicesInformer:
+			informers.EpSlicesInformer = kubeInformerFactory.Discovery().V1().EndpointSlices()
 		case SecretInformer:
 			if akoNSBoundInformer {
 				informers.SecretInformer = akoNSInformerFactory.Core().V1().Secrets()
@@ -382,6 +384,9 @@ func GetNSFilter(obj *K8ValidNamespaces) (string, string) {
 func AddNamespaceToFilter(namespace string) {
 	globalNSFilterObj.validNSList.lock.Lock()
 	defer globalNSFilterObj.validNSList.lock.Unlock()
+	if globalNSFilterObj.validNSList.nsList == nil {
+		globalNSFilterObj.validNSList.nsList = make(map[string]struct{})
+	}
 	globalNSFilterObj.validNSList.nsList[namespace] = struct{}{}
 }
 
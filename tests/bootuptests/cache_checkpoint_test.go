@@ -0,0 +1,135 @@
+package bootuptests
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+)
+
+// injectMWForCacheSync serves the bootup mocks, and records the requests fetching the pools. If modifiedPool is set,
+// the listing of the pools returns a newer _last_modified for it.
+func injectMWForCacheSync(lock *sync.Mutex, poolFetches *[]string, modifiedPool *string) {
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "GET" && strings.Trim(url, "/") == "api/cluster" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"uuid": "cluster-6b3d8b8e-2d5f-4c6c-9a4e-0c8a6c1c4f11"}`))
+		} else if r.Method == "GET" && strings.Contains(url, "/api/cloud/") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"count": 1, "results": [{"name": "CLOUD_VCENTER", "uuid": "cloud-0", "vtype": "CLOUD_VCENTER"}]}`))
+		} else if r.Method == "GET" && strings.Trim(url, "/") == "api/pool" {
+			lock.Lock()
+			defer lock.Unlock()
+			if !strings.Contains(r.URL.RawQuery, "_last_modified") {
+				*poolFetches = append(*poolFetches, r.URL.RawQuery)
+			} else if *modifiedPool != "" {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"count": 2, "results": [` +
+					`{"uuid": "pool-11a38043-e51e-4c93-8187-b390d7d81abd", "_last_modified": "1577342976851289"},` +
+					`{"uuid": "` + *modifiedPool + `", "_last_modified": "1677352610869205"}]}`))
+				return
+			}
+			integrationtest.FeedMockCollectionData(w, r, mockFilePath)
+		} else if r.Method == "GET" {
+			integrationtest.FeedMockCollectionData(w, r, mockFilePath)
+		} else if strings.Contains(url, "login") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": "true"}`))
+		} else if strings.Contains(url, "initial-data") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"version": {"Version": "20.1.2"}}`))
+		}
+	})
+}
+
+// The cache of a restarted AKO is synced from the checkpoint persisted in the AKO volume, and only the objects
+// modified after the checkpoint are fetched.
+func TestIncrementalCacheSync(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	checkpointDir := t.TempDir()
+	os.Setenv("USE_PVC", "true")
+	os.Setenv("LOG_FILE_PATH", checkpointDir)
+	defer os.Unsetenv("USE_PVC")
+	defer os.Unsetenv("LOG_FILE_PATH")
+
+	var lock sync.Mutex
+	var poolFetches []string
+	var modifiedPool string
+	injectMWForCacheSync(&lock, &poolFetches, &modifiedPool)
+	defer integrationtest.ResetMiddleware()
+	k8s.PopulateControllerProperties(KubeClient)
+	aviClients := cache.SharedAVIClients()
+	g.Expect(cache.SetControllerClusterUUID(aviClients)).To(gomega.Succeed())
+
+	// The first sync fetches all the pools, and saves the checkpoint.
+	aviObjCache := cache.NewAviObjCache()
+	aviObjCache.AviObjCachePopulate(aviClients.AviClient, utils.CtrlVersion, utils.CloudName)
+	g.Expect(aviObjCache.PoolCache.AviCacheLen()).To(gomega.Equal(2))
+	g.Expect(poolFetches).To(gomega.HaveLen(1))
+	g.Expect(poolFetches[0]).NotTo(gomega.ContainSubstring("uuid.in"))
+	g.Expect(filepath.Join(checkpointDir, "ako-cache-checkpoint.json")).To(gomega.BeAnExistingFile())
+
+	// After a restart, the unchanged pools are picked from the checkpoint.
+	poolFetches = nil
+	aviObjCache = cache.NewAviObjCache()
+	aviObjCache.AviObjCachePopulate(aviClients.AviClient, utils.CtrlVersion, utils.CloudName)
+	g.Expect(aviObjCache.PoolCache.AviCacheLen()).To(gomega.Equal(2))
+	g.Expect(poolFetches).To(gomega.BeEmpty())
+
+	// Only the modified pool is fetched.
+	lock.Lock()
+	poolFetches = nil
+	modifiedPool = "pool-e3b87aff-a9d7-44eb-9935-6fd9ab81a37c"
+	lock.Unlock()
+	aviObjCache = cache.NewAviObjCache()
+	aviObjCache.AviObjCachePopulate(aviClients.AviClient, utils.CtrlVersion, utils.CloudName)
+	g.Expect(aviObjCache.PoolCache.AviCacheLen()).To(gomega.Equal(2))
+	g.Expect(poolFetches).To(gomega.HaveLen(1))
+	g.Expect(poolFetches[0]).To(gomega.ContainSubstring("uuid.in=pool-e3b87aff-a9d7-44eb-9935-6fd9ab81a37c&"))
+}
+
+// Without USE_PVC, the checkpoint is not persisted, and the unchanged objects are picked from the cache during the
+// next sync of the same cache.
+func TestIncrementalCacheSyncWithoutPVC(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	checkpointDir := t.TempDir()
+	os.Setenv("LOG_FILE_PATH", checkpointDir)
+	defer os.Unsetenv("LOG_FILE_PATH")
+
+	var lock sync.Mutex
+	var poolFetches []string
+	var modifiedPool string
+	injectMWForCacheSync(&lock, &poolFetches, &modifiedPool)
+	defer integrationtest.ResetMiddleware()
+	k8s.PopulateControllerProperties(KubeClient)
+	aviClients := cache.SharedAVIClients()
+	g.Expect(cache.SetControllerClusterUUID(aviClients)).To(gomega.Succeed())
+
+	aviObjCache := cache.NewAviObjCache()
+	aviObjCache.AviObjCachePopulate(aviClients.AviClient, utils.CtrlVersion, utils.CloudName)
+	g.Expect(aviObjCache.PoolCache.AviCacheLen()).To(gomega.Equal(2))
+	g.Expect(poolFetches).To(gomega.HaveLen(1))
+	g.Expect(filepath.Join(checkpointDir, "ako-cache-checkpoint.json")).NotTo(gomega.BeAnExistingFile())
+
+	lock.Lock()
+	poolFetches = nil
+	modifiedPool = "pool-e3b87aff-a9d7-44eb-9935-6fd9ab81a37c"
+	lock.Unlock()
+	// the standby replicas refresh their cache the same way.
+	g.Expect(aviObjCache.AviObjCacheRefresh(aviClients.AviClient, utils.CloudName)).To(gomega.Succeed())
+	g.Expect(aviObjCache.PoolCache.AviCacheLen()).To(gomega.Equal(2))
+	g.Expect(poolFetches).To(gomega.HaveLen(1))
+	g.Expect(poolFetches[0]).To(gomega.ContainSubstring("uuid.in=pool-e3b87aff-a9d7-44eb-9935-6fd9ab81a37c&"))
+	_, found := aviObjCache.VsCacheMeta.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: "cluster-name--Shared-L7-0"})
+	g.Expect(found).To(gomega.BeTrue())
+}
@@ -0,0 +1,131 @@
+package bootuptests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+)
+
+// injectMWForDrift serves the bootup mocks, and empty collections for the objects missing in the mocks.
+func injectMWForDrift() {
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "GET" && strings.Trim(url, "/") == "api/cluster" {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"uuid": "cluster-6b3d8b8e-2d5f-4c6c-9a4e-0c8a6c1c4f11"}`))
+		} else if r.Method == "GET" && strings.Contains(url, "/api/cloud/") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"count": 1, "results": [{"name": "CLOUD_VCENTER", "uuid": "cloud-0", "vtype": "CLOUD_VCENTER"}]}`))
+		} else if r.Method == "GET" && (strings.Contains(url, "/api/poolgroup") || strings.Contains(url, "/api/httppolicyset") ||
+			strings.Contains(url, "/api/l4policyset") || strings.Contains(url, "/api/vsdatascriptset") ||
+			strings.Contains(url, "/api/sslkeyandcertificate") || strings.Contains(url, "/api/pkiprofile")) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"count": 0, "results": []}`))
+		} else if r.Method == "GET" {
+			integrationtest.FeedMockCollectionData(w, r, mockFilePath)
+		} else if strings.Contains(url, "login") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success": "true"}`))
+		} else if strings.Contains(url, "initial-data") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"version": {"Version": "20.1.2"}}`))
+		}
+	})
+}
+
+// The objects modified on the Avi controller after AKO last wrote them are reported as drifted, and reverting a
+// drifted object clears its checksum in the cache.
+func TestDriftDetection(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	injectMWForDrift()
+	defer integrationtest.ResetMiddleware()
+	k8s.PopulateControllerProperties(KubeClient)
+	aviClients := cache.SharedAVIClients()
+
+	aviObjCache := cache.NewAviObjCache()
+	aviObjCache.AviObjCachePopulate(aviClients.AviClient, utils.CtrlVersion, utils.CloudName)
+	drifted, err := aviObjCache.DetectDrift(aviClients.AviClient[0], utils.CloudName)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(drifted).To(gomega.BeEmpty())
+
+	// The pool was modified after AKO last wrote it, and the checksum of the virtualservice was changed.
+	poolKey := cache.NamespaceName{Namespace: lib.GetTenant(), Name: "default-route1-aviroute-pool-8080-tcp"}
+	poolCache, found := aviObjCache.PoolCache.AviCacheGet(poolKey)
+	g.Expect(found).To(gomega.BeTrue())
+	poolCache.(*cache.AviPoolCache).LastModified = "1577342970000000"
+	vsKey := cache.NamespaceName{Namespace: lib.GetTenant(), Name: "cluster-name--Shared-L7-0"}
+	vsCache, found := aviObjCache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.BeTrue())
+	vsCache.(*cache.AviVsCache).CloudConfigCksum = "1234"
+
+	drifted, err = aviObjCache.DetectDrift(aviClients.AviClient[0], utils.CloudName)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(drifted).To(gomega.HaveLen(2))
+	g.Expect(drifted).To(gomega.ContainElement(cache.DriftedObject{
+		ObjectType:         "virtualservice",
+		Name:               "cluster-name--Shared-L7-0",
+		Tenant:             lib.GetTenant(),
+		Uuid:               "virtualservice-7bdd226f-9f37-4978-b0e9-e55cc94d6b6e",
+		VirtualService:     "cluster-name--Shared-L7-0",
+		CachedChecksum:     "1234",
+		LiveChecksum:       "496a4fa92c22ee563b3a63ae11b82bcc",
+		CachedLastModified: "1577342981791609",
+		LiveLastModified:   "1577342981791609",
+	}))
+	var driftedPool cache.DriftedObject
+	for _, obj := range drifted {
+		if obj.ObjectType == "pool" {
+			driftedPool = obj
+		}
+	}
+	g.Expect(driftedPool.Name).To(gomega.Equal("default-route1-aviroute-pool-8080-tcp"))
+	g.Expect(driftedPool.CachedLastModified).To(gomega.Equal("1577342970000000"))
+	g.Expect(driftedPool.LiveLastModified).To(gomega.Equal("1577342976851289"))
+
+	// The cached pool is replaced by a copy with the checksum cleared, the object held by the readers is unchanged.
+	poolChecksum := poolCache.(*cache.AviPoolCache).CloudConfigCksum
+	for _, obj := range drifted {
+		aviObjCache.InvalidateChecksum(obj)
+	}
+	g.Expect(poolCache.(*cache.AviPoolCache).CloudConfigCksum).To(gomega.Equal(poolChecksum))
+	poolCache, _ = aviObjCache.PoolCache.AviCacheGet(poolKey)
+	g.Expect(poolCache.(*cache.AviPoolCache).CloudConfigCksum).To(gomega.BeEmpty())
+	g.Expect(vsCache.(*cache.AviVsCache).CloudConfigCksum).To(gomega.BeEmpty())
+}
+
+// The objects whose _last_modified is not known in the cache are compared through their recomputed checksums.
+func TestDriftDetectionWithoutLastModified(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	injectMWForDrift()
+	defer integrationtest.ResetMiddleware()
+	k8s.PopulateControllerProperties(KubeClient)
+	aviClients := cache.SharedAVIClients()
+
+	aviObjCache := cache.NewAviObjCache()
+	aviObjCache.AviObjCachePopulate(aviClients.AviClient, utils.CtrlVersion, utils.CloudName)
+
+	poolKey := cache.NamespaceName{Namespace: lib.GetTenant(), Name: "default-route1-aviroute-pool-8080-tcp"}
+	poolCache, found := aviObjCache.PoolCache.AviCacheGet(poolKey)
+	g.Expect(found).To(gomega.BeTrue())
+	poolCache.(*cache.AviPoolCache).LastModified = ""
+	drifted, err := aviObjCache.DetectDrift(aviClients.AviClient[0], utils.CloudName)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(drifted).To(gomega.BeEmpty())
+
+	liveChecksum := poolCache.(*cache.AviPoolCache).CloudConfigCksum
+	poolCache.(*cache.AviPoolCache).CloudConfigCksum = "1234"
+	drifted, err = aviObjCache.DetectDrift(aviClients.AviClient[0], utils.CloudName)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(drifted).To(gomega.HaveLen(1))
+	g.Expect(drifted[0].ObjectType).To(gomega.Equal("pool"))
+	g.Expect(drifted[0].CachedChecksum).To(gomega.Equal("1234"))
+	g.Expect(drifted[0].LiveChecksum).To(gomega.Equal(liveChecksum))
+}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
@@ -13,6 +15,8 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 
+	"github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
@@ -162,3 +166,68 @@ func TestNetworkIssueCacheValidationDuringBootup(t *testing.T) {
 	}
 	integrationtest.ResetMiddleware()
 }
+
+// A standby replica waits while the Lease is held by the leader, and takes over once the Lease is released,
+// after refreshing its cache of the Avi objects.
+func TestLeaderElection(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv(lib.LEADER_ELECTION, "true")
+	os.Setenv("POD_NAME", "ako-1")
+	defer os.Unsetenv(lib.LEADER_ELECTION)
+	defer os.Unsetenv("POD_NAME")
+
+	leaderIdentity := "ako-0"
+	leaseDuration := int32(15)
+	renewTime := metav1.NewMicroTime(time.Now())
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: lib.AKOLeaderElectionLease, Namespace: utils.GetAKONamespace()},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &leaderIdentity,
+			LeaseDurationSeconds: &leaseDuration,
+			AcquireTime:          &renewTime,
+			RenewTime:            &renewTime,
+		},
+	}
+	leases := KubeClient.CoordinationV1().Leases(utils.GetAKONamespace())
+	if _, err := leases.Create(context.TODO(), lease, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Lease: %v", err)
+	}
+
+	stopCh := make(chan struct{})
+	k8s.SharedAviController().RunLeaderElection(KubeClient, stopCh)
+	elected := make(chan bool, 1)
+	go func() {
+		elected <- k8s.SharedAviController().WaitForLeadership(stopCh)
+	}()
+	g.Consistently(lib.IsLeader, 3*time.Second).Should(gomega.BeFalse())
+	g.Expect(elected).NotTo(gomega.Receive())
+
+	var cacheRefreshed int32
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "GET" && strings.Contains(url, "/api/virtualservice") {
+			atomic.StoreInt32(&cacheRefreshed, 1)
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+	defer integrationtest.ResetMiddleware()
+
+	leases.Delete(context.TODO(), lib.AKOLeaderElectionLease, metav1.DeleteOptions{})
+	g.Eventually(lib.IsLeader, 10*time.Second).Should(gomega.BeTrue())
+	g.Eventually(elected, 30*time.Second).Should(gomega.Receive(gomega.BeTrue()))
+	g.Expect(atomic.LoadInt32(&cacheRefreshed)).To(gomega.Equal(int32(1)))
+	lease, err := leases.Get(context.TODO(), lib.AKOLeaderElectionLease, metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(*lease.Spec.HolderIdentity).To(gomega.Equal("ako-1"))
+
+	// The Lease is released on shutdown, for a standby replica to take over.
+	close(stopCh)
+	g.Eventually(func() string {
+		lease, _ := leases.Get(context.TODO(), lib.AKOLeaderElectionLease, metav1.GetOptions{})
+		if lease == nil || lease.Spec.HolderIdentity == nil {
+			return ""
+		}
+		return *lease.Spec.HolderIdentity
+	}, 10*time.Second).Should(gomega.Equal(""))
+	lib.SetLeader(false)
+}
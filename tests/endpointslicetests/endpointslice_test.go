@@ -0,0 +1,333 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package endpointslicetests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+var ctrl *k8s.AviController
+
+const (
+	modelName = "admin/cluster--Shared-L7-0"
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("INGRESS_API", "extensionv1")
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+
+	akoControlConfig := lib.AKOControlConfig()
+	KubeClient = k8sfake.NewSimpleClientset()
+	CRDClient = crdfake.NewSimpleClientset()
+	akoControlConfig.SetCRDClientset(CRDClient)
+	akoControlConfig.SetAKOInstanceFlag(true)
+	akoControlConfig.SetEventRecorder(lib.AKOEventComponent, KubeClient, true)
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointSlicesInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+		utils.PodInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+	k8s.NewCRDInformers(CRDClient)
+
+	mcache := cache.SharedAviObjCache()
+	cloudObj := &cache.AviCloudPropertyCache{Name: "Default-Cloud", VType: "mock"}
+	cloudObj.NSIpamDNS = []string{"avi.internal", ".com"}
+	mcache.CloudKeyCache.AviCacheAdd("Default-Cloud", cloudObj)
+
+	integrationtest.InitializeFakeAKOAPIServer()
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	waitGroupMap["ingestion"] = &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = &sync.WaitGroup{}
+	waitGroupMap["graph"] = &sync.WaitGroup{}
+	waitGroupMap["status"] = &sync.WaitGroup{}
+
+	integrationtest.AddConfigMap(KubeClient)
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	integrationtest.KubeClient = KubeClient
+	integrationtest.AddDefaultIngressClass()
+	ctrl.SetSEGroupCloudName()
+
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	os.Exit(m.Run())
+}
+
+type fakeEndpoint struct {
+	ip          string
+	podName     string
+	notReady    bool
+	terminating bool
+}
+
+func buildEndpointSlice(name string, addressType discovery.AddressType, endpoints []fakeEndpoint) *discovery.EndpointSlice {
+	epSlice := &discovery.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{discovery.LabelServiceName: "avisvc"},
+		},
+		AddressType: addressType,
+		Ports: []discovery.EndpointPort{{
+			Name:     pointer.StringPtr("foo0"),
+			Port:     pointer.Int32Ptr(8080),
+			Protocol: func() *corev1.Protocol { p := corev1.ProtocolTCP; return &p }(),
+		}},
+	}
+	for _, ep := range endpoints {
+		endpoint := discovery.Endpoint{
+			Addresses: []string{ep.ip},
+			Conditions: discovery.EndpointConditions{
+				Ready:       pointer.BoolPtr(!ep.notReady && !ep.terminating),
+				Serving:     pointer.BoolPtr(!ep.notReady),
+				Terminating: pointer.BoolPtr(ep.terminating),
+			},
+			NodeName: pointer.StringPtr("node1"),
+			Zone:     pointer.StringPtr("zone-a"),
+		}
+		if ep.podName != "" {
+			endpoint.TargetRef = &corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: ep.podName}
+		}
+		epSlice.Endpoints = append(epSlice.Endpoints, endpoint)
+	}
+	return epSlice
+}
+
+func createEndpointSlice(t *testing.T, name string, addressType discovery.AddressType, endpoints []fakeEndpoint) {
+	epSlice := buildEndpointSlice(name, addressType, endpoints)
+	if _, err := KubeClient.DiscoveryV1().EndpointSlices("default").Create(context.TODO(), epSlice, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding EndpointSlice: %v", err)
+	}
+}
+
+func updateEndpointSlice(t *testing.T, name string, addressType discovery.AddressType, endpoints []fakeEndpoint) {
+	epSlice := buildEndpointSlice(name, addressType, endpoints)
+	epSlice.ResourceVersion = time.Now().String()
+	if _, err := KubeClient.DiscoveryV1().EndpointSlices("default").Update(context.TODO(), epSlice, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating EndpointSlice: %v", err)
+	}
+}
+
+func deleteEndpointSlices(t *testing.T, names ...string) {
+	for _, name := range names {
+		if err := KubeClient.DiscoveryV1().EndpointSlices("default").Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+			t.Fatalf("error in deleting EndpointSlice: %v", err)
+		}
+	}
+}
+
+func setUpIngress(t *testing.T) {
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.CreateSVC(t, "default", "avisvc", corev1.ServiceTypeClusterIP, false)
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+}
+
+func tearDownIngress(t *testing.T) {
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Ingress: %v", err)
+	}
+	integrationtest.DelSVC(t, "default", "avisvc")
+	objects.SharedAviGraphLister().Delete(modelName)
+}
+
+func getPoolServers() []avinodes.AviPoolMetaServer {
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if aviModel == nil {
+		return nil
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	if len(nodes) == 0 || len(nodes[0].PoolRefs) == 0 {
+		return nil
+	}
+	return nodes[0].PoolRefs[0].Servers
+}
+
+func getPoolServerIPs() map[string]bool {
+	servers := make(map[string]bool)
+	for _, server := range getPoolServers() {
+		servers[*server.Ip.Addr] = server.Draining
+	}
+	return servers
+}
+
+func TestEndpointSlicesAcrossSlices(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	setUpIngress(t)
+	// More endpoints than an Endpoints object can carry, spread across the slices.
+	var endpoints []fakeEndpoint
+	for i := 0; i < 1200; i++ {
+		endpoints = append(endpoints, fakeEndpoint{ip: fmt.Sprintf("10.1.%d.%d", i/250, i%250+1)})
+	}
+	createEndpointSlice(t, "avisvc-a", discovery.AddressTypeIPv4, endpoints[:600])
+	createEndpointSlice(t, "avisvc-b", discovery.AddressTypeIPv4, endpoints[600:])
+	g.Eventually(func() int {
+		return len(getPoolServers())
+	}, 20*time.Second).Should(gomega.Equal(1200))
+	servers := getPoolServers()
+	g.Expect(*servers[0].Ip.Type).To(gomega.Equal("V4"))
+	g.Expect(servers[0].ServerNode).To(gomega.Equal("node1"))
+	// The zones are used only in the public clouds.
+	g.Expect(servers[0].AvailabilityZone).To(gomega.BeEmpty())
+
+	// an update to a slice updates the servers of the pool, the not ready endpoints are not added
+	updateEndpointSlice(t, "avisvc-b", discovery.AddressTypeIPv4, []fakeEndpoint{
+		{ip: "10.2.0.1"},
+		{ip: "10.2.0.2", notReady: true},
+	})
+	g.Eventually(func() int {
+		return len(getPoolServers())
+	}, 20*time.Second).Should(gomega.Equal(601))
+	serverIPs := getPoolServerIPs()
+	g.Expect(serverIPs).To(gomega.HaveKey("10.2.0.1"))
+	g.Expect(serverIPs).NotTo(gomega.HaveKey("10.2.0.2"))
+
+	deleteEndpointSlices(t, "avisvc-a")
+	g.Eventually(func() map[string]bool {
+		return getPoolServerIPs()
+	}, 20*time.Second).Should(gomega.Equal(map[string]bool{"10.2.0.1": false}))
+
+	deleteEndpointSlices(t, "avisvc-b")
+	tearDownIngress(t)
+}
+
+func TestEndpointSlicesDualStack(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	setUpIngress(t)
+	createEndpointSlice(t, "avisvc-v4", discovery.AddressTypeIPv4, []fakeEndpoint{{ip: "10.1.1.1"}})
+	createEndpointSlice(t, "avisvc-v6", discovery.AddressTypeIPv6, []fakeEndpoint{{ip: "2001:db8::1"}})
+	g.Eventually(func() map[string]bool {
+		return getPoolServerIPs()
+	}, 20*time.Second).Should(gomega.Equal(map[string]bool{"10.1.1.1": false}))
+
+	// the endpoints of both the IP families of a dual-stack Service are used
+	svc, _ := KubeClient.CoreV1().Services("default").Get(context.TODO(), "avisvc", metav1.GetOptions{})
+	svc.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+	svc.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services("default").Update(context.TODO(), svc, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() map[string]bool {
+		return getPoolServerIPs()
+	}, 20*time.Second).Should(gomega.Equal(map[string]bool{"10.1.1.1": false, "2001:db8::1": false}))
+	serverTypes := make(map[string]string)
+	for _, server := range getPoolServers() {
+		serverTypes[*server.Ip.Addr] = *server.Ip.Type
+	}
+	g.Expect(serverTypes).To(gomega.Equal(map[string]string{"10.1.1.1": "V4", "2001:db8::1": "V6"}))
+
+	svc.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	svc.ResourceVersion = "3"
+	if _, err := KubeClient.CoreV1().Services("default").Update(context.TODO(), svc, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() map[string]bool {
+		return getPoolServerIPs()
+	}, 20*time.Second).Should(gomega.Equal(map[string]bool{"2001:db8::1": false}))
+
+	deleteEndpointSlices(t, "avisvc-v4", "avisvc-v6")
+	tearDownIngress(t)
+}
+
+func TestEndpointSlicesTerminatingEndpoints(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	setUpIngress(t)
+	createEndpointSlice(t, "avisvc-a", discovery.AddressTypeIPv4, []fakeEndpoint{{ip: "10.1.1.1"}, {ip: "10.1.1.2"}})
+	g.Eventually(func() map[string]bool {
+		return getPoolServerIPs()
+	}, 20*time.Second).Should(gomega.Equal(map[string]bool{"10.1.1.1": false, "10.1.1.2": false}))
+
+	// the terminating endpoints are removed when the draining is disabled
+	updateEndpointSlice(t, "avisvc-a", discovery.AddressTypeIPv4, []fakeEndpoint{{ip: "10.1.1.1"}, {ip: "10.1.1.2", terminating: true}})
+	g.Eventually(func() map[string]bool {
+		return getPoolServerIPs()
+	}, 20*time.Second).Should(gomega.Equal(map[string]bool{"10.1.1.1": false}))
+
+	// and are disabled in the pool when the draining is enabled
+	os.Setenv(lib.POOL_DRAIN_TIMEOUT, "60")
+	defer os.Unsetenv(lib.POOL_DRAIN_TIMEOUT)
+	updateEndpointSlice(t, "avisvc-a", discovery.AddressTypeIPv4, []fakeEndpoint{{ip: "10.1.1.1"}, {ip: "10.1.1.3", terminating: true}})
+	g.Eventually(func() map[string]bool {
+		return getPoolServerIPs()
+	}, 20*time.Second).Should(gomega.Equal(map[string]bool{"10.1.1.1": false, "10.1.1.3": true}))
+
+	deleteEndpointSlices(t, "avisvc-a")
+	tearDownIngress(t)
+}
@@ -0,0 +1,743 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package gatewayapitests
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapischeme "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned/scheme"
+	gwapiclient "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned/typed/apis/v1alpha2"
+	gwapifake "sigs.k8s.io/gateway-api/pkg/client/clientset/gateway/versioned/typed/apis/v1alpha2/fake"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+var GatewayAPIClient *FakeGatewayAPIClientset
+var ctrl *k8s.AviController
+
+// FakeGatewayAPIClientset is an object tracker backed Gateway API clientset, the generated fake clientset
+// of the gateway-api module requires a newer client-go than the one the module is pinned to.
+type FakeGatewayAPIClientset struct {
+	k8stesting.Fake
+	discovery *fakediscovery.FakeDiscovery
+}
+
+func NewFakeGatewayAPIClientset() *FakeGatewayAPIClientset {
+	tracker := k8stesting.NewObjectTracker(gwapischeme.Scheme, serializer.NewCodecFactory(gwapischeme.Scheme).UniversalDecoder())
+	cs := &FakeGatewayAPIClientset{}
+	cs.discovery = &fakediscovery.FakeDiscovery{Fake: &cs.Fake}
+	cs.AddReactor("*", "*", k8stesting.ObjectReaction(tracker))
+	cs.AddWatchReactor("*", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w, err := tracker.Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return false, nil, err
+		}
+		return true, w, nil
+	})
+	return cs
+}
+
+func (c *FakeGatewayAPIClientset) Discovery() discovery.DiscoveryInterface {
+	return c.discovery
+}
+
+func (c *FakeGatewayAPIClientset) GatewayV1alpha2() gwapiclient.GatewayV1alpha2Interface {
+	return &gwapifake.FakeGatewayV1alpha2{Fake: &c.Fake}
+}
+
+func TestMain(m *testing.M) {
+	os.Setenv("GATEWAY_API", "true")
+	os.Setenv("INGRESS_API", "extensionv1")
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+	os.Setenv("AUTO_L4_FQDN", "default")
+
+	akoControlConfig := lib.AKOControlConfig()
+	KubeClient = k8sfake.NewSimpleClientset()
+	CRDClient = crdfake.NewSimpleClientset()
+	akoControlConfig.SetCRDClientset(CRDClient)
+	akoControlConfig.SetEventRecorder(lib.AKOEventComponent, KubeClient, true)
+	akoControlConfig.SetAKOInstanceFlag(true)
+	k8s.NewCRDInformers(CRDClient)
+
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	GatewayAPIClient = NewFakeGatewayAPIClientset()
+	akoControlConfig.SetGatewayAPIClientset(GatewayAPIClient)
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+	k8s.NewGatewayAPIInformers(GatewayAPIClient)
+
+	mcache := cache.SharedAviObjCache()
+	cloudObj := &cache.AviCloudPropertyCache{Name: "Default-Cloud", VType: "mock"}
+	subdomains := []string{"avi.internal", ".com"}
+	cloudObj.NSIpamDNS = subdomains
+	mcache.CloudKeyCache.AviCacheAdd("Default-Cloud", cloudObj)
+
+	integrationtest.InitializeFakeAKOAPIServer()
+
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+
+	integrationtest.AddConfigMap(KubeClient)
+	ctrl.SetSEGroupCloudName()
+
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	integrationtest.KubeClient = KubeClient
+	os.Exit(m.Run())
+}
+
+// GatewayClass, Gateway, route and ReferencePolicy lib functions
+func SetupGatewayClass(t *testing.T, name, controllerName string) {
+	gwClass := &gwapiv1alpha2.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: gwapiv1alpha2.GatewayClassSpec{
+			ControllerName: gwapiv1alpha2.GatewayController(controllerName),
+		},
+	}
+	if _, err := GatewayAPIClient.GatewayV1alpha2().GatewayClasses().Create(context.TODO(), gwClass, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding GatewayClass: %v", err)
+	}
+}
+
+func TeardownGatewayClass(t *testing.T, name string) {
+	if err := GatewayAPIClient.GatewayV1alpha2().GatewayClasses().Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting GatewayClass: %v", err)
+	}
+}
+
+func SetupGateway(t *testing.T, name, namespace, gwClass string, listeners []gwapiv1alpha2.Listener) {
+	gateway := &gwapiv1alpha2.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: gwapiv1alpha2.GatewaySpec{
+			GatewayClassName: gwapiv1alpha2.ObjectName(gwClass),
+			Listeners:        listeners,
+		},
+	}
+	if _, err := GatewayAPIClient.GatewayV1alpha2().Gateways(namespace).Create(context.TODO(), gateway, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Gateway: %v", err)
+	}
+}
+
+func TeardownGateway(t *testing.T, name, namespace string) {
+	if err := GatewayAPIClient.GatewayV1alpha2().Gateways(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Gateway: %v", err)
+	}
+}
+
+func Listener(name string, port int32, protocol gwapiv1alpha2.ProtocolType, hostname string) gwapiv1alpha2.Listener {
+	listener := gwapiv1alpha2.Listener{
+		Name:     gwapiv1alpha2.SectionName(name),
+		Port:     gwapiv1alpha2.PortNumber(port),
+		Protocol: protocol,
+	}
+	if hostname != "" {
+		listenerHostname := gwapiv1alpha2.Hostname(hostname)
+		listener.Hostname = &listenerHostname
+	}
+	return listener
+}
+
+func SetupHTTPRoute(t *testing.T, name, namespace, gateway string, hostnames []string, rules []gwapiv1alpha2.HTTPRouteRule) {
+	route := &gwapiv1alpha2.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: gwapiv1alpha2.HTTPRouteSpec{
+			CommonRouteSpec: gwapiv1alpha2.CommonRouteSpec{
+				ParentRefs: []gwapiv1alpha2.ParentRef{{Name: gwapiv1alpha2.ObjectName(gateway)}},
+			},
+			Rules: rules,
+		},
+	}
+	for _, hostname := range hostnames {
+		route.Spec.Hostnames = append(route.Spec.Hostnames, gwapiv1alpha2.Hostname(hostname))
+	}
+	if _, err := GatewayAPIClient.GatewayV1alpha2().HTTPRoutes(namespace).Create(context.TODO(), route, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRoute: %v", err)
+	}
+}
+
+func TeardownHTTPRoute(t *testing.T, name, namespace string) {
+	if err := GatewayAPIClient.GatewayV1alpha2().HTTPRoutes(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting HTTPRoute: %v", err)
+	}
+}
+
+func SetupTLSRoute(t *testing.T, name, namespace, gateway string, hostnames []string, backendRef gwapiv1alpha2.BackendRef) {
+	route := &gwapiv1alpha2.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: gwapiv1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gwapiv1alpha2.CommonRouteSpec{
+				ParentRefs: []gwapiv1alpha2.ParentRef{{Name: gwapiv1alpha2.ObjectName(gateway)}},
+			},
+			Rules: []gwapiv1alpha2.TLSRouteRule{{BackendRefs: []gwapiv1alpha2.BackendRef{backendRef}}},
+		},
+	}
+	for _, hostname := range hostnames {
+		route.Spec.Hostnames = append(route.Spec.Hostnames, gwapiv1alpha2.Hostname(hostname))
+	}
+	if _, err := GatewayAPIClient.GatewayV1alpha2().TLSRoutes(namespace).Create(context.TODO(), route, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding TLSRoute: %v", err)
+	}
+}
+
+func TeardownTLSRoute(t *testing.T, name, namespace string) {
+	if err := GatewayAPIClient.GatewayV1alpha2().TLSRoutes(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting TLSRoute: %v", err)
+	}
+}
+
+func BackendRef(name, namespace string, port int32) gwapiv1alpha2.BackendRef {
+	backendPort := gwapiv1alpha2.PortNumber(port)
+	backendRef := gwapiv1alpha2.BackendRef{
+		BackendObjectReference: gwapiv1alpha2.BackendObjectReference{
+			Name: gwapiv1alpha2.ObjectName(name),
+			Port: &backendPort,
+		},
+	}
+	if namespace != "" {
+		backendNamespace := gwapiv1alpha2.Namespace(namespace)
+		backendRef.Namespace = &backendNamespace
+	}
+	return backendRef
+}
+
+func PathMatch(pathType gwapiv1alpha2.PathMatchType, path string) gwapiv1alpha2.HTTPRouteMatch {
+	return gwapiv1alpha2.HTTPRouteMatch{
+		Path: &gwapiv1alpha2.HTTPPathMatch{Type: &pathType, Value: &path},
+	}
+}
+
+func SetupReferencePolicy(t *testing.T, name, namespace, fromKind, fromNamespace, toKind string) {
+	rp := &gwapiv1alpha2.ReferencePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: gwapiv1alpha2.ReferencePolicySpec{
+			From: []gwapiv1alpha2.ReferencePolicyFrom{{
+				Group:     lib.GatewayAPIGroup,
+				Kind:      gwapiv1alpha2.Kind(fromKind),
+				Namespace: gwapiv1alpha2.Namespace(fromNamespace),
+			}},
+			To: []gwapiv1alpha2.ReferencePolicyTo{{
+				Group: "",
+				Kind:  gwapiv1alpha2.Kind(toKind),
+			}},
+		},
+	}
+	if _, err := GatewayAPIClient.GatewayV1alpha2().ReferencePolicies(namespace).Create(context.TODO(), rp, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding ReferencePolicy: %v", err)
+	}
+}
+
+func TeardownReferencePolicy(t *testing.T, name, namespace string) {
+	if err := GatewayAPIClient.GatewayV1alpha2().ReferencePolicies(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting ReferencePolicy: %v", err)
+	}
+}
+
+// SetupBackendService creates a ClusterIP service with a single endpoint, and waits for the endpoints to be in
+// the informer cache, for the pools of the routes created later to have the servers on the first sync.
+func SetupBackendService(t *testing.T, g *gomega.WithT, name, namespace string) {
+	integrationtest.CreateSVC(t, namespace, name, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, namespace, name, false, false, "1.1.1")
+	g.Eventually(func() error {
+		_, err := utils.GetInformers().EpInformer.Lister().Endpoints(namespace).Get(name)
+		return err
+	}, 10*time.Second).Should(gomega.BeNil())
+}
+
+func TeardownBackendService(t *testing.T, name, namespace string) {
+	integrationtest.DelSVC(t, namespace, name)
+	integrationtest.DelEP(t, namespace, name)
+}
+
+func GetGatewayVSNode(modelName string) *avinodes.AviVsNode {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
+
+func VerifyGatewayVSNodeDeletion(g *gomega.WithT, modelName string) {
+	g.Eventually(func() interface{} {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return aviModel
+	}, 30*time.Second).Should(gomega.BeNil())
+}
+
+// GetListenerCondition returns the status of the condition of the Gateway listener, an empty string
+// is returned if either the listener or the condition is not found.
+func GetListenerCondition(name, namespace, listener, conditionType string) (string, string) {
+	gw, err := GatewayAPIClient.GatewayV1alpha2().Gateways(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", ""
+	}
+	for _, listenerStatus := range gw.Status.Listeners {
+		if string(listenerStatus.Name) != listener {
+			continue
+		}
+		if condition := meta.FindStatusCondition(listenerStatus.Conditions, conditionType); condition != nil {
+			return string(condition.Status), condition.Reason
+		}
+	}
+	return "", ""
+}
+
+func GetGatewayCondition(name, namespace, conditionType string) (string, string) {
+	gw, err := GatewayAPIClient.GatewayV1alpha2().Gateways(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", ""
+	}
+	if condition := meta.FindStatusCondition(gw.Status.Conditions, conditionType); condition != nil {
+		return string(condition.Status), condition.Reason
+	}
+	return "", ""
+}
+
+func GetRouteCondition(routeStatus gwapiv1alpha2.RouteStatus, conditionType string) (string, string) {
+	for _, parent := range routeStatus.Parents {
+		if string(parent.ControllerName) != lib.GatewayAPIAviGatewayController {
+			continue
+		}
+		if condition := meta.FindStatusCondition(parent.Conditions, conditionType); condition != nil {
+			return string(condition.Status), condition.Reason
+		}
+	}
+	return "", ""
+}
+
+func GetHTTPRouteCondition(name, namespace, conditionType string) (string, string) {
+	route, err := GatewayAPIClient.GatewayV1alpha2().HTTPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", ""
+	}
+	return GetRouteCondition(route.Status.RouteStatus, conditionType)
+}
+
+func GetTLSRouteCondition(name, namespace, conditionType string) (string, string) {
+	route, err := GatewayAPIClient.GatewayV1alpha2().TLSRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", ""
+	}
+	return GetRouteCondition(route.Status.RouteStatus, conditionType)
+}
+
+func TestGatewayAPIHTTPRoute(t *testing.T) {
+	// create gwclass, gw with two conflicting listeners and a httproute with a switching and a redirect rule
+	// check the VS node, the httppolicyset, the listener and the route statuses
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, routeName, ns := "avi-lb", "my-gateway", "my-route", "default"
+	modelName := "admin/" + lib.GetGatewayAPIVSName(gatewayName, ns)
+
+	SetupBackendService(t, g, "avisvc", ns)
+	SetupGatewayClass(t, gwClassName, lib.GatewayAPIAviGatewayController)
+	SetupGateway(t, gatewayName, ns, gwClassName, []gwapiv1alpha2.Listener{
+		Listener("http", 80, gwapiv1alpha2.HTTPProtocolType, "foo.com"),
+		Listener("http-conflict", 80, gwapiv1alpha2.HTTPProtocolType, "foo.com"),
+	})
+	redirectHostname := gwapiv1alpha2.PreciseHostname("bar.com")
+	redirectCode := 301
+	SetupHTTPRoute(t, routeName, ns, gatewayName, []string{"foo.com"}, []gwapiv1alpha2.HTTPRouteRule{
+		{
+			Matches: []gwapiv1alpha2.HTTPRouteMatch{PathMatch(gwapiv1alpha2.PathMatchPathPrefix, "/foo")},
+			Filters: []gwapiv1alpha2.HTTPRouteFilter{{
+				Type: gwapiv1alpha2.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &gwapiv1alpha2.HTTPRequestHeaderFilter{
+					Set: []gwapiv1alpha2.HTTPHeader{{Name: "x-env", Value: "test"}},
+				},
+			}},
+			BackendRefs: []gwapiv1alpha2.HTTPBackendRef{{BackendRef: BackendRef("avisvc", "", 8080)}},
+		},
+		{
+			Matches: []gwapiv1alpha2.HTTPRouteMatch{PathMatch(gwapiv1alpha2.PathMatchExact, "/old")},
+			Filters: []gwapiv1alpha2.HTTPRouteFilter{{
+				Type:            gwapiv1alpha2.HTTPRouteFilterRequestRedirect,
+				RequestRedirect: &gwapiv1alpha2.HTTPRequestRedirectFilter{Hostname: &redirectHostname, StatusCode: &redirectCode},
+			}},
+		},
+	})
+
+	g.Eventually(func() int {
+		if vsNode := GetGatewayVSNode(modelName); vsNode != nil && len(vsNode.HttpPolicyRefs) == 1 {
+			return len(vsNode.HttpPolicyRefs[0].HppMap)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(2))
+
+	pgName := lib.GetGatewayAPIPGName(gatewayName, ns, routeName, ns, 0)
+	vsNode := GetGatewayVSNode(modelName)
+	g.Expect(vsNode.Name).To(gomega.Equal(lib.GetGatewayAPIVSName(gatewayName, ns)))
+	g.Expect(vsNode.Dedicated).To(gomega.BeTrue())
+	g.Expect(vsNode.ServiceMetadata.Gateway).To(gomega.Equal(ns + "/" + gatewayName))
+	g.Expect(vsNode.PortProto).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PortProto[0].Port).To(gomega.Equal(int32(80)))
+	g.Expect(vsNode.PortProto[0].EnableSSL).To(gomega.BeFalse())
+	g.Expect(vsNode.VSVIPRefs[0].FQDNs).To(gomega.ConsistOf("foo.com"))
+	// The redirect rule does not have a poolgroup.
+	g.Expect(vsNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolGroupRefs[0].Name).To(gomega.Equal(pgName))
+	g.Expect(vsNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolRefs[0].Name).To(gomega.Equal(lib.GetGatewayAPIPoolName(gatewayName, ns, routeName, ns, 0, "avisvc", ns, 8080)))
+	g.Expect(vsNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+
+	// The exact path match takes precedence.
+	redirectHpp := vsNode.HttpPolicyRefs[0].HppMap[0]
+	g.Expect(redirectHpp.Path).To(gomega.ConsistOf("/old"))
+	g.Expect(redirectHpp.MatchCriteria).To(gomega.Equal("EQUALS"))
+	g.Expect(redirectHpp.PoolGroup).To(gomega.BeEmpty())
+	g.Expect(redirectHpp.Redirect).NotTo(gomega.BeNil())
+	g.Expect(redirectHpp.Redirect.Host).To(gomega.Equal("bar.com"))
+	g.Expect(redirectHpp.Redirect.Protocol).To(gomega.Equal("HTTP"))
+	g.Expect(redirectHpp.Redirect.StatusCode).To(gomega.Equal(lib.STATUS_REDIRECT_PERMANENT))
+	switchingHpp := vsNode.HttpPolicyRefs[0].HppMap[1]
+	g.Expect(switchingHpp.Host).To(gomega.ConsistOf("foo.com"))
+	g.Expect(switchingHpp.Path).To(gomega.ConsistOf("/foo"))
+	g.Expect(switchingHpp.MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+	g.Expect(switchingHpp.Port).To(gomega.Equal(uint32(80)))
+	g.Expect(switchingHpp.PoolGroup).To(gomega.Equal(pgName))
+	g.Expect(switchingHpp.RequestHeaders).To(gomega.Equal([]avinodes.AviHTTPHeaderAction{
+		{Action: lib.HTTPReplaceHeader, Name: "x-env", Value: "test"},
+	}))
+
+	g.Eventually(func() string {
+		status, _ := GetHTTPRouteCondition(routeName, ns, string(gwapiv1alpha2.ConditionRouteAccepted))
+		return status
+	}, 30*time.Second).Should(gomega.Equal(string(metav1.ConditionTrue)))
+	status, _ := GetHTTPRouteCondition(routeName, ns, string(gwapiv1alpha2.ConditionRouteResolvedRefs))
+	g.Expect(status).To(gomega.Equal(string(metav1.ConditionTrue)))
+
+	g.Eventually(func() string {
+		status, _ := GetGatewayCondition(gatewayName, ns, string(gwapiv1alpha2.GatewayConditionScheduled))
+		return status
+	}, 30*time.Second).Should(gomega.Equal(string(metav1.ConditionTrue)))
+	gw, _ := GatewayAPIClient.GatewayV1alpha2().Gateways(ns).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+	g.Expect(gw.Status.Listeners).To(gomega.HaveLen(2))
+	g.Expect(gw.Status.Listeners[0].AttachedRoutes).To(gomega.Equal(int32(1)))
+	g.Expect(gw.Status.Listeners[1].AttachedRoutes).To(gomega.Equal(int32(0)))
+	status, _ = GetListenerCondition(gatewayName, ns, "http", string(gwapiv1alpha2.ListenerConditionReady))
+	g.Expect(status).To(gomega.Equal(string(metav1.ConditionTrue)))
+	status, reason := GetListenerCondition(gatewayName, ns, "http-conflict", string(gwapiv1alpha2.ListenerConditionConflicted))
+	g.Expect(status).To(gomega.Equal(string(metav1.ConditionTrue)))
+	g.Expect(reason).To(gomega.Equal(string(gwapiv1alpha2.ListenerReasonHostnameConflict)))
+	status, _ = GetListenerCondition(gatewayName, ns, "http-conflict", string(gwapiv1alpha2.ListenerConditionReady))
+	g.Expect(status).To(gomega.Equal(string(metav1.ConditionFalse)))
+
+	g.Eventually(func() string {
+		gw, _ := GatewayAPIClient.GatewayV1alpha2().Gateways(ns).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if len(gw.Status.Addresses) > 0 {
+			return gw.Status.Addresses[0].Value
+		}
+		return ""
+	}, 40*time.Second).Should(gomega.Equal("10.250.250.1"))
+
+	TeardownHTTPRoute(t, routeName, ns)
+	g.Eventually(func() int {
+		if vsNode := GetGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolGroupRefs) + len(vsNode.HttpPolicyRefs)
+		}
+		return -1
+	}, 30*time.Second).Should(gomega.Equal(0))
+
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	TeardownBackendService(t, "avisvc", ns)
+}
+
+func TestGatewayAPITLSRoute(t *testing.T) {
+	// create gwclass, gw with a TLS passthrough listener and two tlsroutes for the listener
+	// check the L4 VS node, and that only the older route is attached to the listener
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, ns := "avi-lb", "my-tls-gateway", "default"
+	routeName1, routeName2 := "my-tls-route-a", "my-tls-route-b"
+	modelName := "admin/" + lib.GetGatewayAPIVSName(gatewayName, ns)
+
+	SetupBackendService(t, g, "avisvc", ns)
+	SetupGatewayClass(t, gwClassName, lib.GatewayAPIAviGatewayController)
+	listener := Listener("tls", 443, gwapiv1alpha2.TLSProtocolType, "foo.com")
+	passthrough := gwapiv1alpha2.TLSModePassthrough
+	listener.TLS = &gwapiv1alpha2.GatewayTLSConfig{Mode: &passthrough}
+	SetupGateway(t, gatewayName, ns, gwClassName, []gwapiv1alpha2.Listener{listener})
+	SetupTLSRoute(t, routeName1, ns, gatewayName, []string{"foo.com"}, BackendRef("avisvc", "", 8080))
+
+	g.Eventually(func() int {
+		if vsNode := GetGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolRefs)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+
+	poolName := lib.GetGatewayAPIL4PoolName(gatewayName, ns, utils.TCP, 443, "avisvc", ns, 8080)
+	vsNode := GetGatewayVSNode(modelName)
+	g.Expect(vsNode.ApplicationProfile).To(gomega.Equal(utils.DEFAULT_L4_APP_PROFILE))
+	g.Expect(vsNode.NetworkProfile).To(gomega.Equal(utils.TCP_NW_FAST_PATH))
+	g.Expect(vsNode.PortProto).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PortProto[0].Port).To(gomega.Equal(int32(443)))
+	g.Expect(vsNode.PortProto[0].Protocol).To(gomega.Equal(utils.TCP))
+	g.Expect(vsNode.VSVIPRefs[0].FQDNs).To(gomega.ConsistOf("foo.com"))
+	g.Expect(vsNode.PoolRefs[0].Name).To(gomega.Equal(poolName))
+	g.Expect(vsNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+	g.Expect(vsNode.L4PolicyRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.L4PolicyRefs[0].PortPool).To(gomega.HaveLen(1))
+	g.Expect(vsNode.L4PolicyRefs[0].PortPool[0].Port).To(gomega.Equal(uint32(443)))
+	g.Expect(vsNode.L4PolicyRefs[0].PortPool[0].Pool).To(gomega.Equal("/api/pool?name=" + poolName))
+
+	g.Eventually(func() string {
+		status, _ := GetTLSRouteCondition(routeName1, ns, string(gwapiv1alpha2.ConditionRouteAccepted))
+		return status
+	}, 30*time.Second).Should(gomega.Equal(string(metav1.ConditionTrue)))
+
+	// A L4 listener forwards the traffic to the backend of a single route.
+	SetupTLSRoute(t, routeName2, ns, gatewayName, []string{"foo.com"}, BackendRef("avisvc", "", 8080))
+	g.Eventually(func() string {
+		_, reason := GetTLSRouteCondition(routeName2, ns, string(gwapiv1alpha2.ConditionRouteAccepted))
+		return reason
+	}, 30*time.Second).Should(gomega.Equal(string(gwapiv1alpha2.ListenerReasonRouteConflict)))
+	status, _ := GetTLSRouteCondition(routeName2, ns, string(gwapiv1alpha2.ConditionRouteAccepted))
+	g.Expect(status).To(gomega.Equal(string(metav1.ConditionFalse)))
+	status, _ = GetTLSRouteCondition(routeName1, ns, string(gwapiv1alpha2.ConditionRouteAccepted))
+	g.Expect(status).To(gomega.Equal(string(metav1.ConditionTrue)))
+	gw, _ := GatewayAPIClient.GatewayV1alpha2().Gateways(ns).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+	g.Expect(gw.Status.Listeners).To(gomega.HaveLen(1))
+	g.Expect(gw.Status.Listeners[0].AttachedRoutes).To(gomega.Equal(int32(1)))
+	g.Expect(gw.Status.Listeners[0].SupportedKinds).To(gomega.HaveLen(1))
+	g.Expect(string(gw.Status.Listeners[0].SupportedKinds[0].Kind)).To(gomega.Equal(lib.TLSRoute))
+
+	// The second route is attached once the first one is removed.
+	TeardownTLSRoute(t, routeName1, ns)
+	g.Eventually(func() string {
+		status, _ := GetTLSRouteCondition(routeName2, ns, string(gwapiv1alpha2.ConditionRouteAccepted))
+		return status
+	}, 30*time.Second).Should(gomega.Equal(string(metav1.ConditionTrue)))
+
+	TeardownTLSRoute(t, routeName2, ns)
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	TeardownBackendService(t, "avisvc", ns)
+}
+
+func TestGatewayAPIReferencePolicy(t *testing.T) {
+	// create a httproute with a backend in another namespace
+	// check that the backend is used only while a ReferencePolicy allows the reference
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, routeName, ns, svcNamespace := "avi-lb", "my-gateway", "my-route", "default", "red"
+	modelName := "admin/" + lib.GetGatewayAPIVSName(gatewayName, ns)
+
+	SetupBackendService(t, g, "avisvc", svcNamespace)
+	SetupGatewayClass(t, gwClassName, lib.GatewayAPIAviGatewayController)
+	SetupGateway(t, gatewayName, ns, gwClassName, []gwapiv1alpha2.Listener{
+		Listener("http", 80, gwapiv1alpha2.HTTPProtocolType, "foo.com"),
+	})
+	SetupHTTPRoute(t, routeName, ns, gatewayName, []string{"foo.com"}, []gwapiv1alpha2.HTTPRouteRule{{
+		BackendRefs: []gwapiv1alpha2.HTTPBackendRef{{BackendRef: BackendRef("avisvc", svcNamespace, 8080)}},
+	}})
+
+	g.Eventually(func() string {
+		_, reason := GetHTTPRouteCondition(routeName, ns, string(gwapiv1alpha2.ConditionRouteResolvedRefs))
+		return reason
+	}, 30*time.Second).Should(gomega.Equal("RefNotPermitted"))
+	status, _ := GetHTTPRouteCondition(routeName, ns, string(gwapiv1alpha2.ConditionRouteResolvedRefs))
+	g.Expect(status).To(gomega.Equal(string(metav1.ConditionFalse)))
+	vsNode := GetGatewayVSNode(modelName)
+	g.Expect(vsNode).NotTo(gomega.BeNil())
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(0))
+
+	SetupReferencePolicy(t, "allow-routes", svcNamespace, lib.HTTPRoute, ns, utils.Service)
+	g.Eventually(func() string {
+		status, _ := GetHTTPRouteCondition(routeName, ns, string(gwapiv1alpha2.ConditionRouteResolvedRefs))
+		return status
+	}, 30*time.Second).Should(gomega.Equal(string(metav1.ConditionTrue)))
+	g.Eventually(func() int {
+		if vsNode := GetGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolRefs)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+	vsNode = GetGatewayVSNode(modelName)
+	g.Expect(vsNode.PoolRefs[0].Name).To(gomega.Equal(lib.GetGatewayAPIPoolName(gatewayName, ns, routeName, ns, 0, "avisvc", svcNamespace, 8080)))
+	g.Expect(vsNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+
+	TeardownReferencePolicy(t, "allow-routes", svcNamespace)
+	g.Eventually(func() string {
+		_, reason := GetHTTPRouteCondition(routeName, ns, string(gwapiv1alpha2.ConditionRouteResolvedRefs))
+		return reason
+	}, 30*time.Second).Should(gomega.Equal("RefNotPermitted"))
+	g.Eventually(func() int {
+		if vsNode := GetGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolRefs)
+		}
+		return -1
+	}, 30*time.Second).Should(gomega.Equal(0))
+
+	TeardownHTTPRoute(t, routeName, ns)
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	TeardownBackendService(t, "avisvc", svcNamespace)
+}
+
+func TestGatewayAPIOtherControllerName(t *testing.T) {
+	// create a gwclass with the controllerName of the services API, and a gw of the class
+	// check that the gw is not realised, until the gwclass is recreated with the gateway API controllerName
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, ns := "avi-lb", "my-gateway", "default"
+	modelName := "admin/" + lib.GetGatewayAPIVSName(gatewayName, ns)
+
+	SetupGatewayClass(t, gwClassName, lib.SvcApiAviGatewayController)
+	SetupGateway(t, gatewayName, ns, gwClassName, []gwapiv1alpha2.Listener{
+		Listener("http", 80, gwapiv1alpha2.HTTPProtocolType, "foo.com"),
+	})
+	g.Consistently(func() *avinodes.AviVsNode {
+		return GetGatewayVSNode(modelName)
+	}, 5*time.Second).Should(gomega.BeNil())
+
+	TeardownGatewayClass(t, gwClassName)
+	SetupGatewayClass(t, gwClassName, lib.GatewayAPIAviGatewayController)
+	g.Eventually(func() *avinodes.AviVsNode {
+		return GetGatewayVSNode(modelName)
+	}, 30*time.Second).ShouldNot(gomega.BeNil())
+
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+}
+
+func TestGatewayAPIHTTPSListenerCertificate(t *testing.T) {
+	// create gwclass, gw with a HTTPS listener whose secret does not exist
+	// check the listener and gateway conditions, and the VS once the secret is created
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, secretName, ns := "avi-lb", "my-https-gateway", "my-secret", "default"
+	modelName := "admin/" + lib.GetGatewayAPIVSName(gatewayName, ns)
+
+	SetupGatewayClass(t, gwClassName, lib.GatewayAPIAviGatewayController)
+	listener := Listener("https", 443, gwapiv1alpha2.HTTPSProtocolType, "foo.com")
+	terminate := gwapiv1alpha2.TLSModeTerminate
+	listener.TLS = &gwapiv1alpha2.GatewayTLSConfig{
+		Mode:            &terminate,
+		CertificateRefs: []*gwapiv1alpha2.SecretObjectReference{{Name: gwapiv1alpha2.ObjectName(secretName)}},
+	}
+	SetupGateway(t, gatewayName, ns, gwClassName, []gwapiv1alpha2.Listener{listener})
+
+	g.Eventually(func() string {
+		_, reason := GetListenerCondition(gatewayName, ns, "https", string(gwapiv1alpha2.ListenerConditionResolvedRefs))
+		return reason
+	}, 30*time.Second).Should(gomega.Equal(string(gwapiv1alpha2.ListenerReasonInvalidCertificateRef)))
+	status, _ := GetListenerCondition(gatewayName, ns, "https", string(gwapiv1alpha2.ListenerConditionReady))
+	g.Expect(status).To(gomega.Equal(string(metav1.ConditionFalse)))
+	status, reason := GetGatewayCondition(gatewayName, ns, string(gwapiv1alpha2.GatewayConditionScheduled))
+	g.Expect(status).To(gomega.Equal(string(metav1.ConditionFalse)))
+	g.Expect(reason).To(gomega.Equal(string(gwapiv1alpha2.GatewayReasonListenersNotValid)))
+	g.Expect(GetGatewayVSNode(modelName)).To(gomega.BeNil())
+
+	integrationtest.AddSecret(secretName, ns, "tlsCert", "tlsKey")
+	g.Eventually(func() string {
+		status, _ := GetListenerCondition(gatewayName, ns, "https", string(gwapiv1alpha2.ListenerConditionReady))
+		return status
+	}, 30*time.Second).Should(gomega.Equal(string(metav1.ConditionTrue)))
+	g.Eventually(func() string {
+		status, _ := GetGatewayCondition(gatewayName, ns, string(gwapiv1alpha2.GatewayConditionScheduled))
+		return status
+	}, 30*time.Second).Should(gomega.Equal(string(metav1.ConditionTrue)))
+	g.Eventually(func() bool {
+		return GetGatewayVSNode(modelName) != nil
+	}, 30*time.Second).Should(gomega.BeTrue())
+	vsNode := GetGatewayVSNode(modelName)
+	g.Expect(vsNode.ApplicationProfile).To(gomega.Equal(utils.DEFAULT_L7_SECURE_APP_PROFILE))
+	g.Expect(vsNode.PortProto).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PortProto[0].Port).To(gomega.Equal(int32(443)))
+	g.Expect(vsNode.PortProto[0].EnableSSL).To(gomega.BeTrue())
+	g.Expect(vsNode.SSLKeyCertRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.SSLKeyCertRefs[0].Name).To(gomega.Equal(lib.GetGatewayAPITLSKeyCertName(gatewayName, ns, secretName, ns)))
+
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	integrationtest.DeleteSecret(secretName, ns)
+}
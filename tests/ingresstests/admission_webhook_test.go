@@ -0,0 +1,234 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// admissionReview sends the object to the admission webhook, and returns the response.
+func admissionReview(t *testing.T, kind string, operation admissionv1.Operation, obj, oldObj runtime.Object) *admissionv1.AdmissionResponse {
+	objMeta := obj.(metav1.Object)
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       types.UID("review-" + objMeta.GetName()),
+			Kind:      metav1.GroupVersionKind{Group: "ako.vmware.com", Version: "v1alpha1", Kind: kind},
+			Namespace: objMeta.GetNamespace(),
+			Name:      objMeta.GetName(),
+			Operation: operation,
+			Object:    runtime.RawExtension{Object: obj},
+		},
+	}
+	if oldObj != nil {
+		review.Request.OldObject = runtime.RawExtension{Object: oldObj}
+	}
+	body, err := json.Marshal(review)
+	if err != nil {
+		t.Fatalf("error in marshalling AdmissionReview: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	k8s.ServeAdmissionReview(w, httptest.NewRequest(http.MethodPost, lib.AdmissionWebhookPath, bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("admission webhook returned %d: %s", w.Code, w.Body.String())
+	}
+	response := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Response == nil {
+		t.Fatalf("error in reading AdmissionReview response: %v", err)
+	}
+	if response.Response.UID != review.Request.UID {
+		t.Fatalf("AdmissionReview response uid %s does not match the request", response.Response.UID)
+	}
+	return response.Response
+}
+
+func rejectionMessage(response *admissionv1.AdmissionResponse) string {
+	if response.Allowed || response.Result == nil {
+		return ""
+	}
+	return response.Result.Message
+}
+
+func TestAdmissionWebhookHostRule(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	k8s.SetAdmissionWebhookReady(KubeClient, true)
+	defer k8s.SetAdmissionWebhookReady(KubeClient, false)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+	integrationtest.SetupHostRule(t, "admission-hr-foo", "foo.com", false)
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), "admission-hr-foo", metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	hostrule := integrationtest.FakeHostRule{
+		Name:               "admission-hr-bar",
+		Namespace:          "default",
+		Fqdn:               "bar.com",
+		WafPolicy:          "thisisaviref-waf",
+		ApplicationProfile: "thisisaviref-appprof",
+	}.HostRule()
+	response := admissionReview(t, lib.HostRule, admissionv1.Create, hostrule, nil)
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+
+	// the referred WAF policy does not exist on the controller
+	badRef := hostrule.DeepCopy()
+	badRef.Spec.VirtualHost.WAFPolicy = "thisisBADaviref-waf"
+	response = admissionReview(t, lib.HostRule, admissionv1.Create, badRef, nil)
+	g.Expect(response.Allowed).To(gomega.BeFalse())
+	g.Expect(rejectionMessage(response)).To(gomega.Equal(`wafpolicy "thisisBADaviref-waf" not found on controller`))
+
+	// the fqdn is used by another HostRule
+	duplicate := hostrule.DeepCopy()
+	duplicate.Spec.VirtualHost.Fqdn = "foo.com"
+	response = admissionReview(t, lib.HostRule, admissionv1.Create, duplicate, nil)
+	g.Expect(rejectionMessage(response)).To(gomega.Equal("duplicate fqdn foo.com found in default/admission-hr-foo"))
+
+	// the alias is a host of an Ingress
+	alias := hostrule.DeepCopy()
+	alias.Spec.VirtualHost.FqdnType = v1alpha1.Exact
+	alias.Spec.VirtualHost.Aliases = []string{"foo.com"}
+	response = admissionReview(t, lib.HostRule, admissionv1.Create, alias, nil)
+	g.Expect(rejectionMessage(response)).To(gomega.ContainSubstring("foo.com is already in use by hostrule default/admission-hr-foo"))
+	alias.Spec.VirtualHost.Aliases = []string{"alias.foo.com"}
+	ingressObject := integrationtest.FakeIngress{
+		Name:        "foo-with-alias-host",
+		Namespace:   "default",
+		DnsNames:    []string{"alias.foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/alias"},
+		ServiceName: "avisvc",
+	}
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingressObject.Ingress(), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	g.Eventually(func() string {
+		return rejectionMessage(admissionReview(t, lib.HostRule, admissionv1.Create, alias, nil))
+	}, 10*time.Second).Should(gomega.Equal("alias alias.foo.com is already a host of default/foo-with-alias-host"))
+
+	// schema level problems
+	invalid := hostrule.DeepCopy()
+	invalid.Spec.VirtualHost.Gslb.Fqdn = "bar.com"
+	response = admissionReview(t, lib.HostRule, admissionv1.Create, invalid, nil)
+	g.Expect(rejectionMessage(response)).To(gomega.Equal("GSLB FQDN and local FQDN are same"))
+
+	// the updates which do not change the spec are not validated again
+	labelled := invalid.DeepCopy()
+	labelled.Labels = map[string]string{"team": "web"}
+	response = admissionReview(t, lib.HostRule, admissionv1.Update, labelled, invalid)
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+	response = admissionReview(t, lib.HostRule, admissionv1.Update, invalid, hostrule)
+	g.Expect(response.Allowed).To(gomega.BeFalse())
+
+	KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-alias-host", metav1.DeleteOptions{})
+	integrationtest.TearDownHostRuleWithNoVerify(t, g, "admission-hr-foo")
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestAdmissionWebhookHTTPRuleAndAviInfraSetting(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	k8s.SetAdmissionWebhookReady(KubeClient, true)
+	defer k8s.SetAdmissionWebhookReady(KubeClient, false)
+
+	httprule := integrationtest.FakeHTTPRule{
+		Name:      "admission-rr",
+		Namespace: "default",
+		Fqdn:      "foo.com",
+		PathProperties: []integrationtest.FakeHTTPRulePath{{
+			Path:           "/foo",
+			SslProfile:     "thisisaviref-sslprofile",
+			HealthMonitors: []string{"thisisaviref-hm1"},
+		}},
+	}.HTTPRule()
+	response := admissionReview(t, lib.HTTPRule, admissionv1.Create, httprule, nil)
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+
+	httprule.Spec.Paths[0].RequestHeaders = []v1alpha1.HTTPRuleHeaderAction{{Action: v1alpha1.HTTPRuleHeaderActionRemove, Name: "X-Debug", Value: "1"}}
+	response = admissionReview(t, lib.HTTPRule, admissionv1.Create, httprule, nil)
+	g.Expect(rejectionMessage(response)).To(gomega.Equal("header value cannot be set for Remove action of header X-Debug on path /foo"))
+
+	httprule.Spec.Paths[0].RequestHeaders = nil
+	httprule.Spec.Paths[0].HealthMonitors = []string{"thisisBADaviref-hm1"}
+	response = admissionReview(t, lib.HTTPRule, admissionv1.Create, httprule, nil)
+	g.Expect(rejectionMessage(response)).To(gomega.Equal(`healthmonitor "thisisBADaviref-hm1" not found on controller`))
+
+	infraSetting := integrationtest.FakeAviInfraSetting{
+		Name:        "admission-infra",
+		SeGroupName: "thisisaviref-seGroup",
+		Networks:    []string{"thisisaviref-networkName"},
+		EnableRhi:   false,
+	}.AviInfraSetting()
+	response = admissionReview(t, lib.AviInfraSetting, admissionv1.Create, infraSetting, nil)
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+
+	infraSetting.Spec.Network.BgpPeerLabels = []string{"peer1"}
+	response = admissionReview(t, lib.AviInfraSetting, admissionv1.Create, infraSetting, nil)
+	g.Expect(rejectionMessage(response)).To(gomega.Equal("BGPPeerLabels cannot be set if EnableRhi is false."))
+
+	infraSetting.Spec.Network.BgpPeerLabels = nil
+	infraSetting.Spec.SeGroup.Name = "thisisBADaviref-seGroup"
+	response = admissionReview(t, lib.AviInfraSetting, admissionv1.Create, infraSetting, nil)
+	g.Expect(rejectionMessage(response)).To(gomega.Equal(`serviceenginegroup "thisisBADaviref-seGroup" not found on controller`))
+
+	// deletes are always allowed
+	response = admissionReview(t, lib.AviInfraSetting, admissionv1.Delete, infraSetting, nil)
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+}
+
+func TestAdmissionWebhookNotReady(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the requests fail until AKO is ready to serve them, and are accepted or rejected as per the failurePolicy.
+	hostrule := integrationtest.FakeHostRule{Name: "admission-hr-notready", Namespace: "default", Fqdn: "notready.com"}.HostRule()
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       types.UID("review-" + hostrule.Name),
+			Kind:      metav1.GroupVersionKind{Group: "ako.vmware.com", Version: "v1alpha1", Kind: lib.HostRule},
+			Namespace: hostrule.Namespace,
+			Name:      hostrule.Name,
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Object: hostrule},
+		},
+	}
+	body, _ := json.Marshal(review)
+	w := httptest.NewRecorder()
+	k8s.ServeAdmissionReview(w, httptest.NewRequest(http.MethodPost, lib.AdmissionWebhookPath, bytes.NewReader(body)))
+	g.Expect(w.Code).To(gomega.Equal(http.StatusServiceUnavailable))
+
+	k8s.SetAdmissionWebhookReady(KubeClient, true)
+	defer k8s.SetAdmissionWebhookReady(KubeClient, false)
+	g.Expect(admissionReview(t, lib.HostRule, admissionv1.Create, hostrule, nil).Allowed).To(gomega.BeTrue())
+}
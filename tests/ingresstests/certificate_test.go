@@ -0,0 +1,232 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// createTestCertificate creates a certificate signed by parent, or a self signed CA certificate if parent is nil.
+func createTestCertificate(t *testing.T, cn string, isCA bool, notAfter time.Time, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error in generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	if !isCA {
+		template.DNSNames = []string{cn}
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("error in creating certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCertificate{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// updateSecretWithChain updates my-secret with a leaf certificate for foo.com expiring at notAfter, bundled with its
+// intermediate in tls.crt, and the root in ca.crt.
+func updateSecretWithChain(t *testing.T, notAfter time.Time) (root, intermediate, leaf *testCertificate) {
+	root = createTestCertificate(t, "root-ca", true, time.Now().Add(10*365*24*time.Hour), nil)
+	intermediate = createTestCertificate(t, "intermediate-ca", true, time.Now().Add(5*365*24*time.Hour), root)
+	leaf = createTestCertificate(t, "foo.com", false, notAfter, intermediate)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "my-secret",
+			Namespace:       "default",
+			ResourceVersion: "2",
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"tls.crt":               append(append([]byte{}, leaf.pem...), intermediate.pem...),
+			"tls.key":               []byte("tlsKey"),
+			utils.K8S_TLS_SECRET_CA: root.pem,
+		},
+	}
+	if _, err := KubeClient.CoreV1().Secrets("default").Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Secret: %v", err)
+	}
+	return root, intermediate, leaf
+}
+
+func TestSecretCertificateChain(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+	root, intermediate, leaf := updateSecretWithChain(t, time.Now().Add(365*24*time.Hour))
+
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 0 || len(nodes[0].SniNodes) == 0 {
+			return 0
+		}
+		return len(nodes[0].SniNodes[0].CACertRefs)
+	}, 10*time.Second).Should(gomega.Equal(2))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	sniNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0]
+	g.Expect(sniNode.SSLKeyCertRefs).To(gomega.HaveLen(1))
+	g.Expect(sniNode.SSLKeyCertRefs[0].Cert).To(gomega.Equal(leaf.pem))
+	g.Expect(sniNode.SSLKeyCertRefs[0].Secret).To(gomega.Equal("default/my-secret"))
+	g.Expect(sniNode.SSLKeyCertRefs[0].OCSPConfig).To(gomega.BeNil())
+
+	// the CA certs are ordered root first, each cert referring to its issuer
+	issuerName := lib.GetCACertChainNodeName("", "foo.com", 0)
+	g.Expect(sniNode.SSLKeyCertRefs[0].CACert).To(gomega.Equal(issuerName))
+	g.Expect(sniNode.CACertRefs[0].Name).To(gomega.Equal(lib.GetCACertChainNodeName("", "foo.com", 1)))
+	g.Expect(sniNode.CACertRefs[0].Cert).To(gomega.Equal(root.pem))
+	g.Expect(sniNode.CACertRefs[0].CACert).To(gomega.BeEmpty())
+	g.Expect(sniNode.CACertRefs[1].Name).To(gomega.Equal(issuerName))
+	g.Expect(sniNode.CACertRefs[1].Cert).To(gomega.Equal(intermediate.pem))
+	g.Expect(sniNode.CACertRefs[1].CACert).To(gomega.Equal(sniNode.CACertRefs[0].Name))
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestCertificateExpiryAlerts(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+	_, _, leaf := updateSecretWithChain(t, time.Now().Add(10*24*time.Hour))
+
+	certName := lib.GetTLSKeyCertNodeName("", "foo.com", "")
+	g.Eventually(func() bool {
+		expiry, _ := k8s.CertificateExpiryAlerts(time.Now())
+		_, found := expiry[certName]
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+
+	expiry, alerts := k8s.CertificateExpiryAlerts(time.Now())
+	g.Expect(expiry[certName].Secret).To(gomega.Equal("default/my-secret"))
+	g.Expect(expiry[certName].NotAfter.Equal(leaf.cert.NotAfter)).To(gomega.BeTrue())
+	g.Expect(alerts).To(gomega.HaveLen(1))
+	g.Expect(alerts[0].Name).To(gomega.Equal(certName))
+	g.Expect(alerts[0].Model).To(gomega.Equal(modelName))
+	g.Expect(alerts[0].Hosts).To(gomega.ContainElement("foo.com"))
+	g.Expect(alerts[0].Reason).To(gomega.Equal(lib.CertificateExpiring))
+
+	_, alerts = k8s.CertificateExpiryAlerts(time.Now().Add(11 * 24 * time.Hour))
+	g.Expect(alerts).To(gomega.HaveLen(1))
+	g.Expect(alerts[0].Reason).To(gomega.Equal(lib.CertificateExpired))
+
+	_, alerts = k8s.CertificateExpiryAlerts(time.Now().Add(-30 * 24 * time.Hour))
+	g.Expect(alerts).To(gomega.BeEmpty())
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestHostRuleOCSPStapling(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	hostrule := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hostrule.Spec.VirtualHost.TLS.OCSPStapling = &v1alpha1.HostRuleOCSPStapling{
+		ResponderURLs: []string{"http://ocsp.foo.com"},
+	}
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Create(context.TODO(), hostrule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/samplehr-foo", true)
+	g.Eventually(func() bool {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		sniNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0]
+		return len(sniNode.SSLKeyCertRefs) == 1 && sniNode.SSLKeyCertRefs[0].OCSPConfig != nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	ocspConfig := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0].SSLKeyCertRefs[0].OCSPConfig
+	g.Expect(ocspConfig.ResponderURLLists).To(gomega.Equal([]string{"http://ocsp.foo.com"}))
+	g.Expect(*ocspConfig.URLAction).To(gomega.Equal(lib.OCSPResponderURLFailover))
+	g.Expect(*ocspConfig.OcspReqInterval).To(gomega.Equal(int32(lib.DefaultOCSPRequestInterval)))
+
+	// OCSP stapling is not supported with the certificates referred from the Avi controller
+	hrUpdate := hostrule.DeepCopy()
+	hrUpdate.Spec.VirtualHost.TLS.SSLKeyCertificate.Name = "thisisaviref-sslkey"
+	hrUpdate.ResourceVersion = "2"
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	g.Eventually(func() bool {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		sniNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0]
+		return len(sniNode.SSLKeyCertRefs) == 1 && sniNode.SSLKeyCertRefs[0].OCSPConfig == nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
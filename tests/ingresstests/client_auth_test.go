@@ -0,0 +1,252 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getClientAuthAppProfile(modelName string) *avinodes.AviAppProfileNode {
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	if len(nodes) == 0 || len(nodes[0].SniNodes) == 0 {
+		return nil
+	}
+	return nodes[0].SniNodes[0].ClientAuthAppProfile
+}
+
+func createClientAuthCASecret(t *testing.T, g *gomega.WithT) (*testCertificate, *corev1.Secret) {
+	root := createTestCertificate(t, "client-root-ca", true, time.Now().Add(365*24*time.Hour), nil)
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "client-ca",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			lib.ClientAuthCACertKey: root.pem,
+			lib.ClientAuthCRLKey:    []byte("client-ca-crl"),
+		},
+	}
+	if _, err := KubeClient.CoreV1().Secrets("default").Create(context.TODO(), caSecret, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Secret: %v", err)
+	}
+	g.Eventually(func() error {
+		_, err := utils.GetInformers().SecretInformer.Lister().Secrets("default").Get("client-ca")
+		return err
+	}, 10*time.Second).Should(gomega.BeNil())
+	return root, caSecret
+}
+
+func createClientAuthHostRule(t *testing.T, hrname string) *v1alpha1.HostRule {
+	hostrule := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hostrule.Spec.VirtualHost.TLS.ClientAuth = &v1alpha1.HostRuleClientAuth{
+		CASecret: "client-ca",
+		Headers: []v1alpha1.HostRuleClientAuthHeader{
+			{Name: "X-Client-Subject", Value: v1alpha1.ClientCertSubject},
+			{Name: "X-Client-Cert", Value: v1alpha1.ClientCertCertificate},
+		},
+	}
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Create(context.TODO(), hostrule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HostRule: %v", err)
+	}
+	return hostrule
+}
+
+func TestHostRuleClientAuth(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	var lock sync.Mutex
+	var postedAppProfile *models.ApplicationProfile
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" && strings.Contains(r.URL.EscapedPath(), "/api/applicationprofile") {
+			data, _ := ioutil.ReadAll(r.Body)
+			lock.Lock()
+			postedAppProfile = &models.ApplicationProfile{}
+			json.Unmarshal(data, postedAppProfile)
+			lock.Unlock()
+			r.Body = ioutil.NopCloser(strings.NewReader(string(data)))
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+	defer integrationtest.ResetMiddleware()
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	root, caSecret := createClientAuthCASecret(t, g)
+	hostrule := createClientAuthHostRule(t, hrname)
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/samplehr-foo", true)
+	g.Eventually(func() bool {
+		return getClientAuthAppProfile(modelName) != nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+
+	appProfile := getClientAuthAppProfile(modelName)
+	appProfileName := lib.GetClientAuthAppProfileName(sniVSKey.Name)
+	pkiProfileName := lib.GetClientAuthPKIProfileName(sniVSKey.Name)
+	g.Expect(appProfile.Name).To(gomega.Equal(appProfileName))
+	g.Expect(appProfile.BaseProfile).To(gomega.Equal(utils.DEFAULT_L7_SECURE_APP_PROFILE))
+	g.Expect(appProfile.ClientCertMode).To(gomega.Equal(lib.SSLClientCertificateRequire))
+	g.Expect(appProfile.PkiProfile).NotTo(gomega.BeNil())
+	g.Expect(appProfile.PkiProfile.Name).To(gomega.Equal(pkiProfileName))
+	g.Expect(appProfile.PkiProfile.CACert).To(gomega.Equal(string(root.pem)))
+	g.Expect(appProfile.PkiProfile.CRL).To(gomega.Equal("client-ca-crl"))
+	g.Expect(appProfile.ClientCertHeaders).To(gomega.Equal([]avinodes.AviClientCertHeader{
+		{Name: "X-Client-Subject", Value: "HTTP_POLICY_VAR_SSL_CLIENT_SUBJECT"},
+		{Name: "X-Client-Cert", Value: "HTTP_POLICY_VAR_SSL_CLIENT_RAW"},
+	}))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	sniNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0]
+	g.Expect(sniNode.AppProfileRef).To(gomega.Equal("/api/applicationprofile?name=" + appProfileName))
+
+	mcache := cache.SharedAviObjCache()
+	appProfileKey := cache.NamespaceName{Namespace: "admin", Name: appProfileName}
+	pkiProfileKey := cache.NamespaceName{Namespace: "admin", Name: pkiProfileName}
+	g.Eventually(func() bool {
+		_, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() bool {
+		_, found := mcache.PKIProfileCache.AviCacheGet(pkiProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+
+	// the application profile is cloned from the one the SNI child uses otherwise
+	lock.Lock()
+	g.Expect(postedAppProfile).NotTo(gomega.BeNil())
+	g.Expect(*postedAppProfile.Name).To(gomega.Equal(appProfileName))
+	g.Expect(postedAppProfile.UUID).To(gomega.BeNil())
+	g.Expect(*postedAppProfile.HTTPProfile.HTTPToHTTPS).To(gomega.BeTrue())
+	g.Expect(*postedAppProfile.HTTPProfile.WebsocketsEnabled).To(gomega.BeTrue())
+	g.Expect(*postedAppProfile.HTTPProfile.SslClientCertificateMode).To(gomega.Equal(lib.SSLClientCertificateRequire))
+	g.Expect(*postedAppProfile.HTTPProfile.PkiProfileRef).To(gomega.Equal("/api/pkiprofile?name=" + pkiProfileName))
+	lock.Unlock()
+
+	// the PKI profile is updated along with the CA certificates in the Secret
+	newRoot := createTestCertificate(t, "client-root-ca-2", true, time.Now().Add(365*24*time.Hour), nil)
+	caSecret.Data[lib.ClientAuthCACertKey] = newRoot.pem
+	caSecret.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Secrets("default").Update(context.TODO(), caSecret, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Secret: %v", err)
+	}
+	g.Eventually(func() string {
+		appProfile := getClientAuthAppProfile(modelName)
+		if appProfile == nil || appProfile.PkiProfile == nil {
+			return ""
+		}
+		return appProfile.PkiProfile.CACert
+	}, 10*time.Second).Should(gomega.Equal(string(newRoot.pem)))
+
+	// either a caSecret or a pkiProfile is required
+	hrUpdate := hostrule.DeepCopy()
+	hrUpdate.Spec.VirtualHost.TLS.ClientAuth.PKIProfile = "thisisaviref-pkiprofile"
+	hrUpdate.ResourceVersion = "2"
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	g.Eventually(func() bool {
+		return getClientAuthAppProfile(modelName) == nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() bool {
+		_, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+	g.Eventually(func() bool {
+		_, found := mcache.PKIProfileCache.AviCacheGet(pkiProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+
+	if err := KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "client-ca", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Secret: %v", err)
+	}
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+// TestHostRuleClientAuthWithNewParent creates the Ingress after the HostRule, so that the SNI child with the client
+// certificate settings is created along with its parent virtualservice.
+func TestHostRuleClientAuthWithNewParent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	CleanupCache("cluster--Shared-L7-0")
+
+	createClientAuthCASecret(t, g)
+	createClientAuthHostRule(t, hrname)
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	g.Eventually(func() bool {
+		return getClientAuthAppProfile(modelName) != nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	mcache := cache.SharedAviObjCache()
+	appProfileKey := cache.NamespaceName{Namespace: "admin", Name: lib.GetClientAuthAppProfileName(sniVSKey.Name)}
+	pkiProfileKey := cache.NamespaceName{Namespace: "admin", Name: lib.GetClientAuthPKIProfileName(sniVSKey.Name)}
+	g.Eventually(func() bool {
+		_, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() bool {
+		_, found := mcache.PKIProfileCache.AviCacheGet(pkiProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/samplehr-foo", true)
+
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	g.Eventually(func() bool {
+		_, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+	if err := KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "client-ca", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Secret: %v", err)
+	}
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 
 	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -105,6 +106,82 @@ func TestCreateDeleteHostRule(t *testing.T) {
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
 
+func TestHostRuleSecurity(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	hostrule := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hostrule.Spec.VirtualHost.Security = &v1alpha1.HostRuleSecurity{
+		RateLimits: &v1alpha1.HostRuleRateLimits{
+			Connections: &v1alpha1.HostRuleRateLimit{Count: 1000},
+			Requests:    &v1alpha1.HostRuleRateLimit{Count: 100, Burst: 20, PerClientIP: true},
+		},
+		AllowedCIDRs:   []string{"10.10.0.0/16"},
+		DeniedCIDRs:    []string{"10.10.10.10"},
+		DeniedIPGroups: []string{"thisisaviref-ipgroup"},
+	}
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Create(context.TODO(), hostrule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HostRule: %v", err)
+	}
+
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/samplehr-foo", true)
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	sniNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0]
+	g.Expect(sniNode.ConnectionsRateLimit).NotTo(gomega.BeNil())
+	g.Expect(*sniNode.ConnectionsRateLimit.RateLimiter.Count).To(gomega.Equal(int32(1000)))
+	g.Expect(*sniNode.ConnectionsRateLimit.RateLimiter.Period).To(gomega.Equal(int32(1)))
+	g.Expect(sniNode.HttpPolicyRefs[0].Name).To(gomega.Equal(lib.GetHostRuleSecurityPolicySetName(sniNode.Name)))
+	securityRules := sniNode.HttpPolicyRefs[0].SecurityRules
+	g.Expect(securityRules).To(gomega.HaveLen(3))
+	g.Expect(securityRules[0].Action).To(gomega.Equal(lib.HTTPSecurityActionSendResponse))
+	g.Expect(securityRules[0].ClientIP.MatchCriteria).To(gomega.Equal("IS_IN"))
+	g.Expect(securityRules[0].ClientIP.Prefixes).To(gomega.Equal([]string{"10.10.10.10"}))
+	g.Expect(securityRules[0].ClientIP.IPGroups).To(gomega.Equal([]string{"thisisaviref-ipgroup"}))
+	g.Expect(securityRules[1].ClientIP.MatchCriteria).To(gomega.Equal("IS_NOT_IN"))
+	g.Expect(securityRules[1].ClientIP.Prefixes).To(gomega.Equal([]string{"10.10.0.0/16"}))
+	g.Expect(securityRules[2].Action).To(gomega.Equal(lib.HTTPSecurityActionRateLimit))
+	g.Expect(*securityRules[2].RateLimit).To(gomega.Equal(avinodes.AviHTTPSecurityRateLimit{Count: 100, Period: 1, Burst: 20, PerClientIP: true}))
+
+	// invalid CIDRs reject the hostrule, the applied security settings are retained
+	hrUpdate := hostrule.DeepCopy()
+	hrUpdate.Spec.VirtualHost.Security.AllowedCIDRs = []string{"10.10.0.0/33"}
+	hrUpdate.ResourceVersion = "2"
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	g.Eventually(func() bool {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		sniNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0]
+		for _, policy := range sniNode.HttpPolicyRefs {
+			if len(policy.SecurityRules) > 0 {
+				return false
+			}
+		}
+		return sniNode.ConnectionsRateLimit == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
 func TestCreateDeleteSharedVSHostRule(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
@@ -1227,3 +1304,335 @@ func TestHTTPRuleHostSwitch(t *testing.T) {
 	integrationtest.TeardownHTTPRule(t, rrnameFoo)
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
+
+func TestHTTPRulePathActions(t *testing.T) {
+	// ingress secure foo.com/foo /bar
+	// create httprule with header and rewrite actions on /foo, and a redirect on /bar
+	// httppolicysets get attached to the SNI child, ahead of the switching httppolicyset
+	// delete httprule, httppolicysets get detached
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-foo"
+
+	SetupDomain()
+	SetUpTestForIngress(t, modelName)
+	integrationtest.AddSecret("my-secret", "default", "tlsCert", "tlsKey")
+	integrationtest.PollForCompletion(t, modelName, 5)
+	ingressObject := integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/foo", "/bar"},
+		ServiceName: "avisvc",
+		TlsSecretDNS: map[string][]string{
+			"my-secret": {"foo.com"},
+		},
+	}
+
+	ingrFake := ingressObject.Ingress(true)
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	httprule := &v1alpha1.HTTPRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      rrname,
+		},
+		Spec: v1alpha1.HTTPRuleSpec{
+			Fqdn: "foo.com",
+			Paths: []v1alpha1.HTTPRulePaths{{
+				Target: "/foo",
+				RequestHeaders: []v1alpha1.HTTPRuleHeaderAction{
+					{Action: v1alpha1.HTTPRuleHeaderActionAdd, Name: "X-Env", Value: "staging"},
+					{Action: v1alpha1.HTTPRuleHeaderActionRemove, Name: "X-Debug"},
+				},
+				ResponseHeaders: []v1alpha1.HTTPRuleHeaderAction{
+					{Action: v1alpha1.HTTPRuleHeaderActionReplace, Name: "Server", Value: "avi"},
+				},
+				RewritePrefix: "/v2",
+			}, {
+				Target: "/bar",
+				Redirect: &v1alpha1.HTTPRuleRedirect{
+					Protocol:   "HTTPS",
+					StatusCode: 301,
+				},
+			}},
+		},
+	}
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Create(context.TODO(), httprule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+
+	g.Eventually(func() int {
+		rr, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return len(rr.Status.PathActions)
+	}, 10*time.Second).Should(gomega.Equal(2))
+	rr, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+	g.Expect(rr.Status.Status).To(gomega.Equal("Accepted"))
+	g.Expect(rr.Status.PathActions[0].Target).To(gomega.Equal("/foo"))
+	g.Expect(rr.Status.PathActions[0].Actions).To(gomega.Equal([]string{"RequestHeaders", "ResponseHeaders", "RewritePrefix"}))
+	g.Expect(rr.Status.PathActions[1].Target).To(gomega.Equal("/bar"))
+	g.Expect(rr.Status.PathActions[1].Actions).To(gomega.Equal([]string{"Redirect"}))
+
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes[0].SniNodes) != 1 {
+			return 0
+		}
+		return len(nodes[0].SniNodes[0].HttpPolicyRefs)
+	}, 25*time.Second).Should(gomega.Equal(3))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	sniNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0]
+	// the switching policyset comes first, so that the pools of /foo and /bar are selected before /foo is rewritten
+	g.Expect(sniNode.HttpPolicyRefs[0].HTTPRuleHost).To(gomega.Equal(""))
+	switchingPaths := make(map[string]string)
+	for _, hppMap := range sniNode.HttpPolicyRefs[0].HppMap {
+		g.Expect(hppMap.Path).To(gomega.HaveLen(1))
+		switchingPaths[hppMap.Path[0]] = hppMap.PoolGroup
+	}
+	g.Expect(switchingPaths).To(gomega.Equal(map[string]string{
+		"/foo": lib.GetSniPGName("foo-with-targets", "default", "foo.com", "/foo", "", false),
+		"/bar": lib.GetSniPGName("foo-with-targets", "default", "foo.com", "/bar", "", false),
+	}))
+	// the policysets of the longer path targets follow first, the redirect on /bar precedes the actions on /foo
+	g.Expect(sniNode.HttpPolicyRefs[1].Name).To(gomega.Equal(lib.GetHTTPRulePolicySetName(sniNode.Name, "foo.com", "/bar")))
+	g.Expect(sniNode.HttpPolicyRefs[1].HppMap[0].Redirect.Protocol).To(gomega.Equal("HTTPS"))
+	g.Expect(sniNode.HttpPolicyRefs[1].HppMap[0].Redirect.StatusCode).To(gomega.Equal(lib.STATUS_REDIRECT_PERMANENT))
+	g.Expect(sniNode.HttpPolicyRefs[2].Name).To(gomega.Equal(lib.GetHTTPRulePolicySetName(sniNode.Name, "foo.com", "/foo")))
+	fooPath := sniNode.HttpPolicyRefs[2].HppMap[0]
+	g.Expect(fooPath.Path).To(gomega.Equal([]string{"/foo"}))
+	g.Expect(fooPath.MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+	g.Expect(fooPath.Pool).To(gomega.Equal(""))
+	g.Expect(fooPath.PoolGroup).To(gomega.Equal(""))
+	g.Expect(fooPath.RequestHeaders).To(gomega.HaveLen(2))
+	g.Expect(fooPath.RequestHeaders[0].Action).To(gomega.Equal(lib.HTTPAddHeader))
+	g.Expect(fooPath.RequestHeaders[1].Action).To(gomega.Equal(lib.HTTPRemoveHeader))
+	g.Expect(fooPath.ResponseHeaders).To(gomega.HaveLen(1))
+	g.Expect(fooPath.ResponseHeaders[0].Action).To(gomega.Equal(lib.HTTPReplaceHeader))
+	g.Expect(fooPath.RewriteURL.Path).To(gomega.Equal("/v2"))
+	g.Expect(fooPath.RewriteURL.PrefixTokens).To(gomega.Equal(int32(1)))
+
+	// delete httprule deletes the httppolicysets as well
+	integrationtest.TeardownHTTPRule(t, rrname)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes[0].SniNodes) != 1 {
+			return 0
+		}
+		return len(nodes[0].SniNodes[0].HttpPolicyRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestHTTPRuleCanaryBackends(t *testing.T) {
+	// ingress secure foo.com/foo with service avisvc
+	// create httprule with backends avisvc weight 90, avisvc-canary weight 10 pinned by header and cookie
+	// canary pool gets added to the PG of the path, and a httppolicyset selects it for the matching requests
+	// remove the backends, canary pool and httppolicyset get removed
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-foo"
+	canarySvc := "avisvc-canary"
+
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+	integrationtest.CreateSVC(t, "default", canarySvc, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "default", canarySvc, false, false, "2.2.2")
+
+	canaryWeight, primaryWeight := int32(10), int32(90)
+	httprule := &v1alpha1.HTTPRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      rrname,
+		},
+		Spec: v1alpha1.HTTPRuleSpec{
+			Fqdn: "foo.com",
+			Paths: []v1alpha1.HTTPRulePaths{{
+				Target: "/foo",
+				Backends: []v1alpha1.HTTPRuleBackend{{
+					ServiceName: "avisvc",
+					Weight:      &primaryWeight,
+				}, {
+					ServiceName: canarySvc,
+					Weight:      &canaryWeight,
+					Match: &v1alpha1.HTTPRuleBackendMatch{
+						Headers: []v1alpha1.HTTPRuleMatchCondition{{Name: "X-Canary", Value: "true"}},
+						Cookies: []v1alpha1.HTTPRuleMatchCondition{{Name: "canary", Value: "always"}},
+					},
+				}},
+			}},
+		},
+	}
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Create(context.TODO(), httprule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+
+	g.Eventually(func() int {
+		rr, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return len(rr.Status.PathActions)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	rr, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+	g.Expect(rr.Status.Status).To(gomega.Equal("Accepted"))
+	g.Expect(rr.Status.PathActions[0].Actions).To(gomega.Equal([]string{"Backends"}))
+
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes[0].SniNodes) != 1 {
+			return 0
+		}
+		return len(nodes[0].SniNodes[0].PoolRefs)
+	}, 25*time.Second).Should(gomega.Equal(2))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	sniNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0]
+	primaryPool := lib.GetSniPoolName("foo-with-targets", "default", "foo.com", "/foo", "", false)
+	canaryPool := lib.GetSniPoolName("foo-with-targets", "default", "foo.com", "/foo", "", false, canarySvc)
+	g.Expect(sniNode.PoolRefs[0].Name).To(gomega.Equal(primaryPool))
+	g.Expect(sniNode.PoolRefs[1].Name).To(gomega.Equal(canaryPool))
+	g.Expect(sniNode.PoolRefs[1].Servers).To(gomega.HaveLen(1))
+	g.Expect(*sniNode.PoolRefs[1].Servers[0].Ip.Addr).To(gomega.Equal("2.2.2.1"))
+	g.Expect(sniNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(sniNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(2))
+	g.Expect(*sniNode.PoolGroupRefs[0].Members[0].Ratio).To(gomega.Equal(primaryWeight))
+	g.Expect(*sniNode.PoolGroupRefs[0].Members[1].PoolRef).To(gomega.Equal("/api/pool?name=" + canaryPool))
+	g.Expect(*sniNode.PoolGroupRefs[0].Members[1].Ratio).To(gomega.Equal(canaryWeight))
+
+	// the httppolicyset pinning the requests to the canary pool precedes the switching httppolicyset
+	g.Expect(sniNode.HttpPolicyRefs).To(gomega.HaveLen(2))
+	g.Expect(sniNode.HttpPolicyRefs[0].Name).To(gomega.Equal(lib.GetHTTPRuleBackendPolicySetName(sniNode.Name, "foo.com", "/foo")))
+	canaryPath := sniNode.HttpPolicyRefs[0].HppMap[0]
+	g.Expect(canaryPath.Pool).To(gomega.Equal(canaryPool))
+	g.Expect(canaryPath.Path).To(gomega.Equal([]string{"/foo"}))
+	g.Expect(canaryPath.HdrMatches).To(gomega.HaveLen(1))
+	g.Expect(canaryPath.HdrMatches[0].Name).To(gomega.Equal("X-Canary"))
+	g.Expect(canaryPath.HdrMatches[0].Value).To(gomega.Equal([]string{"true"}))
+	g.Expect(canaryPath.CookieMatch.Name).To(gomega.Equal("canary"))
+	g.Expect(canaryPath.CookieMatch.Value).To(gomega.Equal("always"))
+	g.Expect(sniNode.HttpPolicyRefs[1].HTTPRuleHost).To(gomega.Equal(""))
+
+	// the canary pool is updated along with the Endpoints of the canary Service, added to the ingress by the HTTPRule
+	g.Eventually(func() []string {
+		_, ingresses := objects.SharedSvcLister().IngressMappings("default").GetSvcToIng(canarySvc)
+		return ingresses
+	}, 10*time.Second).Should(gomega.ContainElement("foo-with-targets"))
+	canaryEP, _ := KubeClient.CoreV1().Endpoints("default").Get(context.TODO(), canarySvc, metav1.GetOptions{})
+	canaryEP.Subsets[0].Addresses = append(canaryEP.Subsets[0].Addresses, corev1.EndpointAddress{IP: "2.2.2.2"})
+	canaryEP.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Endpoints("default").Update(context.TODO(), canaryEP, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Endpoint: %v", err)
+	}
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes[0].SniNodes) != 1 || len(nodes[0].SniNodes[0].PoolRefs) != 2 {
+			return 0
+		}
+		return len(nodes[0].SniNodes[0].PoolRefs[1].Servers)
+	}, 10*time.Second).Should(gomega.Equal(2))
+
+	// removing the backends removes the canary pool and the httppolicyset
+	rr.Spec.Paths[0].Backends = nil
+	rr.ResourceVersion = "2"
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Update(context.TODO(), rr, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes[0].SniNodes) != 1 {
+			return 0
+		}
+		return len(nodes[0].SniNodes[0].PoolRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	sniNode = aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].SniNodes[0]
+	g.Expect(sniNode.PoolRefs[0].Name).To(gomega.Equal(primaryPool))
+	g.Expect(sniNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(*sniNode.PoolGroupRefs[0].Members[0].Ratio).To(gomega.Equal(int32(100)))
+	g.Expect(sniNode.HttpPolicyRefs).To(gomega.HaveLen(1))
+	_, ingresses := objects.SharedSvcLister().IngressMappings("default").GetSvcToIng(canarySvc)
+	g.Expect(ingresses).NotTo(gomega.ContainElement("foo-with-targets"))
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	integrationtest.DelSVC(t, "default", canarySvc)
+	integrationtest.DelEP(t, "default", canarySvc)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestHTTPRuleCanaryBackendsInsecure(t *testing.T) {
+	// ingress insecure foo.com/foo with service avisvc
+	// create httprule with backends avisvc, avisvc-canary pinned by header
+	// canary pool gets added to the shared VS, and the httprule status reports that the match is not applied
+	// remove the match condition, the error is cleared from the httprule status
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-foo"
+	canarySvc := "avisvc-canary"
+
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+	integrationtest.CreateSVC(t, "default", canarySvc, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "default", canarySvc, false, false, "2.2.2")
+
+	httprule := &v1alpha1.HTTPRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      rrname,
+		},
+		Spec: v1alpha1.HTTPRuleSpec{
+			Fqdn: "foo.com",
+			Paths: []v1alpha1.HTTPRulePaths{{
+				Target: "/foo",
+				Backends: []v1alpha1.HTTPRuleBackend{{
+					ServiceName: canarySvc,
+					Match: &v1alpha1.HTTPRuleBackendMatch{
+						Headers: []v1alpha1.HTTPRuleMatchCondition{{Name: "X-Canary", Value: "true"}},
+					},
+				}},
+			}},
+		},
+	}
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Create(context.TODO(), httprule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+
+	g.Eventually(func() string {
+		rr, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return rr.Status.Error
+	}, 25*time.Second).Should(gomega.Equal("backend match conditions on paths /foo are not supported for the insecure host foo.com, the backends are selected by weight"))
+	rr, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+	g.Expect(rr.Status.Status).To(gomega.Equal("Accepted"))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	vsNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0]
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(2))
+	for _, policy := range vsNode.HttpPolicyRefs {
+		g.Expect(policy.HTTPRuleHost).To(gomega.Equal(""))
+	}
+
+	// the error is cleared once there are no match conditions to be applied
+	rr.Spec.Paths[0].Backends[0].Match = nil
+	rr.ResourceVersion = "2"
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Update(context.TODO(), rr, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		rr, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return rr.Status.Error
+	}, 25*time.Second).Should(gomega.Equal(""))
+	rr, _ = CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+	g.Expect(rr.Status.Status).To(gomega.Equal("Accepted"))
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	integrationtest.DelSVC(t, "default", canarySvc)
+	integrationtest.DelEP(t, "default", canarySvc)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
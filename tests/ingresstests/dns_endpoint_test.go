@@ -0,0 +1,120 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func getDNSEndpoints(dnsEndpointClient *dynamicfake.FakeDynamicClient, name string) []interface{} {
+	dnsEndpoint, err := dnsEndpointClient.Resource(lib.DNSEndpointGVR).Namespace(utils.GetAKONamespace()).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	endpoints, _, _ := unstructured.NestedSlice(dnsEndpoint.Object, "spec", "endpoints")
+	return endpoints
+}
+
+func TestDNSEndpointForIngress(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	dnsEndpointClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{lib.DNSEndpointGVR: "DNSEndpointList"})
+	lib.SetDynamicClientSet(dnsEndpointClient)
+	os.Setenv("PUBLISH_DNS_ENDPOINTS", "true")
+	defer func() {
+		os.Setenv("PUBLISH_DNS_ENDPOINTS", "false")
+		lib.SetDynamicClientSet(nil)
+	}()
+
+	// the vsvip is created afresh, for its fqdns to be published.
+	CleanupCache("cluster--Shared-L7-0")
+	cache.SharedAviObjCache().VSVIPCache.AviCacheDelete(cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"})
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+
+	// the shared VS has an fqdn of its own, from the sub-domain of the DNS profile.
+	fooEndpoint := map[string]interface{}{
+		"dnsName":    "foo.com",
+		"recordType": "A",
+		"targets":    []interface{}{"10.250.250.10"},
+	}
+	g.Eventually(func() []interface{} {
+		return getDNSEndpoints(dnsEndpointClient, "admin--cluster--shared-l7-0")
+	}, 30*time.Second).Should(gomega.ContainElement(fooEndpoint))
+	dnsEndpoint, _ := dnsEndpointClient.Resource(lib.DNSEndpointGVR).Namespace(utils.GetAKONamespace()).Get(context.TODO(), "admin--cluster--shared-l7-0", metav1.GetOptions{})
+	g.Expect(dnsEndpoint.GetLabels()).To(gomega.HaveKeyWithValue(lib.ClusterNameLabelKey, "cluster"))
+
+	// the DNSEndpoints without a vsvip are deleted by the bulk sync.
+	stale := &unstructured.Unstructured{}
+	stale.SetAPIVersion("externaldns.k8s.io/v1alpha1")
+	stale.SetKind("DNSEndpoint")
+	stale.SetName("cluster--stale")
+	stale.SetNamespace(utils.GetAKONamespace())
+	stale.SetLabels(map[string]string{lib.ClusterNameLabelKey: "cluster"})
+	if _, err := dnsEndpointClient.Resource(lib.DNSEndpointGVR).Namespace(utils.GetAKONamespace()).Create(context.TODO(), stale, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding DNSEndpoint: %v", err)
+	}
+	// the vsvips of different tenants with the same name get their own DNSEndpoints.
+	status.SyncDNSEndpoints([]status.UpdateOptions{
+		{
+			VSName: "cluster--Shared-L7-0",
+			Tenant: "admin",
+			FQDNs:  []string{"cluster--Shared-L7-0.admin.com", "foo.com"},
+			Vip:    []string{"10.250.250.10"},
+		},
+		{
+			VSName: "cluster--Shared-L7-0",
+			Tenant: "Tenant_1",
+			FQDNs:  []string{"bar.com"},
+			Vip:    []string{"10.250.250.20"},
+		},
+	})
+	g.Expect(getDNSEndpoints(dnsEndpointClient, "admin--cluster--shared-l7-0")).To(gomega.HaveLen(2))
+	g.Expect(getDNSEndpoints(dnsEndpointClient, "tenant-1--cluster--shared-l7-0")).To(gomega.ConsistOf(map[string]interface{}{
+		"dnsName":    "bar.com",
+		"recordType": "A",
+		"targets":    []interface{}{"10.250.250.20"},
+	}))
+	list, _ := dnsEndpointClient.Resource(lib.DNSEndpointGVR).Namespace(utils.GetAKONamespace()).List(context.TODO(), metav1.ListOptions{})
+	g.Expect(list.Items).To(gomega.HaveLen(2))
+	for _, item := range list.Items {
+		g.Expect(item.GetName()).To(gomega.BeElementOf("admin--cluster--shared-l7-0", "tenant-1--cluster--shared-l7-0"))
+	}
+
+	// the records of the fqdn are removed along with the Ingress.
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(func() []interface{} {
+		return getDNSEndpoints(dnsEndpointClient, "admin--cluster--shared-l7-0")
+	}, 30*time.Second).ShouldNot(gomega.ContainElement(fooEndpoint))
+	TearDownTestForIngress(t, modelName)
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	apimodels "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeGSLBServices serves the gslbservice collection of the fake controller from memory.
+type fakeGSLBServices struct {
+	lock     sync.Mutex
+	services map[string]*models.GslbService
+}
+
+func (f *fakeGSLBServices) serve(w http.ResponseWriter, r *http.Request) {
+	url := r.URL.EscapedPath()
+	if !strings.Contains(url, "/api/gslbservice") {
+		integrationtest.NormalControllerServer(w, r)
+		return
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	w.WriteHeader(http.StatusOK)
+	switch r.Method {
+	case "GET":
+		if strings.HasSuffix(url, "/runtime") {
+			w.Write([]byte(`[]`))
+			return
+		}
+		var results []*models.GslbService
+		for name, gs := range f.services {
+			if r.URL.Query().Get("name") == "" || r.URL.Query().Get("name") == name {
+				results = append(results, gs)
+			}
+		}
+		data, _ := json.Marshal(results)
+		fmt.Fprintf(w, `{"count": %d, "results": %s}`, len(results), data)
+	case "POST", "PUT":
+		var gs models.GslbService
+		data, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(data, &gs)
+		uuid := "gslbservice-" + *gs.Name + "-" + integrationtest.RANDOMUUID
+		gs.UUID = &uuid
+		f.services[*gs.Name] = &gs
+		data, _ = json.Marshal(gs)
+		w.Write(data)
+	case "DELETE":
+		for name, gs := range f.services {
+			if strings.HasSuffix(url, *gs.UUID) {
+				delete(f.services, name)
+			}
+		}
+	}
+}
+
+func gslbTestHostRule(algorithm v1alpha1.GSLBAlgorithm, priority int32) *v1alpha1.HostRule {
+	ttl := int32(30)
+	return &v1alpha1.HostRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gslb-hr"},
+		Spec: v1alpha1.HostRuleSpec{
+			VirtualHost: v1alpha1.HostRuleVirtualHost{
+				Fqdn: "foo.com",
+				Gslb: v1alpha1.HostRuleGSLB{
+					Fqdn: "foo.global.com",
+					Service: &v1alpha1.HostRuleGSLBService{
+						Algorithm:      algorithm,
+						Priority:       priority,
+						HealthMonitors: []string{"global-hm"},
+						TTL:            &ttl,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGSLBServiceMembers(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+	defer TearDownIngressForCacheSyncCheck(t, modelName)
+
+	var members []k8s.GSLBMember
+	g.Eventually(func() []k8s.GSLBMember {
+		members = k8s.GSLBMembers("foo.com", "foo.global.com")
+		return members
+	}, 10*time.Second).Should(gomega.HaveLen(1))
+	g.Expect(members[0].VirtualService).To(gomega.Equal("cluster--Shared-L7-0"))
+	g.Expect(members[0].VsUuid).To(gomega.ContainSubstring("cluster--Shared-L7-0"))
+	g.Expect(members[0].IP).To(gomega.Equal("10.250.250.10"))
+	g.Expect(k8s.GSLBMembers("bar.com", "bar.global.com")).To(gomega.BeEmpty())
+}
+
+func TestGSLBServiceSync(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	fake := &fakeGSLBServices{services: make(map[string]*models.GslbService)}
+	integrationtest.AddMiddleware(fake.serve)
+	defer integrationtest.ResetMiddleware()
+	client := cache.SharedAVIClients().AviClient[0]
+	members := []k8s.GSLBMember{{VirtualService: "cluster--Shared-L7-0", VsUuid: "vs-uuid-0", IP: "10.250.250.10"}}
+
+	// The GSLB service is created with the members of this cluster.
+	gsStatus, err := k8s.SyncGSLBService(client, "cluster-uuid", gslbTestHostRule(v1alpha1.GSLBAlgorithmPriority, 20), members)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(gsStatus.ServiceName).To(gomega.Equal("foo.global.com"))
+	g.Expect(gsStatus.Members).To(gomega.HaveLen(1))
+	g.Expect(gsStatus.Members[0].VirtualService).To(gomega.Equal("cluster--Shared-L7-0"))
+	g.Expect(gsStatus.Members[0].IP).To(gomega.Equal("10.250.250.10"))
+	g.Expect(gsStatus.Members[0].Status).To(gomega.Equal("OPER_UNKNOWN"))
+
+	gs := fake.services["foo.global.com"]
+	g.Expect(gs).NotTo(gomega.BeNil())
+	g.Expect(gs.DomainNames).To(gomega.Equal([]string{"foo.global.com"}))
+	g.Expect(*gs.PoolAlgorithm).To(gomega.Equal(lib.GSLBServiceAlgorithmPriority))
+	g.Expect(*gs.TTL).To(gomega.Equal(int32(30)))
+	g.Expect(gs.HealthMonitorRefs).To(gomega.Equal([]string{"/api/healthmonitor?name=global-hm"}))
+	g.Expect(gs.Groups).To(gomega.HaveLen(1))
+	g.Expect(*gs.Groups[0].Name).To(gomega.Equal(lib.GetNamePrefix() + "gslb-pool"))
+	g.Expect(*gs.Groups[0].Priority).To(gomega.Equal(int32(20)))
+	g.Expect(*gs.Groups[0].Members[0].ClusterUUID).To(gomega.Equal("cluster-uuid"))
+	g.Expect(*gs.Groups[0].Members[0].VsUUID).To(gomega.Equal("vs-uuid-0"))
+	g.Expect(*gs.Groups[0].Members[0].Description).To(gomega.Equal(lib.GetAKOUser()))
+
+	// The members of the other clusters are kept when the members of this cluster are updated.
+	otherCluster, otherDescription, otherIP := "other-cluster-uuid", "ako-other", "10.10.10.10"
+	gs.Groups = append(gs.Groups, &models.GslbPool{
+		Name: &otherDescription,
+		Members: []*models.GslbPoolMember{{
+			ClusterUUID: &otherCluster,
+			Description: &otherDescription,
+			IP:          &models.IPAddr{Addr: &otherIP},
+		}},
+	})
+	members[0].IP = "10.250.250.11"
+	gsStatus, err = k8s.SyncGSLBService(client, "cluster-uuid", gslbTestHostRule(v1alpha1.GSLBAlgorithmPriority, 20), members)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(gsStatus.Members).To(gomega.HaveLen(1))
+	g.Expect(gsStatus.Members[0].IP).To(gomega.Equal("10.250.250.11"))
+	gs = fake.services["foo.global.com"]
+	g.Expect(gs.Groups).To(gomega.HaveLen(2))
+	g.Expect(*gs.Groups[0].Members[0].IP.Addr).To(gomega.Equal("10.250.250.11"))
+	g.Expect(*gs.Groups[1].Members[0].ClusterUUID).To(gomega.Equal(otherCluster))
+
+	// The GSLB service is kept for the other clusters, when this cluster has no members.
+	gsStatus, err = k8s.SyncGSLBService(client, "cluster-uuid", gslbTestHostRule(v1alpha1.GSLBAlgorithmRoundRobin, 0), nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(gsStatus.Members).To(gomega.BeEmpty())
+	gs = fake.services["foo.global.com"]
+	g.Expect(gs.Groups).To(gomega.HaveLen(1))
+	g.Expect(*gs.Groups[0].Members[0].ClusterUUID).To(gomega.Equal(otherCluster))
+
+	// The members of this cluster share a pool with the other clusters with the round robin algorithm, and the
+	// GSLB service is deleted once the members of all the clusters are removed.
+	gsStatus, err = k8s.SyncGSLBService(client, "cluster-uuid", gslbTestHostRule(v1alpha1.GSLBAlgorithmRoundRobin, 0), members)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(gsStatus.Members[0].Pool).To(gomega.Equal(lib.GSLBSharedPoolName))
+	gs = fake.services["foo.global.com"]
+	g.Expect(gs.Groups).To(gomega.HaveLen(2))
+	g.Expect(*gs.Groups[1].Algorithm).To(gomega.Equal(lib.GSLBAlgorithmRoundRobin))
+	gs.Groups = gs.Groups[1:]
+	_, err = k8s.SyncGSLBService(client, "cluster-uuid", gslbTestHostRule(v1alpha1.GSLBAlgorithmRoundRobin, 0), nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(fake.services).NotTo(gomega.HaveKey("foo.global.com"))
+}
+
+func TestGSLBServiceSyncDryRun(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	fake := &fakeGSLBServices{services: make(map[string]*models.GslbService)}
+	integrationtest.AddMiddleware(fake.serve)
+	defer integrationtest.ResetMiddleware()
+	os.Setenv(lib.DRY_RUN, "true")
+	defer os.Unsetenv(lib.DRY_RUN)
+	client := cache.SharedAVIClients().AviClient[0]
+	members := []k8s.GSLBMember{{VirtualService: "cluster--Shared-L7-0", VsUuid: "vs-uuid-0", IP: "10.250.250.10"}}
+
+	// The creation of the GSLB service is only recorded, and reported as such instead of a status.
+	gsStatus, err := k8s.SyncGSLBService(client, "cluster-uuid", gslbTestHostRule(v1alpha1.GSLBAlgorithmPriority, 20), members)
+	g.Expect(err).To(gomega.Equal(lib.ErrDryRun))
+	g.Expect(gsStatus).To(gomega.BeNil())
+	g.Expect(fake.services).To(gomega.BeEmpty())
+	var recorded bool
+	for _, record := range apimodels.DryRun.GetRecords() {
+		if record.Method == "POST" && record.Model == "gslbservice" {
+			recorded = true
+		}
+	}
+	g.Expect(recorded).To(gomega.BeTrue())
+}
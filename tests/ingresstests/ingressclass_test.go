@@ -955,6 +955,90 @@ func TestMultiFipStatusWithInfraSetting(t *testing.T) {
 	integrationtest.TeardownIngressClass(t, ingClassName)
 }
 
+func TestDualStackVipStatusWithInfraSetting(t *testing.T) {
+	// vip network with both, the v4 and the v6 cidrs, in the infrasetting
+	// allocates dual-stack vips for the ingress
+
+	g := gomega.NewGomegaWithT(t)
+
+	ingClassName, ingressName, ns, settingName := "avi-lb", "foo-with-class", "default", "my-dualstack-infrasetting"
+	modelName := "admin/cluster--Shared-L7-1"
+	secretName := "my-secret"
+
+	SetUpTestForIngress(t, modelName)
+	integrationtest.RemoveDefaultIngressClass()
+	defer integrationtest.AddDefaultIngressClass()
+
+	integrationtest.SetupIngressClass(t, ingClassName, lib.AviIngressController, settingName)
+	integrationtest.AddSecret(secretName, ns, "tlsCert", "tlsKey")
+	ingressCreate := (integrationtest.FakeIngress{
+		Name:        ingressName,
+		Namespace:   ns,
+		ClassName:   ingClassName,
+		DnsNames:    []string{"bar.com"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	_, err := KubeClient.NetworkingV1().Ingresses(ns).Create(context.TODO(), ingressCreate, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+
+	settingModelName := "admin/cluster--Shared-L7-my-dualstack-infrasetting-1"
+
+	settingCreate := (integrationtest.FakeAviInfraSetting{
+		Name:     settingName,
+		Networks: []string{"dualstack-network"},
+	}).AviInfraSetting()
+	settingCreate.Spec.Network.VipNetworks[0].Cidr = "10.250.250.0/24"
+	settingCreate.Spec.Network.VipNetworks[0].V6Cidr = "2001:db8::/64"
+	settingCreate.ResourceVersion = "2"
+	if _, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().AviInfraSettings().Create(context.TODO(), settingCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding AviInfraSetting: %v", err)
+	}
+
+	g.Eventually(func() string {
+		setting, _ := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().AviInfraSettings().Get(context.TODO(), settingName, metav1.GetOptions{})
+		return setting.Status.Status
+	}, 40*time.Second).Should(gomega.Equal("Accepted"))
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(settingModelName); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 && len(nodes[0].VSVIPRefs[0].VipNetworks) > 0 {
+				return nodes[0].VSVIPRefs[0].VipNetworks[0].V6Cidr
+			}
+		}
+		return ""
+	}, 45*time.Second).Should(gomega.Equal("2001:db8::/64"))
+
+	g.Eventually(func() []string {
+		ingress, _ := KubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), ingressName, metav1.GetOptions{})
+		var ips []string
+		for _, lbIngress := range ingress.Status.LoadBalancer.Ingress {
+			ips = append(ips, lbIngress.IP)
+		}
+		return ips
+	}, 20*time.Second).Should(gomega.Equal([]string{"10.250.250.11", "2001:db8::11"}))
+
+	// a v4 cidr in place of the v6 cidr rejects the infrasetting
+	settingCreate.Spec.Network.VipNetworks[0].V6Cidr = "10.250.251.0/24"
+	settingCreate.ResourceVersion = "3"
+	if _, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().AviInfraSettings().Update(context.TODO(), settingCreate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating AviInfraSetting: %v", err)
+	}
+	g.Eventually(func() string {
+		setting, _ := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().AviInfraSettings().Get(context.TODO(), settingName, metav1.GetOptions{})
+		return setting.Status.Error
+	}, 40*time.Second).Should(gomega.Equal("invalid v6 CIDR configuration 10.250.251.0/24 detected for networkName dualstack-network in vipNetworkList"))
+
+	err = KubeClient.NetworkingV1().Ingresses(ns).Delete(context.TODO(), ingressName, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	integrationtest.DeleteSecret(secretName, ns)
+	integrationtest.TeardownAviInfraSetting(t, settingName)
+	TearDownTestForIngress(t, modelName, settingModelName)
+	integrationtest.TeardownIngressClass(t, ingClassName)
+}
+
 func TestUpdateIngressClassWithoutInfraSetting(t *testing.T) {
 	// update ingressclass (without infrasetting) in ingress
 	g := gomega.NewGomegaWithT(t)
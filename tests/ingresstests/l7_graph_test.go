@@ -57,6 +57,7 @@ func TestMain(m *testing.M) {
 	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
 	os.Setenv("SHARD_VS_SIZE", "LARGE")
 	os.Setenv("AUTO_L4_FQDN", "default")
+	os.Setenv("PROBE_HEALTH_MONITOR", "true")
 
 	akoControlConfig := lib.AKOControlConfig()
 	KubeClient = k8sfake.NewSimpleClientset()
@@ -81,6 +82,7 @@ func TestMain(m *testing.M) {
 		utils.NSInformer,
 		utils.NodeInformer,
 		utils.ConfigMapInformer,
+		utils.PodInformer,
 	}
 	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
 	informers := k8s.K8sinformers{Cs: KubeClient}
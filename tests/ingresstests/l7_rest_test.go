@@ -16,22 +16,36 @@ package ingresstests
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 
 	"github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 )
 
 func SetupDomain() {
@@ -241,6 +255,132 @@ func TestCreateIngressWithFaultCacheSync(t *testing.T) {
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
 
+func TestSyncStatusIngressWithFault(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Record the events raised by AKO.
+	recorder := lib.AKOControlConfig().EventRecorder()
+	fakeRecorder := record.NewFakeRecorder(1000)
+	oldRecorder, oldFake, oldEnabled := recorder.Recorder, recorder.Fake, recorder.Enabled
+	recorder.Recorder, recorder.Fake, recorder.Enabled = fakeRecorder, false, true
+	defer func() {
+		recorder.Recorder, recorder.Fake, recorder.Enabled = oldRecorder, oldFake, oldEnabled
+	}()
+
+	injectFault := true
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "POST" && strings.Contains(url, "/api/virtualservice") && injectFault {
+			injectFault = false
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, `{"error": "virtualservice rejected by the controller"}`)
+			return
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+	defer integrationtest.ResetMiddleware()
+
+	CleanupCache("cluster--Shared-L7-0")
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+
+	g.Expect(objects.SharedModelKeyLister().GetKeys(modelName)).To(gomega.ContainElement("Ingress/default/foo-with-targets"))
+
+	var events []string
+	hasEvent := func(substr string) bool {
+		for {
+			select {
+			case event := <-fakeRecorder.Events:
+				events = append(events, event)
+				continue
+			default:
+			}
+			break
+		}
+		for _, event := range events {
+			if strings.Contains(event, substr) {
+				return true
+			}
+		}
+		return false
+	}
+	g.Eventually(func() bool {
+		return hasEvent("Warning SyncFailed Failed to sync virtualservice cluster--Shared-L7-0")
+	}, 20*time.Second).Should(gomega.BeTrue())
+	g.Expect(hasEvent("virtualservice rejected by the controller")).To(gomega.BeTrue())
+
+	syncCondition := func() string {
+		ingress, _ := KubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "foo-with-targets", metav1.GetOptions{})
+		var condition metav1.Condition
+		json.Unmarshal([]byte(ingress.Annotations[lib.SyncedConditionType]), &condition)
+		return string(condition.Status) + "/" + condition.Reason
+	}
+	g.Eventually(syncCondition, 20*time.Second).Should(gomega.Equal("False/SyncFailed"))
+
+	// The 400 error is not retried, the update of the ingress syncs the virtualservice and clears the condition.
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/bar"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	ingress, _ := KubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "foo-with-targets", metav1.GetOptions{})
+	ingrFake.Annotations = ingress.Annotations
+	ingrFake.ResourceVersion = "2"
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Update(context.TODO(), ingrFake, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Ingress: %v", err)
+	}
+	g.Eventually(func() bool {
+		return hasEvent("Normal Synced Synced virtualservice cluster--Shared-L7-0")
+	}, 40*time.Second).Should(gomega.BeTrue())
+	g.Eventually(syncCondition, 20*time.Second).Should(gomega.Equal("True/Synced"))
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+// The ingress moved to another shard is not associated with the previous model anymore.
+func TestSyncStatusIngressMovedToOtherModel(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	CleanupCache("cluster--Shared-L7-0")
+	modelName := "admin/cluster--Shared-L7-0"
+	newModelName := "admin/cluster--Shared-L7-1"
+	objKey := "Ingress/default/foo-with-targets"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+	g.Eventually(func() []string {
+		return objects.SharedModelKeyLister().GetKeys(modelName)
+	}, 20*time.Second).Should(gomega.ContainElement(objKey))
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"bar.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	ingrFake.ResourceVersion = "2"
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Update(context.TODO(), ingrFake, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Ingress: %v", err)
+	}
+	g.Eventually(func() []string {
+		return objects.SharedModelKeyLister().GetKeys(newModelName)
+	}, 20*time.Second).Should(gomega.ContainElement(objKey))
+	g.Expect(objects.SharedModelKeyLister().GetKeys(modelName)).NotTo(gomega.ContainElement(objKey))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(func() []string {
+		return objects.SharedModelKeyLister().GetKeys(newModelName)
+	}, 20*time.Second).ShouldNot(gomega.ContainElement(objKey))
+	TearDownTestForIngress(t, modelName, newModelName)
+}
+
 func TestUpdatePoolCacheSync(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	var err error
@@ -955,3 +1095,379 @@ func TestMultiHostUpdateIngressStatusCheck(t *testing.T) {
 	}
 	TearDownTestForIngress(t, modelName)
 }
+
+func TestDryRunIngressCacheSync(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	mcache := cache.SharedAviObjCache()
+	CleanupCache("cluster--Shared-L7-0")
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+
+	vsKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"}
+	g.Eventually(func() int {
+		vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			return 0
+		}
+		vsCacheObj, _ := vsCache.(*cache.AviVsCache)
+		return len(vsCacheObj.PoolKeyCollection)
+	}, 20*time.Second).Should(gomega.Equal(1))
+
+	dryRunFile := filepath.Join(t.TempDir(), "ako-dryrun.json")
+	os.Setenv(lib.DRY_RUN, "true")
+	models.DryRun.SetFilePath(dryRunFile)
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+
+	findRecord := func(model, method, name string) *models.DryRunRecord {
+		for _, record := range models.DryRun.GetRecords() {
+			if record.Model == model && record.Method == method && strings.Contains(record.Name, name) {
+				return &record
+			}
+		}
+		return nil
+	}
+	g.Eventually(func() bool {
+		return findRecord("Pool", "DELETE", "foo-with-targets") != nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	poolRecord := findRecord("Pool", "DELETE", "foo-with-targets")
+	g.Expect(poolRecord.Tenant).To(gomega.Equal("admin"))
+	g.Expect(poolRecord.Diff).NotTo(gomega.BeEmpty())
+	g.Expect(poolRecord.Diff[0].Desired).To(gomega.BeNil())
+
+	pgRecord := findRecord("PoolGroup", "PUT", "cluster--Shared-L7-0")
+	g.Expect(pgRecord).NotTo(gomega.BeNil())
+	g.Expect(pgRecord.Object).NotTo(gomega.BeEmpty())
+	g.Expect(pgRecord.Diff).To(gomega.HaveLen(1))
+	g.Expect(pgRecord.Diff[0].Field).To(gomega.Equal("cloud_config_cksum"))
+
+	// The rest operations are not executed, hence the cache must still have the pool.
+	vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(vsCache.(*cache.AviVsCache).PoolKeyCollection).To(gomega.HaveLen(1))
+
+	data, err := ioutil.ReadFile(dryRunFile)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	var records []models.DryRunRecord
+	g.Expect(json.Unmarshal(data, &records)).To(gomega.Succeed())
+	g.Expect(records).NotTo(gomega.BeEmpty())
+
+	os.Unsetenv(lib.DRY_RUN)
+	models.DryRun.SetFilePath("")
+	KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "my-secret", metav1.DeleteOptions{})
+	integrationtest.ClearAllCache(mcache)
+	TearDownTestForIngress(t, modelName)
+}
+
+func TestDryRunSSLKeyCertWithoutKey(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	mcache := cache.SharedAviObjCache()
+	CleanupCache("cluster--Shared-L7-0")
+
+	dryRunFile := filepath.Join(t.TempDir(), "ako-dryrun.json")
+	os.Setenv(lib.DRY_RUN, "true")
+	models.DryRun.SetFilePath(dryRunFile)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	var keyCertRecord *models.DryRunRecord
+	g.Eventually(func() bool {
+		for _, record := range models.DryRun.GetRecords() {
+			// the certificate may be left in the cache by the earlier tests, and be updated instead.
+			if record.Model == "SSLKeyAndCertificate" && (record.Method == "POST" || record.Method == "PUT") {
+				keyCertRecord = &record
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second).Should(gomega.BeTrue())
+	var object map[string]interface{}
+	g.Expect(json.Unmarshal(keyCertRecord.Object, &object)).To(gomega.Succeed())
+	g.Expect(object).NotTo(gomega.HaveKey("key"))
+	g.Expect(object).To(gomega.HaveKey("certificate"))
+	for _, diff := range keyCertRecord.Diff {
+		g.Expect(diff.Field).NotTo(gomega.Equal("key"))
+	}
+
+	data, err := ioutil.ReadFile(dryRunFile)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(data)).To(gomega.ContainSubstring("tlsCert"))
+	g.Expect(string(data)).NotTo(gomega.ContainSubstring("tlsKey"))
+
+	os.Unsetenv(lib.DRY_RUN)
+	models.DryRun.SetFilePath("")
+	TearDownIngressForCacheSyncCheck(t, modelName)
+	integrationtest.ClearAllCache(mcache)
+}
+
+func TestMetricsIngressCacheSync(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	mcache := cache.SharedAviObjCache()
+	CleanupCache("cluster--Shared-L7-0")
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+
+	vsKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"}
+	g.Eventually(func() int {
+		vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			return 0
+		}
+		vsCacheObj, _ := vsCache.(*cache.AviVsCache)
+		return len(vsCacheObj.PoolKeyCollection)
+	}, 20*time.Second).Should(gomega.Equal(1))
+
+	scrapeMetrics := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		rec := httptest.NewRecorder()
+		models.Metrics.ApiOperationMap()[0].Handler(rec, req)
+		g.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+		return rec.Body.String()
+	}
+	metrics := scrapeMetrics()
+	g.Expect(metrics).To(gomega.ContainSubstring(`ako_avi_rest_requests_total{method="POST",model="Pool",status_code="2xx"}`))
+	g.Expect(metrics).To(gomega.ContainSubstring(`ako_avi_rest_request_duration_seconds_count{method="POST",model="Pool"}`))
+	g.Expect(metrics).To(gomega.ContainSubstring(`ako_workqueue_adds_total{name="avi-GraphLayer"}`))
+	g.Expect(metrics).To(gomega.ContainSubstring(`ako_workqueue_depth{name="avi-ObjectIngestionLayer"}`))
+	g.Expect(metrics).To(gomega.MatchRegexp(`ako_avi_cache_objects\{type="Pool"\} [1-9]`))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "my-secret", metav1.DeleteOptions{})
+	g.Eventually(func() string {
+		return scrapeMetrics()
+	}, 10*time.Second).Should(gomega.ContainSubstring(`ako_avi_rest_requests_total{method="DELETE",model="Pool",status_code="2xx"}`))
+	TearDownTestForIngress(t, modelName)
+}
+
+// TestWorkqueueMetricsAcrossWorkers checks that an idle workqueue of a layer does not reset the gauges of a busy
+// workqueue of the same layer.
+func TestWorkqueueMetricsAcrossWorkers(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	queueName := "avi-TestMetricsLayer"
+	busyQueue := workqueue.NewNamed(queueName)
+	idleQueue := workqueue.NewNamed(queueName)
+	defer busyQueue.ShutDown()
+	defer idleQueue.ShutDown()
+
+	busyQueue.Add("key")
+	item, _ := busyQueue.Get()
+	defer busyQueue.Done(item)
+
+	gaugeValue := func(metricName string) float64 {
+		families, err := utils.MetricsRegistry.Gather()
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		for _, family := range families {
+			if family.GetName() != metricName {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "name" && label.GetValue() == queueName {
+						return metric.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+		return 0
+	}
+	g.Eventually(func() float64 {
+		return gaugeValue("ako_workqueue_longest_running_processor_seconds")
+	}, 5*time.Second).Should(gomega.BeNumerically(">", 1))
+	// the workqueues update the gauges every 500ms
+	for i := 0; i < 6; i++ {
+		g.Expect(gaugeValue("ako_workqueue_longest_running_processor_seconds")).To(gomega.BeNumerically(">", 1))
+		g.Expect(gaugeValue("ako_workqueue_unfinished_work_seconds")).To(gomega.BeNumerically(">", 1))
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+func TestIntrospectionModelWithoutTLSKey(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	var sniNode *avinodes.AviVsNode
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 0 || len(nodes[0].SniNodes) == 0 {
+			return 0
+		}
+		sniNode = nodes[0].SniNodes[0]
+		return len(sniNode.SSLKeyCertRefs)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	g.Expect(string(sniNode.SSLKeyCertRefs[0].Key)).To(gomega.Equal("tlsKey"))
+
+	apiServer := &api.ApiServer{Models: []models.ApiModel{&k8s.IntrospectionModel{}}}
+	rec := httptest.NewRecorder()
+	apiServer.SetRouter().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/models/"+modelName, nil))
+	g.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+	body := rec.Body.String()
+	g.Expect(body).To(gomega.ContainSubstring(base64.StdEncoding.EncodeToString([]byte("tlsCert"))))
+	g.Expect(body).NotTo(gomega.ContainSubstring(base64.StdEncoding.EncodeToString([]byte("tlsKey"))))
+
+	// the key is removed only from the response, and not from the model
+	g.Expect(string(sniNode.SSLKeyCertRefs[0].Key)).To(gomega.Equal("tlsKey"))
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestIntrospectionIngressCacheSync(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	mcache := cache.SharedAviObjCache()
+	CleanupCache("cluster--Shared-L7-0")
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+
+	vsKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"}
+	g.Eventually(func() int {
+		vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			return 0
+		}
+		vsCacheObj, _ := vsCache.(*cache.AviVsCache)
+		return len(vsCacheObj.PoolKeyCollection)
+	}, 20*time.Second).Should(gomega.Equal(1))
+
+	apiServer := &api.ApiServer{Models: []models.ApiModel{&k8s.IntrospectionModel{}}}
+	router := apiServer.SetRouter()
+	get := func(uri string, response interface{}) int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, uri, nil))
+		if response != nil {
+			g.Expect(json.Unmarshal(rec.Body.Bytes(), response)).To(gomega.Succeed())
+		}
+		return rec.Code
+	}
+
+	var summaries []k8s.ModelSummary
+	g.Expect(get("/api/models", &summaries)).To(gomega.Equal(http.StatusOK))
+	var modelNames []string
+	for _, summary := range summaries {
+		modelNames = append(modelNames, summary.Name)
+	}
+	g.Expect(modelNames).To(gomega.ContainElement(modelName))
+
+	var model map[string]interface{}
+	g.Expect(get("/api/models/"+modelName, &model)).To(gomega.Equal(http.StatusOK))
+	g.Expect(model["name"]).To(gomega.Equal(modelName))
+	g.Expect(model["nodes"]).NotTo(gomega.BeEmpty())
+
+	var modelObjects k8s.ModelObjects
+	g.Expect(get("/api/models/"+modelName+"/objects", &modelObjects)).To(gomega.Equal(http.StatusOK))
+	g.Expect(modelObjects.VirtualServices).To(gomega.ContainElement("cluster--Shared-L7-0"))
+	g.Expect(modelObjects.Hosts).To(gomega.HaveKey("foo.com"))
+	g.Expect(modelObjects.Ingresses).To(gomega.ContainElement("default/foo-with-targets"))
+	g.Expect(modelObjects.Services).To(gomega.ContainElement("default/avisvc"))
+
+	var vsCacheDump map[string]interface{}
+	g.Expect(get("/api/cache/virtualservices/"+modelName, &vsCacheDump)).To(gomega.Equal(http.StatusOK))
+	g.Expect(vsCacheDump["pools"]).To(gomega.HaveLen(1))
+	g.Expect(vsCacheDump["poolgroups"]).To(gomega.HaveLen(1))
+	g.Expect(vsCacheDump["vsdatascriptsets"]).To(gomega.HaveLen(1))
+
+	g.Expect(get("/api/models/admin/cluster--Shared-L7-100", nil)).To(gomega.Equal(http.StatusNotFound))
+	g.Expect(get("/api/cache/virtualservices/admin/cluster--Shared-L7-100", nil)).To(gomega.Equal(http.StatusNotFound))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "my-secret", metav1.DeleteOptions{})
+	g.Eventually(func() int {
+		vsCache, _ := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		vsCacheObj, _ := vsCache.(*cache.AviVsCache)
+		return len(vsCacheObj.PoolKeyCollection)
+	}, 10*time.Second).Should(gomega.Equal(0))
+	TearDownTestForIngress(t, modelName)
+}
+
+func TestRateLimitedIngressCacheSync(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	mcache := cache.SharedAviObjCache()
+
+	// The controller rejects the first two virtualservice POSTs with 429, which are retried by the limiter.
+	var rejected int32
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "POST" && strings.Contains(url, "/api/virtualservice") && atomic.LoadInt32(&rejected) < 2 {
+			atomic.AddInt32(&rejected, 1)
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprintln(w, `{"error": "Too many requests"}`)
+			return
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+	defer integrationtest.ResetMiddleware()
+
+	CleanupCache("cluster--Shared-L7-0")
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+
+	vsKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 20*time.Second).Should(gomega.BeTrue())
+	g.Expect(atomic.LoadInt32(&rejected)).To(gomega.Equal(int32(2)))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+	rec := httptest.NewRecorder()
+	models.RestStatus.ApiOperationMap()[0].Handler(rec, req)
+	g.Expect(rec.Code).To(gomega.Equal(http.StatusOK))
+	var status models.StatusModel
+	g.Expect(json.Unmarshal(rec.Body.Bytes(), &status)).To(gomega.Succeed())
+	g.Expect(status.AviRestLimiter.CircuitState).To(gomega.Equal(utils.CircuitClosed))
+	g.Expect(status.AviRestLimiter.ConsecutiveFailures).To(gomega.Equal(0))
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestRestLimiterOverloadSignals(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// The controller fails the first request to each uri with the status code in the uri.
+	var lock sync.Mutex
+	requests := make(map[string]int)
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := "/" + strings.TrimLeft(r.URL.EscapedPath(), "/")
+		if !strings.HasPrefix(url, "/api/overload-test/") {
+			integrationtest.NormalControllerServer(w, r)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		requests[url]++
+		if code, _ := strconv.Atoi(url[strings.LastIndex(url, "/")+1:]); code != 0 && requests[url] == 1 {
+			w.WriteHeader(code)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{}`)
+	})
+	defer integrationtest.ResetMiddleware()
+	client := cache.SharedAVIClients().AviClient[0]
+	post := func(uri string) error {
+		return utils.SharedAviRestLimiter().Do(func() error {
+			var response interface{}
+			return client.AviSession.Post(uri, map[string]string{}, &response)
+		})
+	}
+
+	// A 503 is an overload signal, and the request is retried.
+	g.Expect(post("/api/overload-test/503")).To(gomega.Succeed())
+	g.Expect(requests["/api/overload-test/503"]).To(gomega.Equal(2))
+
+	// Other server errors are returned, and don't open the circuit.
+	for i := 0; i < 6; i++ {
+		g.Expect(post("/api/overload-test/500-" + strconv.Itoa(i) + "/500")).NotTo(gomega.Succeed())
+	}
+	g.Expect(utils.SharedAviRestLimiter().Status().CircuitState).To(gomega.Equal(utils.CircuitClosed))
+	g.Expect(utils.SharedAviRestLimiter().Status().ConsecutiveFailures).To(gomega.Equal(0))
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func updateDrainEndpoints(t *testing.T, podIPs map[string]string) {
+	var addresses []corev1.EndpointAddress
+	for podName, ip := range podIPs {
+		addresses = append(addresses, corev1.EndpointAddress{
+			IP:        ip,
+			TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: podName},
+		})
+	}
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "avisvc"},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: addresses,
+			Ports:     []corev1.EndpointPort{{Name: "foo0", Port: 8080, Protocol: "TCP"}},
+		}},
+	}
+	epExample.ResourceVersion = time.Now().String()
+	if _, err := KubeClient.CoreV1().Endpoints("default").Update(context.TODO(), epExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Endpoint: %v", err)
+	}
+}
+
+func getPoolServers(modelName string) map[string]bool {
+	servers := make(map[string]bool)
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if aviModel == nil {
+		return servers
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	if len(nodes) == 0 || len(nodes[0].PoolRefs) == 0 {
+		return servers
+	}
+	for _, server := range nodes[0].PoolRefs[0].Servers {
+		servers[*server.Ip.Addr] = server.Draining
+	}
+	return servers
+}
+
+func TestPoolDrainTerminatingPod(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv(lib.POOL_DRAIN_TIMEOUT, "5")
+	defer os.Unsetenv(lib.POOL_DRAIN_TIMEOUT)
+	g.Expect(lib.GetPoolGracefulDisableTimeout()).To(gomega.Equal(int32(1)))
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+	for _, podName := range []string{"avisvc-drain-0", "avisvc-drain-1"} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: podName, Labels: map[string]string{"app": "avisvc"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: "avi/web"}}},
+		}
+		if _, err := KubeClient.CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error in adding Pod: %v", err)
+		}
+	}
+	updateDrainEndpoints(t, map[string]string{"avisvc-drain-0": "10.1.1.1", "avisvc-drain-1": "10.1.1.2"})
+	g.Eventually(func() map[string]bool {
+		return getPoolServers(modelName)
+	}, 20*time.Second).Should(gomega.Equal(map[string]bool{"10.1.1.1": false, "10.1.1.2": false}))
+
+	// terminate the Pod, its server is disabled after it is removed from the Endpoints
+	pod, _ := KubeClient.CoreV1().Pods("default").Get(context.TODO(), "avisvc-drain-1", metav1.GetOptions{})
+	gracePeriod := int64(30)
+	deletionTimestamp := metav1.NewTime(time.Now().Add(time.Duration(gracePeriod) * time.Second))
+	pod.DeletionTimestamp = &deletionTimestamp
+	pod.DeletionGracePeriodSeconds = &gracePeriod
+	pod.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Pods("default").Update(context.TODO(), pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Pod: %v", err)
+	}
+	g.Eventually(func() bool {
+		pod, err := utils.GetInformers().PodInformer.Lister().Pods("default").Get("avisvc-drain-1")
+		return err == nil && pod.DeletionTimestamp != nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	updateDrainEndpoints(t, map[string]string{"avisvc-drain-0": "10.1.1.1"})
+	g.Eventually(func() map[string]bool {
+		return getPoolServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal(map[string]bool{"10.1.1.1": false, "10.1.1.2": true}))
+
+	// the server is removed once the drain timeout passes
+	g.Eventually(func() map[string]bool {
+		return getPoolServers(modelName)
+	}, 20*time.Second).Should(gomega.Equal(map[string]bool{"10.1.1.1": false}))
+	poolName := "cluster--foo.com_foo-default-foo-with-targets"
+	g.Expect(avinodes.GetPoolDrainServerCount("admin", poolName)).To(gomega.Equal(1))
+
+	for _, podName := range []string{"avisvc-drain-0", "avisvc-drain-1"} {
+		KubeClient.CoreV1().Pods("default").Delete(context.TODO(), podName, metav1.DeleteOptions{})
+	}
+	// the servers of the deleted pool are forgotten
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(func() int {
+		return avinodes.GetPoolDrainServerCount("admin", poolName)
+	}, 20*time.Second).Should(gomega.Equal(0))
+	TearDownTestForIngress(t, modelName)
+}
@@ -0,0 +1,261 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func createProbePod(t *testing.T, name string, probe *corev1.Probe) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{"app": "avisvc"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "web",
+				Image: "avi/web",
+				Ports: []corev1.ContainerPort{
+					{Name: "http", ContainerPort: 8080},
+					{Name: "health", ContainerPort: 8081},
+				},
+				ReadinessProbe: probe,
+			}},
+		},
+	}
+	if _, err := KubeClient.CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Pod: %v", err)
+	}
+}
+
+func updateProbeService(t *testing.T, annotations map[string]string) {
+	svc, err := KubeClient.CoreV1().Services("default").Get(context.TODO(), "avisvc", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error in getting Service: %v", err)
+	}
+	svc.Spec.Selector = map[string]string{"app": "avisvc"}
+	svc.Annotations = annotations
+	rv, _ := strconv.Atoi(svc.ResourceVersion)
+	svc.ResourceVersion = strconv.Itoa(rv + 1)
+	if _, err := KubeClient.CoreV1().Services("default").Update(context.TODO(), svc, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+}
+
+func TestProbeHealthMonitorFromServiceAnnotation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	poolName := "cluster--foo.com_foo-default-foo-with-targets"
+	hmKey := cache.NamespaceName{Namespace: "admin", Name: lib.GetPoolHealthMonitorName(poolName)}
+	poolKey := cache.NamespaceName{Namespace: "admin", Name: poolName}
+	mcache := cache.SharedAviObjCache()
+
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+	createProbePod(t, "avisvc-pod-0", &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:        "/healthz",
+				Port:        intstr.FromString("health"),
+				HTTPHeaders: []corev1.HTTPHeader{{Name: "Host", Value: "foo.com"}},
+			},
+		},
+		PeriodSeconds:    5,
+		TimeoutSeconds:   2,
+		FailureThreshold: 4,
+	})
+	updateProbeService(t, map[string]string{lib.ProbeHealthMonitorAnnotation: "true"})
+
+	g.Eventually(func() bool {
+		_, found := mcache.HealthMonitorCache.AviCacheGet(hmKey)
+		return found
+	}, 20*time.Second).Should(gomega.BeTrue())
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs).To(gomega.HaveLen(1))
+	hmNode := nodes[0].PoolRefs[0].ProbeHealthMonitor
+	g.Expect(hmNode).NotTo(gomega.BeNil())
+	g.Expect(hmNode.Name).To(gomega.Equal(hmKey.Name))
+	g.Expect(hmNode.Type).To(gomega.Equal(lib.HEALTH_MONITOR_HTTP))
+	g.Expect(hmNode.HTTPRequest).To(gomega.Equal("GET /healthz HTTP/1.0\r\nHost: foo.com"))
+	g.Expect(hmNode.MonitorPort).To(gomega.Equal(int32(8081)))
+	g.Expect(hmNode.SendInterval).To(gomega.Equal(int32(5)))
+	g.Expect(hmNode.ReceiveTimeout).To(gomega.Equal(int32(2)))
+	g.Expect(hmNode.SuccessfulChecks).To(gomega.Equal(int32(1)))
+	g.Expect(hmNode.FailedChecks).To(gomega.Equal(int32(4)))
+
+	g.Eventually(func() cache.NamespaceName {
+		poolCache, found := mcache.PoolCache.AviCacheGet(poolKey)
+		if !found {
+			return cache.NamespaceName{}
+		}
+		return poolCache.(*cache.AviPoolCache).HealthMonitorCollection
+	}, 20*time.Second).Should(gomega.Equal(hmKey))
+
+	// removing the annotation removes the health monitor from the pool, and deletes it
+	updateProbeService(t, nil)
+	g.Eventually(func() bool {
+		_, found := mcache.HealthMonitorCache.AviCacheGet(hmKey)
+		return found
+	}, 20*time.Second).Should(gomega.BeFalse())
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs[0].ProbeHealthMonitor).To(gomega.BeNil())
+
+	KubeClient.CoreV1().Pods("default").Delete(context.TODO(), "avisvc-pod-0", metav1.DeleteOptions{})
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestProbeHealthMonitorFromHTTPRule(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-probe"
+	poolName := "cluster--default-foo.com_foo-foo-with-targets"
+	hmKey := cache.NamespaceName{Namespace: "admin", Name: lib.GetPoolHealthMonitorName(poolName)}
+	poolKey := cache.NamespaceName{Namespace: "admin", Name: poolName}
+	mcache := cache.SharedAviObjCache()
+
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+	createProbePod(t, "avisvc-pod-1", &corev1.Probe{
+		Handler: corev1.Handler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)},
+		},
+	})
+	updateProbeService(t, nil)
+
+	httpRule := &v1alpha1.HTTPRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      rrname,
+		},
+		Spec: v1alpha1.HTTPRuleSpec{
+			Fqdn: "foo.com",
+			Paths: []v1alpha1.HTTPRulePaths{{
+				Target:             "/foo",
+				ProbeHealthMonitor: true,
+			}},
+		},
+	}
+	if _, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().HTTPRules("default").Create(context.TODO(), httpRule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+	integrationtest.VerifyMetadataHTTPRule(t, g, poolKey, "default/"+rrname+"/foo", true)
+
+	g.Eventually(func() bool {
+		_, found := mcache.HealthMonitorCache.AviCacheGet(hmKey)
+		return found
+	}, 20*time.Second).Should(gomega.BeTrue())
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	hmNode := nodes[0].SniNodes[0].PoolRefs[0].ProbeHealthMonitor
+	g.Expect(hmNode).NotTo(gomega.BeNil())
+	g.Expect(hmNode.Type).To(gomega.Equal(lib.HEALTH_MONITOR_TCP))
+	g.Expect(hmNode.MonitorPort).To(gomega.Equal(int32(0)))
+	g.Expect(hmNode.SendInterval).To(gomega.Equal(int32(10)))
+	g.Expect(hmNode.ReceiveTimeout).To(gomega.Equal(int32(1)))
+	g.Expect(hmNode.FailedChecks).To(gomega.Equal(int32(3)))
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	g.Eventually(func() bool {
+		_, found := mcache.HealthMonitorCache.AviCacheGet(hmKey)
+		return found
+	}, 20*time.Second).Should(gomega.BeFalse())
+
+	KubeClient.CoreV1().Pods("default").Delete(context.TODO(), "avisvc-pod-1", metav1.DeleteOptions{})
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestProbeHealthMonitorOnPodProbeChange(t *testing.T) {
+	// the health monitor follows the probes of the Pods, which do not change the Endpoints of the Service
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	poolName := "cluster--foo.com_foo-default-foo-with-targets"
+	mcache := cache.SharedAviObjCache()
+	hmKey := cache.NamespaceName{Namespace: "admin", Name: lib.GetPoolHealthMonitorName(poolName)}
+
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+	updateProbeService(t, map[string]string{lib.ProbeHealthMonitorAnnotation: "true"})
+	getProbeHM := func() *avinodes.AviHealthMonitorNode {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if aviModel == nil {
+			return nil
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 0 || len(nodes[0].PoolRefs) == 0 {
+			return nil
+		}
+		return nodes[0].PoolRefs[0].ProbeHealthMonitor
+	}
+	g.Consistently(getProbeHM, 3*time.Second).Should(gomega.BeNil())
+
+	createProbePod(t, "avisvc-pod-2", &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromString("http")},
+		},
+	})
+	g.Eventually(func() string {
+		if hmNode := getProbeHM(); hmNode != nil {
+			return hmNode.HTTPRequest
+		}
+		return ""
+	}, 20*time.Second).Should(gomega.Equal("GET /ready HTTP/1.0"))
+	g.Eventually(func() bool {
+		_, found := mcache.HealthMonitorCache.AviCacheGet(hmKey)
+		return found
+	}, 20*time.Second).Should(gomega.BeTrue())
+
+	// the Pod is recreated with a different probe
+	KubeClient.CoreV1().Pods("default").Delete(context.TODO(), "avisvc-pod-2", metav1.DeleteOptions{})
+	g.Eventually(getProbeHM, 20*time.Second).Should(gomega.BeNil())
+	createProbePod(t, "avisvc-pod-2", &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("http")},
+		},
+	})
+	g.Eventually(func() string {
+		if hmNode := getProbeHM(); hmNode != nil {
+			return hmNode.HTTPRequest
+		}
+		return ""
+	}, 20*time.Second).Should(gomega.Equal("GET /healthz HTTP/1.0"))
+
+	updateProbeService(t, nil)
+	g.Eventually(func() bool {
+		_, found := mcache.HealthMonitorCache.AviCacheGet(hmKey)
+		return found
+	}, 20*time.Second).Should(gomega.BeFalse())
+	KubeClient.CoreV1().Pods("default").Delete(context.TODO(), "avisvc-pod-2", metav1.DeleteOptions{})
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNamespaceTenant(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv(lib.TENANTS_PER_NAMESPACE, "true")
+	defer os.Unsetenv(lib.TENANTS_PER_NAMESPACE)
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "red"}}
+	g.Expect(lib.GetNamespaceTenant(ns)).To(gomega.Equal("admin"))
+	ns.Labels = map[string]string{lib.TenantAnnotation: "red-label-tenant"}
+	g.Expect(lib.GetNamespaceTenant(ns)).To(gomega.Equal("red-label-tenant"))
+	ns.Annotations = map[string]string{lib.TenantAnnotation: "red-tenant"}
+	g.Expect(lib.GetNamespaceTenant(ns)).To(gomega.Equal("red-tenant"))
+	g.Expect(lib.GetCacheTenant()).To(gomega.Equal(lib.AllTenants))
+
+	if _, err := KubeClient.CoreV1().Namespaces().Create(context.TODO(), ns, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Namespace: %v", err)
+	}
+	g.Eventually(func() string {
+		return lib.GetTenantInNamespace("red")
+	}, 10*time.Second).Should(gomega.Equal("red-tenant"))
+	g.Expect(lib.GetTenantInNamespace("default")).To(gomega.Equal("admin"))
+
+	modelName := "red-tenant/cluster--Shared-L7-0"
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.CreateSVC(t, "red", "avisvc", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "red", "avisvc", false, false, "1.1.1")
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "red",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1().Ingresses("red").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) == 1 {
+				return len(nodes[0].PoolRefs)
+			}
+		}
+		return 0
+	}, 10*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].Tenant).To(gomega.Equal("red-tenant"))
+	g.Expect(nodes[0].PoolRefs[0].Tenant).To(gomega.Equal("red-tenant"))
+	g.Expect(nodes[0].PoolGroupRefs[0].Tenant).To(gomega.Equal("red-tenant"))
+	g.Expect(nodes[0].VSVIPRefs[0].Tenant).To(gomega.Equal("red-tenant"))
+
+	if err := KubeClient.NetworkingV1().Ingresses("red").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	VerifyIngressDeletion(t, g, aviModel, 0)
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.DelSVC(t, "red", "avisvc")
+	integrationtest.DelEP(t, "red", "avisvc")
+	KubeClient.CoreV1().Namespaces().Delete(context.TODO(), "red", metav1.DeleteOptions{})
+	g.Eventually(func() error {
+		_, err := utils.GetInformers().NSInformer.Lister().Get("red")
+		return err
+	}, 10*time.Second).ShouldNot(gomega.BeNil())
+}
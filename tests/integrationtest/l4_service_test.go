@@ -31,6 +31,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
@@ -692,6 +693,160 @@ func TestAviSvcCreationWithStaticIP(t *testing.T) {
 	TearDownTestForSvcLB(t, g)
 }
 
+func TestAviSvcCreationDualStack(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	svcName := "testsvc-dualstack"
+	modelName := "admin/cluster--red-ns-" + svcName
+	objects.SharedAviGraphLister().Delete(modelName)
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	svcExample := (FakeService{
+		Name:         svcName,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	svcExample.Spec.IPFamilyPolicy = &requireDualStack
+	_, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+	PollForCompletion(t, modelName, 5)
+
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].VSVIPRefs) > 0 {
+				return nodes[0].VSVIPRefs[0].IPType
+			}
+		}
+		return ""
+	}, 20*time.Second).Should(gomega.Equal(lib.VipIPTypeDualStack))
+
+	// the status of the Service carries both, the IPv4 and the IPv6 addresses of the vip.
+	g.Eventually(func() []string {
+		svc, _ := KubeClient.CoreV1().Services(NAMESPACE).Get(context.TODO(), svcName, metav1.GetOptions{})
+		var ips []string
+		for _, lbIngress := range svc.Status.LoadBalancer.Ingress {
+			ips = append(ips, lbIngress.IP)
+		}
+		return ips
+	}, 20*time.Second).Should(gomega.Equal([]string{"10.250.250.1", "2001:db8::1"}))
+
+	// switching the Service to single stack IPv6 releases the IPv4 address of the vip.
+	svcExample.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	singleStack := corev1.IPFamilyPolicySingleStack
+	svcExample.Spec.IPFamilyPolicy = &singleStack
+	svcExample.ResourceVersion = "2"
+	if _, err = KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() []corev1.LoadBalancerIngress {
+		svc, _ := KubeClient.CoreV1().Services(NAMESPACE).Get(context.TODO(), svcName, metav1.GetOptions{})
+		return svc.Status.LoadBalancer.Ingress
+	}, 20*time.Second).Should(gomega.Equal([]corev1.LoadBalancerIngress{{IP: "2001:db8::1"}}))
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	DelSVC(t, NAMESPACE, svcName)
+	DelEP(t, NAMESPACE, svcName)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: "cluster--red-ns-" + svcName}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+}
+
+func TestAviSvcCreationDualStackWithoutV6Cidr(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the vip is allocated from the v4 range, when the vip network has no v6 CIDR.
+	vipNetworks := lib.GetVipNetworkList()
+	lib.SetVipNetworkList([]akov1alpha1.AviInfraSettingVipNetwork{{NetworkName: "net123", Cidr: "10.250.250.0/24"}})
+	defer lib.SetVipNetworkList(vipNetworks)
+
+	svcName := "testsvc-dualstack-v4"
+	modelName := "admin/cluster--red-ns-" + svcName
+	objects.SharedAviGraphLister().Delete(modelName)
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	svcExample := (FakeService{
+		Name:         svcName,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	svcExample.Spec.IPFamilyPolicy = &requireDualStack
+	_, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+	PollForCompletion(t, modelName, 5)
+
+	g.Eventually(func() []corev1.LoadBalancerIngress {
+		svc, _ := KubeClient.CoreV1().Services(NAMESPACE).Get(context.TODO(), svcName, metav1.GetOptions{})
+		return svc.Status.LoadBalancer.Ingress
+	}, 20*time.Second).Should(gomega.Equal([]corev1.LoadBalancerIngress{{IP: "10.250.250.1"}}))
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	DelSVC(t, NAMESPACE, svcName)
+	DelEP(t, NAMESPACE, svcName)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: "cluster--red-ns-" + svcName}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+}
+
+func TestAviSvcCreationIPv6WithStaticIP(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	staticIP := "2001:db8::80"
+	svcName := "testsvc-ipv6"
+	modelName := "admin/cluster--red-ns-" + svcName
+	objects.SharedAviGraphLister().Delete(modelName)
+	svcExample := (FakeService{
+		Name:           svcName,
+		Namespace:      NAMESPACE,
+		Type:           corev1.ServiceTypeLoadBalancer,
+		LoadBalancerIP: staticIP,
+		ServicePorts:   []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	_, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+	PollForCompletion(t, modelName, 5)
+
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].VSVIPRefs) > 0 {
+				return nodes[0].VSVIPRefs[0].IPType
+			}
+		}
+		return ""
+	}, 20*time.Second).Should(gomega.Equal(lib.VipIPTypeV6Only))
+
+	g.Eventually(func() []corev1.LoadBalancerIngress {
+		svc, _ := KubeClient.CoreV1().Services(NAMESPACE).Get(context.TODO(), svcName, metav1.GetOptions{})
+		return svc.Status.LoadBalancer.Ingress
+	}, 20*time.Second).Should(gomega.Equal([]corev1.LoadBalancerIngress{{IP: staticIP}}))
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	DelSVC(t, NAMESPACE, svcName)
+	DelEP(t, NAMESPACE, svcName)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: "cluster--red-ns-" + svcName}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+}
+
 // Infra CRD tests via service annotation
 
 func TestWithInfraSettingStatusUpdates(t *testing.T) {
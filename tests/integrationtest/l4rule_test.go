@@ -0,0 +1,229 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func fakeL4Rule(name string) *akov1alpha1.L4Rule {
+	return &akov1alpha1.L4Rule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: NAMESPACE,
+			Name:      name,
+		},
+		Spec: akov1alpha1.L4RuleSpec{
+			NetworkProfile:     "thisisaviref-networkprofile",
+			ApplicationProfile: "thisisaviref-l4appprofile",
+			AnalyticsProfile:   "thisisaviref-analyticsprofile",
+			LoadBalancerIP:     "10.10.10.10",
+			BackendProperties: akov1alpha1.L4RuleBackendProperties{
+				LoadBalancerPolicy: akov1alpha1.L4RuleLBPolicy{
+					Algorithm: "LB_ALGORITHM_CONSISTENT_HASH",
+					Hash:      "LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS",
+				},
+				ApplicationPersistence: "thisisaviref-persistence",
+				HealthMonitors:         []string{"thisisaviref-hm1"},
+			},
+		},
+	}
+}
+
+func setUpTestForL4Rule(t *testing.T, ruleName string) {
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := (FakeService{
+		Name:         SINGLEPORTSVC,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Annotations = map[string]string{lib.L4RuleAnnotation: ruleName}
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	PollForCompletion(t, SINGLEPORTMODEL, 5)
+}
+
+func TestL4RuleStatusUpdates(t *testing.T) {
+	// create svcLB referring to an L4Rule, create the L4Rule with valid refs
+	// check for Accepted status, check layer 2 model for the L4Rule configs
+	// update the L4Rule with a bad health monitor ref, check for Rejected status
+	// check layer 2 model for defaults
+
+	g := gomega.NewGomegaWithT(t)
+	ruleName := "l4-rule"
+	setUpTestForL4Rule(t, ruleName)
+
+	ruleCreate := fakeL4Rule(ruleName)
+	if _, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Create(context.TODO(), ruleCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding L4Rule: %v", err)
+	}
+
+	g.Eventually(func() string {
+		rule, _ := CRDClient.AkoV1alpha1().L4Rules(NAMESPACE).Get(context.TODO(), ruleName, metav1.GetOptions{})
+		return rule.Status.Status
+	}, 15*time.Second).Should(gomega.Equal("Accepted"))
+
+	g.Eventually(func() bool {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				return nodes[0].NetworkProfile == "thisisaviref-networkprofile" &&
+					nodes[0].AppProfileRef == "/api/applicationprofile?name=thisisaviref-l4appprofile" &&
+					nodes[0].AnalyticsProfileRef == "/api/analyticsprofile?name=thisisaviref-analyticsprofile" &&
+					nodes[0].VSVIPRefs[0].IPAddress == "10.10.10.10"
+			}
+		}
+		return false
+	}, 40*time.Second).Should(gomega.Equal(true))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].PoolRefs[0].LbAlgorithm).To(gomega.Equal("LB_ALGORITHM_CONSISTENT_HASH"))
+	g.Expect(nodes[0].PoolRefs[0].LbAlgorithmHash).To(gomega.Equal("LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS"))
+	g.Expect(nodes[0].PoolRefs[0].ApplicationPersistence).To(gomega.Equal("/api/applicationpersistenceprofile?name=thisisaviref-persistence"))
+	g.Expect(nodes[0].PoolRefs[0].HealthMonitors).To(gomega.ContainElement("/api/healthmonitor?name=thisisaviref-hm1"))
+
+	ruleUpdate := fakeL4Rule(ruleName)
+	ruleUpdate.Spec.BackendProperties.HealthMonitors = []string{"thisisBADaviref-hm1"}
+	ruleUpdate.ResourceVersion = "2"
+	if _, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Update(context.TODO(), ruleUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating L4Rule: %v", err)
+	}
+
+	g.Eventually(func() string {
+		rule, _ := CRDClient.AkoV1alpha1().L4Rules(NAMESPACE).Get(context.TODO(), ruleName, metav1.GetOptions{})
+		return rule.Status.Status
+	}, 15*time.Second).Should(gomega.Equal("Rejected"))
+
+	g.Eventually(func() bool {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				return nodes[0].AppProfileRef == "" &&
+					nodes[0].AnalyticsProfileRef == "" &&
+					nodes[0].VSVIPRefs[0].IPAddress == "" &&
+					len(nodes[0].PoolRefs) == 1 &&
+					nodes[0].PoolRefs[0].ApplicationPersistence == ""
+			}
+		}
+		return false
+	}, 40*time.Second).Should(gomega.Equal(true))
+
+	if err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Delete(context.TODO(), ruleName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting L4Rule: %v", err)
+	}
+	TearDownTestForSvcLB(t, g)
+}
+
+func TestL4RuleInvalidSpec(t *testing.T) {
+	// create L4Rules with an invalid spec, check for Rejected status
+
+	g := gomega.NewGomegaWithT(t)
+	ruleName := "l4-rule-invalid"
+	setUpTestForL4Rule(t, ruleName)
+
+	// hash is allowed only with LB_ALGORITHM_CONSISTENT_HASH.
+	ruleCreate := fakeL4Rule(ruleName)
+	ruleCreate.Spec.BackendProperties.LoadBalancerPolicy.Algorithm = "LB_ALGORITHM_ROUND_ROBIN"
+	if _, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Create(context.TODO(), ruleCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding L4Rule: %v", err)
+	}
+	g.Eventually(func() string {
+		rule, _ := CRDClient.AkoV1alpha1().L4Rules(NAMESPACE).Get(context.TODO(), ruleName, metav1.GetOptions{})
+		return rule.Status.Status
+	}, 15*time.Second).Should(gomega.Equal("Rejected"))
+
+	// an L4 application profile is required.
+	ruleUpdate := fakeL4Rule(ruleName)
+	ruleUpdate.Spec.ApplicationProfile = "thisisaviref-httpappprofile"
+	ruleUpdate.ResourceVersion = "2"
+	if _, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Update(context.TODO(), ruleUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating L4Rule: %v", err)
+	}
+	g.Eventually(func() string {
+		rule, _ := CRDClient.AkoV1alpha1().L4Rules(NAMESPACE).Get(context.TODO(), ruleName, metav1.GetOptions{})
+		return rule.Status.Error
+	}, 15*time.Second).Should(gomega.ContainSubstring("thisisaviref-httpappprofile"))
+
+	g.Eventually(func() bool {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				return nodes[0].AppProfileRef == "" && nodes[0].NetworkProfile != "thisisaviref-networkprofile"
+			}
+		}
+		return false
+	}, 40*time.Second).Should(gomega.Equal(true))
+
+	if err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Delete(context.TODO(), ruleName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting L4Rule: %v", err)
+	}
+	TearDownTestForSvcLB(t, g)
+}
+
+func TestL4RuleLoadBalancerIPOverService(t *testing.T) {
+	// create svcLB with a loadBalancerIP referring to an L4Rule with another loadBalancerIP
+	// check that the VIP of the L4Rule is used, delete the L4Rule, check for the VIP of the Service
+
+	g := gomega.NewGomegaWithT(t)
+	ruleName := "l4-rule-lbip"
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := (FakeService{
+		Name:         SINGLEPORTSVC,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Annotations = map[string]string{lib.L4RuleAnnotation: ruleName}
+	svcExample.Spec.LoadBalancerIP = "10.10.10.20"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	PollForCompletion(t, SINGLEPORTMODEL, 5)
+
+	ruleCreate := fakeL4Rule(ruleName)
+	if _, err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Create(context.TODO(), ruleCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding L4Rule: %v", err)
+	}
+
+	vipAddress := func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 && len(nodes[0].VSVIPRefs) > 0 {
+				return nodes[0].VSVIPRefs[0].IPAddress
+			}
+		}
+		return ""
+	}
+	g.Eventually(vipAddress, 40*time.Second).Should(gomega.Equal("10.10.10.10"))
+
+	if err := lib.AKOControlConfig().CRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Delete(context.TODO(), ruleName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting L4Rule: %v", err)
+	}
+	g.Eventually(vipAddress, 40*time.Second).Should(gomega.Equal("10.10.10.20"))
+
+	TearDownTestForSvcLB(t, g)
+}
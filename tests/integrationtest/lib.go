@@ -978,6 +978,35 @@ func NewAviFakeClientInstance(kubeclient *k8sfake.Clientset, skipCachePopulation
 	}
 }
 
+// allocateV6Vips allocates the IPv6 addresses for the vips, as per the auto_allocate_ip_type
+// in the vsvip request, replacing the IPv4 addresses for V6_ONLY vips.
+func allocateV6Vips(requestVips interface{}, vips []interface{}) {
+	requestVipList, ok := requestVips.([]interface{})
+	if !ok {
+		return
+	}
+	for i, requestVipIntf := range requestVipList {
+		requestVip, ok := requestVipIntf.(map[string]interface{})
+		if !ok || i >= len(vips) {
+			continue
+		}
+		vip := vips[i].(map[string]interface{})
+		ipType, _ := requestVip["auto_allocate_ip_type"].(string)
+		if ipType != "V6_ONLY" && ipType != "V4_V6" {
+			continue
+		}
+		v4Addr := vip["ip_address"].(map[string]string)["addr"]
+		v6Addr := "2001:db8::" + v4Addr[strings.LastIndex(v4Addr, ".")+1:]
+		if staticAddr, ok := requestVip["ip6_address"].(map[string]interface{}); ok {
+			v6Addr = staticAddr["addr"].(string)
+		}
+		vip["ip6_address"] = map[string]string{"addr": v6Addr, "type": "V6"}
+		if ipType == "V6_ONLY" {
+			delete(vip, "ip_address")
+		}
+	}
+}
+
 func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...string) {
 	mockFilePath := defaultMockFilePath
 	if len(args) > 0 {
@@ -1042,6 +1071,7 @@ func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...stri
 			}
 
 			vipAddress = reg.ReplaceAllString(vipAddress, "")
+			requestVips := resp["vip"]
 			resp["vip"] = []interface{}{map[string]interface{}{"ip_address": map[string]string{"addr": vipAddress, "type": "V4"}}}
 			if strings.Contains(rName, "public") {
 				fipAddress := "35.250.250.1"
@@ -1065,6 +1095,7 @@ func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...stri
 					}
 				}
 			}
+			allocateV6Vips(requestVips, resp["vip"].([]interface{}))
 		}
 		finalResponse, _ = json.Marshal(resp)
 		w.WriteHeader(http.StatusOK)
@@ -1107,6 +1138,7 @@ func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...stri
 				vipAddress = addrPrefix + ".1"
 			}
 			vipAddress = reg.ReplaceAllString(vipAddress, "")
+			requestVips := resp["vip"]
 			resp["vip"] = []interface{}{map[string]interface{}{"ip_address": map[string]string{"addr": vipAddress, "type": "V4"}}}
 
 			if strings.Contains(url, "public") {
@@ -1129,6 +1161,7 @@ func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...stri
 					}
 				}
 			}
+			allocateV6Vips(requestVips, resp["vip"].([]interface{}))
 		}
 		finalResponse, _ = json.Marshal(resp)
 		w.WriteHeader(http.StatusOK)
@@ -1145,9 +1178,19 @@ func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...stri
 		data, _ := ioutil.ReadFile(fmt.Sprintf("%s/vrfcontext_uuid_mock.json", mockFilePath))
 		w.Write(data)
 
+	} else if r.Method == "GET" && object == "applicationprofile" && strings.Contains(r.URL.RawQuery, "name=System-") {
+		// the system profiles which the profiles created by AKO are cloned from
+		name := r.URL.Query().Get("name")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fmt.Sprintf(`{"results": [{"name": "%s", "uuid": "applicationprofile-%s", "type": "APPLICATION_PROFILE_TYPE_HTTP", `+
+			`"http_profile": {"http_to_https": true, "x_forwarded_proto_enabled": true, "websockets_enabled": true}}], "count": 1}`, name, name)))
+
 	} else if r.Method == "GET" && strings.Contains(r.URL.RawQuery, "aviref") {
 		// block to handle
-		if strings.Contains(r.URL.RawQuery, "thisisaviref") {
+		if strings.Contains(r.URL.RawQuery, "thisisaviref-l4appprofile") {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"results": [{"name": "thisisaviref-l4appprofile", "type": "APPLICATION_PROFILE_TYPE_L4"}], "count": 1}`))
+		} else if strings.Contains(r.URL.RawQuery, "thisisaviref") {
 			w.WriteHeader(http.StatusOK)
 			data, _ := ioutil.ReadFile(fmt.Sprintf("%s/crd_mock.json", mockFilePath))
 			w.Write(data)
@@ -24,6 +24,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 
 	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -279,3 +280,43 @@ func TestNodeCIDRInAnnotation(t *testing.T) {
 	}, 10*time.Second).Should(gomega.Equal("10.244.0.0"))
 	KubeClient.CoreV1().Nodes().Delete(context.TODO(), "testNodeAnnotation", metav1.DeleteOptions{})
 }
+
+func TestDualStackNodeAdd(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/global"
+	nodeip, nodeip6 := "10.1.1.3", "2001:db8:1::3"
+	objects.SharedAviGraphLister().Delete(modelName)
+	nodeExample := (FakeNode{
+		Name:    "testNodeDualStack",
+		PodCIDR: "10.246.0.0/24",
+		Version: "1",
+		NodeIP:  nodeip,
+	}).Node()
+	nodeExample.Spec.PodCIDRs = []string{"10.246.0.0/24", "fd00:10:246::/64"}
+	nodeExample.Status.Addresses = append(nodeExample.Status.Addresses, corev1.NodeAddress{Type: "InternalIP", Address: nodeip6})
+
+	_, err := KubeClient.CoreV1().Nodes().Create(context.TODO(), nodeExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in adding Node: %v", err)
+	}
+
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVRF(); len(nodes) > 0 {
+				return len(nodes[0].StaticRoutes)
+			}
+		}
+		return 0
+	}, 10*time.Second).Should(gomega.Equal(2))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVRF()
+	g.Expect(*(nodes[0].StaticRoutes[0].NextHop.Addr)).To(gomega.Equal(nodeip))
+	g.Expect(*(nodes[0].StaticRoutes[0].Prefix.IPAddr.Type)).To(gomega.Equal("V4"))
+	g.Expect(*(nodes[0].StaticRoutes[1].NextHop.Addr)).To(gomega.Equal(nodeip6))
+	g.Expect(*(nodes[0].StaticRoutes[1].NextHop.Type)).To(gomega.Equal("V6"))
+	g.Expect(*(nodes[0].StaticRoutes[1].Prefix.IPAddr.Addr)).To(gomega.Equal("fd00:10:246::"))
+	g.Expect(*(nodes[0].StaticRoutes[1].Prefix.IPAddr.Type)).To(gomega.Equal("V6"))
+	g.Expect(*(nodes[0].StaticRoutes[1].Prefix.Mask)).To(gomega.Equal(int32(64)))
+
+	KubeClient.CoreV1().Nodes().Delete(context.TODO(), "testNodeDualStack", metav1.DeleteOptions{})
+}
@@ -0,0 +1,749 @@
+/*
+ * Copyright 2022-2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package istiotests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	istionetworking "istio.io/api/networking/v1alpha3"
+	istiov1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	istiofake "istio.io/client-go/pkg/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+var IstioClient *istiofake.Clientset
+var ctrl *k8s.AviController
+
+func TestMain(m *testing.M) {
+	os.Setenv("ISTIO_ENABLED", "true")
+	os.Setenv("INGRESS_API", "extensionv1")
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+	os.Setenv("AUTO_L4_FQDN", "default")
+
+	akoControlConfig := lib.AKOControlConfig()
+	KubeClient = k8sfake.NewSimpleClientset()
+	CRDClient = crdfake.NewSimpleClientset()
+	akoControlConfig.SetCRDClientset(CRDClient)
+	akoControlConfig.SetEventRecorder(lib.AKOEventComponent, KubeClient, true)
+	akoControlConfig.SetAKOInstanceFlag(true)
+	k8s.NewCRDInformers(CRDClient)
+
+	IstioClient = istiofake.NewSimpleClientset()
+	akoControlConfig.SetIstioClientset(IstioClient)
+	k8s.NewIstioCRDInformers(IstioClient)
+
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+		utils.PodInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+
+	mcache := cache.SharedAviObjCache()
+	cloudObj := &cache.AviCloudPropertyCache{Name: "Default-Cloud", VType: "mock"}
+	subdomains := []string{"avi.internal", ".com"}
+	cloudObj.NSIpamDNS = subdomains
+	mcache.CloudKeyCache.AviCacheAdd("Default-Cloud", cloudObj)
+
+	integrationtest.InitializeFakeAKOAPIServer()
+
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+
+	integrationtest.AddConfigMap(KubeClient)
+	ctrl.SetSEGroupCloudName()
+
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	integrationtest.KubeClient = KubeClient
+	os.Exit(m.Run())
+}
+
+// Istio Gateway, VirtualService and DestinationRule lib functions
+func SetupIstioGateway(t *testing.T, name, namespace string, hosts []string) {
+	gateway := &istiov1alpha3.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: istionetworking.Gateway{
+			Selector: map[string]string{"istio": "ingressgateway"},
+			Servers: []*istionetworking.Server{{
+				Port:  &istionetworking.Port{Number: 80, Name: "http", Protocol: "HTTP"},
+				Hosts: hosts,
+			}},
+		},
+	}
+	if _, err := IstioClient.NetworkingV1alpha3().Gateways(namespace).Create(context.TODO(), gateway, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Istio Gateway: %v", err)
+	}
+}
+
+func TeardownIstioGateway(t *testing.T, name, namespace string) {
+	if err := IstioClient.NetworkingV1alpha3().Gateways(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Istio Gateway: %v", err)
+	}
+}
+
+func SetupIstioVirtualService(t *testing.T, name, namespace string, gateways, hosts []string, httpRoutes []*istionetworking.HTTPRoute) {
+	vs := &istiov1alpha3.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: istionetworking.VirtualService{
+			Hosts:    hosts,
+			Gateways: gateways,
+			Http:     httpRoutes,
+		},
+	}
+	if _, err := IstioClient.NetworkingV1alpha3().VirtualServices(namespace).Create(context.TODO(), vs, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Istio VirtualService: %v", err)
+	}
+}
+
+func TeardownIstioVirtualService(t *testing.T, name, namespace string) {
+	if err := IstioClient.NetworkingV1alpha3().VirtualServices(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Istio VirtualService: %v", err)
+	}
+}
+
+func SetupIstioDestinationRule(t *testing.T, name, namespace, host string, trafficPolicy *istionetworking.TrafficPolicy, subsets []*istionetworking.Subset) {
+	dr := &istiov1alpha3.DestinationRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: istionetworking.DestinationRule{
+			Host:          host,
+			TrafficPolicy: trafficPolicy,
+			Subsets:       subsets,
+		},
+	}
+	if _, err := IstioClient.NetworkingV1alpha3().DestinationRules(namespace).Create(context.TODO(), dr, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Istio DestinationRule: %v", err)
+	}
+}
+
+func TeardownIstioDestinationRule(t *testing.T, name, namespace string) {
+	if err := IstioClient.NetworkingV1alpha3().DestinationRules(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Istio DestinationRule: %v", err)
+	}
+}
+
+func IstioDestination(host, subset string, weight int32) *istionetworking.HTTPRouteDestination {
+	return &istionetworking.HTTPRouteDestination{
+		Destination: &istionetworking.Destination{
+			Host:   host,
+			Subset: subset,
+			Port:   &istionetworking.PortSelector{Number: 8080},
+		},
+		Weight: weight,
+	}
+}
+
+func IstioPrefixMatch(prefix string) *istionetworking.HTTPMatchRequest {
+	return &istionetworking.HTTPMatchRequest{
+		Uri: &istionetworking.StringMatch{MatchType: &istionetworking.StringMatch_Prefix{Prefix: prefix}},
+	}
+}
+
+// CreatePodsWithEP creates a pod for each of the labels and the endpoints of the service with the pod
+// addresses 1.1.1.1, 1.1.1.2 etc., referring to the pods.
+func CreatePodsWithEP(t *testing.T, ns, svcName string, podLabels []map[string]string) {
+	var epAddresses []corev1.EndpointAddress
+	for i, podLabel := range podLabels {
+		podName := fmt.Sprintf("%s-pod-%d", svcName, i)
+		ip := fmt.Sprintf("1.1.1.%d", i+1)
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: ns, Labels: podLabel},
+			Status:     corev1.PodStatus{PodIP: ip},
+		}
+		if _, err := KubeClient.CoreV1().Pods(ns).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error in creating Pod: %v", err)
+		}
+		epAddresses = append(epAddresses, corev1.EndpointAddress{
+			IP:        ip,
+			TargetRef: &corev1.ObjectReference{Kind: utils.Pod, Name: podName, Namespace: ns},
+		})
+	}
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: svcName},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: epAddresses,
+			Ports:     []corev1.EndpointPort{{Name: "foo0", Port: 8080, Protocol: "TCP"}},
+		}},
+	}
+	if _, err := KubeClient.CoreV1().Endpoints(ns).Create(context.TODO(), epExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+}
+
+func DeletePods(t *testing.T, ns, svcName string, count int) {
+	for i := 0; i < count; i++ {
+		if err := KubeClient.CoreV1().Pods(ns).Delete(context.TODO(), fmt.Sprintf("%s-pod-%d", svcName, i), metav1.DeleteOptions{}); err != nil {
+			t.Fatalf("error in deleting Pod: %v", err)
+		}
+	}
+}
+
+// WaitForServiceSync waits for the service to be processed and its endpoints to be in the informer cache,
+// for the pools of the Istio VirtualServices created later to have the servers on the first sync.
+func WaitForServiceSync(g *gomega.WithT, ns, svcName string) {
+	g.Eventually(func() bool {
+		found, _ := objects.SharedClusterIpLister().Get(ns + "/" + svcName)
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() error {
+		_, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(svcName)
+		return err
+	}, 10*time.Second).Should(gomega.BeNil())
+}
+
+func GetIstioGatewayVSNode(modelName string) *avinodes.AviVsNode {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
+
+func VerifyIstioGatewayVSNodeDeletion(g *gomega.WithT, modelName string) {
+	g.Eventually(func() interface{} {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return aviModel
+	}, 30*time.Second).Should(gomega.BeNil())
+}
+
+func TestIstioGatewayHostRoute(t *testing.T) {
+	// create svc, gateway and virtualservice
+	// check the VS node, the poolgroup and the host/path match of the route
+	g := gomega.NewGomegaWithT(t)
+
+	gatewayName, vsName, ns := "my-gateway", "my-vs", "default"
+	modelName := "admin/" + lib.GetIstioGatewayVSName(gatewayName, ns)
+
+	integrationtest.CreateSVC(t, ns, "avisvc", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, ns, "avisvc", false, false, "1.1.1")
+	WaitForServiceSync(g, ns, "avisvc")
+	SetupIstioGateway(t, gatewayName, ns, []string{"foo.com"})
+	SetupIstioVirtualService(t, vsName, ns, []string{gatewayName}, []string{"foo.com"}, []*istionetworking.HTTPRoute{{
+		Match: []*istionetworking.HTTPMatchRequest{IstioPrefixMatch("/foo")},
+		Route: []*istionetworking.HTTPRouteDestination{IstioDestination("avisvc", "", 0)},
+	}})
+
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolGroupRefs)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+
+	pgName := lib.GetIstioPGName(gatewayName, ns, vsName, ns, 0)
+	vsNode := GetIstioGatewayVSNode(modelName)
+	g.Expect(vsNode.Name).To(gomega.Equal(lib.GetIstioGatewayVSName(gatewayName, ns)))
+	g.Expect(vsNode.Dedicated).To(gomega.BeTrue())
+	g.Expect(vsNode.PortProto).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PortProto[0].Port).To(gomega.Equal(int32(80)))
+	g.Expect(vsNode.VSVIPRefs[0].FQDNs).To(gomega.ConsistOf("foo.com"))
+	g.Expect(vsNode.PoolGroupRefs[0].Name).To(gomega.Equal(pgName))
+	g.Expect(vsNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(*vsNode.PoolGroupRefs[0].Members[0].Ratio).To(gomega.Equal(int32(100)))
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolRefs[0].Name).To(gomega.Equal(lib.GetIstioPoolName(gatewayName, ns, vsName, ns, 0, "avisvc", ns, "", 8080)))
+	g.Expect(vsNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+	g.Expect(vsNode.HttpPolicyRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.HttpPolicyRefs[0].Name).To(gomega.Equal(lib.GetIstioHttpPolName(gatewayName, ns)))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(1))
+	hpp := vsNode.HttpPolicyRefs[0].HppMap[0]
+	g.Expect(hpp.Host).To(gomega.ConsistOf("foo.com"))
+	g.Expect(hpp.Path).To(gomega.ConsistOf("/foo"))
+	g.Expect(hpp.MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+	g.Expect(hpp.PoolGroup).To(gomega.Equal(pgName))
+
+	TeardownIstioVirtualService(t, vsName, ns)
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolGroupRefs) + len(vsNode.HttpPolicyRefs)
+		}
+		return -1
+	}, 30*time.Second).Should(gomega.Equal(0))
+
+	TeardownIstioGateway(t, gatewayName, ns)
+	VerifyIstioGatewayVSNodeDeletion(g, modelName)
+	integrationtest.DelSVC(t, ns, "avisvc")
+	integrationtest.DelEP(t, ns, "avisvc")
+}
+
+func TestIstioDestinationRuleSubsets(t *testing.T) {
+	// create pods with version labels, a destinationrule with subsets and a weighted route to the subsets
+	// check that the pool of each subset has the servers of the pods of the subset
+	g := gomega.NewGomegaWithT(t)
+
+	gatewayName, vsName, drName, ns := "my-gateway", "my-vs", "my-dr", "default"
+	modelName := "admin/" + lib.GetIstioGatewayVSName(gatewayName, ns)
+
+	integrationtest.CreateSVC(t, ns, "avisvc", corev1.ServiceTypeClusterIP, false)
+	CreatePodsWithEP(t, ns, "avisvc", []map[string]string{{"version": "v1"}, {"version": "v2"}, {"version": "v2"}})
+	WaitForServiceSync(g, ns, "avisvc")
+	g.Eventually(func() int {
+		pods, _ := utils.GetInformers().PodInformer.Lister().Pods(ns).List(labels.Everything())
+		return len(pods)
+	}, 10*time.Second).Should(gomega.Equal(3))
+	SetupIstioDestinationRule(t, drName, ns, "avisvc",
+		&istionetworking.TrafficPolicy{
+			LoadBalancer: &istionetworking.LoadBalancerSettings{
+				LbPolicy: &istionetworking.LoadBalancerSettings_Simple{Simple: istionetworking.LoadBalancerSettings_LEAST_CONN},
+			},
+		},
+		[]*istionetworking.Subset{
+			{Name: "v1", Labels: map[string]string{"version": "v1"}},
+			{Name: "v2", Labels: map[string]string{"version": "v2"}},
+		})
+	SetupIstioGateway(t, gatewayName, ns, []string{"foo.com"})
+	SetupIstioVirtualService(t, vsName, ns, []string{gatewayName}, []string{"foo.com"}, []*istionetworking.HTTPRoute{{
+		Route: []*istionetworking.HTTPRouteDestination{
+			IstioDestination("avisvc", "v1", 80),
+			IstioDestination("avisvc.default.svc.cluster.local", "v2", 20),
+		},
+	}})
+
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolRefs)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(2))
+
+	vsNode := GetIstioGatewayVSNode(modelName)
+	g.Expect(vsNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(2))
+	g.Expect(*vsNode.PoolGroupRefs[0].Members[0].Ratio).To(gomega.Equal(int32(80)))
+	g.Expect(*vsNode.PoolGroupRefs[0].Members[1].Ratio).To(gomega.Equal(int32(20)))
+	g.Expect(vsNode.PoolRefs[0].Name).To(gomega.Equal(lib.GetIstioPoolName(gatewayName, ns, vsName, ns, 0, "avisvc", ns, "v1", 8080)))
+	g.Expect(vsNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+	g.Expect(*vsNode.PoolRefs[0].Servers[0].Ip.Addr).To(gomega.Equal("1.1.1.1"))
+	g.Expect(vsNode.PoolRefs[0].LbAlgorithm).To(gomega.Equal(lib.LB_ALGORITHM_LEAST_CONNECTIONS))
+	g.Expect(vsNode.PoolRefs[1].Name).To(gomega.Equal(lib.GetIstioPoolName(gatewayName, ns, vsName, ns, 0, "avisvc", ns, "v2", 8080)))
+	g.Expect(vsNode.PoolRefs[1].Servers).To(gomega.HaveLen(2))
+	g.Expect(*vsNode.PoolRefs[1].Servers[0].Ip.Addr).To(gomega.Equal("1.1.1.2"))
+	g.Expect(*vsNode.PoolRefs[1].Servers[1].Ip.Addr).To(gomega.Equal("1.1.1.3"))
+	g.Expect(vsNode.HttpPolicyRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(1))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap[0].Path).To(gomega.BeEmpty())
+
+	// without the DestinationRule, the subsets can not be resolved and the pools have all the servers.
+	TeardownIstioDestinationRule(t, drName, ns)
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName); vsNode != nil && len(vsNode.PoolRefs) == 2 {
+			return len(vsNode.PoolRefs[0].Servers)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(3))
+	vsNode = GetIstioGatewayVSNode(modelName)
+	g.Expect(vsNode.PoolRefs[0].LbAlgorithm).To(gomega.BeEmpty())
+
+	TeardownIstioVirtualService(t, vsName, ns)
+	TeardownIstioGateway(t, gatewayName, ns)
+	VerifyIstioGatewayVSNodeDeletion(g, modelName)
+	integrationtest.DelSVC(t, ns, "avisvc")
+	integrationtest.DelEP(t, ns, "avisvc")
+	DeletePods(t, ns, "avisvc", 3)
+}
+
+func TestIstioRedirectRewriteHeaders(t *testing.T) {
+	// create a virtualservice with a redirect route and a route with a rewrite and header operations
+	// check the redirect, rewrite and header actions on the httppolicyset
+	g := gomega.NewGomegaWithT(t)
+
+	gatewayName, vsName, ns := "my-gateway", "my-vs", "default"
+	modelName := "admin/" + lib.GetIstioGatewayVSName(gatewayName, ns)
+
+	integrationtest.CreateSVC(t, ns, "avisvc", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, ns, "avisvc", false, false, "1.1.1")
+	WaitForServiceSync(g, ns, "avisvc")
+	SetupIstioGateway(t, gatewayName, ns, []string{"foo.com"})
+	SetupIstioVirtualService(t, vsName, ns, []string{gatewayName}, []string{"foo.com"}, []*istionetworking.HTTPRoute{
+		{
+			Match: []*istionetworking.HTTPMatchRequest{{
+				Uri: &istionetworking.StringMatch{MatchType: &istionetworking.StringMatch_Exact{Exact: "/old"}},
+			}},
+			Redirect: &istionetworking.HTTPRedirect{Uri: "/new", Authority: "bar.com", RedirectCode: 302},
+		},
+		{
+			Match:   []*istionetworking.HTTPMatchRequest{IstioPrefixMatch("/api")},
+			Rewrite: &istionetworking.HTTPRewrite{Uri: "/v2"},
+			Headers: &istionetworking.Headers{
+				Request: &istionetworking.Headers_HeaderOperations{
+					Set: map[string]string{"x-request": "set"},
+					Add: map[string]string{"x-added": "added"},
+				},
+				Response: &istionetworking.Headers_HeaderOperations{
+					Remove: []string{"x-internal"},
+				},
+			},
+			Route: []*istionetworking.HTTPRouteDestination{IstioDestination("avisvc", "", 0)},
+		},
+	})
+
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName); vsNode != nil && len(vsNode.HttpPolicyRefs) == 1 {
+			return len(vsNode.HttpPolicyRefs[0].HppMap)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(2))
+
+	vsNode := GetIstioGatewayVSNode(modelName)
+	// The redirect route does not have a poolgroup.
+	g.Expect(vsNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolGroupRefs[0].Name).To(gomega.Equal(lib.GetIstioPGName(gatewayName, ns, vsName, ns, 1)))
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(1))
+
+	redirectHpp := vsNode.HttpPolicyRefs[0].HppMap[0]
+	g.Expect(redirectHpp.Path).To(gomega.ConsistOf("/old"))
+	g.Expect(redirectHpp.MatchCriteria).To(gomega.Equal("EQUALS"))
+	g.Expect(redirectHpp.PoolGroup).To(gomega.BeEmpty())
+	g.Expect(redirectHpp.Redirect).NotTo(gomega.BeNil())
+	g.Expect(redirectHpp.Redirect.Host).To(gomega.Equal("bar.com"))
+	g.Expect(redirectHpp.Redirect.Path).To(gomega.Equal("/new"))
+	g.Expect(redirectHpp.Redirect.Protocol).To(gomega.Equal("HTTP"))
+	g.Expect(redirectHpp.Redirect.StatusCode).To(gomega.Equal(lib.STATUS_REDIRECT))
+
+	rewriteHpp := vsNode.HttpPolicyRefs[0].HppMap[1]
+	g.Expect(rewriteHpp.Path).To(gomega.ConsistOf("/api"))
+	g.Expect(rewriteHpp.PoolGroup).To(gomega.Equal(lib.GetIstioPGName(gatewayName, ns, vsName, ns, 1)))
+	g.Expect(rewriteHpp.Redirect).To(gomega.BeNil())
+	g.Expect(rewriteHpp.RewriteURL).NotTo(gomega.BeNil())
+	g.Expect(rewriteHpp.RewriteURL.Path).To(gomega.Equal("/v2"))
+	g.Expect(rewriteHpp.RewriteURL.ReplacePrefix).To(gomega.BeTrue())
+	g.Expect(rewriteHpp.RewriteURL.PrefixTokens).To(gomega.Equal(int32(1)))
+	g.Expect(rewriteHpp.RequestHeaders).To(gomega.Equal([]avinodes.AviHTTPHeaderAction{
+		{Action: lib.HTTPReplaceHeader, Name: "x-request", Value: "set"},
+		{Action: lib.HTTPAddHeader, Name: "x-added", Value: "added"},
+	}))
+	g.Expect(rewriteHpp.ResponseHeaders).To(gomega.Equal([]avinodes.AviHTTPHeaderAction{
+		{Action: lib.HTTPRemoveHeader, Name: "x-internal"},
+	}))
+
+	TeardownIstioVirtualService(t, vsName, ns)
+	TeardownIstioGateway(t, gatewayName, ns)
+	VerifyIstioGatewayVSNodeDeletion(g, modelName)
+	integrationtest.DelSVC(t, ns, "avisvc")
+	integrationtest.DelEP(t, ns, "avisvc")
+}
+
+func TestIstioMatchGatewaysFilter(t *testing.T) {
+	// create a virtualservice bound to two gateways, with a route that matches only one of the gateways
+	// check that the route, along with its pools and poolgroup, is skipped on the other gateway
+	g := gomega.NewGomegaWithT(t)
+
+	gatewayName1, gatewayName2, vsName, ns := "my-gateway1", "my-gateway2", "my-vs", "default"
+	modelName1 := "admin/" + lib.GetIstioGatewayVSName(gatewayName1, ns)
+	modelName2 := "admin/" + lib.GetIstioGatewayVSName(gatewayName2, ns)
+
+	integrationtest.CreateSVC(t, ns, "avisvc", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, ns, "avisvc", false, false, "1.1.1")
+	WaitForServiceSync(g, ns, "avisvc")
+	SetupIstioGateway(t, gatewayName1, ns, []string{"foo.com"})
+	SetupIstioGateway(t, gatewayName2, ns, []string{"foo.com"})
+	gw2Match := IstioPrefixMatch("/gw2")
+	gw2Match.Gateways = []string{ns + "/" + gatewayName2}
+	SetupIstioVirtualService(t, vsName, ns, []string{gatewayName1, gatewayName2}, []string{"foo.com"}, []*istionetworking.HTTPRoute{
+		{
+			Match: []*istionetworking.HTTPMatchRequest{gw2Match},
+			Route: []*istionetworking.HTTPRouteDestination{IstioDestination("avisvc", "", 0)},
+		},
+		{
+			Route: []*istionetworking.HTTPRouteDestination{IstioDestination("avisvc", "", 0)},
+		},
+	})
+
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName2); vsNode != nil {
+			return len(vsNode.PoolGroupRefs)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(2))
+	vsNode := GetIstioGatewayVSNode(modelName2)
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(2))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(2))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap[0].Path).To(gomega.ConsistOf("/gw2"))
+
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName1); vsNode != nil {
+			return len(vsNode.PoolGroupRefs)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+	vsNode = GetIstioGatewayVSNode(modelName1)
+	g.Expect(vsNode.PoolGroupRefs[0].Name).To(gomega.Equal(lib.GetIstioPGName(gatewayName1, ns, vsName, ns, 1)))
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolRefs[0].Name).To(gomega.Equal(lib.GetIstioPoolName(gatewayName1, ns, vsName, ns, 1, "avisvc", ns, "", 8080)))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(1))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap[0].Path).To(gomega.BeEmpty())
+
+	TeardownIstioVirtualService(t, vsName, ns)
+	TeardownIstioGateway(t, gatewayName1, ns)
+	TeardownIstioGateway(t, gatewayName2, ns)
+	VerifyIstioGatewayVSNodeDeletion(g, modelName1)
+	VerifyIstioGatewayVSNodeDeletion(g, modelName2)
+	integrationtest.DelSVC(t, ns, "avisvc")
+	integrationtest.DelEP(t, ns, "avisvc")
+}
+
+func TestIstioDestinationServiceNotFound(t *testing.T) {
+	// create a virtualservice with a route to a service that does not exist
+	// check that no poolgroup or route is added, until the service gets created
+	g := gomega.NewGomegaWithT(t)
+
+	gatewayName, vsName, ns := "my-gateway", "my-vs", "default"
+	modelName := "admin/" + lib.GetIstioGatewayVSName(gatewayName, ns)
+
+	SetupIstioGateway(t, gatewayName, ns, []string{"foo.com"})
+	SetupIstioVirtualService(t, vsName, ns, []string{gatewayName}, []string{"foo.com"}, []*istionetworking.HTTPRoute{{
+		Match: []*istionetworking.HTTPMatchRequest{IstioPrefixMatch("/foo")},
+		Route: []*istionetworking.HTTPRouteDestination{IstioDestination("avisvc", "", 0)},
+	}})
+
+	g.Eventually(func() bool {
+		return GetIstioGatewayVSNode(modelName) != nil
+	}, 30*time.Second).Should(gomega.BeTrue())
+	g.Consistently(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolGroupRefs) + len(vsNode.PoolRefs) + len(vsNode.HttpPolicyRefs)
+		}
+		return -1
+	}, 5*time.Second).Should(gomega.Equal(0))
+
+	integrationtest.CreateSVC(t, ns, "avisvc", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, ns, "avisvc", false, false, "1.1.1")
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolGroupRefs)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+	vsNode := GetIstioGatewayVSNode(modelName)
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.HttpPolicyRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap[0].Path).To(gomega.ConsistOf("/foo"))
+
+	TeardownIstioVirtualService(t, vsName, ns)
+	TeardownIstioGateway(t, gatewayName, ns)
+	VerifyIstioGatewayVSNodeDeletion(g, modelName)
+	integrationtest.DelSVC(t, ns, "avisvc")
+	integrationtest.DelEP(t, ns, "avisvc")
+}
+
+func TestIstioGatewaySelectorNotMatched(t *testing.T) {
+	// create a gateway selecting a different gateway workload
+	// check that no model is built, until the selector is updated to select the ingressgateway
+	g := gomega.NewGomegaWithT(t)
+
+	gatewayName, ns := "my-gateway", "default"
+	modelName := "admin/" + lib.GetIstioGatewayVSName(gatewayName, ns)
+
+	gateway := &istiov1alpha3.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: gatewayName, Namespace: ns},
+		Spec: istionetworking.Gateway{
+			Selector: map[string]string{"istio": "eastwestgateway"},
+			Servers: []*istionetworking.Server{{
+				Port:  &istionetworking.Port{Number: 80, Name: "http", Protocol: "HTTP"},
+				Hosts: []string{"foo.com"},
+			}},
+		},
+	}
+	if _, err := IstioClient.NetworkingV1alpha3().Gateways(ns).Create(context.TODO(), gateway, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Istio Gateway: %v", err)
+	}
+	g.Consistently(func() bool {
+		return GetIstioGatewayVSNode(modelName) != nil
+	}, 5*time.Second).Should(gomega.BeFalse())
+
+	gateway.Spec.Selector = map[string]string{"istio": "ingressgateway"}
+	gateway.ResourceVersion = "2"
+	if _, err := IstioClient.NetworkingV1alpha3().Gateways(ns).Update(context.TODO(), gateway, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Istio Gateway: %v", err)
+	}
+	g.Eventually(func() bool {
+		return GetIstioGatewayVSNode(modelName) != nil
+	}, 30*time.Second).Should(gomega.BeTrue())
+
+	gateway.Spec.Selector = map[string]string{"istio": "eastwestgateway"}
+	gateway.ResourceVersion = "3"
+	if _, err := IstioClient.NetworkingV1alpha3().Gateways(ns).Update(context.TODO(), gateway, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Istio Gateway: %v", err)
+	}
+	VerifyIstioGatewayVSNodeDeletion(g, modelName)
+
+	TeardownIstioGateway(t, gatewayName, ns)
+}
+
+func TestIstioExternalDestinationHost(t *testing.T) {
+	// create a virtualservice with a route to an in-cluster service and a route to an external host
+	// check that only the route to the in-cluster service is programmed
+	g := gomega.NewGomegaWithT(t)
+
+	gatewayName, vsName, ns := "my-gateway", "my-vs", "default"
+	modelName := "admin/" + lib.GetIstioGatewayVSName(gatewayName, ns)
+
+	integrationtest.CreateSVC(t, ns, "avisvc", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, ns, "avisvc", false, false, "1.1.1")
+	// a service that the external host would resolve to, when split on "."
+	integrationtest.CreateSVC(t, "com", "example", corev1.ServiceTypeClusterIP, false)
+	WaitForServiceSync(g, ns, "avisvc")
+	SetupIstioGateway(t, gatewayName, ns, []string{"foo.com"})
+	SetupIstioVirtualService(t, vsName, ns, []string{gatewayName}, []string{"foo.com"}, []*istionetworking.HTTPRoute{
+		{
+			Match: []*istionetworking.HTTPMatchRequest{IstioPrefixMatch("/ext")},
+			Route: []*istionetworking.HTTPRouteDestination{IstioDestination("example.com", "", 0)},
+		},
+		{
+			Match: []*istionetworking.HTTPMatchRequest{IstioPrefixMatch("/foo")},
+			Route: []*istionetworking.HTTPRouteDestination{IstioDestination("avisvc.default.svc.cluster.local", "", 0)},
+		},
+	})
+
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PoolGroupRefs)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+	vsNode := GetIstioGatewayVSNode(modelName)
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolRefs[0].Name).To(gomega.Equal(lib.GetIstioPoolName(gatewayName, ns, vsName, ns, 1, "avisvc", ns, "", 8080)))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(1))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap[0].Path).To(gomega.ConsistOf("/foo"))
+
+	TeardownIstioVirtualService(t, vsName, ns)
+	TeardownIstioGateway(t, gatewayName, ns)
+	VerifyIstioGatewayVSNodeDeletion(g, modelName)
+	integrationtest.DelSVC(t, "com", "example")
+	integrationtest.DelSVC(t, ns, "avisvc")
+	integrationtest.DelEP(t, ns, "avisvc")
+}
+
+func TestIstioHTTPSServersSecretNotFound(t *testing.T) {
+	// create a gateway with two https servers using the same secret, that does not exist
+	// check that ssl is not enabled on any port without the certificate, until the secret gets created
+	g := gomega.NewGomegaWithT(t)
+
+	gatewayName, ns, secretName := "my-gateway", "default", "gw-secret"
+	modelName := "admin/" + lib.GetIstioGatewayVSName(gatewayName, ns)
+
+	tls := &istionetworking.ServerTLSSettings{Mode: istionetworking.ServerTLSSettings_SIMPLE, CredentialName: secretName}
+	gateway := &istiov1alpha3.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: gatewayName, Namespace: ns},
+		Spec: istionetworking.Gateway{
+			Selector: map[string]string{"istio": "ingressgateway"},
+			Servers: []*istionetworking.Server{
+				{Port: &istionetworking.Port{Number: 80, Name: "http", Protocol: "HTTP"}, Hosts: []string{"foo.com"}},
+				{Port: &istionetworking.Port{Number: 443, Name: "https", Protocol: "HTTPS"}, Hosts: []string{"foo.com"}, Tls: tls},
+				{Port: &istionetworking.Port{Number: 8443, Name: "https-alt", Protocol: "HTTPS"}, Hosts: []string{"foo.com"}, Tls: tls},
+			},
+		},
+	}
+	if _, err := IstioClient.NetworkingV1alpha3().Gateways(ns).Create(context.TODO(), gateway, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Istio Gateway: %v", err)
+	}
+	g.Eventually(func() bool {
+		return GetIstioGatewayVSNode(modelName) != nil
+	}, 30*time.Second).Should(gomega.BeTrue())
+	vsNode := GetIstioGatewayVSNode(modelName)
+	g.Expect(vsNode.PortProto).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PortProto[0].Port).To(gomega.Equal(int32(80)))
+	g.Expect(vsNode.SSLKeyCertRefs).To(gomega.HaveLen(0))
+
+	integrationtest.AddSecret(secretName, ns, "tlsCert", "tlsKey")
+	g.Eventually(func() int {
+		if vsNode := GetIstioGatewayVSNode(modelName); vsNode != nil {
+			return len(vsNode.PortProto)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(3))
+	vsNode = GetIstioGatewayVSNode(modelName)
+	g.Expect(vsNode.SSLKeyCertRefs).To(gomega.HaveLen(1))
+	for _, pp := range vsNode.PortProto {
+		g.Expect(pp.EnableSSL).To(gomega.Equal(pp.Port != 80))
+	}
+
+	TeardownIstioGateway(t, gatewayName, ns)
+	VerifyIstioGatewayVSNodeDeletion(g, modelName)
+	integrationtest.DeleteSecret(secretName, ns)
+}
@@ -302,6 +302,46 @@ func TestIngressNoUpdate(t *testing.T) {
 	waitAndverify(t, "")
 }
 
+// The sync condition set by AKO in the annotation of the ingress should not add the ingress key to ingestion queue
+func TestIngressSyncConditionNoUpdate(t *testing.T) {
+	ingrSynced := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "red-ns",
+			Name:      "testingr-synced",
+		},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: "testsvc",
+				},
+			},
+		},
+	}
+	_, err := kubeClient.NetworkingV1().Ingresses("red-ns").Create(context.TODO(), ingrSynced, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	waitAndverify(t, "Ingress/red-ns/testingr-synced")
+
+	ingrSynced.Annotations = map[string]string{
+		lib.SyncedConditionType: `{"type":"ako.vmware.com/Synced","status":"False","reason":"SyncFailed","message":"Rest request error"}`,
+	}
+	ingrSynced.ResourceVersion = "2"
+	_, err = kubeClient.NetworkingV1().Ingresses("red-ns").Update(context.TODO(), ingrSynced, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("error in updating Ingress: %v", err)
+	}
+
+	ingrSynced.Annotations[lib.SyncedConditionType] = `{"type":"ako.vmware.com/Synced","status":"False","reason":"SyncFailed","message":"Rest request timeout"}`
+	ingrSynced.ResourceVersion = "3"
+	_, err = kubeClient.NetworkingV1().Ingresses("red-ns").Update(context.TODO(), ingrSynced, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("error in updating Ingress: %v", err)
+	}
+
+	waitAndverify(t, "")
+}
+
 func TestNode(t *testing.T) {
 	nodeExample := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2023 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package offlinesynctests
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+
+const (
+	modelName  = "admin/cluster--Shared-L7-0"
+	checkpoint = `{"version":"2","controllerUUID":"cluster-1","cloud":"CLOUD_VCENTER","clusterName":"cluster","tenant":"admin",
+"vsVips":[{"Name":"cluster--Shared-L7-0","Tenant":"admin","Uuid":"vsvip-1","Vips":["10.10.10.1"]}],
+"virtualServices":[{"Name":"cluster--Shared-L7-0","Tenant":"admin","Uuid":"virtualservice-1",
+"VSVipKeyCollection":[{"Namespace":"admin","Name":"cluster--Shared-L7-0"}]}]}`
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "SMALL")
+	os.Setenv("DISABLE_STATIC_ROUTE_SYNC", "true")
+	utils.SetCloudName("CLOUD_VCENTER")
+
+	enableVirtualHost := false
+	kubeObjects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		integrationtest.ConstructService("default", "avisvc", corev1.ServiceTypeClusterIP, false, nil),
+		&corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "avisvc"},
+			Subsets: []corev1.EndpointSubset{{
+				Addresses: []corev1.EndpointAddress{{IP: "1.1.1.1"}, {IP: "1.1.1.2"}},
+				Ports:     []corev1.EndpointPort{{Name: "foo0", Port: 8080, Protocol: "TCP"}},
+			}},
+		},
+		&networking.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        integrationtest.DefaultIngressClass,
+				Annotations: map[string]string{lib.DefaultIngressClassAnnotation: "true"},
+			},
+			Spec: networking.IngressClassSpec{Controller: lib.AviIngressController},
+		},
+		(integrationtest.FakeIngress{
+			Name:         "foo-with-targets",
+			Namespace:    "default",
+			DnsNames:     []string{"foo.com", "bar.com"},
+			Paths:        []string{"/foo", "/bar"},
+			ServiceName:  "avisvc",
+			TlsSecretDNS: map[string][]string{"my-secret": {"bar.com"}},
+		}).Ingress(),
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "my-secret"},
+			Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+		},
+	}
+	crdObjects := []runtime.Object{
+		&akov1alpha1.HostRule{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar-hostrule"},
+			Spec: akov1alpha1.HostRuleSpec{
+				VirtualHost: akov1alpha1.HostRuleVirtualHost{
+					Fqdn:              "bar.com",
+					WAFPolicy:         "thisisaviref-waf",
+					EnableVirtualHost: &enableVirtualHost,
+				},
+			},
+		},
+	}
+
+	KubeClient = k8sfake.NewSimpleClientset(kubeObjects...)
+	CRDClient = crdfake.NewSimpleClientset(crdObjects...)
+	akoControlConfig := lib.AKOControlConfig()
+	akoControlConfig.SetCRDClientset(CRDClient)
+	akoControlConfig.SetAKOInstanceFlag(true)
+	akoControlConfig.SetEventRecorder(lib.AKOEventComponent, KubeClient, true)
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+		utils.PodInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	k8s.NewCRDInformers(CRDClient)
+
+	mcache := cache.SharedAviObjCache()
+	cloudObj := &cache.AviCloudPropertyCache{Name: "CLOUD_VCENTER", VType: lib.CLOUD_VCENTER}
+	mcache.CloudKeyCache.AviCacheAdd("CLOUD_VCENTER", cloudObj)
+
+	checkpointDir, _ := ioutil.TempDir("", "offlinesync")
+	defer os.RemoveAll(checkpointDir)
+	checkpointFile := filepath.Join(checkpointDir, "checkpoint.json")
+	ioutil.WriteFile(checkpointFile, []byte(checkpoint), 0644)
+	if err := mcache.PopulateCacheFromCheckpoint(checkpointFile); err != nil {
+		utils.AviLog.Fatalf("Failed to populate the cache from the checkpoint: %v", err)
+	}
+
+	stopCh := make(chan struct{})
+	if err := k8s.SharedAviController().OfflineSync(stopCh); err != nil {
+		utils.AviLog.Fatalf("Offline sync failed: %v", err)
+	}
+	code := m.Run()
+	close(stopCh)
+	os.Exit(code)
+}
+
+func getAviModel(t *testing.T) *avinodes.AviObjectGraph {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		t.Fatalf("model %s not found", modelName)
+	}
+	return aviModel.(*avinodes.AviObjectGraph)
+}
+
+func TestOfflineSyncIngress(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	nodes := getAviModel(t).GetAviVS()
+	g.Expect(nodes).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].VSVIPRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].VSVIPRefs[0].FQDNs).To(gomega.ConsistOf("foo.com", "bar.com"))
+	g.Expect(nodes[0].PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].PoolRefs[0].Name).To(gomega.Equal("cluster--foo.com_foo-default-foo-with-targets"))
+	g.Expect(nodes[0].PoolRefs[0].Servers).To(gomega.HaveLen(2))
+	g.Expect(nodes[0].SniNodes).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].SniNodes[0].VHDomainNames).To(gomega.ConsistOf("bar.com"))
+}
+
+func TestOfflineSyncHostRule(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	hostRule, err := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), "bar-hostrule", metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(hostRule.Status.Status).To(gomega.Equal(lib.StatusAccepted))
+
+	// The references in the HostRule are not verified on the controller, and are applied to the SNI child.
+	nodes := getAviModel(t).GetAviVS()
+	g.Expect(nodes).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].SniNodes).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].SniNodes[0].WafPolicyRef).To(gomega.ContainSubstring("thisisaviref-waf"))
+	g.Expect(*nodes[0].SniNodes[0].Enabled).To(gomega.BeFalse())
+}
+
+func TestOfflineSyncCacheCheckpoint(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"}
+	vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(vsCache.(*cache.AviVsCache).Uuid).To(gomega.Equal("virtualservice-1"))
+	_, found = mcache.VSVIPCache.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.BeTrue())
+}
@@ -714,8 +714,9 @@ func (avisess *AviSession) restRequest(verb string, uri string, payload interfac
 			glog.Error("CheckControllerStatus is disabled for this session, not going to retry.")
 			if err != nil {
 				glog.Errorf("Failed to invoke API. Error: %s", err.Error())
+				return nil, fmt.Errorf("Rest request error, returning to caller: %w", err)
 			}
-			return nil, errors.New("Rest request error, returning to caller")
+			return nil, fmt.Errorf("Rest request error, returning to caller, status code: %d", errorResult.HttpStatusCode)
 
 		}
 	}